@@ -17,10 +17,14 @@ limitations under the License.
 package v1
 
 import (
+	"fmt"
 	"sort"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 )
 
 // A ConditionType represents a condition a resource could be in.
@@ -34,6 +38,28 @@ const (
 	// TypeSynced resources are believed to be in sync with the
 	// Kubernetes resources that manage their lifecycle.
 	TypeSynced ConditionType = "Synced"
+
+	// TypeConnectionDetailsPublished resources have published their
+	// connection details to every configured destination.
+	TypeConnectionDetailsPublished ConditionType = "ConnectionDetailsPublished"
+
+	// TypeLateInitialized resources have no late initialized fields that
+	// were since changed both in the resource's spec and in the external
+	// system.
+	TypeLateInitialized ConditionType = "LateInitialized"
+
+	// TypeExternalNameUnique resources declare an external name that no
+	// other resource of the same kind and provider config declares.
+	TypeExternalNameUnique ConditionType = "ExternalNameUnique"
+
+	// TypePlanned resources are in dry-run mode, and report what would be
+	// done to their external resource instead of doing it.
+	TypePlanned ConditionType = "Planned"
+
+	// TypeHealthy resources passed their most recent health check. Unlike
+	// Ready, which reflects the state the external system reports, Healthy
+	// reflects a deeper check such as querying a database endpoint.
+	TypeHealthy ConditionType = "Healthy"
 )
 
 // A ConditionReason represents the reason a resource is in a condition.
@@ -49,9 +75,53 @@ const (
 
 // Reasons a resource is or is not synced.
 const (
-	ReasonReconcileSuccess ConditionReason = "ReconcileSuccess"
-	ReasonReconcileError   ConditionReason = "ReconcileError"
-	ReasonReconcilePaused  ConditionReason = "ReconcilePaused"
+	ReasonReconcileSuccess       ConditionReason = "ReconcileSuccess"
+	ReasonReconcileError         ConditionReason = "ReconcileError"
+	ReasonReconcileTerminalError ConditionReason = "ReconcileTerminalError"
+	ReasonReconcilePaused        ConditionReason = "ReconcilePaused"
+
+	// Reasons a resource is not synced because of a particular class of
+	// error. They're used instead of ReasonReconcileError when the class of
+	// error is known.
+	ReasonReconcileThrottled    ConditionReason = "ReconcileThrottled"
+	ReasonReconcileUnauthorized ConditionReason = "ReconcileUnauthorized"
+	ReasonReconcileInvalidInput ConditionReason = "ReconcileInvalidInput"
+	ReasonReconcileNotFound     ConditionReason = "ReconcileNotFound"
+	ReasonReconcileConflict     ConditionReason = "ReconcileConflict"
+)
+
+// Reasons a resource's connection details are or are not published.
+const (
+	ReasonPublished          ConditionReason = "Published"
+	ReasonPartiallyPublished ConditionReason = "PartiallyPublished"
+	ReasonPublishFailed      ConditionReason = "PublishFailed"
+)
+
+// Reasons a resource's late initialized fields do or do not conflict.
+const (
+	ReasonLateInitConsistent ConditionReason = "Consistent"
+	ReasonLateInitConflict   ConditionReason = "Conflict"
+)
+
+// Reasons a resource's external name is or is not unique.
+const (
+	ReasonExternalNameUnique   ConditionReason = "Unique"
+	ReasonExternalNameConflict ConditionReason = "Conflict"
+)
+
+// Reasons a resource is or is not planned.
+const (
+	ReasonPlannedCreate    ConditionReason = "PlannedCreate"
+	ReasonPlannedUpdate    ConditionReason = "PlannedUpdate"
+	ReasonPlannedDelete    ConditionReason = "PlannedDelete"
+	ReasonPlannedNoChanges ConditionReason = "PlannedNoChanges"
+	ReasonPlanApplied      ConditionReason = "PlanApplied"
+)
+
+// Reasons a resource is or is not healthy.
+const (
+	ReasonHealthy   ConditionReason = "HealthCheckPassed"
+	ReasonUnhealthy ConditionReason = "HealthCheckFailed"
 )
 
 // A Condition that may apply to a resource.
@@ -92,6 +162,20 @@ func (c Condition) WithMessage(msg string) Condition {
 	return c
 }
 
+// WithMessagef returns a condition by adding a message formatted according to
+// the supplied format specifier to the existing condition.
+func (c Condition) WithMessagef(format string, args ...any) Condition {
+	c.Message = fmt.Sprintf(format, args...)
+	return c
+}
+
+// WithMessageTemplate returns a condition by adding a message rendered from
+// the supplied template to the existing condition.
+func (c Condition) WithMessageTemplate(t MessageTemplate, args ...any) Condition {
+	c.Message = t.Render(args...)
+	return c
+}
+
 // NOTE(negz): Conditions are implemented as a slice rather than a map to comply
 // with Kubernetes API conventions. Ideally we'd comply by using a map that
 // marshalled to a JSON array, but doing so confuses the CRD schema generator.
@@ -248,7 +332,21 @@ func ReconcileError(err error) Condition {
 		Status:             corev1.ConditionFalse,
 		LastTransitionTime: metav1.Now(),
 		Reason:             ReasonReconcileError,
-		Message:            err.Error(),
+		Message:            errorMessage(err),
+	}
+}
+
+// ReconcileTerminalError returns a condition indicating that Crossplane
+// encountered an error while reconciling the resource that will not be
+// resolved by retrying. Crossplane won't reconcile the resource again until
+// it changes.
+func ReconcileTerminalError(err error) Condition {
+	return Condition{
+		Type:               TypeSynced,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonReconcileTerminalError,
+		Message:            errorMessage(err),
 	}
 }
 
@@ -262,3 +360,193 @@ func ReconcilePaused() Condition {
 		Reason:             ReasonReconcilePaused,
 	}
 }
+
+// ConnectionDetailsPublished returns a condition indicating that a resource's
+// connection details were published to every configured destination.
+func ConnectionDetailsPublished() Condition {
+	return Condition{
+		Type:               TypeConnectionDetailsPublished,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPublished,
+	}
+}
+
+// ConnectionDetailsPartiallyPublished returns a condition indicating that a
+// resource's connection details were published to the supplied destinations,
+// but could not be published to others.
+func ConnectionDetailsPartiallyPublished(published []string, err error) Condition {
+	return Condition{
+		Type:               TypeConnectionDetailsPublished,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPartiallyPublished,
+		Message:            errors.Truncate(fmt.Sprintf("Published to %s\n%s", strings.Join(published, ", "), errorMessage(err)), maxMessageLength),
+	}
+}
+
+// ConnectionDetailsPublishFailed returns a condition indicating that a
+// resource's connection details could not be published to any destination.
+func ConnectionDetailsPublishFailed(err error) Condition {
+	return Condition{
+		Type:               TypeConnectionDetailsPublished,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPublishFailed,
+		Message:            errorMessage(err),
+	}
+}
+
+// LateInitConsistent returns a condition indicating that none of a resource's
+// late initialized fields conflict.
+func LateInitConsistent() Condition {
+	return Condition{
+		Type:               TypeLateInitialized,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonLateInitConsistent,
+	}
+}
+
+// LateInitConflict returns a condition indicating that some of a resource's
+// late initialized fields were since changed both in the resource's spec and
+// in the external system, and no longer agree.
+func LateInitConflict(err error) Condition {
+	return Condition{
+		Type:               TypeLateInitialized,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonLateInitConflict,
+		Message:            errorMessage(err),
+	}
+}
+
+// ExternalNameUnique returns a condition indicating that no other resource of
+// the same kind and provider config declares a resource's external name.
+func ExternalNameUnique() Condition {
+	return Condition{
+		Type:               TypeExternalNameUnique,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonExternalNameUnique,
+	}
+}
+
+// ExternalNameConflict returns a condition indicating that other resources of
+// the same kind and provider config declare a resource's external name, and
+// would thus manage the same external resource.
+func ExternalNameConflict(err error) Condition {
+	return Condition{
+		Type:               TypeExternalNameUnique,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonExternalNameConflict,
+		Message:            errorMessage(err),
+	}
+}
+
+// PlannedCreate returns a condition indicating that a resource in dry-run
+// mode would create its external resource.
+func PlannedCreate() Condition {
+	return Condition{
+		Type:               TypePlanned,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPlannedCreate,
+		Message:            "External resource would be created",
+	}
+}
+
+// PlannedUpdate returns a condition indicating that a resource in dry-run
+// mode would update its external resource. The supplied diff, if any,
+// describes the update.
+func PlannedUpdate(diff string) Condition {
+	msg := "External resource would be updated"
+	if diff != "" {
+		msg = errors.Truncate(msg+":\n"+diff, maxMessageLength)
+	}
+	return Condition{
+		Type:               TypePlanned,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPlannedUpdate,
+		Message:            msg,
+	}
+}
+
+// PlannedDelete returns a condition indicating that a resource in dry-run
+// mode would delete its external resource.
+func PlannedDelete() Condition {
+	return Condition{
+		Type:               TypePlanned,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPlannedDelete,
+		Message:            "External resource would be deleted",
+	}
+}
+
+// PlannedNoChanges returns a condition indicating that a resource in dry-run
+// mode would not change its external resource.
+func PlannedNoChanges() Condition {
+	return Condition{
+		Type:               TypePlanned,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPlannedNoChanges,
+		Message:            "External resource is up to date",
+	}
+}
+
+// PlanApplied returns a condition indicating that a resource left dry-run
+// mode, and now changes its external resource.
+func PlanApplied() Condition {
+	return Condition{
+		Type:               TypePlanned,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPlanApplied,
+	}
+}
+
+// Healthy returns a condition indicating that a resource passed its most
+// recent health check.
+func Healthy() Condition {
+	return Condition{
+		Type:               TypeHealthy,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonHealthy,
+	}
+}
+
+// Unhealthy returns a condition indicating that a resource failed its most
+// recent health check.
+func Unhealthy(err error) Condition {
+	return Condition{
+		Type:               TypeHealthy,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonUnhealthy,
+		Message:            errorMessage(err),
+	}
+}
+
+const (
+	// maxMessageCauses is the maximum number of causes of an error that will
+	// be rendered in a condition message.
+	maxMessageCauses = 10
+
+	// maxMessageLength is the maximum length of a condition message that
+	// describes an error, in bytes.
+	maxMessageLength = 4096
+)
+
+// errorMessage renders the supplied error as a condition message. Errors that
+// have several independent causes (i.e. that were produced by errors.Join) are
+// rendered with each cause as a separate bullet point. At most
+// maxMessageCauses causes are rendered, and the message is truncated to
+// maxMessageLength bytes.
+func errorMessage(err error) string {
+	return errors.Truncate(errors.Summarize(err, maxMessageCauses), maxMessageLength)
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package v1
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -228,3 +229,79 @@ func TestConditionWithMessage(t *testing.T) {
 		})
 	}
 }
+
+func TestConditionWithMessagef(t *testing.T) {
+	cannotConnect := MessageTemplate{Name: "CannotConnect", Format: "cannot connect to %s: %v"}
+	cases := map[string]struct {
+		reason string
+		got    Condition
+		want   Condition
+	}{
+		"Format": {
+			reason: "A message formatted according to the format specifier should be added.",
+			got:    Condition{Type: TypeReady, Reason: ReasonUnavailable}.WithMessagef("%d of %d replicas ready", 1, 3),
+			want:   Condition{Type: TypeReady, Reason: ReasonUnavailable, Message: "1 of 3 replicas ready"},
+		},
+		"Template": {
+			reason: "A message rendered from the template should be added.",
+			got:    Condition{Type: TypeReady, Reason: ReasonUnavailable}.WithMessageTemplate(cannotConnect, "us-east-1", "timeout"),
+			want:   Condition{Type: TypeReady, Reason: ReasonUnavailable, Message: "cannot connect to us-east-1: timeout"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, tc.got); diff != "" {
+				t.Errorf("\n%s\nWithMessagef(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestReconcileErrorMessage(t *testing.T) {
+	many := make([]error, maxMessageCauses+2)
+	for i := range many {
+		many[i] = errors.Errorf("cause %d", i)
+	}
+
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   string
+	}{
+		"SingleCause": {
+			reason: "An error with a single cause should be rendered as is.",
+			err:    errors.Wrap(errors.New("boom"), "context"),
+			want:   "context: boom",
+		},
+		"JoinedCauses": {
+			reason: "Each cause of a joined error should be rendered as a bullet point.",
+			err:    errors.Join(errors.New("boom"), errors.New("bang")),
+			want:   "- boom\n- bang",
+		},
+		"WrappedJoinedCauses": {
+			reason: "Context wrapping a joined error should precede its causes.",
+			err:    errors.Wrap(errors.Join(errors.New("boom"), errors.Join(errors.New("bang"), errors.New("pow"))), "context"),
+			want:   "context:\n- boom\n- bang\n- pow",
+		},
+		"TooManyCauses": {
+			reason: "Only the first maxMessageCauses causes should be rendered.",
+			err:    errors.Join(many...),
+			want:   "- cause 0\n- cause 1\n- cause 2\n- cause 3\n- cause 4\n- cause 5\n- cause 6\n- cause 7\n- cause 8\n- cause 9\n- ...and 2 more",
+		},
+		"TooLong": {
+			reason: "Messages should be truncated to maxMessageLength.",
+			err:    errors.New(strings.Repeat("ü", maxMessageLength)),
+			want:   strings.Repeat("ü", (maxMessageLength-3)/2) + "...",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ReconcileError(tc.err).Message
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nReconcileError(...).Message: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
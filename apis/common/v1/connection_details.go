@@ -17,6 +17,9 @@ limitations under the License.
 package v1
 
 import (
+	"strconv"
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -29,11 +32,27 @@ const (
 	// across Secret Store implementations and expect all to support
 	// setting/getting labels.
 	LabelKeyOwnerUID = "secret.crossplane.io/owner-uid"
+
+	// LabelKeyPublishGeneration identifies the key values most recently
+	// published to a connection secret. It lets Crossplane tell whether a
+	// publish that reported an error was in fact partially or fully written.
+	LabelKeyPublishGeneration = "secret.crossplane.io/publish-generation"
+
+	// LabelKeyLastChanged is the time, in seconds since the Unix epoch, at
+	// which the key values of a connection secret last changed. It is a
+	// label, rather than an annotation, because it must survive secret stores
+	// that support only labels, e.g. Vault custom metadata.
+	LabelKeyLastChanged = "secret.crossplane.io/last-changed"
 )
 
 // PublishConnectionDetailsTo represents configuration of a connection secret.
 type PublishConnectionDetailsTo struct {
-	// Name is the name of the connection secret.
+	// Name is the name of the connection secret. It may be a Go template
+	// over the owner's metadata, e.g. "{{ .ClaimName }}-{{ .Kind }}", which
+	// is rendered when the connection secret is published. Only the owner's
+	// .Name, .Namespace, .Kind, .ClaimName and .ClaimNamespace are available,
+	// because they never change. Publishing fails if the rendered name is
+	// used by the connection secret of another owner.
 	Name string `json:"name"`
 
 	// Metadata is the metadata for connection secret.
@@ -83,8 +102,43 @@ func (in *ConnectionSecretMetadata) GetOwnerUID() string {
 	return ""
 }
 
+// SetPublishGeneration sets the publish generation label.
+func (in *ConnectionSecretMetadata) SetPublishGeneration(g string) {
+	if in.Labels == nil {
+		in.Labels = map[string]string{}
+	}
+	in.Labels[LabelKeyPublishGeneration] = g
+}
+
+// GetPublishGeneration gets the publish generation label.
+func (in *ConnectionSecretMetadata) GetPublishGeneration() string {
+	return in.Labels[LabelKeyPublishGeneration]
+}
+
+// SetLastChanged sets the last changed label.
+func (in *ConnectionSecretMetadata) SetLastChanged(t time.Time) {
+	if in.Labels == nil {
+		in.Labels = map[string]string{}
+	}
+	in.Labels[LabelKeyLastChanged] = strconv.FormatInt(t.Unix(), 10)
+}
+
+// GetLastChanged gets the time from the last changed label. It returns false
+// if the label is not set or cannot be parsed.
+func (in *ConnectionSecretMetadata) GetLastChanged() (time.Time, bool) {
+	s, ok := in.Labels[LabelKeyLastChanged]
+	if !ok {
+		return time.Time{}, false
+	}
+	sec, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(sec, 0), true
+}
+
 // SecretStoreType represents a secret store type.
-// +kubebuilder:validation:Enum=Kubernetes;Vault;Plugin
+// +kubebuilder:validation:Enum=Kubernetes;Vault;Plugin;SOPS
 type SecretStoreType string
 
 const (
@@ -98,6 +152,10 @@ const (
 
 	// SecretStorePlugin indicates that secret store type is Plugin and will be used with external secret stores.
 	SecretStorePlugin SecretStoreType = "Plugin"
+
+	// SecretStoreSOPS indicates that secret store type is SOPS, i.e. that
+	// connection secrets will be written to SOPS encrypted files.
+	SecretStoreSOPS SecretStoreType = "SOPS"
 )
 
 // SecretStoreConfig represents configuration of a Secret Store.
@@ -132,6 +190,52 @@ type SecretStoreConfig struct {
 	// Plugin configures External secret store as a plugin.
 	// +optional
 	Plugin *PluginStoreConfig `json:"plugin,omitempty"`
+
+	// SOPS configures a SOPS secret store.
+	// +optional
+	SOPS *SOPSSecretStoreConfig `json:"sops,omitempty"`
+}
+
+// SOPSSecretStoreConfig represents the configuration of a secret store that
+// writes connection secrets as SOPS encrypted Kubernetes Secret manifests.
+// Each secret is written to <path>/<scope>/<name>.yaml, which lets GitOps
+// tools that decrypt SOPS files, e.g. Flux, apply it. The path may be a
+// volume or a Git checkout that is committed and pushed by a sidecar.
+// https://github.com/getsops/sops
+type SOPSSecretStoreConfig struct {
+	// Path of the directory connection secrets are written to.
+	Path string `json:"path"`
+
+	// VaultTransit configures the Vault transit key used to encrypt the data
+	// key of each file.
+	VaultTransit SOPSVaultTransitConfig `json:"vaultTransit"`
+}
+
+// SOPSVaultTransitConfig represents the configuration of a Vault transit key
+// used as a SOPS master key.
+// https://developer.hashicorp.com/vault/docs/secrets/transit
+type SOPSVaultTransitConfig struct {
+	// Server is the url of the Vault server, e.g. "https://vault.acme.org"
+	Server string `json:"server"`
+
+	// Namespace is the Namespace of vault on which to operate
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// MountPath is the mount path of the transit secrets engine. Defaults to
+	// "transit".
+	// +optional
+	MountPath string `json:"mountPath,omitempty"`
+
+	// KeyName is the name of the transit key.
+	KeyName string `json:"keyName"`
+
+	// CABundle configures CA bundle for Vault Server.
+	// +optional
+	CABundle *VaultCABundleConfig `json:"caBundle,omitempty"`
+
+	// Auth configures an authentication method for Vault.
+	Auth VaultAuthConfig `json:"auth"`
 }
 
 // PluginStoreConfig represents configuration of an External Secret Store.
@@ -187,6 +291,14 @@ const (
 	// authenticate to Vault.
 	// https://developer.hashicorp.com/vault/docs/auth/kubernetes
 	VaultAuthKubernetes VaultAuthMethod = "Kubernetes"
+	// VaultAuthCert indicates that "TLS Certificates Auth" will be used to
+	// authenticate to Vault.
+	// https://developer.hashicorp.com/vault/docs/auth/cert
+	VaultAuthCert VaultAuthMethod = "Cert"
+	// VaultAuthAWSIAM indicates that the IAM type of "AWS Auth" will be used
+	// to authenticate to Vault.
+	// https://developer.hashicorp.com/vault/docs/auth/aws#iam-auth-method
+	VaultAuthAWSIAM VaultAuthMethod = "AWSIAM"
 )
 
 // VaultAuthTokenConfig represents configuration for Vault Token Auth Method.
@@ -231,6 +343,54 @@ type VaultAuthKubernetesConfig struct {
 	ServiceAccountTokenSource *ServiceAccountTokenSourceConfig `json:"serviceAccountTokenSource,omitempty"`
 }
 
+// VaultAuthCertConfig represents configuration for Vault TLS Certificates
+// Auth Method.
+// https://developer.hashicorp.com/vault/docs/auth/cert
+type VaultAuthCertConfig struct {
+	// Name of the certificate role in Vault to authenticate against. If
+	// omitted Vault tries all roles whose certificates match the client
+	// certificate.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// MountPath of the cert auth method in Vault. Defaults to "cert".
+	// +optional
+	MountPath string `json:"mountPath,omitempty"`
+
+	// Source of the client certificate. The credentials must contain both
+	// the PEM encoded certificate and its PEM encoded private key.
+	// +kubebuilder:validation:Enum=None;Secret;Environment;Filesystem
+	Source CredentialsSource `json:"source"`
+
+	// CommonCredentialSelectors provides common selectors for extracting
+	// credentials.
+	CommonCredentialSelectors `json:",inline"`
+}
+
+// VaultAuthAWSIAMConfig represents configuration for the IAM type of Vault
+// AWS Auth Method. Credentials are obtained using the default AWS credential
+// chain, e.g. from the environment, an EKS service account or an EC2 instance
+// profile.
+// https://developer.hashicorp.com/vault/docs/auth/aws#iam-auth-method
+type VaultAuthAWSIAMConfig struct {
+	// Role is the name of the role in Vault to authenticate against.
+	Role string `json:"role"`
+
+	// MountPath of the aws auth method in Vault. Defaults to "aws".
+	// +optional
+	MountPath string `json:"mountPath,omitempty"`
+
+	// Region of the STS endpoint the signed GetCallerIdentity request is
+	// sent to by Vault. The global STS endpoint is used if omitted.
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// ServerIDHeaderValue is sent as the X-Vault-AWS-IAM-Server-ID header
+	// of the signed request, if the Vault aws auth method requires it.
+	// +optional
+	ServerIDHeaderValue string `json:"serverIDHeaderValue,omitempty"`
+}
+
 // VaultAuthConfig required to authenticate to a Vault API.
 type VaultAuthConfig struct {
 	// Method configures which auth method will be used.
@@ -241,6 +401,12 @@ type VaultAuthConfig struct {
 	// Kubernetes configes Kubernetes Auth for Vault
 	// +optional
 	Kubernetes *VaultAuthKubernetesConfig `json:"kubernetes,omitempty"`
+	// Cert configures TLS Certificates Auth for Vault.
+	// +optional
+	Cert *VaultAuthCertConfig `json:"cert,omitempty"`
+	// AWSIAM configures the IAM type of AWS Auth for Vault.
+	// +optional
+	AWSIAM *VaultAuthAWSIAMConfig `json:"awsIAM,omitempty"`
 }
 
 // VaultCABundleConfig represents configuration for configuring a CA bundle.
@@ -280,6 +446,21 @@ type VaultSecretStoreConfig struct {
 	// MountPath is the mount path of the KV secrets engine.
 	MountPath string `json:"mountPath"`
 
+	// NamespaceTemplate is a Go template that renders the Vault namespace of
+	// each connection secret from its metadata, so that a single store config
+	// may serve many tenants. The template may refer to the secret's .Name,
+	// .Scope, .Labels and .Annotations, e.g. `teams/{{ .Labels.team }}`. It
+	// takes precedence over Namespace.
+	// +optional
+	NamespaceTemplate *string `json:"namespaceTemplate,omitempty"`
+
+	// ParentPathTemplate is a Go template that renders the parent path of each
+	// connection secret from its metadata, within the KV secrets engine. It
+	// may refer to the same fields as NamespaceTemplate, and takes precedence
+	// over the secret's scope and the default scope.
+	// +optional
+	ParentPathTemplate *string `json:"parentPathTemplate,omitempty"`
+
 	// Version of the KV Secrets engine of Vault.
 	// https://www.vaultproject.io/docs/secrets/kv
 	// +optional
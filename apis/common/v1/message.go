@@ -0,0 +1,140 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// A MessageTemplate is a named, parameterized condition message. Rendering
+// condition messages from shared templates keeps them consistent, and lets
+// tests assert that a message was rendered from a template rather than
+// asserting on the full message.
+// +kubebuilder:object:generate=false
+type MessageTemplate struct {
+	// Name uniquely identifies the template, e.g. CannotConnect.
+	Name string
+
+	// Format of the message, as understood by fmt.Sprintf, e.g. "cannot
+	// connect to %s: %v".
+	Format string
+}
+
+// Render a message from the template using the supplied arguments.
+func (t MessageTemplate) Render(args ...any) string {
+	return fmt.Sprintf(t.Format, args...)
+}
+
+// Matches returns true if the supplied message could have been rendered from
+// the template, i.e. if it matches the template's format with any value in
+// place of each of its verbs.
+func (t MessageTemplate) Matches(msg string) bool {
+	return regexp.MustCompile(formatPattern(t.Format)).MatchString(msg)
+}
+
+// formatPattern returns a regular expression that matches any string that
+// could be rendered from the supplied fmt format specifier.
+func formatPattern(format string) string {
+	p := &strings.Builder{}
+	p.WriteString("^")
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			p.WriteString(regexp.QuoteMeta(format[i : i+1]))
+			continue
+		}
+		i++
+		if i < len(format) && format[i] == '%' {
+			p.WriteString("%")
+			continue
+		}
+		// Skip any flags, width, precision, and argument indexes until we
+		// find the verb.
+		for i < len(format) && strings.IndexByte("+-# 0123456789.*[]", format[i]) >= 0 {
+			i++
+		}
+		p.WriteString("(?s:.*?)")
+	}
+	p.WriteString("$")
+	return p.String()
+}
+
+// A MessageTemplates registry contains the condition message templates of a
+// provider. It is safe for concurrent use.
+// +kubebuilder:object:generate=false
+type MessageTemplates struct {
+	mu        sync.RWMutex
+	templates map[string]MessageTemplate
+}
+
+// NewMessageTemplates returns a registry containing the supplied templates.
+func NewMessageTemplates(t ...MessageTemplate) *MessageTemplates {
+	r := &MessageTemplates{templates: make(map[string]MessageTemplate, len(t))}
+	for _, tmpl := range t {
+		r.Register(tmpl)
+	}
+	return r
+}
+
+// Register the supplied template, replacing any template of the same name.
+func (r *MessageTemplates) Register(t MessageTemplate) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.templates[t.Name] = t
+}
+
+// Get the template of the supplied name.
+func (r *MessageTemplates) Get(name string) (MessageTemplate, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.templates[name]
+	return t, ok
+}
+
+// Render a message from the template of the supplied name. The name is
+// returned as the message if no such template is registered, so that a
+// missing template does not cause a condition to be set with no message.
+func (r *MessageTemplates) Render(name string, args ...any) string {
+	t, ok := r.Get(name)
+	if !ok {
+		return name
+	}
+	return t.Render(args...)
+}
+
+// Match returns the template the supplied message was rendered from. Matching
+// templates are considered in order of name, so if several templates match a
+// message the one whose name sorts first is returned.
+func (r *MessageTemplates) Match(msg string) (MessageTemplate, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.templates))
+	for n := range r.templates {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		if r.templates[n].Matches(msg) {
+			return r.templates[n], true
+		}
+	}
+	return MessageTemplate{}, false
+}
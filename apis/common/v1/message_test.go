@@ -0,0 +1,115 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMessageTemplateMatches(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		format string
+		msg    string
+		want   bool
+	}{
+		"Literal": {
+			reason: "A template with no verbs should match only its format.",
+			format: "cannot observe (yet)",
+			msg:    "cannot observe (yet)",
+			want:   true,
+		},
+		"Verbs": {
+			reason: "A template's verbs should match any value.",
+			format: "cannot connect to %s: %v",
+			msg:    "cannot connect to us-east-1: context deadline exceeded: i/o timeout",
+			want:   true,
+		},
+		"FlagsAndWidth": {
+			reason: "Verbs with flags, width, and precision should match any value.",
+			format: "%05.2f%% of %-10q done",
+			msg:    "42.00% of \"bucket\" done",
+			want:   true,
+		},
+		"Mismatch": {
+			reason: "A message that was not rendered from the template should not match.",
+			format: "cannot connect to %s: %v",
+			msg:    "cannot observe us-east-1: timeout",
+			want:   false,
+		},
+		"Suffix": {
+			reason: "A message with content after the template's format should not match.",
+			format: "%d replicas ready",
+			msg:    "3 replicas ready, 1 pending",
+			want:   false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := MessageTemplate{Format: tc.format}.Matches(tc.msg)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nMatches(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMessageTemplatesMatch(t *testing.T) {
+	cannotConnect := MessageTemplate{Name: "CannotConnect", Format: "cannot connect to %s: %v"}
+	waiting := MessageTemplate{Name: "Waiting", Format: "waiting for %s"}
+	r := NewMessageTemplates(cannotConnect, waiting)
+
+	type want struct {
+		t  MessageTemplate
+		ok bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		msg    string
+		want   want
+	}{
+		"Match": {
+			reason: "The template a message was rendered from should be returned.",
+			msg:    r.Render("Waiting", "the database"),
+			want:   want{t: waiting, ok: true},
+		},
+		"NoMatch": {
+			reason: "No template should be returned if the message was not rendered from a registered template.",
+			msg:    "something else",
+			want:   want{},
+		},
+		"UnknownTemplate": {
+			reason: "Rendering an unknown template should return its name, which matches no template.",
+			msg:    r.Render("Unknown", "cool"),
+			want:   want{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tmpl, ok := r.Match(tc.msg)
+			got := want{t: tmpl, ok: ok}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nMatch(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -35,6 +35,42 @@ const (
 	ManagementOrphanOnDelete ManagementPolicy = "OrphanOnDelete"
 )
 
+// IsDefault returns true if the policy is the default, FullControl. A policy
+// that is not set is the default.
+func (p ManagementPolicy) IsDefault() bool {
+	return p == "" || p == ManagementFullControl
+}
+
+// ShouldCreate returns true if Crossplane controllers should create the
+// external resource if it does not exist.
+func (p ManagementPolicy) ShouldCreate() bool {
+	return p.IsDefault() || p == ManagementOrphanOnDelete
+}
+
+// ShouldUpdate returns true if Crossplane controllers should update the
+// external resource if it differs from the desired state.
+func (p ManagementPolicy) ShouldUpdate() bool {
+	return p.IsDefault() || p == ManagementOrphanOnDelete
+}
+
+// ShouldLateInitialize returns true if Crossplane controllers should late
+// initialize the spec of the managed resource from the external resource.
+func (p ManagementPolicy) ShouldLateInitialize() bool {
+	return p.IsDefault() || p == ManagementOrphanOnDelete
+}
+
+// ShouldDelete returns true if Crossplane controllers should delete the
+// external resource when its managed resource is deleted.
+func (p ManagementPolicy) ShouldDelete() bool {
+	return p.IsDefault()
+}
+
+// ShouldOnlyObserve returns true if Crossplane controllers should only observe
+// the external resource, and never create, update, or delete it.
+func (p ManagementPolicy) ShouldOnlyObserve() bool {
+	return p == ManagementObserveOnly
+}
+
 // A DeletionPolicy determines what should happen to the underlying external
 // resource when a managed resource is deleted.
 // +kubebuilder:validation:Enum=Orphan;Delete
@@ -0,0 +1,99 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestManagementPolicy(t *testing.T) {
+	type want struct {
+		IsDefault            bool
+		ShouldCreate         bool
+		ShouldUpdate         bool
+		ShouldLateInitialize bool
+		ShouldDelete         bool
+		ShouldOnlyObserve    bool
+	}
+	cases := map[string]struct {
+		reason string
+		p      ManagementPolicy
+		want   want
+	}{
+		"Unset": {
+			reason: "An unset policy should be treated as FullControl.",
+			p:      "",
+			want: want{
+				IsDefault:            true,
+				ShouldCreate:         true,
+				ShouldUpdate:         true,
+				ShouldLateInitialize: true,
+				ShouldDelete:         true,
+			},
+		},
+		"FullControl": {
+			reason: "Crossplane should do everything to an external resource under full control.",
+			p:      ManagementFullControl,
+			want: want{
+				IsDefault:            true,
+				ShouldCreate:         true,
+				ShouldUpdate:         true,
+				ShouldLateInitialize: true,
+				ShouldDelete:         true,
+			},
+		},
+		"ObserveOnly": {
+			reason: "Crossplane should only observe an observe only external resource.",
+			p:      ManagementObserveOnly,
+			want: want{
+				ShouldOnlyObserve: true,
+			},
+		},
+		"OrphanOnDelete": {
+			reason: "Crossplane should do everything but delete an orphan on delete external resource.",
+			p:      ManagementOrphanOnDelete,
+			want: want{
+				ShouldCreate:         true,
+				ShouldUpdate:         true,
+				ShouldLateInitialize: true,
+			},
+		},
+		"Unknown": {
+			reason: "Crossplane should do nothing to an external resource with an unknown policy.",
+			p:      ManagementPolicy("Bogus"),
+			want:   want{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := want{
+				IsDefault:            tc.p.IsDefault(),
+				ShouldCreate:         tc.p.ShouldCreate(),
+				ShouldUpdate:         tc.p.ShouldUpdate(),
+				ShouldLateInitialize: tc.p.ShouldLateInitialize(),
+				ShouldDelete:         tc.p.ShouldDelete(),
+				ShouldOnlyObserve:    tc.p.ShouldOnlyObserve(),
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\n%q: -want, +got:\n%s", tc.reason, tc.p, diff)
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -124,7 +125,8 @@ type Reference struct {
 
 // A TypedReference refers to an object by Name, Kind, and APIVersion. It is
 // commonly used to reference cluster-scoped objects or objects where the
-// namespace is already known.
+// namespace is already known. When the UID is set, only the object with that
+// UID satisfies the reference, even if another object of the same name exists.
 type TypedReference struct {
 	// APIVersion of the referenced object.
 	APIVersion string `json:"apiVersion"`
@@ -135,7 +137,14 @@ type TypedReference struct {
 	// Name of the referenced object.
 	Name string `json:"name"`
 
-	// UID of the referenced object.
+	// Namespace of the referenced object. Omitted for cluster-scoped objects,
+	// or when the namespace is already known.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// UID of the referenced object. If set, the reference is only satisfied
+	// by the object with this UID. This protects against the referenced object
+	// being deleted and a new object of the same name being created.
 	// +optional
 	UID types.UID `json:"uid,omitempty"`
 }
@@ -145,6 +154,11 @@ type Selector struct {
 	// MatchLabels ensures an object with matching labels is selected.
 	MatchLabels map[string]string `json:"matchLabels,omitempty"`
 
+	// MatchExpressions ensures an object whose labels satisfy all of the
+	// set-based requirements is selected.
+	// +optional
+	MatchExpressions []metav1.LabelSelectorRequirement `json:"matchExpressions,omitempty"`
+
 	// MatchControllerRef ensures an object with the same controller reference
 	// as the selecting object is selected.
 	MatchControllerRef *bool `json:"matchControllerRef,omitempty"`
@@ -230,6 +244,42 @@ type ResourceSpec struct {
 // ResourceStatus represents the observed state of a managed resource.
 type ResourceStatus struct {
 	ConditionedStatus `json:",inline"`
+
+	// ResolvedReferences records the outcome of resolving this managed
+	// resource's references and selectors, if it opts in to recording them.
+	// +optional
+	ResolvedReferences []ResolvedReference `json:"resolvedReferences,omitempty"`
+
+	// TimeToReady is how long this managed resource took to first become
+	// ready after it was created, if it opts in to recording it.
+	// +optional
+	TimeToReady *metav1.Duration `json:"timeToReady,omitempty"`
+}
+
+// A ResolvedReference records the outcome of resolving a reference or selector
+// to another resource.
+type ResolvedReference struct {
+	// FieldPath of the field the reference was resolved into, e.g.
+	// spec.forProvider.vpcId.
+	FieldPath string `json:"fieldPath"`
+
+	// Name of the resource that satisfied the reference or selector.
+	Name string `json:"name"`
+
+	// UID of the resource that satisfied the reference or selector.
+	// +optional
+	UID types.UID `json:"uid,omitempty"`
+
+	// ResourceVersion of the resource that satisfied the reference or
+	// selector, when the reference was last resolved.
+	// +optional
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+
+	// Value resolved from the resource.
+	Value string `json:"value"`
+
+	// ResolvedAt is the time the resolved value last changed.
+	ResolvedAt metav1.Time `json:"resolvedAt"`
 }
 
 // A CredentialsSource is a source from which provider credentials may be
@@ -23,6 +23,7 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -363,6 +364,22 @@ func (in *Reference) DeepCopy() *Reference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResolvedReference) DeepCopyInto(out *ResolvedReference) {
+	*out = *in
+	in.ResolvedAt.DeepCopyInto(&out.ResolvedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResolvedReference.
+func (in *ResolvedReference) DeepCopy() *ResolvedReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ResolvedReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceSpec) DeepCopyInto(out *ResourceSpec) {
 	*out = *in
@@ -402,6 +419,18 @@ func (in *ResourceSpec) DeepCopy() *ResourceSpec {
 func (in *ResourceStatus) DeepCopyInto(out *ResourceStatus) {
 	*out = *in
 	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	if in.ResolvedReferences != nil {
+		in, out := &in.ResolvedReferences, &out.ResolvedReferences
+		*out = make([]ResolvedReference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TimeToReady != nil {
+		in, out := &in.TimeToReady, &out.TimeToReady
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceStatus.
@@ -414,6 +443,43 @@ func (in *ResourceStatus) DeepCopy() *ResourceStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SOPSSecretStoreConfig) DeepCopyInto(out *SOPSSecretStoreConfig) {
+	*out = *in
+	in.VaultTransit.DeepCopyInto(&out.VaultTransit)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SOPSSecretStoreConfig.
+func (in *SOPSSecretStoreConfig) DeepCopy() *SOPSSecretStoreConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SOPSSecretStoreConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SOPSVaultTransitConfig) DeepCopyInto(out *SOPSVaultTransitConfig) {
+	*out = *in
+	if in.CABundle != nil {
+		in, out := &in.CABundle, &out.CABundle
+		*out = new(VaultCABundleConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Auth.DeepCopyInto(&out.Auth)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SOPSVaultTransitConfig.
+func (in *SOPSVaultTransitConfig) DeepCopy() *SOPSVaultTransitConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SOPSVaultTransitConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretKeySelector) DeepCopyInto(out *SecretKeySelector) {
 	*out = *in
@@ -468,6 +534,11 @@ func (in *SecretStoreConfig) DeepCopyInto(out *SecretStoreConfig) {
 		*out = new(PluginStoreConfig)
 		**out = **in
 	}
+	if in.SOPS != nil {
+		in, out := &in.SOPS, &out.SOPS
+		*out = new(SOPSSecretStoreConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreConfig.
@@ -490,6 +561,13 @@ func (in *Selector) DeepCopyInto(out *Selector) {
 			(*out)[key] = val
 		}
 	}
+	if in.MatchExpressions != nil {
+		in, out := &in.MatchExpressions, &out.MatchExpressions
+		*out = make([]metav1.LabelSelectorRequirement, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.MatchControllerRef != nil {
 		in, out := &in.MatchControllerRef, &out.MatchControllerRef
 		*out = new(bool)
@@ -584,6 +662,37 @@ func (in *TypedReference) DeepCopy() *TypedReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultAuthAWSIAMConfig) DeepCopyInto(out *VaultAuthAWSIAMConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultAuthAWSIAMConfig.
+func (in *VaultAuthAWSIAMConfig) DeepCopy() *VaultAuthAWSIAMConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultAuthAWSIAMConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultAuthCertConfig) DeepCopyInto(out *VaultAuthCertConfig) {
+	*out = *in
+	in.CommonCredentialSelectors.DeepCopyInto(&out.CommonCredentialSelectors)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultAuthCertConfig.
+func (in *VaultAuthCertConfig) DeepCopy() *VaultAuthCertConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultAuthCertConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VaultAuthConfig) DeepCopyInto(out *VaultAuthConfig) {
 	*out = *in
@@ -597,6 +706,16 @@ func (in *VaultAuthConfig) DeepCopyInto(out *VaultAuthConfig) {
 		*out = new(VaultAuthKubernetesConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Cert != nil {
+		in, out := &in.Cert, &out.Cert
+		*out = new(VaultAuthCertConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AWSIAM != nil {
+		in, out := &in.AWSIAM, &out.AWSIAM
+		*out = new(VaultAuthAWSIAMConfig)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultAuthConfig.
@@ -664,6 +783,16 @@ func (in *VaultCABundleConfig) DeepCopy() *VaultCABundleConfig {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VaultSecretStoreConfig) DeepCopyInto(out *VaultSecretStoreConfig) {
 	*out = *in
+	if in.NamespaceTemplate != nil {
+		in, out := &in.NamespaceTemplate, &out.NamespaceTemplate
+		*out = new(string)
+		**out = **in
+	}
+	if in.ParentPathTemplate != nil {
+		in, out := &in.ParentPathTemplate, &out.ParentPathTemplate
+		*out = new(string)
+		**out = **in
+	}
 	if in.Version != nil {
 		in, out := &in.Version, &out.Version
 		*out = new(VaultKVVersion)
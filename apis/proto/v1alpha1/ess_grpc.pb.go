@@ -25,6 +25,7 @@ type ExternalSecretStorePluginServiceClient interface {
 	GetSecret(ctx context.Context, in *GetSecretRequest, opts ...grpc.CallOption) (*GetSecretResponse, error)
 	ApplySecret(ctx context.Context, in *ApplySecretRequest, opts ...grpc.CallOption) (*ApplySecretResponse, error)
 	DeleteKeys(ctx context.Context, in *DeleteKeysRequest, opts ...grpc.CallOption) (*DeleteKeysResponse, error)
+	WatchSecrets(ctx context.Context, in *WatchSecretsRequest, opts ...grpc.CallOption) (ExternalSecretStorePluginService_WatchSecretsClient, error)
 }
 
 type externalSecretStorePluginServiceClient struct {
@@ -62,6 +63,38 @@ func (c *externalSecretStorePluginServiceClient) DeleteKeys(ctx context.Context,
 	return out, nil
 }
 
+func (c *externalSecretStorePluginServiceClient) WatchSecrets(ctx context.Context, in *WatchSecretsRequest, opts ...grpc.CallOption) (ExternalSecretStorePluginService_WatchSecretsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ExternalSecretStorePluginService_ServiceDesc.Streams[0], "/ess.proto.v1alpha1.ExternalSecretStorePluginService/WatchSecrets", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &externalSecretStorePluginServiceWatchSecretsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ExternalSecretStorePluginService_WatchSecretsClient interface {
+	Recv() (*WatchSecretsResponse, error)
+	grpc.ClientStream
+}
+
+type externalSecretStorePluginServiceWatchSecretsClient struct {
+	grpc.ClientStream
+}
+
+func (x *externalSecretStorePluginServiceWatchSecretsClient) Recv() (*WatchSecretsResponse, error) {
+	m := new(WatchSecretsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // ExternalSecretStorePluginServiceServer is the server API for ExternalSecretStorePluginService service.
 // All implementations must embed UnimplementedExternalSecretStorePluginServiceServer
 // for forward compatibility
@@ -69,6 +102,7 @@ type ExternalSecretStorePluginServiceServer interface {
 	GetSecret(context.Context, *GetSecretRequest) (*GetSecretResponse, error)
 	ApplySecret(context.Context, *ApplySecretRequest) (*ApplySecretResponse, error)
 	DeleteKeys(context.Context, *DeleteKeysRequest) (*DeleteKeysResponse, error)
+	WatchSecrets(*WatchSecretsRequest, ExternalSecretStorePluginService_WatchSecretsServer) error
 	mustEmbedUnimplementedExternalSecretStorePluginServiceServer()
 }
 
@@ -85,6 +119,9 @@ func (UnimplementedExternalSecretStorePluginServiceServer) ApplySecret(context.C
 func (UnimplementedExternalSecretStorePluginServiceServer) DeleteKeys(context.Context, *DeleteKeysRequest) (*DeleteKeysResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteKeys not implemented")
 }
+func (UnimplementedExternalSecretStorePluginServiceServer) WatchSecrets(*WatchSecretsRequest, ExternalSecretStorePluginService_WatchSecretsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchSecrets not implemented")
+}
 func (UnimplementedExternalSecretStorePluginServiceServer) mustEmbedUnimplementedExternalSecretStorePluginServiceServer() {
 }
 
@@ -153,6 +190,27 @@ func _ExternalSecretStorePluginService_DeleteKeys_Handler(srv interface{}, ctx c
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ExternalSecretStorePluginService_WatchSecrets_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchSecretsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ExternalSecretStorePluginServiceServer).WatchSecrets(m, &externalSecretStorePluginServiceWatchSecretsServer{stream})
+}
+
+type ExternalSecretStorePluginService_WatchSecretsServer interface {
+	Send(*WatchSecretsResponse) error
+	grpc.ServerStream
+}
+
+type externalSecretStorePluginServiceWatchSecretsServer struct {
+	grpc.ServerStream
+}
+
+func (x *externalSecretStorePluginServiceWatchSecretsServer) Send(m *WatchSecretsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // ExternalSecretStorePluginService_ServiceDesc is the grpc.ServiceDesc for ExternalSecretStorePluginService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -173,6 +231,12 @@ var ExternalSecretStorePluginService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _ExternalSecretStorePluginService_DeleteKeys_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchSecrets",
+			Handler:       _ExternalSecretStorePluginService_WatchSecrets_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "proto/v1alpha1/ess.proto",
 }
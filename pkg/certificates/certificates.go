@@ -14,7 +14,7 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-// Package certificates loads TLS certificates from a given folder.
+// Package certificates loads, generates, and rotates TLS certificates.
 package certificates
 
 import (
@@ -0,0 +1,142 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificates
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"sync"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	errNoPeerCertificates = "peer presented no certificates"
+	errNotLoaded          = "no TLS material has been loaded"
+	errNoServerName       = "cannot verify server certificate: no server name is configured"
+)
+
+// dynamic holds TLS material that may be replaced while it is in use. The TLS
+// configs it returns always serve its latest material, so that certificates
+// can be renewed without restarting long-running processes.
+type dynamic struct {
+	mx   sync.RWMutex
+	cert *tls.Certificate
+	pool *x509.CertPool
+}
+
+// set the TLS material served by this dynamic config. The existing material
+// is left untouched if the supplied material is invalid.
+func (d *dynamic) set(ca, cert, key []byte) error {
+	c, err := tls.X509KeyPair(cert, key)
+	if err != nil {
+		return errors.Wrap(err, errLoadCert)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return errors.New(errInvalidCA)
+	}
+
+	d.mx.Lock()
+	defer d.mx.Unlock()
+	d.cert = &c
+	d.pool = pool
+	return nil
+}
+
+func (d *dynamic) get() (*tls.Certificate, *x509.CertPool) {
+	d.mx.RLock()
+	defer d.mx.RUnlock()
+	return d.cert, d.pool
+}
+
+func (d *dynamic) getCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	cert, _ := d.get()
+	if cert == nil {
+		return nil, errors.New(errNotLoaded)
+	}
+	return cert, nil
+}
+
+// config returns a TLS config that serves the latest material. Servers
+// require and verify client certificates, while clients verify the server's
+// certificate against the latest CA. Clients must be configured with the
+// server name to verify, though gRPC does so automatically.
+func (d *dynamic) config(isServer bool) *tls.Config {
+	if isServer {
+		base := &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			ClientAuth: tls.RequireAndVerifyClientCert,
+		}
+		return &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			// GetCertificate is superseded by GetConfigForClient, but allows
+			// the latest certificate to be served by a TLS config that does
+			// not verify clients. See WebhookServerTLSOpt.
+			GetCertificate: d.getCertificate,
+			GetConfigForClient: func(_ *tls.ClientHelloInfo) (*tls.Config, error) {
+				cert, pool := d.get()
+				if cert == nil {
+					return nil, errors.New(errNotLoaded)
+				}
+				cfg := base.Clone()
+				cfg.Certificates = []tls.Certificate{*cert}
+				cfg.ClientCAs = pool
+				return cfg, nil
+			},
+		}
+	}
+
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetClientCertificate: func(_ *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, _ := d.get()
+			if cert == nil {
+				return nil, errors.New(errNotLoaded)
+			}
+			return cert, nil
+		},
+		// The CA may change, so we can't use a static RootCAs pool. Instead
+		// we skip the default verification and verify the server's
+		// certificate chain and name against the latest CA ourselves.
+		InsecureSkipVerify: true, //nolint:gosec // We verify the peer in VerifyConnection.
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			if len(cs.PeerCertificates) == 0 {
+				return errors.New(errNoPeerCertificates)
+			}
+			// x509 skips hostname verification when no DNS name is
+			// supplied, so we'd accept any certificate signed by the CA.
+			if cs.ServerName == "" {
+				return errors.New(errNoServerName)
+			}
+			_, pool := d.get()
+			if pool == nil {
+				return errors.New(errNotLoaded)
+			}
+			opts := x509.VerifyOptions{
+				Roots:         pool,
+				DNSName:       cs.ServerName,
+				Intermediates: x509.NewCertPool(),
+			}
+			for _, c := range cs.PeerCertificates[1:] {
+				opts.Intermediates.AddCert(c)
+			}
+			_, err := cs.PeerCertificates[0].Verify(opts)
+			return err
+		},
+	}
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificates
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// Well-known file names for TLS material, as used by Kubernetes TLS secrets.
+const (
+	CACertFileName  = "ca.crt"
+	CAKeyFileName   = "ca.key"
+	TLSCertFileName = "tls.crt"
+	TLSKeyFileName  = "tls.key"
+)
+
+const (
+	errGenerateKey    = "cannot generate private key"
+	errGenerateSerial = "cannot generate serial number"
+	errCreateCert     = "cannot create certificate"
+	errMarshalKey     = "cannot marshal private key"
+	errParseKeyPair   = "cannot parse key pair"
+	errParseCert      = "cannot parse certificate"
+	errNoCertificate  = "key pair contains no certificate"
+)
+
+// A KeyPair is a PEM encoded certificate and private key.
+type KeyPair struct {
+	Cert []byte
+	Key  []byte
+}
+
+// Certificate returns the parsed certificate of this KeyPair.
+func (kp *KeyPair) Certificate() (*x509.Certificate, error) {
+	c, err := tls.X509KeyPair(kp.Cert, kp.Key)
+	if err != nil {
+		return nil, errors.Wrap(err, errParseKeyPair)
+	}
+	if len(c.Certificate) == 0 {
+		return nil, errors.New(errNoCertificate)
+	}
+	cert, err := x509.ParseCertificate(c.Certificate[0])
+	return cert, errors.Wrap(err, errParseCert)
+}
+
+// NeedsRotation returns true if this KeyPair is invalid, or if its certificate
+// expires within the supplied duration of the supplied time.
+func (kp *KeyPair) NeedsRotation(now time.Time, before time.Duration) bool {
+	if kp == nil {
+		return true
+	}
+	c, err := kp.Certificate()
+	if err != nil {
+		return true
+	}
+	return now.Add(before).After(c.NotAfter)
+}
+
+// NewCA returns a new self-signed certificate authority that is valid for the
+// supplied duration.
+func NewCA(commonName string, validity time.Duration) (*KeyPair, error) {
+	tmpl := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: commonName},
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	return newKeyPair(tmpl, nil, validity)
+}
+
+// NewServingCert returns a new serving certificate for the supplied DNS names,
+// signed by the supplied certificate authority and valid for the supplied
+// duration.
+func NewServingCert(ca *KeyPair, dnsNames []string, validity time.Duration) (*KeyPair, error) {
+	cn := ""
+	if len(dnsNames) > 0 {
+		cn = dnsNames[0]
+	}
+	tmpl := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: cn},
+		DNSNames:    dnsNames,
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	return newKeyPair(tmpl, ca, validity)
+}
+
+// NewClientCert returns a new client certificate with the supplied common name,
+// signed by the supplied certificate authority and valid for the supplied
+// duration.
+func NewClientCert(ca *KeyPair, commonName string, validity time.Duration) (*KeyPair, error) {
+	tmpl := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: commonName},
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	return newKeyPair(tmpl, ca, validity)
+}
+
+func newKeyPair(tmpl *x509.Certificate, ca *KeyPair, validity time.Duration) (*KeyPair, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, errors.Wrap(err, errGenerateKey)
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, errors.Wrap(err, errGenerateSerial)
+	}
+
+	now := time.Now()
+	tmpl.SerialNumber = serial
+	tmpl.NotBefore = now.Add(-5 * time.Minute) // Allow for clock skew.
+	tmpl.NotAfter = now.Add(validity)
+
+	// Self-sign unless we were given a CA.
+	parent, signer := tmpl, any(key)
+	if ca != nil {
+		c, err := tls.X509KeyPair(ca.Cert, ca.Key)
+		if err != nil {
+			return nil, errors.Wrap(err, errParseKeyPair)
+		}
+		if parent, err = ca.Certificate(); err != nil {
+			return nil, err
+		}
+		signer = c.PrivateKey
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, key.Public(), signer)
+	if err != nil {
+		return nil, errors.Wrap(err, errCreateCert)
+	}
+	k, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, errors.Wrap(err, errMarshalKey)
+	}
+
+	return &KeyPair{
+		Cert: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		Key:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: k}),
+	}, nil
+}
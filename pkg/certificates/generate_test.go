@@ -0,0 +1,104 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificates
+
+import (
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewServingCert(t *testing.T) {
+	ca, err := NewCA("cool-ca", time.Hour)
+	if err != nil {
+		t.Fatalf("NewCA(...): %v", err)
+	}
+	cert, err := NewServingCert(ca, []string{"cool.svc", "cool.svc.cluster.local"}, time.Hour)
+	if err != nil {
+		t.Fatalf("NewServingCert(...): %v", err)
+	}
+
+	cac, err := ca.Certificate()
+	if err != nil {
+		t.Fatalf("ca.Certificate(): %v", err)
+	}
+	if !cac.IsCA {
+		t.Errorf("ca.Certificate(): want a CA certificate")
+	}
+
+	c, err := cert.Certificate()
+	if err != nil {
+		t.Fatalf("cert.Certificate(): %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cac)
+	if _, err := c.Verify(x509.VerifyOptions{DNSName: "cool.svc", Roots: pool}); err != nil {
+		t.Errorf("c.Verify(...): %v", err)
+	}
+	if diff := cmp.Diff([]string{"cool.svc", "cool.svc.cluster.local"}, c.DNSNames); diff != "" {
+		t.Errorf("c.DNSNames: -want, +got:\n%s", diff)
+	}
+}
+
+func TestNeedsRotation(t *testing.T) {
+	kp, err := NewCA("cool-ca", time.Hour)
+	if err != nil {
+		t.Fatalf("NewCA(...): %v", err)
+	}
+
+	cases := map[string]struct {
+		reason string
+		kp     *KeyPair
+		before time.Duration
+		want   bool
+	}{
+		"Nil": {
+			reason: "A nil key pair needs rotation.",
+			kp:     nil,
+			want:   true,
+		},
+		"Invalid": {
+			reason: "An invalid key pair needs rotation.",
+			kp:     &KeyPair{Cert: []byte("cert"), Key: []byte("key")},
+			want:   true,
+		},
+		"NotDue": {
+			reason: "A key pair that expires after the renewal window does not need rotation.",
+			kp:     kp,
+			before: 30 * time.Minute,
+			want:   false,
+		},
+		"Due": {
+			reason: "A key pair that expires within the renewal window needs rotation.",
+			kp:     kp,
+			before: 2 * time.Hour,
+			want:   true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.kp.NeedsRotation(time.Now(), tc.before)
+			if got != tc.want {
+				t.Errorf("\n%s\nkp.NeedsRotation(...): want %t, got %t", tc.reason, tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificates
+
+import (
+	"bytes"
+	"context"
+
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	errGetWebhookConfig    = "cannot get webhook configuration"
+	errUpdateWebhookConfig = "cannot update webhook configuration"
+	errGetCRD              = "cannot get CustomResourceDefinition"
+	errUpdateCRD           = "cannot update CustomResourceDefinition"
+	errCRDNoWebhook        = "CustomResourceDefinition does not use a conversion webhook"
+)
+
+// A CABundleInjector injects a CA bundle into the configuration of a webhook,
+// so that the API server trusts the webhook's serving certificate.
+type CABundleInjector interface {
+	InjectCABundle(ctx context.Context, c client.Client, bundle []byte) error
+}
+
+// A CABundleInjectorFn is a function that satisfies CABundleInjector.
+type CABundleInjectorFn func(ctx context.Context, c client.Client, bundle []byte) error
+
+// InjectCABundle into the configuration of a webhook.
+func (fn CABundleInjectorFn) InjectCABundle(ctx context.Context, c client.Client, bundle []byte) error {
+	return fn(ctx, c, bundle)
+}
+
+// MutatingWebhookConfiguration returns a CABundleInjector that injects a CA
+// bundle into all webhooks of the named MutatingWebhookConfiguration.
+func MutatingWebhookConfiguration(name string) CABundleInjector {
+	return CABundleInjectorFn(func(ctx context.Context, c client.Client, bundle []byte) error {
+		wc := &admissionv1.MutatingWebhookConfiguration{}
+		if err := c.Get(ctx, types.NamespacedName{Name: name}, wc); err != nil {
+			return errors.Wrap(err, errGetWebhookConfig)
+		}
+		changed := false
+		for i := range wc.Webhooks {
+			if !bytes.Equal(wc.Webhooks[i].ClientConfig.CABundle, bundle) {
+				wc.Webhooks[i].ClientConfig.CABundle = bundle
+				changed = true
+			}
+		}
+		if !changed {
+			return nil
+		}
+		return errors.Wrap(c.Update(ctx, wc), errUpdateWebhookConfig)
+	})
+}
+
+// ValidatingWebhookConfiguration returns a CABundleInjector that injects a CA
+// bundle into all webhooks of the named ValidatingWebhookConfiguration.
+func ValidatingWebhookConfiguration(name string) CABundleInjector {
+	return CABundleInjectorFn(func(ctx context.Context, c client.Client, bundle []byte) error {
+		wc := &admissionv1.ValidatingWebhookConfiguration{}
+		if err := c.Get(ctx, types.NamespacedName{Name: name}, wc); err != nil {
+			return errors.Wrap(err, errGetWebhookConfig)
+		}
+		changed := false
+		for i := range wc.Webhooks {
+			if !bytes.Equal(wc.Webhooks[i].ClientConfig.CABundle, bundle) {
+				wc.Webhooks[i].ClientConfig.CABundle = bundle
+				changed = true
+			}
+		}
+		if !changed {
+			return nil
+		}
+		return errors.Wrap(c.Update(ctx, wc), errUpdateWebhookConfig)
+	})
+}
+
+// CustomResourceDefinition returns a CABundleInjector that injects a CA bundle
+// into the conversion webhook configuration of the named CRD.
+func CustomResourceDefinition(name string) CABundleInjector {
+	return CABundleInjectorFn(func(ctx context.Context, c client.Client, bundle []byte) error {
+		crd := &extv1.CustomResourceDefinition{}
+		if err := c.Get(ctx, types.NamespacedName{Name: name}, crd); err != nil {
+			return errors.Wrap(err, errGetCRD)
+		}
+		cv := crd.Spec.Conversion
+		if cv == nil || cv.Webhook == nil || cv.Webhook.ClientConfig == nil {
+			return errors.New(errCRDNoWebhook)
+		}
+		if bytes.Equal(cv.Webhook.ClientConfig.CABundle, bundle) {
+			return nil
+		}
+		cv.Webhook.ClientConfig.CABundle = bundle
+		return errors.Wrap(c.Update(ctx, crd), errUpdateCRD)
+	})
+}
@@ -0,0 +1,364 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificates
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const (
+	errGetSecret     = "cannot get certificate secret"
+	errCreateSecret  = "cannot create certificate secret"
+	errUpdateSecret  = "cannot update certificate secret"
+	errGenerateCA    = "cannot generate CA certificate"
+	errGenerateCert  = "cannot generate serving certificate"
+	errWriteCertDir  = "cannot write certificates to directory"
+	errInjectCA      = "cannot inject CA bundle"
+	errRotateOnStart = "cannot rotate certificates on start"
+)
+
+// CABundleFileName is the key of a Rotator's Secret that holds the CA bundle
+// injected into webhook configurations. It contains the current CA, and any
+// previous CA that has not yet expired.
+const CABundleFileName = "ca-bundle.crt"
+
+// Defaults used by a Rotator.
+const (
+	DefaultCAValidity    = 10 * 365 * 24 * time.Hour
+	DefaultCertValidity  = 365 * 24 * time.Hour
+	DefaultRenewBefore   = 30 * 24 * time.Hour
+	DefaultCheckInterval = 1 * time.Hour
+)
+
+// A RotatorOption configures a Rotator.
+type RotatorOption func(r *Rotator)
+
+// WithCABundleInjectors configures the injectors a Rotator uses to inject its
+// CA bundle into webhook configurations.
+func WithCABundleInjectors(i ...CABundleInjector) RotatorOption {
+	return func(r *Rotator) {
+		r.injectors = append(r.injectors, i...)
+	}
+}
+
+// WithValidity configures how long the CA and serving certificates generated
+// by a Rotator are valid for.
+func WithValidity(ca, cert time.Duration) RotatorOption {
+	return func(r *Rotator) {
+		r.caValidity = ca
+		r.certValidity = cert
+	}
+}
+
+// WithRenewBefore configures how long before expiry a Rotator renews a
+// certificate.
+func WithRenewBefore(d time.Duration) RotatorOption {
+	return func(r *Rotator) {
+		r.renewBefore = d
+	}
+}
+
+// WithCheckInterval configures how often a Rotator checks whether its
+// certificates need to be renewed.
+func WithCheckInterval(d time.Duration) RotatorOption {
+	return func(r *Rotator) {
+		r.interval = d
+	}
+}
+
+// WithLogger configures the logger used by a Rotator.
+func WithLogger(l logging.Logger) RotatorOption {
+	return func(r *Rotator) {
+		r.log = l
+	}
+}
+
+// A Rotator bootstraps a self-signed CA and a webhook serving certificate
+// signed by it, and renews them before they expire. The CA and certificate
+// are persisted in a Secret so that they are shared by all replicas of a
+// webhook server, written to the directory the webhook server loads its
+// certificates from, and injected into the configurations of the webhooks
+// served by the webhook server.
+type Rotator struct {
+	client   client.Client
+	secret   types.NamespacedName
+	certDir  string
+	dnsNames []string
+
+	injectors    []CABundleInjector
+	caValidity   time.Duration
+	certValidity time.Duration
+	renewBefore  time.Duration
+	interval     time.Duration
+	log          logging.Logger
+}
+
+// NewRotator returns a Rotator that persists certificates for the supplied DNS
+// names in the supplied Secret, and writes them to the supplied directory.
+func NewRotator(c client.Client, secret types.NamespacedName, certDir string, dnsNames []string, o ...RotatorOption) *Rotator {
+	r := &Rotator{
+		client:       c,
+		secret:       secret,
+		certDir:      certDir,
+		dnsNames:     dnsNames,
+		caValidity:   DefaultCAValidity,
+		certValidity: DefaultCertValidity,
+		renewBefore:  DefaultRenewBefore,
+		interval:     DefaultCheckInterval,
+		log:          logging.NewNopLogger(),
+	}
+	for _, fn := range o {
+		fn(r)
+	}
+	return r
+}
+
+// NeedLeaderElection returns false, because every replica of a webhook server
+// needs certificates.
+func (r *Rotator) NeedLeaderElection() bool {
+	return false
+}
+
+// Start rotating certificates. Start returns an error if certificates cannot
+// be rotated on start. Subsequent failures are logged and retried at the next
+// check interval. Start blocks until the supplied context is done.
+func (r *Rotator) Start(ctx context.Context) error {
+	if err := r.Rotate(ctx); err != nil {
+		return errors.Wrap(err, errRotateOnStart)
+	}
+
+	t := time.NewTicker(r.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+			if err := r.Rotate(ctx); err != nil {
+				r.log.Info("Cannot rotate certificates", "error", err)
+			}
+		}
+	}
+}
+
+// Rotate generates a new CA and serving certificate if they don't exist, or
+// are due to expire. It then injects the CA bundle into all configured webhook
+// configurations and writes the certificates to the certificate directory.
+//
+// When a new CA is generated the previous CA remains in the CA bundle until it
+// expires, so that the API server continues to trust replicas of the webhook
+// server that have not yet loaded a serving certificate signed by the new CA.
+func (r *Rotator) Rotate(ctx context.Context) error {
+	err := r.rotate(ctx)
+	if resource.IsAnyOf(kerrors.IsAlreadyExists, kerrors.IsConflict)(err) {
+		// Another replica created or updated the Secret after we read it.
+		// Try again using what it wrote.
+		r.log.Debug("Secret was written concurrently, retrying", "secret", r.secret)
+		err = r.rotate(ctx)
+	}
+	return err
+}
+
+func (r *Rotator) rotate(ctx context.Context) error {
+	s := &corev1.Secret{}
+	err := r.client.Get(ctx, r.secret, s)
+	if resource.IgnoreNotFound(err) != nil {
+		return errors.Wrap(err, errGetSecret)
+	}
+	exists := err == nil
+
+	ca := &KeyPair{Cert: s.Data[CACertFileName], Key: s.Data[CAKeyFileName]}
+	cert := &KeyPair{Cert: s.Data[TLSCertFileName], Key: s.Data[TLSKeyFileName]}
+	bundle := s.Data[CABundleFileName]
+	now := time.Now()
+	changed := false
+
+	if ca.NeedsRotation(now, r.renewBefore) {
+		r.log.Debug("Generating CA certificate", "secret", r.secret)
+		previous := ca.Cert
+		if ca, err = NewCA(r.secret.Name, r.caValidity); err != nil {
+			return errors.Wrap(err, errGenerateCA)
+		}
+		bundle = append(append([]byte{}, bundle...), previous...)
+		// A serving certificate must be signed by the current CA.
+		cert = nil
+		changed = true
+	}
+
+	if cert.NeedsRotation(now, r.renewBefore) || !r.covers(cert) {
+		r.log.Debug("Generating serving certificate", "secret", r.secret, "dns-names", r.dnsNames)
+		if cert, err = NewServingCert(ca, r.dnsNames, r.certValidity); err != nil {
+			return errors.Wrap(err, errGenerateCert)
+		}
+		changed = true
+	}
+
+	if b := newBundle(ca.Cert, bundle, now); !bytes.Equal(b, s.Data[CABundleFileName]) {
+		bundle = b
+		changed = true
+	}
+
+	if changed {
+		s.SetName(r.secret.Name)
+		s.SetNamespace(r.secret.Namespace)
+		s.Type = corev1.SecretTypeOpaque
+		s.Data = map[string][]byte{
+			CACertFileName:   ca.Cert,
+			CAKeyFileName:    ca.Key,
+			CABundleFileName: bundle,
+			TLSCertFileName:  cert.Cert,
+			TLSKeyFileName:   cert.Key,
+		}
+		if !exists {
+			if err := r.client.Create(ctx, s); err != nil {
+				return errors.Wrap(err, errCreateSecret)
+			}
+		} else if err := r.client.Update(ctx, s); err != nil {
+			return errors.Wrap(err, errUpdateSecret)
+		}
+	}
+
+	// Inject the CA bundle before we start serving a certificate that may be
+	// signed by a new CA.
+	for _, i := range r.injectors {
+		if err := i.InjectCABundle(ctx, r.client, bundle); err != nil {
+			return errors.Wrap(err, errInjectCA)
+		}
+	}
+
+	return errors.Wrap(r.write(ca, cert), errWriteCertDir)
+}
+
+// newBundle returns a PEM encoded CA bundle containing the supplied current CA
+// certificate, followed by each other certificate in the supplied bundle that
+// has not expired.
+func newBundle(current, bundle []byte, now time.Time) []byte {
+	out := append([]byte{}, current...)
+	seen := map[string]bool{}
+	if cb, _ := pem.Decode(current); cb != nil {
+		seen[string(cb.Bytes)] = true
+	}
+	for {
+		var b *pem.Block
+		b, bundle = pem.Decode(bundle)
+		if b == nil {
+			return out
+		}
+		if seen[string(b.Bytes)] {
+			continue
+		}
+		seen[string(b.Bytes)] = true
+		c, err := x509.ParseCertificate(b.Bytes)
+		if err != nil || !now.Before(c.NotAfter) {
+			continue
+		}
+		out = append(out, pem.EncodeToMemory(b)...)
+	}
+}
+
+// covers returns true if the supplied serving certificate is valid for exactly
+// the DNS names of this Rotator.
+func (r *Rotator) covers(kp *KeyPair) bool {
+	c, err := kp.Certificate()
+	if err != nil {
+		return false
+	}
+	return equalNames(c, r.dnsNames)
+}
+
+func equalNames(c *x509.Certificate, names []string) bool {
+	got := append([]string{}, c.DNSNames...)
+	want := append([]string{}, names...)
+	sort.Strings(got)
+	sort.Strings(want)
+	return reflect.DeepEqual(got, want)
+}
+
+// write the supplied key pairs to the certificate directory, if they differ
+// from what's already there. A webhook server watching the directory will
+// load the new certificate without needing to be restarted. Each file is
+// written to a temporary file that is then renamed, so that a watcher never
+// reads a partially written file. The certificate and key are renamed only
+// once both have been written, which minimises the window in which a watcher
+// may observe a mismatched pair. Watchers reject mismatched pairs, and load
+// the new pair when the rename of the second file is observed.
+func (r *Rotator) write(ca, cert *KeyPair) error {
+	if err := os.MkdirAll(r.certDir, 0o700); err != nil {
+		return err
+	}
+	files := []struct {
+		name string
+		data []byte
+	}{
+		{name: CACertFileName, data: ca.Cert},
+		{name: TLSKeyFileName, data: cert.Key},
+		{name: TLSCertFileName, data: cert.Cert},
+	}
+
+	type rename struct{ from, to string }
+	renames := make([]rename, 0, len(files))
+	defer func() {
+		// Clean up any temporary files that were not renamed.
+		for _, rn := range renames {
+			_ = os.Remove(rn.from)
+		}
+	}()
+
+	for _, f := range files {
+		path := filepath.Join(r.certDir, f.name)
+		if existing, err := os.ReadFile(filepath.Clean(path)); err == nil && bytes.Equal(existing, f.data) {
+			continue
+		}
+		tmp, err := os.CreateTemp(r.certDir, "."+f.name+"-")
+		if err != nil {
+			return err
+		}
+		renames = append(renames, rename{from: tmp.Name(), to: path})
+		if _, err := tmp.Write(f.data); err != nil {
+			_ = tmp.Close()
+			return err
+		}
+		if err := tmp.Close(); err != nil {
+			return err
+		}
+	}
+
+	for len(renames) > 0 {
+		if err := os.Rename(renames[0].from, renames[0].to); err != nil {
+			return err
+		}
+		renames = renames[1:]
+	}
+	return nil
+}
@@ -0,0 +1,286 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificates
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestRotate(t *testing.T) {
+	errBoom := errors.New("boom")
+	secret := types.NamespacedName{Namespace: "crossplane-system", Name: "webhook-tls"}
+	names := []string{"cool.crossplane-system.svc"}
+	notFound := kerrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, secret.Name)
+
+	ca, err := NewCA("cool", time.Hour*24*365)
+	if err != nil {
+		t.Fatalf("NewCA(...): %v", err)
+	}
+	cert, err := NewServingCert(ca, names, time.Hour*24*365)
+	if err != nil {
+		t.Fatalf("NewServingCert(...): %v", err)
+	}
+	other, err := NewServingCert(ca, []string{"other.svc"}, time.Hour*24*365)
+	if err != nil {
+		t.Fatalf("NewServingCert(...): %v", err)
+	}
+	expiring, err := NewCA("cool", time.Hour*24)
+	if err != nil {
+		t.Fatalf("NewCA(...): %v", err)
+	}
+	expiringCert, err := NewServingCert(expiring, names, time.Hour*24)
+	if err != nil {
+		t.Fatalf("NewServingCert(...): %v", err)
+	}
+
+	data := func(ca, cert *KeyPair, bundle []byte) map[string][]byte {
+		return map[string][]byte{
+			CACertFileName:   ca.Cert,
+			CAKeyFileName:    ca.Key,
+			CABundleFileName: bundle,
+			TLSCertFileName:  cert.Cert,
+			TLSKeyFileName:   cert.Key,
+		}
+	}
+
+	type args struct {
+		data      map[string][]byte
+		getErr    error
+		createErr error
+	}
+	type want struct {
+		err     error
+		created bool
+		updated bool
+		sameCA  bool
+		bundle  []*KeyPair
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"GetError": {
+			reason: "We should return any error encountered getting the Secret.",
+			args: args{
+				getErr: errBoom,
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetSecret),
+			},
+		},
+		"Bootstrap": {
+			reason: "We should generate and persist a new CA and certificate if the Secret does not exist.",
+			args: args{
+				getErr: notFound,
+			},
+			want: want{
+				created: true,
+			},
+		},
+		"CreatedConcurrently": {
+			reason: "We should use the Secret created by another replica if it beats us to creating it.",
+			args: args{
+				data:      data(ca, cert, ca.Cert),
+				getErr:    notFound,
+				createErr: kerrors.NewAlreadyExists(schema.GroupResource{Resource: "secrets"}, secret.Name),
+			},
+			want: want{
+				created: true,
+				sameCA:  true,
+				bundle:  []*KeyPair{ca},
+			},
+		},
+		"UpToDate": {
+			reason: "We should not update the Secret if its certificates are valid.",
+			args: args{
+				data: data(ca, cert, ca.Cert),
+			},
+			want: want{
+				sameCA: true,
+				bundle: []*KeyPair{ca},
+			},
+		},
+		"NoBundle": {
+			reason: "We should add a CA bundle to a Secret that does not have one.",
+			args: args{
+				data: data(ca, cert, nil),
+			},
+			want: want{
+				updated: true,
+				sameCA:  true,
+				bundle:  []*KeyPair{ca},
+			},
+		},
+		"NamesChanged": {
+			reason: "We should issue a new serving certificate, signed by the existing CA, if the DNS names change.",
+			args: args{
+				data: data(ca, other, ca.Cert),
+			},
+			want: want{
+				updated: true,
+				sameCA:  true,
+				bundle:  []*KeyPair{ca},
+			},
+		},
+		"CAExpiring": {
+			reason: "We should generate a new CA if the existing CA is due to expire, and keep trusting the existing CA until it expires.",
+			args: args{
+				data: data(expiring, expiringCert, expiring.Cert),
+			},
+			want: want{
+				updated: true,
+				bundle:  []*KeyPair{nil, expiring},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			created, updated, gets := false, false, 0
+			var injected []byte
+			var persisted map[string][]byte
+
+			c := &test.MockClient{
+				MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+					gets++
+					if gets == 1 && tc.args.getErr != nil {
+						return tc.args.getErr
+					}
+					obj.(*corev1.Secret).Data = tc.args.data
+					persisted = tc.args.data
+					return nil
+				},
+				MockCreate: func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+					created = true
+					if tc.args.createErr != nil {
+						return tc.args.createErr
+					}
+					persisted = obj.(*corev1.Secret).Data
+					return nil
+				},
+				MockUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+					updated = true
+					persisted = obj.(*corev1.Secret).Data
+					return nil
+				},
+			}
+			inject := CABundleInjectorFn(func(_ context.Context, _ client.Client, bundle []byte) error {
+				injected = bundle
+				return nil
+			})
+
+			r := NewRotator(c, secret, dir, names, WithCABundleInjectors(inject))
+			err := r.Rotate(context.Background())
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nr.Rotate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if tc.want.err != nil {
+				return
+			}
+			if created != tc.want.created {
+				t.Errorf("\n%s\nr.Rotate(...): want created %t, got %t", tc.reason, tc.want.created, created)
+			}
+			if updated != tc.want.updated {
+				t.Errorf("\n%s\nr.Rotate(...): want updated %t, got %t", tc.reason, tc.want.updated, updated)
+			}
+			if got := bytes.Equal(persisted[CACertFileName], ca.Cert); got != tc.want.sameCA {
+				t.Errorf("\n%s\nr.Rotate(...): want same CA %t, got %t", tc.reason, tc.want.sameCA, got)
+			}
+			if diff := cmp.Diff(persisted[CABundleFileName], injected); diff != "" {
+				t.Errorf("\n%s\nr.Rotate(...): -want injected CA bundle, +got:\n%s", tc.reason, diff)
+			}
+			if tc.want.bundle != nil {
+				// A nil KeyPair refers to the newly generated CA.
+				want := []byte{}
+				for _, kp := range tc.want.bundle {
+					if kp == nil {
+						kp = &KeyPair{Cert: persisted[CACertFileName]}
+					}
+					want = append(want, kp.Cert...)
+				}
+				if diff := cmp.Diff(want, injected); diff != "" {
+					t.Errorf("\n%s\nr.Rotate(...): -want CA bundle, +got:\n%s", tc.reason, diff)
+				}
+			}
+			for _, f := range []string{CACertFileName, TLSCertFileName, TLSKeyFileName} {
+				got, err := os.ReadFile(filepath.Join(dir, f))
+				if err != nil {
+					t.Fatalf("\n%s\nos.ReadFile(%s): %v", tc.reason, f, err)
+				}
+				if diff := cmp.Diff(persisted[f], got); diff != "" {
+					t.Errorf("\n%s\nr.Rotate(...): -want %s, +got:\n%s", tc.reason, f, diff)
+				}
+			}
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatalf("\n%s\nos.ReadDir(...): %v", tc.reason, err)
+			}
+			if len(entries) != 3 {
+				t.Errorf("\n%s\nr.Rotate(...): want only certificate files in %s, got %d files", tc.reason, dir, len(entries))
+			}
+			kp := &KeyPair{Cert: persisted[TLSCertFileName], Key: persisted[TLSKeyFileName]}
+			if !r.covers(kp) {
+				t.Errorf("\n%s\nr.Rotate(...): serving certificate does not cover DNS names %v", tc.reason, names)
+			}
+		})
+	}
+}
+
+func TestValidatingWebhookConfiguration(t *testing.T) {
+	bundle := []byte("bundle")
+	var got *admissionv1.ValidatingWebhookConfiguration
+
+	c := &test.MockClient{
+		MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			obj.(*admissionv1.ValidatingWebhookConfiguration).Webhooks = []admissionv1.ValidatingWebhook{{Name: "a"}, {Name: "b"}}
+			return nil
+		},
+		MockUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+			got = obj.(*admissionv1.ValidatingWebhookConfiguration)
+			return nil
+		},
+	}
+
+	if err := ValidatingWebhookConfiguration("cool").InjectCABundle(context.Background(), c, bundle); err != nil {
+		t.Fatalf("InjectCABundle(...): %v", err)
+	}
+	for _, wh := range got.Webhooks {
+		if diff := cmp.Diff(bundle, wh.ClientConfig.CABundle); diff != "" {
+			t.Errorf("InjectCABundle(...): webhook %s: -want, +got:\n%s", wh.Name, diff)
+		}
+	}
+}
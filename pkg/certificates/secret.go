@@ -0,0 +1,148 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificates
+
+import (
+	"context"
+	"crypto/tls"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+const errLoadSecret = "cannot load TLS material from secret"
+
+// DefaultSecretPollInterval is how often a SecretWatcher checks its Secret
+// for renewed TLS material by default.
+const DefaultSecretPollInterval = 1 * time.Minute
+
+// A SecretWatcherOption configures a SecretWatcher.
+type SecretWatcherOption func(w *SecretWatcher)
+
+// WithSecretPollInterval configures how often a SecretWatcher checks its
+// Secret for renewed TLS material.
+func WithSecretPollInterval(d time.Duration) SecretWatcherOption {
+	return func(w *SecretWatcher) {
+		w.interval = d
+	}
+}
+
+// WithSecretWatcherLogger configures the logger used by a SecretWatcher.
+func WithSecretWatcherLogger(l logging.Logger) SecretWatcherOption {
+	return func(w *SecretWatcher) {
+		w.log = l
+	}
+}
+
+// A SecretWatcher sources TLS material from a Kubernetes TLS Secret, such as
+// one issued and renewed by cert-manager. The Secret must contain the
+// well-known ca.crt, tls.crt, and tls.key keys. TLS configs returned by a
+// SecretWatcher always serve the latest material from the Secret, so a
+// long-running process picks up renewed certificates without restarting.
+//
+// A SecretWatcher is typically supplied a controller-runtime client backed by
+// a cache, in which case checking the Secret for changes is served by a watch
+// rather than a call to the API server.
+type SecretWatcher struct {
+	client   client.Reader
+	secret   types.NamespacedName
+	interval time.Duration
+	log      logging.Logger
+
+	dynamic
+
+	// loading serializes calls to Load, and protects version.
+	loading sync.Mutex
+	version string
+}
+
+// NewSecretWatcher returns a SecretWatcher that sources TLS material from the
+// supplied Secret.
+func NewSecretWatcher(c client.Reader, secret types.NamespacedName, o ...SecretWatcherOption) *SecretWatcher {
+	w := &SecretWatcher{
+		client:   c,
+		secret:   secret,
+		interval: DefaultSecretPollInterval,
+		log:      logging.NewNopLogger(),
+	}
+	for _, fn := range o {
+		fn(w)
+	}
+	return w
+}
+
+// Load the latest TLS material from the Secret. Load is a no-op if the Secret
+// has not changed since it was last loaded.
+func (w *SecretWatcher) Load(ctx context.Context) error {
+	w.loading.Lock()
+	defer w.loading.Unlock()
+
+	s := &corev1.Secret{}
+	if err := w.client.Get(ctx, w.secret, s); err != nil {
+		return errors.Wrap(err, errLoadSecret)
+	}
+	if s.GetResourceVersion() != "" && s.GetResourceVersion() == w.version {
+		return nil
+	}
+	if err := w.set(s.Data[CACertFileName], s.Data[TLSCertFileName], s.Data[TLSKeyFileName]); err != nil {
+		return errors.Wrap(err, errLoadSecret)
+	}
+	w.version = s.GetResourceVersion()
+	w.log.Debug("Loaded TLS material", "secret", w.secret, "resource-version", w.version)
+	return nil
+}
+
+// TLSConfig returns a TLS config that serves the latest TLS material loaded
+// from the Secret. Load must be called successfully before the TLS config is
+// used.
+func (w *SecretWatcher) TLSConfig(isServer bool) *tls.Config {
+	return w.config(isServer)
+}
+
+// NeedLeaderElection returns false, because every replica needs TLS material.
+func (w *SecretWatcher) NeedLeaderElection() bool {
+	return false
+}
+
+// Start watching the Secret for renewed TLS material. Start returns an error
+// if TLS material cannot be loaded on start. Subsequent failures are logged,
+// and the previously loaded material continues to be served. Start blocks
+// until the supplied context is done.
+func (w *SecretWatcher) Start(ctx context.Context) error {
+	if err := w.Load(ctx); err != nil {
+		return err
+	}
+
+	t := time.NewTicker(w.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+			if err := w.Load(ctx); err != nil {
+				w.log.Info("Cannot reload TLS material", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificates
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+// handshake performs a TLS handshake between the supplied configs over a
+// loopback connection, and returns the serial number of the certificate the
+// server presented. We don't use net.Pipe, which is unbuffered, because both
+// sides may write at once when a handshake fails.
+func handshake(t *testing.T, server, client *tls.Config) (string, error) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen(...): %v", err)
+	}
+	defer l.Close() //nolint:errcheck // Only a test.
+
+	// Don't hang forever if one side of the handshake never completes.
+	deadline := time.Now().Add(10 * time.Second)
+
+	errs := make(chan error, 1)
+	go func() {
+		sc, err := l.Accept()
+		if err != nil {
+			errs <- err
+			return
+		}
+		defer sc.Close() //nolint:errcheck // Only a test.
+		_ = sc.SetDeadline(deadline)
+		errs <- tls.Server(sc, server).Handshake()
+	}()
+
+	cc, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatalf("net.Dial(...): %v", err)
+	}
+	defer cc.Close() //nolint:errcheck // Only a test.
+	_ = cc.SetDeadline(deadline)
+
+	c := tls.Client(cc, client)
+	if err := c.Handshake(); err != nil {
+		return "", err
+	}
+
+	// In TLS 1.3 the client finishes its handshake before the server has
+	// verified the client's certificate, so we must wait for the server.
+	if err := <-errs; err != nil {
+		return "", err
+	}
+	return c.ConnectionState().PeerCertificates[0].SerialNumber.String(), nil
+}
+
+// keyPairs returns a CA, a client certificate, and two serving certificates
+// for cool.svc, all signed by the CA.
+func keyPairs(t *testing.T) (ca, client, server1, server2 *KeyPair) {
+	t.Helper()
+	var err error
+	if ca, err = NewCA("cool-ca", time.Hour); err != nil {
+		t.Fatalf("NewCA(...): %v", err)
+	}
+	if client, err = NewClientCert(ca, "cool-client", time.Hour); err != nil {
+		t.Fatalf("NewClientCert(...): %v", err)
+	}
+	if server1, err = NewServingCert(ca, []string{"cool.svc"}, time.Hour); err != nil {
+		t.Fatalf("NewServingCert(...): %v", err)
+	}
+	if server2, err = NewServingCert(ca, []string{"cool.svc"}, time.Hour); err != nil {
+		t.Fatalf("NewServingCert(...): %v", err)
+	}
+	return ca, client, server1, server2
+}
+
+// serial returns the serial number of the supplied key pair's certificate.
+func serial(t *testing.T, kp *KeyPair) string {
+	t.Helper()
+	c, err := kp.Certificate()
+	if err != nil {
+		t.Fatalf("kp.Certificate(): %v", err)
+	}
+	return c.SerialNumber.String()
+}
+
+func TestSecretWatcher(t *testing.T) {
+	ca, client1, server1, server2 := keyPairs(t)
+
+	secret := func(version string, kp *KeyPair) *corev1.Secret {
+		s := &corev1.Secret{Data: map[string][]byte{
+			CACertFileName:  ca.Cert,
+			TLSCertFileName: kp.Cert,
+			TLSKeyFileName:  kp.Key,
+		}}
+		s.SetResourceVersion(version)
+		return s
+	}
+	invalid := &corev1.Secret{Data: map[string][]byte{CACertFileName: []byte("nope")}}
+	invalid.SetResourceVersion("3")
+
+	type args struct {
+		// secrets are loaded in order, before the handshake.
+		secrets    []*corev1.Secret
+		serverName string
+	}
+	type want struct {
+		loadErr bool
+		serial  string
+		err     bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotLoaded": {
+			reason: "TLS configs should fail closed until material has been loaded.",
+			args: args{
+				serverName: "cool.svc",
+			},
+			want: want{
+				err: true,
+			},
+		},
+		"Loaded": {
+			reason: "The loaded serving certificate should be served.",
+			args: args{
+				secrets:    []*corev1.Secret{secret("1", server1)},
+				serverName: "cool.svc",
+			},
+			want: want{
+				serial: serial(t, server1),
+			},
+		},
+		"Renewed": {
+			reason: "A renewed serving certificate should be served once the Secret is reloaded.",
+			args: args{
+				secrets:    []*corev1.Secret{secret("1", server1), secret("2", server2)},
+				serverName: "cool.svc",
+			},
+			want: want{
+				serial: serial(t, server2),
+			},
+		},
+		"Invalid": {
+			reason: "Invalid material should not replace valid material.",
+			args: args{
+				secrets:    []*corev1.Secret{secret("1", server1), invalid},
+				serverName: "cool.svc",
+			},
+			want: want{
+				loadErr: true,
+				serial:  serial(t, server1),
+			},
+		},
+		"WrongServerName": {
+			reason: "Clients should reject a certificate that is not valid for the server name.",
+			args: args{
+				secrets:    []*corev1.Secret{secret("1", server1)},
+				serverName: "lame.svc",
+			},
+			want: want{
+				err: true,
+			},
+		},
+		"NoServerName": {
+			reason: "Clients should refuse to connect if no server name is configured, rather than accept any certificate signed by the CA.",
+			args: args{
+				secrets: []*corev1.Secret{secret("1", server1)},
+			},
+			want: want{
+				err: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var current *corev1.Secret
+			sc := &test.MockClient{
+				MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+					current.DeepCopyInto(obj.(*corev1.Secret))
+					return nil
+				},
+			}
+			cc := &test.MockClient{
+				MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+					secret("1", client1).DeepCopyInto(obj.(*corev1.Secret))
+					return nil
+				},
+			}
+
+			sw := NewSecretWatcher(sc, types.NamespacedName{Name: "server"})
+			cw := NewSecretWatcher(cc, types.NamespacedName{Name: "client"})
+			if err := cw.Load(context.Background()); err != nil {
+				t.Fatalf("\n%s\ncw.Load(...): %v", tc.reason, err)
+			}
+
+			// Get the TLS config before loading to ensure it serves the
+			// latest material.
+			scfg := sw.TLSConfig(true)
+			var err error
+			for _, s := range tc.args.secrets {
+				current = s
+				err = sw.Load(context.Background())
+			}
+			if (err != nil) != tc.want.loadErr {
+				t.Errorf("\n%s\nsw.Load(...): want error %t, got %v", tc.reason, tc.want.loadErr, err)
+			}
+
+			ccfg := cw.TLSConfig(false)
+			ccfg.ServerName = tc.args.serverName
+			got, err := handshake(t, scfg, ccfg)
+			if (err != nil) != tc.want.err {
+				t.Fatalf("\n%s\nhandshake(...): want error %t, got %v", tc.reason, tc.want.err, err)
+			}
+			if diff := cmp.Diff(tc.want.serial, got); diff != "" {
+				t.Errorf("\n%s\nhandshake(...): -want serial, +got serial:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
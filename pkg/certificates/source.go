@@ -0,0 +1,131 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificates
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	errGetCertificate = "cannot get serving certificate"
+	errWriteCertFiles = "cannot write placeholder certificate files"
+)
+
+// A Source of TLS material.
+type Source interface {
+	// TLSConfig returns a mutual TLS config that serves the latest TLS
+	// material from this Source until the supplied context is done.
+	TLSConfig(ctx context.Context, isServer bool) (*tls.Config, error)
+}
+
+// A SourceFn is a function that satisfies Source.
+type SourceFn func(ctx context.Context, isServer bool) (*tls.Config, error)
+
+// TLSConfig returns a mutual TLS config that serves the latest TLS material
+// from this Source until the supplied context is done.
+func (fn SourceFn) TLSConfig(ctx context.Context, isServer bool) (*tls.Config, error) {
+	return fn(ctx, isServer)
+}
+
+// FromSecret returns a Source of TLS material from the supplied Kubernetes TLS
+// Secret, such as one issued and renewed by cert-manager. The Secret is loaded
+// when a TLS config is requested, and reloaded in the background whenever it
+// changes. The supplied client must be able to read the Secret before any
+// caches are started, for example a manager's API reader.
+func FromSecret(c client.Reader, secret types.NamespacedName, o ...SecretWatcherOption) Source {
+	return SourceFn(func(ctx context.Context, isServer bool) (*tls.Config, error) {
+		w := NewSecretWatcher(c, secret, o...)
+		if err := w.Load(ctx); err != nil {
+			return nil, err
+		}
+		go w.Start(ctx) //nolint:errcheck // Start only returns an error if the initial load fails, which we've checked.
+		return w.TLSConfig(isServer), nil
+	})
+}
+
+// ConfigureWebhookServer configures the supplied controller-runtime webhook
+// server to serve its certificate from the supplied Source, rather than from
+// its certificate directory. Client authentication is left unchanged, because
+// the API server does not present a client certificate by default.
+//
+// The webhook server refuses to start if its certificate directory does not
+// contain a certificate and key, even though they won't be served. If they
+// don't exist they're written using the Source's current certificate.
+func ConfigureWebhookServer(ctx context.Context, s *webhook.Server, src Source) error {
+	cfg, err := src.TLSConfig(ctx, true)
+	if err != nil {
+		return err
+	}
+	cert, err := cfg.GetCertificate(nil)
+	if err != nil {
+		return errors.Wrap(err, errGetCertificate)
+	}
+	if err := writeIfMissing(s, cert); err != nil {
+		return errors.Wrap(err, errWriteCertFiles)
+	}
+	s.TLSOpts = append(s.TLSOpts, WebhookServerTLSOpt(cfg))
+	return nil
+}
+
+// writeIfMissing writes the supplied certificate to the files the supplied
+// webhook server loads its certificate from, unless they already exist.
+func writeIfMissing(s *webhook.Server, cert *tls.Certificate) error {
+	dir := s.CertDir
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "k8s-webhook-server", "serving-certs")
+	}
+	certPath := filepath.Join(dir, TLSCertFileName)
+	if s.CertName != "" {
+		certPath = filepath.Join(dir, s.CertName)
+	}
+	keyPath := filepath.Join(dir, TLSKeyFileName)
+	if s.KeyName != "" {
+		keyPath = filepath.Join(dir, s.KeyName)
+	}
+	_, cerr := os.Stat(certPath)
+	_, kerr := os.Stat(keyPath)
+	if cerr == nil && kerr == nil {
+		return nil
+	}
+
+	key, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	if err != nil {
+		return errors.Wrap(err, errMarshalKey)
+	}
+	certPEM := []byte{}
+	for _, der := range cert.Certificate {
+		certPEM = append(certPEM, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: key}), 0o600); err != nil {
+		return err
+	}
+	return os.WriteFile(certPath, certPEM, 0o600)
+}
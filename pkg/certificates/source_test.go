@@ -0,0 +1,151 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificates
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestConfigureWebhookServer(t *testing.T) {
+	errBoom := errors.New("boom")
+	ca, _, server1, _ := keyPairs(t)
+
+	fromSecret := FromSecret(&test.MockClient{
+		MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			obj.(*corev1.Secret).Data = map[string][]byte{
+				CACertFileName:  ca.Cert,
+				TLSCertFileName: server1.Cert,
+				TLSKeyFileName:  server1.Key,
+			}
+			return nil
+		},
+	}, types.NamespacedName{Name: "cool"})
+
+	type args struct {
+		src      Source
+		existing []byte
+	}
+	type want struct {
+		err    error
+		serial string
+		files  bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"SourceError": {
+			reason: "We should return any error encountered getting a TLS config from the Source.",
+			args: args{
+				src: SourceFn(func(_ context.Context, _ bool) (*tls.Config, error) { return nil, errBoom }),
+			},
+			want: want{
+				err: errBoom,
+			},
+		},
+		"WritePlaceholder": {
+			reason: "We should serve the Source's certificate, and write it to the certificate directory if it does not exist.",
+			args: args{
+				src: fromSecret,
+			},
+			want: want{
+				serial: serial(t, server1),
+				files:  true,
+			},
+		},
+		"ExistingFiles": {
+			reason: "We should serve the Source's certificate, and leave any existing certificate files alone.",
+			args: args{
+				src:      fromSecret,
+				existing: []byte("existing"),
+			},
+			want: want{
+				serial: serial(t, server1),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			s := &webhook.Server{CertDir: t.TempDir()}
+			for _, f := range []string{TLSCertFileName, TLSKeyFileName} {
+				if tc.args.existing == nil {
+					continue
+				}
+				if err := os.WriteFile(filepath.Join(s.CertDir, f), tc.args.existing, 0o600); err != nil {
+					t.Fatalf("os.WriteFile(...): %v", err)
+				}
+			}
+
+			err := ConfigureWebhookServer(ctx, s, tc.args.src)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nConfigureWebhookServer(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if tc.want.err != nil {
+				return
+			}
+
+			cfg := &tls.Config{MinVersion: tls.VersionTLS13}
+			for _, fn := range s.TLSOpts {
+				fn(cfg)
+			}
+			if cfg.MinVersion != tls.VersionTLS13 || cfg.ClientAuth != tls.NoClientCert {
+				t.Errorf("\n%s\nConfigureWebhookServer(...): want base TLS config to be preserved", tc.reason)
+			}
+			cert, err := cfg.GetCertificate(nil)
+			if err != nil {
+				t.Fatalf("\n%s\ncfg.GetCertificate(...): %v", tc.reason, err)
+			}
+			x, err := x509.ParseCertificate(cert.Certificate[0])
+			if err != nil {
+				t.Fatalf("\n%s\nx509.ParseCertificate(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.serial, x.SerialNumber.String()); diff != "" {
+				t.Errorf("\n%s\ncfg.GetCertificate(...): -want serial, +got serial:\n%s", tc.reason, diff)
+			}
+
+			c, err := tls.LoadX509KeyPair(filepath.Join(s.CertDir, TLSCertFileName), filepath.Join(s.CertDir, TLSKeyFileName))
+			if got := err == nil; got != tc.want.files {
+				t.Errorf("\n%s\ntls.LoadX509KeyPair(...): want valid placeholder files %t, got error %v", tc.reason, tc.want.files, err)
+			}
+			if err == nil {
+				if diff := cmp.Diff(cert.Certificate, c.Certificate); diff != "" {
+					t.Errorf("\n%s\ntls.LoadX509KeyPair(...): -want, +got:\n%s", tc.reason, diff)
+				}
+			}
+		})
+	}
+}
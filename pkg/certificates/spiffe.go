@@ -0,0 +1,177 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificates
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/url"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	errGetSVID           = "cannot get X.509 SVID from identity source"
+	errGetBundle         = "cannot get X.509 trust bundle from identity source"
+	errVerifyPeer        = "cannot verify peer certificate"
+	errNoSPIFFEID        = "peer certificate has no SPIFFE ID"
+	errMultipleSPIFFEIDs = "peer certificate has more than one URI SAN"
+
+	errFmtUnauthorizedID          = "SPIFFE ID %q is not authorized"
+	errFmtUnauthorizedTrustDomain = "SPIFFE ID %q is not a member of trust domain %q"
+)
+
+// SPIFFEScheme is the URI scheme of a SPIFFE ID.
+const SPIFFEScheme = "spiffe"
+
+// An IdentitySource supplies X.509 identities, known as X.509 SVIDs, and the
+// trust bundle used to verify them. It is typically backed by a SPIFFE
+// Workload API socket, for example by adapting the X509Source of the go-spiffe
+// library, which rotates identities as they are renewed.
+type IdentitySource interface {
+	// GetX509SVID returns the current X.509 identity of this workload.
+	GetX509SVID() (*tls.Certificate, error)
+
+	// GetX509Bundle returns the pool of authorities used to verify the X.509
+	// identities of peers.
+	GetX509Bundle() (*x509.CertPool, error)
+}
+
+// An Authorizer determines whether a peer with the supplied SPIFFE ID may
+// connect.
+type Authorizer func(id *url.URL) error
+
+// AuthorizeAny authorizes any peer with a valid SPIFFE ID.
+func AuthorizeAny() Authorizer {
+	return func(_ *url.URL) error {
+		return nil
+	}
+}
+
+// AuthorizeID authorizes peers with one of the supplied SPIFFE IDs, for
+// example spiffe://example.org/ns/crossplane-system/sa/ess-plugin-vault.
+func AuthorizeID(ids ...string) Authorizer {
+	allowed := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		allowed[id] = true
+	}
+	return func(id *url.URL) error {
+		if !allowed[id.String()] {
+			return errors.Errorf(errFmtUnauthorizedID, id)
+		}
+		return nil
+	}
+}
+
+// AuthorizeMemberOf authorizes peers with a SPIFFE ID in the supplied trust
+// domain, for example example.org.
+func AuthorizeMemberOf(trustDomain string) Authorizer {
+	return func(id *url.URL) error {
+		if id.Host != trustDomain {
+			return errors.Errorf(errFmtUnauthorizedTrustDomain, id, trustDomain)
+		}
+		return nil
+	}
+}
+
+// SPIFFEID returns the SPIFFE ID of the supplied certificate. A certificate
+// with a SPIFFE ID must have exactly one URI SAN.
+func SPIFFEID(c *x509.Certificate) (*url.URL, error) {
+	if len(c.URIs) > 1 {
+		return nil, errors.New(errMultipleSPIFFEIDs)
+	}
+	if len(c.URIs) == 0 || c.URIs[0].Scheme != SPIFFEScheme || c.URIs[0].Host == "" {
+		return nil, errors.New(errNoSPIFFEID)
+	}
+	return c.URIs[0], nil
+}
+
+// SPIFFEConfig returns a mutual TLS config that authenticates using the X.509
+// identities of the supplied source, rather than mounted certificate files.
+// Peers are authenticated by their SPIFFE ID, which must be accepted by the
+// supplied Authorizer. Server names are not verified. The returned TLS config
+// may be used anywhere the TLS config returned by LoadMTLSConfig is, for
+// example by External Secret Store plugin clients and servers. Use FromSPIFFE
+// to load it using controller.NewESSOptions.
+func SPIFFEConfig(src IdentitySource, isServer bool, authorize Authorizer) *tls.Config {
+	verify := func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return errors.New(errNoPeerCertificates)
+		}
+		pool, err := src.GetX509Bundle()
+		if err != nil {
+			return errors.Wrap(err, errGetBundle)
+		}
+		opts := x509.VerifyOptions{
+			Roots:         pool,
+			Intermediates: x509.NewCertPool(),
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		}
+		for _, c := range cs.PeerCertificates[1:] {
+			opts.Intermediates.AddCert(c)
+		}
+		if _, err := cs.PeerCertificates[0].Verify(opts); err != nil {
+			return errors.Wrap(err, errVerifyPeer)
+		}
+		id, err := SPIFFEID(cs.PeerCertificates[0])
+		if err != nil {
+			return err
+		}
+		return authorize(id)
+	}
+
+	svid := func() (*tls.Certificate, error) {
+		c, err := src.GetX509SVID()
+		return c, errors.Wrap(err, errGetSVID)
+	}
+
+	if isServer {
+		return &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			// SPIFFE identities are verified against the latest trust bundle
+			// in VerifyConnection, so we can't use a static ClientCAs pool.
+			ClientAuth: tls.RequireAnyClientCert,
+			GetCertificate: func(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+				return svid()
+			},
+			VerifyConnection: verify,
+		}
+	}
+
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetClientCertificate: func(_ *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return svid()
+		},
+		// SPIFFE authenticates peers by SPIFFE ID rather than by DNS name, so
+		// we skip the default verification and verify the server's identity
+		// against the latest trust bundle ourselves.
+		InsecureSkipVerify: true, //nolint:gosec // We verify the peer in VerifyConnection.
+		VerifyConnection:   verify,
+	}
+}
+
+// FromSPIFFE returns a Source of TLS material from the supplied X.509 identity
+// source, such as a SPIFFE Workload API socket. Peers are authenticated by
+// their SPIFFE ID, which must be accepted by the supplied Authorizer. See
+// SPIFFEConfig.
+func FromSPIFFE(src IdentitySource, authorize Authorizer) Source {
+	return SourceFn(func(_ context.Context, isServer bool) (*tls.Config, error) {
+		return SPIFFEConfig(src, isServer, authorize), nil
+	})
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificates
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+type staticIdentitySource struct {
+	svid *tls.Certificate
+	pool *x509.CertPool
+}
+
+func (s *staticIdentitySource) GetX509SVID() (*tls.Certificate, error) { return s.svid, nil }
+func (s *staticIdentitySource) GetX509Bundle() (*x509.CertPool, error) { return s.pool, nil }
+
+// newIdentitySource returns an IdentitySource that supplies an X.509 SVID
+// with the supplied SPIFFE ID, signed by the supplied CA, and trusts the
+// supplied CA.
+func newIdentitySource(t *testing.T, ca *KeyPair, id *url.URL) *staticIdentitySource {
+	t.Helper()
+	kp, err := newKeyPair(&x509.Certificate{
+		Subject:     pkix.Name{CommonName: "cool-workload"},
+		URIs:        []*url.URL{id},
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}, ca, time.Hour)
+	if err != nil {
+		t.Fatalf("newKeyPair(...): %v", err)
+	}
+	c, err := tls.X509KeyPair(kp.Cert, kp.Key)
+	if err != nil {
+		t.Fatalf("tls.X509KeyPair(...): %v", err)
+	}
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(ca.Cert)
+	return &staticIdentitySource{svid: &c, pool: pool}
+}
+
+func TestSPIFFEConfig(t *testing.T) {
+	ca, err := NewCA("spiffe-ca", time.Hour)
+	if err != nil {
+		t.Fatalf("NewCA(...): %v", err)
+	}
+	other, err := NewCA("other-ca", time.Hour)
+	if err != nil {
+		t.Fatalf("NewCA(...): %v", err)
+	}
+
+	plugin := &url.URL{Scheme: SPIFFEScheme, Host: "example.org", Path: "/ns/crossplane-system/sa/ess-plugin"}
+	provider := &url.URL{Scheme: SPIFFEScheme, Host: "example.org", Path: "/ns/crossplane-system/sa/provider"}
+	foreign := &url.URL{Scheme: SPIFFEScheme, Host: "example.net", Path: "/provider"}
+
+	type args struct {
+		server          IdentitySource
+		client          IdentitySource
+		serverAuthorize Authorizer
+		clientAuthorize Authorizer
+	}
+
+	cases := map[string]struct {
+		reason  string
+		args    args
+		wantErr bool
+	}{
+		"Authorized": {
+			reason: "Peers with authorized SPIFFE IDs from the trust bundle should connect.",
+			args: args{
+				server:          newIdentitySource(t, ca, plugin),
+				client:          newIdentitySource(t, ca, provider),
+				serverAuthorize: AuthorizeMemberOf("example.org"),
+				clientAuthorize: AuthorizeID(plugin.String()),
+			},
+			wantErr: false,
+		},
+		"UnauthorizedServer": {
+			reason: "Clients should reject servers with unauthorized SPIFFE IDs.",
+			args: args{
+				server:          newIdentitySource(t, ca, provider),
+				client:          newIdentitySource(t, ca, provider),
+				serverAuthorize: AuthorizeAny(),
+				clientAuthorize: AuthorizeID(plugin.String()),
+			},
+			wantErr: true,
+		},
+		"UnauthorizedClient": {
+			reason: "Servers should reject clients outside the authorized trust domain.",
+			args: args{
+				server:          newIdentitySource(t, ca, plugin),
+				client:          newIdentitySource(t, ca, foreign),
+				serverAuthorize: AuthorizeMemberOf("example.org"),
+				clientAuthorize: AuthorizeAny(),
+			},
+			wantErr: true,
+		},
+		"UntrustedClient": {
+			reason: "Servers should reject clients whose identity is not signed by the trust bundle.",
+			args: args{
+				server:          newIdentitySource(t, ca, plugin),
+				client:          newIdentitySource(t, other, provider),
+				serverAuthorize: AuthorizeAny(),
+				clientAuthorize: AuthorizeAny(),
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			scfg, err := FromSPIFFE(tc.args.server, tc.args.serverAuthorize).TLSConfig(ctx, true)
+			if err != nil {
+				t.Fatalf("\n%s\nFromSPIFFE(...).TLSConfig(...): %v", tc.reason, err)
+			}
+			ccfg, err := FromSPIFFE(tc.args.client, tc.args.clientAuthorize).TLSConfig(ctx, false)
+			if err != nil {
+				t.Fatalf("\n%s\nFromSPIFFE(...).TLSConfig(...): %v", tc.reason, err)
+			}
+			_, err = handshake(t, scfg, ccfg)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("\n%s\nhandshake(...): want error %t, got %v", tc.reason, tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSPIFFEID(t *testing.T) {
+	id := &url.URL{Scheme: SPIFFEScheme, Host: "example.org", Path: "/cool"}
+
+	type want struct {
+		id  *url.URL
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		uris   []*url.URL
+		want   want
+	}{
+		"Valid": {
+			reason: "A certificate with a single SPIFFE URI SAN should return its ID.",
+			uris:   []*url.URL{id},
+			want: want{
+				id: id,
+			},
+		},
+		"NotSPIFFE": {
+			reason: "A URI SAN that is not a SPIFFE ID should return an error.",
+			uris:   []*url.URL{{Scheme: "https", Host: "example.org", Path: "/cool"}},
+			want: want{
+				err: errors.New(errNoSPIFFEID),
+			},
+		},
+		"Multiple": {
+			reason: "A certificate with more than one URI SAN should return an error.",
+			uris:   []*url.URL{id, id},
+			want: want{
+				err: errors.New(errMultipleSPIFFEIDs),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := SPIFFEID(&x509.Certificate{URIs: tc.uris})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nSPIFFEID(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.id, got); diff != "" {
+				t.Errorf("\n%s\nSPIFFEID(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
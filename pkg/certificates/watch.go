@@ -0,0 +1,162 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificates
+
+import (
+	"context"
+	"crypto/tls"
+	"os"
+	"path/filepath"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+const (
+	errCreateWatcher = "cannot create file watcher"
+	errWatchDir      = "cannot watch directory"
+)
+
+// A WatchOption configures Watch.
+type WatchOption func(w *fileWatcher)
+
+// WithWatchLogger configures the logger used to report failures to reload
+// TLS material.
+func WithWatchLogger(l logging.Logger) WatchOption {
+	return func(w *fileWatcher) {
+		w.log = l
+	}
+}
+
+type fileWatcher struct {
+	caPath   string
+	certPath string
+	keyPath  string
+	log      logging.Logger
+
+	dynamic
+}
+
+func (w *fileWatcher) load() error {
+	ca, err := os.ReadFile(w.caPath)
+	if err != nil {
+		return errors.Wrap(err, errLoadCA)
+	}
+	cert, err := os.ReadFile(w.certPath)
+	if err != nil {
+		return errors.Wrap(err, errLoadCert)
+	}
+	key, err := os.ReadFile(w.keyPath)
+	if err != nil {
+		return errors.Wrap(err, errLoadCert)
+	}
+	return w.set(ca, cert, key)
+}
+
+// Watch is a variant of LoadMTLSConfig that returns a TLS config that always
+// serves the latest TLS material from the supplied files. The files are
+// reloaded whenever they change, for example when the Secret they are mounted
+// from is updated, until the supplied context is done. Watch returns an error
+// if the files cannot be loaded initially. Subsequent failures to reload are
+// logged, and the previously loaded material continues to be served.
+//
+// The returned TLS config may be used anywhere the TLS config returned by
+// LoadMTLSConfig is, for example by External Secret Store plugin clients and
+// servers. Use FromFiles to load it using controller.NewESSOptions or
+// ConfigureWebhookServer.
+func Watch(ctx context.Context, caPath, certPath, keyPath string, isServer bool, o ...WatchOption) (*tls.Config, error) {
+	w := &fileWatcher{
+		caPath:   filepath.Clean(caPath),
+		certPath: filepath.Clean(certPath),
+		keyPath:  filepath.Clean(keyPath),
+		log:      logging.NewNopLogger(),
+	}
+	for _, fn := range o {
+		fn(w)
+	}
+
+	if err := w.load(); err != nil {
+		return nil, err
+	}
+
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, errors.Wrap(err, errCreateWatcher)
+	}
+
+	// Kubernetes updates mounted Secrets by atomically swapping a symlink, so
+	// we watch the directories containing the files rather than the files.
+	dirs := map[string]bool{}
+	for _, p := range []string{w.caPath, w.certPath, w.keyPath} {
+		dirs[filepath.Dir(p)] = true
+	}
+	for d := range dirs {
+		if err := fsw.Add(d); err != nil {
+			_ = fsw.Close()
+			return nil, errors.Wrap(err, errWatchDir)
+		}
+	}
+
+	go func() {
+		defer fsw.Close() //nolint:errcheck // Nothing to do if we can't close the watcher.
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-fsw.Events:
+				if !ok {
+					return
+				}
+				// A single update may produce several events, and may be
+				// observed half way through. Reloading is idempotent and
+				// invalid material is never served, so we just try again.
+				if err := w.load(); err != nil {
+					w.log.Debug("Cannot reload TLS material", "error", err)
+					continue
+				}
+				w.log.Debug("Reloaded TLS material", "cert", w.certPath)
+			case err, ok := <-fsw.Errors:
+				if !ok {
+					return
+				}
+				w.log.Info("Error watching TLS material", "error", err)
+			}
+		}
+	}()
+
+	return w.config(isServer), nil
+}
+
+// WebhookServerTLSOpt returns an option for a controller-runtime webhook
+// server's TLSOpts that makes it serve the latest certificate of the supplied
+// server TLS config, for example one returned by Watch. The webhook server's
+// other TLS settings, including client authentication, are left unchanged.
+func WebhookServerTLSOpt(cfg *tls.Config) func(*tls.Config) {
+	return func(c *tls.Config) {
+		c.GetCertificate = cfg.GetCertificate
+	}
+}
+
+// FromFiles returns a Source of TLS material from the supplied files, which
+// are reloaded whenever they change. See Watch.
+func FromFiles(caPath, certPath, keyPath string, o ...WatchOption) Source {
+	return SourceFn(func(ctx context.Context, isServer bool) (*tls.Config, error) {
+		return Watch(ctx, caPath, certPath, keyPath, isServer, o...)
+	})
+}
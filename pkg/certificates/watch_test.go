@@ -0,0 +1,189 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificates
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// writeKeyPair writes the supplied CA and key pair to the supplied directory.
+func writeKeyPair(t *testing.T, dir string, ca []byte, kp *KeyPair) {
+	t.Helper()
+	for name, data := range map[string][]byte{
+		CACertFileName:  ca,
+		TLSCertFileName: kp.Cert,
+		TLSKeyFileName:  kp.Key,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+			t.Fatalf("os.WriteFile(...): %v", err)
+		}
+	}
+}
+
+// watch returns a TLS config that watches the TLS material in the supplied
+// directory.
+func watch(ctx context.Context, dir string, isServer bool) (*tls.Config, error) {
+	return Watch(ctx, filepath.Join(dir, CACertFileName), filepath.Join(dir, TLSCertFileName), filepath.Join(dir, TLSKeyFileName), isServer)
+}
+
+func TestWatch(t *testing.T) {
+	ca, client, server1, server2 := keyPairs(t)
+
+	type args struct {
+		initial *KeyPair
+		update  *KeyPair
+	}
+	type want struct {
+		err    bool
+		serial string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"MissingFiles": {
+			reason: "We should return an error if the files cannot be loaded initially.",
+			args:   args{},
+			want: want{
+				err: true,
+			},
+		},
+		"Loaded": {
+			reason: "We should serve the loaded certificate.",
+			args: args{
+				initial: server1,
+			},
+			want: want{
+				serial: serial(t, server1),
+			},
+		},
+		"Renewed": {
+			reason: "We should serve a renewed certificate once it is written to disk.",
+			args: args{
+				initial: server1,
+				update:  server2,
+			},
+			want: want{
+				serial: serial(t, server2),
+			},
+		},
+		"Invalid": {
+			reason: "We should keep serving the loaded certificate if invalid material is written to disk.",
+			args: args{
+				initial: server1,
+				update:  &KeyPair{Cert: []byte("nope"), Key: []byte("nope")},
+			},
+			want: want{
+				serial: serial(t, server1),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			sdir, cdir := t.TempDir(), t.TempDir()
+			writeKeyPair(t, cdir, ca.Cert, client)
+			if tc.args.initial != nil {
+				writeKeyPair(t, sdir, ca.Cert, tc.args.initial)
+			}
+
+			scfg, err := watch(ctx, sdir, true)
+			if (err != nil) != tc.want.err {
+				t.Fatalf("\n%s\nWatch(...): want error %t, got %v", tc.reason, tc.want.err, err)
+			}
+			if err != nil {
+				return
+			}
+			ccfg, err := watch(ctx, cdir, false)
+			if err != nil {
+				t.Fatalf("\n%s\nWatch(...): %v", tc.reason, err)
+			}
+			ccfg.ServerName = "cool.svc"
+
+			if tc.args.update != nil {
+				writeKeyPair(t, sdir, ca.Cert, tc.args.update)
+				// Give the watcher a chance to observe the update.
+				time.Sleep(100 * time.Millisecond)
+			}
+
+			// The watcher reloads asynchronously, so we wait briefly for the
+			// desired certificate to be served.
+			var got string
+			deadline := time.Now().Add(5 * time.Second)
+			for time.Now().Before(deadline) {
+				got, err = handshake(t, scfg, ccfg)
+				if err == nil && got == tc.want.serial {
+					break
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+			if err != nil {
+				t.Fatalf("\n%s\nhandshake(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.serial, got); diff != "" {
+				t.Errorf("\n%s\nhandshake(...): -want serial, +got serial:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestWebhookServerTLSOpt(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ca, _, server1, _ := keyPairs(t)
+	dir := t.TempDir()
+	writeKeyPair(t, dir, ca.Cert, server1)
+	watched, err := watch(ctx, dir, true)
+	if err != nil {
+		t.Fatalf("Watch(...): %v", err)
+	}
+
+	// The API server does not present a client certificate by default, so the
+	// webhook server must not require one.
+	scfg := &tls.Config{MinVersion: tls.VersionTLS12, NextProtos: []string{"h2"}}
+	WebhookServerTLSOpt(watched)(scfg)
+
+	cac, err := ca.Certificate()
+	if err != nil {
+		t.Fatalf("ca.Certificate(): %v", err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(cac)
+	ccfg := &tls.Config{MinVersion: tls.VersionTLS12, RootCAs: pool, ServerName: "cool.svc"}
+
+	got, err := handshake(t, scfg, ccfg)
+	if err != nil {
+		t.Fatalf("handshake(...): %v", err)
+	}
+	if diff := cmp.Diff(serial(t, server1), got); diff != "" {
+		t.Errorf("handshake(...): -want serial, +got serial:\n%s", diff)
+	}
+}
@@ -0,0 +1,259 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cloudevent publishes CloudEvents describing the lifecycle of managed
+// resources, so that systems outside Kubernetes can follow them without
+// watching the API server.
+package cloudevent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/uuid"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const (
+	errMarshal       = "cannot marshal CloudEvent"
+	errNewRequest    = "cannot create CloudEvent request"
+	errSend          = "cannot send CloudEvent"
+	errFmtSendStatus = "CloudEvent sink returned status %d: %s"
+)
+
+const (
+	// SpecVersion is the version of the CloudEvents specification events
+	// conform to.
+	SpecVersion = "1.0"
+
+	// ContentType is the media type of a CloudEvent in structured mode.
+	ContentType = "application/cloudevents+json"
+
+	// DefaultSendTimeout is the default time allowed to send an event.
+	DefaultSendTimeout = 5 * time.Second
+)
+
+// A Type of managed resource lifecycle event.
+type Type string
+
+// Managed resource lifecycle event types.
+const (
+	TypeCreated       Type = "io.crossplane.managed.created"
+	TypeBecameReady   Type = "io.crossplane.managed.ready"
+	TypeDriftDetected Type = "io.crossplane.managed.drift"
+	TypeDeleted       Type = "io.crossplane.managed.deleted"
+)
+
+// Data describes the managed resource an event concerns.
+type Data struct {
+	APIVersion   string            `json:"apiVersion"`
+	Kind         string            `json:"kind"`
+	Name         string            `json:"name"`
+	Namespace    string            `json:"namespace,omitempty"`
+	UID          string            `json:"uid"`
+	ExternalName string            `json:"externalName,omitempty"`
+	Labels       map[string]string `json:"labels,omitempty"`
+}
+
+// An Event is a CloudEvent in structured mode.
+type Event struct {
+	SpecVersion     string    `json:"specversion"`
+	ID              string    `json:"id"`
+	Source          string    `json:"source"`
+	Type            Type      `json:"type"`
+	Subject         string    `json:"subject,omitempty"`
+	Time            time.Time `json:"time"`
+	DataContentType string    `json:"datacontenttype"`
+	Data            Data      `json:"data"`
+}
+
+// A Sink sends CloudEvents somewhere, for example to an HTTP endpoint or a
+// Kafka topic.
+type Sink interface {
+	Send(ctx context.Context, e Event) error
+}
+
+// A SinkFn is a function that satisfies the Sink interface.
+type SinkFn func(ctx context.Context, e Event) error
+
+// Send the supplied event.
+func (fn SinkFn) Send(ctx context.Context, e Event) error {
+	return fn(ctx, e)
+}
+
+// An HTTPSinkOption configures an HTTPSink.
+type HTTPSinkOption func(s *HTTPSink)
+
+// WithHTTPClient configures the HTTP client used to send events.
+func WithHTTPClient(c *http.Client) HTTPSinkOption {
+	return func(s *HTTPSink) {
+		s.client = c
+	}
+}
+
+// WithHeaders configures headers sent with each event, for example to
+// authenticate to the sink.
+func WithHeaders(h map[string]string) HTTPSinkOption {
+	return func(s *HTTPSink) {
+		s.headers = h
+	}
+}
+
+// An HTTPSink POSTs CloudEvents in structured mode to an HTTP endpoint. Kafka
+// brokers can receive events via an HTTP bridge such as Knative's KafkaSink.
+type HTTPSink struct {
+	url     string
+	client  *http.Client
+	headers map[string]string
+}
+
+// NewHTTPSink returns a Sink that POSTs CloudEvents to the supplied URL.
+func NewHTTPSink(url string, o ...HTTPSinkOption) *HTTPSink {
+	s := &HTTPSink{url: url, client: http.DefaultClient}
+	for _, fn := range o {
+		fn(s)
+	}
+	return s
+}
+
+// Send the supplied event.
+func (s *HTTPSink) Send(ctx context.Context, e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return errors.Wrap(err, errMarshal)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, errNewRequest)
+	}
+	req.Header.Set("Content-Type", ContentType)
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+
+	rsp, err := s.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, errSend)
+	}
+	defer rsp.Body.Close() //nolint:errcheck // Nothing useful can be done with this error.
+	if rsp.StatusCode < 200 || rsp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(rsp.Body, 1024))
+		return errors.Errorf(errFmtSendStatus, rsp.StatusCode, string(msg))
+	}
+	return nil
+}
+
+// An Emitter emits lifecycle events for managed resources.
+type Emitter interface {
+	// Emit an event of the supplied type for the supplied managed resource.
+	Emit(ctx context.Context, t Type, gvk schema.GroupVersionKind, mg resource.Managed)
+}
+
+// A NopEmitter does nothing.
+type NopEmitter struct{}
+
+// NewNopEmitter returns an Emitter that does nothing.
+func NewNopEmitter() Emitter {
+	return NopEmitter{}
+}
+
+// Emit does nothing.
+func (NopEmitter) Emit(_ context.Context, _ Type, _ schema.GroupVersionKind, _ resource.Managed) {}
+
+// A SinkEmitterOption configures a SinkEmitter.
+type SinkEmitterOption func(e *SinkEmitter)
+
+// WithSendTimeout configures the time allowed to send an event.
+func WithSendTimeout(d time.Duration) SinkEmitterOption {
+	return func(e *SinkEmitter) {
+		e.timeout = d
+	}
+}
+
+// WithLogger configures the logger used to report events that could not be
+// sent.
+func WithLogger(l logging.Logger) SinkEmitterOption {
+	return func(e *SinkEmitter) {
+		e.log = l
+	}
+}
+
+// A SinkEmitter emits lifecycle events to a Sink. Events are best effort;
+// events that can't be sent are logged and dropped so that they don't block
+// reconciliation.
+type SinkEmitter struct {
+	sink    Sink
+	source  string
+	timeout time.Duration
+	log     logging.Logger
+
+	now   func() time.Time
+	newID func() string
+}
+
+// NewSinkEmitter returns an Emitter that sends events to the supplied Sink.
+// The supplied source identifies the emitter, e.g. the provider's name.
+func NewSinkEmitter(s Sink, source string, o ...SinkEmitterOption) *SinkEmitter {
+	e := &SinkEmitter{
+		sink:    s,
+		source:  source,
+		timeout: DefaultSendTimeout,
+		log:     logging.NewNopLogger(),
+		now:     time.Now,
+		newID:   func() string { return string(uuid.NewUUID()) },
+	}
+	for _, fn := range o {
+		fn(e)
+	}
+	return e
+}
+
+// Emit an event of the supplied type for the supplied managed resource.
+func (e *SinkEmitter) Emit(ctx context.Context, t Type, gvk schema.GroupVersionKind, mg resource.Managed) {
+	ev := Event{
+		SpecVersion:     SpecVersion,
+		ID:              e.newID(),
+		Source:          e.source,
+		Type:            t,
+		Subject:         mg.GetName(),
+		Time:            e.now().UTC(),
+		DataContentType: "application/json",
+		Data: Data{
+			APIVersion:   gvk.GroupVersion().String(),
+			Kind:         gvk.Kind,
+			Name:         mg.GetName(),
+			Namespace:    mg.GetNamespace(),
+			UID:          string(mg.GetUID()),
+			ExternalName: meta.GetExternalName(mg),
+			Labels:       mg.GetLabels(),
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+	if err := e.sink.Send(ctx, ev); err != nil {
+		e.log.Info("Cannot send lifecycle event", "error", err, "type", t, "name", mg.GetName())
+	}
+}
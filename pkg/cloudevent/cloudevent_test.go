@@ -0,0 +1,162 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudevent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestHTTPSink(t *testing.T) {
+	ev := Event{SpecVersion: SpecVersion, ID: "cool-id", Source: "provider-cool", Type: TypeCreated, Time: time.Unix(0, 0).UTC()}
+
+	type want struct {
+		ev  *Event
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		status int
+		want   want
+	}{
+		"Success": {
+			reason: "The event should be POSTed to the sink in structured mode.",
+			status: http.StatusAccepted,
+			want: want{
+				ev: &ev,
+			},
+		},
+		"ErrorStatus": {
+			reason: "An error should be returned if the sink does not accept the event.",
+			status: http.StatusBadRequest,
+			want: want{
+				err: errors.Errorf(errFmtSendStatus, http.StatusBadRequest, "nope"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got *Event
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Content-Type") != ContentType || r.Header.Get("Authorization") != "Bearer cool" {
+					w.WriteHeader(http.StatusUnsupportedMediaType)
+					return
+				}
+				if tc.status != http.StatusAccepted {
+					w.WriteHeader(tc.status)
+					_, _ = w.Write([]byte("nope"))
+					return
+				}
+				got = &Event{}
+				if err := json.NewDecoder(r.Body).Decode(got); err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				w.WriteHeader(tc.status)
+			}))
+			defer srv.Close()
+
+			s := NewHTTPSink(srv.URL, WithHeaders(map[string]string{"Authorization": "Bearer cool"}))
+			err := s.Send(context.Background(), ev)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.Send(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ev, got); diff != "" {
+				t.Errorf("\n%s\ns.Send(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSinkEmitterEmit(t *testing.T) {
+	now := time.Unix(100, 0).UTC()
+	gvk := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Cool"}
+
+	type args struct {
+		t  Type
+		mg *fake.Managed
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   Event
+	}{
+		"BecameReady": {
+			reason: "The emitted event should describe the managed resource.",
+			args: args{
+				t: TypeBecameReady,
+				mg: func() *fake.Managed {
+					mg := &fake.Managed{ObjectMeta: metav1.ObjectMeta{
+						Name:   "cool",
+						UID:    "cool-uid",
+						Labels: map[string]string{"team": "cool"},
+					}}
+					meta.SetExternalName(mg, "cool-external")
+					return mg
+				}(),
+			},
+			want: Event{
+				SpecVersion:     SpecVersion,
+				ID:              "cool-id",
+				Source:          "provider-cool",
+				Type:            TypeBecameReady,
+				Subject:         "cool",
+				Time:            now,
+				DataContentType: "application/json",
+				Data: Data{
+					APIVersion:   "example.org/v1",
+					Kind:         "Cool",
+					Name:         "cool",
+					UID:          "cool-uid",
+					ExternalName: "cool-external",
+					Labels:       map[string]string{"team": "cool"},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got Event
+			e := NewSinkEmitter(SinkFn(func(_ context.Context, ev Event) error {
+				got = ev
+				return nil
+			}), "provider-cool")
+			e.now = func() time.Time { return now }
+			e.newID = func() string { return "cool-id" }
+
+			e.Emit(context.Background(), tc.args.t, gvk, tc.args.mg)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ne.Emit(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
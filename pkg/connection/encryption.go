@@ -0,0 +1,242 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connection
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/connection/store"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// Error strings.
+const (
+	errEncrypt       = "cannot encrypt connection details"
+	errDecrypt       = "cannot decrypt connection details"
+	errNewAEAD       = "cannot create AES-GCM cipher"
+	errGenerateKey   = "cannot generate data key"
+	errMalformedData = "malformed envelope encrypted data"
+)
+
+// envelopePrefix marks values encrypted by an EnvelopeCipher.
+const envelopePrefix = "crossplane:envelope:v1:"
+
+// A Cipher encrypts and decrypts connection detail values.
+type Cipher interface {
+	// Encrypt the supplied plaintext.
+	Encrypt(ctx context.Context, plaintext []byte) ([]byte, error)
+
+	// Decrypt the supplied ciphertext. Values that were not encrypted by
+	// the Cipher must be returned unchanged, so that connection details
+	// written before encryption was enabled remain readable.
+	Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error)
+}
+
+// An EncryptingStore encrypts the values of the connection details it writes
+// to the Store it wraps, and decrypts them when they are read. It's intended
+// for stores that don't encrypt secrets themselves, for example Kubernetes
+// Secrets in clusters without encryption at rest.
+type EncryptingStore struct {
+	Store
+	cipher Cipher
+}
+
+// NewEncryptingStore returns a Store that encrypts connection details using
+// the supplied Cipher before writing them to the supplied Store.
+func NewEncryptingStore(s Store, c Cipher) *EncryptingStore {
+	return &EncryptingStore{Store: s, cipher: c}
+}
+
+// ReadKeyValues reads and decrypts the key values of the supplied secret.
+func (s *EncryptingStore) ReadKeyValues(ctx context.Context, n store.ScopedName, sec *store.Secret) error {
+	if err := s.Store.ReadKeyValues(ctx, n, sec); err != nil {
+		return err
+	}
+	kv, err := s.decrypt(ctx, sec.Data)
+	if err != nil {
+		return err
+	}
+	sec.Data = kv
+	return nil
+}
+
+// WriteKeyValues encrypts and writes the key values of the supplied secret.
+// Values that are unchanged keep their current ciphertext, so that writing the
+// same connection details again is a no-op despite encryption being
+// non-deterministic.
+func (s *EncryptingStore) WriteKeyValues(ctx context.Context, sec *store.Secret, wo ...store.WriteOption) (bool, error) {
+	kv := make(store.KeyValues, len(sec.Data))
+	for k, v := range sec.Data {
+		ct, err := s.cipher.Encrypt(ctx, v)
+		if err != nil {
+			return false, errors.Wrap(err, errEncrypt)
+		}
+		kv[k] = ct
+	}
+	encrypted := &store.Secret{ScopedName: sec.ScopedName, Metadata: sec.Metadata, Data: kv}
+
+	keep := func(ctx context.Context, current, desired *store.Secret) error {
+		for k, ct := range current.Data {
+			if _, ok := desired.Data[k]; !ok {
+				continue
+			}
+			pt, err := s.cipher.Decrypt(ctx, ct)
+			if err != nil {
+				// We can't tell whether the value changed, so we
+				// overwrite it.
+				continue
+			}
+			if bytes.Equal(pt, sec.Data[k]) && !bytes.Equal(pt, ct) {
+				desired.Data[k] = ct
+			}
+		}
+		return nil
+	}
+
+	return s.Store.WriteKeyValues(ctx, encrypted, append([]store.WriteOption{keep}, wo...)...)
+}
+
+// WatchSecrets watches the secrets of the wrapped Store, if it is a
+// WatchingStore, and decrypts the secrets of any events.
+func (s *EncryptingStore) WatchSecrets(ctx context.Context, owners []types.UID, fn func(store.SecretEvent)) error {
+	ws, ok := s.Store.(WatchingStore)
+	if !ok {
+		return errors.New(errNotWatchable)
+	}
+	return ws.WatchSecrets(ctx, owners, func(e store.SecretEvent) {
+		if e.Secret != nil {
+			if kv, err := s.decrypt(ctx, e.Secret.Data); err == nil {
+				e.Secret.Data = kv
+			}
+		}
+		fn(e)
+	})
+}
+
+func (s *EncryptingStore) decrypt(ctx context.Context, in store.KeyValues) (store.KeyValues, error) {
+	if in == nil {
+		return nil, nil
+	}
+	out := make(store.KeyValues, len(in))
+	for k, v := range in {
+		pt, err := s.cipher.Decrypt(ctx, v)
+		if err != nil {
+			return nil, errors.Wrap(err, errDecrypt)
+		}
+		out[k] = pt
+	}
+	return out, nil
+}
+
+// An EnvelopeCipher encrypts each value with a random data key, which is in
+// turn encrypted with a key encryption key and stored alongside the value.
+// Both are encrypted using AES-GCM.
+type EnvelopeCipher struct {
+	kek cipher.AEAD
+}
+
+// NewEnvelopeCipher returns a Cipher that uses the supplied key encryption key,
+// which must be 16, 24, or 32 bytes long.
+func NewEnvelopeCipher(kek []byte) (*EnvelopeCipher, error) {
+	a, err := newAEAD(kek)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewAEAD)
+	}
+	return &EnvelopeCipher{kek: a}, nil
+}
+
+// Encrypt the supplied plaintext.
+func (c *EnvelopeCipher) Encrypt(_ context.Context, plaintext []byte) ([]byte, error) {
+	dk := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dk); err != nil {
+		return nil, errors.Wrap(err, errGenerateKey)
+	}
+	a, err := newAEAD(dk)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewAEAD)
+	}
+	edk, err := seal(c.kek, dk)
+	if err != nil {
+		return nil, err
+	}
+	ct, err := seal(a, plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	// The envelope is the encrypted data key followed by the ciphertext.
+	env := append([]byte{byte(len(edk))}, edk...)
+	env = append(env, ct...)
+	return []byte(envelopePrefix + base64.StdEncoding.EncodeToString(env)), nil
+}
+
+// Decrypt the supplied ciphertext. Values that don't look like they were
+// encrypted by an EnvelopeCipher are returned unchanged.
+func (c *EnvelopeCipher) Decrypt(_ context.Context, ciphertext []byte) ([]byte, error) {
+	if !bytes.HasPrefix(ciphertext, []byte(envelopePrefix)) {
+		return ciphertext, nil
+	}
+	env, err := base64.StdEncoding.DecodeString(string(ciphertext[len(envelopePrefix):]))
+	if err != nil {
+		return nil, errors.Wrap(err, errMalformedData)
+	}
+	if len(env) == 0 || len(env) < 1+int(env[0]) {
+		return nil, errors.New(errMalformedData)
+	}
+	dk, err := open(c.kek, env[1:1+int(env[0])])
+	if err != nil {
+		return nil, err
+	}
+	a, err := newAEAD(dk)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewAEAD)
+	}
+	return open(a, env[1+int(env[0]):])
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	b, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(b)
+}
+
+// seal returns the supplied plaintext encrypted by the supplied AEAD, prefixed
+// by the random nonce it was encrypted with.
+func seal(a cipher.AEAD, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, a.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return a.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func open(a cipher.AEAD, ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < a.NonceSize() {
+		return nil, errors.New(errMalformedData)
+	}
+	return a.Open(nil, ciphertext[:a.NonceSize()], ciphertext[a.NonceSize():], nil)
+}
@@ -0,0 +1,251 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connection
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/connection/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/connection/store"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+// prefixCipher is a non-deterministic Cipher that is easy to reason about. It
+// numbers the ciphertexts of each plaintext in the order they're encrypted.
+type prefixCipher struct {
+	n map[string]int
+}
+
+func (c *prefixCipher) Encrypt(_ context.Context, plaintext []byte) ([]byte, error) {
+	if c.n == nil {
+		c.n = map[string]int{}
+	}
+	c.n[string(plaintext)]++
+	return []byte(fmt.Sprintf("enc%d:%s", c.n[string(plaintext)], plaintext)), nil
+}
+
+func (c *prefixCipher) Decrypt(_ context.Context, ciphertext []byte) ([]byte, error) {
+	if !bytes.HasPrefix(ciphertext, []byte("enc")) {
+		return ciphertext, nil
+	}
+	return ciphertext[bytes.IndexByte(ciphertext, ':')+1:], nil
+}
+
+func TestEncryptingStoreReadKeyValues(t *testing.T) {
+	type want struct {
+		data store.KeyValues
+		err  error
+	}
+	cases := map[string]struct {
+		reason string
+		store  Store
+		want   want
+	}{
+		"ReadError": {
+			reason: "We should return any error encountered reading the wrapped store.",
+			store: &fake.SecretStore{
+				ReadKeyValuesFn: func(_ context.Context, _ store.ScopedName, _ *store.Secret) error {
+					return errBoom
+				},
+			},
+			want: want{
+				err: errBoom,
+			},
+		},
+		"Decrypted": {
+			reason: "We should decrypt encrypted values and return unencrypted values unchanged.",
+			store: &fake.SecretStore{
+				ReadKeyValuesFn: func(_ context.Context, _ store.ScopedName, s *store.Secret) error {
+					s.Data = store.KeyValues{"encrypted": []byte("enc1:s3cr3t"), "plain": []byte("value")}
+					return nil
+				},
+			},
+			want: want{
+				data: store.KeyValues{"encrypted": []byte("s3cr3t"), "plain": []byte("value")},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := &store.Secret{}
+			err := NewEncryptingStore(tc.store, &prefixCipher{}).ReadKeyValues(context.Background(), store.ScopedName{Name: "conn"}, s)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.ReadKeyValues(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.data, s.Data); diff != "" {
+				t.Errorf("\n%s\ns.ReadKeyValues(...): -want data, +got data:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestEncryptingStoreWriteKeyValues(t *testing.T) {
+	type args struct {
+		current store.KeyValues
+		desired store.KeyValues
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   store.KeyValues
+	}{
+		"NewSecret": {
+			reason: "We should encrypt the values of a new secret.",
+			args: args{
+				desired: store.KeyValues{"password": []byte("s3cr3t")},
+			},
+			want: store.KeyValues{"password": []byte("enc1:s3cr3t")},
+		},
+		"UnchangedValues": {
+			reason: "We should keep the current ciphertext of values that didn't change.",
+			args: args{
+				current: store.KeyValues{"password": []byte("enc9:s3cr3t"), "user": []byte("enc9:admin")},
+				desired: store.KeyValues{"password": []byte("s3cr3t"), "user": []byte("root")},
+			},
+			want: store.KeyValues{"password": []byte("enc9:s3cr3t"), "user": []byte("enc1:root")},
+		},
+		"UnencryptedValues": {
+			reason: "We should encrypt values that were written before encryption was enabled.",
+			args: args{
+				current: store.KeyValues{"password": []byte("s3cr3t")},
+				desired: store.KeyValues{"password": []byte("s3cr3t")},
+			},
+			want: store.KeyValues{"password": []byte("enc1:s3cr3t")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got store.KeyValues
+			ss := &fake.SecretStore{
+				WriteKeyValuesFn: func(ctx context.Context, s *store.Secret, wo ...store.WriteOption) (bool, error) {
+					if tc.args.current != nil {
+						for _, o := range wo {
+							if err := o(ctx, &store.Secret{Data: tc.args.current}, s); err != nil {
+								return false, err
+							}
+						}
+					}
+					got = s.Data
+					return true, nil
+				},
+			}
+			_, err := NewEncryptingStore(ss, &prefixCipher{}).WriteKeyValues(context.Background(), &store.Secret{Data: tc.args.desired})
+			if diff := cmp.Diff(nil, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.WriteKeyValues(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ns.WriteKeyValues(...): -want written data, +got written data:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestEnvelopeCipher(t *testing.T) {
+	kek := bytes.Repeat([]byte("k"), 32)
+	other, _ := NewEnvelopeCipher(bytes.Repeat([]byte("o"), 32))
+	encrypted, _ := other.Encrypt(context.Background(), []byte("s3cr3t"))
+
+	type want struct {
+		plaintext []byte
+		err       bool
+	}
+	cases := map[string]struct {
+		reason     string
+		ciphertext func(c *EnvelopeCipher) []byte
+		want       want
+	}{
+		"RoundTrip": {
+			reason: "We should decrypt what we encrypted.",
+			ciphertext: func(c *EnvelopeCipher) []byte {
+				ct, _ := c.Encrypt(context.Background(), []byte("s3cr3t"))
+				return ct
+			},
+			want: want{plaintext: []byte("s3cr3t")},
+		},
+		"NotEncrypted": {
+			reason: "We should return values we didn't encrypt unchanged.",
+			ciphertext: func(_ *EnvelopeCipher) []byte {
+				return []byte("s3cr3t")
+			},
+			want: want{plaintext: []byte("s3cr3t")},
+		},
+		"Malformed": {
+			reason: "We should return an error if the envelope is malformed.",
+			ciphertext: func(_ *EnvelopeCipher) []byte {
+				return []byte(envelopePrefix + "AQ==")
+			},
+			want: want{err: true},
+		},
+		"WrongKey": {
+			reason: "We should return an error if the value was encrypted with another key encryption key.",
+			ciphertext: func(_ *EnvelopeCipher) []byte {
+				return encrypted
+			},
+			want: want{err: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c, err := NewEnvelopeCipher(kek)
+			if err != nil {
+				t.Fatalf("NewEnvelopeCipher(...): %s", err)
+			}
+			got, err := c.Decrypt(context.Background(), tc.ciphertext(c))
+			if diff := cmp.Diff(tc.want.err, err != nil); diff != "" {
+				t.Errorf("\n%s\nc.Decrypt(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.plaintext, got); diff != "" {
+				t.Errorf("\n%s\nc.Decrypt(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNewEnvelopeCipher(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		kek    []byte
+		want   error
+	}{
+		"InvalidKeyLength": {
+			reason: "We should return an error if the key encryption key is not a valid AES key.",
+			kek:    []byte("short"),
+			want:   errors.Wrap(errors.New("crypto/aes: invalid key size 5"), errNewAEAD),
+		},
+		"ValidKey": {
+			reason: "We should accept a 32 byte key encryption key.",
+			kek:    bytes.Repeat([]byte("k"), 32),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewEnvelopeCipher(tc.kek)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nNewEnvelopeCipher(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -24,6 +24,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 
 	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/connection/store"
@@ -51,6 +52,18 @@ func (ss *SecretStore) DeleteKeyValues(ctx context.Context, s *store.Secret, do
 	return ss.DeleteKeyValuesFn(ctx, s, do...)
 }
 
+// WatchingSecretStore is a fake SecretStore that can watch secrets.
+type WatchingSecretStore struct {
+	SecretStore
+
+	WatchSecretsFn func(ctx context.Context, owners []types.UID, fn func(store.SecretEvent)) error
+}
+
+// WatchSecrets watches secrets.
+func (ss *WatchingSecretStore) WatchSecrets(ctx context.Context, owners []types.UID, fn func(store.SecretEvent)) error {
+	return ss.WatchSecretsFn(ctx, owners, fn)
+}
+
 // StoreConfig is a mock implementation of the StoreConfig interface.
 type StoreConfig struct { //nolint:musttag // This is a fake implementation to be used in unit tests only.
 	metav1.ObjectMeta
@@ -0,0 +1,105 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connection
+
+import (
+	"sort"
+
+	"github.com/crossplane/crossplane-runtime/pkg/connection/store"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+var _ resource.ConnectionDetailsFilterFn = PropagationFilter{}.FilterSecretData
+
+// A PropagationFilter declares which connection details are propagated from
+// one resource to another, for example from a composite resource to its claim.
+// It may be used to withhold sensitive keys, such as admin credentials, from
+// the consumers of a claim.
+type PropagationFilter struct {
+	// Allow is the set of keys that may be propagated. All keys are allowed if
+	// Allow is empty.
+	Allow []string
+
+	// Deny is the set of keys that must never be propagated. Deny takes
+	// precedence over Allow.
+	Deny []string
+
+	// Renames maps the key of a connection detail to the key it should be
+	// propagated as. Keys are matched before they are renamed, so Allow and
+	// Deny refer to the original key. A renamed key overwrites any propagated
+	// key of the same name. If several propagated keys are renamed to the same
+	// key the first of them, in lexical order, wins.
+	Renames map[string]string
+}
+
+// Filter returns the subset of the supplied connection details that should be
+// propagated, with any renames applied. The supplied details are not modified.
+func (f PropagationFilter) Filter(kv store.KeyValues) store.KeyValues {
+	allow := make(map[string]bool, len(f.Allow))
+	for _, k := range f.Allow {
+		allow[k] = true
+	}
+	deny := make(map[string]bool, len(f.Deny))
+	for _, k := range f.Deny {
+		deny[k] = true
+	}
+
+	out := make(store.KeyValues, len(kv))
+	rename := make([]string, 0, len(f.Renames))
+	for k, v := range kv {
+		if deny[k] || (len(allow) > 0 && !allow[k]) {
+			continue
+		}
+		if _, ok := f.Renames[k]; ok {
+			rename = append(rename, k)
+			continue
+		}
+		out[k] = v
+	}
+
+	// Apply renames last so that they win any collision with a key that was
+	// propagated as is. Renames are applied in lexical order of their source
+	// key so that collisions between renamed keys are resolved
+	// deterministically.
+	sort.Strings(rename)
+	renamed := make(map[string]bool, len(rename))
+	for _, k := range rename {
+		to := f.Renames[k]
+		if renamed[to] {
+			continue
+		}
+		renamed[to] = true
+		out[to] = kv[k]
+	}
+	return out
+}
+
+// FilterSecretData is a variant of Filter that may be used as a
+// resource.ConnectionDetailsFilterFn, for example to filter the connection
+// details propagated by a resource.APIConnectionPropagator.
+func (f PropagationFilter) FilterSecretData(data map[string][]byte) map[string][]byte {
+	return f.Filter(data)
+}
+
+// WithPropagationFilter configures the filter used to determine which
+// connection details are propagated by PropagateConnection. All connection
+// details are propagated by default.
+func WithPropagationFilter(f PropagationFilter) DetailsManagerOption {
+	return func(m *DetailsManager) {
+		m.filter = &f
+	}
+}
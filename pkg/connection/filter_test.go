@@ -0,0 +1,104 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connection
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/connection/store"
+)
+
+func TestPropagationFilter(t *testing.T) {
+	kv := store.KeyValues{
+		"endpoint": []byte("https://example.org"),
+		"username": []byte("admin"),
+		"password": []byte("hunter2"),
+	}
+
+	cases := map[string]struct {
+		reason string
+		f      PropagationFilter
+		kv     store.KeyValues
+		want   store.KeyValues
+	}{
+		"NoFilter": {
+			reason: "An empty filter should propagate all keys.",
+			f:      PropagationFilter{},
+			kv:     kv,
+			want:   kv,
+		},
+		"Allow": {
+			reason: "Only allowed keys should be propagated.",
+			f:      PropagationFilter{Allow: []string{"endpoint"}},
+			kv:     kv,
+			want: store.KeyValues{
+				"endpoint": []byte("https://example.org"),
+			},
+		},
+		"DenyTakesPrecedence": {
+			reason: "Denied keys should not be propagated, even if they are allowed.",
+			f: PropagationFilter{
+				Allow: []string{"endpoint", "password"},
+				Deny:  []string{"password"},
+			},
+			kv: kv,
+			want: store.KeyValues{
+				"endpoint": []byte("https://example.org"),
+			},
+		},
+		"Rename": {
+			reason: "Renamed keys should be propagated under their new name, overwriting any existing key of that name.",
+			f: PropagationFilter{
+				Deny: []string{"password"},
+				Renames: map[string]string{
+					"endpoint": "url",
+					"username": "endpoint",
+				},
+			},
+			kv: kv,
+			want: store.KeyValues{
+				"url":      []byte("https://example.org"),
+				"endpoint": []byte("admin"),
+			},
+		},
+		"RenameCollision": {
+			reason: "When several keys are renamed to the same key the first of them in lexical order should win.",
+			f: PropagationFilter{
+				Renames: map[string]string{
+					"username": "credential",
+					"password": "credential",
+				},
+			},
+			kv: kv,
+			want: store.KeyValues{
+				"endpoint":   []byte("https://example.org"),
+				"credential": []byte("hunter2"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.f.Filter(tc.kv)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nf.Filter(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
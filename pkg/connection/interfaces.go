@@ -19,6 +19,8 @@ package connection
 import (
 	"context"
 
+	"k8s.io/apimachinery/pkg/types"
+
 	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/connection/store"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
@@ -37,3 +39,14 @@ type Store interface {
 	WriteKeyValues(ctx context.Context, s *store.Secret, wo ...store.WriteOption) (changed bool, err error)
 	DeleteKeyValues(ctx context.Context, s *store.Secret, do ...store.DeleteOption) error
 }
+
+// A WatchingStore is a Store that can watch for changes made to the secrets
+// it stores outside Crossplane, for example credentials rotated by Vault.
+type WatchingStore interface {
+	Store
+
+	// WatchSecrets calls the supplied function each time a secret owned by
+	// one of the supplied UIDs is changed or deleted. It blocks until the
+	// supplied context is done or the watch ends.
+	WatchSecrets(ctx context.Context, owners []types.UID, fn func(store.SecretEvent)) error
+}
@@ -18,13 +18,20 @@ limitations under the License.
 package connection
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 
 	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/connection/store"
@@ -41,8 +48,22 @@ const (
 	errDeleteFromStore = "cannot delete from secret store"
 	errGetStoreConfig  = "cannot get store config"
 	errSecretConflict  = "cannot establish control of existing connection secret"
+	errWatchStore      = "cannot watch secret store"
+	errNotWatchable    = "secret store cannot watch secrets"
+	errGetOwnerKind    = "cannot determine kind of connection secret owner"
 
-	errFmtNotOwnedBy = "existing secret is not owned by UID %q"
+	errFmtNotOwnedBy    = "existing secret is not owned by UID %q"
+	errFmtNameCollision = "connection secret name %q rendered from template is already used by the connection secret of UID %q"
+)
+
+const (
+	// defaultPublishRetries is how many times a failed write of connection
+	// details is retried by default.
+	defaultPublishRetries = 2
+
+	// publishGenerationLength is the number of hex characters of the digest
+	// of the published key values that make up a publish generation.
+	publishGenerationLength = 16
 )
 
 // StoreBuilderFn is a function that builds and returns a Store with a given
@@ -59,13 +80,55 @@ func WithStoreBuilder(sb StoreBuilderFn) DetailsManagerOption {
 	}
 }
 
-// WithTLSConfig configures the TLS config to use.
+// WithTLSConfig configures the TLS config to use when connecting to External
+// Secret Store plugins. Use a TLS config returned by a certificates.Source to
+// pick up renewed client certificates without restarting.
 func WithTLSConfig(tcfg *tls.Config) DetailsManagerOption {
 	return func(m *DetailsManager) {
 		m.tcfg = tcfg
 	}
 }
 
+// WithPublishRetries configures how many times a write of connection details
+// that failed is retried. Before each retry the store contents are compared to
+// the desired key values, so that a write that partially succeeded, e.g.
+// because a store wrote the data but failed to update its metadata, is only
+// completed rather than assumed to have failed entirely.
+func WithPublishRetries(n int) DetailsManagerOption {
+	return func(m *DetailsManager) {
+		m.retries = n
+	}
+}
+
+// WithCipher configures the DetailsManager to encrypt connection details using
+// the supplied Cipher before writing them to any store other than Vault or
+// SOPS, and to decrypt them when they are read.
+func WithCipher(c Cipher) DetailsManagerOption {
+	return func(m *DetailsManager) {
+		m.cipher = c
+	}
+}
+
+// An AgeRecorder records when the connection details of connection secret
+// owners last changed, e.g. to expose their age as a metric.
+type AgeRecorder interface {
+	// RecordChanged records the time at which the connection details of the
+	// supplied owner last changed.
+	RecordChanged(gvk schema.GroupVersionKind, o metav1.Object, changed time.Time)
+
+	// Forget forgets the connection details of the supplied owner.
+	Forget(gvk schema.GroupVersionKind, o metav1.Object)
+}
+
+// WithAgeRecorder configures the DetailsManager to record when the connection
+// details it publishes last changed. The time is also stored with the
+// connection secret, so that it survives restarts.
+func WithAgeRecorder(r AgeRecorder) DetailsManagerOption {
+	return func(m *DetailsManager) {
+		m.ages = r
+	}
+}
+
 // DetailsManager is a connection details manager that satisfies the required
 // interfaces to work with connection details by managing interaction with
 // different store implementations.
@@ -74,6 +137,11 @@ type DetailsManager struct {
 	newConfig    func() StoreConfig
 	storeBuilder StoreBuilderFn
 	tcfg         *tls.Config
+	filter       *PropagationFilter
+	retries      int
+	cipher       Cipher
+	ages         AgeRecorder
+	now          func() time.Time
 }
 
 // NewDetailsManager returns a new connection DetailsManager.
@@ -90,6 +158,8 @@ func NewDetailsManager(c client.Client, of schema.GroupVersionKind, o ...Details
 		client:       c,
 		newConfig:    nc,
 		storeBuilder: RuntimeStoreBuilder,
+		retries:      defaultPublishRetries,
+		now:          time.Now,
 	}
 
 	for _, mo := range o {
@@ -113,8 +183,7 @@ func (m *DetailsManager) PublishConnection(ctx context.Context, so resource.Conn
 		return false, errors.Wrap(err, errConnectStore)
 	}
 
-	changed, err := ss.WriteKeyValues(ctx, store.NewSecret(so, store.KeyValues(conn)), SecretToWriteMustBeOwnedBy(so))
-	return changed, errors.Wrap(err, errWriteStore)
+	return m.publish(ctx, ss, so, store.KeyValues(conn))
 }
 
 // UnpublishConnection deletes connection details secret to the configured
@@ -131,7 +200,52 @@ func (m *DetailsManager) UnpublishConnection(ctx context.Context, so resource.Co
 		return errors.Wrap(err, errConnectStore)
 	}
 
-	return errors.Wrap(ss.DeleteKeyValues(ctx, store.NewSecret(so, store.KeyValues(conn)), SecretToDeleteMustBeOwnedBy(so)), errDeleteFromStore)
+	s, err := m.newSecret(so, store.KeyValues(conn))
+	if err != nil {
+		return err
+	}
+	if err := ss.DeleteKeyValues(ctx, s, SecretToDeleteMustBeOwnedBy(so)); err != nil {
+		return errors.Wrap(err, errDeleteFromStore)
+	}
+	m.forget(so)
+	return nil
+}
+
+// AdoptConnection transfers ownership of the connection secret of the supplied
+// ConnectionSecretOwner from the supplied previous UID, e.g. because the owner
+// was restored from a backup. Secrets that are not owned by the previous UID
+// are left alone.
+func (m *DetailsManager) AdoptConnection(ctx context.Context, so resource.ConnectionSecretOwner, previous types.UID) error {
+	// This resource does not want to expose a connection secret.
+	p := so.GetPublishConnectionDetailsTo()
+	if p == nil {
+		return nil
+	}
+
+	ss, err := m.connectStore(ctx, p)
+	if err != nil {
+		return errors.Wrap(err, errConnectStore)
+	}
+
+	name, err := m.secretName(so)
+	if err != nil {
+		return err
+	}
+
+	current := lookupSecret(p)
+	if err := ss.ReadKeyValues(ctx, store.ScopedName{Name: name, Scope: so.GetNamespace()}, current); resource.IgnoreNotFound(err) != nil {
+		return errors.Wrap(err, errReadStore)
+	}
+	if current.GetOwner() != string(previous) {
+		return nil
+	}
+
+	desired := store.NewSecret(so, current.Data)
+	desired.Name = name
+	_, err = ss.WriteKeyValues(ctx, desired, func(_ context.Context, current, _ *store.Secret) error {
+		return secretMustBeOwnedBy(&metav1.ObjectMeta{UID: previous}, current)
+	})
+	return errors.Wrap(err, errWriteStore)
 }
 
 // FetchConnection fetches connection details of a given ConnectionSecretOwner.
@@ -147,8 +261,13 @@ func (m *DetailsManager) FetchConnection(ctx context.Context, so resource.Connec
 		return nil, errors.Wrap(err, errConnectStore)
 	}
 
-	s := &store.Secret{}
-	return managed.ConnectionDetails(s.Data), errors.Wrap(ss.ReadKeyValues(ctx, store.ScopedName{Name: p.Name, Scope: so.GetNamespace()}, s), errReadStore)
+	name, err := m.secretName(so)
+	if err != nil {
+		return nil, err
+	}
+
+	s := lookupSecret(p)
+	return managed.ConnectionDetails(s.Data), errors.Wrap(ss.ReadKeyValues(ctx, store.ScopedName{Name: name, Scope: so.GetNamespace()}, s), errReadStore)
 }
 
 // PropagateConnection propagate connection details from one resource to another.
@@ -163,9 +282,14 @@ func (m *DetailsManager) PropagateConnection(ctx context.Context, to resource.Lo
 		return false, errors.Wrap(err, errConnectStore)
 	}
 
-	sFrom := &store.Secret{}
+	nFrom, err := m.secretName(from)
+	if err != nil {
+		return false, err
+	}
+
+	sFrom := lookupSecret(from.GetPublishConnectionDetailsTo())
 	if err = ssFrom.ReadKeyValues(ctx, store.ScopedName{
-		Name:  from.GetPublishConnectionDetailsTo().Name,
+		Name:  nFrom,
 		Scope: from.GetNamespace(),
 	}, sFrom); err != nil {
 		return false, errors.Wrap(err, errReadStore)
@@ -183,8 +307,175 @@ func (m *DetailsManager) PropagateConnection(ctx context.Context, to resource.Lo
 		return false, errors.Wrap(err, errConnectStore)
 	}
 
-	changed, err := ssTo.WriteKeyValues(ctx, store.NewSecret(to, sFrom.Data), SecretToWriteMustBeOwnedBy(to))
-	return changed, errors.Wrap(err, errWriteStore)
+	data := sFrom.Data
+	if m.filter != nil {
+		data = m.filter.Filter(data)
+	}
+
+	return m.publish(ctx, ssTo, to, data)
+}
+
+// WatchConnections calls the supplied function each time a connection secret
+// owned by one of the supplied UIDs is changed or deleted in the store
+// configured by the supplied store config, until the supplied context is
+// done. Callers typically use it to requeue the owners of changed secrets, so
+// that their connection details are re-synced. It returns an error if the
+// store cannot watch secrets.
+func (m *DetailsManager) WatchConnections(ctx context.Context, storeConfig string, owners []types.UID, fn func(store.SecretEvent)) error {
+	ss, err := m.connectStore(ctx, &v1.PublishConnectionDetailsTo{SecretStoreConfigRef: &v1.Reference{Name: storeConfig}})
+	if err != nil {
+		return errors.Wrap(err, errConnectStore)
+	}
+	ws, ok := ss.(WatchingStore)
+	if !ok {
+		return errors.New(errNotWatchable)
+	}
+	return errors.Wrap(ws.WatchSecrets(ctx, owners, fn), errWatchStore)
+}
+
+// publish writes the supplied key values to the connection secret of the
+// supplied owner, labelled with their publish generation and the time they
+// last changed. A write that fails is retried until the store contents match
+// the desired key values, or the configured number of retries is exhausted.
+func (m *DetailsManager) publish(ctx context.Context, ss Store, so store.SecretOwner, kv store.KeyValues) (bool, error) {
+	desired, err := m.newSecret(so, kv)
+	if err != nil {
+		return false, err
+	}
+
+	// NewSecret shares its metadata with the owner's spec. Copy it, so that the
+	// publish generation doesn't leak into the owner.
+	desired.Metadata = desired.Metadata.DeepCopy()
+	desired.Metadata.SetPublishGeneration(PublishGeneration(kv))
+
+	lastChanged := m.now()
+	desired.Metadata.SetLastChanged(lastChanged)
+	keep := func(_ context.Context, current, desired *store.Secret) error {
+		if t, ok := unchangedSince(current, desired); ok {
+			desired.Metadata.SetLastChanged(t)
+			lastChanged = t
+		}
+		return nil
+	}
+
+	wo := []store.WriteOption{SecretToWriteMustBeOwnedBy(so), keep}
+	if IsSecretNameTemplate(so.GetPublishConnectionDetailsTo().Name) {
+		// Report owners whose templates render the same name as a collision,
+		// rather than only as a secret they don't own.
+		wo = append([]store.WriteOption{secretToWriteMustNotCollide(so)}, wo...)
+	}
+
+	for i := 0; i <= m.retries; i++ {
+		var changed bool
+		if changed, err = ss.WriteKeyValues(ctx, desired, wo...); err == nil {
+			m.recordChanged(so, lastChanged)
+			return changed, nil
+		}
+
+		// The write may have been partially or even fully applied. Compare
+		// the store contents to what we want, rather than assuming nothing
+		// was written.
+		current := lookupSecret(so.GetPublishConnectionDetailsTo())
+		if ss.ReadKeyValues(ctx, desired.ScopedName, current) == nil && published(current, desired) {
+			m.recordChanged(so, lastChanged)
+			return true, nil
+		}
+	}
+	return false, errors.Wrap(err, errWriteStore)
+}
+
+// secretName returns the name of the connection secret of the supplied owner,
+// rendering it if it is a template.
+func (m *DetailsManager) secretName(so store.SecretOwner) (string, error) {
+	name := so.GetPublishConnectionDetailsTo().Name
+	if !IsSecretNameTemplate(name) {
+		return name, nil
+	}
+	gvk, err := apiutil.GVKForObject(so, m.client.Scheme())
+	if err != nil {
+		return "", errors.Wrap(err, errGetOwnerKind)
+	}
+	return RenderSecretName(name, so, gvk)
+}
+
+// newSecret returns the connection secret of the supplied owner containing the
+// supplied key values.
+func (m *DetailsManager) newSecret(so store.SecretOwner, kv store.KeyValues) (*store.Secret, error) {
+	name, err := m.secretName(so)
+	if err != nil {
+		return nil, err
+	}
+	s := store.NewSecret(so, kv)
+	s.Name = name
+	return s, nil
+}
+
+// published returns true if the current secret is owned by the owner of the
+// desired secret and contains its publish generation and key values.
+func published(current, desired *store.Secret) bool {
+	if current.Metadata == nil || current.GetOwner() != desired.GetOwner() {
+		return false
+	}
+	if current.Metadata.GetPublishGeneration() != desired.Metadata.GetPublishGeneration() {
+		return false
+	}
+	for k, v := range desired.Data {
+		if cv, ok := current.Data[k]; !ok || !bytes.Equal(cv, v) {
+			return false
+		}
+	}
+	return true
+}
+
+// unchangedSince returns the time at which the current secret's key values last
+// changed, if they have the same publish generation as the desired secret.
+func unchangedSince(current, desired *store.Secret) (time.Time, bool) {
+	if current.Metadata == nil || current.Metadata.GetPublishGeneration() != desired.Metadata.GetPublishGeneration() {
+		return time.Time{}, false
+	}
+	return current.Metadata.GetLastChanged()
+}
+
+// recordChanged records the time at which the connection details of the
+// supplied owner last changed, if an AgeRecorder is configured. Owners of an
+// unknown kind are not recorded.
+func (m *DetailsManager) recordChanged(so store.SecretOwner, t time.Time) {
+	if m.ages == nil {
+		return
+	}
+	if gvk, err := apiutil.GVKForObject(so, m.client.Scheme()); err == nil {
+		m.ages.RecordChanged(gvk, so, t)
+	}
+}
+
+// forget forgets the connection details of the supplied owner, if an
+// AgeRecorder is configured.
+func (m *DetailsManager) forget(so store.SecretOwner) {
+	if m.ages == nil {
+		return
+	}
+	if gvk, err := apiutil.GVKForObject(so, m.client.Scheme()); err == nil {
+		m.ages.Forget(gvk, so)
+	}
+}
+
+// PublishGeneration returns the publish generation of the supplied key values.
+// It is a digest of the key values, so publishing the same key values always
+// results in the same generation.
+func PublishGeneration(kv store.KeyValues) string {
+	keys := make([]string, 0, len(kv))
+	for k := range kv {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, k := range keys {
+		// Length prefixes keep distinct key values from hashing alike.
+		_, _ = h.Write([]byte(fmt.Sprintf("%d:%s%d:", len(k), k, len(kv[k]))))
+		_, _ = h.Write(kv[k])
+	}
+	return hex.EncodeToString(h.Sum(nil))[:publishGenerationLength]
 }
 
 func (m *DetailsManager) connectStore(ctx context.Context, p *v1.PublishConnectionDetailsTo) (Store, error) {
@@ -193,7 +484,18 @@ func (m *DetailsManager) connectStore(ctx context.Context, p *v1.PublishConnecti
 		return nil, errors.Wrap(err, errGetStoreConfig)
 	}
 
-	return m.storeBuilder(ctx, m.client, m.tcfg, sc.GetStoreConfig())
+	cfg := sc.GetStoreConfig()
+	ss, err := m.storeBuilder(ctx, m.client, m.tcfg, cfg)
+	if err != nil || m.cipher == nil {
+		return ss, err
+	}
+
+	// Vault and SOPS stores encrypt the secrets they store, so there's no
+	// need to encrypt them again.
+	if cfg.Type != nil && (*cfg.Type == v1.SecretStoreVault || *cfg.Type == v1.SecretStoreSOPS) {
+		return ss, nil
+	}
+	return NewEncryptingStore(ss, m.cipher), nil
 }
 
 // SecretToWriteMustBeOwnedBy requires that the current object is a
@@ -212,6 +514,33 @@ func SecretToDeleteMustBeOwnedBy(so metav1.Object) store.DeleteOption {
 	}
 }
 
+// secretToWriteMustNotCollide requires that the current secret, if it is owned
+// at all, is owned by the supplied owner.
+func secretToWriteMustNotCollide(so metav1.Object) store.WriteOption {
+	return func(_ context.Context, current, desired *store.Secret) error {
+		if o := current.GetOwner(); o != "" && o != string(so.GetUID()) {
+			return errors.Errorf(errFmtNameCollision, desired.Name, o)
+		}
+		return nil
+	}
+}
+
+// lookupSecret returns a Secret to read the connection secret described by
+// the supplied config into. Its metadata lets stores locate the secret, e.g.
+// by rendering a Vault namespace from its labels. It never claims an owner, so
+// a store that reads no metadata cannot be mistaken for having read one.
+func lookupSecret(p *v1.PublishConnectionDetailsTo) *store.Secret {
+	s := &store.Secret{}
+	if p.Metadata == nil {
+		return s
+	}
+	s.Metadata = p.Metadata.DeepCopy()
+	delete(s.Metadata.Labels, v1.LabelKeyOwnerUID)
+	delete(s.Metadata.Labels, v1.LabelKeyPublishGeneration)
+	delete(s.Metadata.Labels, v1.LabelKeyLastChanged)
+	return s
+}
+
 func secretMustBeOwnedBy(so metav1.Object, secret *store.Secret) error {
 	if secret.Metadata == nil || secret.Metadata.GetOwnerUID() != string(so.GetUID()) {
 		return errors.Errorf(errFmtNotOwnedBy, string(so.GetUID()))
@@ -20,17 +20,20 @@ import (
 	"context"
 	"crypto/tls"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/connection/fake"
 	"github.com/crossplane/crossplane-runtime/pkg/connection/store"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	resourcefake "github.com/crossplane/crossplane-runtime/pkg/resource/fake"
@@ -154,6 +157,58 @@ func TestManagerConnectStore(t *testing.T) {
 	}
 }
 
+func TestManagerConnectStoreWithCipher(t *testing.T) {
+	vault := v1.SecretStoreVault
+
+	cases := map[string]struct {
+		reason    string
+		storeType *v1.SecretStoreType
+		want      bool
+	}{
+		"EncryptNonVaultStore": {
+			reason:    "We should encrypt connection details written to stores other than Vault.",
+			storeType: &fakeStore,
+			want:      true,
+		},
+		"DoNotEncryptVaultStore": {
+			reason:    "We should not encrypt connection details written to Vault.",
+			storeType: &vault,
+			want:      false,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &test.MockClient{
+				MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+					*obj.(*fake.StoreConfig) = fake.StoreConfig{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: fakeConfig,
+						},
+						Config: v1.SecretStoreConfig{
+							Type: tc.storeType,
+						},
+					}
+					return nil
+				},
+				MockScheme: test.NewMockSchemeFn(resourcefake.SchemeWith(&fake.StoreConfig{})),
+			}
+			sb := func(_ context.Context, _ client.Client, _ *tls.Config, _ v1.SecretStoreConfig) (Store, error) {
+				return &fake.SecretStore{}, nil
+			}
+			m := NewDetailsManager(c, resourcefake.GVK(&fake.StoreConfig{}), WithStoreBuilder(sb), WithCipher(&prefixCipher{}))
+
+			ss, err := m.connectStore(context.Background(), &v1.PublishConnectionDetailsTo{SecretStoreConfigRef: &v1.Reference{Name: fakeConfig}})
+			if err != nil {
+				t.Fatalf("m.connectStore(...): %s", err)
+			}
+			_, got := ss.(*EncryptingStore)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\nm.connectStore(...): -want encrypting store, +got encrypting store:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestManagerPublishConnection(t *testing.T) {
 	type args struct {
 		c  client.Client
@@ -229,6 +284,9 @@ func TestManagerPublishConnection(t *testing.T) {
 					MockScheme: test.NewMockSchemeFn(resourcefake.SchemeWith(&fake.StoreConfig{})),
 				},
 				sb: fakeStoreBuilderFn(fake.SecretStore{
+					ReadKeyValuesFn: func(ctx context.Context, n store.ScopedName, s *store.Secret) error {
+						return errBoom
+					},
 					WriteKeyValuesFn: func(ctx context.Context, s *store.Secret, wo ...store.WriteOption) (bool, error) {
 						return false, errBoom
 					},
@@ -245,6 +303,108 @@ func TestManagerPublishConnection(t *testing.T) {
 				err: errors.Wrap(errBoom, errWriteStore),
 			},
 		},
+		"RecoveredPartialWrite": {
+			reason: "We should consider connection details published if the store contains them despite a write error.",
+			args: args{
+				c: &test.MockClient{
+					MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+						*obj.(*fake.StoreConfig) = fake.StoreConfig{
+							ObjectMeta: metav1.ObjectMeta{
+								Name: fakeConfig,
+							},
+							Config: v1.SecretStoreConfig{
+								Type: &fakeStore,
+							},
+						}
+						return nil
+					},
+					MockScheme: test.NewMockSchemeFn(resourcefake.SchemeWith(&fake.StoreConfig{})),
+				},
+				sb: fakeStoreBuilderFn(fake.SecretStore{
+					ReadKeyValuesFn: func(ctx context.Context, n store.ScopedName, s *store.Secret) error {
+						s.Metadata = &v1.ConnectionSecretMetadata{
+							Labels: map[string]string{
+								v1.LabelKeyOwnerUID:          testUID,
+								v1.LabelKeyPublishGeneration: PublishGeneration(store.KeyValues{"key": []byte("value")}),
+							},
+						}
+						s.Data = store.KeyValues{"key": []byte("value"), "other": []byte("value")}
+						return nil
+					},
+					WriteKeyValuesFn: func(ctx context.Context, s *store.Secret, wo ...store.WriteOption) (bool, error) {
+						return false, errBoom
+					},
+				}),
+				conn: managed.ConnectionDetails{"key": []byte("value")},
+				so: &resourcefake.MockConnectionSecretOwner{
+					ObjectMeta: metav1.ObjectMeta{
+						UID: testUID,
+					},
+					To: &v1.PublishConnectionDetailsTo{
+						SecretStoreConfigRef: &v1.Reference{
+							Name: fakeConfig,
+						},
+					},
+				},
+			},
+			want: want{
+				published: true,
+			},
+		},
+		"RetriedPartialWrite": {
+			reason: "We should retry a write that left the store without the desired connection details.",
+			args: args{
+				c: &test.MockClient{
+					MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+						*obj.(*fake.StoreConfig) = fake.StoreConfig{
+							ObjectMeta: metav1.ObjectMeta{
+								Name: fakeConfig,
+							},
+							Config: v1.SecretStoreConfig{
+								Type: &fakeStore,
+							},
+						}
+						return nil
+					},
+					MockScheme: test.NewMockSchemeFn(resourcefake.SchemeWith(&fake.StoreConfig{})),
+				},
+				sb: func() StoreBuilderFn {
+					writes := 0
+					return fakeStoreBuilderFn(fake.SecretStore{
+						ReadKeyValuesFn: func(ctx context.Context, n store.ScopedName, s *store.Secret) error {
+							// The metadata was written, but the data was not.
+							s.Metadata = &v1.ConnectionSecretMetadata{
+								Labels: map[string]string{
+									v1.LabelKeyOwnerUID:          testUID,
+									v1.LabelKeyPublishGeneration: PublishGeneration(store.KeyValues{"key": []byte("value")}),
+								},
+							}
+							return nil
+						},
+						WriteKeyValuesFn: func(ctx context.Context, s *store.Secret, wo ...store.WriteOption) (bool, error) {
+							if writes++; writes == 1 {
+								return false, errBoom
+							}
+							return true, nil
+						},
+					})
+				}(),
+				conn: managed.ConnectionDetails{"key": []byte("value")},
+				so: &resourcefake.MockConnectionSecretOwner{
+					ObjectMeta: metav1.ObjectMeta{
+						UID: testUID,
+					},
+					To: &v1.PublishConnectionDetailsTo{
+						SecretStoreConfigRef: &v1.Reference{
+							Name: fakeConfig,
+						},
+					},
+				},
+			},
+			want: want{
+				published: true,
+			},
+		},
 		"SuccessfulPublishWithOwnerUID": {
 			reason: "We should return no error when published successfully.",
 			args: args{
@@ -267,6 +427,9 @@ func TestManagerPublishConnection(t *testing.T) {
 						if diff := cmp.Diff(testUID, s.Metadata.GetOwnerUID()); diff != "" {
 							t.Errorf("\nReason: %s\nm.publishConnection(...): -want ownerUID, +got ownerUID:\n%s", testUID, diff)
 						}
+						if diff := cmp.Diff(PublishGeneration(nil), s.Metadata.GetPublishGeneration()); diff != "" {
+							t.Errorf("\nReason: %s\nm.publishConnection(...): -want publish generation, +got publish generation:\n%s", testUID, diff)
+						}
 						return true, nil
 					},
 				}),
@@ -286,6 +449,99 @@ func TestManagerPublishConnection(t *testing.T) {
 				published: true,
 			},
 		},
+		"TemplatedName": {
+			reason: "We should publish to the secret name rendered from the owner's template.",
+			args: args{
+				c: &test.MockClient{
+					MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+						*obj.(*fake.StoreConfig) = fake.StoreConfig{
+							ObjectMeta: metav1.ObjectMeta{
+								Name: fakeConfig,
+							},
+							Config: v1.SecretStoreConfig{
+								Type: &fakeStore,
+							},
+						}
+						return nil
+					},
+					MockScheme: test.NewMockSchemeFn(resourcefake.SchemeWith(&fake.StoreConfig{}, &resourcefake.MockConnectionSecretOwner{})),
+				},
+				sb: fakeStoreBuilderFn(fake.SecretStore{
+					WriteKeyValuesFn: func(ctx context.Context, s *store.Secret, wo ...store.WriteOption) (bool, error) {
+						if s.Name != "cool-claim-conn" {
+							return false, errors.Errorf("unexpected secret name %q", s.Name)
+						}
+						return true, nil
+					},
+				}),
+				so: &resourcefake.MockConnectionSecretOwner{
+					ObjectMeta: metav1.ObjectMeta{
+						UID:    testUID,
+						Labels: map[string]string{meta.LabelKeyClaimName: "cool-claim"},
+					},
+					To: &v1.PublishConnectionDetailsTo{
+						Name: "{{ .ClaimName }}-conn",
+						SecretStoreConfigRef: &v1.Reference{
+							Name: fakeConfig,
+						},
+					},
+				},
+			},
+			want: want{
+				published: true,
+			},
+		},
+		"TemplatedNameCollision": {
+			reason: "We should return an error if the rendered secret name is used by the secret of another owner.",
+			args: args{
+				c: &test.MockClient{
+					MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+						*obj.(*fake.StoreConfig) = fake.StoreConfig{
+							ObjectMeta: metav1.ObjectMeta{
+								Name: fakeConfig,
+							},
+							Config: v1.SecretStoreConfig{
+								Type: &fakeStore,
+							},
+						}
+						return nil
+					},
+					MockScheme: test.NewMockSchemeFn(resourcefake.SchemeWith(&fake.StoreConfig{}, &resourcefake.MockConnectionSecretOwner{})),
+				},
+				sb: fakeStoreBuilderFn(fake.SecretStore{
+					ReadKeyValuesFn: func(ctx context.Context, n store.ScopedName, s *store.Secret) error {
+						s.Metadata = &v1.ConnectionSecretMetadata{Labels: map[string]string{v1.LabelKeyOwnerUID: "other-uid"}}
+						return nil
+					},
+					WriteKeyValuesFn: func(ctx context.Context, s *store.Secret, wo ...store.WriteOption) (bool, error) {
+						current := &store.Secret{
+							Metadata: &v1.ConnectionSecretMetadata{Labels: map[string]string{v1.LabelKeyOwnerUID: "other-uid"}},
+						}
+						for _, o := range wo {
+							if err := o(ctx, current, s); err != nil {
+								return false, err
+							}
+						}
+						return true, nil
+					},
+				}),
+				so: &resourcefake.MockConnectionSecretOwner{
+					ObjectMeta: metav1.ObjectMeta{
+						UID:    testUID,
+						Labels: map[string]string{meta.LabelKeyClaimName: "cool-claim"},
+					},
+					To: &v1.PublishConnectionDetailsTo{
+						Name: "{{ .ClaimName }}-conn",
+						SecretStoreConfigRef: &v1.Reference{
+							Name: fakeConfig,
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errors.Errorf(errFmtNameCollision, "cool-claim-conn", "other-uid"), errWriteStore),
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
@@ -302,6 +558,145 @@ func TestManagerPublishConnection(t *testing.T) {
 	}
 }
 
+func TestManagerPublishConnectionLastChanged(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	before := now.Add(-24 * time.Hour)
+	kv := store.KeyValues{"key": []byte("value")}
+
+	type args struct {
+		current *store.Secret
+	}
+	type want struct {
+		label    string
+		recorded map[string]time.Time
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NewSecret": {
+			reason: "Connection details written to a new secret should be considered changed now.",
+			args: args{
+				current: &store.Secret{
+					Metadata: &v1.ConnectionSecretMetadata{
+						Labels: map[string]string{v1.LabelKeyOwnerUID: testUID},
+					},
+				},
+			},
+			want: want{
+				label:    "1700000000",
+				recorded: map[string]time.Time{"cool": now},
+			},
+		},
+		"Changed": {
+			reason: "Connection details that differ from those in the store should be considered changed now.",
+			args: args{
+				current: &store.Secret{
+					Metadata: &v1.ConnectionSecretMetadata{
+						Labels: map[string]string{
+							v1.LabelKeyOwnerUID:          testUID,
+							v1.LabelKeyPublishGeneration: PublishGeneration(store.KeyValues{"key": []byte("old")}),
+							v1.LabelKeyLastChanged:       "1699913600",
+						},
+					},
+				},
+			},
+			want: want{
+				label:    "1700000000",
+				recorded: map[string]time.Time{"cool": now},
+			},
+		},
+		"Unchanged": {
+			reason: "Connection details that match those in the store should keep the time they last changed.",
+			args: args{
+				current: &store.Secret{
+					Metadata: &v1.ConnectionSecretMetadata{
+						Labels: map[string]string{
+							v1.LabelKeyOwnerUID:          testUID,
+							v1.LabelKeyPublishGeneration: PublishGeneration(kv),
+							v1.LabelKeyLastChanged:       "1699913600",
+						},
+					},
+				},
+			},
+			want: want{
+				label:    "1699913600",
+				recorded: map[string]time.Time{"cool": before},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &test.MockClient{
+				MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+					*obj.(*fake.StoreConfig) = fake.StoreConfig{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: fakeConfig,
+						},
+						Config: v1.SecretStoreConfig{
+							Type: &fakeStore,
+						},
+					}
+					return nil
+				},
+				MockScheme: test.NewMockSchemeFn(resourcefake.SchemeWith(&fake.StoreConfig{}, &resourcefake.MockConnectionSecretOwner{})),
+			}
+			var label string
+			sb := fakeStoreBuilderFn(fake.SecretStore{
+				WriteKeyValuesFn: func(ctx context.Context, s *store.Secret, wo ...store.WriteOption) (bool, error) {
+					for _, o := range wo {
+						if err := o(ctx, tc.args.current, s); err != nil {
+							return false, err
+						}
+					}
+					label = s.Metadata.Labels[v1.LabelKeyLastChanged]
+					return true, nil
+				},
+			})
+			ages := &ageRecorder{recorded: map[string]time.Time{}}
+			so := &resourcefake.MockConnectionSecretOwner{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cool",
+					UID:  testUID,
+				},
+				To: &v1.PublishConnectionDetailsTo{
+					SecretStoreConfigRef: &v1.Reference{
+						Name: fakeConfig,
+					},
+				},
+			}
+
+			m := NewDetailsManager(c, resourcefake.GVK(&fake.StoreConfig{}), WithStoreBuilder(sb), WithAgeRecorder(ages))
+			m.now = func() time.Time { return now }
+
+			if _, err := m.PublishConnection(context.Background(), so, managed.ConnectionDetails(kv)); err != nil {
+				t.Fatalf("\n%s\nm.PublishConnection(...): %s", tc.reason, err)
+			}
+			got := want{label: label, recorded: ages.recorded}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nm.PublishConnection(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// An ageRecorder records the time connection details last changed by owner
+// name.
+type ageRecorder struct {
+	recorded map[string]time.Time
+}
+
+func (r *ageRecorder) RecordChanged(_ schema.GroupVersionKind, o metav1.Object, changed time.Time) {
+	r.recorded[o.GetName()] = changed
+}
+
+func (r *ageRecorder) Forget(_ schema.GroupVersionKind, o metav1.Object) {
+	delete(r.recorded, o.GetName())
+}
+
 func TestManagerUnpublishConnection(t *testing.T) {
 	type args struct {
 		c  client.Client
@@ -645,10 +1040,149 @@ func TestManagerFetchConnection(t *testing.T) {
 	}
 }
 
+func TestManagerAdoptConnection(t *testing.T) {
+	previous := types.UID("previous-uid")
+
+	// getConfig returns the fake store config.
+	getConfig := func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+		*obj.(*fake.StoreConfig) = fake.StoreConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: fakeConfig,
+			},
+			Config: v1.SecretStoreConfig{
+				Type: &fakeStore,
+			},
+		}
+		return nil
+	}
+
+	// ownedBy returns a read function that reads a secret owned by the
+	// supplied UID.
+	ownedBy := func(uid types.UID) func(ctx context.Context, n store.ScopedName, s *store.Secret) error {
+		return func(_ context.Context, _ store.ScopedName, s *store.Secret) error {
+			s.Metadata = &v1.ConnectionSecretMetadata{}
+			s.Metadata.SetOwnerUID(uid)
+			s.Data = store.KeyValues{"key1": []byte("val1")}
+			return nil
+		}
+	}
+
+	type args struct {
+		c  client.Client
+		sb StoreBuilderFn
+
+		so resource.ConnectionSecretOwner
+	}
+
+	type want struct {
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"NoConnectionDetails": {
+			reason: "We should return no error if resource does not want to expose a connection secret.",
+			args: args{
+				c: &test.MockClient{
+					MockScheme: test.NewMockSchemeFn(resourcefake.SchemeWith(&fake.StoreConfig{})),
+				},
+				so: &resourcefake.MockConnectionSecretOwner{To: nil},
+			},
+		},
+		"CannotRead": {
+			reason: "We should return a proper error when reading from the secret store failed.",
+			args: args{
+				c: &test.MockClient{
+					MockGet:    getConfig,
+					MockScheme: test.NewMockSchemeFn(resourcefake.SchemeWith(&fake.StoreConfig{})),
+				},
+				sb: fakeStoreBuilderFn(fake.SecretStore{
+					ReadKeyValuesFn: func(ctx context.Context, n store.ScopedName, s *store.Secret) error {
+						return errBoom
+					},
+				}),
+				so: &resourcefake.MockConnectionSecretOwner{
+					To: &v1.PublishConnectionDetailsTo{
+						SecretStoreConfigRef: &v1.Reference{Name: fakeConfig},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errReadStore),
+			},
+		},
+		"NotOwnedByPrevious": {
+			reason: "We should not adopt a secret that isn't owned by the previous UID.",
+			args: args{
+				c: &test.MockClient{
+					MockGet:    getConfig,
+					MockScheme: test.NewMockSchemeFn(resourcefake.SchemeWith(&fake.StoreConfig{})),
+				},
+				sb: fakeStoreBuilderFn(fake.SecretStore{
+					ReadKeyValuesFn: ownedBy("someone-else"),
+				}),
+				so: &resourcefake.MockConnectionSecretOwner{
+					To: &v1.PublishConnectionDetailsTo{
+						SecretStoreConfigRef: &v1.Reference{Name: fakeConfig},
+					},
+				},
+			},
+		},
+		"Adopted": {
+			reason: "We should rewrite a secret owned by the previous UID with the owner's UID, preserving its data.",
+			args: args{
+				c: &test.MockClient{
+					MockGet:    getConfig,
+					MockScheme: test.NewMockSchemeFn(resourcefake.SchemeWith(&fake.StoreConfig{})),
+				},
+				sb: fakeStoreBuilderFn(fake.SecretStore{
+					ReadKeyValuesFn: ownedBy(previous),
+					WriteKeyValuesFn: func(ctx context.Context, s *store.Secret, wo ...store.WriteOption) (bool, error) {
+						current := &store.Secret{}
+						_ = ownedBy(previous)(ctx, s.ScopedName, current)
+						for _, o := range wo {
+							if err := o(ctx, current, s); err != nil {
+								return false, err
+							}
+						}
+						if diff := cmp.Diff(testUID, s.GetOwner()); diff != "" {
+							t.Errorf("\nWriteKeyValues(...): -want owner, +got owner:\n%s", diff)
+						}
+						if diff := cmp.Diff(store.KeyValues{"key1": []byte("val1")}, s.Data); diff != "" {
+							t.Errorf("\nWriteKeyValues(...): -want data, +got data:\n%s", diff)
+						}
+						return true, nil
+					},
+				}),
+				so: &resourcefake.MockConnectionSecretOwner{
+					ObjectMeta: metav1.ObjectMeta{UID: testUID},
+					To: &v1.PublishConnectionDetailsTo{
+						SecretStoreConfigRef: &v1.Reference{Name: fakeConfig},
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := NewDetailsManager(tc.args.c, resourcefake.GVK(&fake.StoreConfig{}), WithStoreBuilder(tc.args.sb))
+
+			err := m.AdoptConnection(context.Background(), tc.args.so, previous)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nm.AdoptConnection(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestManagerPropagateConnection(t *testing.T) {
 	type args struct {
 		c  client.Client
 		sb StoreBuilderFn
+		o  []DetailsManagerOption
 
 		to   resource.LocalConnectionSecretOwner
 		from resource.ConnectionSecretOwner
@@ -1210,10 +1744,77 @@ func TestManagerPropagateConnection(t *testing.T) {
 				propagated: true,
 			},
 		},
+		"SuccessfulPropagateFiltered": {
+			reason: "We should only propagate the connection details allowed by the configured filter.",
+			args: args{
+				c: &test.MockClient{
+					MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+						*obj.(*fake.StoreConfig) = fake.StoreConfig{
+							ObjectMeta: metav1.ObjectMeta{
+								Name: fakeConfig,
+							},
+							Config: v1.SecretStoreConfig{
+								Type: &fakeStore,
+							},
+						}
+						return nil
+					},
+					MockScheme: test.NewMockSchemeFn(resourcefake.SchemeWith(&fake.StoreConfig{})),
+				},
+				sb: fakeStoreBuilderFn(fake.SecretStore{
+					ReadKeyValuesFn: func(ctx context.Context, n store.ScopedName, s *store.Secret) error {
+						s.Metadata = &v1.ConnectionSecretMetadata{
+							Labels: map[string]string{
+								v1.LabelKeyOwnerUID: testUID,
+							},
+						}
+						s.Data = store.KeyValues{
+							"endpoint": []byte("https://example.org"),
+							"password": []byte("hunter2"),
+						}
+						return nil
+					},
+					WriteKeyValuesFn: func(ctx context.Context, s *store.Secret, wo ...store.WriteOption) (bool, error) {
+						want := store.KeyValues{"url": []byte("https://example.org")}
+						if diff := cmp.Diff(want, s.Data); diff != "" {
+							return false, errors.Errorf("unexpected connection details: -want, +got:\n%s", diff)
+						}
+						return true, nil
+					},
+				}),
+				o: []DetailsManagerOption{WithPropagationFilter(PropagationFilter{
+					Deny:    []string{"password"},
+					Renames: map[string]string{"endpoint": "url"},
+				})},
+				from: &resourcefake.MockConnectionSecretOwner{
+					ObjectMeta: metav1.ObjectMeta{
+						UID: testUID,
+					},
+					To: &v1.PublishConnectionDetailsTo{
+						SecretStoreConfigRef: &v1.Reference{
+							Name: fakeConfig,
+						},
+					},
+				},
+				to: &resourcefake.MockLocalConnectionSecretOwner{
+					ObjectMeta: metav1.ObjectMeta{
+						UID: testUID,
+					},
+					To: &v1.PublishConnectionDetailsTo{
+						SecretStoreConfigRef: &v1.Reference{
+							Name: fakeConfig,
+						},
+					},
+				},
+			},
+			want: want{
+				propagated: true,
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			m := NewDetailsManager(tc.args.c, resourcefake.GVK(&fake.StoreConfig{}), WithStoreBuilder(tc.args.sb))
+			m := NewDetailsManager(tc.args.c, resourcefake.GVK(&fake.StoreConfig{}), append([]DetailsManagerOption{WithStoreBuilder(tc.args.sb)}, tc.args.o...)...)
 
 			got, err := m.PropagateConnection(context.Background(), tc.args.to, tc.args.from)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
@@ -1226,6 +1827,151 @@ func TestManagerPropagateConnection(t *testing.T) {
 	}
 }
 
+func TestManagerWatchConnections(t *testing.T) {
+	owners := []types.UID{testUID}
+	event := store.SecretEvent{Type: store.SecretChanged, Secret: &store.Secret{ScopedName: store.ScopedName{Name: "cool"}}}
+
+	getConfig := func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+		*obj.(*fake.StoreConfig) = fake.StoreConfig{Config: v1.SecretStoreConfig{Type: &fakeStore}}
+		return nil
+	}
+	storeBuilder := func(ss Store) StoreBuilderFn {
+		return func(_ context.Context, _ client.Client, _ *tls.Config, _ v1.SecretStoreConfig) (Store, error) {
+			return ss, nil
+		}
+	}
+
+	type args struct {
+		c  client.Client
+		sb StoreBuilderFn
+	}
+	type want struct {
+		events []store.SecretEvent
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"CannotConnect": {
+			reason: "We should return any error encountered while connecting to the store.",
+			args: args{
+				c: &test.MockClient{
+					MockGet:    test.NewMockGetFn(errBoom),
+					MockScheme: test.NewMockSchemeFn(resourcefake.SchemeWith(&fake.StoreConfig{})),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrap(errBoom, errGetStoreConfig), errConnectStore),
+			},
+		},
+		"NotWatchable": {
+			reason: "We should return an error if the store cannot watch secrets.",
+			args: args{
+				c: &test.MockClient{
+					MockGet:    getConfig,
+					MockScheme: test.NewMockSchemeFn(resourcefake.SchemeWith(&fake.StoreConfig{})),
+				},
+				sb: storeBuilder(&fake.SecretStore{}),
+			},
+			want: want{
+				err: errors.New(errNotWatchable),
+			},
+		},
+		"WatchError": {
+			reason: "We should return any error encountered while watching the store.",
+			args: args{
+				c: &test.MockClient{
+					MockGet:    getConfig,
+					MockScheme: test.NewMockSchemeFn(resourcefake.SchemeWith(&fake.StoreConfig{})),
+				},
+				sb: storeBuilder(&fake.WatchingSecretStore{
+					WatchSecretsFn: func(_ context.Context, _ []types.UID, _ func(store.SecretEvent)) error {
+						return errBoom
+					},
+				}),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errWatchStore),
+			},
+		},
+		"Watched": {
+			reason: "We should pass events for secrets owned by the supplied UIDs to the supplied function.",
+			args: args{
+				c: &test.MockClient{
+					MockGet:    getConfig,
+					MockScheme: test.NewMockSchemeFn(resourcefake.SchemeWith(&fake.StoreConfig{})),
+				},
+				sb: storeBuilder(&fake.WatchingSecretStore{
+					WatchSecretsFn: func(_ context.Context, o []types.UID, fn func(store.SecretEvent)) error {
+						if diff := cmp.Diff(owners, o); diff != "" {
+							return errors.Errorf("unexpected owners: %s", diff)
+						}
+						fn(event)
+						return nil
+					},
+				}),
+			},
+			want: want{
+				events: []store.SecretEvent{event},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := NewDetailsManager(tc.args.c, resourcefake.GVK(&fake.StoreConfig{}), WithStoreBuilder(tc.args.sb))
+
+			var got []store.SecretEvent
+			err := m.WatchConnections(context.Background(), fakeConfig, owners, func(e store.SecretEvent) { got = append(got, e) })
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nm.WatchConnections(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.events, got); diff != "" {
+				t.Errorf("\n%s\nm.WatchConnections(...): -want events, +got events:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPublishGeneration(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		a      store.KeyValues
+		b      store.KeyValues
+		want   bool
+	}{
+		"SameKeyValues": {
+			reason: "The same key values should have the same publish generation.",
+			a:      store.KeyValues{"a": []byte("1"), "b": []byte("2")},
+			b:      store.KeyValues{"b": []byte("2"), "a": []byte("1")},
+			want:   true,
+		},
+		"DifferentValue": {
+			reason: "Changing a value should change the publish generation.",
+			a:      store.KeyValues{"a": []byte("1")},
+			b:      store.KeyValues{"a": []byte("2")},
+			want:   false,
+		},
+		"ShiftedBoundary": {
+			reason: "Moving bytes between a key and its value should change the publish generation.",
+			a:      store.KeyValues{"ab": []byte("c")},
+			b:      store.KeyValues{"a": []byte("bc")},
+			want:   false,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := PublishGeneration(tc.a) == PublishGeneration(tc.b)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\nPublishGeneration(a) == PublishGeneration(b): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func fakeStoreBuilderFn(ss fake.SecretStore) StoreBuilderFn {
 	return func(_ context.Context, _ client.Client, tcfg *tls.Config, cfg v1.SecretStoreConfig) (Store, error) {
 		if *cfg.Type == fakeStore {
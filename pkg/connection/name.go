@@ -0,0 +1,108 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connection
+
+import (
+	"strings"
+	"sync"
+	"text/template"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+)
+
+// Error strings.
+const (
+	errParseSecretNameTemplate  = "cannot parse connection secret name template"
+	errRenderSecretNameTemplate = "cannot render connection secret name template"
+	errEmptySecretName          = "connection secret name template rendered an empty name"
+)
+
+// SecretNameTemplateData is the data available to a connection secret name
+// template. For example the template "{{ .ClaimName }}-{{ .Kind }}" renders
+// the name of the claim the owner belongs to and the owner's kind. Only fields
+// that can't change over the owner's lifetime are available, so that a
+// rendered name never changes and orphans a published secret.
+type SecretNameTemplateData struct {
+	// Name and Namespace of the connection secret owner.
+	Name      string
+	Namespace string
+
+	// Kind of the connection secret owner, in lower case.
+	Kind string
+
+	// ClaimName and ClaimNamespace of the claim the connection secret owner
+	// belongs to, if any.
+	ClaimName      string
+	ClaimNamespace string
+}
+
+// secretNameTemplates caches parsed connection secret name templates, keyed by
+// the template string, so each template is parsed only once.
+var secretNameTemplates sync.Map
+
+// parseSecretNameTemplate returns the parsed form of the supplied connection
+// secret name template.
+func parseSecretNameTemplate(tmpl string) (*template.Template, error) {
+	if t, ok := secretNameTemplates.Load(tmpl); ok {
+		return t.(*template.Template), nil
+	}
+	t, err := template.New("secret-name").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return nil, errors.Wrap(err, errParseSecretNameTemplate)
+	}
+	secretNameTemplates.Store(tmpl, t)
+	return t, nil
+}
+
+// IsSecretNameTemplate returns true if the supplied connection secret name is
+// a template that must be rendered before it can be used. No valid secret name
+// contains the opening template delimiter.
+func IsSecretNameTemplate(name string) bool {
+	return strings.Contains(name, "{{")
+}
+
+// RenderSecretName renders the supplied connection secret name template with
+// the SecretNameTemplateData of the supplied owner of the supplied kind. It
+// returns an error if the template is invalid, refers to a field that is not
+// part of SecretNameTemplateData, or renders an empty name.
+func RenderSecretName(tmpl string, o metav1.Object, gvk schema.GroupVersionKind) (string, error) {
+	t, err := parseSecretNameTemplate(tmpl)
+	if err != nil {
+		return "", err
+	}
+
+	d := SecretNameTemplateData{
+		Name:           o.GetName(),
+		Namespace:      o.GetNamespace(),
+		Kind:           strings.ToLower(gvk.Kind),
+		ClaimName:      o.GetLabels()[meta.LabelKeyClaimName],
+		ClaimNamespace: o.GetLabels()[meta.LabelKeyClaimNamespace],
+	}
+	b := &strings.Builder{}
+	if err := t.Execute(b, d); err != nil {
+		return "", errors.Wrap(err, errRenderSecretNameTemplate)
+	}
+
+	if b.Len() == 0 {
+		return "", errors.New(errEmptySecretName)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connection
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestRenderSecretName(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "database.example.org", Version: "v1", Kind: "PostgreSQLInstance"}
+	o := &metav1.ObjectMeta{
+		Name:      "cool-xr-8fd2k",
+		Namespace: "cool-ns",
+		Labels: map[string]string{
+			meta.LabelKeyClaimName:      "cool-claim",
+			meta.LabelKeyClaimNamespace: "cool-claim-ns",
+		},
+	}
+
+	type args struct {
+		tmpl string
+		o    metav1.Object
+	}
+	type want struct {
+		name string
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Claim": {
+			reason: "A template should be able to render the claim name and namespace and the owner's kind.",
+			args: args{
+				tmpl: "{{ .ClaimNamespace }}-{{ .ClaimName }}-{{ .Kind }}",
+				o:    o,
+			},
+			want: want{
+				name: "cool-claim-ns-cool-claim-postgresqlinstance",
+			},
+		},
+		"Owner": {
+			reason: "A template should be able to render the owner's name and namespace.",
+			args: args{
+				tmpl: "{{ .Namespace }}-{{ .Name }}",
+				o:    o,
+			},
+			want: want{
+				name: "cool-ns-cool-xr-8fd2k",
+			},
+		},
+		"InvalidTemplate": {
+			reason: "An invalid template should return an error.",
+			args: args{
+				tmpl: "{{ .Name ",
+				o:    o,
+			},
+			want: want{
+				err: errors.Wrap(errors.New(`template: secret-name:1: unclosed action`), errParseSecretNameTemplate),
+			},
+		},
+		"Labels": {
+			reason: "A template should not be able to render the owner's labels, which may change after the secret is published.",
+			args: args{
+				tmpl: "{{ .Labels.team }}",
+				o:    o,
+			},
+			want: want{
+				err: errors.Wrap(errors.New(`template: secret-name:1:10: executing "secret-name" at <.Labels.team>: can't evaluate field Labels in type connection.SecretNameTemplateData`), errRenderSecretNameTemplate),
+			},
+		},
+		"Empty": {
+			reason: "A template that renders an empty name should return an error.",
+			args: args{
+				tmpl: "{{ .ClaimName }}",
+				o:    &metav1.ObjectMeta{Name: "unclaimed"},
+			},
+			want: want{
+				err: errors.New(errEmptySecretName),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := RenderSecretName(tc.args.tmpl, tc.args.o, gvk)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nRenderSecretName(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.name, got); diff != "" {
+				t.Errorf("\n%s\nRenderSecretName(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestParseSecretNameTemplate(t *testing.T) {
+	tmpl := "{{ .Name }}-parsed-once"
+
+	first, err := parseSecretNameTemplate(tmpl)
+	if err != nil {
+		t.Fatalf("parseSecretNameTemplate(...): %s", err)
+	}
+	second, err := parseSecretNameTemplate(tmpl)
+	if err != nil {
+		t.Fatalf("parseSecretNameTemplate(...): %s", err)
+	}
+	if first != second {
+		t.Errorf("parseSecretNameTemplate(...): want the same template to be parsed only once")
+	}
+}
@@ -24,9 +24,10 @@ import (
 
 // ExternalSecretStorePluginServiceClient is a fake ExternalSecretStorePluginServiceClient.
 type ExternalSecretStorePluginServiceClient struct {
-	GetSecretFn   func(context.Context, *ess.GetSecretRequest, ...grpc.CallOption) (*ess.GetSecretResponse, error)
-	ApplySecretFn func(context.Context, *ess.ApplySecretRequest, ...grpc.CallOption) (*ess.ApplySecretResponse, error)
-	DeleteKeysFn  func(context.Context, *ess.DeleteKeysRequest, ...grpc.CallOption) (*ess.DeleteKeysResponse, error)
+	GetSecretFn    func(context.Context, *ess.GetSecretRequest, ...grpc.CallOption) (*ess.GetSecretResponse, error)
+	ApplySecretFn  func(context.Context, *ess.ApplySecretRequest, ...grpc.CallOption) (*ess.ApplySecretResponse, error)
+	DeleteKeysFn   func(context.Context, *ess.DeleteKeysRequest, ...grpc.CallOption) (*ess.DeleteKeysResponse, error)
+	WatchSecretsFn func(context.Context, *ess.WatchSecretsRequest, ...grpc.CallOption) (ess.ExternalSecretStorePluginService_WatchSecretsClient, error)
 	*ess.UnimplementedExternalSecretStorePluginServiceServer
 }
 
@@ -44,3 +45,28 @@ func (e *ExternalSecretStorePluginServiceClient) ApplySecret(ctx context.Context
 func (e *ExternalSecretStorePluginServiceClient) DeleteKeys(ctx context.Context, req *ess.DeleteKeysRequest, _ ...grpc.CallOption) (*ess.DeleteKeysResponse, error) {
 	return e.DeleteKeysFn(ctx, req)
 }
+
+// WatchSecrets watches secrets.
+func (e *ExternalSecretStorePluginServiceClient) WatchSecrets(ctx context.Context, req *ess.WatchSecretsRequest, _ ...grpc.CallOption) (ess.ExternalSecretStorePluginService_WatchSecretsClient, error) {
+	return e.WatchSecretsFn(ctx, req)
+}
+
+// WatchSecretsClient is a fake ExternalSecretStorePluginService_WatchSecretsClient
+// that streams the supplied responses, then the supplied error.
+type WatchSecretsClient struct {
+	grpc.ClientStream
+
+	Responses []*ess.WatchSecretsResponse
+	Err       error
+}
+
+// Recv returns the next response, or the error once all responses have been
+// returned.
+func (c *WatchSecretsClient) Recv() (*ess.WatchSecretsResponse, error) {
+	if len(c.Responses) == 0 {
+		return nil, c.Err
+	}
+	r := c.Responses[0]
+	c.Responses = c.Responses[1:]
+	return r, nil
+}
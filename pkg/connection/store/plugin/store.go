@@ -20,10 +20,13 @@ package plugin
 import (
 	"context"
 	"crypto/tls"
+	"io"
 	"path/filepath"
+	"strings"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
@@ -37,6 +40,7 @@ const (
 	errGet    = "cannot get secret"
 	errApply  = "cannot apply secret"
 	errDelete = "cannot delete secret"
+	errWatch  = "cannot watch secrets"
 
 	errFmtCannotDial = "cannot dial to the endpoint: %s"
 )
@@ -74,17 +78,7 @@ func (ss *SecretStore) ReadKeyValues(ctx context.Context, n store.ScopedName, s
 	}
 
 	s.ScopedName = n
-	s.Data = make(map[string][]byte, len(resp.Secret.Data))
-	for d := range resp.Secret.Data {
-		s.Data[d] = resp.Secret.Data[d]
-	}
-	if resp.Secret != nil && len(resp.Secret.Metadata) != 0 {
-		s.Metadata = new(v1.ConnectionSecretMetadata)
-		s.Metadata.Labels = make(map[string]string, len(resp.Secret.Metadata))
-		for k, v := range resp.Secret.Metadata {
-			s.Metadata.Labels[k] = v
-		}
-	}
+	copyFromProto(resp.Secret, s)
 
 	return nil
 }
@@ -120,6 +114,58 @@ func (ss *SecretStore) DeleteKeyValues(ctx context.Context, s *store.Secret, _ .
 	return errors.Wrap(err, errDelete)
 }
 
+// WatchSecrets calls the supplied function each time a secret owned by one of
+// the supplied UIDs is changed or deleted, until the supplied context is done
+// or the plugin ends the watch. It blocks while watching, and returns nil if
+// the watch ended because the context is done or the plugin ended it.
+func (ss *SecretStore) WatchSecrets(ctx context.Context, owners []types.UID, fn func(store.SecretEvent)) error {
+	uids := make([]string, len(owners))
+	for i := range owners {
+		uids[i] = string(owners[i])
+	}
+
+	stream, err := ss.client.WatchSecrets(ctx, &essproto.WatchSecretsRequest{Config: ss.getConfigReference(), OwnerUids: uids})
+	if err != nil {
+		return errors.Wrap(err, errWatch)
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) || ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, errWatch)
+		}
+
+		e := store.SecretEvent{Type: store.SecretChanged, Secret: &store.Secret{}}
+		if resp.Type == essproto.SecretEventType_SECRET_EVENT_TYPE_DELETED {
+			e.Type = store.SecretDeleted
+		}
+		if resp.Secret != nil {
+			e.Secret.ScopedName = ss.parseScopedName(resp.Secret.ScopedName)
+		}
+		copyFromProto(resp.Secret, e.Secret)
+		fn(e)
+	}
+}
+
+// copyFromProto copies the data and metadata of the supplied protobuf secret
+// to the supplied secret.
+func copyFromProto(from *essproto.Secret, to *store.Secret) {
+	to.Data = make(map[string][]byte, len(from.GetData()))
+	for k, v := range from.GetData() {
+		to.Data[k] = v
+	}
+	if len(from.GetMetadata()) != 0 {
+		to.Metadata = new(v1.ConnectionSecretMetadata)
+		to.Metadata.Labels = make(map[string]string, len(from.GetMetadata()))
+		for k, v := range from.GetMetadata() {
+			to.Metadata.Labels[k] = v
+		}
+	}
+}
+
 func (ss *SecretStore) getConfigReference() *essproto.ConfigReference {
 	return &essproto.ConfigReference{
 		ApiVersion: ss.config.APIVersion,
@@ -134,3 +180,9 @@ func (ss *SecretStore) getScopedName(n store.ScopedName) string {
 	}
 	return filepath.Join(n.Scope, n.Name)
 }
+
+// parseScopedName is the inverse of getScopedName.
+func (ss *SecretStore) parseScopedName(sn string) store.ScopedName {
+	scope, name := filepath.Split(sn)
+	return store.ScopedName{Name: name, Scope: strings.TrimSuffix(scope, "/")}
+}
@@ -18,11 +18,13 @@ package plugin
 
 import (
 	"context"
+	"io"
 	"path/filepath"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"google.golang.org/grpc"
+	"k8s.io/apimachinery/pkg/types"
 
 	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	ess "github.com/crossplane/crossplane-runtime/apis/proto/v1alpha1"
@@ -278,3 +280,114 @@ func TestDeleteKeyValues(t *testing.T) {
 		})
 	}
 }
+
+func TestWatchSecrets(t *testing.T) {
+	owners := []types.UID{"cool-uid"}
+
+	type args struct {
+		client ess.ExternalSecretStorePluginServiceClient
+	}
+	type want struct {
+		events []store.SecretEvent
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"ErrorWhileWatching": {
+			reason: "Should return a proper error if the watch cannot be started",
+			args: args{
+				client: &fake.ExternalSecretStorePluginServiceClient{
+					WatchSecretsFn: func(_ context.Context, _ *ess.WatchSecretsRequest, _ ...grpc.CallOption) (ess.ExternalSecretStorePluginService_WatchSecretsClient, error) {
+						return nil, errBoom
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errWatch),
+			},
+		},
+		"ErrorWhileReceiving": {
+			reason: "Should return a proper error if the stream fails",
+			args: args{
+				client: &fake.ExternalSecretStorePluginServiceClient{
+					WatchSecretsFn: func(_ context.Context, _ *ess.WatchSecretsRequest, _ ...grpc.CallOption) (ess.ExternalSecretStorePluginService_WatchSecretsClient, error) {
+						return &fake.WatchSecretsClient{Err: errBoom}, nil
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errWatch),
+			},
+		},
+		"SuccessfulWatch": {
+			reason: "Should stream events for the watched owners until the stream ends",
+			args: args{
+				client: &fake.ExternalSecretStorePluginServiceClient{
+					WatchSecretsFn: func(_ context.Context, req *ess.WatchSecretsRequest, _ ...grpc.CallOption) (ess.ExternalSecretStorePluginService_WatchSecretsClient, error) {
+						if diff := cmp.Diff([]string{"cool-uid"}, req.GetOwnerUids()); diff != "" {
+							return nil, errors.Errorf("unexpected owners: %s", diff)
+						}
+						return &fake.WatchSecretsClient{
+							Responses: []*ess.WatchSecretsResponse{
+								{
+									Type: ess.SecretEventType_SECRET_EVENT_TYPE_CHANGED,
+									Secret: &ess.Secret{
+										ScopedName: filepath.Join(parentPath, secretName),
+										Metadata:   map[string]string{v1.LabelKeyOwnerUID: "cool-uid"},
+										Data:       map[string][]byte{"password": []byte("rotated")},
+									},
+								},
+								{
+									Type:   ess.SecretEventType_SECRET_EVENT_TYPE_DELETED,
+									Secret: &ess.Secret{ScopedName: secretName},
+								},
+							},
+							Err: io.EOF,
+						}, nil
+					},
+				},
+			},
+			want: want{
+				events: []store.SecretEvent{
+					{
+						Type: store.SecretChanged,
+						Secret: &store.Secret{
+							ScopedName: store.ScopedName{Name: secretName, Scope: parentPath},
+							Metadata:   &v1.ConnectionSecretMetadata{Labels: map[string]string{v1.LabelKeyOwnerUID: "cool-uid"}},
+							Data:       store.KeyValues{"password": []byte("rotated")},
+						},
+					},
+					{
+						Type: store.SecretDeleted,
+						Secret: &store.Secret{
+							ScopedName: store.ScopedName{Name: secretName},
+							Data:       store.KeyValues{},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ss := &SecretStore{
+				client: tc.args.client,
+				config: &v1.Config{APIVersion: "v1alpha1", Kind: "VaultConfig", Name: "ess-test"},
+			}
+
+			var got []store.SecretEvent
+			err := ss.WatchSecrets(context.Background(), owners, func(e store.SecretEvent) { got = append(got, e) })
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nss.WatchSecrets(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.events, got); diff != "" {
+				t.Errorf("\n%s\nss.WatchSecrets(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,320 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sops
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"time"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// Error strings.
+const (
+	errMarshalFile      = "cannot marshal SOPS file"
+	errUnmarshalFile    = "cannot unmarshal SOPS file"
+	errNotSOPSFile      = "file has no SOPS metadata"
+	errNoVaultKey       = "file has no Vault transit master key"
+	errGenerateDataKey  = "cannot generate data key"
+	errEncryptDataKey   = "cannot encrypt data key"
+	errDecryptDataKey   = "cannot decrypt data key"
+	errEncryptValue     = "cannot encrypt value"
+	errDecryptValue     = "cannot decrypt value"
+	errMalformedValue   = "malformed encrypted value"
+	errMACMismatch      = "file MAC does not match its contents"
+	errFmtUnsupportedTy = "unsupported encrypted value type %q"
+)
+
+const (
+	// sopsVersion is the SOPS version whose file format we write.
+	sopsVersion = "3.7.3"
+
+	// encryptedRegex matches the keys whose values SOPS encrypts. It leaves
+	// the rest of the Secret manifest readable.
+	encryptedRegex = "^(data|stringData)$"
+
+	dataKeySize = 32
+	nonceSize   = 32
+)
+
+var (
+	// encryptedValue matches a value encrypted by SOPS.
+	encryptedValue = regexp.MustCompile(`^ENC\[AES256_GCM,data:(.+),iv:(.+),tag:(.+),type:(.+)\]`)
+
+	encryptedKey = regexp.MustCompile(encryptedRegex)
+)
+
+// A KeyCipher encrypts and decrypts the data key of SOPS files.
+type KeyCipher interface {
+	Encrypt(ctx context.Context, plaintext []byte) ([]byte, error)
+	Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error)
+}
+
+// A VaultTransitKey identifies the Vault transit key a KeyCipher uses. It's
+// recorded in each file, so that SOPS can decrypt it.
+type VaultTransitKey struct {
+	Address    string
+	EnginePath string
+	KeyName    string
+}
+
+// vaultKey is a Vault transit master key of a SOPS file.
+type vaultKey struct {
+	VaultAddress string `json:"vault_address"`
+	EnginePath   string `json:"engine_path"`
+	KeyName      string `json:"key_name"`
+	CreatedAt    string `json:"created_at"`
+	Enc          string `json:"enc"`
+}
+
+// metadata is the metadata of a SOPS file, i.e. its top level "sops" key.
+type metadata struct {
+	HCVault        []vaultKey `json:"hc_vault,omitempty"`
+	LastModified   string     `json:"lastmodified"`
+	MAC            string     `json:"mac"`
+	EncryptedRegex string     `json:"encrypted_regex,omitempty"`
+	Version        string     `json:"version"`
+}
+
+// encrypt returns the supplied document as a SOPS encrypted YAML file. The
+// document must be JSON compatible.
+func encrypt(ctx context.Context, kc KeyCipher, key VaultTransitKey, now time.Time, doc map[string]any) ([]byte, error) {
+	dk := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dk); err != nil {
+		return nil, errors.Wrap(err, errGenerateDataKey)
+	}
+	edk, err := kc.Encrypt(ctx, dk)
+	if err != nil {
+		return nil, errors.Wrap(err, errEncryptDataKey)
+	}
+
+	lastModified := now.UTC().Format(time.RFC3339)
+	mac, err := encryptValue(dk, computeMAC(doc), lastModified)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]any, len(doc)+1)
+	for k, v := range doc {
+		if !isEncrypted(k) {
+			out[k] = v
+			continue
+		}
+		if out[k], err = transform(v, []string{k}, func(v string, path []string) (string, error) {
+			return encryptValue(dk, v, additionalData(path))
+		}); err != nil {
+			return nil, err
+		}
+	}
+	out["sops"] = metadata{
+		HCVault: []vaultKey{{
+			VaultAddress: key.Address,
+			EnginePath:   key.EnginePath,
+			KeyName:      key.KeyName,
+			CreatedAt:    lastModified,
+			Enc:          string(edk),
+		}},
+		LastModified:   lastModified,
+		MAC:            mac,
+		EncryptedRegex: encryptedRegex,
+		Version:        sopsVersion,
+	}
+
+	b, err := yaml.Marshal(out)
+	return b, errors.Wrap(err, errMarshalFile)
+}
+
+// decrypt returns the document of the supplied SOPS encrypted YAML file.
+func decrypt(ctx context.Context, kc KeyCipher, file []byte) (map[string]any, error) {
+	doc := map[string]any{}
+	if err := yaml.Unmarshal(file, &doc); err != nil {
+		return nil, errors.Wrap(err, errUnmarshalFile)
+	}
+	raw, ok := doc["sops"]
+	if !ok {
+		return nil, errors.New(errNotSOPSFile)
+	}
+	delete(doc, "sops")
+
+	// Round trip the metadata through JSON to convert it to its type.
+	md := metadata{}
+	j, err := json.Marshal(raw)
+	if err != nil {
+		return nil, errors.Wrap(err, errUnmarshalFile)
+	}
+	if err := json.Unmarshal(j, &md); err != nil {
+		return nil, errors.Wrap(err, errUnmarshalFile)
+	}
+	if len(md.HCVault) == 0 {
+		return nil, errors.New(errNoVaultKey)
+	}
+	dk, err := kc.Decrypt(ctx, []byte(md.HCVault[0].Enc))
+	if err != nil {
+		return nil, errors.Wrap(err, errDecryptDataKey)
+	}
+
+	for k, v := range doc {
+		if !isEncrypted(k) {
+			continue
+		}
+		if doc[k], err = transform(v, []string{k}, func(v string, path []string) (string, error) {
+			return decryptValue(dk, v, additionalData(path))
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	mac, err := decryptValue(dk, md.MAC, md.LastModified)
+	if err != nil {
+		return nil, err
+	}
+	if mac != computeMAC(doc) {
+		return nil, errors.New(errMACMismatch)
+	}
+	return doc, nil
+}
+
+func isEncrypted(key string) bool {
+	return encryptedKey.MatchString(key)
+}
+
+// additionalData returns the additional authenticated data SOPS uses to
+// encrypt the value at the supplied path, which binds the value to its key.
+func additionalData(path []string) string {
+	ad := ""
+	for _, p := range path {
+		ad += p + ":"
+	}
+	return ad
+}
+
+// transform calls the supplied function with each string leaf of the supplied
+// tree and its path, and returns a tree of the results.
+func transform(v any, path []string, fn func(v string, path []string) (string, error)) (any, error) {
+	switch t := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, v := range t {
+			var err error
+			if out[k], err = transform(v, append(path[:len(path):len(path)], k), fn); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	case string:
+		return fn(t, path)
+	}
+	return v, nil
+}
+
+// computeMAC returns the message authentication code SOPS computes over the
+// plaintext of a file, i.e. the hex encoded SHA-512 digest of all of its leaf
+// values in document order. We always write keys in lexical order.
+func computeMAC(doc map[string]any) string {
+	h := sha512.New()
+	var walk func(v any)
+	walk = func(v any) {
+		switch t := v.(type) {
+		case map[string]any:
+			keys := make([]string, 0, len(t))
+			for k := range t {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				walk(t[k])
+			}
+		case []any:
+			for _, e := range t {
+				walk(e)
+			}
+		case string:
+			_, _ = h.Write([]byte(t))
+		case nil:
+		default:
+			_, _ = h.Write([]byte(fmt.Sprint(t)))
+		}
+	}
+	walk(doc)
+	return fmt.Sprintf("%X", h.Sum(nil))
+}
+
+func newAEAD(dk []byte) (cipher.AEAD, error) {
+	b, err := aes.NewCipher(dk)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCMWithNonceSize(b, nonceSize)
+}
+
+// encryptValue encrypts the supplied string value the way SOPS does.
+func encryptValue(dk []byte, v, ad string) (string, error) {
+	a, err := newAEAD(dk)
+	if err != nil {
+		return "", errors.Wrap(err, errEncryptValue)
+	}
+	iv := make([]byte, nonceSize)
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return "", errors.Wrap(err, errEncryptValue)
+	}
+	ct := a.Seal(nil, iv, []byte(v), []byte(ad))
+	data, tag := ct[:len(ct)-a.Overhead()], ct[len(ct)-a.Overhead():]
+	enc := base64.StdEncoding.EncodeToString
+	return fmt.Sprintf("ENC[AES256_GCM,data:%s,iv:%s,tag:%s,type:str]", enc(data), enc(iv), enc(tag)), nil
+}
+
+// decryptValue decrypts the supplied SOPS encrypted string value.
+func decryptValue(dk []byte, v, ad string) (string, error) {
+	m := encryptedValue.FindStringSubmatch(v)
+	if m == nil {
+		return "", errors.New(errMalformedValue)
+	}
+	if m[4] != "str" {
+		return "", errors.Errorf(errFmtUnsupportedTy, m[4])
+	}
+	var parts [3][]byte
+	for i := range parts {
+		b, err := base64.StdEncoding.DecodeString(m[i+1])
+		if err != nil {
+			return "", errors.Wrap(err, errMalformedValue)
+		}
+		parts[i] = b
+	}
+	data, iv, tag := parts[0], parts[1], parts[2]
+
+	a, err := newAEAD(dk)
+	if err != nil {
+		return "", errors.Wrap(err, errDecryptValue)
+	}
+	if len(iv) != nonceSize {
+		return "", errors.New(errMalformedValue)
+	}
+	pt, err := a.Open(nil, iv, append(data, tag...), []byte(ad))
+	return string(pt), errors.Wrap(err, errDecryptValue)
+}
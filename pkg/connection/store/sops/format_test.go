@@ -0,0 +1,159 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sops
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var errBoom = errors.New("boom")
+
+// fakeCipher is a KeyCipher that base64 encodes its input, like the Vault
+// transit engine returns text ciphertext.
+type fakeCipher struct {
+	err error
+}
+
+func (c fakeCipher) Encrypt(_ context.Context, plaintext []byte) ([]byte, error) {
+	return []byte("fake:" + base64.StdEncoding.EncodeToString(plaintext)), c.err
+}
+
+func (c fakeCipher) Decrypt(_ context.Context, ciphertext []byte) ([]byte, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return base64.StdEncoding.DecodeString(strings.TrimPrefix(string(ciphertext), "fake:"))
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	doc := map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]any{"name": "conn", "namespace": "default"},
+		"data":       map[string]any{"password": "czNjcjN0", "username": "YWRtaW4="},
+	}
+
+	type want struct {
+		doc map[string]any
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		tamper func(file []byte) []byte
+		want   want
+	}{
+		"RoundTrip": {
+			reason: "We should decrypt what we encrypted.",
+			tamper: func(file []byte) []byte { return file },
+			want:   want{doc: doc},
+		},
+		"TamperedPlaintext": {
+			reason: "We should detect changes to values that are not encrypted.",
+			tamper: func(file []byte) []byte {
+				return []byte(strings.Replace(string(file), "name: conn", "name: evil", 1))
+			},
+			want: want{err: errors.New(errMACMismatch)},
+		},
+		"SwappedValues": {
+			reason: "We should detect encrypted values that were moved to another key.",
+			tamper: func(file []byte) []byte {
+				f := map[string]any{}
+				_ = yaml.Unmarshal(file, &f)
+				data := f["data"].(map[string]any)
+				data["password"], data["username"] = data["username"], data["password"]
+				b, _ := yaml.Marshal(f)
+				return b
+			},
+			want: want{err: errors.Wrap(errors.New("cipher: message authentication failed"), errDecryptValue)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			file, err := encrypt(context.Background(), fakeCipher{}, VaultTransitKey{Address: "https://vault", EnginePath: "transit", KeyName: "k"}, now, doc)
+			if err != nil {
+				t.Fatalf("encrypt(...): %s", err)
+			}
+			if strings.Contains(string(file), "czNjcjN0") {
+				t.Errorf("\n%s\nencrypt(...): file contains plaintext value:\n%s", tc.reason, file)
+			}
+
+			got, err := decrypt(context.Background(), fakeCipher{}, tc.tamper(file))
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ndecrypt(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.doc, got); diff != "" {
+				t.Errorf("\n%s\ndecrypt(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDecrypt(t *testing.T) {
+	type want struct {
+		doc map[string]any
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		kc     KeyCipher
+		file   string
+		want   want
+	}{
+		"NotSOPSFile": {
+			reason: "We should return an error if the file has no SOPS metadata.",
+			kc:     fakeCipher{},
+			file:   "kind: Secret",
+			want:   want{err: errors.New(errNotSOPSFile)},
+		},
+		"NoVaultKey": {
+			reason: "We should return an error if the file has no Vault transit master key.",
+			kc:     fakeCipher{},
+			file:   "kind: Secret\nsops:\n  version: 3.7.3",
+			want:   want{err: errors.New(errNoVaultKey)},
+		},
+		"DecryptDataKeyError": {
+			reason: "We should return any error encountered decrypting the data key.",
+			kc:     fakeCipher{err: errBoom},
+			file:   "kind: Secret\nsops:\n  hc_vault:\n  - enc: vault:v1:abc",
+			want:   want{err: errors.Wrap(errBoom, errDecryptDataKey)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := decrypt(context.Background(), tc.kc, []byte(tc.file))
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ndecrypt(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.doc, got); diff != "" {
+				t.Errorf("\n%s\ndecrypt(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
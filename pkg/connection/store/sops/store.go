@@ -0,0 +1,302 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sops implements a secret store that writes connection secrets to
+// SOPS encrypted files.
+package sops
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/spf13/afero"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/connection/store"
+	"github.com/crossplane/crossplane-runtime/pkg/connection/store/vault"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errNoConfig        = "no SOPS config provided"
+	errNewVaultClient  = "cannot create Vault client"
+	errReadFile        = "cannot read secret file"
+	errWriteFile       = "cannot write secret file"
+	errDeleteFile      = "cannot delete secret file"
+	errDecryptFile     = "cannot decrypt secret file"
+	errEncryptFile     = "cannot encrypt secret file"
+	errConvertDocument = "cannot convert secret file to a Secret"
+	errFmtInvalidName  = "invalid secret name or scope %q"
+)
+
+const (
+	defaultTransitMountPath = "transit"
+
+	dirPerm  = 0o700
+	filePerm = 0o600
+)
+
+// SecretStore is a SOPS Secret Store. It writes each secret as a SOPS
+// encrypted Kubernetes Secret manifest.
+type SecretStore struct {
+	fs     afero.Fs
+	root   string
+	cipher KeyCipher
+	key    VaultTransitKey
+	now    func() time.Time
+
+	defaultScope string
+}
+
+// NewSecretStore returns a new SOPS SecretStore.
+func NewSecretStore(ctx context.Context, kube client.Client, _ *tls.Config, cfg v1.SecretStoreConfig) (*SecretStore, error) {
+	if cfg.SOPS == nil {
+		return nil, errors.New(errNoConfig)
+	}
+	vt := cfg.SOPS.VaultTransit
+	c, err := vault.NewClient(ctx, kube, vault.ClientConfig{
+		Server:    vt.Server,
+		Namespace: vt.Namespace,
+		CABundle:  vt.CABundle,
+		Auth:      vt.Auth,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errNewVaultClient)
+	}
+
+	mount := vt.MountPath
+	if mount == "" {
+		mount = defaultTransitMountPath
+	}
+
+	return &SecretStore{
+		fs:           afero.NewOsFs(),
+		root:         cfg.SOPS.Path,
+		cipher:       vault.NewTransitCipher(c.Logical(), vt.KeyName, vault.WithTransitMountPath(mount)),
+		key:          VaultTransitKey{Address: vt.Server, EnginePath: mount, KeyName: vt.KeyName},
+		now:          time.Now,
+		defaultScope: cfg.DefaultScope,
+	}, nil
+}
+
+// ReadKeyValues reads and returns key value pairs for a given secret. A secret
+// that doesn't exist is read as an empty secret.
+func (ss *SecretStore) ReadKeyValues(ctx context.Context, n store.ScopedName, s *store.Secret) error {
+	ks, err := ss.read(ctx, n)
+	if err != nil {
+		return err
+	}
+	s.ScopedName = n
+	if ks == nil {
+		return nil
+	}
+	*s = *fromSecret(n, ks)
+	return nil
+}
+
+// WriteKeyValues writes key value pairs to a given secret.
+func (ss *SecretStore) WriteKeyValues(ctx context.Context, s *store.Secret, wo ...store.WriteOption) (bool, error) {
+	ks, err := ss.read(ctx, s.ScopedName)
+	if err != nil {
+		return false, err
+	}
+
+	desired := &store.Secret{ScopedName: s.ScopedName, Metadata: s.Metadata.DeepCopy(), Data: s.Data}
+	if ks != nil {
+		current := fromSecret(s.ScopedName, ks)
+		for _, o := range wo {
+			if err := o(ctx, current, desired); err != nil {
+				return false, err
+			}
+		}
+		if unchanged(current, desired) {
+			return false, nil
+		}
+	}
+
+	return true, ss.write(ctx, desired)
+}
+
+// DeleteKeyValues delete key value pairs from a given secret. If no key values
+// are specified, the whole secret is deleted. If key values are specified,
+// those are deleted and the secret is deleted only if no key values remain.
+func (ss *SecretStore) DeleteKeyValues(ctx context.Context, s *store.Secret, do ...store.DeleteOption) error {
+	ks, err := ss.read(ctx, s.ScopedName)
+	if err != nil || ks == nil {
+		return err
+	}
+
+	current := fromSecret(s.ScopedName, ks)
+	for _, o := range do {
+		if err := o(ctx, current); err != nil {
+			return err
+		}
+	}
+
+	for k := range s.Data {
+		delete(current.Data, k)
+	}
+	if len(s.Data) == 0 || len(current.Data) == 0 {
+		p, err := ss.path(s.ScopedName)
+		if err != nil {
+			return err
+		}
+		return errors.Wrap(ss.fs.Remove(p), errDeleteFile)
+	}
+	return ss.write(ctx, current)
+}
+
+// path returns the path of the file of the supplied secret.
+func (ss *SecretStore) path(n store.ScopedName) (string, error) {
+	scope := n.Scope
+	if scope == "" {
+		scope = ss.defaultScope
+	}
+	for _, s := range []string{n.Name, scope} {
+		if s == "" || s == "." || s == ".." || strings.ContainsRune(s, filepath.Separator) {
+			return "", errors.Errorf(errFmtInvalidName, s)
+		}
+	}
+	return filepath.Join(ss.root, scope, n.Name+".yaml"), nil
+}
+
+// read returns the Secret stored in the file of the supplied secret, or nil if
+// there is no such file.
+func (ss *SecretStore) read(ctx context.Context, n store.ScopedName) (*corev1.Secret, error) {
+	p, err := ss.path(n)
+	if err != nil {
+		return nil, err
+	}
+	b, err := afero.ReadFile(ss.fs, p)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errReadFile)
+	}
+
+	doc, err := decrypt(ctx, ss.cipher, b)
+	if err != nil {
+		return nil, errors.Wrap(err, errDecryptFile)
+	}
+	j, err := json.Marshal(doc)
+	if err != nil {
+		return nil, errors.Wrap(err, errConvertDocument)
+	}
+	ks := &corev1.Secret{}
+	return ks, errors.Wrap(json.Unmarshal(j, ks), errConvertDocument)
+}
+
+// write writes the supplied secret to its file. The file is replaced
+// atomically, so that readers never observe a partially written file.
+func (ss *SecretStore) write(ctx context.Context, s *store.Secret) error {
+	p, err := ss.path(s.ScopedName)
+	if err != nil {
+		return err
+	}
+	b, err := encrypt(ctx, ss.cipher, ss.key, ss.now(), toDocument(p, s))
+	if err != nil {
+		return errors.Wrap(err, errEncryptFile)
+	}
+	if err := ss.fs.MkdirAll(filepath.Dir(p), dirPerm); err != nil {
+		return errors.Wrap(err, errWriteFile)
+	}
+	tmp := p + ".tmp"
+	if err := afero.WriteFile(ss.fs, tmp, b, filePerm); err != nil {
+		return errors.Wrap(err, errWriteFile)
+	}
+	return errors.Wrap(ss.fs.Rename(tmp, p), errWriteFile)
+}
+
+// toDocument returns the Kubernetes Secret manifest of the supplied secret,
+// which is to be written to the supplied path.
+func toDocument(path string, s *store.Secret) map[string]any {
+	md := map[string]any{
+		"name":      s.Name,
+		"namespace": filepath.Base(filepath.Dir(path)),
+	}
+	t := string(resource.SecretTypeConnection)
+	if s.Metadata != nil {
+		if len(s.Metadata.Labels) > 0 {
+			md["labels"] = toAny(s.Metadata.Labels)
+		}
+		if len(s.Metadata.Annotations) > 0 {
+			md["annotations"] = toAny(s.Metadata.Annotations)
+		}
+		if s.Metadata.Type != nil {
+			t = string(*s.Metadata.Type)
+		}
+	}
+	doc := map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   md,
+		"type":       t,
+	}
+	if len(s.Data) > 0 {
+		data := make(map[string]any, len(s.Data))
+		for k, v := range s.Data {
+			data[k] = base64.StdEncoding.EncodeToString(v)
+		}
+		doc["data"] = data
+	}
+	return doc
+}
+
+func toAny(in map[string]string) map[string]any {
+	out := make(map[string]any, len(in))
+	for k, v := range in {
+		out[k] = v
+	}
+	return out
+}
+
+func fromSecret(n store.ScopedName, ks *corev1.Secret) *store.Secret {
+	t := ks.Type
+	return &store.Secret{
+		ScopedName: n,
+		Metadata: &v1.ConnectionSecretMetadata{
+			Labels:      ks.Labels,
+			Annotations: ks.Annotations,
+			Type:        &t,
+		},
+		Data: ks.Data,
+	}
+}
+
+func unchanged(current, desired *store.Secret) bool {
+	annotations := func(s *store.Secret) map[string]string {
+		if s.Metadata == nil {
+			return nil
+		}
+		return s.Metadata.Annotations
+	}
+	return cmp.Equal(current.Data, desired.Data, cmpopts.EquateEmpty()) &&
+		cmp.Equal(current.GetLabels(), desired.GetLabels(), cmpopts.EquateEmpty()) &&
+		cmp.Equal(annotations(current), annotations(desired), cmpopts.EquateEmpty())
+}
@@ -0,0 +1,388 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sops
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+	corev1 "k8s.io/api/core/v1"
+
+	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/connection/store"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+const (
+	root       = "/secrets"
+	secretName = "conn"
+	scope      = "crossplane-system"
+)
+
+func newStore(t *testing.T, existing *store.Secret) *SecretStore {
+	t.Helper()
+	ss := &SecretStore{
+		fs:           afero.NewMemMapFs(),
+		root:         root,
+		cipher:       fakeCipher{},
+		key:          VaultTransitKey{Address: "https://vault", EnginePath: "transit", KeyName: "k"},
+		now:          func() time.Time { return time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC) },
+		defaultScope: scope,
+	}
+	if existing != nil {
+		if err := ss.write(context.Background(), existing); err != nil {
+			t.Fatalf("ss.write(...): %s", err)
+		}
+	}
+	return ss
+}
+
+func TestSecretStoreReadKeyValues(t *testing.T) {
+	connType := resource.SecretTypeConnection
+
+	type want struct {
+		secret *store.Secret
+		err    error
+	}
+	cases := map[string]struct {
+		reason   string
+		existing *store.Secret
+		name     store.ScopedName
+		want     want
+	}{
+		"NotFound": {
+			reason: "We should read a secret that doesn't exist as an empty secret.",
+			name:   store.ScopedName{Name: secretName},
+			want: want{
+				secret: &store.Secret{ScopedName: store.ScopedName{Name: secretName}},
+			},
+		},
+		"InvalidName": {
+			reason: "We should not read secrets outside the store's path.",
+			name:   store.ScopedName{Name: secretName, Scope: ".."},
+			want: want{
+				secret: &store.Secret{},
+				err:    errors.Errorf(errFmtInvalidName, ".."),
+			},
+		},
+		"Success": {
+			reason: "We should read the key values and metadata of an existing secret.",
+			existing: &store.Secret{
+				ScopedName: store.ScopedName{Name: secretName},
+				Metadata: &v1.ConnectionSecretMetadata{
+					Labels: map[string]string{v1.LabelKeyOwnerUID: "uid"},
+				},
+				Data: store.KeyValues{"password": []byte("s3cr3t")},
+			},
+			name: store.ScopedName{Name: secretName},
+			want: want{
+				secret: &store.Secret{
+					ScopedName: store.ScopedName{Name: secretName},
+					Metadata: &v1.ConnectionSecretMetadata{
+						Labels: map[string]string{v1.LabelKeyOwnerUID: "uid"},
+						Type:   &connType,
+					},
+					Data: store.KeyValues{"password": []byte("s3cr3t")},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ss := newStore(t, tc.existing)
+			got := &store.Secret{}
+			err := ss.ReadKeyValues(context.Background(), tc.name, got)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nss.ReadKeyValues(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.secret, got); diff != "" {
+				t.Errorf("\n%s\nss.ReadKeyValues(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSecretStoreWriteKeyValues(t *testing.T) {
+	type args struct {
+		secret *store.Secret
+		wo     []store.WriteOption
+	}
+	type want struct {
+		changed bool
+		data    store.KeyValues
+		err     error
+	}
+	cases := map[string]struct {
+		reason   string
+		existing *store.Secret
+		args     args
+		want     want
+	}{
+		"Create": {
+			reason: "We should create a secret that doesn't exist.",
+			args: args{
+				secret: &store.Secret{
+					ScopedName: store.ScopedName{Name: secretName},
+					Data:       store.KeyValues{"password": []byte("s3cr3t")},
+				},
+			},
+			want: want{
+				changed: true,
+				data:    store.KeyValues{"password": []byte("s3cr3t")},
+			},
+		},
+		"WriteOptionError": {
+			reason: "We should return any error returned by a write option.",
+			existing: &store.Secret{
+				ScopedName: store.ScopedName{Name: secretName},
+				Data:       store.KeyValues{"password": []byte("s3cr3t")},
+			},
+			args: args{
+				secret: &store.Secret{
+					ScopedName: store.ScopedName{Name: secretName},
+					Data:       store.KeyValues{"password": []byte("n3w")},
+				},
+				wo: []store.WriteOption{func(_ context.Context, _, _ *store.Secret) error { return errBoom }},
+			},
+			want: want{
+				data: store.KeyValues{"password": []byte("s3cr3t")},
+				err:  errBoom,
+			},
+		},
+		"Unchanged": {
+			reason: "We should not rewrite a secret that would not change.",
+			existing: &store.Secret{
+				ScopedName: store.ScopedName{Name: secretName},
+				Data:       store.KeyValues{"password": []byte("s3cr3t")},
+			},
+			args: args{
+				secret: &store.Secret{
+					ScopedName: store.ScopedName{Name: secretName},
+					Data:       store.KeyValues{"password": []byte("s3cr3t")},
+				},
+			},
+			want: want{
+				data: store.KeyValues{"password": []byte("s3cr3t")},
+			},
+		},
+		"Update": {
+			reason: "We should update a secret whose key values changed.",
+			existing: &store.Secret{
+				ScopedName: store.ScopedName{Name: secretName},
+				Data:       store.KeyValues{"password": []byte("s3cr3t")},
+			},
+			args: args{
+				secret: &store.Secret{
+					ScopedName: store.ScopedName{Name: secretName},
+					Data:       store.KeyValues{"password": []byte("n3w")},
+				},
+			},
+			want: want{
+				changed: true,
+				data:    store.KeyValues{"password": []byte("n3w")},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ss := newStore(t, tc.existing)
+			changed, err := ss.WriteKeyValues(context.Background(), tc.args.secret, tc.args.wo...)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nss.WriteKeyValues(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.changed, changed); diff != "" {
+				t.Errorf("\n%s\nss.WriteKeyValues(...): -want changed, +got changed:\n%s", tc.reason, diff)
+			}
+			got := &store.Secret{}
+			if err := ss.ReadKeyValues(context.Background(), tc.args.secret.ScopedName, got); err != nil {
+				t.Fatalf("ss.ReadKeyValues(...): %s", err)
+			}
+			if diff := cmp.Diff(tc.want.data, got.Data); diff != "" {
+				t.Errorf("\n%s\nss.WriteKeyValues(...): -want data, +got data:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSecretStoreDeleteKeyValues(t *testing.T) {
+	type want struct {
+		data store.KeyValues
+		err  error
+	}
+	cases := map[string]struct {
+		reason   string
+		existing *store.Secret
+		secret   *store.Secret
+		do       []store.DeleteOption
+		want     want
+	}{
+		"NotFound": {
+			reason: "We should return no error if the secret doesn't exist.",
+			secret: &store.Secret{ScopedName: store.ScopedName{Name: secretName}},
+		},
+		"DeleteOptionError": {
+			reason: "We should return any error returned by a delete option.",
+			existing: &store.Secret{
+				ScopedName: store.ScopedName{Name: secretName},
+				Data:       store.KeyValues{"password": []byte("s3cr3t")},
+			},
+			secret: &store.Secret{ScopedName: store.ScopedName{Name: secretName}},
+			do:     []store.DeleteOption{func(_ context.Context, _ *store.Secret) error { return errBoom }},
+			want: want{
+				data: store.KeyValues{"password": []byte("s3cr3t")},
+				err:  errBoom,
+			},
+		},
+		"DeleteSomeKeys": {
+			reason: "We should only delete the supplied keys if others remain.",
+			existing: &store.Secret{
+				ScopedName: store.ScopedName{Name: secretName},
+				Data:       store.KeyValues{"password": []byte("s3cr3t"), "username": []byte("admin")},
+			},
+			secret: &store.Secret{
+				ScopedName: store.ScopedName{Name: secretName},
+				Data:       store.KeyValues{"password": nil},
+			},
+			want: want{
+				data: store.KeyValues{"username": []byte("admin")},
+			},
+		},
+		"DeleteSecret": {
+			reason: "We should delete the whole secret if no keys are supplied.",
+			existing: &store.Secret{
+				ScopedName: store.ScopedName{Name: secretName},
+				Data:       store.KeyValues{"password": []byte("s3cr3t")},
+			},
+			secret: &store.Secret{ScopedName: store.ScopedName{Name: secretName}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ss := newStore(t, tc.existing)
+			err := ss.DeleteKeyValues(context.Background(), tc.secret, tc.do...)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nss.DeleteKeyValues(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			got := &store.Secret{}
+			if err := ss.ReadKeyValues(context.Background(), tc.secret.ScopedName, got); err != nil {
+				t.Fatalf("ss.ReadKeyValues(...): %s", err)
+			}
+			if diff := cmp.Diff(tc.want.data, got.Data); diff != "" {
+				t.Errorf("\n%s\nss.DeleteKeyValues(...): -want data, +got data:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestToDocument(t *testing.T) {
+	opaque := corev1.SecretTypeOpaque
+
+	cases := map[string]struct {
+		reason string
+		path   string
+		secret *store.Secret
+		want   map[string]any
+	}{
+		"Minimal": {
+			reason: "We should write a connection Secret in the namespace named after the file's directory.",
+			path:   "/secrets/default/conn.yaml",
+			secret: &store.Secret{ScopedName: store.ScopedName{Name: "conn"}},
+			want: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "Secret",
+				"metadata":   map[string]any{"name": "conn", "namespace": "default"},
+				"type":       string(resource.SecretTypeConnection),
+			},
+		},
+		"Full": {
+			reason: "We should write the metadata, type, and base64 encoded data of the secret.",
+			path:   "/secrets/default/conn.yaml",
+			secret: &store.Secret{
+				ScopedName: store.ScopedName{Name: "conn"},
+				Metadata: &v1.ConnectionSecretMetadata{
+					Labels:      map[string]string{"l": "v"},
+					Annotations: map[string]string{"a": "v"},
+					Type:        &opaque,
+				},
+				Data: store.KeyValues{"password": []byte("s3cr3t")},
+			},
+			want: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "Secret",
+				"metadata": map[string]any{
+					"name":        "conn",
+					"namespace":   "default",
+					"labels":      map[string]any{"l": "v"},
+					"annotations": map[string]any{"a": "v"},
+				},
+				"type": string(corev1.SecretTypeOpaque),
+				"data": map[string]any{"password": "czNjcjN0"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := toDocument(tc.path, tc.secret)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ntoDocument(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNewSecretStore(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		cfg    v1.SecretStoreConfig
+		want   error
+	}{
+		"NoConfig": {
+			reason: "We should return an error if no SOPS config is provided.",
+			cfg:    v1.SecretStoreConfig{},
+			want:   errors.New(errNoConfig),
+		},
+		"InvalidVaultAuth": {
+			reason: "We should return an error if we cannot create a Vault client.",
+			cfg: v1.SecretStoreConfig{
+				SOPS: &v1.SOPSSecretStoreConfig{
+					VaultTransit: v1.SOPSVaultTransitConfig{
+						Auth: v1.VaultAuthConfig{Method: v1.VaultAuthToken},
+					},
+				},
+			},
+			want: errors.Wrap(errors.New("token auth configured but no token provided"), errNewVaultClient),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewSecretStore(context.Background(), nil, nil, tc.cfg)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nNewSecretStore(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
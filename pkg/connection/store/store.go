@@ -90,3 +90,22 @@ type WriteOption func(ctx context.Context, current, desired *Secret) error
 
 // An DeleteOption is called before deleting the secret.
 type DeleteOption func(ctx context.Context, secret *Secret) error
+
+// A SecretEventType is the type of a change to a watched secret.
+type SecretEventType string
+
+// Secret event types.
+const (
+	// SecretChanged indicates a watched secret was created or updated.
+	SecretChanged SecretEventType = "Changed"
+
+	// SecretDeleted indicates a watched secret was deleted.
+	SecretDeleted SecretEventType = "Deleted"
+)
+
+// A SecretEvent reports a change to a watched secret, for example because its
+// credentials were rotated outside Crossplane.
+type SecretEvent struct {
+	Type   SecretEventType
+	Secret *Secret
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"path"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/hashicorp/vault/api"
+
+	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	defaultAWSMountPath = "aws"
+	defaultAWSRegion    = "us-east-1"
+
+	headerIAMServerID = "X-Vault-AWS-IAM-Server-ID"
+)
+
+// awsIAMLoginData returns the data used to login to Vault using the IAM type
+// of AWS auth. It contains an STS GetCallerIdentity request signed using the
+// supplied credentials, which Vault sends to AWS to learn the caller's
+// identity.
+func awsIAMLoginData(cfg *v1.VaultAuthAWSIAMConfig, creds *credentials.Credentials) (map[string]any, error) {
+	// Requests are sent to the global STS endpoint unless a region is
+	// configured, in which case the regional endpoint is used.
+	ac := &aws.Config{Credentials: creds, Region: aws.String(defaultAWSRegion)}
+	if cfg.Region != "" {
+		ac.Region = aws.String(cfg.Region)
+		ac.STSRegionalEndpoint = endpoints.RegionalSTSEndpoint
+	}
+	sess, err := session.NewSession(ac)
+	if err != nil {
+		return nil, err
+	}
+
+	req, _ := sts.New(sess).GetCallerIdentityRequest(nil)
+	if cfg.ServerIDHeaderValue != "" {
+		req.HTTPRequest.Header.Add(headerIAMServerID, cfg.ServerIDHeaderValue)
+	}
+	if err := req.Sign(); err != nil {
+		return nil, err
+	}
+
+	headers, err := json.Marshal(req.HTTPRequest.Header)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(req.HTTPRequest.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"role":                    cfg.Role,
+		"iam_http_request_method": req.HTTPRequest.Method,
+		"iam_request_url":         base64.StdEncoding.EncodeToString([]byte(req.HTTPRequest.URL.String())),
+		"iam_request_headers":     base64.StdEncoding.EncodeToString(headers),
+		"iam_request_body":        base64.StdEncoding.EncodeToString(body),
+	}, nil
+}
+
+// loginAWSIAM sets a token obtained using the IAM type of AWS auth on the
+// supplied client.
+func loginAWSIAM(ctx context.Context, c *api.Client, cfg *v1.VaultAuthAWSIAMConfig, creds *credentials.Credentials) error {
+	data, err := awsIAMLoginData(cfg, creds)
+	if err != nil {
+		return errors.Wrap(err, errSignAWSRequest)
+	}
+
+	mount := cfg.MountPath
+	if mount == "" {
+		mount = defaultAWSMountPath
+	}
+	s, err := c.Logical().WriteWithContext(ctx, path.Join("auth", mount, "login"), data)
+	if err != nil {
+		return err
+	}
+	if s == nil || s.Auth == nil || s.Auth.ClientToken == "" {
+		return errors.New(errNoAuthInfo)
+	}
+	c.SetToken(s.Auth.ClientToken)
+	return nil
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/vault/api"
+
+	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestLoginAWSIAM(t *testing.T) {
+	// login is the subset of the login request that doesn't vary with the
+	// time the request was signed at.
+	type login struct {
+		Path       string
+		Role       string
+		Method     string
+		URL        string
+		Body       string
+		ServerID   []string
+		HasSigning bool
+	}
+	type args struct {
+		cfg     *v1.VaultAuthAWSIAMConfig
+		respond *api.Secret
+	}
+	type want struct {
+		token string
+		login login
+		err   error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Login": {
+			reason: "We should login by sending a signed STS GetCallerIdentity request to Vault.",
+			args: args{
+				cfg:     &v1.VaultAuthAWSIAMConfig{Role: "provider"},
+				respond: &api.Secret{Auth: &api.SecretAuth{ClientToken: "new-token"}},
+			},
+			want: want{
+				token: "new-token",
+				login: login{
+					Path:       "/v1/auth/aws/login",
+					Role:       "provider",
+					Method:     http.MethodPost,
+					URL:        "https://sts.amazonaws.com/",
+					Body:       "Action=GetCallerIdentity&Version=2011-06-15",
+					HasSigning: true,
+				},
+			},
+		},
+		"LoginWithServerID": {
+			reason: "We should honor the configured mount path, region and server ID header.",
+			args: args{
+				cfg: &v1.VaultAuthAWSIAMConfig{
+					Role:                "provider",
+					MountPath:           "aws-eu",
+					Region:              "eu-west-1",
+					ServerIDHeaderValue: "vault.example.org",
+				},
+				respond: &api.Secret{Auth: &api.SecretAuth{ClientToken: "new-token"}},
+			},
+			want: want{
+				token: "new-token",
+				login: login{
+					Path:       "/v1/auth/aws-eu/login",
+					Role:       "provider",
+					Method:     http.MethodPost,
+					URL:        "https://sts.eu-west-1.amazonaws.com/",
+					Body:       "Action=GetCallerIdentity&Version=2011-06-15",
+					ServerID:   []string{"vault.example.org"},
+					HasSigning: true,
+				},
+			},
+		},
+		"NoAuthInfo": {
+			reason: "We should return an error if Vault does not return a token.",
+			args: args{
+				cfg:     &v1.VaultAuthAWSIAMConfig{Role: "provider"},
+				respond: &api.Secret{},
+			},
+			want: want{
+				login: login{
+					Path:       "/v1/auth/aws/login",
+					Role:       "provider",
+					Method:     http.MethodPost,
+					URL:        "https://sts.amazonaws.com/",
+					Body:       "Action=GetCallerIdentity&Version=2011-06-15",
+					HasSigning: true,
+				},
+				err: errors.New(errNoAuthInfo),
+			},
+		},
+	}
+
+	decode := func(t *testing.T, s string) []byte {
+		t.Helper()
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			t.Fatalf("base64.DecodeString(...): %s", err)
+		}
+		return b
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got login
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				data := map[string]string{}
+				if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+					t.Errorf("json.Decode(...): %s", err)
+				}
+				h := http.Header{}
+				if err := json.Unmarshal(decode(t, data["iam_request_headers"]), &h); err != nil {
+					t.Errorf("json.Unmarshal(...): %s", err)
+				}
+				got = login{
+					Path:       r.URL.Path,
+					Role:       data["role"],
+					Method:     data["iam_http_request_method"],
+					URL:        string(decode(t, data["iam_request_url"])),
+					Body:       string(decode(t, data["iam_request_body"])),
+					ServerID:   h.Values(headerIAMServerID),
+					HasSigning: h.Get("Authorization") != "",
+				}
+				_ = json.NewEncoder(w).Encode(tc.args.respond)
+			}))
+			defer srv.Close()
+
+			cfg := api.DefaultConfig()
+			cfg.Address = srv.URL
+			c, err := api.NewClient(cfg)
+			if err != nil {
+				t.Fatalf("api.NewClient(...): %s", err)
+			}
+			c.ClearToken()
+
+			creds := credentials.NewStaticCredentials("AKID", "SECRET", "")
+			err = loginAWSIAM(context.Background(), c, tc.args.cfg, creds)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nloginAWSIAM(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.token, c.Token()); diff != "" {
+				t.Errorf("\n%s\nloginAWSIAM(...): -want token, +got token:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.login, got); diff != "" {
+				t.Errorf("\n%s\nloginAWSIAM(...): -want login, +got login:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/vault/api"
+
+	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	defaultCertMountPath = "cert"
+
+	// tokenExpiryMargin is how long before its lease expires a cached token
+	// is considered expired, so that it isn't used just before Vault
+	// revokes it.
+	tokenExpiryMargin = 30 * time.Second
+)
+
+// certTokens caches the tokens obtained using TLS certificate auth. Secret
+// stores are built on demand, so without it every store would log in to
+// Vault anew.
+var certTokens = newTokenCache(time.Now)
+
+type cachedToken struct {
+	fingerprint [sha256.Size]byte
+	token       string
+	expires     time.Time
+}
+
+// A tokenCache caches Vault tokens by login. A cached token is only reused
+// while it has not expired and was issued for the same client certificate,
+// so rotating the certificate results in a fresh login.
+type tokenCache struct {
+	mu     sync.Mutex
+	now    func() time.Time
+	tokens map[string]cachedToken
+}
+
+func newTokenCache(now func() time.Time) *tokenCache {
+	return &tokenCache{now: now, tokens: make(map[string]cachedToken)}
+}
+
+// useClientCert configures the supplied Vault config to present the supplied
+// PEM encoded certificate and private key to Vault.
+func useClientCert(cfg *api.Config, pem []byte) error {
+	cert, err := tls.X509KeyPair(pem, pem)
+	if err != nil {
+		return err
+	}
+	cfg.HttpClient.Transport.(*http.Transport).TLSClientConfig.Certificates = []tls.Certificate{cert}
+	return nil
+}
+
+// loginCert sets a token obtained using TLS certificate auth on the supplied
+// client, which must present the supplied PEM encoded client certificate.
+func (tc *tokenCache) loginCert(ctx context.Context, c *api.Client, cfg *v1.VaultAuthCertConfig, pem []byte) error {
+	mount := cfg.MountPath
+	if mount == "" {
+		mount = defaultCertMountPath
+	}
+	key := strings.Join([]string{c.Address(), c.Namespace(), mount, cfg.Name}, "/")
+	fp := sha256.Sum256(pem)
+
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	if t, ok := tc.tokens[key]; ok && t.fingerprint == fp && (t.expires.IsZero() || tc.now().Before(t.expires)) {
+		c.SetToken(t.token)
+		return nil
+	}
+
+	var data map[string]any
+	if cfg.Name != "" {
+		data = map[string]any{"name": cfg.Name}
+	}
+	s, err := c.Logical().WriteWithContext(ctx, path.Join("auth", mount, "login"), data)
+	if err != nil {
+		return err
+	}
+	if s == nil || s.Auth == nil || s.Auth.ClientToken == "" {
+		return errors.New(errNoAuthInfo)
+	}
+
+	t := cachedToken{fingerprint: fp, token: s.Auth.ClientToken}
+	if s.Auth.LeaseDuration > 0 {
+		t.expires = tc.now().Add(time.Duration(s.Auth.LeaseDuration)*time.Second - tokenExpiryMargin)
+	}
+	tc.tokens[key] = t
+	c.SetToken(t.token)
+	return nil
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/vault/api"
+
+	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func newClientCertPEM(t *testing.T) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey(...): %s", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "crossplane"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("x509.CreateCertificate(...): %s", err)
+	}
+	k, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("x509.MarshalECPrivateKey(...): %s", err)
+	}
+	return append(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: k})...)
+}
+
+func TestUseClientCert(t *testing.T) {
+	type want struct {
+		certs int
+		err   bool
+	}
+	cases := map[string]struct {
+		reason string
+		pem    []byte
+		want   want
+	}{
+		"InvalidCert": {
+			reason: "We should return an error if the credentials are not a PEM encoded certificate and key.",
+			pem:    []byte("not-a-cert"),
+			want:   want{err: true},
+		},
+		"ValidCert": {
+			reason: "We should present a valid certificate to Vault.",
+			pem:    newClientCertPEM(t),
+			want:   want{certs: 1},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cfg := api.DefaultConfig()
+			err := useClientCert(cfg, tc.pem)
+			if diff := cmp.Diff(tc.want.err, err != nil); diff != "" {
+				t.Errorf("\n%s\nuseClientCert(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			got := len(cfg.HttpClient.Transport.(*http.Transport).TLSClientConfig.Certificates)
+			if diff := cmp.Diff(tc.want.certs, got); diff != "" {
+				t.Errorf("\n%s\nuseClientCert(...): -want certificates, +got certificates:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTokenCacheLoginCert(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	cert := []byte("cert")
+	rotated := []byte("rotated")
+
+	type request struct {
+		Path string
+		Name string
+	}
+	type args struct {
+		cached map[string]cachedToken
+		cfg    *v1.VaultAuthCertConfig
+		pem    []byte
+	}
+	type want struct {
+		token    string
+		requests []request
+		err      error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Login": {
+			reason: "We should login using the configured role and mount path if no token is cached.",
+			args: args{
+				cfg: &v1.VaultAuthCertConfig{Name: "web", MountPath: "tls"},
+				pem: cert,
+			},
+			want: want{
+				token:    "new-token",
+				requests: []request{{Path: "/v1/auth/tls/login", Name: "web"}},
+			},
+		},
+		"ReuseCachedToken": {
+			reason: "We should reuse a cached token that was issued for the same certificate.",
+			args: args{
+				cached: map[string]cachedToken{
+					"cert/web": {fingerprint: sha256.Sum256(cert), token: "cached-token", expires: now.Add(time.Minute)},
+				},
+				cfg: &v1.VaultAuthCertConfig{Name: "web"},
+				pem: cert,
+			},
+			want: want{
+				token: "cached-token",
+			},
+		},
+		"CertRotated": {
+			reason: "We should login again if the certificate was rotated since the cached token was issued.",
+			args: args{
+				cached: map[string]cachedToken{
+					"cert/": {fingerprint: sha256.Sum256(cert), token: "cached-token"},
+				},
+				cfg: &v1.VaultAuthCertConfig{},
+				pem: rotated,
+			},
+			want: want{
+				token:    "new-token",
+				requests: []request{{Path: "/v1/auth/cert/login"}},
+			},
+		},
+		"TokenExpired": {
+			reason: "We should login again if the cached token has expired.",
+			args: args{
+				cached: map[string]cachedToken{
+					"cert/": {fingerprint: sha256.Sum256(cert), token: "cached-token", expires: now},
+				},
+				cfg: &v1.VaultAuthCertConfig{},
+				pem: cert,
+			},
+			want: want{
+				token:    "new-token",
+				requests: []request{{Path: "/v1/auth/cert/login"}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var requests []request
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				rq := request{Path: r.URL.Path}
+				_ = json.NewDecoder(r.Body).Decode(&rq)
+				requests = append(requests, rq)
+				_ = json.NewEncoder(w).Encode(api.Secret{Auth: &api.SecretAuth{ClientToken: "new-token", LeaseDuration: 3600}})
+			}))
+			defer srv.Close()
+
+			cfg := api.DefaultConfig()
+			cfg.Address = srv.URL
+			c, err := api.NewClient(cfg)
+			if err != nil {
+				t.Fatalf("api.NewClient(...): %s", err)
+			}
+
+			tcache := newTokenCache(func() time.Time { return now })
+			for k, v := range tc.args.cached {
+				tcache.tokens[srv.URL+"//"+k] = v
+			}
+
+			err = tcache.loginCert(context.Background(), c, tc.args.cfg, tc.args.pem)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nloginCert(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.token, c.Token()); diff != "" {
+				t.Errorf("\n%s\nloginCert(...): -want token, +got token:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.requests, requests); diff != "" {
+				t.Errorf("\n%s\nloginCert(...): -want requests, +got requests:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
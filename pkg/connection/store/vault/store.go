@@ -23,7 +23,10 @@ import (
 	"crypto/x509"
 	"net/http"
 	"path/filepath"
+	"strings"
+	"text/template"
 
+	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/hashicorp/vault/api"
@@ -48,6 +51,20 @@ const (
 	errLoginKubernetesAuth = "cannot logging in with kubernetes auth"
 	errNoTokenProvided     = "token auth configured but no token provided"
 	errNoRoleProvided      = "kubernetes auth configured but no role provided"
+	errNoCertProvided      = "cert auth configured but no certificate provided"
+	errExtractClientCert   = "cannot extract client certificate"
+	errParseClientCert     = "cannot parse client certificate"
+	errLoginCertAuth       = "cannot login with cert auth"
+	errNoAuthInfo          = "login response contains no auth information"
+	errNoAWSRoleProvided   = "aws iam auth configured but no role provided"
+	errNewAWSSession       = "cannot create aws session"
+	errSignAWSRequest      = "cannot sign aws sts request"
+	errLoginAWSIAMAuth     = "cannot login with aws iam auth"
+	errParseNamespace      = "cannot parse namespace template"
+	errParseParentPath     = "cannot parse parent path template"
+	errRenderNamespace     = "cannot render namespace template"
+	errRenderParentPath    = "cannot render parent path template"
+	errEmptyRender         = "template rendered an empty string"
 
 	errGet    = "cannot get secret"
 	errApply  = "cannot apply secret"
@@ -65,22 +82,87 @@ type KVClient interface {
 type SecretStore struct {
 	client KVClient
 
+	// clientFor returns a client for the supplied Vault namespace. It is
+	// only used when the namespace is templated.
+	clientFor  func(namespace string) KVClient
+	namespace  *template.Template
+	parentPath *template.Template
+
 	defaultParentPath string
 }
 
 // NewSecretStore returns a new Vault SecretStore.
-func NewSecretStore(ctx context.Context, kube client.Client, _ *tls.Config, cfg v1.SecretStoreConfig) (*SecretStore, error) { //nolint: gocyclo // See note below.
-	// NOTE(turkenh): Adding linter exception for gocyclo since this function
-	// went a little over the limit due to the switch statements not because of
-	// some complex logic.
+func NewSecretStore(ctx context.Context, kube client.Client, _ *tls.Config, cfg v1.SecretStoreConfig) (*SecretStore, error) {
 	if cfg.Vault == nil {
 		return nil, errors.New(errNoConfig)
 	}
+
+	var err error
+	ss := &SecretStore{defaultParentPath: cfg.DefaultScope}
+	if t := cfg.Vault.NamespaceTemplate; t != nil {
+		if ss.namespace, err = parseTemplate("namespace", *t); err != nil {
+			return nil, errors.Wrap(err, errParseNamespace)
+		}
+	}
+	if t := cfg.Vault.ParentPathTemplate; t != nil {
+		if ss.parentPath, err = parseTemplate("parentPath", *t); err != nil {
+			return nil, errors.Wrap(err, errParseParentPath)
+		}
+	}
+
+	c, err := NewClient(ctx, kube, ClientConfig{
+		Server:    cfg.Vault.Server,
+		Namespace: cfg.Vault.Namespace,
+		CABundle:  cfg.Vault.CABundle,
+		Auth:      cfg.Vault.Auth,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	newKVClient := func(c *api.Client) KVClient {
+		switch *cfg.Vault.Version {
+		case v1.VaultKVVersionV1:
+			return kv.NewV1Client(c.Logical(), cfg.Vault.MountPath)
+		case v1.VaultKVVersionV2:
+			return kv.NewV2Client(c.Logical(), cfg.Vault.MountPath)
+		}
+		return nil
+	}
+
+	ss.client = newKVClient(c)
+	ss.clientFor = func(namespace string) KVClient {
+		return newKVClient(c.WithNamespace(namespace))
+	}
+	return ss, nil
+}
+
+// A ClientConfig configures a Vault API client.
+type ClientConfig struct {
+	// Server is the URL of the Vault server.
+	Server string
+
+	// Namespace of Vault to operate on, if any.
+	Namespace string
+
+	// CABundle configures the CA bundle of the Vault server, if any.
+	CABundle *v1.VaultCABundleConfig
+
+	// Auth configures how to authenticate to Vault.
+	Auth v1.VaultAuthConfig
+}
+
+// NewClient returns a Vault API client that is authenticated as configured
+// by the supplied config.
+func NewClient(ctx context.Context, kube client.Client, cfg ClientConfig) (*api.Client, error) { //nolint: gocyclo // See note below.
+	// NOTE(turkenh): Adding linter exception for gocyclo since this function
+	// went a little over the limit due to the switch statements not because of
+	// some complex logic.
 	vCfg := api.DefaultConfig()
-	vCfg.Address = cfg.Vault.Server
+	vCfg.Address = cfg.Server
 
-	if cfg.Vault.CABundle != nil {
-		ca, err := resource.CommonCredentialExtractor(ctx, cfg.Vault.CABundle.Source, kube, cfg.Vault.CABundle.CommonCredentialSelectors)
+	if cfg.CABundle != nil {
+		ca, err := resource.CommonCredentialExtractor(ctx, cfg.CABundle.Source, kube, cfg.CABundle.CommonCredentialSelectors)
 		if err != nil {
 			return nil, errors.Wrap(err, errExtractCABundle)
 		}
@@ -91,44 +173,61 @@ func NewSecretStore(ctx context.Context, kube client.Client, _ *tls.Config, cfg
 		vCfg.HttpClient.Transport.(*http.Transport).TLSClientConfig.RootCAs = pool
 	}
 
+	// The client certificate is read whenever a client is built, so that a
+	// rotated certificate is picked up and used to login again.
+	var cert []byte
+	if cfg.Auth.Method == v1.VaultAuthCert {
+		if cfg.Auth.Cert == nil {
+			return nil, errors.New(errNoCertProvided)
+		}
+		var err error
+		cert, err = resource.CommonCredentialExtractor(ctx, cfg.Auth.Cert.Source, kube, cfg.Auth.Cert.CommonCredentialSelectors)
+		if err != nil {
+			return nil, errors.Wrap(err, errExtractClientCert)
+		}
+		if err := useClientCert(vCfg, cert); err != nil {
+			return nil, errors.Wrap(err, errParseClientCert)
+		}
+	}
+
 	c, err := api.NewClient(vCfg)
 	if err != nil {
 		return nil, errors.Wrap(err, errNewClient)
 	}
 
-	if cfg.Vault.Namespace != "" {
-		c.SetNamespace(cfg.Vault.Namespace)
+	if cfg.Namespace != "" {
+		c.SetNamespace(cfg.Namespace)
 	}
 
-	switch cfg.Vault.Auth.Method {
+	switch cfg.Auth.Method {
 	case v1.VaultAuthToken:
-		if cfg.Vault.Auth.Token == nil {
+		if cfg.Auth.Token == nil {
 			return nil, errors.New(errNoTokenProvided)
 		}
-		t, err := resource.CommonCredentialExtractor(ctx, cfg.Vault.Auth.Token.Source, kube, cfg.Vault.Auth.Token.CommonCredentialSelectors)
+		t, err := resource.CommonCredentialExtractor(ctx, cfg.Auth.Token.Source, kube, cfg.Auth.Token.CommonCredentialSelectors)
 		if err != nil {
 			return nil, errors.Wrap(err, errExtractToken)
 		}
 		c.SetToken(string(t))
 	case v1.VaultAuthKubernetes:
-		if cfg.Vault.Auth.Kubernetes == nil {
+		if cfg.Auth.Kubernetes == nil {
 			return nil, errors.New(errNoRoleProvided)
 		}
 
 		var loginOpts []kubernetes.LoginOption
-		if cfg.Vault.Auth.Kubernetes.MountPath != "" {
-			loginOpts = append(loginOpts, kubernetes.WithMountPath(cfg.Vault.Auth.Kubernetes.MountPath))
+		if cfg.Auth.Kubernetes.MountPath != "" {
+			loginOpts = append(loginOpts, kubernetes.WithMountPath(cfg.Auth.Kubernetes.MountPath))
 		}
 
-		if cfg.Vault.Auth.Kubernetes.ServiceAccountTokenSource != nil {
-			t, err := resource.CommonCredentialExtractor(ctx, cfg.Vault.Auth.Kubernetes.ServiceAccountTokenSource.Source, kube, cfg.Vault.Auth.Kubernetes.ServiceAccountTokenSource.CommonCredentialSelectors)
+		if cfg.Auth.Kubernetes.ServiceAccountTokenSource != nil {
+			t, err := resource.CommonCredentialExtractor(ctx, cfg.Auth.Kubernetes.ServiceAccountTokenSource.Source, kube, cfg.Auth.Kubernetes.ServiceAccountTokenSource.CommonCredentialSelectors)
 			if err != nil {
 				return nil, errors.Wrap(err, errExtractToken)
 			}
 			loginOpts = append(loginOpts, kubernetes.WithServiceAccountToken(string(t)))
 		}
 
-		auth, err := kubernetes.NewKubernetesAuth(cfg.Vault.Auth.Kubernetes.Role, loginOpts...)
+		auth, err := kubernetes.NewKubernetesAuth(cfg.Auth.Kubernetes.Role, loginOpts...)
 		if err != nil {
 			return nil, errors.Wrap(err, errSetupKubernetesAuth)
 		}
@@ -137,33 +236,47 @@ func NewSecretStore(ctx context.Context, kube client.Client, _ *tls.Config, cfg
 		if err != nil {
 			return nil, errors.Wrap(err, errLoginKubernetesAuth)
 		}
+	case v1.VaultAuthCert:
+		if err := certTokens.loginCert(ctx, c, cfg.Auth.Cert, cert); err != nil {
+			return nil, errors.Wrap(err, errLoginCertAuth)
+		}
+	case v1.VaultAuthAWSIAM:
+		if cfg.Auth.AWSIAM == nil || cfg.Auth.AWSIAM.Role == "" {
+			return nil, errors.New(errNoAWSRoleProvided)
+		}
+		// The default credential chain covers static credentials in the
+		// environment, EKS service accounts and EC2 instance profiles.
+		sess, err := session.NewSession()
+		if err != nil {
+			return nil, errors.Wrap(err, errNewAWSSession)
+		}
+		if err := loginAWSIAM(ctx, c, cfg.Auth.AWSIAM, sess.Config.Credentials); err != nil {
+			return nil, errors.Wrap(err, errLoginAWSIAMAuth)
+		}
 	default:
-		return nil, errors.Errorf("%q is not supported as an auth method", cfg.Vault.Auth.Method)
+		return nil, errors.Errorf("%q is not supported as an auth method", cfg.Auth.Method)
 	}
 
-	var kvClient KVClient
-	switch *cfg.Vault.Version {
-	case v1.VaultKVVersionV1:
-		kvClient = kv.NewV1Client(c.Logical(), cfg.Vault.MountPath)
-	case v1.VaultKVVersionV2:
-		kvClient = kv.NewV2Client(c.Logical(), cfg.Vault.MountPath)
-	}
-
-	return &SecretStore{
-		client:            kvClient,
-		defaultParentPath: cfg.DefaultScope,
-	}, nil
+	return c, nil
 }
 
 // ReadKeyValues reads and returns key value pairs for a given Vault Secret.
+// Any metadata of the supplied Secret is used to render the namespace and
+// parent path templates, if any, and is replaced by the metadata that was read.
 func (ss *SecretStore) ReadKeyValues(_ context.Context, n store.ScopedName, s *store.Secret) error {
+	c, path, err := ss.locate(&store.Secret{ScopedName: n, Metadata: s.Metadata})
+	if err != nil {
+		return err
+	}
+
 	kvs := &kv.Secret{}
-	if err := ss.client.Get(ss.path(n), kvs); resource.Ignore(kv.IsNotFound, err) != nil {
+	if err := c.Get(path, kvs); resource.Ignore(kv.IsNotFound, err) != nil {
 		return errors.Wrap(err, errGet)
 	}
 
 	s.ScopedName = n
 	s.Data = keyValuesFromData(kvs.Data)
+	s.Metadata = nil
 	if len(kvs.CustomMeta) > 0 {
 		s.Metadata = &v1.ConnectionSecretMetadata{
 			Labels: kvs.CustomMeta,
@@ -179,7 +292,12 @@ func (ss *SecretStore) WriteKeyValues(ctx context.Context, s *store.Secret, wo .
 		return !cmp.Equal(current, desired, cmpopts.EquateEmpty(), cmpopts.IgnoreUnexported(kv.Secret{}))
 	}))
 
-	err = ss.client.Apply(ss.path(s.ScopedName), kv.NewSecret(dataFromKeyValues(s.Data), s.GetLabels()), ao...)
+	c, path, err := ss.locate(s)
+	if err != nil {
+		return false, err
+	}
+
+	err = c.Apply(path, kv.NewSecret(dataFromKeyValues(s.Data), s.GetLabels()), ao...)
 	if resource.IsNotAllowed(err) {
 		// The update was not allowed because it was a no-op.
 		return false, nil
@@ -195,8 +313,13 @@ func (ss *SecretStore) WriteKeyValues(ctx context.Context, s *store.Secret, wo .
 // If kv specified, those would be deleted and secret instance will be deleted
 // only if there is no Data left.
 func (ss *SecretStore) DeleteKeyValues(ctx context.Context, s *store.Secret, do ...store.DeleteOption) error {
+	c, path, err := ss.locate(s)
+	if err != nil {
+		return err
+	}
+
 	Secret := &kv.Secret{}
-	err := ss.client.Get(ss.path(s.ScopedName), Secret)
+	err = c.Get(path, Secret)
 	if kv.IsNotFound(err) {
 		// Secret already deleted, nothing to do.
 		return nil
@@ -218,17 +341,65 @@ func (ss *SecretStore) DeleteKeyValues(ctx context.Context, s *store.Secret, do
 		// Secret is deleted only if:
 		// - No kv to delete specified as input
 		// - No data left in the secret
-		return errors.Wrap(ss.client.Delete(ss.path(s.ScopedName)), errDelete)
+		return errors.Wrap(c.Delete(path), errDelete)
 	}
 	// If there are still keys left, update the secret with the remaining.
-	return errors.Wrap(ss.client.Apply(ss.path(s.ScopedName), Secret), errApply)
+	return errors.Wrap(c.Apply(path, Secret), errApply)
 }
 
-func (ss *SecretStore) path(s store.ScopedName) string {
+// templateData is the data the namespace and parent path templates of a
+// Vault store config are rendered with.
+type templateData struct {
+	Name        string
+	Scope       string
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+func parseTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Option("missingkey=error").Parse(text)
+}
+
+func render(t *template.Template, s *store.Secret) (string, error) {
+	d := templateData{Name: s.Name, Scope: s.Scope}
+	if s.Metadata != nil {
+		d.Labels = s.Metadata.Labels
+		d.Annotations = s.Metadata.Annotations
+	}
+	b := &strings.Builder{}
+	if err := t.Execute(b, d); err != nil {
+		return "", err
+	}
+	if b.Len() == 0 {
+		return "", errors.New(errEmptyRender)
+	}
+	return b.String(), nil
+}
+
+// locate returns the client and path of the supplied secret, rendering the
+// namespace and parent path templates from its metadata if configured.
+func (ss *SecretStore) locate(s *store.Secret) (KVClient, string, error) {
+	c := ss.client
+	if ss.namespace != nil {
+		ns, err := render(ss.namespace, s)
+		if err != nil {
+			return nil, "", errors.Wrap(err, errRenderNamespace)
+		}
+		c = ss.clientFor(ns)
+	}
+
+	if ss.parentPath != nil {
+		pp, err := render(ss.parentPath, s)
+		if err != nil {
+			return nil, "", errors.Wrap(err, errRenderParentPath)
+		}
+		return c, filepath.Join(pp, s.Name), nil
+	}
+
 	if s.Scope != "" {
-		return filepath.Join(s.Scope, s.Name)
+		return c, filepath.Join(s.Scope, s.Name), nil
 	}
-	return filepath.Join(ss.defaultParentPath, s.Name)
+	return c, filepath.Join(ss.defaultParentPath, s.Name), nil
 }
 
 func applyOptions(ctx context.Context, wo ...store.WriteOption) []kv.ApplyOption {
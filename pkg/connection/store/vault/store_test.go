@@ -23,6 +23,7 @@ import (
 	"net/http/httptest"
 	"path/filepath"
 	"testing"
+	"text/template"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/vault/api"
@@ -721,6 +722,9 @@ func TestSecretStoreDeleteKeyValues(t *testing.T) {
 
 func TestNewSecretStore(t *testing.T) {
 	kvv2 := v1.VaultKVVersionV2
+	badTemplate := "{{ .Labels.team"
+	_, errParseNamespaceTemplate := parseTemplate("namespace", badTemplate)
+	_, errParseParentPathTemplate := parseTemplate("parentPath", badTemplate)
 
 	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		payload := api.Secret{
@@ -794,6 +798,68 @@ func TestNewSecretStore(t *testing.T) {
 				err: errors.Wrap(errors.New("no role name was provided"), errSetupKubernetesAuth),
 			},
 		},
+		"InvalidCertAuthConfig": {
+			reason: "Should return a proper error if vault cert auth configuration is not valid.",
+			args: args{
+				cfg: v1.SecretStoreConfig{
+					Vault: &v1.VaultSecretStoreConfig{
+						Auth: v1.VaultAuthConfig{
+							Method: v1.VaultAuthCert,
+							Cert:   nil,
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.New(errNoCertProvided),
+			},
+		},
+		"NoClientCertSecret": {
+			reason: "Should return a proper error if the client certificate secret does not exist.",
+			args: args{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "vault-cert")),
+				},
+				cfg: v1.SecretStoreConfig{
+					Vault: &v1.VaultSecretStoreConfig{
+						Auth: v1.VaultAuthConfig{
+							Method: v1.VaultAuthCert,
+							Cert: &v1.VaultAuthCertConfig{
+								Source: v1.CredentialsSourceSecret,
+								CommonCredentialSelectors: v1.CommonCredentialSelectors{
+									SecretRef: &v1.SecretKeySelector{
+										SecretReference: v1.SecretReference{
+											Name:      "vault-cert",
+											Namespace: "crossplane-system",
+										},
+										Key: "tls.pem",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrap(kerrors.NewNotFound(schema.GroupResource{}, "vault-cert"), "cannot get credentials secret"), errExtractClientCert),
+			},
+		},
+		"NoAWSIAMRoleProvided": {
+			reason: "Should return a proper error if vault aws iam auth is configured without a role.",
+			args: args{
+				cfg: v1.SecretStoreConfig{
+					Vault: &v1.VaultSecretStoreConfig{
+						Auth: v1.VaultAuthConfig{
+							Method: v1.VaultAuthAWSIAM,
+							AWSIAM: &v1.VaultAuthAWSIAMConfig{},
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.New(errNoAWSRoleProvided),
+			},
+		},
 		"NoTokenSecret": {
 			reason: "Should return a proper error if configured vault token secret does not exist.",
 			args: args{
@@ -861,6 +927,32 @@ func TestNewSecretStore(t *testing.T) {
 				err: errors.Wrap(errors.Wrap(kerrors.NewNotFound(schema.GroupResource{}, "service-account-token"), "cannot get credentials secret"), errExtractToken),
 			},
 		},
+		"InvalidNamespaceTemplate": {
+			reason: "Should return a proper error if the namespace template cannot be parsed.",
+			args: args{
+				cfg: v1.SecretStoreConfig{
+					Vault: &v1.VaultSecretStoreConfig{
+						NamespaceTemplate: &badTemplate,
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errParseNamespaceTemplate, errParseNamespace),
+			},
+		},
+		"InvalidParentPathTemplate": {
+			reason: "Should return a proper error if the parent path template cannot be parsed.",
+			args: args{
+				cfg: v1.SecretStoreConfig{
+					Vault: &v1.VaultSecretStoreConfig{
+						ParentPathTemplate: &badTemplate,
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errParseParentPathTemplate, errParseParentPath),
+			},
+		},
 		"SuccessfulStore": {
 			reason: "Should return no error after building store successfully.",
 			args: args{
@@ -941,6 +1033,46 @@ func TestNewSecretStore(t *testing.T) {
 				err: nil,
 			},
 		},
+		"SuccessfulCertStore": {
+			reason: "Should return no error after building store successfully.",
+			args: args{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						*obj.(*corev1.Secret) = corev1.Secret{
+							Data: map[string][]byte{
+								"tls.pem": newClientCertPEM(t),
+							},
+						}
+						return nil
+					}),
+				},
+				cfg: v1.SecretStoreConfig{
+					Vault: &v1.VaultSecretStoreConfig{
+						Server:  testServer.URL,
+						Version: &kvv2,
+						Auth: v1.VaultAuthConfig{
+							Method: v1.VaultAuthCert,
+							Cert: &v1.VaultAuthCertConfig{
+								Name:   "some-role",
+								Source: v1.CredentialsSourceSecret,
+								CommonCredentialSelectors: v1.CommonCredentialSelectors{
+									SecretRef: &v1.SecretKeySelector{
+										SecretReference: v1.SecretReference{
+											Name:      "vault-cert",
+											Namespace: "crossplane-system",
+										},
+										Key: "tls.pem",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				err: nil,
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
@@ -951,3 +1083,113 @@ func TestNewSecretStore(t *testing.T) {
 		})
 	}
 }
+
+func TestSecretStoreLocate(t *testing.T) {
+	team := func(team string) *v1.ConnectionSecretMetadata {
+		return &v1.ConnectionSecretMetadata{Labels: map[string]string{"team": team}}
+	}
+
+	type args struct {
+		namespaceTemplate  string
+		parentPathTemplate string
+		secret             *store.Secret
+	}
+	type want struct {
+		namespace string
+		path      string
+		err       error
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"NoTemplates": {
+			reason: "Secrets should be located by their scope if no templates are configured.",
+			args: args{
+				secret: &store.Secret{ScopedName: store.ScopedName{Name: secretName, Scope: "some-scope"}, Metadata: team("a")},
+			},
+			want: want{
+				path: filepath.Join("some-scope", secretName),
+			},
+		},
+		"NamespaceTemplate": {
+			reason: "Secrets should be located in the namespace rendered from their metadata.",
+			args: args{
+				namespaceTemplate: "teams/{{ .Labels.team }}",
+				secret:            &store.Secret{ScopedName: store.ScopedName{Name: secretName}, Metadata: team("a")},
+			},
+			want: want{
+				namespace: "teams/a",
+				path:      filepath.Join(parentPathDefault, secretName),
+			},
+		},
+		"ParentPathTemplate": {
+			reason: "Secrets should be located at the parent path rendered from their metadata, regardless of their scope.",
+			args: args{
+				parentPathTemplate: "{{ .Labels.team }}/{{ .Scope }}",
+				secret:             &store.Secret{ScopedName: store.ScopedName{Name: secretName, Scope: "some-scope"}, Metadata: team("a")},
+			},
+			want: want{
+				path: filepath.Join("a", "some-scope", secretName),
+			},
+		},
+		"MissingLabel": {
+			reason: "Should return a proper error if a template refers to a missing label.",
+			args: args{
+				namespaceTemplate: "teams/{{ .Labels.team }}",
+				secret:            &store.Secret{ScopedName: store.ScopedName{Name: secretName}},
+			},
+			want: want{
+				err: errors.Wrap(errors.New(`template: namespace:1:16: executing "namespace" at <.Labels.team>: map has no entry for key "team"`), errRenderNamespace),
+			},
+		},
+		"EmptyRender": {
+			reason: "Should return a proper error if a template renders an empty string.",
+			args: args{
+				parentPathTemplate: `{{ index .Annotations "team" }}`,
+				secret:             &store.Secret{ScopedName: store.ScopedName{Name: secretName}, Metadata: team("a")},
+			},
+			want: want{
+				err: errors.Wrap(errors.New(errEmptyRender), errRenderParentPath),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			namespaced := map[string]*fake.KVClient{}
+			ss := &SecretStore{
+				client: &fake.KVClient{},
+				clientFor: func(namespace string) KVClient {
+					namespaced[namespace] = &fake.KVClient{}
+					return namespaced[namespace]
+				},
+				defaultParentPath: parentPathDefault,
+			}
+			if tc.args.namespaceTemplate != "" {
+				ss.namespace = template.Must(parseTemplate("namespace", tc.args.namespaceTemplate))
+			}
+			if tc.args.parentPathTemplate != "" {
+				ss.parentPath = template.Must(parseTemplate("parentPath", tc.args.parentPathTemplate))
+			}
+
+			c, path, err := ss.locate(tc.args.secret)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nss.locate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.path, path); diff != "" {
+				t.Errorf("\n%s\nss.locate(...): -want path, +got path:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			wantClient := KVClient(ss.client)
+			if tc.want.namespace != "" {
+				wantClient = namespaced[tc.want.namespace]
+			}
+			if c != wantClient {
+				t.Errorf("\n%s\nss.locate(...): want client for namespace %q", tc.reason, tc.want.namespace)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"context"
+	"encoding/base64"
+	"path"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/connection/store/vault/kv"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// Error strings.
+const (
+	errTransitEncrypt  = "cannot encrypt using Vault transit engine"
+	errTransitDecrypt  = "cannot decrypt using Vault transit engine"
+	errNoCiphertext    = "Vault transit engine returned no ciphertext"
+	errNoPlaintext     = "Vault transit engine returned no plaintext"
+	errDecodePlaintext = "cannot decode plaintext returned by Vault transit engine"
+)
+
+const (
+	defaultTransitMountPath = "transit"
+
+	// transitPrefix is the prefix of all ciphertext returned by the Vault
+	// transit engine, followed by the key version.
+	transitPrefix = "vault:v"
+)
+
+// A TransitCipherOption configures a TransitCipher.
+type TransitCipherOption func(*TransitCipher)
+
+// WithTransitMountPath configures the mount path of the transit secrets
+// engine. Defaults to "transit".
+func WithTransitMountPath(p string) TransitCipherOption {
+	return func(c *TransitCipher) {
+		c.mountPath = p
+	}
+}
+
+// A TransitCipher encrypts and decrypts connection detail values using the
+// Vault transit secrets engine, so that the encryption key never leaves
+// Vault. It can be used to encrypt connection details written to stores other
+// than Vault.
+// https://developer.hashicorp.com/vault/docs/secrets/transit
+type TransitCipher struct {
+	client    kv.LogicalClient
+	mountPath string
+	key       string
+}
+
+// NewTransitCipher returns a TransitCipher that uses the supplied named key.
+func NewTransitCipher(c kv.LogicalClient, key string, o ...TransitCipherOption) *TransitCipher {
+	tc := &TransitCipher{client: c, mountPath: defaultTransitMountPath, key: key}
+	for _, fn := range o {
+		fn(tc)
+	}
+	return tc
+}
+
+// Encrypt the supplied plaintext.
+func (c *TransitCipher) Encrypt(_ context.Context, plaintext []byte) ([]byte, error) {
+	s, err := c.client.Write(path.Join(c.mountPath, "encrypt", c.key), map[string]any{
+		"plaintext": base64.StdEncoding.EncodeToString(plaintext),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errTransitEncrypt)
+	}
+	if s == nil {
+		return nil, errors.New(errNoCiphertext)
+	}
+	ct, ok := s.Data["ciphertext"].(string)
+	if !ok || ct == "" {
+		return nil, errors.New(errNoCiphertext)
+	}
+	return []byte(ct), nil
+}
+
+// Decrypt the supplied ciphertext. Values that don't look like they were
+// encrypted by the transit engine are returned unchanged.
+func (c *TransitCipher) Decrypt(_ context.Context, ciphertext []byte) ([]byte, error) {
+	if !strings.HasPrefix(string(ciphertext), transitPrefix) {
+		return ciphertext, nil
+	}
+	s, err := c.client.Write(path.Join(c.mountPath, "decrypt", c.key), map[string]any{
+		"ciphertext": string(ciphertext),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errTransitDecrypt)
+	}
+	if s == nil {
+		return nil, errors.New(errNoPlaintext)
+	}
+	pt, ok := s.Data["plaintext"].(string)
+	if !ok {
+		return nil, errors.New(errNoPlaintext)
+	}
+	b, err := base64.StdEncoding.DecodeString(pt)
+	return b, errors.Wrap(err, errDecodePlaintext)
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/vault/api"
+
+	"github.com/crossplane/crossplane-runtime/pkg/connection/store/vault/kv/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestTransitCipherEncrypt(t *testing.T) {
+	type args struct {
+		client    *fake.LogicalClient
+		o         []TransitCipherOption
+		plaintext []byte
+	}
+	type want struct {
+		ciphertext []byte
+		err        error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"WriteError": {
+			reason: "We should return any error encountered while encrypting.",
+			args: args{
+				client: &fake.LogicalClient{
+					WriteFn: func(path string, data map[string]any) (*api.Secret, error) {
+						return nil, errBoom
+					},
+				},
+				plaintext: []byte("s3cr3t"),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errTransitEncrypt),
+			},
+		},
+		"NoCiphertext": {
+			reason: "We should return an error if Vault returns no ciphertext.",
+			args: args{
+				client: &fake.LogicalClient{
+					WriteFn: func(path string, data map[string]any) (*api.Secret, error) {
+						return &api.Secret{}, nil
+					},
+				},
+				plaintext: []byte("s3cr3t"),
+			},
+			want: want{
+				err: errors.New(errNoCiphertext),
+			},
+		},
+		"Success": {
+			reason: "We should send the base64 encoded plaintext to the encrypt endpoint of the configured key.",
+			args: args{
+				client: &fake.LogicalClient{
+					WriteFn: func(path string, data map[string]any) (*api.Secret, error) {
+						if diff := cmp.Diff("secrets/encrypt/conn", path); diff != "" {
+							t.Errorf("Write(...): -want path, +got path:\n%s", diff)
+						}
+						if diff := cmp.Diff(map[string]any{"plaintext": "czNjcjN0"}, data); diff != "" {
+							t.Errorf("Write(...): -want data, +got data:\n%s", diff)
+						}
+						return &api.Secret{Data: map[string]any{"ciphertext": "vault:v1:abc"}}, nil
+					},
+				},
+				o:         []TransitCipherOption{WithTransitMountPath("secrets")},
+				plaintext: []byte("s3cr3t"),
+			},
+			want: want{
+				ciphertext: []byte("vault:v1:abc"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := NewTransitCipher(tc.args.client, "conn", tc.args.o...)
+			got, err := c.Encrypt(context.Background(), tc.args.plaintext)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nc.Encrypt(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ciphertext, got); diff != "" {
+				t.Errorf("\n%s\nc.Encrypt(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTransitCipherDecrypt(t *testing.T) {
+	type args struct {
+		client     *fake.LogicalClient
+		ciphertext []byte
+	}
+	type want struct {
+		plaintext []byte
+		err       error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotEncrypted": {
+			reason: "We should return values that weren't encrypted by the transit engine unchanged.",
+			args: args{
+				client:     &fake.LogicalClient{},
+				ciphertext: []byte("s3cr3t"),
+			},
+			want: want{
+				plaintext: []byte("s3cr3t"),
+			},
+		},
+		"WriteError": {
+			reason: "We should return any error encountered while decrypting.",
+			args: args{
+				client: &fake.LogicalClient{
+					WriteFn: func(path string, data map[string]any) (*api.Secret, error) {
+						return nil, errBoom
+					},
+				},
+				ciphertext: []byte("vault:v1:abc"),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errTransitDecrypt),
+			},
+		},
+		"Success": {
+			reason: "We should return the base64 decoded plaintext returned by the decrypt endpoint.",
+			args: args{
+				client: &fake.LogicalClient{
+					WriteFn: func(path string, data map[string]any) (*api.Secret, error) {
+						if diff := cmp.Diff("transit/decrypt/conn", path); diff != "" {
+							t.Errorf("Write(...): -want path, +got path:\n%s", diff)
+						}
+						if diff := cmp.Diff(map[string]any{"ciphertext": "vault:v1:abc"}, data); diff != "" {
+							t.Errorf("Write(...): -want data, +got data:\n%s", diff)
+						}
+						return &api.Secret{Data: map[string]any{"plaintext": "czNjcjN0"}}, nil
+					},
+				},
+				ciphertext: []byte("vault:v1:abc"),
+			},
+			want: want{
+				plaintext: []byte("s3cr3t"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := NewTransitCipher(tc.args.client, "conn")
+			got, err := c.Decrypt(context.Background(), tc.args.ciphertext)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nc.Decrypt(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.plaintext, got); diff != "" {
+				t.Errorf("\n%s\nc.Decrypt(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -25,10 +25,13 @@ import (
 	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/connection/store/kubernetes"
 	"github.com/crossplane/crossplane-runtime/pkg/connection/store/plugin"
+	"github.com/crossplane/crossplane-runtime/pkg/connection/store/sops"
 	"github.com/crossplane/crossplane-runtime/pkg/connection/store/vault"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 )
 
+var _ WatchingStore = &plugin.SecretStore{}
+
 const (
 	errFmtUnknownSecretStore = "unknown secret store type: %q"
 )
@@ -45,6 +48,8 @@ func RuntimeStoreBuilder(ctx context.Context, local client.Client, tcfg *tls.Con
 		return vault.NewSecretStore(ctx, local, nil, cfg)
 	case v1.SecretStorePlugin:
 		return plugin.NewSecretStore(ctx, local, tcfg, cfg)
+	case v1.SecretStoreSOPS:
+		return sops.NewSecretStore(ctx, local, nil, cfg)
 	}
 	return nil, errors.Errorf(errFmtUnknownSecretStore, *cfg.Type)
 }
@@ -0,0 +1,127 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// A CacheOption configures the informer cache of a controller manager.
+type CacheOption func(o *cache.Options)
+
+// WithCacheSelector configures the cache to only cache objects of the supplied
+// type that match the supplied label and field selectors. Either selector may
+// be nil. Objects that don't match can't be read from the cache, and don't
+// trigger watch events.
+func WithCacheSelector(obj client.Object, l labels.Selector, f fields.Selector) CacheOption {
+	return func(o *cache.Options) {
+		if o.SelectorsByObject == nil {
+			o.SelectorsByObject = cache.SelectorsByObject{}
+		}
+		o.SelectorsByObject[obj] = cache.ObjectSelector{Label: l, Field: f}
+	}
+}
+
+// WithCacheTransform configures the cache to transform objects of the supplied
+// type before caching them. Transforms are chained if several are supplied for
+// the same type.
+func WithCacheTransform(obj client.Object, fn toolscache.TransformFunc) CacheOption {
+	return func(o *cache.Options) {
+		if o.TransformByObject == nil {
+			o.TransformByObject = cache.TransformByObject{}
+		}
+		o.TransformByObject[obj] = ChainTransforms(o.TransformByObject[obj], fn)
+	}
+}
+
+// WithDefaultCacheTransform configures the cache to transform objects of types
+// that have no transform configured using WithCacheTransform before caching
+// them. Transforms are chained if several are supplied.
+func WithDefaultCacheTransform(fn toolscache.TransformFunc) CacheOption {
+	return func(o *cache.Options) {
+		o.DefaultTransform = ChainTransforms(o.DefaultTransform, fn)
+	}
+}
+
+// NewCacheFunc returns a function that creates an informer cache configured
+// with the supplied options, for use as manager.Options.NewCache.
+func NewCacheFunc(o ...CacheOption) cache.NewCacheFunc {
+	opts := cache.Options{}
+	for _, fn := range o {
+		fn(&opts)
+	}
+	return cache.BuilderWithOptions(opts)
+}
+
+// ChainTransforms returns a transform that calls each of the supplied
+// transforms in order. Nil transforms are ignored.
+func ChainTransforms(fns ...toolscache.TransformFunc) toolscache.TransformFunc {
+	return func(obj any) (any, error) {
+		var err error
+		for _, fn := range fns {
+			if fn == nil {
+				continue
+			}
+			if obj, err = fn(obj); err != nil {
+				return nil, err
+			}
+		}
+		return obj, nil
+	}
+}
+
+// StripManagedFields returns a transform that removes the managed fields of
+// objects before they are cached. Managed fields are often a large part of an
+// object, and are rarely read by controllers. It's safe to update an object
+// read from the cache; the API server retains the managed fields of an object
+// that is updated without them.
+func StripManagedFields() toolscache.TransformFunc {
+	return func(obj any) (any, error) {
+		if a, err := kmeta.Accessor(obj); err == nil {
+			a.SetManagedFields(nil)
+		}
+		return obj, nil
+	}
+}
+
+// StripAnnotations returns a transform that removes the supplied annotations
+// of objects before they are cached, for example the large
+// kubectl.kubernetes.io/last-applied-configuration annotation. Don't strip
+// annotations from types the controller updates; updating an object read
+// from the cache would remove the annotations from the API server.
+func StripAnnotations(keys ...string) toolscache.TransformFunc {
+	return func(obj any) (any, error) {
+		a, err := kmeta.Accessor(obj)
+		if err != nil {
+			return obj, nil //nolint:nilerr // Objects that aren't Kubernetes objects are cached as is.
+		}
+		an := a.GetAnnotations()
+		if len(an) == 0 {
+			return obj, nil
+		}
+		for _, k := range keys {
+			delete(an, k)
+		}
+		a.SetAnnotations(an)
+		return obj, nil
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	toolscache "k8s.io/client-go/tools/cache"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestTransforms(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		obj any
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		fn     toolscache.TransformFunc
+		obj    any
+		want   want
+	}{
+		"StripManagedFields": {
+			reason: "StripManagedFields should remove managed fields.",
+			fn:     StripManagedFields(),
+			obj: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+				Name:          "cool",
+				ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+			}},
+			want: want{
+				obj: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "cool"}},
+			},
+		},
+		"StripAnnotations": {
+			reason: "StripAnnotations should remove only the supplied annotations.",
+			fn:     StripAnnotations(corev1.LastAppliedConfigAnnotation),
+			obj: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+				Name: "cool",
+				Annotations: map[string]string{
+					corev1.LastAppliedConfigAnnotation: "{}",
+					"keep":                             "me",
+				},
+			}},
+			want: want{
+				obj: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+					Name:        "cool",
+					Annotations: map[string]string{"keep": "me"},
+				}},
+			},
+		},
+		"NotAnObject": {
+			reason: "Transforms should return values that aren't Kubernetes objects unchanged.",
+			fn:     ChainTransforms(StripManagedFields(), StripAnnotations("a")),
+			obj:    "cool",
+			want: want{
+				obj: "cool",
+			},
+		},
+		"Chain": {
+			reason: "ChainTransforms should call each transform in order, ignoring nil transforms.",
+			fn:     ChainTransforms(nil, StripManagedFields(), StripAnnotations("a")),
+			obj: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+				Name:          "cool",
+				Annotations:   map[string]string{"a": "b"},
+				ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+			}},
+			want: want{
+				obj: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+					Name:        "cool",
+					Annotations: map[string]string{},
+				}},
+			},
+		},
+		"ChainError": {
+			reason: "ChainTransforms should return errors from transforms.",
+			fn: ChainTransforms(StripManagedFields(), func(_ any) (any, error) {
+				return nil, errBoom
+			}),
+			obj: &corev1.Secret{},
+			want: want{
+				err: errBoom,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.fn(tc.obj)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nfn(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.obj, got); diff != "" {
+				t.Errorf("\n%s\nfn(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
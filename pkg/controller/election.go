@@ -0,0 +1,291 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errNewClientset = "cannot create Kubernetes clientset"
+	errNewLock      = "cannot create leader election lock"
+	errNewElector   = "cannot create leader elector"
+	errReleaseLease = "cannot release leader election lease"
+)
+
+// Default leader election durations. These match controller-runtime's.
+const (
+	defaultLeaseDuration = 15 * time.Second
+	defaultRenewDeadline = 10 * time.Second
+	defaultRetryPeriod   = 2 * time.Second
+
+	// defaultDrainTimeout is how long to wait for a group's controllers to
+	// finish their in-flight reconciles by default. It's longer than the
+	// managed reconciler's timeout.
+	defaultDrainTimeout = 2 * time.Minute
+)
+
+// A ControllerStarter starts and stops named controllers.
+type ControllerStarter interface {
+	// Start the named controller with the supplied options and watches.
+	Start(name string, o controller.Options, w ...Watch) error
+
+	// StopAndWait stops the named controller, and waits until any
+	// reconciles it was running have finished or the context is done.
+	StopAndWait(ctx context.Context, name string) error
+}
+
+type groupController struct {
+	name    string
+	options controller.Options
+	watches []Watch
+}
+
+// An ElectionGroupOption configures an ElectionGroup.
+type ElectionGroupOption func(g *ElectionGroup)
+
+// WithElectionDurations configures how long the ElectionGroup's lease lasts,
+// how long the leader tries to renew it before giving up, and how long to
+// wait between attempts to acquire or renew it.
+func WithElectionDurations(lease, renew, retry time.Duration) ElectionGroupOption {
+	return func(g *ElectionGroup) {
+		g.leaseDuration = lease
+		g.renewDeadline = renew
+		g.retryPeriod = retry
+	}
+}
+
+// WithDrainTimeout configures how long the ElectionGroup waits for its
+// controllers to finish their in-flight reconciles once it stops leading. A
+// group that stops leading because it is shutting down releases its lease only
+// if its controllers finished within this time.
+func WithDrainTimeout(d time.Duration) ElectionGroupOption {
+	return func(g *ElectionGroup) {
+		g.drainTimeout = d
+	}
+}
+
+// WithElectionLogger configures the logger used by the ElectionGroup.
+func WithElectionLogger(l logging.Logger) ElectionGroupOption {
+	return func(g *ElectionGroup) {
+		g.log = l
+	}
+}
+
+// An ElectionGroup runs a set of controllers only while it holds its own
+// leader election lease. Splitting the controllers of one process into
+// several groups, e.g. one for kinds that are expensive to reconcile and one
+// for the rest, means a lease lost by, or a slow shutdown of, one group
+// doesn't stall the others. A group that loses its lease stops its
+// controllers and campaigns for the lease again, rather than exiting.
+//
+// A group waits for its controllers to finish their in-flight reconciles
+// before it campaigns again, and before it releases its lease when it shuts
+// down, so that another process doesn't start reconciling the same resources
+// while they're still being reconciled.
+//
+// An ElectionGroup is a controller-runtime Runnable. Add it to a manager that
+// has leader election disabled, so that the controllers it starts using an
+// Engine are not also subject to the manager's lease.
+type ElectionGroup struct {
+	name        string
+	lock        resourcelock.Interface
+	starter     ControllerStarter
+	controllers []groupController
+	mx          sync.Mutex
+
+	// drained is true if the group's controllers finished their in-flight
+	// reconciles the last time they were stopped.
+	drained bool
+
+	leaseDuration time.Duration
+	renewDeadline time.Duration
+	retryPeriod   time.Duration
+	drainTimeout  time.Duration
+
+	log logging.Logger
+}
+
+// NewElectionGroup returns an ElectionGroup that uses the supplied lock to
+// elect a leader, and the supplied ControllerStarter (typically an Engine) to
+// start and stop its controllers.
+func NewElectionGroup(name string, lock resourcelock.Interface, s ControllerStarter, o ...ElectionGroupOption) *ElectionGroup {
+	g := &ElectionGroup{
+		name:          name,
+		lock:          lock,
+		starter:       s,
+		leaseDuration: defaultLeaseDuration,
+		renewDeadline: defaultRenewDeadline,
+		retryPeriod:   defaultRetryPeriod,
+		drainTimeout:  defaultDrainTimeout,
+		log:           logging.NewNopLogger(),
+	}
+	for _, fn := range o {
+		fn(g)
+	}
+	return g
+}
+
+// Add the named controller to the group. It is started with the supplied
+// options and watches each time the group is elected. Add must be called
+// before the group is started.
+func (g *ElectionGroup) Add(name string, o controller.Options, w ...Watch) {
+	g.controllers = append(g.controllers, groupController{name: name, options: o, watches: w})
+}
+
+// NeedLeaderElection returns false, because an ElectionGroup holds its own
+// lease.
+func (g *ElectionGroup) NeedLeaderElection() bool {
+	return false
+}
+
+// Start campaigning for the group's lease. The group's controllers are
+// started when it is acquired, and stopped when it is lost. Start blocks
+// until the supplied context is done, then stops the group's controllers and
+// releases the lease.
+func (g *ElectionGroup) Start(ctx context.Context) error {
+	for {
+		le, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+			Lock:          g.lock,
+			Name:          g.name,
+			LeaseDuration: g.leaseDuration,
+			RenewDeadline: g.renewDeadline,
+			RetryPeriod:   g.retryPeriod,
+			Callbacks: leaderelection.LeaderCallbacks{
+				OnStartedLeading: g.startControllers,
+				OnStoppedLeading: g.stopControllers,
+			},
+			// The elector would release the lease before calling
+			// OnStoppedLeading, i.e. before our controllers have
+			// stopped. We release it ourselves once they have.
+			ReleaseOnCancel: false,
+		})
+		if err != nil {
+			return errors.Wrap(err, errNewElector)
+		}
+
+		// Run returns when the lease is lost, or the context is done. In
+		// either case it first calls stopControllers, which waits for our
+		// controllers to stop.
+		le.Run(ctx)
+		if ctx.Err() != nil {
+			// The lease will expire if we can't release it.
+			if err := g.release(); err != nil {
+				g.log.Info("Cannot release leader election lease", "group", g.name, "error", err)
+			}
+			return nil
+		}
+		g.log.Info("Lost leader election lease, campaigning again", "group", g.name)
+	}
+}
+
+// startControllers is called in a goroutine once the lease is acquired. The
+// supplied context is cancelled when the lease is lost, before
+// stopControllers is called.
+func (g *ElectionGroup) startControllers(ctx context.Context) {
+	g.mx.Lock()
+	defer g.mx.Unlock()
+
+	g.log.Debug("Acquired leader election lease, starting controllers", "group", g.name)
+	for _, c := range g.controllers {
+		// Don't start controllers after we've lost the lease; they'd never
+		// be stopped.
+		if ctx.Err() != nil {
+			return
+		}
+		if err := g.starter.Start(c.name, c.options, c.watches...); err != nil {
+			g.log.Info("Cannot start controller", "group", g.name, "controller", c.name, "error", err)
+		}
+	}
+}
+
+// stopControllers is called when the lease is lost, or the group is shutting
+// down. It waits for the group's controllers to stop, or the drain timeout.
+func (g *ElectionGroup) stopControllers() {
+	g.mx.Lock()
+	defer g.mx.Unlock()
+
+	g.log.Debug("Stopping controllers", "group", g.name)
+	ctx, cancel := context.WithTimeout(context.Background(), g.drainTimeout)
+	defer cancel()
+
+	g.drained = true
+	for _, c := range g.controllers {
+		if err := g.starter.StopAndWait(ctx, c.name); err != nil {
+			g.log.Info("Controller did not stop within the drain timeout", "group", g.name, "controller", c.name, "error", err)
+			g.drained = false
+		}
+	}
+}
+
+// release the group's lease if we hold it and our controllers have stopped,
+// so that another process can acquire it without waiting for it to expire.
+// The lease is left to expire if our controllers might still be running.
+func (g *ElectionGroup) release() error {
+	g.mx.Lock()
+	defer g.mx.Unlock()
+	if !g.drained {
+		return nil
+	}
+
+	// The context passed to Start is done, so we need a new one.
+	ctx, cancel := context.WithTimeout(context.Background(), g.renewDeadline)
+	defer cancel()
+
+	ler, _, err := g.lock.Get(ctx)
+	if err != nil {
+		return errors.Wrap(resource.IgnoreNotFound(err), errReleaseLease)
+	}
+	if ler.HolderIdentity != g.lock.Identity() {
+		return nil
+	}
+
+	// This mirrors how the leader elector releases a lease.
+	now := metav1.Now()
+	return errors.Wrap(g.lock.Update(ctx, resourcelock.LeaderElectionRecord{
+		LeaderTransitions:    ler.LeaderTransitions,
+		LeaseDurationSeconds: 1,
+		RenewTime:            now,
+		AcquireTime:          now,
+	}), errReleaseLease)
+}
+
+// NewLeaseLock returns a leader election lock that uses the named Lease in
+// the supplied namespace. The identity distinguishes the processes that
+// contend for the lease; it is typically the name of the pod.
+func NewLeaseLock(cfg *rest.Config, namespace, name, identity string) (resourcelock.Interface, error) {
+	cs, err := kubernetes.NewForConfig(rest.AddUserAgent(cfg, "leader-election"))
+	if err != nil {
+		return nil, errors.Wrap(err, errNewClientset)
+	}
+	l, err := resourcelock.New(resourcelock.LeasesResourceLock, namespace, name, cs.CoreV1(), cs.CoordinationV1(), resourcelock.ResourceLockConfig{Identity: identity})
+	return l, errors.Wrap(err, errNewLock)
+}
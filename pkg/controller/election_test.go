@@ -0,0 +1,255 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// A journal records events in the order they happen.
+type journal struct {
+	mx     sync.Mutex
+	events []string
+}
+
+func (j *journal) Record(e string) {
+	j.mx.Lock()
+	defer j.mx.Unlock()
+	j.events = append(j.events, e)
+}
+
+func (j *journal) Events() []string {
+	j.mx.Lock()
+	defer j.mx.Unlock()
+	return append([]string{}, j.events...)
+}
+
+// A memoryLock is a leader election lock held in memory. It records when it
+// is released, and when it is acquired after writes to it failed.
+type memoryLock struct {
+	id      string
+	journal *journal
+
+	mx     sync.Mutex
+	ler    *resourcelock.LeaderElectionRecord
+	fail   bool
+	failed bool
+}
+
+func (l *memoryLock) Fail(fail bool) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.fail = fail
+}
+
+func (l *memoryLock) Get(_ context.Context) (*resourcelock.LeaderElectionRecord, []byte, error) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	if l.ler == nil {
+		return nil, nil, kerrors.NewNotFound(schema.GroupResource{Group: "coordination.k8s.io", Resource: "leases"}, "lock")
+	}
+	r := *l.ler
+	return &r, []byte(r.HolderIdentity + r.RenewTime.String()), nil
+}
+
+func (l *memoryLock) Create(_ context.Context, ler resourcelock.LeaderElectionRecord) error {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.ler = &ler
+	return nil
+}
+
+func (l *memoryLock) Update(_ context.Context, ler resourcelock.LeaderElectionRecord) error {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	if l.fail {
+		l.failed = true
+		return errors.New("boom")
+	}
+	switch {
+	case ler.HolderIdentity == "":
+		l.journal.Record("release")
+	case l.failed:
+		l.journal.Record("acquire")
+		l.failed = false
+	}
+	l.ler = &ler
+	return nil
+}
+
+func (l *memoryLock) RecordEvent(string) {}
+func (l *memoryLock) Identity() string   { return l.id }
+func (l *memoryLock) Describe() string   { return "memory/lock" }
+
+// A recordingStarter records the controllers it starts and stops. Controllers
+// take a little while to stop, as if they were finishing in-flight reconciles.
+type recordingStarter struct {
+	journal *journal
+	err     error
+}
+
+func (s *recordingStarter) Start(name string, _ controller.Options, _ ...Watch) error {
+	s.journal.Record("start " + name)
+	return s.err
+}
+
+func (s *recordingStarter) StopAndWait(_ context.Context, name string) error {
+	time.Sleep(20 * time.Millisecond)
+	s.journal.Record("stop " + name)
+	return nil
+}
+
+// waitFor polls until the supplied function returns true, or a deadline.
+func waitFor(fn func() bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !fn() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func count(events []string, prefix string) int {
+	n := 0
+	for _, e := range events {
+		if strings.HasPrefix(e, prefix) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestElectionGroup(t *testing.T) {
+	type args struct {
+		ler *resourcelock.LeaderElectionRecord
+		err error
+
+		// starts is the number of controllers we expect to be started.
+		starts int
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []string
+	}{
+		"Elected": {
+			reason: "The group's controllers should be started when it acquires the lease, and stopped before it releases the lease when it's done.",
+			args: args{
+				starts: 2,
+			},
+			want: []string{"start a", "start b", "stop a", "stop b", "release"},
+		},
+		"CannotStart": {
+			reason: "The group should start its other controllers if one can't be started.",
+			args: args{
+				err:    errors.New("boom"),
+				starts: 2,
+			},
+			want: []string{"start a", "start b", "stop a", "stop b", "release"},
+		},
+		"NotElected": {
+			reason: "The group's controllers should not be started, nor the lease released, while another process holds the lease.",
+			args: args{
+				ler: &resourcelock.LeaderElectionRecord{
+					HolderIdentity:       "you",
+					LeaseDurationSeconds: 60,
+					AcquireTime:          metav1.Now(),
+					RenewTime:            metav1.Now(),
+				},
+			},
+			want: []string{"stop a", "stop b"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			j := &journal{}
+			s := &recordingStarter{journal: j, err: tc.args.err}
+			l := &memoryLock{id: "me", journal: j, ler: tc.args.ler}
+			g := NewElectionGroup("heavy", l, s, WithElectionDurations(time.Second, 500*time.Millisecond, 50*time.Millisecond))
+			g.Add("a", controller.Options{})
+			g.Add("b", controller.Options{})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error)
+			go func() { done <- g.Start(ctx) }()
+
+			// Give the group a chance to acquire the lease and start its
+			// controllers.
+			waitFor(func() bool { return count(j.Events(), "start") >= tc.args.starts })
+			if tc.args.starts == 0 {
+				time.Sleep(300 * time.Millisecond)
+			}
+			cancel()
+			if err := <-done; err != nil {
+				t.Errorf("\n%s\ng.Start(...): %s", tc.reason, err)
+			}
+
+			if diff := cmp.Diff(tc.want, j.Events()); diff != "" {
+				t.Errorf("\n%s\ng.Start(...): -want events, +got events:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestElectionGroupLostLease(t *testing.T) {
+	reason := "A group that loses its lease should wait for its controllers to stop before it campaigns for the lease again."
+
+	j := &journal{}
+	s := &recordingStarter{journal: j}
+	l := &memoryLock{id: "me", journal: j}
+	g := NewElectionGroup("heavy", l, s, WithElectionDurations(time.Second, 500*time.Millisecond, 50*time.Millisecond))
+	g.Add("a", controller.Options{})
+	g.Add("b", controller.Options{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- g.Start(ctx) }()
+
+	// Lose the lease by failing to renew it until our controllers have
+	// stopped, then let the group acquire it again.
+	waitFor(func() bool { return count(j.Events(), "start") >= 2 })
+	l.Fail(true)
+	waitFor(func() bool { return count(j.Events(), "stop") >= 2 })
+	l.Fail(false)
+	waitFor(func() bool { return count(j.Events(), "start") >= 4 })
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("\n%s\ng.Start(...): %s", reason, err)
+	}
+
+	want := []string{
+		"start a", "start b", "stop a", "stop b",
+		"acquire", "start a", "start b", "stop a", "stop b",
+		"release",
+	}
+	if diff := cmp.Diff(want, j.Events()); diff != "" {
+		t.Errorf("\n%s\ng.Start(...): -want events, +got events:\n%s", reason, diff)
+	}
+}
@@ -20,6 +20,7 @@ package controller
 import (
 	"context"
 	"sync"
+	"time"
 
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
@@ -40,6 +41,7 @@ const (
 	errCrashCache       = "cache error"
 	errCrashController  = "controller error"
 	errWatch            = "cannot setup watch"
+	errStopController   = "cannot wait for controller to stop"
 )
 
 // A NewCacheFn creates a new controller-runtime cache.
@@ -61,6 +63,7 @@ type Engine struct {
 	mgr manager.Manager
 
 	started map[string]context.CancelFunc
+	stopped map[string]chan struct{}
 	errors  map[string]error
 	mx      sync.RWMutex
 
@@ -93,6 +96,7 @@ func NewEngine(mgr manager.Manager, o ...EngineOption) *Engine {
 		mgr: mgr,
 
 		started: make(map[string]context.CancelFunc),
+		stopped: make(map[string]chan struct{}),
 		errors:  make(map[string]error),
 
 		newCache: DefaultNewCacheFn,
@@ -130,6 +134,26 @@ func (e *Engine) Stop(name string) {
 	e.done(name, nil)
 }
 
+// StopAndWait stops the named controller, and waits until it has returned -
+// i.e. until any reconciles it was running have finished - or until the
+// supplied context is done.
+func (e *Engine) StopAndWait(ctx context.Context, name string) error {
+	e.mx.RLock()
+	stopped, ok := e.stopped[name]
+	e.mx.RUnlock()
+
+	e.Stop(name)
+	if !ok {
+		return nil
+	}
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), errStopController)
+	}
+}
+
 func (e *Engine) done(name string, err error) {
 	e.mx.Lock()
 	defer e.mx.Unlock()
@@ -152,6 +176,7 @@ type Watch struct {
 	kind       client.Object
 	handler    handler.EventHandler
 	predicates []predicate.Predicate
+	resync     time.Duration
 }
 
 // For returns a Watch for the supplied kind of object. Events will be handled
@@ -160,6 +185,13 @@ func For(kind client.Object, h handler.EventHandler, p ...predicate.Predicate) W
 	return Watch{kind: kind, handler: h, predicates: p}
 }
 
+// WithResyncPeriod returns a copy of the Watch that resyncs every object of
+// its kind at the supplied period, rather than at the cache's resync period.
+func (w Watch) WithResyncPeriod(d time.Duration) Watch {
+	w.resync = d
+	return w
+}
+
 // Start the named controller. Each controller is started with its own cache
 // whose lifecycle is coupled to the controller. The controller is started with
 // the supplied options, and configured with the supplied watches. Start does
@@ -191,7 +223,11 @@ func (e *Engine) Start(name string, o controller.Options, w ...Watch) error {
 	}
 
 	for _, wt := range w {
-		if err := ctrl.Watch(source.NewKindWithCache(wt.kind, ca), wt.handler, wt.predicates...); err != nil {
+		var src source.Source = source.NewKindWithCache(wt.kind, ca)
+		if wt.resync > 0 {
+			src = NewKindWithResync(wt.kind, ca, wt.resync)
+		}
+		if err := ctrl.Watch(src, wt.handler, wt.predicates...); err != nil {
 			return errors.Wrap(err, errWatch)
 		}
 	}
@@ -200,8 +236,25 @@ func (e *Engine) Start(name string, o controller.Options, w ...Watch) error {
 		<-e.mgr.Elected()
 		e.done(name, errors.Wrap(ca.Start(ctx), errCrashCache))
 	}()
+	stopped := make(chan struct{})
+	e.mx.Lock()
+	e.stopped[name] = stopped
+	e.mx.Unlock()
+
 	go func() {
-		<-e.mgr.Elected()
+		defer close(stopped)
+		select {
+		case <-e.mgr.Elected():
+		case <-ctx.Done():
+			// We were stopped before we were elected, unless we were
+			// elected at the same time.
+			select {
+			case <-e.mgr.Elected():
+			default:
+				return
+			}
+		}
+		// Start returns once the controller's workers have finished.
 		e.done(name, errors.Wrap(ctrl.Start(ctx), errCrashController))
 	}()
 
@@ -120,6 +120,31 @@ func TestEngine(t *testing.T) {
 				err: errors.Wrap(errBoom, errWatch),
 			},
 		},
+		"WatchWithResync": {
+			reason: "Watches with a resync period should use a source that resyncs at that period",
+			e: NewEngine(&fake.Manager{},
+				WithNewCacheFn(func(*rest.Config, cache.Options) (cache.Cache, error) {
+					return &MockCache{MockStart: func(context.Context) error { return nil }}, nil
+				}),
+				WithNewControllerFn(func(string, manager.Manager, controller.Options) (controller.Controller, error) {
+					c := &MockController{
+						MockStart: func(context.Context) error { return nil },
+						MockWatch: func(s source.Source, _ handler.EventHandler, _ ...predicate.Predicate) error {
+							if _, ok := s.(*resyncKind); !ok {
+								return errBoom
+							}
+							return nil
+						},
+					}
+					return c, nil
+				}),
+			),
+			args: args{
+				name: "coolcontroller",
+				w:    []Watch{For(&fake.Managed{}, nil).WithResyncPeriod(time.Hour)},
+			},
+			want: want{},
+		},
 		"CacheCrashError": {
 			reason: "Errors starting or running a cache should be returned",
 			e: NewEngine(&fake.Manager{},
@@ -184,3 +209,81 @@ func TestEngine(t *testing.T) {
 		})
 	}
 }
+
+func TestEngineStopAndWait(t *testing.T) {
+	type want struct {
+		err     error
+		drained bool
+	}
+	cases := map[string]struct {
+		reason  string
+		drain   time.Duration
+		timeout time.Duration
+		want    want
+	}{
+		"Drained": {
+			reason:  "StopAndWait should return once the controller has returned.",
+			drain:   50 * time.Millisecond,
+			timeout: 5 * time.Second,
+			want: want{
+				drained: true,
+			},
+		},
+		"Timeout": {
+			reason:  "StopAndWait should return an error if the controller does not return before the context is done.",
+			drain:   5 * time.Second,
+			timeout: 50 * time.Millisecond,
+			want: want{
+				err: errors.Wrap(context.DeadlineExceeded, errStopController),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			drain := tc.drain
+			started := make(chan struct{})
+			drained := make(chan struct{})
+			e := NewEngine(&fake.Manager{},
+				WithNewCacheFn(func(*rest.Config, cache.Options) (cache.Cache, error) {
+					return &MockCache{MockStart: func(ctx context.Context) error {
+						<-ctx.Done()
+						return nil
+					}}, nil
+				}),
+				WithNewControllerFn(func(string, manager.Manager, controller.Options) (controller.Controller, error) {
+					c := &MockController{MockStart: func(ctx context.Context) error {
+						close(started)
+						<-ctx.Done()
+						// Simulate finishing in-flight reconciles.
+						time.Sleep(drain)
+						close(drained)
+						return nil
+					}}
+					return c, nil
+				}),
+			)
+			if err := e.Start("coolcontroller", controller.Options{}); err != nil {
+				t.Fatalf("\n%s\ne.Start(...): %s", tc.reason, err)
+			}
+			<-started
+
+			ctx, cancel := context.WithTimeout(context.Background(), tc.timeout)
+			defer cancel()
+			err := e.StopAndWait(ctx, "coolcontroller")
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.StopAndWait(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			got := false
+			select {
+			case <-drained:
+				got = true
+			default:
+			}
+			if diff := cmp.Diff(tc.want.drained, got); diff != "" {
+				t.Errorf("\n%s\ne.StopAndWait(...): -want drained, +got drained:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -17,15 +17,22 @@ limitations under the License.
 package controller
 
 import (
+	"context"
 	"crypto/tls"
 	"time"
 
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
+	"github.com/crossplane/crossplane-runtime/pkg/certificates"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/feature"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/shard"
 )
 
 // DefaultOptions returns a functional set of options with conservative
@@ -34,6 +41,7 @@ func DefaultOptions() Options {
 	return Options{
 		Logger:                  logging.NewNopLogger(),
 		GlobalRateLimiter:       ratelimiter.NewGlobal(1),
+		RateLimiters:            ratelimiter.NewRegistry(),
 		PollInterval:            1 * time.Minute,
 		MaxConcurrentReconciles: 1,
 		Features:                &feature.Flags{},
@@ -49,6 +57,10 @@ type Options struct {
 	// reconciles across all controllers will be subject to this limit.
 	GlobalRateLimiter workqueue.RateLimiter
 
+	// RateLimiters shared by all controllers of this controller manager, for
+	// example to limit calls to an external API endpoint or account.
+	RateLimiters *ratelimiter.Registry
+
 	// PollInterval at which each controller should speculatively poll to
 	// determine whether it has work to do.
 	PollInterval time.Duration
@@ -61,6 +73,18 @@ type Options struct {
 
 	// ESSOptions for External Secret Stores.
 	ESSOptions *ESSOptions
+
+	// Shard of resources controllers should reconcile. Controllers reconcile
+	// all resources if Shard is nil. Use ShardPredicate to filter watches,
+	// and managed.WithShard to ignore requests for resources of other shards
+	// that are enqueued by watches of other kinds.
+	Shard *shard.Shard
+
+	// ResyncPeriods of kinds of resources. Every resource of a kind is
+	// resynced at its period, for example to use long resyncs for kinds that
+	// are expensive to reconcile. Kinds that aren't configured are resynced at
+	// the cache's resync period. Use Source to watch a kind.
+	ResyncPeriods map[schema.GroupKind]time.Duration
 }
 
 // ForControllerRuntime extracts options for controller-runtime.
@@ -71,8 +95,33 @@ func (o Options) ForControllerRuntime() controller.Options {
 	}
 }
 
+// ShardPredicate returns a predicate that accepts only events for objects in
+// the configured Shard, or all events if no Shard is configured.
+func (o Options) ShardPredicate() predicate.Predicate {
+	if o.Shard == nil {
+		return predicate.NewPredicateFuncs(func(client.Object) bool { return true })
+	}
+	return o.Shard.Predicate()
+}
+
+const errLoadESSTLSConfig = "cannot load External Secret Store TLS config"
+
 // ESSOptions for External Secret Stores.
 type ESSOptions struct {
+	// TLSConfig used to connect to External Secret Store plugins. Use
+	// NewESSOptions to hot-reload renewed TLS material.
 	TLSConfig     *tls.Config
 	TLSSecretName *string
 }
+
+// NewESSOptions returns ESSOptions that connect to External Secret Store
+// plugins using the latest TLS material from the supplied Source, for example
+// a cert-manager issued Secret. The TLS material is reloaded when it changes
+// until the supplied context is done.
+func NewESSOptions(ctx context.Context, src certificates.Source) (*ESSOptions, error) {
+	cfg, err := src.TLSConfig(ctx, false)
+	if err != nil {
+		return nil, errors.Wrap(err, errLoadESSTLSConfig)
+	}
+	return &ESSOptions{TLSConfig: cfg}, nil
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// Error strings.
+const (
+	errGetInformer     = "cannot get informer"
+	errAddEventHandler = "cannot add event handler"
+	errSyncCache       = "cannot sync cache"
+	errGetGVK          = "cannot determine kind of object"
+)
+
+// NewKindWithResync returns a source of events for the supplied kind of object
+// that are read from the supplied cache. Unlike controller-runtime's Kind
+// source, every object of the kind is resynced - i.e. an update event is
+// emitted for it - at the supplied period, rather than at the resync period
+// of the whole cache. A period of zero uses the cache's resync period.
+//
+// Note that an informer can't resync more often than the shortest resync
+// period it had when it was started. Use periods that are longer than the
+// cache's resync period, or add all watches of a kind before the cache is
+// started.
+func NewKindWithResync(kind client.Object, c cache.Cache, period time.Duration) source.SyncingSource {
+	return &resyncKind{kind: kind, cache: c, period: period}
+}
+
+type resyncKind struct {
+	kind   client.Object
+	cache  cache.Cache
+	period time.Duration
+}
+
+// Start adds an event handler with the configured resync period to the
+// informer for the configured kind.
+func (k *resyncKind) Start(ctx context.Context, h handler.EventHandler, q workqueue.RateLimitingInterface, p ...predicate.Predicate) error {
+	i, err := k.cache.GetInformer(ctx, k.kind)
+	if err != nil {
+		return errors.Wrap(err, errGetInformer)
+	}
+	_, err = i.AddEventHandlerWithResyncPeriod(eventHandler{handler: h, queue: q, predicates: p}, k.period)
+	return errors.Wrap(err, errAddEventHandler)
+}
+
+// WaitForSync blocks until the cache is synced.
+func (k *resyncKind) WaitForSync(ctx context.Context) error {
+	if !k.cache.WaitForCacheSync(ctx) {
+		return errors.New(errSyncCache)
+	}
+	return nil
+}
+
+func (k *resyncKind) String() string {
+	return fmt.Sprintf("kind source: %T, resync period: %s", k.kind, k.period)
+}
+
+// eventHandler adapts a controller-runtime EventHandler to an informer's
+// ResourceEventHandler.
+type eventHandler struct {
+	handler    handler.EventHandler
+	queue      workqueue.RateLimitingInterface
+	predicates []predicate.Predicate
+}
+
+func (e eventHandler) OnAdd(obj any) {
+	o, ok := obj.(client.Object)
+	if !ok {
+		return
+	}
+	ev := event.CreateEvent{Object: o}
+	for _, p := range e.predicates {
+		if !p.Create(ev) {
+			return
+		}
+	}
+	e.handler.Create(ev, e.queue)
+}
+
+func (e eventHandler) OnUpdate(oldObj, newObj any) {
+	oo, ok := oldObj.(client.Object)
+	if !ok {
+		return
+	}
+	no, ok := newObj.(client.Object)
+	if !ok {
+		return
+	}
+	ev := event.UpdateEvent{ObjectOld: oo, ObjectNew: no}
+	for _, p := range e.predicates {
+		if !p.Update(ev) {
+			return
+		}
+	}
+	e.handler.Update(ev, e.queue)
+}
+
+func (e eventHandler) OnDelete(obj any) {
+	// Objects that were deleted while the informer was disconnected are
+	// delivered wrapped in a tombstone.
+	if t, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+		obj = t.Obj
+	}
+	o, ok := obj.(client.Object)
+	if !ok {
+		return
+	}
+	ev := event.DeleteEvent{Object: o}
+	for _, p := range e.predicates {
+		if !p.Delete(ev) {
+			return
+		}
+	}
+	e.handler.Delete(ev, e.queue)
+}
+
+// ResyncPeriodFor returns the resync period configured for the supplied kind,
+// or zero if the kind should use the cache's resync period.
+func (o Options) ResyncPeriodFor(gk schema.GroupKind) time.Duration {
+	return o.ResyncPeriods[gk]
+}
+
+// Source returns a source of events for the supplied kind of object that are
+// read from the supplied cache, resynced at the period configured for the
+// kind. The supplied scheme is used to determine the kind of the object.
+func (o Options) Source(c cache.Cache, s *runtime.Scheme, kind client.Object) (source.SyncingSource, error) {
+	gvk, err := apiutil.GVKForObject(kind, s)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetGVK)
+	}
+	if d := o.ResyncPeriodFor(gvk.GroupKind()); d > 0 {
+		return NewKindWithResync(kind, c, d), nil
+	}
+	return source.NewKindWithCache(kind, c), nil
+}
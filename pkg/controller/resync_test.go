@@ -0,0 +1,238 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+type MockInformer struct {
+	cache.Informer
+
+	MockAddEventHandlerWithResyncPeriod func(h toolscache.ResourceEventHandler, d time.Duration) (toolscache.ResourceEventHandlerRegistration, error)
+}
+
+func (i *MockInformer) AddEventHandlerWithResyncPeriod(h toolscache.ResourceEventHandler, d time.Duration) (toolscache.ResourceEventHandlerRegistration, error) {
+	return i.MockAddEventHandlerWithResyncPeriod(h, d)
+}
+
+type MockInformerCache struct {
+	cache.Cache
+
+	MockGetInformer func(ctx context.Context, obj client.Object) (cache.Informer, error)
+}
+
+func (c *MockInformerCache) GetInformer(ctx context.Context, obj client.Object) (cache.Informer, error) {
+	return c.MockGetInformer(ctx, obj)
+}
+
+func TestResyncKindStart(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		err    error
+		period time.Duration
+	}
+
+	cases := map[string]struct {
+		reason string
+		c      cache.Cache
+		want   want
+	}{
+		"GetInformerError": {
+			reason: "Errors getting the informer should be returned.",
+			c: &MockInformerCache{MockGetInformer: func(_ context.Context, _ client.Object) (cache.Informer, error) {
+				return nil, errBoom
+			}},
+			want: want{
+				err: errors.Wrap(errBoom, errGetInformer),
+			},
+		},
+		"AddEventHandlerError": {
+			reason: "Errors adding the event handler should be returned.",
+			c: &MockInformerCache{MockGetInformer: func(_ context.Context, _ client.Object) (cache.Informer, error) {
+				return &MockInformer{MockAddEventHandlerWithResyncPeriod: func(_ toolscache.ResourceEventHandler, _ time.Duration) (toolscache.ResourceEventHandlerRegistration, error) {
+					return nil, errBoom
+				}}, nil
+			}},
+			want: want{
+				err: errors.Wrap(errBoom, errAddEventHandler),
+			},
+		},
+		"Success": {
+			reason: "The event handler should be added with the configured resync period.",
+			c: &MockInformerCache{MockGetInformer: func(_ context.Context, _ client.Object) (cache.Informer, error) {
+				return &MockInformer{MockAddEventHandlerWithResyncPeriod: func(_ toolscache.ResourceEventHandler, d time.Duration) (toolscache.ResourceEventHandlerRegistration, error) {
+					if diff := cmp.Diff(time.Hour, d); diff != "" {
+						t.Errorf("AddEventHandlerWithResyncPeriod(...): -want period, +got period:\n%s", diff)
+					}
+					return nil, nil
+				}}, nil
+			}},
+			want: want{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			src := NewKindWithResync(&corev1.Secret{}, tc.c, time.Hour)
+			err := src.Start(context.Background(), &handler.EnqueueRequestForObject{}, nil)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nStart(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestEventHandler(t *testing.T) {
+	obj := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "cool"}}
+	reject := predicate.Funcs{
+		CreateFunc: func(event.CreateEvent) bool { return false },
+		UpdateFunc: func(event.UpdateEvent) bool { return false },
+		DeleteFunc: func(event.DeleteEvent) bool { return false },
+	}
+
+	type args struct {
+		p  []predicate.Predicate
+		fn func(e eventHandler)
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []string
+	}{
+		"Add": {
+			reason: "Added objects should be handled as create events.",
+			args: args{
+				fn: func(e eventHandler) { e.OnAdd(obj) },
+			},
+			want: []string{"Create"},
+		},
+		"Update": {
+			reason: "Updated, and resynced, objects should be handled as update events.",
+			args: args{
+				fn: func(e eventHandler) { e.OnUpdate(obj, obj) },
+			},
+			want: []string{"Update"},
+		},
+		"Delete": {
+			reason: "Deleted objects should be handled as delete events.",
+			args: args{
+				fn: func(e eventHandler) { e.OnDelete(obj) },
+			},
+			want: []string{"Delete"},
+		},
+		"DeleteTombstone": {
+			reason: "Deleted objects wrapped in a tombstone should be handled as delete events.",
+			args: args{
+				fn: func(e eventHandler) { e.OnDelete(toolscache.DeletedFinalStateUnknown{Key: "cool", Obj: obj}) },
+			},
+			want: []string{"Delete"},
+		},
+		"NotAnObject": {
+			reason: "Events for things that aren't objects should be ignored.",
+			args: args{
+				fn: func(e eventHandler) { e.OnAdd("cool") },
+			},
+		},
+		"Filtered": {
+			reason: "Events rejected by a predicate should be ignored.",
+			args: args{
+				p: []predicate.Predicate{reject},
+				fn: func(e eventHandler) {
+					e.OnAdd(obj)
+					e.OnUpdate(obj, obj)
+					e.OnDelete(obj)
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			h := handler.Funcs{
+				CreateFunc: func(event.CreateEvent, workqueue.RateLimitingInterface) { got = append(got, "Create") },
+				UpdateFunc: func(event.UpdateEvent, workqueue.RateLimitingInterface) { got = append(got, "Update") },
+				DeleteFunc: func(event.DeleteEvent, workqueue.RateLimitingInterface) { got = append(got, "Delete") },
+			}
+			tc.args.fn(eventHandler{handler: h, predicates: tc.args.p})
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\neventHandler: -want events, +got events:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestOptionsSource(t *testing.T) {
+	type want struct {
+		resync bool
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		o      Options
+		kind   client.Object
+		want   want
+	}{
+		"NotConfigured": {
+			reason: "Kinds with no configured resync period should use the cache's resync period.",
+			o:      Options{ResyncPeriods: map[schema.GroupKind]time.Duration{{Kind: "ConfigMap"}: time.Hour}},
+			kind:   &corev1.Secret{},
+			want:   want{},
+		},
+		"Configured": {
+			reason: "Kinds with a configured resync period should be resynced at that period.",
+			o:      Options{ResyncPeriods: map[schema.GroupKind]time.Duration{{Kind: "Secret"}: time.Hour}},
+			kind:   &corev1.Secret{},
+			want:   want{resync: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			src, err := tc.o.Source(nil, scheme.Scheme, tc.kind)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\no.Source(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			_, resync := src.(*resyncKind)
+			if diff := cmp.Diff(tc.want.resync, resync); diff != "" {
+				t.Errorf("\n%s\no.Source(...): -want resync, +got resync:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"context"
+	"runtime/pprof"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// LabelKeyGVK is the goroutine label that identifies the kind of resource a
+// goroutine is reconciling.
+const LabelKeyGVK = "gvk"
+
+// WithGoroutineLabels returns a reconcile.Reconciler that runs the supplied
+// Reconciler with the supplied pprof goroutine labels, as key-value pairs.
+// Goroutines started while reconciling inherit the labels, so that CPU and
+// goroutine profiles can be broken down by controller.
+func WithGoroutineLabels(r reconcile.Reconciler, kv ...string) reconcile.Reconciler {
+	labels := pprof.Labels(kv...)
+	return reconcile.Func(func(ctx context.Context, req reconcile.Request) (result reconcile.Result, err error) {
+		pprof.Do(ctx, labels, func(ctx context.Context) {
+			result, err = r.Reconcile(ctx, req)
+		})
+		return result, err
+	})
+}
@@ -0,0 +1,277 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+const (
+	errMarshalStates  = "cannot marshal reconcile states"
+	errWriteConfigMap = "cannot write reconcile states ConfigMap"
+)
+
+// DefaultReconcileStateRetention is the default time for which the state of a
+// resource that is no longer being reconciled is retained.
+const DefaultReconcileStateRetention = 24 * time.Hour
+
+// Results of a reconcile.
+const (
+	ResultSuccess      = "Success"
+	ResultRequeue      = "Requeue"
+	ResultRequeueAfter = "RequeueAfter"
+	ResultError        = "Error"
+)
+
+// ReconcileState is the state of the most recent reconcile of a resource.
+type ReconcileState struct {
+	Controller string        `json:"controller"`
+	Namespace  string        `json:"namespace,omitempty"`
+	Name       string        `json:"name"`
+	Time       time.Time     `json:"time"`
+	Duration   time.Duration `json:"duration"`
+	Result     string        `json:"result"`
+	RequeueIn  time.Duration `json:"requeueIn,omitempty"`
+	Error      string        `json:"error,omitempty"`
+}
+
+type stateKey struct {
+	controller string
+	nn         types.NamespacedName
+}
+
+// A ReconcileRegistryOption configures a ReconcileRegistry.
+type ReconcileRegistryOption func(r *ReconcileRegistry)
+
+// WithReconcileStateRetention configures how long the state of a resource that
+// is no longer being reconciled, for example because it was deleted, is
+// retained.
+func WithReconcileStateRetention(d time.Duration) ReconcileRegistryOption {
+	return func(r *ReconcileRegistry) {
+		r.retention = d
+	}
+}
+
+// A ReconcileRegistry tracks the most recent reconcile of each resource, so
+// that operators can tell whether and how a controller is reconciling a
+// particular resource.
+type ReconcileRegistry struct {
+	retention time.Duration
+	now       func() time.Time
+
+	mu     sync.RWMutex
+	states map[stateKey]ReconcileState
+}
+
+// NewReconcileRegistry returns an empty ReconcileRegistry.
+func NewReconcileRegistry(o ...ReconcileRegistryOption) *ReconcileRegistry {
+	r := &ReconcileRegistry{
+		retention: DefaultReconcileStateRetention,
+		now:       time.Now,
+		states:    make(map[stateKey]ReconcileState),
+	}
+	for _, fn := range o {
+		fn(r)
+	}
+	return r
+}
+
+// Track returns a reconcile.Reconciler that records the state of each
+// reconcile of the supplied Reconciler under the supplied controller name.
+func (r *ReconcileRegistry) Track(controller string, rec reconcile.Reconciler) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		started := r.now()
+		result, err := rec.Reconcile(ctx, req)
+
+		s := ReconcileState{
+			Controller: controller,
+			Namespace:  req.Namespace,
+			Name:       req.Name,
+			Time:       started,
+			Duration:   r.now().Sub(started),
+			Result:     ResultSuccess,
+		}
+		switch {
+		case err != nil:
+			s.Result = ResultError
+			s.Error = err.Error()
+		case result.RequeueAfter > 0:
+			s.Result = ResultRequeueAfter
+			s.RequeueIn = result.RequeueAfter
+		case result.Requeue:
+			s.Result = ResultRequeue
+		}
+
+		r.mu.Lock()
+		r.states[stateKey{controller: controller, nn: req.NamespacedName}] = s
+		r.mu.Unlock()
+
+		return result, err
+	})
+}
+
+// Snapshot returns the most recent reconcile of each resource reconciled
+// within the retention period, ordered by controller, namespace, and name.
+// States older than the retention period are discarded.
+func (r *ReconcileRegistry) Snapshot() []ReconcileState {
+	cutoff := r.now().Add(-r.retention)
+
+	r.mu.Lock()
+	out := make([]ReconcileState, 0, len(r.states))
+	for k, s := range r.states {
+		if s.Time.Before(cutoff) {
+			delete(r.states, k)
+			continue
+		}
+		out = append(out, s)
+	}
+	r.mu.Unlock()
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Controller != out[j].Controller {
+			return out[i].Controller < out[j].Controller
+		}
+		if out[i].Namespace != out[j].Namespace {
+			return out[i].Namespace < out[j].Namespace
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// ServeHTTP serves a snapshot of reconcile states as JSON. The optional
+// controller, namespace, and name query parameters filter the states served.
+func (r *ReconcileRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	q := req.URL.Query()
+	out := make([]ReconcileState, 0)
+	for _, s := range r.Snapshot() {
+		if c := q.Get("controller"); c != "" && c != s.Controller {
+			continue
+		}
+		if ns := q.Get("namespace"); ns != "" && ns != s.Namespace {
+			continue
+		}
+		if n := q.Get("name"); n != "" && n != s.Name {
+			continue
+		}
+		out = append(out, s)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+// RequireBearerToken returns a handler that serves requests using the supplied
+// handler only if they present the supplied bearer token, for example to
+// protect diagnostics that name the resources a provider manages.
+func RequireBearerToken(token string, h http.Handler) http.Handler {
+	want := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(req.Header.Get("Authorization")), want) != 1 {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		h.ServeHTTP(w, req)
+	})
+}
+
+// invalidKeyChars matches characters that may not appear in a ConfigMap key.
+var invalidKeyChars = regexp.MustCompile(`[^-._a-zA-Z0-9]`)
+
+// WriteConfigMap writes a snapshot of the supplied registry's reconcile states
+// to the supplied ConfigMap, creating it if it does not exist. The data has
+// one key per controller, whose value is a JSON array of reconcile states. Any
+// other data is replaced. Note that ConfigMaps are limited to 1MiB.
+func WriteConfigMap(ctx context.Context, c client.Client, nn types.NamespacedName, r *ReconcileRegistry) error {
+	byController := map[string][]ReconcileState{}
+	for _, s := range r.Snapshot() {
+		byController[s.Controller] = append(byController[s.Controller], s)
+	}
+
+	data := make(map[string]string, len(byController))
+	for controller, states := range byController {
+		j, err := json.Marshal(states)
+		if err != nil {
+			return errors.Wrap(err, errMarshalStates)
+		}
+		data[invalidKeyChars.ReplaceAllString(controller, "_")+".json"] = string(j)
+	}
+
+	cm := &corev1.ConfigMap{}
+	cm.SetNamespace(nn.Namespace)
+	cm.SetName(nn.Name)
+	_, err := controllerutil.CreateOrUpdate(ctx, c, cm, func() error {
+		cm.Data = data
+		return nil
+	})
+	return errors.Wrap(err, errWriteConfigMap)
+}
+
+// A ConfigMapDumper periodically writes the reconcile states of a registry to
+// a ConfigMap. Add it to a controller manager to dump states while the
+// manager runs.
+type ConfigMapDumper struct {
+	client   client.Client
+	nn       types.NamespacedName
+	registry *ReconcileRegistry
+	interval time.Duration
+	log      logging.Logger
+}
+
+// NewConfigMapDumper returns a ConfigMapDumper that writes the reconcile
+// states of the supplied registry to the supplied ConfigMap at the supplied
+// interval.
+func NewConfigMapDumper(c client.Client, nn types.NamespacedName, r *ReconcileRegistry, interval time.Duration, l logging.Logger) *ConfigMapDumper {
+	return &ConfigMapDumper{client: c, nn: nn, registry: r, interval: interval, log: l}
+}
+
+// NeedLeaderElection returns false; every replica dumps its own reconcile
+// states. Give each replica its own ConfigMap when running several replicas.
+func (d *ConfigMapDumper) NeedLeaderElection() bool {
+	return false
+}
+
+// Start writing reconcile states until the supplied context is cancelled.
+func (d *ConfigMapDumper) Start(ctx context.Context) error {
+	t := time.NewTicker(d.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+			if err := WriteConfigMap(ctx, d.client, d.nn, d.registry); err != nil {
+				d.log.Info("Cannot dump reconcile states", "error", err, "configmap", d.nn)
+			}
+		}
+	}
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+func TestReconcileRegistry(t *testing.T) {
+	errBoom := errors.New("boom")
+	now := time.Unix(1000, 0)
+
+	type reconciled struct {
+		name   string
+		result reconcile.Result
+		err    error
+		at     time.Time
+	}
+	cases := map[string]struct {
+		reason     string
+		reconciled []reconciled
+		query      string
+		want       []ReconcileState
+	}{
+		"Results": {
+			reason: "The most recent reconcile of each resource should be recorded.",
+			reconciled: []reconciled{
+				{name: "b", result: reconcile.Result{Requeue: true}, at: now},
+				{name: "a", err: errBoom, at: now},
+				{name: "c", result: reconcile.Result{RequeueAfter: time.Minute}, at: now},
+				{name: "c", at: now},
+			},
+			want: []ReconcileState{
+				{Controller: "cool", Name: "a", Time: now, Result: ResultError, Error: "boom"},
+				{Controller: "cool", Name: "b", Time: now, Result: ResultRequeue},
+				{Controller: "cool", Name: "c", Time: now, Result: ResultSuccess},
+			},
+		},
+		"Filtered": {
+			reason: "Only resources matching the query should be served.",
+			reconciled: []reconciled{
+				{name: "a", at: now},
+				{name: "b", result: reconcile.Result{RequeueAfter: time.Minute}, at: now},
+			},
+			query: "?controller=cool&name=b",
+			want: []ReconcileState{
+				{Controller: "cool", Name: "b", Time: now, Result: ResultRequeueAfter, RequeueIn: time.Minute},
+			},
+		},
+		"Retention": {
+			reason: "Resources that haven't been reconciled within the retention period should be discarded.",
+			reconciled: []reconciled{
+				{name: "old", at: now.Add(-2 * time.Hour)},
+				{name: "new", at: now},
+			},
+			want: []ReconcileState{
+				{Controller: "cool", Name: "new", Time: now, Result: ResultSuccess},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			reg := NewReconcileRegistry(WithReconcileStateRetention(time.Hour))
+			for _, rc := range tc.reconciled {
+				rc := rc
+				reg.now = func() time.Time { return rc.at }
+				r := reg.Track("cool", reconcile.Func(func(_ context.Context, _ reconcile.Request) (reconcile.Result, error) {
+					return rc.result, rc.err
+				}))
+				_, _ = r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: rc.name}})
+			}
+			reg.now = func() time.Time { return now }
+
+			w := httptest.NewRecorder()
+			RequireBearerToken("cool", reg).ServeHTTP(w, func() *http.Request {
+				r := httptest.NewRequest(http.MethodGet, "/debug/reconciles"+tc.query, nil)
+				r.Header.Set("Authorization", "Bearer cool")
+				return r
+			}())
+			got := make([]ReconcileState, 0)
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatalf("\n%s\nreg.ServeHTTP(...): cannot decode response: %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nreg.ServeHTTP(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRequireBearerToken(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		header string
+		want   int
+	}{
+		"Authorized": {
+			reason: "Requests presenting the token should be served.",
+			header: "Bearer cool",
+			want:   http.StatusOK,
+		},
+		"Unauthorized": {
+			reason: "Requests not presenting the token should be rejected.",
+			header: "Bearer uncool",
+			want:   http.StatusUnauthorized,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := RequireBearerToken("cool", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Authorization", tc.header)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if diff := cmp.Diff(tc.want, w.Code); diff != "" {
+				t.Errorf("\n%s\nh.ServeHTTP(...): -want status, +got status:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestWriteConfigMap(t *testing.T) {
+	now := time.Unix(1000, 0).UTC()
+	nn := types.NamespacedName{Namespace: "crossplane-system", Name: "reconciles"}
+
+	cases := map[string]struct {
+		reason     string
+		controller string
+		want       map[string]string
+	}{
+		"Written": {
+			reason:     "Reconcile states should be written to one key per controller.",
+			controller: "managed/cool.example.org",
+			want: map[string]string{
+				"managed_cool.example.org.json": `[{"controller":"managed/cool.example.org","name":"a","time":"1970-01-01T00:16:40Z","duration":0,"result":"Success"}]`,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			reg := NewReconcileRegistry()
+			reg.now = func() time.Time { return now }
+			r := reg.Track(tc.controller, reconcile.Func(func(_ context.Context, _ reconcile.Request) (reconcile.Result, error) {
+				return reconcile.Result{}, nil
+			}))
+			_, _ = r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "a"}})
+
+			c := fake.NewClientBuilder().Build()
+			if err := WriteConfigMap(context.Background(), c, nn, reg); err != nil {
+				t.Fatalf("\n%s\nWriteConfigMap(...): %s", tc.reason, err)
+			}
+			cm := &corev1.ConfigMap{}
+			if err := c.Get(context.Background(), nn, cm); err != nil {
+				t.Fatalf("\n%s\nc.Get(...): %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, cm.Data); diff != "" {
+				t.Errorf("\n%s\nWriteConfigMap(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
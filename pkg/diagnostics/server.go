@@ -0,0 +1,203 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package diagnostics serves runtime diagnostics, such as profiles, to help
+// investigate misbehaving providers.
+package diagnostics
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+const (
+	errListen         = "cannot listen for diagnostics requests"
+	errServe          = "cannot serve diagnostics requests"
+	errAddToManager   = "cannot add diagnostics server to manager"
+	errGatherMetrics  = "cannot gather metrics"
+	shutdownTimeout   = 5 * time.Second
+	readHeaderTimeout = 10 * time.Second
+	workqueuePrefix   = "workqueue_"
+	labelQueueName    = "name"
+)
+
+// An Option configures a Server.
+type Option func(s *Server)
+
+// WithGatherer configures the Prometheus gatherer workqueue metrics are read
+// from. The controller-runtime metrics registry is used by default.
+func WithGatherer(g prometheus.Gatherer) Option {
+	return func(s *Server) {
+		s.gatherer = g
+	}
+}
+
+// WithHandler configures an additional handler served at the supplied path.
+func WithHandler(path string, h http.Handler) Option {
+	return func(s *Server) {
+		s.mux.Handle(path, h)
+	}
+}
+
+// WithReconcileStates configures the Server to serve the reconcile states
+// tracked by the supplied registry at /debug/reconciles. Requests must present
+// the supplied bearer token unless it is empty.
+func WithReconcileStates(r *ReconcileRegistry, token string) Option {
+	return func(s *Server) {
+		var h http.Handler = r
+		if token != "" {
+			h = RequireBearerToken(token, r)
+		}
+		s.mux.Handle("/debug/reconciles", h)
+	}
+}
+
+// WithLogger configures the logger used by the Server.
+func WithLogger(l logging.Logger) Option {
+	return func(s *Server) {
+		s.log = l
+	}
+}
+
+// A Server serves diagnostics over HTTP. It serves:
+//
+//   - /debug/pprof/ - Go runtime profiles.
+//   - /debug/vars - Variables published using the expvar package.
+//   - /debug/workqueues - The state of each controller's workqueue.
+//
+// Diagnostics may expose sensitive information, so the Server should only
+// listen on a local address, or one protected by a network policy.
+type Server struct {
+	addr     string
+	mux      *http.ServeMux
+	gatherer prometheus.Gatherer
+	log      logging.Logger
+}
+
+// NewServer returns a Server that listens on the supplied address.
+func NewServer(addr string, o ...Option) *Server {
+	s := &Server{
+		addr:     addr,
+		mux:      http.NewServeMux(),
+		gatherer: metrics.Registry,
+		log:      logging.NewNopLogger(),
+	}
+
+	s.mux.HandleFunc("/debug/pprof/", pprof.Index)
+	s.mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	s.mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	s.mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	s.mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	s.mux.Handle("/debug/vars", expvar.Handler())
+	s.mux.HandleFunc("/debug/workqueues", s.workqueues)
+
+	for _, fn := range o {
+		fn(s)
+	}
+	return s
+}
+
+// AddToManager adds a Server that listens on the supplied address to the
+// supplied manager. The Server runs while the manager runs.
+func AddToManager(mgr manager.Manager, addr string, o ...Option) error {
+	return errors.Wrap(mgr.Add(NewServer(addr, o...)), errAddToManager)
+}
+
+// NeedLeaderElection returns false; every replica serves diagnostics.
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// ServeHTTP serves diagnostics.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Start serving diagnostics until the supplied context is cancelled.
+func (s *Server) Start(ctx context.Context) error {
+	l, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return errors.Wrap(err, errListen)
+	}
+
+	srv := &http.Server{Handler: s.mux, ReadHeaderTimeout: readHeaderTimeout}
+	go func() {
+		<-ctx.Done()
+		sctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		_ = srv.Shutdown(sctx)
+	}()
+
+	s.log.Debug("Serving diagnostics", "address", l.Addr().String())
+	if err := srv.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return errors.Wrap(err, errServe)
+	}
+	return nil
+}
+
+// workqueues serves the workqueue metrics of each controller as JSON, keyed by
+// controller name and then by metric, e.g. {"cool": {"depth": 3}}. Histograms
+// are reported by their sample count.
+func (s *Server) workqueues(w http.ResponseWriter, _ *http.Request) {
+	mfs, err := s.gatherer.Gather()
+	if err != nil {
+		http.Error(w, errors.Wrap(err, errGatherMetrics).Error(), http.StatusInternalServerError)
+		return
+	}
+
+	queues := map[string]map[string]float64{}
+	for _, mf := range mfs {
+		if !strings.HasPrefix(mf.GetName(), workqueuePrefix) {
+			continue
+		}
+		name := strings.TrimPrefix(mf.GetName(), workqueuePrefix)
+		for _, m := range mf.GetMetric() {
+			q := ""
+			for _, l := range m.GetLabel() {
+				if l.GetName() == labelQueueName {
+					q = l.GetValue()
+				}
+			}
+			if queues[q] == nil {
+				queues[q] = map[string]float64{}
+			}
+			switch {
+			case m.GetGauge() != nil:
+				queues[q][name] = m.GetGauge().GetValue()
+			case m.GetCounter() != nil:
+				queues[q][name] = m.GetCounter().GetValue()
+			case m.GetHistogram() != nil:
+				queues[q][name] = float64(m.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(queues)
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"runtime/pprof"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestServer(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	depth := prometheus.NewGaugeVec(prometheus.GaugeOpts{Subsystem: "workqueue", Name: "depth"}, []string{"name"})
+	depth.WithLabelValues("cool").Set(3)
+	adds := prometheus.NewCounterVec(prometheus.CounterOpts{Subsystem: "workqueue", Name: "adds_total"}, []string{"name"})
+	adds.WithLabelValues("cool").Add(42)
+	other := prometheus.NewGauge(prometheus.GaugeOpts{Name: "other"})
+	reg.MustRegister(depth, adds, other)
+
+	type want struct {
+		status int
+		body   string
+	}
+	cases := map[string]struct {
+		reason string
+		path   string
+		want   want
+	}{
+		"Workqueues": {
+			reason: "Workqueue metrics should be served keyed by controller and metric.",
+			path:   "/debug/workqueues",
+			want: want{
+				status: http.StatusOK,
+				body:   `{"cool":{"adds_total":42,"depth":3}}` + "\n",
+			},
+		},
+		"Handler": {
+			reason: "Additional handlers should be served.",
+			path:   "/debug/cool",
+			want: want{
+				status: http.StatusTeapot,
+				body:   "",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := NewServer("127.0.0.1:0", WithGatherer(reg), WithHandler("/debug/cool", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusTeapot)
+			})))
+
+			w := httptest.NewRecorder()
+			s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.path, nil))
+			got := want{status: w.Code, body: w.Body.String()}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\ns.ServeHTTP(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestWithGoroutineLabels(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		kv     []string
+		want   string
+	}{
+		"Labelled": {
+			reason: "The wrapped Reconciler should be called with the supplied labels.",
+			kv:     []string{LabelKeyGVK, "example.org/v1, Kind=Cool"},
+			want:   "example.org/v1, Kind=Cool",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got string
+			r := WithGoroutineLabels(reconcile.Func(func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+				got, _ = pprof.Label(ctx, LabelKeyGVK)
+				return reconcile.Result{}, nil
+			}), tc.kv...)
+			if _, err := r.Reconcile(context.Background(), reconcile.Request{}); err != nil {
+				t.Errorf("\n%s\nr.Reconcile(...): %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+// A Code is a machine-readable class of error. Codes let reconcilers,
+// conditions, metrics, and events key off the kind of error that occurred
+// rather than its message.
+type Code string
+
+// Standard error codes.
+const (
+	// CodeUnknown is returned by CodeOf for errors that have no code.
+	CodeUnknown Code = ""
+
+	// CodeThrottled indicates a request was rate limited and may succeed if
+	// retried later.
+	CodeThrottled Code = "Throttled"
+
+	// CodeNotFound indicates a requested resource does not exist.
+	CodeNotFound Code = "NotFound"
+
+	// CodeConflict indicates a request conflicted with the current state of
+	// a resource, for example because it was modified concurrently.
+	CodeConflict Code = "Conflict"
+
+	// CodeInvalidInput indicates a request was rejected because its input
+	// was invalid. Retrying the same request will not succeed.
+	CodeInvalidInput Code = "InvalidInput"
+
+	// CodeUnauthorized indicates the caller was not authenticated or not
+	// authorized to make a request.
+	CodeUnauthorized Code = "Unauthorized"
+
+	// CodeTerminal indicates an error that will not be resolved by retrying.
+	CodeTerminal Code = "Terminal"
+)
+
+// Codes returns all standard error codes, excluding CodeUnknown.
+func Codes() []Code {
+	return []Code{CodeThrottled, CodeNotFound, CodeConflict, CodeInvalidInput, CodeUnauthorized, CodeTerminal}
+}
+
+// A coder is an error that has a Code.
+type coder interface {
+	Code() Code
+}
+
+type codedError struct {
+	err  error
+	code Code
+}
+
+func (e *codedError) Error() string { return e.err.Error() }
+func (e *codedError) Unwrap() error { return e.err }
+func (e *codedError) Code() Code    { return e.code }
+
+// WithCode annotates err with the supplied code. The annotated error's message
+// is identical to err's. If err is nil, WithCode returns nil.
+func WithCode(err error, c Code) error {
+	if err == nil {
+		return nil
+	}
+	return &codedError{err: err, code: c}
+}
+
+// CodeOf returns the code of the outermost error in err's chain that has one,
+// or CodeUnknown if no error in the chain has a code. Errors may be given a
+// code using WithCode, or by implementing a method Code() Code.
+func CodeOf(err error) Code {
+	var c coder
+	if !As(err, &c) {
+		return CodeUnknown
+	}
+	return c.Code()
+}
+
+// An externalCoder is an error that has an external code.
+type externalCoder interface {
+	ExternalCode() string
+}
+
+type externalCodedError struct {
+	err  error
+	code string
+}
+
+func (e *externalCodedError) Error() string        { return e.err.Error() }
+func (e *externalCodedError) Unwrap() error        { return e.err }
+func (e *externalCodedError) ExternalCode() string { return e.code }
+
+// WithExternalCode annotates err with the supplied external code, i.e. the
+// error code returned by an external API such as "ThrottlingException" or
+// "403". Unlike a Code, an external code is specific to the external API. The
+// annotated error's message is identical to err's. If err is nil,
+// WithExternalCode returns nil.
+func WithExternalCode(err error, code string) error {
+	if err == nil {
+		return nil
+	}
+	return &externalCodedError{err: err, code: code}
+}
+
+// ExternalCodeOf returns the external code of the outermost error in err's
+// chain that has one, or the empty string if no error in the chain has an
+// external code.
+func ExternalCodeOf(err error) string {
+	var c externalCoder
+	if !As(err, &c) {
+		return ""
+	}
+	return c.ExternalCode()
+}
+
+// HasCode reports whether the code of err is c.
+func HasCode(err error, c Code) bool {
+	return err != nil && CodeOf(err) == c
+}
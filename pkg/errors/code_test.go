@@ -0,0 +1,188 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type customCodeError struct{}
+
+func (customCodeError) Error() string { return "boom" }
+func (customCodeError) Code() Code    { return CodeThrottled }
+
+func TestWithCode(t *testing.T) {
+	type args struct {
+		err  error
+		code Code
+	}
+	type want struct {
+		msg  string
+		code Code
+		is   bool
+	}
+	errBoom := New("boom")
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"NilError": {
+			args: args{
+				err:  nil,
+				code: CodeNotFound,
+			},
+			want: want{},
+		},
+		"NonNilError": {
+			args: args{
+				err:  errBoom,
+				code: CodeNotFound,
+			},
+			want: want{
+				msg:  "boom",
+				code: CodeNotFound,
+				is:   true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := WithCode(tc.args.err, tc.args.code)
+			if tc.args.err == nil {
+				if err != nil {
+					t.Errorf("WithCode(nil, ...): want nil, got %v", err)
+				}
+				return
+			}
+			if diff := cmp.Diff(tc.want.msg, err.Error()); diff != "" {
+				t.Errorf("WithCode(...).Error(): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.code, CodeOf(err)); diff != "" {
+				t.Errorf("CodeOf(WithCode(...)): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.is, Is(err, tc.args.err)); diff != "" {
+				t.Errorf("Is(WithCode(...), err): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCodeOf(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		want Code
+	}{
+		"NilError": {
+			err:  nil,
+			want: CodeUnknown,
+		},
+		"NoCode": {
+			err:  New("boom"),
+			want: CodeUnknown,
+		},
+		"Coded": {
+			err:  WithCode(New("boom"), CodeConflict),
+			want: CodeConflict,
+		},
+		"WrappedCoded": {
+			err:  Wrap(WithCode(New("boom"), CodeUnauthorized), "very useful context"),
+			want: CodeUnauthorized,
+		},
+		"OutermostCodeWins": {
+			err:  WithCode(Wrap(WithCode(New("boom"), CodeThrottled), "context"), CodeTerminal),
+			want: CodeTerminal,
+		},
+		"CustomCoder": {
+			err:  Wrap(customCodeError{}, "very useful context"),
+			want: CodeThrottled,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := CodeOf(tc.err)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("CodeOf(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExternalCodeOf(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		want string
+	}{
+		"NilError": {
+			err:  nil,
+			want: "",
+		},
+		"NoExternalCode": {
+			err:  WithCode(New("boom"), CodeThrottled),
+			want: "",
+		},
+		"WrappedExternalCode": {
+			err:  WithCode(Wrap(WithExternalCode(New("boom"), "ThrottlingException"), "context"), CodeThrottled),
+			want: "ThrottlingException",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ExternalCodeOf(tc.err)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ExternalCodeOf(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHasCode(t *testing.T) {
+	type args struct {
+		err  error
+		code Code
+	}
+	cases := map[string]struct {
+		args args
+		want bool
+	}{
+		"NilError": {
+			args: args{err: nil, code: CodeUnknown},
+			want: false,
+		},
+		"Match": {
+			args: args{err: WithCode(New("boom"), CodeInvalidInput), code: CodeInvalidInput},
+			want: true,
+		},
+		"Mismatch": {
+			args: args{err: WithCode(New("boom"), CodeInvalidInput), code: CodeTerminal},
+			want: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := HasCode(tc.args.err, tc.args.code)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("HasCode(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -17,13 +17,27 @@ limitations under the License.
 package errors
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
-
-	"github.com/crossplane/crossplane-runtime/pkg/test"
 )
 
+// equateErrors is equivalent to test.EquateErrors. We can't use that here,
+// because package test imports package v1 of the common APIs, which imports
+// this package.
+func equateErrors() cmp.Option {
+	return cmp.Comparer(func(a, b error) bool {
+		if a == nil || b == nil {
+			return a == nil && b == nil
+		}
+		if reflect.TypeOf(a) != reflect.TypeOf(b) {
+			return false
+		}
+		return a.Error() == b.Error()
+	})
+}
+
 func TestWrap(t *testing.T) {
 	type args struct {
 		err     error
@@ -52,7 +66,7 @@ func TestWrap(t *testing.T) {
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			got := Wrap(tc.args.err, tc.args.message)
-			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+			if diff := cmp.Diff(tc.want, got, equateErrors()); diff != "" {
 				t.Errorf("Wrap(...): -want, +got:\n%s", diff)
 			}
 		})
@@ -89,7 +103,7 @@ func TestWrapf(t *testing.T) {
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			got := Wrapf(tc.args.err, tc.args.message, tc.args.args...)
-			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+			if diff := cmp.Diff(tc.want, got, equateErrors()); diff != "" {
 				t.Errorf("Wrapf(...): -want, +got:\n%s", diff)
 			}
 		})
@@ -118,7 +132,7 @@ func TestCause(t *testing.T) {
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			got := Cause(tc.err)
-			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+			if diff := cmp.Diff(tc.want, got, equateErrors()); diff != "" {
 				t.Errorf("Cause(...): -want, +got:\n%s", diff)
 			}
 		})
@@ -0,0 +1,165 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Join returns an error that wraps the supplied errors. Any nil errors are
+// discarded. Join returns nil if every supplied error is nil, and the only
+// non-nil error if there is exactly one. The joined error formats as the
+// messages of the supplied errors, separated by "; ".
+//
+// Is and As consider every supplied error to be part of the joined error's
+// chain.
+func Join(errs ...error) error {
+	j := &joinError{}
+	for _, err := range errs {
+		if err != nil {
+			j.errs = append(j.errs, err)
+		}
+	}
+	switch len(j.errs) {
+	case 0:
+		return nil
+	case 1:
+		return j.errs[0]
+	}
+	return j
+}
+
+type joinError struct {
+	errs []error
+}
+
+func (e *joinError) Error() string {
+	msgs := make([]string, len(e.errs))
+	for i := range e.errs {
+		msgs[i] = e.errs[i].Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the joined errors. It's used by errors.Is and errors.As in Go
+// 1.20 and later.
+func (e *joinError) Unwrap() []error { return e.errs }
+
+// Is reports whether any of the joined errors match target. It's used by
+// errors.Is in Go versions that don't support Unwrap() []error.
+func (e *joinError) Is(target error) bool {
+	for _, err := range e.errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first joined error that matches target. It's used by
+// errors.As in Go versions that don't support Unwrap() []error.
+func (e *joinError) As(target any) bool {
+	for _, err := range e.errs {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Causes returns the independent causes of err. If err is, or wraps, an error
+// produced by Join the causes are the joined errors, recursively flattened.
+// Otherwise err is its only cause. Causes returns nil if err is nil.
+func Causes(err error) []error {
+	if err == nil {
+		return nil
+	}
+	_, j := outermostJoin(err)
+	if j == nil {
+		return []error{err}
+	}
+	causes := make([]error, 0, len(j.Unwrap()))
+	for _, e := range j.Unwrap() {
+		causes = append(causes, Causes(e)...)
+	}
+	return causes
+}
+
+type joined interface {
+	error
+	Unwrap() []error
+}
+
+// outermostJoin returns the outermost joined error in err's chain, and the
+// context it was wrapped with - e.g. "cannot publish connection details".
+func outermostJoin(err error) (string, joined) {
+	for e := err; e != nil; e = Unwrap(e) {
+		//nolint:errorlint // We want to find the outermost joined error.
+		if j, ok := e.(joined); ok {
+			return strings.TrimSuffix(strings.TrimSuffix(err.Error(), j.Error()), ": "), j
+		}
+	}
+	return "", nil
+}
+
+// Summarize renders err as a human readable message. Errors that have several
+// independent causes (see Causes) are rendered as the context they were
+// wrapped with, followed by each cause as a separate bullet point. At most
+// maxCauses causes are rendered. Summarize returns the empty string if err is
+// nil.
+func Summarize(err error, maxCauses int) string {
+	causes := Causes(err)
+	if len(causes) < 2 {
+		if err == nil {
+			return ""
+		}
+		return err.Error()
+	}
+
+	context, _ := outermostJoin(err)
+	b := &strings.Builder{}
+	b.WriteString(context)
+	if context != "" {
+		b.WriteString(":")
+	}
+	for i, c := range causes {
+		if i == maxCauses {
+			fmt.Fprintf(b, "\n- ...and %d more", len(causes)-maxCauses)
+			break
+		}
+		b.WriteString("\n- ")
+		b.WriteString(c.Error())
+	}
+	return strings.TrimPrefix(b.String(), "\n")
+}
+
+// Truncate the supplied message to at most n bytes, without splitting a UTF-8
+// character. Truncated messages end in an ellipsis.
+func Truncate(msg string, n int) string {
+	if len(msg) <= n {
+		return msg
+	}
+	const ellipsis = "..."
+	n -= len(ellipsis)
+	for n > 0 && !utf8.RuneStart(msg[n]) {
+		n--
+	}
+	return msg[:n] + ellipsis
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestJoin(t *testing.T) {
+	errBoom := New("boom")
+	errBang := New("bang")
+
+	type want struct {
+		msg  string
+		is   []error
+		code Code
+	}
+	cases := map[string]struct {
+		reason string
+		errs   []error
+		want   want
+	}{
+		"NoErrors": {
+			reason: "Joining no errors should return nil.",
+			errs:   []error{nil, nil},
+			want:   want{},
+		},
+		"OneError": {
+			reason: "Joining one error should return that error.",
+			errs:   []error{nil, errBoom},
+			want:   want{msg: "boom", is: []error{errBoom}},
+		},
+		"ManyErrors": {
+			reason: "Joining many errors should return an error whose chain includes all of them.",
+			errs:   []error{errBoom, nil, WithCode(errBang, CodeThrottled)},
+			want:   want{msg: "boom; bang", is: []error{errBoom, errBang}, code: CodeThrottled},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := Join(tc.errs...)
+			if err == nil {
+				if tc.want.msg != "" {
+					t.Errorf("\n%s\nJoin(...): want error %q, got nil", tc.reason, tc.want.msg)
+				}
+				return
+			}
+			if diff := cmp.Diff(tc.want.msg, err.Error()); diff != "" {
+				t.Errorf("\n%s\nJoin(...).Error(): -want, +got:\n%s", tc.reason, diff)
+			}
+			for _, target := range tc.want.is {
+				if !Is(err, target) {
+					t.Errorf("\n%s\nIs(Join(...), %q): want true, got false", tc.reason, target)
+				}
+			}
+			if diff := cmp.Diff(tc.want.code, CodeOf(err)); diff != "" {
+				t.Errorf("\n%s\nCodeOf(Join(...)): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCauses(t *testing.T) {
+	errBoom := New("boom")
+	errBang := New("bang")
+	errPow := New("pow")
+
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   []error
+	}{
+		"NilError": {
+			reason: "A nil error has no causes.",
+			err:    nil,
+			want:   nil,
+		},
+		"SingleCause": {
+			reason: "An error that was not joined is its own cause.",
+			err:    Wrap(errBoom, "context"),
+			want:   []error{Wrap(errBoom, "context")},
+		},
+		"Joined": {
+			reason: "The causes of a wrapped, nested joined error should be flattened.",
+			err:    Wrap(Join(errBoom, Join(errBang, errPow)), "context"),
+			want:   []error{errBoom, errBang, errPow},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Causes(tc.err)
+			if diff := cmp.Diff(tc.want, got, equateErrors()); diff != "" {
+				t.Errorf("\n%s\nCauses(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	many := make([]error, 5)
+	for i := range many {
+		many[i] = Errorf("cause %d", i)
+	}
+
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   string
+	}{
+		"NilError": {
+			reason: "A nil error should be summarized as the empty string.",
+			err:    nil,
+			want:   "",
+		},
+		"SingleCause": {
+			reason: "An error with a single cause should be rendered as is.",
+			err:    Wrap(New("boom"), "context"),
+			want:   "context: boom",
+		},
+		"JoinedCauses": {
+			reason: "Each cause of a joined error should be rendered as a bullet point.",
+			err:    Join(New("boom"), New("bang")),
+			want:   "- boom\n- bang",
+		},
+		"WrappedJoinedCauses": {
+			reason: "Context wrapping a joined error should precede its causes.",
+			err:    Wrap(Join(New("boom"), Join(New("bang"), New("pow"))), "context"),
+			want:   "context:\n- boom\n- bang\n- pow",
+		},
+		"TooManyCauses": {
+			reason: "Only the first maxCauses causes should be rendered.",
+			err:    Join(many...),
+			want:   "- cause 0\n- cause 1\n- cause 2\n- ...and 2 more",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Summarize(tc.err, 3)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nSummarize(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	type args struct {
+		msg string
+		n   int
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   string
+	}{
+		"Short": {
+			reason: "Messages no longer than n bytes should not be truncated.",
+			args:   args{msg: "boom", n: 4},
+			want:   "boom",
+		},
+		"Long": {
+			reason: "Messages longer than n bytes should be truncated to n bytes, including an ellipsis.",
+			args:   args{msg: "kaboom", n: 5},
+			want:   "ka...",
+		},
+		"MultiByte": {
+			reason: "Truncation should not split a UTF-8 character.",
+			args:   args{msg: "üüüü", n: 6},
+			want:   "ü...",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Truncate(tc.args.msg, tc.args.n)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nTruncate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
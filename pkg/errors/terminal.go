@@ -0,0 +1,77 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+// Terminal marks err as terminal, i.e. an error that will not be resolved by
+// retrying. Terminal errors have CodeTerminal. If err is nil, Terminal returns
+// nil.
+func Terminal(err error) error {
+	return WithCode(err, CodeTerminal)
+}
+
+// Retryable marks err as retryable, i.e. an error that may be resolved by
+// retrying. Retryable overrides any Terminal mark made deeper in err's chain;
+// otherwise the code of err is preserved. If err is nil, Retryable returns
+// nil.
+func Retryable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &retryableError{err: err}
+}
+
+type retryableError struct {
+	err error
+}
+
+func (e *retryableError) Error() string { return e.err.Error() }
+func (e *retryableError) Unwrap() error { return e.err }
+
+func (e *retryableError) Code() Code {
+	if c := CodeOf(e.err); c != CodeTerminal {
+		return c
+	}
+	return CodeUnknown
+}
+
+// IsTerminal reports whether err is terminal. An error is terminal if the
+// outermost code in its chain is CodeTerminal. An error produced by Join is
+// terminal only if all of the joined errors are terminal; retrying may resolve
+// any that are not.
+func IsTerminal(err error) bool {
+	for e := err; e != nil; e = Unwrap(e) {
+		//nolint:errorlint // We want the outermost code or joined error.
+		switch ee := e.(type) {
+		case coder:
+			return ee.Code() == CodeTerminal
+		case joined:
+			for _, c := range ee.Unwrap() {
+				if !IsTerminal(c) {
+					return false
+				}
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// IsRetryable reports whether err may be resolved by retrying. All non-nil
+// errors that are not terminal are retryable.
+func IsRetryable(err error) bool {
+	return err != nil && !IsTerminal(err)
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestIsTerminal(t *testing.T) {
+	type want struct {
+		terminal  bool
+		retryable bool
+		code      Code
+	}
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   want
+	}{
+		"NilError": {
+			reason: "A nil error is neither terminal nor retryable.",
+			err:    nil,
+			want:   want{},
+		},
+		"Unmarked": {
+			reason: "Unmarked errors are retryable.",
+			err:    New("boom"),
+			want:   want{retryable: true},
+		},
+		"Terminal": {
+			reason: "Errors marked terminal are terminal.",
+			err:    Wrap(Terminal(New("boom")), "very useful context"),
+			want:   want{terminal: true, code: CodeTerminal},
+		},
+		"RetryableTerminal": {
+			reason: "Retryable overrides a terminal mark deeper in the chain.",
+			err:    Retryable(Terminal(New("boom"))),
+			want:   want{retryable: true},
+		},
+		"TerminalRetryable": {
+			reason: "Terminal overrides a retryable mark deeper in the chain.",
+			err:    Terminal(Retryable(New("boom"))),
+			want:   want{terminal: true, code: CodeTerminal},
+		},
+		"RetryablePreservesCode": {
+			reason: "Retryable preserves the code of the error it marks.",
+			err:    Retryable(WithCode(New("boom"), CodeThrottled)),
+			want:   want{retryable: true, code: CodeThrottled},
+		},
+		"JoinedTerminal": {
+			reason: "Joined errors are terminal if all of the joined errors are terminal.",
+			err:    Wrap(Join(Terminal(New("boom")), Wrap(Terminal(New("bang")), "context")), "very useful context"),
+			want:   want{terminal: true, code: CodeTerminal},
+		},
+		"JoinedTerminalAndUnmarked": {
+			reason: "Joined errors are retryable if any of the joined errors is retryable, even if the first is terminal.",
+			err:    Join(Terminal(New("boom")), New("bang")),
+			want:   want{retryable: true, code: CodeTerminal},
+		},
+		"JoinedUnmarkedAndTerminal": {
+			reason: "Joined errors are retryable if any of the joined errors is retryable.",
+			err:    Join(New("boom"), Terminal(New("bang"))),
+			want:   want{retryable: true, code: CodeTerminal},
+		},
+		"JoinedTerminalAndRetryable": {
+			reason: "Joined errors are retryable if any of the joined errors is marked retryable.",
+			err:    Join(Terminal(New("boom")), Retryable(Terminal(New("bang")))),
+			want:   want{retryable: true, code: CodeTerminal},
+		},
+		"NestedJoinedTerminal": {
+			reason: "Nested joined errors are terminal if all of their causes are terminal.",
+			err:    Join(Terminal(New("boom")), Join(Terminal(New("bang")), Terminal(New("pow")))),
+			want:   want{terminal: true, code: CodeTerminal},
+		},
+		"NestedJoinedRetryable": {
+			reason: "Nested joined errors are retryable if any of their causes is retryable.",
+			err:    Join(Terminal(New("boom")), Join(Terminal(New("bang")), New("pow"))),
+			want:   want{retryable: true, code: CodeTerminal},
+		},
+		"TerminalJoined": {
+			reason: "A terminal mark outside a joined error applies to all of its causes.",
+			err:    Terminal(Join(New("boom"), New("bang"))),
+			want:   want{terminal: true, code: CodeTerminal},
+		},
+		"RetryableJoined": {
+			reason: "A retryable mark outside a joined error applies to all of its causes.",
+			err:    Retryable(Join(Terminal(New("boom")), Terminal(New("bang")))),
+			want:   want{retryable: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := want{
+				terminal:  IsTerminal(tc.err),
+				retryable: IsRetryable(tc.err),
+				code:      CodeOf(tc.err),
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nIsTerminal(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package translate maps errors returned by external APIs, such as cloud
+// provider SDKs, to standard error codes and human readable messages.
+package translate
+
+import (
+	"net/http"
+	"strconv"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// A Translation of an error.
+type Translation struct {
+	// Code is the standard class of the error.
+	Code errors.Code
+
+	// Message is an optional, human readable explanation of the error. It
+	// is prepended to the error's own message.
+	Message string
+
+	// ExternalCode is an optional code specific to the external API, for
+	// example "ThrottlingException". It is used to label metrics. Matchers
+	// that match an API error code, HTTP status code, or gRPC status code
+	// use that code if no external code is supplied.
+	ExternalCode string
+}
+
+// A Matcher returns the Translation of the supplied error, and true, if it
+// matches the error. It returns false if it does not.
+type Matcher func(err error) (Translation, bool)
+
+// A Registry translates errors using the first of its Matchers that matches.
+type Registry struct {
+	matchers []Matcher
+}
+
+// NewRegistry returns a Registry that translates errors using the supplied
+// matchers, in order.
+func NewRegistry(m ...Matcher) *Registry {
+	return &Registry{matchers: m}
+}
+
+// Register the supplied matchers. They are consulted after any matchers that
+// were already registered. Register is not safe for concurrent use; matchers
+// should be registered before the registry is used.
+func (r *Registry) Register(m ...Matcher) {
+	r.matchers = append(r.matchers, m...)
+}
+
+// Translate the supplied error. The returned error wraps the supplied error
+// with the code and message of the first matching Translation. Errors that
+// already have a code, and errors that no Matcher matches, are returned
+// unchanged. Translate returns nil if err is nil.
+func (r *Registry) Translate(err error) error {
+	if err == nil || errors.CodeOf(err) != errors.CodeUnknown {
+		return err
+	}
+	for _, m := range r.matchers {
+		t, ok := m(err)
+		if !ok {
+			continue
+		}
+		if t.Message != "" {
+			err = errors.Wrap(err, t.Message)
+		}
+		if t.ExternalCode != "" {
+			err = errors.WithExternalCode(err, t.ExternalCode)
+		}
+		return errors.WithCode(err, t.Code)
+	}
+	return err
+}
+
+// Is returns a Matcher that matches errors for which errors.Is(err, target)
+// is true.
+func Is(target error, t Translation) Matcher {
+	return func(err error) (Translation, bool) {
+		return t, errors.Is(err, target)
+	}
+}
+
+// HTTPStatus returns a Matcher that matches errors with the supplied HTTP
+// status code. An error has an HTTP status code if it, or an error in its
+// chain, has a method HTTPStatusCode() int. The errors returned by the AWS
+// SDK for Go v2 have such a method.
+func HTTPStatus(code int, t Translation) Matcher {
+	type httpStatuser interface {
+		HTTPStatusCode() int
+	}
+	t = withExternalCode(t, strconv.Itoa(code))
+	return func(err error) (Translation, bool) {
+		var s httpStatuser
+		return t, errors.As(err, &s) && s.HTTPStatusCode() == code
+	}
+}
+
+// APIErrorCode returns a Matcher that matches errors with the supplied API
+// error code, for example "ThrottlingException". An error has an API error
+// code if it, or an error in its chain, has a method ErrorCode() string. The
+// errors returned by the AWS SDK for Go v2 have such a method.
+func APIErrorCode(code string, t Translation) Matcher {
+	type errorCoder interface {
+		ErrorCode() string
+	}
+	t = withExternalCode(t, code)
+	return func(err error) (Translation, bool) {
+		var c errorCoder
+		return t, errors.As(err, &c) && c.ErrorCode() == code
+	}
+}
+
+// GRPCCode returns a Matcher that matches errors with the supplied gRPC status
+// code. An error has a gRPC status code if it, or an error in its chain, has a
+// method GRPCStatus() *status.Status. The errors returned by gRPC clients,
+// including the Google Cloud client libraries, have such a method.
+func GRPCCode(code codes.Code, t Translation) Matcher {
+	type grpcStatuser interface {
+		GRPCStatus() *status.Status
+	}
+	t = withExternalCode(t, code.String())
+	return func(err error) (Translation, bool) {
+		var s grpcStatuser
+		return t, errors.As(err, &s) && s.GRPCStatus().Code() == code
+	}
+}
+
+// withExternalCode returns the supplied Translation with the supplied external
+// code, unless it already has one.
+func withExternalCode(t Translation, code string) Translation {
+	if t.ExternalCode == "" {
+		t.ExternalCode = code
+	}
+	return t
+}
+
+// DefaultHTTPStatusMatchers return Matchers that translate common HTTP status
+// codes to standard error codes.
+func DefaultHTTPStatusMatchers() []Matcher {
+	return []Matcher{
+		HTTPStatus(http.StatusBadRequest, Translation{Code: errors.CodeInvalidInput}),
+		HTTPStatus(http.StatusUnauthorized, Translation{Code: errors.CodeUnauthorized}),
+		HTTPStatus(http.StatusForbidden, Translation{Code: errors.CodeUnauthorized}),
+		HTTPStatus(http.StatusNotFound, Translation{Code: errors.CodeNotFound}),
+		HTTPStatus(http.StatusConflict, Translation{Code: errors.CodeConflict}),
+		HTTPStatus(http.StatusTooManyRequests, Translation{Code: errors.CodeThrottled}),
+	}
+}
+
+// DefaultGRPCCodeMatchers return Matchers that translate common gRPC status
+// codes to standard error codes.
+func DefaultGRPCCodeMatchers() []Matcher {
+	return []Matcher{
+		GRPCCode(codes.InvalidArgument, Translation{Code: errors.CodeInvalidInput}),
+		GRPCCode(codes.Unauthenticated, Translation{Code: errors.CodeUnauthorized}),
+		GRPCCode(codes.PermissionDenied, Translation{Code: errors.CodeUnauthorized}),
+		GRPCCode(codes.NotFound, Translation{Code: errors.CodeNotFound}),
+		GRPCCode(codes.AlreadyExists, Translation{Code: errors.CodeConflict}),
+		GRPCCode(codes.Aborted, Translation{Code: errors.CodeConflict}),
+		GRPCCode(codes.ResourceExhausted, Translation{Code: errors.CodeThrottled}),
+	}
+}
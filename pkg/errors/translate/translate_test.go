@@ -0,0 +1,124 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package translate
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+type apiError struct {
+	code   string
+	status int
+}
+
+func (e *apiError) Error() string       { return "api error " + e.code }
+func (e *apiError) ErrorCode() string   { return e.code }
+func (e *apiError) HTTPStatusCode() int { return e.status }
+
+func TestTranslate(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		msg      string
+		code     errors.Code
+		external string
+	}
+	cases := map[string]struct {
+		reason   string
+		matchers []Matcher
+		err      error
+		want     want
+	}{
+		"NilError": {
+			reason:   "A nil error should not be translated.",
+			matchers: []Matcher{Is(errBoom, Translation{Code: errors.CodeTerminal})},
+			err:      nil,
+			want:     want{},
+		},
+		"NoMatch": {
+			reason:   "An error that no matcher matches should be returned unchanged.",
+			matchers: DefaultHTTPStatusMatchers(),
+			err:      errBoom,
+			want:     want{msg: "boom"},
+		},
+		"AlreadyCoded": {
+			reason:   "An error that already has a code should be returned unchanged.",
+			matchers: []Matcher{Is(errBoom, Translation{Code: errors.CodeTerminal})},
+			err:      errors.WithCode(errBoom, errors.CodeThrottled),
+			want:     want{msg: "boom", code: errors.CodeThrottled},
+		},
+		"Is": {
+			reason:   "An error matched by Is should be translated.",
+			matchers: []Matcher{Is(errBoom, Translation{Code: errors.CodeTerminal, Message: "explosion"})},
+			err:      errors.Wrap(errBoom, "context"),
+			want:     want{msg: "explosion: context: boom", code: errors.CodeTerminal},
+		},
+		"FirstMatchWins": {
+			reason: "The first matching matcher should be used.",
+			matchers: []Matcher{
+				APIErrorCode("ThrottlingException", Translation{Code: errors.CodeThrottled, Message: "slow down"}),
+				HTTPStatus(http.StatusBadRequest, Translation{Code: errors.CodeInvalidInput}),
+			},
+			err:  errors.Wrap(&apiError{code: "ThrottlingException", status: http.StatusBadRequest}, "context"),
+			want: want{msg: "slow down: context: api error ThrottlingException", code: errors.CodeThrottled, external: "ThrottlingException"},
+		},
+		"ExplicitExternalCode": {
+			reason:   "An explicit external code should take precedence over the matched code.",
+			matchers: []Matcher{APIErrorCode("AccessDenied", Translation{Code: errors.CodeUnauthorized, ExternalCode: "Denied"})},
+			err:      &apiError{code: "AccessDenied", status: http.StatusForbidden},
+			want:     want{msg: "api error AccessDenied", code: errors.CodeUnauthorized, external: "Denied"},
+		},
+		"HTTPStatus": {
+			reason:   "An error with a matching HTTP status code should be translated.",
+			matchers: DefaultHTTPStatusMatchers(),
+			err:      &apiError{code: "AccessDenied", status: http.StatusForbidden},
+			want:     want{msg: "api error AccessDenied", code: errors.CodeUnauthorized, external: "403"},
+		},
+		"GRPCCode": {
+			reason:   "An error with a matching gRPC status code should be translated.",
+			matchers: DefaultGRPCCodeMatchers(),
+			err:      errors.Wrap(status.Error(codes.ResourceExhausted, "quota"), "context"),
+			want:     want{msg: "context: rpc error: code = ResourceExhausted desc = quota", code: errors.CodeThrottled, external: "ResourceExhausted"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewRegistry()
+			r.Register(tc.matchers...)
+			err := r.Translate(tc.err)
+
+			got := want{code: errors.CodeOf(err), external: errors.ExternalCodeOf(err)}
+			if err != nil {
+				got.msg = err.Error()
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nTranslate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if tc.err != nil && !errors.Is(err, tc.err) {
+				t.Errorf("\n%s\nerrors.Is(Translate(err), err): want true, got false", tc.reason)
+			}
+		})
+	}
+}
@@ -19,6 +19,7 @@ package event
 
 import (
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/tools/record"
 )
 
@@ -108,6 +109,44 @@ func sliceMap(from []string, to map[string]string) {
 	}
 }
 
+// Annotations that identify the provider and controller that recorded an event.
+const (
+	AnnotationKeyProviderName    = "crossplane.io/provider-name"
+	AnnotationKeyProviderVersion = "crossplane.io/provider-version"
+	AnnotationKeyControllerGVK   = "crossplane.io/controller-gvk"
+)
+
+// A ProviderIdentity identifies the provider and controller that record
+// events.
+type ProviderIdentity struct {
+	// Name of the provider, e.g. provider-aws.
+	Name string
+
+	// Version of the provider, e.g. v0.40.0.
+	Version string
+
+	// GroupVersionKind of the resources the recording controller reconciles.
+	GroupVersionKind schema.GroupVersionKind
+}
+
+// WithProviderIdentity returns a Recorder that annotates all events recorded
+// by the supplied Recorder with the supplied provider identity. This allows
+// operators of clusters running many providers to attribute events without
+// cross-referencing pod logs. Identity fields that are not set are omitted.
+func WithProviderIdentity(r Recorder, id ProviderIdentity) Recorder {
+	kv := make([]string, 0, 6)
+	if id.Name != "" {
+		kv = append(kv, AnnotationKeyProviderName, id.Name)
+	}
+	if id.Version != "" {
+		kv = append(kv, AnnotationKeyProviderVersion, id.Version)
+	}
+	if !id.GroupVersionKind.Empty() {
+		kv = append(kv, AnnotationKeyControllerGVK, id.GroupVersionKind.String())
+	}
+	return r.WithAnnotations(kv...)
+}
+
 // A NopRecorder does nothing.
 type NopRecorder struct{}
 
@@ -21,6 +21,9 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
 )
 
 func TestSliceMap(t *testing.T) {
@@ -88,3 +91,52 @@ func TestSliceMap(t *testing.T) {
 	}
 
 }
+
+// annotationRecorder is a record.EventRecorder that records the annotations of
+// the last event.
+type annotationRecorder struct {
+	record.EventRecorder
+	annotations map[string]string
+}
+
+func (r *annotationRecorder) AnnotatedEventf(_ runtime.Object, annotations map[string]string, _, _, _ string, _ ...any) {
+	r.annotations = annotations
+}
+
+func TestWithProviderIdentity(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		id     ProviderIdentity
+		want   map[string]string
+	}{
+		"NoIdentity": {
+			reason: "No annotations should be added if the identity is empty.",
+			id:     ProviderIdentity{},
+			want:   map[string]string{},
+		},
+		"FullIdentity": {
+			reason: "Events should be annotated with the provider name, version, and controller GVK.",
+			id: ProviderIdentity{
+				Name:             "provider-cool",
+				Version:          "v1.0.0",
+				GroupVersionKind: schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Cool"},
+			},
+			want: map[string]string{
+				AnnotationKeyProviderName:    "provider-cool",
+				AnnotationKeyProviderVersion: "v1.0.0",
+				AnnotationKeyControllerGVK:   "example.org/v1, Kind=Cool",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			kube := &annotationRecorder{}
+			WithProviderIdentity(NewAPIRecorder(kube), tc.id).Event(nil, Normal("Cool", "very"))
+
+			if diff := cmp.Diff(tc.want, kube.annotations); diff != "" {
+				t.Errorf("%s\nWithProviderIdentity(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
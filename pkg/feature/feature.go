@@ -24,19 +24,55 @@ import (
 // A Flag enables a particular feature.
 type Flag string
 
+// A ChangeFn is called when a feature flag is enabled or disabled.
+type ChangeFn func(f Flag, enabled bool)
+
 // Flags that are enabled. The zero value - i.e. &feature.Flags{} - is usable.
 type Flags struct {
-	m       sync.RWMutex
-	enabled map[Flag]bool
+	m        sync.RWMutex
+	enabled  map[Flag]bool
+	scopes   map[Flag]Scope
+	onChange []ChangeFn
 }
 
 // Enable a feature flag.
 func (fs *Flags) Enable(f Flag) {
+	fs.Set(f, true)
+}
+
+// Disable a feature flag.
+func (fs *Flags) Disable(f Flag) {
+	fs.Set(f, false)
+}
+
+// Set whether a feature flag is enabled. Any functions registered using
+// OnChange are called if this changes whether the flag is enabled.
+func (fs *Flags) Set(f Flag, enabled bool) {
 	fs.m.Lock()
 	if fs.enabled == nil {
 		fs.enabled = make(map[Flag]bool)
 	}
-	fs.enabled[f] = true
+	changed := fs.enabled[f] != enabled
+	fs.enabled[f] = enabled
+	onChange := fs.onChange
+	fs.m.Unlock()
+
+	if !changed {
+		return
+	}
+	for _, fn := range onChange {
+		fn(f, enabled)
+	}
+}
+
+// OnChange registers a function to be called whenever a feature flag is
+// enabled or disabled, for example to enable a beta reconciler path without
+// restarting. The function is called synchronously by whatever changed the
+// flag, so it must not block.
+func (fs *Flags) OnChange(fn ChangeFn) {
+	fs.m.Lock()
+	// Copy on write, so Set can call the functions without holding the lock.
+	fs.onChange = append(append([]ChangeFn{}, fs.onChange...), fn)
 	fs.m.Unlock()
 }
 
@@ -49,3 +85,18 @@ func (fs *Flags) Enabled(f Flag) bool {
 	defer fs.m.RUnlock()
 	return fs.enabled[f]
 }
+
+// Snapshot returns whether each feature flag that has been explicitly enabled or
+// disabled is currently enabled.
+func (fs *Flags) Snapshot() map[Flag]bool {
+	if fs == nil {
+		return map[Flag]bool{}
+	}
+	fs.m.RLock()
+	defer fs.m.RUnlock()
+	s := make(map[Flag]bool, len(fs.enabled))
+	for f, enabled := range fs.enabled {
+		s[f] = enabled
+	}
+	return s
+}
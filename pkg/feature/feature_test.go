@@ -59,3 +59,94 @@ func TestEnable(t *testing.T) {
 		}
 	})
 }
+
+func TestSet(t *testing.T) {
+	var cool Flag = "cool"
+
+	type change struct {
+		Flag    Flag
+		Enabled bool
+	}
+
+	type want struct {
+		enabled bool
+		changes []change
+	}
+
+	cases := map[string]struct {
+		reason  string
+		initial []Flag
+		set     []bool
+		want    want
+	}{
+		"EnableDisabledFlag": {
+			reason: "Enabling a disabled flag should notify OnChange functions.",
+			set:    []bool{true},
+			want: want{
+				enabled: true,
+				changes: []change{{Flag: cool, Enabled: true}},
+			},
+		},
+		"EnableEnabledFlag": {
+			reason:  "Enabling an enabled flag should not notify OnChange functions.",
+			initial: []Flag{cool},
+			set:     []bool{true},
+			want: want{
+				enabled: true,
+			},
+		},
+		"DisableEnabledFlag": {
+			reason:  "Disabling an enabled flag should notify OnChange functions.",
+			initial: []Flag{cool},
+			set:     []bool{false},
+			want: want{
+				enabled: false,
+				changes: []change{{Flag: cool, Enabled: false}},
+			},
+		},
+		"DisableDisabledFlag": {
+			reason: "Disabling a disabled flag should not notify OnChange functions.",
+			set:    []bool{false},
+			want: want{
+				enabled: false,
+			},
+		},
+		"Toggle": {
+			reason: "Every change to a flag should notify OnChange functions.",
+			set:    []bool{true, false, true},
+			want: want{
+				enabled: true,
+				changes: []change{
+					{Flag: cool, Enabled: true},
+					{Flag: cool, Enabled: false},
+					{Flag: cool, Enabled: true},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			f := &Flags{}
+			for _, fl := range tc.initial {
+				f.Enable(fl)
+			}
+
+			var got []change
+			f.OnChange(func(fl Flag, enabled bool) {
+				got = append(got, change{Flag: fl, Enabled: enabled})
+			})
+
+			for _, enabled := range tc.set {
+				f.Set(cool, enabled)
+			}
+
+			if diff := cmp.Diff(tc.want.enabled, f.Enabled(cool)); diff != "" {
+				t.Errorf("\n%s\nf.Enabled(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.changes, got); diff != "" {
+				t.Errorf("\n%s\nf.OnChange(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package feature
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	errGetNamespace = "cannot get namespace"
+)
+
+// A Scope determines which objects an enabled feature flag applies to. Scopes
+// allow a feature to be rolled out to some objects - for example those of one
+// team - before it is rolled out to the whole cluster.
+type Scope interface {
+	// Contains returns true if the feature flag applies to the supplied
+	// object.
+	Contains(ctx context.Context, o metav1.Object) (bool, error)
+}
+
+// A ScopeFn is a function that satisfies the Scope interface.
+type ScopeFn func(ctx context.Context, o metav1.Object) (bool, error)
+
+// Contains returns true if the feature flag applies to the supplied object.
+func (fn ScopeFn) Contains(ctx context.Context, o metav1.Object) (bool, error) {
+	return fn(ctx, o)
+}
+
+// InNamespaces returns a Scope that contains objects in any of the supplied
+// namespaces.
+func InNamespaces(namespaces ...string) Scope {
+	in := make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		in[ns] = true
+	}
+	return ScopeFn(func(_ context.Context, o metav1.Object) (bool, error) {
+		return in[o.GetNamespace()], nil
+	})
+}
+
+// MatchingLabels returns a Scope that contains objects whose labels match the
+// supplied selector.
+func MatchingLabels(s labels.Selector) Scope {
+	return ScopeFn(func(_ context.Context, o metav1.Object) (bool, error) {
+		return s.Matches(labels.Set(o.GetLabels())), nil
+	})
+}
+
+// MatchingAnnotation returns a Scope that contains objects annotated with the
+// supplied key and value, allowing users to opt individual objects into a
+// feature.
+func MatchingAnnotation(key, value string) Scope {
+	return ScopeFn(func(_ context.Context, o metav1.Object) (bool, error) {
+		v, ok := o.GetAnnotations()[key]
+		return ok && v == value, nil
+	})
+}
+
+// MatchingNamespaceLabels returns a Scope that contains objects in a namespace
+// whose labels match the supplied selector. Cluster scoped objects are never
+// contained.
+func MatchingNamespaceLabels(c client.Reader, s labels.Selector) Scope {
+	return ScopeFn(func(ctx context.Context, o metav1.Object) (bool, error) {
+		if o.GetNamespace() == "" {
+			return false, nil
+		}
+		ns := &corev1.Namespace{}
+		if err := c.Get(ctx, types.NamespacedName{Name: o.GetNamespace()}, ns); err != nil {
+			return false, errors.Wrap(err, errGetNamespace)
+		}
+		return s.Matches(labels.Set(ns.GetLabels())), nil
+	})
+}
+
+// AnyOf returns a Scope that contains objects contained by any of the supplied
+// scopes.
+func AnyOf(scopes ...Scope) Scope {
+	return ScopeFn(func(ctx context.Context, o metav1.Object) (bool, error) {
+		for _, s := range scopes {
+			ok, err := s.Contains(ctx, o)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+}
+
+// SetScope restricts the supplied feature flag to objects contained by the
+// supplied scope. A nil scope applies the feature flag to all objects. Note
+// that scopes only affect EnabledFor, not Enabled.
+func (fs *Flags) SetScope(f Flag, s Scope) {
+	fs.m.Lock()
+	defer fs.m.Unlock()
+	if s == nil {
+		delete(fs.scopes, f)
+		return
+	}
+	if fs.scopes == nil {
+		fs.scopes = make(map[Flag]Scope)
+	}
+	fs.scopes[f] = s
+}
+
+// EnabledFor returns true if the supplied feature flag is enabled, and applies
+// to the supplied object.
+func (fs *Flags) EnabledFor(ctx context.Context, f Flag, o metav1.Object) (bool, error) {
+	if fs == nil {
+		return false, nil
+	}
+	fs.m.RLock()
+	enabled, s := fs.enabled[f], fs.scopes[f]
+	fs.m.RUnlock()
+
+	if !enabled || s == nil {
+		return enabled, nil
+	}
+	return s.Contains(ctx, o)
+}
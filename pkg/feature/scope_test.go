@@ -0,0 +1,164 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package feature
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestEnabledFor(t *testing.T) {
+	var cool Flag = "cool"
+	errBoom := errors.New("boom")
+
+	team := labels.SelectorFromSet(labels.Set{"team": "cool"})
+
+	type args struct {
+		enabled bool
+		scope   Scope
+		o       metav1.Object
+	}
+	type want struct {
+		enabled bool
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Disabled": {
+			reason: "A disabled flag should not be enabled for any object, regardless of scope.",
+			args: args{
+				scope: InNamespaces("default"),
+				o:     &metav1.ObjectMeta{Namespace: "default"},
+			},
+			want: want{enabled: false},
+		},
+		"Unscoped": {
+			reason: "An enabled flag without a scope should be enabled for all objects.",
+			args: args{
+				enabled: true,
+				o:       &metav1.ObjectMeta{Namespace: "default"},
+			},
+			want: want{enabled: true},
+		},
+		"InNamespace": {
+			reason: "An enabled flag should be enabled for objects in its scope.",
+			args: args{
+				enabled: true,
+				scope:   InNamespaces("default"),
+				o:       &metav1.ObjectMeta{Namespace: "default"},
+			},
+			want: want{enabled: true},
+		},
+		"NotInNamespace": {
+			reason: "An enabled flag should not be enabled for objects outside its scope.",
+			args: args{
+				enabled: true,
+				scope:   InNamespaces("default"),
+				o:       &metav1.ObjectMeta{Namespace: "other"},
+			},
+			want: want{enabled: false},
+		},
+		"MatchingLabels": {
+			reason: "An enabled flag should be enabled for objects whose labels match its scope.",
+			args: args{
+				enabled: true,
+				scope:   MatchingLabels(team),
+				o:       &metav1.ObjectMeta{Labels: map[string]string{"team": "cool"}},
+			},
+			want: want{enabled: true},
+		},
+		"MatchingAnnotation": {
+			reason: "An enabled flag should not be enabled for objects whose annotation doesn't match its scope.",
+			args: args{
+				enabled: true,
+				scope:   MatchingAnnotation("cool", "true"),
+				o:       &metav1.ObjectMeta{Annotations: map[string]string{"cool": "false"}},
+			},
+			want: want{enabled: false},
+		},
+		"MatchingNamespaceLabels": {
+			reason: "An enabled flag should be enabled for objects in a namespace whose labels match its scope.",
+			args: args{
+				enabled: true,
+				scope: MatchingNamespaceLabels(&test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						obj.(*corev1.Namespace).SetLabels(map[string]string{"team": "cool"})
+						return nil
+					}),
+				}, team),
+				o: &metav1.ObjectMeta{Namespace: "default"},
+			},
+			want: want{enabled: true},
+		},
+		"MatchingNamespaceLabelsClusterScoped": {
+			reason: "An enabled flag scoped to namespace labels should not be enabled for cluster scoped objects.",
+			args: args{
+				enabled: true,
+				scope:   MatchingNamespaceLabels(&test.MockClient{MockGet: test.NewMockGetFn(errBoom)}, team),
+				o:       &metav1.ObjectMeta{},
+			},
+			want: want{enabled: false},
+		},
+		"MatchingNamespaceLabelsError": {
+			reason: "Errors getting the namespace should be returned.",
+			args: args{
+				enabled: true,
+				scope:   MatchingNamespaceLabels(&test.MockClient{MockGet: test.NewMockGetFn(errBoom)}, team),
+				o:       &metav1.ObjectMeta{Namespace: "default"},
+			},
+			want: want{err: errors.Wrap(errBoom, errGetNamespace)},
+		},
+		"AnyOf": {
+			reason: "An enabled flag should be enabled for objects contained by any of its scopes.",
+			args: args{
+				enabled: true,
+				scope:   AnyOf(InNamespaces("other"), MatchingAnnotation("cool", "true")),
+				o:       &metav1.ObjectMeta{Namespace: "default", Annotations: map[string]string{"cool": "true"}},
+			},
+			want: want{enabled: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fs := &Flags{}
+			fs.Set(cool, tc.args.enabled)
+			fs.SetScope(cool, tc.args.scope)
+
+			got, err := fs.EnabledFor(context.Background(), cool, tc.args.o)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nfs.EnabledFor(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.enabled, got); diff != "" {
+				t.Errorf("\n%s\nfs.EnabledFor(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
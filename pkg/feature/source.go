@@ -0,0 +1,275 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package feature
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+const (
+	errReadFile      = "cannot read feature flags file"
+	errParseFile     = "cannot parse feature flags file"
+	errCreateWatcher = "cannot create file watcher"
+	errWatchDir      = "cannot watch directory"
+	errGetConfigMap  = "cannot get feature flags ConfigMap"
+	errFmtParseFlag  = "cannot parse value %q of feature flag %q"
+)
+
+// How long to wait before watching a ConfigMap again after its watch fails.
+const rewatchInterval = 5 * time.Second
+
+// A WatchOption configures WatchFile and WatchConfigMap.
+type WatchOption func(s *source)
+
+// WithLogger configures the logger used to report feature flag changes, and
+// failures to reload feature flags.
+func WithLogger(l logging.Logger) WatchOption {
+	return func(s *source) {
+		s.log = l
+	}
+}
+
+// A source sets feature flags from key-value data. It disables flags that it
+// previously set, but that are no longer present in the data.
+type source struct {
+	flags *Flags
+	log   logging.Logger
+
+	m   sync.Mutex
+	set map[Flag]bool
+}
+
+func newSource(fs *Flags, o ...WatchOption) *source {
+	s := &source{flags: fs, log: logging.NewNopLogger(), set: map[Flag]bool{}}
+	for _, fn := range o {
+		fn(s)
+	}
+	return s
+}
+
+// apply the supplied data, which maps flag names to booleans. No flags are
+// changed if any value is not a valid boolean.
+func (s *source) apply(data map[string]string) error {
+	parsed := make(map[Flag]bool, len(data))
+	for k, v := range data {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return errors.Wrapf(err, errFmtParseFlag, v, k)
+		}
+		parsed[Flag(k)] = b
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+	for f := range s.set {
+		if _, ok := parsed[f]; !ok {
+			s.flags.Disable(f)
+			s.log.Debug("Disabled feature flag", "flag", f)
+		}
+	}
+	for f, enabled := range parsed {
+		if s.flags.Enabled(f) != enabled {
+			s.log.Debug("Changed feature flag", "flag", f, "enabled", enabled)
+		}
+		s.flags.Set(f, enabled)
+	}
+	s.set = parsed
+	return nil
+}
+
+// WatchFile sets the supplied feature flags from a YAML file that maps flag
+// names to booleans, for example:
+//
+//	EnableAlphaManagementPolicies: true
+//	EnableBetaExternalSecretStores: false
+//
+// The file is reloaded whenever it changes - for example when the ConfigMap it
+// is mounted from is updated - until the supplied context is done. Flags that
+// are removed from the file are disabled. WatchFile returns an error if the
+// file cannot be loaded initially. Subsequent failures to reload are logged,
+// and the flags are left unchanged.
+func WatchFile(ctx context.Context, path string, fs *Flags, o ...WatchOption) error {
+	s := newSource(fs, o...)
+	path = filepath.Clean(path)
+
+	load := func() error {
+		b, err := os.ReadFile(path) //nolint:gosec // The path is supplied by the provider author.
+		if err != nil {
+			return errors.Wrap(err, errReadFile)
+		}
+		raw := map[string]any{}
+		if err := yaml.Unmarshal(b, &raw); err != nil {
+			return errors.Wrap(err, errParseFile)
+		}
+		data := make(map[string]string, len(raw))
+		for k, v := range raw {
+			data[k] = fmt.Sprint(v)
+		}
+		return s.apply(data)
+	}
+
+	if err := load(); err != nil {
+		return err
+	}
+
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return errors.Wrap(err, errCreateWatcher)
+	}
+
+	// Kubernetes updates mounted ConfigMaps by atomically swapping a symlink,
+	// so we watch the directory containing the file rather than the file.
+	if err := fsw.Add(filepath.Dir(path)); err != nil {
+		_ = fsw.Close()
+		return errors.Wrap(err, errWatchDir)
+	}
+
+	go func() {
+		defer fsw.Close() //nolint:errcheck // Nothing to do if we can't close the watcher.
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-fsw.Events:
+				if !ok {
+					return
+				}
+				if err := load(); err != nil {
+					s.log.Info("Cannot reload feature flags", "error", err, "path", path)
+				}
+			case err, ok := <-fsw.Errors:
+				if !ok {
+					return
+				}
+				s.log.Info("Error watching feature flags", "error", err, "path", path)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// WatchConfigMap sets the supplied feature flags from the data of the supplied
+// ConfigMap, which maps flag names to booleans. The ConfigMap is watched for
+// changes until the supplied context is done. Flags that are removed from the
+// ConfigMap, or that were set by the ConfigMap before it was deleted, are
+// disabled. WatchConfigMap returns an error if the ConfigMap cannot be loaded
+// initially. A ConfigMap that does not exist sets no flags. Subsequent failures
+// to reload are logged, and the flags are left unchanged.
+//
+// Note that the client must be able to watch the ConfigMap. The client of a
+// controller-runtime manager cannot; use client.NewWithWatch.
+func WatchConfigMap(ctx context.Context, c client.WithWatch, nn types.NamespacedName, fs *Flags, o ...WatchOption) error {
+	s := newSource(fs, o...)
+
+	cm := &corev1.ConfigMap{}
+	err := c.Get(ctx, nn, cm)
+	if client.IgnoreNotFound(err) != nil {
+		return errors.Wrap(err, errGetConfigMap)
+	}
+	if err := s.apply(cm.Data); err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			s.watchConfigMap(ctx, c, nn, cm.GetResourceVersion())
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(rewatchInterval):
+			}
+
+			// Catch up on any changes we missed while not watching.
+			cm = &corev1.ConfigMap{}
+			if err := c.Get(ctx, nn, cm); client.IgnoreNotFound(err) != nil {
+				s.log.Info("Cannot get feature flags ConfigMap", "error", err)
+				continue
+			}
+			if err := s.apply(cm.Data); err != nil {
+				s.log.Info("Cannot reload feature flags", "error", err)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// watchConfigMap applies changes to the supplied ConfigMap until the watch
+// ends, or the supplied context is done.
+func (s *source) watchConfigMap(ctx context.Context, c client.WithWatch, nn types.NamespacedName, rv string) {
+	w, err := c.Watch(ctx, &corev1.ConfigMapList{},
+		client.InNamespace(nn.Namespace),
+		client.MatchingFieldsSelector{Selector: fields.OneTermEqualSelector("metadata.name", nn.Name)},
+		&client.ListOptions{Raw: &metav1.ListOptions{ResourceVersion: rv}},
+	)
+	if err != nil {
+		s.log.Info("Cannot watch feature flags ConfigMap", "error", err)
+		return
+	}
+	defer w.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e, ok := <-w.ResultChan():
+			if !ok {
+				return
+			}
+			switch e.Type {
+			case watch.Added, watch.Modified:
+				cm, ok := e.Object.(*corev1.ConfigMap)
+				if !ok || cm.GetName() != nn.Name {
+					continue
+				}
+				if err := s.apply(cm.Data); err != nil {
+					s.log.Info("Cannot reload feature flags", "error", err)
+				}
+			case watch.Deleted:
+				cm, ok := e.Object.(*corev1.ConfigMap)
+				if !ok || cm.GetName() != nn.Name {
+					continue
+				}
+				_ = s.apply(nil)
+			case watch.Error:
+				s.log.Info("Error watching feature flags ConfigMap", "error", kerrors.FromObject(e.Object))
+				return
+			case watch.Bookmark:
+			}
+		}
+	}
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package feature
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var (
+	alpha Flag = "EnableAlphaCool"
+	beta  Flag = "EnableBetaCool"
+)
+
+func TestSourceApply(t *testing.T) {
+	type want struct {
+		err     error
+		enabled map[Flag]bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		data   []map[string]string
+		want   want
+	}{
+		"EnableAndDisable": {
+			reason: "Flags should be enabled or disabled according to the data.",
+			data:   []map[string]string{{string(alpha): "true", string(beta): "false"}},
+			want: want{
+				enabled: map[Flag]bool{alpha: true, beta: false},
+			},
+		},
+		"RemovedFlagIsDisabled": {
+			reason: "Flags that are removed from the data should be disabled.",
+			data: []map[string]string{
+				{string(alpha): "true", string(beta): "true"},
+				{string(beta): "true"},
+			},
+			want: want{
+				enabled: map[Flag]bool{alpha: false, beta: true},
+			},
+		},
+		"InvalidValue": {
+			reason: "No flags should change if any value is not a boolean.",
+			data:   []map[string]string{{string(alpha): "true", string(beta): "maybe"}},
+			want: want{
+				err:     errors.Wrapf(func() error { _, err := strconv.ParseBool("maybe"); return err }(), errFmtParseFlag, "maybe", beta),
+				enabled: map[Flag]bool{alpha: false, beta: false},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			f := &Flags{}
+			s := newSource(f)
+
+			var err error
+			for _, d := range tc.data {
+				err = s.apply(d)
+			}
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.apply(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			got := map[Flag]bool{}
+			for fl := range tc.want.enabled {
+				got[fl] = f.Enabled(fl)
+			}
+			if diff := cmp.Diff(tc.want.enabled, got); diff != "" {
+				t.Errorf("\n%s\nf.Enabled(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// eventually returns true if fn returns true before a timeout.
+func eventually(fn func() bool) bool {
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		if fn() {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
+
+func TestWatchFile(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	path := filepath.Join(t.TempDir(), "flags.yaml")
+	if err := os.WriteFile(path, []byte("EnableAlphaCool: true\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	f := &Flags{}
+	if err := WatchFile(ctx, path, f); err != nil {
+		t.Fatalf("WatchFile(...): %s", err)
+	}
+	if !f.Enabled(alpha) {
+		t.Errorf("WatchFile(...): want %q enabled after initial load", alpha)
+	}
+
+	if err := os.WriteFile(path, []byte("EnableBetaCool: true\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if !eventually(func() bool { return !f.Enabled(alpha) && f.Enabled(beta) }) {
+		t.Errorf("WatchFile(...): want %q disabled and %q enabled after file changed", alpha, beta)
+	}
+}
+
+func TestWatchFileMissing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.yaml")
+	if err := WatchFile(context.Background(), path, &Flags{}); err == nil {
+		t.Errorf("WatchFile(...): want error loading missing file")
+	}
+}
+
+func TestWatchConfigMap(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "crossplane-system", Name: "flags"},
+		Data:       map[string]string{string(alpha): "true"},
+	}
+	c := fake.NewClientBuilder().WithObjects(cm).Build()
+
+	f := &Flags{}
+	nn := types.NamespacedName{Namespace: cm.GetNamespace(), Name: cm.GetName()}
+	if err := WatchConfigMap(ctx, c, nn, f); err != nil {
+		t.Fatalf("WatchConfigMap(...): %s", err)
+	}
+	if !f.Enabled(alpha) {
+		t.Errorf("WatchConfigMap(...): want %q enabled after initial load", alpha)
+	}
+
+	// Wait for the watch to start, then update the ConfigMap until the change
+	// is observed.
+	if !eventually(func() bool {
+		cm.Data = map[string]string{string(beta): "true"}
+		if err := c.Update(ctx, cm); err != nil {
+			return false
+		}
+		return !f.Enabled(alpha) && f.Enabled(beta)
+	}) {
+		t.Errorf("WatchConfigMap(...): want %q disabled and %q enabled after ConfigMap changed", alpha, beta)
+	}
+
+	if err := c.Delete(ctx, cm); err != nil {
+		t.Fatal(err)
+	}
+	if !eventually(func() bool { return !f.Enabled(beta) }) {
+		t.Errorf("WatchConfigMap(...): want %q disabled after ConfigMap deleted", beta)
+	}
+}
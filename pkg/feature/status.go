@@ -0,0 +1,94 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package feature
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	errWriteConfigMap = "cannot write feature flags ConfigMap"
+)
+
+// A Collector exposes a gauge per feature flag that is 1 if the flag is
+// enabled, and 0 if it is disabled. Only flags that have been explicitly
+// enabled or disabled are exposed.
+type Collector struct {
+	flags   *Flags
+	enabled *prometheus.Desc
+}
+
+// NewCollector returns a Collector that exposes the supplied feature flags.
+// Register it with the controller-runtime metrics registry, i.e.
+// metrics.Registry.MustRegister(feature.NewCollector(fs)).
+func NewCollector(fs *Flags) *Collector {
+	return &Collector{
+		flags: fs,
+		enabled: prometheus.NewDesc(
+			"crossplane_feature_flag_enabled",
+			"Whether a feature flag is enabled (1) or disabled (0).",
+			[]string{"flag"}, nil,
+		),
+	}
+}
+
+// Describe sends the descriptor of the feature flag gauge.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.enabled
+}
+
+// Collect sends the current value of each feature flag.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	for f, enabled := range c.flags.Snapshot() {
+		v := 0.0
+		if enabled {
+			v = 1.0
+		}
+		ch <- prometheus.MustNewConstMetric(c.enabled, prometheus.GaugeValue, v, string(f))
+	}
+}
+
+// WriteConfigMap writes the supplied feature flags to the data of the supplied
+// ConfigMap, creating it if it does not exist, so that operators can audit
+// which features a provider is running with. The data maps each flag that has
+// been explicitly enabled or disabled to "true" or "false"; any other data is
+// replaced. Call WriteConfigMap from a function registered using
+// Flags.OnChange to keep the ConfigMap up to date.
+func WriteConfigMap(ctx context.Context, c client.Client, nn types.NamespacedName, fs *Flags) error {
+	cm := &corev1.ConfigMap{}
+	cm.SetNamespace(nn.Namespace)
+	cm.SetName(nn.Name)
+
+	_, err := controllerutil.CreateOrUpdate(ctx, c, cm, func() error {
+		s := fs.Snapshot()
+		cm.Data = make(map[string]string, len(s))
+		for f, enabled := range s {
+			cm.Data[string(f)] = strconv.FormatBool(enabled)
+		}
+		return nil
+	})
+	return errors.Wrap(err, errWriteConfigMap)
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package feature
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestCollector(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		flags  map[Flag]bool
+		want   string
+	}{
+		"NoFlags": {
+			reason: "No gauges should be exposed if no flags were set.",
+			want:   "",
+		},
+		"SomeFlags": {
+			reason: "A gauge should be exposed for each flag that was enabled or disabled.",
+			flags:  map[Flag]bool{alpha: true, beta: false},
+			want: `
+# HELP crossplane_feature_flag_enabled Whether a feature flag is enabled (1) or disabled (0).
+# TYPE crossplane_feature_flag_enabled gauge
+crossplane_feature_flag_enabled{flag="EnableAlphaCool"} 1
+crossplane_feature_flag_enabled{flag="EnableBetaCool"} 0
+`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fs := &Flags{}
+			for f, enabled := range tc.flags {
+				fs.Set(f, enabled)
+			}
+
+			err := testutil.CollectAndCompare(NewCollector(fs), strings.NewReader(tc.want))
+			if diff := cmp.Diff(nil, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ntestutil.CollectAndCompare(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestWriteConfigMap(t *testing.T) {
+	nn := types.NamespacedName{Namespace: "crossplane-system", Name: "flags"}
+
+	cases := map[string]struct {
+		reason   string
+		existing []client.Object
+		flags    map[Flag]bool
+		want     map[string]string
+	}{
+		"Create": {
+			reason: "The ConfigMap should be created if it does not exist.",
+			flags:  map[Flag]bool{alpha: true, beta: false},
+			want:   map[string]string{string(alpha): "true", string(beta): "false"},
+		},
+		"Update": {
+			reason: "The data of an existing ConfigMap should be replaced.",
+			existing: []client.Object{&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: nn.Namespace, Name: nn.Name},
+				Data:       map[string]string{"EnableStale": "true"},
+			}},
+			flags: map[Flag]bool{alpha: true},
+			want:  map[string]string{string(alpha): "true"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithObjects(tc.existing...).Build()
+			fs := &Flags{}
+			for f, enabled := range tc.flags {
+				fs.Set(f, enabled)
+			}
+
+			if err := WriteConfigMap(context.Background(), c, nn, fs); err != nil {
+				t.Fatalf("\n%s\nWriteConfigMap(...): %s", tc.reason, err)
+			}
+
+			got := &corev1.ConfigMap{}
+			if err := c.Get(context.Background(), nn, got); err != nil {
+				t.Fatalf("\n%s\nc.Get(...): %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got.Data); diff != "" {
+				t.Errorf("\n%s\nWriteConfigMap(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
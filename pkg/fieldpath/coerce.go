@@ -0,0 +1,133 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fieldpath
+
+import (
+	"math"
+	"strconv"
+
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// OpenAPI schema types.
+const (
+	schemaTypeString  = "string"
+	schemaTypeInteger = "integer"
+	schemaTypeNumber  = "number"
+	schemaTypeBoolean = "boolean"
+	schemaTypeObject  = "object"
+	schemaTypeArray   = "array"
+)
+
+// schemaAt returns the schema of the supplied field path within the supplied
+// schema, or nil if the field path is not declared by the schema.
+func schemaAt(s *extv1.JSONSchemaProps, segments Segments) *extv1.JSONSchemaProps {
+	for _, current := range segments {
+		if s == nil {
+			return nil
+		}
+		switch current.Type {
+		case SegmentField:
+			s = fieldSchema(s, current.Field)
+		case SegmentIndex:
+			s = itemSchema(s)
+		}
+	}
+	return s
+}
+
+func fieldSchema(s *extv1.JSONSchemaProps, field string) *extv1.JSONSchemaProps {
+	if p, ok := s.Properties[field]; ok {
+		return &p
+	}
+	if s.AdditionalProperties != nil {
+		return s.AdditionalProperties.Schema
+	}
+	return nil
+}
+
+func itemSchema(s *extv1.JSONSchemaProps) *extv1.JSONSchemaProps {
+	if s.Items == nil {
+		return nil
+	}
+	return s.Items.Schema
+}
+
+// coerce the supplied JSON value to the type declared by the supplied schema.
+// Values that are already of the declared type, or for which no type is
+// declared, are returned unchanged.
+func coerce(v any, s *extv1.JSONSchemaProps) (any, error) { //nolint:gocyclo // A flat switch is the easiest way to read this.
+	if v == nil || s == nil || s.XIntOrString {
+		return v, nil
+	}
+
+	switch s.Type {
+	case schemaTypeString:
+		switch t := v.(type) {
+		case int64:
+			return strconv.FormatInt(t, 10), nil
+		case float64:
+			return strconv.FormatFloat(t, 'f', -1, 64), nil
+		case bool:
+			return strconv.FormatBool(t), nil
+		}
+	case schemaTypeInteger:
+		switch t := v.(type) {
+		case string:
+			i, err := strconv.ParseInt(t, 10, 64)
+			return i, errors.Wrapf(err, "cannot coerce %q to an integer", t)
+		case float64:
+			if t != math.Trunc(t) {
+				return nil, errors.Errorf("cannot coerce %v to an integer", t)
+			}
+			return int64(t), nil
+		}
+	case schemaTypeNumber:
+		if t, ok := v.(string); ok {
+			f, err := strconv.ParseFloat(t, 64)
+			return f, errors.Wrapf(err, "cannot coerce %q to a number", t)
+		}
+	case schemaTypeBoolean:
+		if t, ok := v.(string); ok {
+			b, err := strconv.ParseBool(t)
+			return b, errors.Wrapf(err, "cannot coerce %q to a boolean", t)
+		}
+	case schemaTypeObject:
+		if t, ok := v.(map[string]any); ok {
+			for k, fv := range t {
+				c, err := coerce(fv, fieldSchema(s, k))
+				if err != nil {
+					return nil, errors.Wrapf(err, "cannot coerce field %q", k)
+				}
+				t[k] = c
+			}
+		}
+	case schemaTypeArray:
+		if t, ok := v.([]any); ok {
+			for i, iv := range t {
+				c, err := coerce(iv, itemSchema(s))
+				if err != nil {
+					return nil, errors.Wrapf(err, "cannot coerce element %d", i)
+				}
+				t[i] = c
+			}
+		}
+	}
+	return v, nil
+}
@@ -19,6 +19,7 @@ package fieldpath
 import (
 	"strconv"
 
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/json"
 
@@ -51,6 +52,7 @@ func IsNotFound(err error) bool {
 type Paved struct {
 	object            map[string]any
 	maxFieldPathIndex uint
+	schema            *extv1.JSONSchemaProps
 }
 
 // PavedOption can be used to configure a Paved behavior.
@@ -81,6 +83,17 @@ func WithMaxFieldPathIndex(max uint) PavedOption {
 	}
 }
 
+// WithSchema returns a PavedOption that coerces values set at a field path to
+// the type the supplied OpenAPI schema declares for that field path. This
+// allows, for example, the string "3" read from an annotation to be set as the
+// integer 3. Values set at field paths that are not declared by the schema are
+// not coerced.
+func WithSchema(s *extv1.JSONSchemaProps) PavedOption {
+	return func(paved *Paved) {
+		paved.schema = s
+	}
+}
+
 func (p *Paved) maxFieldPathIndexEnabled() bool {
 	return p.maxFieldPathIndex > 0
 }
@@ -367,6 +380,12 @@ func (p *Paved) setValue(s Segments, value any) error {
 		return err
 	}
 
+	if p.schema != nil {
+		if v, err = coerce(v, schemaAt(p.schema, s)); err != nil {
+			return errors.Wrapf(err, "cannot coerce value for %s", s)
+		}
+	}
+
 	if err := p.validateSegments(s); err != nil {
 		return err
 	}
@@ -18,10 +18,12 @@ package fieldpath
 
 import (
 	"fmt"
+	"strconv"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/json"
@@ -591,6 +593,32 @@ func TestGetInteger(t *testing.T) {
 }
 
 func TestSetValue(t *testing.T) {
+	schema := &extv1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]extv1.JSONSchemaProps{
+			"spec": {
+				Type: "object",
+				Properties: map[string]extv1.JSONSchemaProps{
+					"name":     {Type: "string"},
+					"replicas": {Type: "integer"},
+					"ports": {
+						Type: "array",
+						Items: &extv1.JSONSchemaPropsOrArray{Schema: &extv1.JSONSchemaProps{
+							Type: "object",
+							Properties: map[string]extv1.JSONSchemaProps{
+								"enabled": {Type: "boolean"},
+								"weights": {
+									Type:                 "object",
+									AdditionalProperties: &extv1.JSONSchemaPropsOrBool{Schema: &extv1.JSONSchemaProps{Type: "number"}},
+								},
+							},
+						}},
+					},
+				},
+			},
+		},
+	}
+
 	type args struct {
 		path  string
 		value any
@@ -845,6 +873,86 @@ func TestSetValue(t *testing.T) {
 				err:    errors.Wrap(errors.New("unexpected ']' at position 5"), "cannot parse path \"spec[]\""),
 			},
 		},
+		"CoerceStringToInteger": {
+			reason: "A string should be coerced to an integer if the schema says so",
+			data:   []byte(`{}`),
+			args: args{
+				path:  "spec.replicas",
+				value: "3",
+				opts:  []PavedOption{WithSchema(schema)},
+			},
+			want: want{
+				object: map[string]any{
+					"spec": map[string]any{
+						"replicas": int64(3),
+					},
+				},
+			},
+		},
+		"CoerceIntoArrayAndMap": {
+			reason: "Values should be coerced according to the schemas of array items and map values",
+			data:   []byte(`{}`),
+			args: args{
+				path:  "spec.ports[0]",
+				value: map[string]any{"enabled": "true", "weights": map[string]any{"a": "0.5"}},
+				opts:  []PavedOption{WithSchema(schema)},
+			},
+			want: want{
+				object: map[string]any{
+					"spec": map[string]any{
+						"ports": []any{
+							map[string]any{"enabled": true, "weights": map[string]any{"a": 0.5}},
+						},
+					},
+				},
+			},
+		},
+		"CoerceNumberToString": {
+			reason: "A number should be coerced to a string if the schema says so",
+			data:   []byte(`{}`),
+			args: args{
+				path:  "spec.name",
+				value: 42,
+				opts:  []PavedOption{WithSchema(schema)},
+			},
+			want: want{
+				object: map[string]any{
+					"spec": map[string]any{
+						"name": "42",
+					},
+				},
+			},
+		},
+		"NotInSchema": {
+			reason: "A value at a field path not declared by the schema should not be coerced",
+			data:   []byte(`{}`),
+			args: args{
+				path:  "spec.other",
+				value: "3",
+				opts:  []PavedOption{WithSchema(schema)},
+			},
+			want: want{
+				object: map[string]any{
+					"spec": map[string]any{
+						"other": "3",
+					},
+				},
+			},
+		},
+		"CannotCoerce": {
+			reason: "An error should be returned if a value cannot be coerced",
+			data:   []byte(`{}`),
+			args: args{
+				path:  "spec.replicas",
+				value: "three",
+				opts:  []PavedOption{WithSchema(schema)},
+			},
+			want: want{
+				object: map[string]any{},
+				err: errors.Wrap(errors.Wrapf(&strconv.NumError{Func: "ParseInt", Num: "three", Err: strconv.ErrSyntax}, "cannot coerce %q to an integer", "three"),
+					"cannot coerce value for spec.replicas"),
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -0,0 +1,144 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	errFmtNoProgress   = "no reconcile has succeeded in %s"
+	errFmtQueueDepth   = "workqueue depth %v exceeds %v"
+	errGatherMetrics   = "cannot gather metrics"
+	metricQueueDepth   = "workqueue_depth"
+	labelQueueName     = "name"
+	defaultPingTimeout = 5 * time.Second
+)
+
+// A ReconcileTracker tracks whether a controller is making progress. It is
+// unhealthy if reconciles have been attempted for longer than its maximum age
+// without any succeeding. An idle controller is healthy.
+type ReconcileTracker struct {
+	maxAge time.Duration
+	now    func() time.Time
+
+	mu      sync.Mutex
+	pending time.Time
+}
+
+// NewReconcileTracker returns a ReconcileTracker that is unhealthy if no
+// reconcile has succeeded within maxAge of a reconcile being attempted.
+func NewReconcileTracker(maxAge time.Duration) *ReconcileTracker {
+	return &ReconcileTracker{maxAge: maxAge, now: time.Now}
+}
+
+// Started records that a reconcile was attempted.
+func (t *ReconcileTracker) Started() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.pending.IsZero() {
+		t.pending = t.now()
+	}
+}
+
+// Succeeded records that a reconcile succeeded.
+func (t *ReconcileTracker) Succeeded() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending = time.Time{}
+}
+
+// Check returns an error if reconciles have been attempted for longer than
+// the maximum age without any succeeding.
+func (t *ReconcileTracker) Check(_ context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.pending.IsZero() && t.now().Sub(t.pending) > t.maxAge {
+		return errors.Errorf(errFmtNoProgress, t.maxAge)
+	}
+	return nil
+}
+
+// Track returns a reconcile.Reconciler that records each reconcile of the
+// supplied Reconciler. A reconcile succeeds if it returns no error.
+func (t *ReconcileTracker) Track(r reconcile.Reconciler) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		t.Started()
+		result, err := r.Reconcile(ctx, req)
+		if err == nil {
+			t.Succeeded()
+		}
+		return result, err
+	})
+}
+
+// QueueDepth returns a Checker that is unhealthy if the depth of the named
+// controller's workqueue exceeds max. Workqueue depth is read from the
+// workqueue_depth metric exposed by controller-runtime.
+func QueueDepth(controller string, max float64) Checker {
+	return QueueDepthFrom(metrics.Registry, controller, max)
+}
+
+// QueueDepthFrom is like QueueDepth, but reads the workqueue_depth metric
+// from the supplied prometheus.Gatherer.
+func QueueDepthFrom(g prometheus.Gatherer, controller string, max float64) Checker {
+	return CheckerFn(func(_ context.Context) error {
+		mfs, err := g.Gather()
+		if err != nil {
+			return errors.Wrap(err, errGatherMetrics)
+		}
+		for _, mf := range mfs {
+			if mf.GetName() != metricQueueDepth {
+				continue
+			}
+			for _, m := range mf.GetMetric() {
+				for _, l := range m.GetLabel() {
+					if l.GetName() != labelQueueName || l.GetValue() != controller {
+						continue
+					}
+					if d := m.GetGauge().GetValue(); d > max {
+						return errors.Errorf(errFmtQueueDepth, d, max)
+					}
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// Ping returns a Checker that is unhealthy if the supplied function returns an
+// error, for example to check connectivity to an external secret store. The
+// function is passed a context that is cancelled after the supplied timeout. A
+// timeout of zero or less uses a default of five seconds.
+func Ping(fn func(ctx context.Context) error, timeout time.Duration) Checker {
+	if timeout <= 0 {
+		timeout = defaultPingTimeout
+	}
+	return CheckerFn(func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return fn(ctx)
+	})
+}
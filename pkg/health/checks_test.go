@@ -0,0 +1,151 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestReconcileTracker(t *testing.T) {
+	errBoom := errors.New("boom")
+	maxAge := time.Minute
+
+	type args struct {
+		errs    []error
+		elapsed time.Duration
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"Idle": {
+			reason: "A controller that hasn't attempted a reconcile should be healthy.",
+			args: args{
+				elapsed: time.Hour,
+			},
+		},
+		"Succeeded": {
+			reason: "A controller whose last reconcile succeeded should be healthy.",
+			args: args{
+				errs:    []error{errBoom, nil},
+				elapsed: time.Hour,
+			},
+		},
+		"RecentlyFailing": {
+			reason: "A controller that started failing recently should be healthy.",
+			args: args{
+				errs:    []error{nil, errBoom},
+				elapsed: time.Second,
+			},
+		},
+		"Failing": {
+			reason: "A controller that has been failing for longer than the maximum age should be unhealthy.",
+			args: args{
+				errs:    []error{nil, errBoom, errBoom},
+				elapsed: time.Hour,
+			},
+			want: errors.Errorf(errFmtNoProgress, maxAge),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			now := time.Now()
+			tr := NewReconcileTracker(maxAge)
+			tr.now = func() time.Time { return now }
+
+			for _, err := range tc.args.errs {
+				err := err
+				r := tr.Track(reconcile.Func(func(_ context.Context, _ reconcile.Request) (reconcile.Result, error) {
+					return reconcile.Result{}, err
+				}))
+				if _, got := r.Reconcile(context.Background(), reconcile.Request{}); !errors.Is(got, err) {
+					t.Errorf("\n%s\nr.Reconcile(...): want error %v, got %v", tc.reason, err, got)
+				}
+			}
+
+			now = now.Add(tc.args.elapsed)
+			err := tr.Check(context.Background())
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ntr.Check(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestQueueDepth(t *testing.T) {
+	type args struct {
+		controller string
+		depth      float64
+		max        float64
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"Shallow": {
+			reason: "A workqueue that isn't deeper than the maximum should be healthy.",
+			args: args{
+				controller: "cool",
+				depth:      10,
+				max:        10,
+			},
+		},
+		"Deep": {
+			reason: "A workqueue that is deeper than the maximum should be unhealthy.",
+			args: args{
+				controller: "cool",
+				depth:      11,
+				max:        10,
+			},
+			want: errors.Errorf(errFmtQueueDepth, 11.0, 10.0),
+		},
+		"OtherController": {
+			reason: "Only the named controller's workqueue should be checked.",
+			args: args{
+				controller: "other",
+				depth:      11,
+				max:        10,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			depth := prometheus.NewGaugeVec(prometheus.GaugeOpts{Subsystem: "workqueue", Name: "depth"}, []string{"name"})
+			depth.WithLabelValues("cool").Set(tc.args.depth)
+			reg := prometheus.NewRegistry()
+			reg.MustRegister(depth)
+
+			err := QueueDepthFrom(reg, tc.args.controller, tc.args.max).Check(context.Background())
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nQueueDepthFrom(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package health aggregates the health of the controllers and clients that
+// make up a provider, for use by liveness and readiness probes.
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	errFmtChecksFailed = "%d of %d health checks failed: %s"
+	errAddHealthz      = "cannot add liveness check to manager"
+	errAddReadyz       = "cannot add readiness check to manager"
+)
+
+// A Checker checks whether something is healthy.
+type Checker interface {
+	// Check returns an error if the thing being checked is unhealthy.
+	Check(ctx context.Context) error
+}
+
+// A CheckerFn is a function that satisfies the Checker interface.
+type CheckerFn func(ctx context.Context) error
+
+// Check returns an error if the thing being checked is unhealthy.
+func (fn CheckerFn) Check(ctx context.Context) error {
+	return fn(ctx)
+}
+
+// A Registry of named Checkers. A Registry is healthy only if all of its
+// Checkers are healthy.
+type Registry struct {
+	mu     sync.RWMutex
+	checks map[string]Checker
+}
+
+// NewRegistry returns an empty Registry, which is always healthy.
+func NewRegistry() *Registry {
+	return &Registry{checks: make(map[string]Checker)}
+}
+
+// Register the supplied Checker under the supplied name, replacing any Checker
+// already registered under that name.
+func (r *Registry) Register(name string, c Checker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks[name] = c
+}
+
+// Unregister the Checker registered under the supplied name, if any.
+func (r *Registry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.checks, name)
+}
+
+type result struct {
+	name string
+	err  error
+}
+
+// run all registered checks, ordered by name.
+func (r *Registry) run(ctx context.Context) []result {
+	r.mu.RLock()
+	names := make([]string, 0, len(r.checks))
+	checks := make(map[string]Checker, len(r.checks))
+	for name, c := range r.checks {
+		names = append(names, name)
+		checks[name] = c
+	}
+	r.mu.RUnlock()
+
+	sort.Strings(names)
+	results := make([]result, len(names))
+	for i, name := range names {
+		results[i] = result{name: name, err: checks[name].Check(ctx)}
+	}
+	return results
+}
+
+// Check returns an error describing every registered Checker that is
+// unhealthy.
+func (r *Registry) Check(ctx context.Context) error {
+	results := r.run(ctx)
+	failed := make([]string, 0)
+	for _, res := range results {
+		if res.err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %s", res.name, res.err))
+		}
+	}
+	if len(failed) > 0 {
+		return errors.Errorf(errFmtChecksFailed, len(failed), len(results), strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// Healthz returns a healthz.Checker that checks the Registry, suitable for
+// adding to a controller manager's liveness or readiness checks.
+func (r *Registry) Healthz() healthz.Checker {
+	return func(req *http.Request) error {
+		return r.Check(req.Context())
+	}
+}
+
+// ServeHTTP reports the result of each registered check. It responds with
+// status 200 if all checks pass, and 503 otherwise.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	results := r.run(req.Context())
+
+	b := &strings.Builder{}
+	healthy := true
+	for _, res := range results {
+		if res.err != nil {
+			healthy = false
+			fmt.Fprintf(b, "[-]%s failed: %s\n", res.name, res.err)
+			continue
+		}
+		fmt.Fprintf(b, "[+]%s ok\n", res.name)
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if !healthy {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		b.WriteString("health check failed\n")
+	} else {
+		b.WriteString("health check passed\n")
+	}
+	_, _ = w.Write([]byte(b.String()))
+}
+
+// A ProbeAdder can add liveness and readiness checks. It is satisfied by a
+// controller-runtime manager, which serves them at /healthz and /readyz.
+type ProbeAdder interface {
+	AddHealthzCheck(name string, check healthz.Checker) error
+	AddReadyzCheck(name string, check healthz.Checker) error
+}
+
+// AddToManager adds the supplied liveness and readiness Registries to the
+// supplied manager under the supplied name. Either Registry may be nil.
+func AddToManager(m ProbeAdder, name string, live, ready *Registry) error {
+	if live != nil {
+		if err := m.AddHealthzCheck(name, live.Healthz()); err != nil {
+			return errors.Wrap(err, errAddHealthz)
+		}
+	}
+	if ready != nil {
+		if err := m.AddReadyzCheck(name, ready.Healthz()); err != nil {
+			return errors.Wrap(err, errAddReadyz)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestRegistry(t *testing.T) {
+	errBoom := errors.New("boom")
+	ok := CheckerFn(func(_ context.Context) error { return nil })
+	boom := CheckerFn(func(_ context.Context) error { return errBoom })
+
+	type want struct {
+		err    error
+		status int
+		body   string
+	}
+	cases := map[string]struct {
+		reason string
+		checks map[string]Checker
+		want   want
+	}{
+		"Empty": {
+			reason: "A Registry with no checks should be healthy.",
+			want: want{
+				status: http.StatusOK,
+				body:   "health check passed\n",
+			},
+		},
+		"Healthy": {
+			reason: "A Registry should be healthy if all of its checks pass.",
+			checks: map[string]Checker{"b": ok, "a": ok},
+			want: want{
+				status: http.StatusOK,
+				body:   "[+]a ok\n[+]b ok\nhealth check passed\n",
+			},
+		},
+		"Unhealthy": {
+			reason: "A Registry should be unhealthy if any of its checks fail.",
+			checks: map[string]Checker{"a": ok, "b": boom, "c": boom},
+			want: want{
+				err:    errors.Errorf(errFmtChecksFailed, 2, 3, "b: boom; c: boom"),
+				status: http.StatusServiceUnavailable,
+				body:   "[+]a ok\n[-]b failed: boom\n[-]c failed: boom\nhealth check failed\n",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewRegistry()
+			for name, c := range tc.checks {
+				r.Register(name, c)
+			}
+
+			err := r.Check(context.Background())
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Check(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+			if diff := cmp.Diff(tc.want.status, w.Code); diff != "" {
+				t.Errorf("\n%s\nr.ServeHTTP(...): -want status, +got status:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.body, w.Body.String()); diff != "" {
+				t.Errorf("\n%s\nr.ServeHTTP(...): -want body, +got body:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -74,7 +74,8 @@ func (l nopLogger) WithValues(_ ...any) Logger { return nopLogger{} }
 // which may be satisfied in turn by various logging implementations (Zap, klog,
 // etc). Debug messages are logged at V(1).
 func NewLogrLogger(l logr.Logger) Logger {
-	return logrLogger{log: l}
+	// Skip this package's frames when reporting the caller of a message.
+	return logrLogger{log: l.WithCallDepth(1)}
 }
 
 type logrLogger struct {
@@ -0,0 +1,151 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"io"
+
+	"github.com/go-logr/logr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	crzap "sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+// An Encoding of log messages.
+type Encoding string
+
+// Log message encodings.
+const (
+	EncodingJSON    Encoding = "json"
+	EncodingConsole Encoding = "console"
+)
+
+// A TimestampFormat of log messages.
+type TimestampFormat string
+
+// Log message timestamp formats.
+const (
+	TimestampRFC3339     TimestampFormat = "rfc3339"
+	TimestampRFC3339Nano TimestampFormat = "rfc3339nano"
+	TimestampEpoch       TimestampFormat = "epoch"
+)
+
+type zapConfig struct {
+	debug      bool
+	encoding   Encoding
+	timestamps TimestampFormat
+	caller     bool
+	stacktrace zapcore.Level
+	dest       io.Writer
+}
+
+// A ZapOption configures a zap backed logr.Logger.
+type ZapOption func(c *zapConfig)
+
+// WithDebug configures whether debug messages are logged.
+func WithDebug(debug bool) ZapOption {
+	return func(c *zapConfig) {
+		c.debug = debug
+	}
+}
+
+// WithEncoding configures how log messages are encoded.
+func WithEncoding(e Encoding) ZapOption {
+	return func(c *zapConfig) {
+		c.encoding = e
+	}
+}
+
+// WithTimestampFormat configures how log message timestamps are formatted.
+func WithTimestampFormat(f TimestampFormat) ZapOption {
+	return func(c *zapConfig) {
+		c.timestamps = f
+	}
+}
+
+// WithCaller configures whether log messages include the file and line that
+// logged them.
+func WithCaller(caller bool) ZapOption {
+	return func(c *zapConfig) {
+		c.caller = caller
+	}
+}
+
+// WithStacktraceLevel configures the level at and above which log messages
+// include a stacktrace.
+func WithStacktraceLevel(l zapcore.Level) ZapOption {
+	return func(c *zapConfig) {
+		c.stacktrace = l
+	}
+}
+
+// WithDestination configures where log messages are written. Messages are
+// written to stderr by default.
+func WithDestination(w io.Writer) ZapOption {
+	return func(c *zapConfig) {
+		c.dest = w
+	}
+}
+
+// NewZapLogr returns a zap backed logr.Logger, allowing providers to match an
+// organisation's log schema. Wrap it using NewLogrLogger to satisfy Logger. By
+// default messages are JSON encoded with RFC3339 timestamps and no caller,
+// debug messages are not logged, and only error messages include a stacktrace.
+func NewZapLogr(o ...ZapOption) logr.Logger {
+	c := &zapConfig{
+		encoding:   EncodingJSON,
+		timestamps: TimestampRFC3339,
+		stacktrace: zapcore.ErrorLevel,
+	}
+	for _, fn := range o {
+		fn(c)
+	}
+
+	level := zapcore.InfoLevel
+	if c.debug {
+		level = zapcore.DebugLevel
+	}
+
+	opts := []crzap.Opts{
+		crzap.Level(level),
+		crzap.StacktraceLevel(c.stacktrace),
+		crzap.RawZapOpts(zap.WithCaller(c.caller)),
+		func(zo *crzap.Options) { zo.TimeEncoder = timeEncoder(c.timestamps) },
+	}
+	if c.dest != nil {
+		opts = append(opts, crzap.WriteTo(c.dest))
+	}
+	switch c.encoding {
+	case EncodingConsole:
+		opts = append(opts, crzap.ConsoleEncoder())
+	case EncodingJSON:
+		opts = append(opts, crzap.JSONEncoder())
+	}
+	return crzap.New(opts...)
+}
+
+func timeEncoder(f TimestampFormat) zapcore.TimeEncoder {
+	switch f {
+	case TimestampEpoch:
+		return zapcore.EpochTimeEncoder
+	case TimestampRFC3339Nano:
+		return zapcore.RFC3339NanoTimeEncoder
+	case TimestampRFC3339:
+		return zapcore.RFC3339TimeEncoder
+	}
+	return zapcore.RFC3339TimeEncoder
+}
@@ -18,9 +18,12 @@ limitations under the License.
 package meta
 
 import (
+	"encoding/json"
 	"fmt"
 	"hash/fnv"
+	"regexp"
 	"strings"
+	"text/template"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -38,6 +41,11 @@ const (
 	// systems.
 	AnnotationKeyExternalName = "crossplane.io/external-name"
 
+	// AnnotationKeyExternalNameHistory is the key in the annotations map of
+	// a resource for its previous external names, most recent first. Its
+	// value must be a JSON array of strings.
+	AnnotationKeyExternalNameHistory = "crossplane.io/external-name-history"
+
 	// AnnotationKeyExternalCreatePending is the key in the annotations map
 	// of a resource that indicates the last time creation of the external
 	// resource was pending (i.e. about to happen). Its value must be an
@@ -58,12 +66,146 @@ const (
 	// resource failed. Its value must be an RFC3999 timestamp.
 	AnnotationKeyExternalCreateFailed = "crossplane.io/external-create-failed"
 
+	// AnnotationKeyExternalUpdatePending is the key in the annotations map
+	// of a resource that indicates the last time an update of the external
+	// resource was started. Its value must be an RFC3339 timestamp.
+	AnnotationKeyExternalUpdatePending = "crossplane.io/external-update-pending"
+
+	// AnnotationKeyExternalUpdateSucceeded is the key in the annotations
+	// map of a resource that indicates the last time the external resource
+	// was updated successfully. Its value must be an RFC3339 timestamp.
+	AnnotationKeyExternalUpdateSucceeded = "crossplane.io/external-update-succeeded"
+
+	// AnnotationKeyExternalUpdateFailed is the key in the annotations map
+	// of a resource that indicates the last time an update of the external
+	// resource failed. Its value must be an RFC3339 timestamp.
+	AnnotationKeyExternalUpdateFailed = "crossplane.io/external-update-failed"
+
+	// AnnotationKeyExternalUpdateError is the key in the annotations map of
+	// a resource that contains the error that caused the most recent update
+	// of the external resource to fail.
+	AnnotationKeyExternalUpdateError = "crossplane.io/external-update-error"
+
+	// AnnotationKeyExternalDeletePending is the key in the annotations map
+	// of a resource that indicates the last time deletion of the external
+	// resource was started. Its value must be an RFC3339 timestamp.
+	AnnotationKeyExternalDeletePending = "crossplane.io/external-delete-pending"
+
+	// AnnotationKeyExternalDeleteSucceeded is the key in the annotations
+	// map of a resource that indicates the last time deletion of the
+	// external resource was requested successfully. Its value must be an
+	// RFC3339 timestamp.
+	AnnotationKeyExternalDeleteSucceeded = "crossplane.io/external-delete-succeeded"
+
+	// AnnotationKeyExternalDeleteFailed is the key in the annotations map
+	// of a resource that indicates the last time deletion of the external
+	// resource failed. Its value must be an RFC3339 timestamp.
+	AnnotationKeyExternalDeleteFailed = "crossplane.io/external-delete-failed"
+
+	// AnnotationKeyExternalDeleteError is the key in the annotations map of
+	// a resource that contains the error that caused the most recent
+	// deletion of the external resource to fail.
+	AnnotationKeyExternalDeleteError = "crossplane.io/external-delete-error"
+
 	// AnnotationKeyReconciliationPaused is the key in the annotations map
 	// of a resource that indicates that further reconciliations on the
 	// resource are paused. All create/update/delete/generic events on
 	// the resource will be filtered and thus no further reconcile requests
 	// will be queued for the resource.
 	AnnotationKeyReconciliationPaused = "crossplane.io/paused"
+
+	// AnnotationKeyDefaultProviderConfig is the key in the annotations map
+	// of a namespace that specifies the name of the ProviderConfig used by
+	// managed resources in that namespace that don't reference one.
+	AnnotationKeyDefaultProviderConfig = "crossplane.io/default-provider-config"
+
+	// AnnotationKeyLastAppliedConfiguration is the key in the annotations map
+	// of a resource that contains the JSON encoded desired state most
+	// recently applied to it. It is used to determine which fields were
+	// removed from the desired state, and thus must be removed from the
+	// resource.
+	AnnotationKeyLastAppliedConfiguration = "crossplane.io/last-applied-configuration"
+
+	// AnnotationKeyLateInitialized is the key in the annotations map of a
+	// resource that contains the JSON encoded values its fields were late
+	// initialized to, by field path. It is used to detect late initialized
+	// fields that were subsequently changed both in the resource's spec and
+	// in the external system.
+	AnnotationKeyLateInitialized = "crossplane.io/late-initialized"
+
+	// AnnotationKeyExternalCreateUID is the key in the annotations map of a
+	// managed resource that contains the UID of the managed resource that
+	// created its external resource. A managed resource whose UID differs
+	// from this annotation was restored from a backup, for example by Velero.
+	AnnotationKeyExternalCreateUID = "crossplane.io/external-create-uid"
+
+	// AnnotationKeyAdoptConnectionFrom is the key in the annotations map of a
+	// managed resource that contains the UID of a previous managed resource
+	// whose connection secret it should adopt, for example because the
+	// previous managed resource was deleted with an Orphan deletion policy
+	// and this one now manages the same external resource. The annotation is
+	// removed once the connection secret is adopted.
+	AnnotationKeyAdoptConnectionFrom = "crossplane.io/adopt-connection-from"
+
+	// AnnotationKeyDryRun is the key in the annotations map of a managed
+	// resource that indicates it is in dry-run mode. A managed resource in
+	// dry-run mode reports what it would do to its external resource, but
+	// does not do it.
+	AnnotationKeyDryRun = "crossplane.io/dry-run"
+
+	// AnnotationKeyDriftIgnore is the key in the annotations map of a
+	// resource that contains a comma separated list of field paths, e.g.
+	// spec.forProvider.tags,spec.forProvider.desiredCount. Drift of these
+	// fields (and fields beneath them) from their desired state is ignored,
+	// because they are managed outside Crossplane.
+	AnnotationKeyDriftIgnore = "drift.crossplane.io/ignore"
+
+	// AnnotationKeyIdempotencyToken is the key in the annotations map of a
+	// managed resource that contains the idempotency token used to create its
+	// external resource. Reusing the token when a create call is retried lets
+	// the external system, or the provider, recognise that the external
+	// resource was already created by an earlier attempt.
+	AnnotationKeyIdempotencyToken = "crossplane.io/idempotency-token"
+)
+
+const (
+	errParseExternalNameTemplate  = "cannot parse external name template"
+	errRenderExternalNameTemplate = "cannot render external name template"
+	errEmptyExternalName          = "external name template rendered an empty name"
+	errFmtExternalNameTooLong     = "external name %q is longer than %d characters"
+	errFmtExternalNamePattern     = "external name %q does not match pattern %q"
+
+	errFmtCompilePropagationPattern = "cannot compile propagation pattern %q"
+)
+
+// Crossplane labels resources composed by a claim's composite resource with
+// the following keys.
+const (
+	// LabelKeyClaimName is the key of the label that contains the name of the
+	// claim a resource was composed for.
+	LabelKeyClaimName = "crossplane.io/claim-name"
+
+	// LabelKeyClaimNamespace is the key of the label that contains the
+	// namespace of the claim a resource was composed for.
+	LabelKeyClaimNamespace = "crossplane.io/claim-namespace"
+
+	// LabelKeyComposite is the key of the label that contains the name of the
+	// composite resource a resource was composed for.
+	LabelKeyComposite = "crossplane.io/composite"
+)
+
+// Managed resources may be labelled with the UIDs of the composite resource
+// and claim they were composed for, in addition to their names, so that labels
+// of deleted and recreated owners of the same name aren't mistaken for labels
+// of the current owners.
+const (
+	// LabelKeyCompositeUID is the key of the label that contains the UID of
+	// the composite resource a resource was composed for.
+	LabelKeyCompositeUID = "crossplane.io/composite-uid"
+
+	// LabelKeyClaimUID is the key of the label that contains the UID of the
+	// claim a resource was composed for.
+	LabelKeyClaimUID = "crossplane.io/claim-uid"
 )
 
 // Supported resources with all of these annotations will be fully or partially
@@ -101,6 +243,7 @@ func TypedReferenceTo(o metav1.Object, of schema.GroupVersionKind) *xpv1.TypedRe
 		APIVersion: v,
 		Kind:       k,
 		Name:       o.GetName(),
+		Namespace:  o.GetNamespace(),
 		UID:        o.GetUID(),
 	}
 }
@@ -254,6 +397,145 @@ func RemoveAnnotations(o metav1.Object, annotations ...string) {
 	o.SetAnnotations(a)
 }
 
+// SetCompositeLabels labels the supplied object with the name and UID of the
+// supplied composite resource. It returns true if the object's labels changed.
+func SetCompositeLabels(o, composite metav1.Object) bool {
+	return MergeLabels(o, map[string]string{
+		LabelKeyComposite:    composite.GetName(),
+		LabelKeyCompositeUID: string(composite.GetUID()),
+	})
+}
+
+// RemoveCompositeLabels removes the labels that identify the composite
+// resource of the supplied object. It returns true if the object's labels
+// changed.
+func RemoveCompositeLabels(o metav1.Object) bool {
+	return removeKeys(o, LabelKeyComposite, LabelKeyCompositeUID)
+}
+
+// SetClaimLabels labels the supplied object with the name, namespace, and UID
+// of the supplied claim. It returns true if the object's labels changed.
+func SetClaimLabels(o, claim metav1.Object) bool {
+	return MergeLabels(o, map[string]string{
+		LabelKeyClaimName:      claim.GetName(),
+		LabelKeyClaimNamespace: claim.GetNamespace(),
+		LabelKeyClaimUID:       string(claim.GetUID()),
+	})
+}
+
+// RemoveClaimLabels removes the labels that identify the claim of the
+// supplied object. It returns true if the object's labels changed.
+func RemoveClaimLabels(o metav1.Object) bool {
+	return removeKeys(o, LabelKeyClaimName, LabelKeyClaimNamespace, LabelKeyClaimUID)
+}
+
+func removeKeys(o metav1.Object, keys ...string) bool {
+	l := o.GetLabels()
+	changed := false
+	for _, k := range keys {
+		if _, ok := l[k]; ok {
+			delete(l, k)
+			changed = true
+		}
+	}
+	if changed {
+		o.SetLabels(l)
+	}
+	return changed
+}
+
+// MergeLabels adds the supplied labels to the supplied object. It returns true
+// if the object's labels changed, so that callers may skip no-op updates.
+func MergeLabels(o metav1.Object, labels map[string]string) bool {
+	l, changed := merge(o.GetLabels(), labels)
+	o.SetLabels(l)
+	return changed
+}
+
+// MergeAnnotations adds the supplied annotations to the supplied object. It
+// returns true if the object's annotations changed, so that callers may skip
+// no-op updates.
+func MergeAnnotations(o metav1.Object, annotations map[string]string) bool {
+	a, changed := merge(o.GetAnnotations(), annotations)
+	o.SetAnnotations(a)
+	return changed
+}
+
+// RemoveLabelsWithPrefix removes labels whose keys begin with any of the
+// supplied prefixes from the supplied object. It returns true if the object's
+// labels changed.
+func RemoveLabelsWithPrefix(o metav1.Object, prefixes ...string) bool {
+	l, changed := removeWithPrefix(o.GetLabels(), nil, prefixes...)
+	o.SetLabels(l)
+	return changed
+}
+
+// RemoveAnnotationsWithPrefix removes annotations whose keys begin with any of
+// the supplied prefixes from the supplied object. It returns true if the
+// object's annotations changed.
+func RemoveAnnotationsWithPrefix(o metav1.Object, prefixes ...string) bool {
+	a, changed := removeWithPrefix(o.GetAnnotations(), nil, prefixes...)
+	o.SetAnnotations(a)
+	return changed
+}
+
+// ReplaceLabelsWithPrefix makes the supplied labels the only labels of the
+// supplied object whose keys begin with the supplied prefix, i.e. the labels
+// under the prefix are managed by the caller. Other labels are untouched. It
+// returns true if the object's labels changed.
+func ReplaceLabelsWithPrefix(o metav1.Object, prefix string, labels map[string]string) bool {
+	l, removed := removeWithPrefix(o.GetLabels(), labels, prefix)
+	l, merged := merge(l, labels)
+	o.SetLabels(l)
+	return removed || merged
+}
+
+// ReplaceAnnotationsWithPrefix makes the supplied annotations the only
+// annotations of the supplied object whose keys begin with the supplied
+// prefix, i.e. the annotations under the prefix are managed by the caller.
+// Other annotations are untouched. It returns true if the object's annotations
+// changed.
+func ReplaceAnnotationsWithPrefix(o metav1.Object, prefix string, annotations map[string]string) bool {
+	a, removed := removeWithPrefix(o.GetAnnotations(), annotations, prefix)
+	a, merged := merge(a, annotations)
+	o.SetAnnotations(a)
+	return removed || merged
+}
+
+func merge(to, from map[string]string) (map[string]string, bool) {
+	changed := false
+	for k, v := range from {
+		if cur, ok := to[k]; ok && cur == v {
+			continue
+		}
+		if to == nil {
+			to = make(map[string]string, len(from))
+		}
+		to[k] = v
+		changed = true
+	}
+	return to, changed
+}
+
+// removeWithPrefix removes keys that begin with any of the supplied prefixes,
+// except those in keep.
+func removeWithPrefix(m, keep map[string]string, prefixes ...string) (map[string]string, bool) {
+	changed := false
+	for k := range m {
+		if _, ok := keep[k]; ok {
+			continue
+		}
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(k, prefix) {
+				delete(m, k)
+				changed = true
+				break
+			}
+		}
+	}
+	return m, changed
+}
+
 // WasDeleted returns true if the supplied object was deleted from the API server.
 func WasDeleted(o metav1.Object) bool {
 	return !o.GetDeletionTimestamp().IsZero()
@@ -277,6 +559,129 @@ func SetExternalName(o metav1.Object, name string) {
 	AddAnnotations(o, map[string]string{AnnotationKeyExternalName: name})
 }
 
+// MaxExternalNameHistory is the number of previous external names recorded by
+// ChangeExternalName.
+const MaxExternalNameHistory = 5
+
+// GetExternalNameHistory returns the previous external names of the resource,
+// most recent first. It returns nil if the history annotation is not set or
+// is invalid.
+func GetExternalNameHistory(o metav1.Object) []string {
+	v, ok := o.GetAnnotations()[AnnotationKeyExternalNameHistory]
+	if !ok {
+		return nil
+	}
+	var h []string
+	if err := json.Unmarshal([]byte(v), &h); err != nil {
+		return nil
+	}
+	return h
+}
+
+// ChangeExternalName sets the external name annotation of the resource to the
+// supplied name, recording its previous external name, if any, in its external
+// name history. At most MaxExternalNameHistory previous names are kept. It
+// returns false, and changes nothing, if the supplied name is empty or is
+// already the resource's external name.
+func ChangeExternalName(o metav1.Object, name string) bool {
+	previous := GetExternalName(o)
+	if name == "" || name == previous {
+		return false
+	}
+	SetExternalName(o, name)
+	if previous == "" {
+		return true
+	}
+
+	h := []string{previous}
+	for _, n := range GetExternalNameHistory(o) {
+		if n != previous && n != name && len(h) < MaxExternalNameHistory {
+			h = append(h, n)
+		}
+	}
+	// Marshalling a slice of strings can't fail.
+	b, _ := json.Marshal(h)
+	AddAnnotations(o, map[string]string{AnnotationKeyExternalNameHistory: string(b)})
+	return true
+}
+
+// ExternalNameTemplateData is the data available to an external name
+// template. For example the template "{{ .Labels.team }}-{{ .Name }}" renders
+// the object's team label and name.
+type ExternalNameTemplateData struct {
+	Name        string
+	Namespace   string
+	UID         string
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+// An ExternalNameConstraint returns an error if the supplied external name
+// does not satisfy it. External name constraints typically reflect the naming
+// rules of a provider's API.
+type ExternalNameConstraint func(name string) error
+
+// MaxLength returns an ExternalNameConstraint that requires an external name
+// to be at most n bytes long.
+func MaxLength(n int) ExternalNameConstraint {
+	return func(name string) error {
+		if len(name) > n {
+			return errors.Errorf(errFmtExternalNameTooLong, name, n)
+		}
+		return nil
+	}
+}
+
+// MatchesPattern returns an ExternalNameConstraint that requires an external
+// name to match the supplied regular expression, for example
+// ^[a-z0-9-]+$ to allow only lowercase alphanumeric characters and hyphens.
+func MatchesPattern(re *regexp.Regexp) ExternalNameConstraint {
+	return func(name string) error {
+		if !re.MatchString(name) {
+			return errors.Errorf(errFmtExternalNamePattern, name, re.String())
+		}
+		return nil
+	}
+}
+
+// SetExternalNameFromTemplate renders the supplied Go template with the
+// object's ExternalNameTemplateData and sets the result as the object's
+// external name. It returns an error, and leaves the external name unchanged,
+// if the template is invalid, refers to a label or annotation the object
+// doesn't have, renders an empty name, or renders a name that does not satisfy
+// all of the supplied constraints.
+func SetExternalNameFromTemplate(o metav1.Object, tmpl string, c ...ExternalNameConstraint) error {
+	t, err := template.New("external-name").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return errors.Wrap(err, errParseExternalNameTemplate)
+	}
+
+	d := ExternalNameTemplateData{
+		Name:        o.GetName(),
+		Namespace:   o.GetNamespace(),
+		UID:         string(o.GetUID()),
+		Labels:      o.GetLabels(),
+		Annotations: o.GetAnnotations(),
+	}
+	b := &strings.Builder{}
+	if err := t.Execute(b, d); err != nil {
+		return errors.Wrap(err, errRenderExternalNameTemplate)
+	}
+
+	name := b.String()
+	if name == "" {
+		return errors.New(errEmptyExternalName)
+	}
+	for _, fn := range c {
+		if err := fn(name); err != nil {
+			return err
+		}
+	}
+
+	SetExternalName(o, name)
+	return nil
+}
+
 // GetExternalCreatePending returns the time at which the external resource
 // was most recently pending creation.
 func GetExternalCreatePending(o metav1.Object) time.Time {
@@ -361,6 +766,270 @@ func ExternalCreateSucceededDuring(o metav1.Object, d time.Duration) bool {
 	return time.Since(t) < d
 }
 
+// GetExternalCreateUID returns the UID of the managed resource that created
+// the external resource.
+func GetExternalCreateUID(o metav1.Object) types.UID {
+	return types.UID(o.GetAnnotations()[AnnotationKeyExternalCreateUID])
+}
+
+// GetIdempotencyToken returns the idempotency token used to create the
+// supplied managed resource's external resource.
+func GetIdempotencyToken(o metav1.Object) string {
+	return o.GetAnnotations()[AnnotationKeyIdempotencyToken]
+}
+
+// SetIdempotencyToken sets the idempotency token used to create the supplied
+// managed resource's external resource.
+func SetIdempotencyToken(o metav1.Object, token string) {
+	AddAnnotations(o, map[string]string{AnnotationKeyIdempotencyToken: token})
+}
+
+// SetExternalCreateUID records that the supplied managed resource created, or
+// adopted, its external resource. It does nothing if the managed resource has
+// no UID, i.e. has not yet been created.
+func SetExternalCreateUID(o metav1.Object) {
+	if o.GetUID() == "" {
+		return
+	}
+	AddAnnotations(o, map[string]string{AnnotationKeyExternalCreateUID: string(o.GetUID())})
+}
+
+// GetAdoptConnectionFrom returns the UID of the previous managed resource
+// whose connection secret the supplied managed resource should adopt, if any.
+// A managed resource never adopts from itself.
+func GetAdoptConnectionFrom(o metav1.Object) types.UID {
+	uid := types.UID(o.GetAnnotations()[AnnotationKeyAdoptConnectionFrom])
+	if uid == o.GetUID() {
+		return ""
+	}
+	return uid
+}
+
+// WasRestored returns true if the supplied managed resource appears to have
+// been restored from a backup. We deem a managed resource to have been
+// restored if it records that its external resource was successfully created
+// by a managed resource with a different UID.
+func WasRestored(o metav1.Object) bool {
+	uid := GetExternalCreateUID(o)
+	if uid == "" || uid == o.GetUID() {
+		return false
+	}
+	return GetExternalName(o) != "" && !GetExternalCreateSucceeded(o).IsZero()
+}
+
+// GetExternalUpdatePending returns the time at which an update of the external
+// resource was most recently started.
+func GetExternalUpdatePending(o metav1.Object) time.Time {
+	return getTime(o, AnnotationKeyExternalUpdatePending)
+}
+
+// SetExternalUpdatePending sets the time at which an update of the external
+// resource was most recently started to the supplied time.
+func SetExternalUpdatePending(o metav1.Object, t time.Time) {
+	AddAnnotations(o, map[string]string{AnnotationKeyExternalUpdatePending: t.Format(time.RFC3339)})
+}
+
+// GetExternalUpdateSucceeded returns the time at which the external resource
+// was most recently updated.
+func GetExternalUpdateSucceeded(o metav1.Object) time.Time {
+	return getTime(o, AnnotationKeyExternalUpdateSucceeded)
+}
+
+// SetExternalUpdateSucceeded sets the time at which the external resource was
+// most recently updated to the supplied time.
+func SetExternalUpdateSucceeded(o metav1.Object, t time.Time) {
+	AddAnnotations(o, map[string]string{AnnotationKeyExternalUpdateSucceeded: t.Format(time.RFC3339)})
+}
+
+// GetExternalUpdateFailed returns the time at which the external resource
+// most recently failed to update, and the error that caused it to fail.
+func GetExternalUpdateFailed(o metav1.Object) (time.Time, string) {
+	return getTime(o, AnnotationKeyExternalUpdateFailed), o.GetAnnotations()[AnnotationKeyExternalUpdateError]
+}
+
+// SetExternalUpdateFailed sets the time at which the external resource most
+// recently failed to update to the supplied time, and records the supplied
+// error. Long error messages are truncated.
+func SetExternalUpdateFailed(o metav1.Object, t time.Time, err error) {
+	AddAnnotations(o, map[string]string{
+		AnnotationKeyExternalUpdateFailed: t.Format(time.RFC3339),
+		AnnotationKeyExternalUpdateError:  errorAnnotation(err),
+	})
+}
+
+// GetExternalDeletePending returns the time at which an delete of the external
+// resource was most recently started.
+func GetExternalDeletePending(o metav1.Object) time.Time {
+	return getTime(o, AnnotationKeyExternalDeletePending)
+}
+
+// SetExternalDeletePending sets the time at which an delete of the external
+// resource was most recently started to the supplied time.
+func SetExternalDeletePending(o metav1.Object, t time.Time) {
+	AddAnnotations(o, map[string]string{AnnotationKeyExternalDeletePending: t.Format(time.RFC3339)})
+}
+
+// GetExternalDeleteSucceeded returns the time at which the external resource
+// was most recently deleted.
+func GetExternalDeleteSucceeded(o metav1.Object) time.Time {
+	return getTime(o, AnnotationKeyExternalDeleteSucceeded)
+}
+
+// SetExternalDeleteSucceeded sets the time at which the external resource was
+// most recently deleted to the supplied time.
+func SetExternalDeleteSucceeded(o metav1.Object, t time.Time) {
+	AddAnnotations(o, map[string]string{AnnotationKeyExternalDeleteSucceeded: t.Format(time.RFC3339)})
+}
+
+// GetExternalDeleteFailed returns the time at which the external resource
+// most recently failed to delete, and the error that caused it to fail.
+func GetExternalDeleteFailed(o metav1.Object) (time.Time, string) {
+	return getTime(o, AnnotationKeyExternalDeleteFailed), o.GetAnnotations()[AnnotationKeyExternalDeleteError]
+}
+
+// SetExternalDeleteFailed sets the time at which the external resource most
+// recently failed to delete to the supplied time, and records the supplied
+// error. Long error messages are truncated.
+func SetExternalDeleteFailed(o metav1.Object, t time.Time, err error) {
+	AddAnnotations(o, map[string]string{
+		AnnotationKeyExternalDeleteFailed: t.Format(time.RFC3339),
+		AnnotationKeyExternalDeleteError:  errorAnnotation(err),
+	})
+}
+
+// getTime returns the RFC3339 timestamp stored in the supplied annotation, or
+// the zero time if the annotation is not set or is not a valid timestamp.
+func getTime(o metav1.Object, key string) time.Time {
+	t, err := time.Parse(time.RFC3339, o.GetAnnotations()[key])
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// maxErrorAnnotationLength is the maximum length of an error recorded in an
+// annotation, in bytes.
+const maxErrorAnnotationLength = 1024
+
+// errorAnnotation returns the message of the supplied error, truncated to
+// maxErrorAnnotationLength.
+func errorAnnotation(err error) string {
+	if err == nil {
+		return ""
+	}
+	return errors.Truncate(err.Error(), maxErrorAnnotationLength)
+}
+
+// Labels and annotations with these prefixes are never propagated by
+// PropagateLabels or PropagateAnnotations. They hold state that is specific to
+// the object they're set on, like its external name.
+var reservedPropagationPrefixes = []string{
+	"crossplane.io/",
+	"kubectl.kubernetes.io/",
+}
+
+// A PropagationPolicy determines which labels or annotations are propagated
+// from one object to another. The zero value propagates nothing.
+type PropagationPolicy struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+// NewPropagationPolicy returns a PropagationPolicy that propagates keys that
+// match any of the supplied include patterns, unless they also match any of
+// the supplied exclude patterns. Patterns may contain the wildcard '*', which
+// matches any sequence of characters - including '/'. For example the pattern
+// "example.org/*" matches the key "example.org/team".
+func NewPropagationPolicy(include, exclude []string) (PropagationPolicy, error) {
+	in, err := compilePatterns(include)
+	if err != nil {
+		return PropagationPolicy{}, err
+	}
+	ex, err := compilePatterns(exclude)
+	if err != nil {
+		return PropagationPolicy{}, err
+	}
+	return PropagationPolicy{include: in, exclude: ex}, nil
+}
+
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, len(patterns))
+	for i, pattern := range patterns {
+		re, err := regexp.Compile("^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$")
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtCompilePropagationPattern, pattern)
+		}
+		res[i] = re
+	}
+	return res, nil
+}
+
+// Propagates returns true if the supplied key should be propagated.
+func (p PropagationPolicy) Propagates(key string) bool {
+	for _, prefix := range reservedPropagationPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return false
+		}
+	}
+	return matchesAny(key, p.include) && !matchesAny(key, p.exclude)
+}
+
+func matchesAny(key string, res []*regexp.Regexp) bool {
+	for _, re := range res {
+		if re.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// PropagateLabels keeps the labels of the 'to' object that the supplied policy
+// propagates in sync with those of the 'from' object. Propagated labels are
+// added to or updated on the 'to' object, and any labels the policy propagates
+// that the 'from' object doesn't have are removed from the 'to' object. It
+// returns true if the labels of the 'to' object changed.
+func PropagateLabels(from, to metav1.Object, p PropagationPolicy) bool {
+	l, changed := propagate(from.GetLabels(), to.GetLabels(), p)
+	to.SetLabels(l)
+	return changed
+}
+
+// PropagateAnnotations keeps the annotations of the 'to' object that the
+// supplied policy propagates in sync with those of the 'from' object.
+// Propagated annotations are added to or updated on the 'to' object, and any
+// annotations the policy propagates that the 'from' object doesn't have are
+// removed from the 'to' object. It returns true if the annotations of the 'to'
+// object changed.
+func PropagateAnnotations(from, to metav1.Object, p PropagationPolicy) bool {
+	a, changed := propagate(from.GetAnnotations(), to.GetAnnotations(), p)
+	to.SetAnnotations(a)
+	return changed
+}
+
+func propagate(from, to map[string]string, p PropagationPolicy) (map[string]string, bool) {
+	changed := false
+	for k := range to {
+		if _, ok := from[k]; !ok && p.Propagates(k) {
+			delete(to, k)
+			changed = true
+		}
+	}
+	for k, v := range from {
+		if !p.Propagates(k) {
+			continue
+		}
+		if cur, ok := to[k]; ok && cur == v {
+			continue
+		}
+		if to == nil {
+			to = make(map[string]string)
+		}
+		to[k] = v
+		changed = true
+	}
+	return to, changed
+}
+
 // AllowPropagation from one object to another by adding consenting annotations
 // to both.
 // Deprecated: This functionality will be removed soon.
@@ -421,6 +1090,28 @@ func AllowsPropagationTo(from metav1.Object) map[types.NamespacedName]bool {
 	return to
 }
 
+// GetDriftIgnoredPaths returns the field paths whose drift from their desired
+// state should be ignored, per the AnnotationKeyDriftIgnore annotation.
+func GetDriftIgnoredPaths(o metav1.Object) []string {
+	v := o.GetAnnotations()[AnnotationKeyDriftIgnore]
+	if v == "" {
+		return nil
+	}
+	paths := make([]string, 0, strings.Count(v, ",")+1)
+	for _, p := range strings.Split(v, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// IsDryRun returns true if the object has the AnnotationKeyDryRun annotation
+// set to `true`.
+func IsDryRun(o metav1.Object) bool {
+	return o.GetAnnotations()[AnnotationKeyDryRun] == "true"
+}
+
 // IsPaused returns true if the object has the AnnotationKeyReconciliationPaused
 // annotation set to `true`.
 func IsPaused(o metav1.Object) bool {
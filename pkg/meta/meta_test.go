@@ -19,6 +19,8 @@ package meta
 import (
 	"fmt"
 	"hash/fnv"
+	"regexp"
+	"strings"
 	"testing"
 	"time"
 
@@ -115,6 +117,7 @@ func TestTypedReferenceTo(t *testing.T) {
 				APIVersion: groupVersion,
 				Kind:       kind,
 				Name:       name,
+				Namespace:  namespace,
 				UID:        uid,
 			},
 		},
@@ -655,6 +658,217 @@ func TestAddLabels(t *testing.T) {
 	}
 }
 
+func TestMergeLabels(t *testing.T) {
+	type args struct {
+		o      metav1.Object
+		labels map[string]string
+	}
+	type want struct {
+		labels  map[string]string
+		changed bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoExistingLabels": {
+			reason: "Adding labels to an object without labels should change it.",
+			args: args{
+				o:      &corev1.Pod{},
+				labels: map[string]string{"cool": "very"},
+			},
+			want: want{labels: map[string]string{"cool": "very"}, changed: true},
+		},
+		"Updated": {
+			reason: "Changing the value of an existing label should change the object.",
+			args: args{
+				o:      &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"cool": "very", "other": "label"}}},
+				labels: map[string]string{"cool": "extremely"},
+			},
+			want: want{labels: map[string]string{"cool": "extremely", "other": "label"}, changed: true},
+		},
+		"Unchanged": {
+			reason: "Adding labels the object already has should not change it.",
+			args: args{
+				o:      &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"cool": "very", "other": "label"}}},
+				labels: map[string]string{"cool": "very"},
+			},
+			want: want{labels: map[string]string{"cool": "very", "other": "label"}, changed: false},
+		},
+		"Nothing": {
+			reason: "Adding no labels should not change the object.",
+			args: args{
+				o: &corev1.Pod{},
+			},
+			want: want{changed: false},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			changed := MergeLabels(tc.args.o, tc.args.labels)
+			got := want{labels: tc.args.o.GetLabels(), changed: changed}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nMergeLabels(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRemoveLabelsWithPrefix(t *testing.T) {
+	type args struct {
+		o        metav1.Object
+		prefixes []string
+	}
+	type want struct {
+		labels  map[string]string
+		changed bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Removed": {
+			reason: "Labels with any of the prefixes should be removed.",
+			args: args{
+				o: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+					"example.org/team": "cool",
+					"example.net/env":  "prod",
+					"other":            "label",
+				}}},
+				prefixes: []string{"example.org/", "example.net/"},
+			},
+			want: want{labels: map[string]string{"other": "label"}, changed: true},
+		},
+		"Unchanged": {
+			reason: "The object should not change if it has no labels with the prefixes.",
+			args: args{
+				o:        &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"other": "label"}}},
+				prefixes: []string{"example.org/"},
+			},
+			want: want{labels: map[string]string{"other": "label"}, changed: false},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			changed := RemoveLabelsWithPrefix(tc.args.o, tc.args.prefixes...)
+			got := want{labels: tc.args.o.GetLabels(), changed: changed}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nRemoveLabelsWithPrefix(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestReplaceAnnotationsWithPrefix(t *testing.T) {
+	type args struct {
+		o           metav1.Object
+		prefix      string
+		annotations map[string]string
+	}
+	type want struct {
+		annotations map[string]string
+		changed     bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Replaced": {
+			reason: "Annotations with the prefix that weren't supplied should be removed, and supplied annotations added.",
+			args: args{
+				o: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+					"example.org/stale": "yes",
+					"example.org/team":  "cool",
+					"other":             "annotation",
+				}}},
+				prefix:      "example.org/",
+				annotations: map[string]string{"example.org/team": "cool", "example.org/env": "prod"},
+			},
+			want: want{
+				annotations: map[string]string{"example.org/team": "cool", "example.org/env": "prod", "other": "annotation"},
+				changed:     true,
+			},
+		},
+		"Unchanged": {
+			reason: "The object should not change if its annotations with the prefix are already the supplied annotations.",
+			args: args{
+				o: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+					"example.org/team": "cool",
+					"other":            "annotation",
+				}}},
+				prefix:      "example.org/",
+				annotations: map[string]string{"example.org/team": "cool"},
+			},
+			want: want{
+				annotations: map[string]string{"example.org/team": "cool", "other": "annotation"},
+				changed:     false,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			changed := ReplaceAnnotationsWithPrefix(tc.args.o, tc.args.prefix, tc.args.annotations)
+			got := want{annotations: tc.args.o.GetAnnotations(), changed: changed}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nReplaceAnnotationsWithPrefix(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRemoveClaimLabels(t *testing.T) {
+	type want struct {
+		labels  map[string]string
+		changed bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		o      metav1.Object
+		want   want
+	}{
+		"Claimed": {
+			reason: "Removing the claim labels of an object with claim labels should change it.",
+			o: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+				LabelKeyClaimName:      "cool",
+				LabelKeyClaimNamespace: "default",
+				LabelKeyClaimUID:       "uid",
+				LabelKeyComposite:      "xcool",
+			}}},
+			want: want{labels: map[string]string{LabelKeyComposite: "xcool"}, changed: true},
+		},
+		"Unclaimed": {
+			reason: "Removing the claim labels of an object without claim labels should not change it.",
+			o:      &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{LabelKeyComposite: "xcool"}}},
+			want:   want{labels: map[string]string{LabelKeyComposite: "xcool"}, changed: false},
+		},
+		"NoLabels": {
+			reason: "Removing the claim labels of an object without labels should not change it.",
+			o:      &corev1.Pod{},
+			want:   want{changed: false},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			changed := RemoveClaimLabels(tc.o)
+			got := want{labels: tc.o.GetLabels(), changed: changed}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nRemoveClaimLabels(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestRemoveLabels(t *testing.T) {
 	keyA, valueA := "keyA", "valueA"
 	keyB, valueB := "keyB", "valueB"
@@ -903,6 +1117,372 @@ func TestSetExternalName(t *testing.T) {
 	}
 }
 
+func TestChangeExternalName(t *testing.T) {
+	annotated := func(a map[string]string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: a}}
+	}
+
+	type args struct {
+		o    metav1.Object
+		name string
+	}
+	type want struct {
+		o       metav1.Object
+		changed bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Empty": {
+			reason: "An empty external name should not be set.",
+			args: args{
+				o: annotated(map[string]string{AnnotationKeyExternalName: "old"}),
+			},
+			want: want{
+				o: annotated(map[string]string{AnnotationKeyExternalName: "old"}),
+			},
+		},
+		"Unchanged": {
+			reason: "Setting the current external name should not change anything.",
+			args: args{
+				o:    annotated(map[string]string{AnnotationKeyExternalName: "old"}),
+				name: "old",
+			},
+			want: want{
+				o: annotated(map[string]string{AnnotationKeyExternalName: "old"}),
+			},
+		},
+		"NoPrevious": {
+			reason: "Setting the first external name should not record any history.",
+			args: args{
+				o:    annotated(nil),
+				name: "new",
+			},
+			want: want{
+				o:       annotated(map[string]string{AnnotationKeyExternalName: "new"}),
+				changed: true,
+			},
+		},
+		"Changed": {
+			reason: "Changing the external name should record the previous one, most recent first, without duplicates.",
+			args: args{
+				o: annotated(map[string]string{
+					AnnotationKeyExternalName:        "old",
+					AnnotationKeyExternalNameHistory: `["new","older"]`,
+				}),
+				name: "new",
+			},
+			want: want{
+				o: annotated(map[string]string{
+					AnnotationKeyExternalName:        "new",
+					AnnotationKeyExternalNameHistory: `["old","older"]`,
+				}),
+				changed: true,
+			},
+		},
+		"Truncated": {
+			reason: "Only MaxExternalNameHistory previous names should be kept.",
+			args: args{
+				o: annotated(map[string]string{
+					AnnotationKeyExternalName:        "old",
+					AnnotationKeyExternalNameHistory: `["a","b","c","d","e"]`,
+				}),
+				name: "new",
+			},
+			want: want{
+				o: annotated(map[string]string{
+					AnnotationKeyExternalName:        "new",
+					AnnotationKeyExternalNameHistory: `["old","a","b","c","d"]`,
+				}),
+				changed: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			changed := ChangeExternalName(tc.args.o, tc.args.name)
+			if diff := cmp.Diff(tc.want.changed, changed); diff != "" {
+				t.Errorf("\n%s\nChangeExternalName(...): -want changed, +got changed:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, tc.args.o); diff != "" {
+				t.Errorf("\n%s\nChangeExternalName(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSetExternalNameFromTemplate(t *testing.T) {
+	type args struct {
+		o    metav1.Object
+		tmpl string
+		c    []ExternalNameConstraint
+	}
+	type want struct {
+		o   metav1.Object
+		err error
+	}
+
+	objMeta := func() metav1.ObjectMeta {
+		return metav1.ObjectMeta{
+			Name:      "cool",
+			Namespace: "default",
+			Labels:    map[string]string{"team": "platform"},
+		}
+	}
+	withExternalName := func(n string) metav1.ObjectMeta {
+		om := objMeta()
+		om.Annotations = map[string]string{AnnotationKeyExternalName: n}
+		return om
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Rendered": {
+			reason: "The external name should be rendered from the object's metadata.",
+			args: args{
+				o:    &corev1.Pod{ObjectMeta: objMeta()},
+				tmpl: "{{ .Labels.team }}-{{ .Namespace }}-{{ .Name }}",
+				c:    []ExternalNameConstraint{MaxLength(30), MatchesPattern(regexp.MustCompile("^[a-z-]+$"))},
+			},
+			want: want{
+				o: &corev1.Pod{ObjectMeta: withExternalName("platform-default-cool")},
+			},
+		},
+		"InvalidTemplate": {
+			reason: "An error should be returned if the template can't be parsed.",
+			args: args{
+				o:    &corev1.Pod{ObjectMeta: objMeta()},
+				tmpl: "{{ .Name ",
+			},
+			want: want{
+				o:   &corev1.Pod{ObjectMeta: objMeta()},
+				err: errors.Wrap(errors.New("template: external-name:1: unclosed action"), errParseExternalNameTemplate),
+			},
+		},
+		"MissingLabel": {
+			reason: "An error should be returned if the template refers to a label the object doesn't have.",
+			args: args{
+				o:    &corev1.Pod{ObjectMeta: objMeta()},
+				tmpl: "{{ .Labels.owner }}-{{ .Name }}",
+			},
+			want: want{
+				o:   &corev1.Pod{ObjectMeta: objMeta()},
+				err: errors.Wrap(errors.New(`template: external-name:1:10: executing "external-name" at <.Labels.owner>: map has no entry for key "owner"`), errRenderExternalNameTemplate),
+			},
+		},
+		"Empty": {
+			reason: "An error should be returned if the template renders an empty name.",
+			args: args{
+				o:    &corev1.Pod{ObjectMeta: objMeta()},
+				tmpl: "{{ .UID }}",
+			},
+			want: want{
+				o:   &corev1.Pod{ObjectMeta: objMeta()},
+				err: errors.New(errEmptyExternalName),
+			},
+		},
+		"TooLong": {
+			reason: "An error should be returned if the rendered name is too long.",
+			args: args{
+				o:    &corev1.Pod{ObjectMeta: objMeta()},
+				tmpl: "{{ .Labels.team }}-{{ .Name }}",
+				c:    []ExternalNameConstraint{MaxLength(8)},
+			},
+			want: want{
+				o:   &corev1.Pod{ObjectMeta: objMeta()},
+				err: errors.Errorf(errFmtExternalNameTooLong, "platform-cool", 8),
+			},
+		},
+		"InvalidCharacters": {
+			reason: "An error should be returned if the rendered name contains invalid characters.",
+			args: args{
+				o:    &corev1.Pod{ObjectMeta: objMeta()},
+				tmpl: "{{ .Labels.team }}_{{ .Name }}",
+				c:    []ExternalNameConstraint{MatchesPattern(regexp.MustCompile("^[a-z-]+$"))},
+			},
+			want: want{
+				o:   &corev1.Pod{ObjectMeta: objMeta()},
+				err: errors.Errorf(errFmtExternalNamePattern, "platform_cool", "^[a-z-]+$"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := SetExternalNameFromTemplate(tc.args.o, tc.args.tmpl, tc.args.c...)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nSetExternalNameFromTemplate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, tc.args.o); diff != "" {
+				t.Errorf("\n%s\nSetExternalNameFromTemplate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestExternalUpdateFailed(t *testing.T) {
+	now := time.Now().Round(time.Second)
+	long := strings.Repeat("a", maxErrorAnnotationLength+1)
+
+	type want struct {
+		t   time.Time
+		msg string
+	}
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   want
+	}{
+		"ErrorRecorded": {
+			reason: "The time and error of a failed update should be recorded.",
+			err:    errors.New("boom"),
+			want:   want{t: now, msg: "boom"},
+		},
+		"LongErrorTruncated": {
+			reason: "Long errors should be truncated.",
+			err:    errors.New(long),
+			want:   want{t: now, msg: long[:maxErrorAnnotationLength-3] + "..."},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o := &corev1.Pod{}
+			SetExternalUpdateFailed(o, now, tc.err)
+			gotT, gotMsg := GetExternalUpdateFailed(o)
+			if diff := cmp.Diff(tc.want, want{t: gotT, msg: gotMsg}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nGetExternalUpdateFailed(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestGetExternalDeleteSucceeded(t *testing.T) {
+	now := time.Now().Round(time.Second)
+
+	cases := map[string]struct {
+		o    metav1.Object
+		want time.Time
+	}{
+		"ExternalDeleteSucceededExists": {
+			o:    &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{AnnotationKeyExternalDeleteSucceeded: now.Format(time.RFC3339)}}},
+			want: now,
+		},
+		"NoExternalDeleteSucceeded": {
+			o:    &corev1.Pod{},
+			want: time.Time{},
+		},
+		"InvalidExternalDeleteSucceeded": {
+			o:    &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{AnnotationKeyExternalDeleteSucceeded: "yesterday"}}},
+			want: time.Time{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := GetExternalDeleteSucceeded(tc.o)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("GetExternalDeleteSucceeded(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPropagateLabels(t *testing.T) {
+	policy := func(include, exclude []string) PropagationPolicy {
+		p, err := NewPropagationPolicy(include, exclude)
+		if err != nil {
+			t.Fatalf("NewPropagationPolicy(...): %s", err)
+		}
+		return p
+	}
+
+	type args struct {
+		from metav1.Object
+		to   metav1.Object
+		p    PropagationPolicy
+	}
+	type want struct {
+		to      metav1.Object
+		changed bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NothingToPropagate": {
+			reason: "Objects should be unchanged if the policy propagates nothing.",
+			args: args{
+				from: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "platform"}}},
+				to:   &corev1.Pod{},
+				p:    PropagationPolicy{},
+			},
+			want: want{
+				to: &corev1.Pod{},
+			},
+		},
+		"Propagated": {
+			reason: "Included labels should be added, updated, or removed. Excluded and reserved labels should be left alone.",
+			args: args{
+				from: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+					"example.org/team":        "platform",
+					"example.org/cost-center": "42",
+					"example.org/secret":      "shh",
+					"crossplane.io/claim":     "cool",
+					"unrelated":               "yes",
+				}}},
+				to: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+					"example.org/team":  "old",
+					"example.org/owner": "gone",
+					"crossplane.io/foo": "bar",
+					"own":               "label",
+				}}},
+				p: policy([]string{"*"}, []string{"example.org/secret", "unrelated"}),
+			},
+			want: want{
+				to: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+					"example.org/team":        "platform",
+					"example.org/cost-center": "42",
+					"crossplane.io/foo":       "bar",
+				}}},
+				changed: true,
+			},
+		},
+		"InSync": {
+			reason: "No change should be reported if the labels are already in sync.",
+			args: args{
+				from: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"example.org/team": "platform"}}},
+				to:   &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"example.org/team": "platform", "own": "label"}}},
+				p:    policy([]string{"example.org/*"}, nil),
+			},
+			want: want{
+				to: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"example.org/team": "platform", "own": "label"}}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			changed := PropagateLabels(tc.args.from, tc.args.to, tc.args.p)
+			if diff := cmp.Diff(tc.want.changed, changed); diff != "" {
+				t.Errorf("\n%s\nPropagateLabels(...): -want changed, +got changed:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.to, tc.args.to); diff != "" {
+				t.Errorf("\n%s\nPropagateLabels(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestGetExternalCreatePending(t *testing.T) {
 	now := time.Now().Round(time.Second)
 
@@ -1112,6 +1692,69 @@ func TestExternalCreateSucceededDuring(t *testing.T) {
 	}
 }
 
+func TestWasRestored(t *testing.T) {
+	succeeded := time.Now().Format(time.RFC3339)
+
+	cases := map[string]struct {
+		reason string
+		o      metav1.Object
+		want   bool
+	}{
+		"NoCreateUID": {
+			reason: "A managed resource that doesn't record which UID created its external resource was not restored.",
+			o: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				UID:         "new-uid",
+				Annotations: map[string]string{AnnotationKeyExternalName: "cool", AnnotationKeyExternalCreateSucceeded: succeeded},
+			}},
+			want: false,
+		},
+		"SameUID": {
+			reason: "A managed resource that created its own external resource was not restored.",
+			o: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				UID: "new-uid",
+				Annotations: map[string]string{
+					AnnotationKeyExternalName:            "cool",
+					AnnotationKeyExternalCreateSucceeded: succeeded,
+					AnnotationKeyExternalCreateUID:       "new-uid",
+				},
+			}},
+			want: false,
+		},
+		"NotCreated": {
+			reason: "A managed resource that never successfully created its external resource was not restored.",
+			o: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				UID: "new-uid",
+				Annotations: map[string]string{
+					AnnotationKeyExternalName:      "cool",
+					AnnotationKeyExternalCreateUID: "old-uid",
+				},
+			}},
+			want: false,
+		},
+		"Restored": {
+			reason: "A managed resource whose external resource was created by a different UID was restored.",
+			o: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				UID: "new-uid",
+				Annotations: map[string]string{
+					AnnotationKeyExternalName:            "cool",
+					AnnotationKeyExternalCreateSucceeded: succeeded,
+					AnnotationKeyExternalCreateUID:       "old-uid",
+				},
+			}},
+			want: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := WasRestored(tc.o)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nWasRestored(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestExternalCreateIncomplete(t *testing.T) {
 
 	now := time.Now().Format(time.RFC3339)
@@ -1322,6 +1965,34 @@ func TestAllowsPropagationTo(t *testing.T) {
 	}
 }
 
+func TestGetDriftIgnoredPaths(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		o      metav1.Object
+		want   []string
+	}{
+		"NoAnnotation": {
+			reason: "No paths should be returned if the annotation is not set.",
+			o:      &corev1.Pod{},
+		},
+		"Paths": {
+			reason: "Comma separated paths should be returned without surrounding whitespace or empty entries.",
+			o: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+				AnnotationKeyDriftIgnore: "spec.forProvider.tags, spec.forProvider.desiredCount,",
+			}}},
+			want: []string{"spec.forProvider.tags", "spec.forProvider.desiredCount"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := GetDriftIgnoredPaths(tc.o)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nGetDriftIgnoredPaths(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestIsPaused(t *testing.T) {
 	cases := map[string]struct {
 		o    metav1.Object
@@ -1361,3 +2032,42 @@ func TestIsPaused(t *testing.T) {
 		})
 	}
 }
+
+func TestGetAdoptConnectionFrom(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		o      metav1.Object
+		want   types.UID
+	}{
+		"NotAnnotated": {
+			reason: "A managed resource that isn't annotated should not adopt connection details.",
+			o:      &corev1.Pod{ObjectMeta: metav1.ObjectMeta{UID: "new-uid"}},
+			want:   "",
+		},
+		"Self": {
+			reason: "A managed resource should not adopt connection details from itself.",
+			o: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				UID:         "new-uid",
+				Annotations: map[string]string{AnnotationKeyAdoptConnectionFrom: "new-uid"},
+			}},
+			want: "",
+		},
+		"Previous": {
+			reason: "A managed resource should adopt connection details from the annotated UID.",
+			o: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				UID:         "new-uid",
+				Annotations: map[string]string{AnnotationKeyAdoptConnectionFrom: "old-uid"},
+			}},
+			want: "old-uid",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := GetAdoptConnectionFrom(tc.o)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nGetAdoptConnectionFrom(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+)
+
+type callCounterKey struct{}
+
+type callCounter struct {
+	n int64
+}
+
+// WithCallCounter returns a copy of the supplied context that carries a
+// counter of external API calls. Calls counted using the returned context, or
+// any context derived from it, are added to the counter.
+func WithCallCounter(ctx context.Context) context.Context {
+	return context.WithValue(ctx, callCounterKey{}, &callCounter{})
+}
+
+// CountCall counts a call to an external API against the counter carried by
+// the supplied context, if any. Providers should count each request they make
+// to an external API using the context passed to their ExternalClient, unless
+// their requests are made by an http.Client using a CountingTransport.
+func CountCall(ctx context.Context) {
+	if c, ok := ctx.Value(callCounterKey{}).(*callCounter); ok {
+		atomic.AddInt64(&c.n, 1)
+	}
+}
+
+// CallsFrom returns the number of external API calls counted by the counter
+// carried by the supplied context, or zero if it carries no counter.
+func CallsFrom(ctx context.Context) int {
+	if c, ok := ctx.Value(callCounterKey{}).(*callCounter); ok {
+		return int(atomic.LoadInt64(&c.n))
+	}
+	return 0
+}
+
+// A CountingTransport is an http.RoundTripper that counts each request it
+// sends against the call counter carried by the request's context.
+type CountingTransport struct {
+	// Base is the RoundTripper used to send requests. The
+	// http.DefaultTransport is used if it is nil.
+	Base http.RoundTripper
+}
+
+// RoundTrip counts and sends the supplied request.
+func (t *CountingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	CountCall(req.Context())
+	if t.Base == nil {
+		return http.DefaultTransport.RoundTrip(req)
+	}
+	return t.Base.RoundTrip(req)
+}
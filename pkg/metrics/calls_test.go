@@ -0,0 +1,97 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCallsFrom(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		count  func() context.Context
+		want   int
+	}{
+		"NoCounter": {
+			reason: "Calls counted using a context without a counter should be ignored.",
+			count: func() context.Context {
+				ctx := context.Background()
+				CountCall(ctx)
+				return ctx
+			},
+			want: 0,
+		},
+		"Counted": {
+			reason: "Calls counted using a context with a counter should be counted.",
+			count: func() context.Context {
+				ctx := WithCallCounter(context.Background())
+				CountCall(ctx)
+				CountCall(ctx)
+				return ctx
+			},
+			want: 2,
+		},
+		"DerivedContext": {
+			reason: "Calls counted using a context derived from one with a counter should be counted.",
+			count: func() context.Context {
+				ctx := WithCallCounter(context.Background())
+				dctx, cancel := context.WithCancel(ctx)
+				defer cancel()
+				CountCall(dctx)
+				return ctx
+			},
+			want: 1,
+		},
+		"CountingTransport": {
+			reason: "Requests sent by a CountingTransport should be counted.",
+			count: func() context.Context {
+				srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {}))
+				defer srv.Close()
+
+				ctx := WithCallCounter(context.Background())
+				c := &http.Client{Transport: &CountingTransport{Base: srv.Client().Transport}}
+				for i := 0; i < 3; i++ {
+					req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+					if err != nil {
+						t.Fatalf("http.NewRequestWithContext(...): %s", err)
+					}
+					rsp, err := c.Do(req)
+					if err != nil {
+						t.Fatalf("c.Do(...): %s", err)
+					}
+					_ = rsp.Body.Close()
+				}
+				return ctx
+			},
+			want: 3,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := CallsFrom(tc.count())
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nCallsFrom(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
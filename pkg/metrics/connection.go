@@ -0,0 +1,105 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Labels applied to connection details metrics.
+const (
+	// LabelNamespace is the namespace of the connection secret owner, if
+	// any.
+	LabelNamespace = "namespace"
+
+	// LabelName is the name of the connection secret owner.
+	LabelName = "name"
+)
+
+type ownerKey struct {
+	gvk       string
+	namespace string
+	name      string
+}
+
+// ConnectionDetailsAge exposes the time since the connection details of each
+// connection secret owner, e.g. a managed resource, last changed. Alert on it
+// to find credentials that have not been rotated as often as policy requires.
+// Register it with the controller-runtime metrics registry, i.e.
+// metrics.Registry.MustRegister(a), and pass it to the connection details
+// manager.
+type ConnectionDetailsAge struct {
+	desc *prometheus.Desc
+
+	mu      sync.RWMutex
+	changed map[ownerKey]time.Time
+
+	now func() time.Time
+}
+
+// NewConnectionDetailsAge returns a new ConnectionDetailsAge.
+func NewConnectionDetailsAge(o ...ManagedMetricsOption) *ConnectionDetailsAge {
+	cl := prometheus.Labels{}
+	for _, fn := range o {
+		fn(&cl)
+	}
+	return &ConnectionDetailsAge{
+		desc: prometheus.NewDesc(
+			"crossplane_managed_resource_connection_details_age_seconds",
+			"The time since the connection details of a managed resource last changed.",
+			[]string{LabelGVK, LabelNamespace, LabelName}, cl,
+		),
+		changed: map[ownerKey]time.Time{},
+		now:     time.Now,
+	}
+}
+
+// RecordChanged records the time at which the connection details of the
+// supplied owner last changed.
+func (a *ConnectionDetailsAge) RecordChanged(gvk schema.GroupVersionKind, o metav1.Object, changed time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.changed[ownerKey{gvk: gvk.String(), namespace: o.GetNamespace(), name: o.GetName()}] = changed
+}
+
+// Forget stops exposing the age of the connection details of the supplied
+// owner, e.g. because they were deleted.
+func (a *ConnectionDetailsAge) Forget(gvk schema.GroupVersionKind, o metav1.Object) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.changed, ownerKey{gvk: gvk.String(), namespace: o.GetNamespace(), name: o.GetName()})
+}
+
+// Describe sends the descriptor of the connection details age metric.
+func (a *ConnectionDetailsAge) Describe(ch chan<- *prometheus.Desc) {
+	ch <- a.desc
+}
+
+// Collect sends the current age of the connection details of each owner.
+func (a *ConnectionDetailsAge) Collect(ch chan<- prometheus.Metric) {
+	now := a.now()
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	for k, t := range a.changed {
+		ch <- prometheus.MustNewConstMetric(a.desc, prometheus.GaugeValue, now.Sub(t).Seconds(), k.gvk, k.namespace, k.name)
+	}
+}
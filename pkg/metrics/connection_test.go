@@ -0,0 +1,91 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+)
+
+func TestConnectionDetailsAge(t *testing.T) {
+	now := time.Now()
+	gvk := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Cool"}
+	cool := &fake.Managed{ObjectMeta: metav1.ObjectMeta{Name: "cool"}}
+	lame := &fake.Managed{ObjectMeta: metav1.ObjectMeta{Name: "lame"}}
+
+	cases := map[string]struct {
+		reason string
+		record func(a *ConnectionDetailsAge)
+		want   string
+	}{
+		"Changed": {
+			reason: "The time since connection details last changed should be exposed.",
+			record: func(a *ConnectionDetailsAge) {
+				a.RecordChanged(gvk, cool, now.Add(-1*time.Hour))
+			},
+			want: `
+				# HELP crossplane_managed_resource_connection_details_age_seconds The time since the connection details of a managed resource last changed.
+				# TYPE crossplane_managed_resource_connection_details_age_seconds gauge
+				crossplane_managed_resource_connection_details_age_seconds{gvk="example.org/v1, Kind=Cool",name="cool",namespace=""} 3600
+			`,
+		},
+		"ChangedAgain": {
+			reason: "Only the time connection details most recently changed should be exposed.",
+			record: func(a *ConnectionDetailsAge) {
+				a.RecordChanged(gvk, cool, now.Add(-1*time.Hour))
+				a.RecordChanged(gvk, cool, now.Add(-1*time.Minute))
+			},
+			want: `
+				# HELP crossplane_managed_resource_connection_details_age_seconds The time since the connection details of a managed resource last changed.
+				# TYPE crossplane_managed_resource_connection_details_age_seconds gauge
+				crossplane_managed_resource_connection_details_age_seconds{gvk="example.org/v1, Kind=Cool",name="cool",namespace=""} 60
+			`,
+		},
+		"Forgotten": {
+			reason: "The age of forgotten connection details should not be exposed.",
+			record: func(a *ConnectionDetailsAge) {
+				a.RecordChanged(gvk, cool, now.Add(-1*time.Hour))
+				a.RecordChanged(gvk, lame, now.Add(-1*time.Hour))
+				a.Forget(gvk, lame)
+			},
+			want: `
+				# HELP crossplane_managed_resource_connection_details_age_seconds The time since the connection details of a managed resource last changed.
+				# TYPE crossplane_managed_resource_connection_details_age_seconds gauge
+				crossplane_managed_resource_connection_details_age_seconds{gvk="example.org/v1, Kind=Cool",name="cool",namespace=""} 3600
+			`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			a := NewConnectionDetailsAge()
+			a.now = func() time.Time { return now }
+			tc.record(a)
+
+			if err := testutil.CollectAndCompare(a, strings.NewReader(tc.want)); err != nil {
+				t.Errorf("\n%s\nCollect(...): %s", tc.reason, err)
+			}
+		})
+	}
+}
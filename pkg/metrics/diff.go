@@ -0,0 +1,77 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// DiffMetrics is a resource.DiffRecorder that counts the changes applicators
+// make to Kubernetes objects. Register it with the controller-runtime metrics
+// registry, i.e. metrics.Registry.MustRegister(m).
+type DiffMetrics struct {
+	typer   runtime.ObjectTyper
+	applies *prometheus.CounterVec
+	fields  *prometheus.CounterVec
+}
+
+// NewDiffMetrics returns a new DiffMetrics. The supplied typer is used to
+// determine the kind of objects that don't specify one.
+func NewDiffMetrics(t runtime.ObjectTyper) *DiffMetrics {
+	return &DiffMetrics{
+		typer: t,
+		applies: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "crossplane_applicator_changes_total",
+			Help: "The number of times an applicator changed a Kubernetes object.",
+		}, []string{LabelGVK}),
+		fields: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "crossplane_applicator_changed_fields_total",
+			Help: "The number of fields an applicator changed in Kubernetes objects.",
+		}, []string{LabelGVK}),
+	}
+}
+
+// RecordDiff records the supplied changes.
+func (m *DiffMetrics) RecordDiff(_ context.Context, o client.Object, d resource.Diff) {
+	gvk := o.GetObjectKind().GroupVersionKind()
+	if gvk.Empty() {
+		if kinds, _, err := m.typer.ObjectKinds(o); err == nil && len(kinds) > 0 {
+			gvk = kinds[0]
+		}
+	}
+	l := prometheus.Labels{LabelGVK: gvk.String()}
+	m.applies.With(l).Inc()
+	m.fields.With(l).Add(float64(len(d)))
+}
+
+// Describe sends the descriptors of all diff metrics.
+func (m *DiffMetrics) Describe(ch chan<- *prometheus.Desc) {
+	m.applies.Describe(ch)
+	m.fields.Describe(ch)
+}
+
+// Collect sends the current values of all diff metrics.
+func (m *DiffMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.applies.Collect(ch)
+	m.fields.Collect(ch)
+}
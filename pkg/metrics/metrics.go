@@ -0,0 +1,313 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics records Prometheus metrics about managed resources.
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Labels applied to managed resource metrics.
+const (
+	// LabelGVK is the group, version, and kind of the managed resource.
+	LabelGVK = "gvk"
+
+	// LabelClaim is the namespace and name of the claim the managed resource
+	// was composed for, if any, in the form namespace/name.
+	LabelClaim = "claim"
+
+	// LabelComposite is the name of the composite resource the managed
+	// resource was composed for, if any.
+	LabelComposite = "composite"
+
+	// LabelOperation is the external API operation that was called.
+	LabelOperation = "operation"
+
+	// LabelCluster is the name of the cluster the managed resource exists in,
+	// when a provider reconciles managed resources in several clusters.
+	LabelCluster = "cluster"
+
+	// LabelErrorClass is the standard class of an error returned by an
+	// external API, e.g. Throttled. See errors.Code.
+	LabelErrorClass = "class"
+
+	// LabelErrorCode is the code of an error returned by an external API, as
+	// registered by the provider, e.g. ThrottlingException.
+	LabelErrorCode = "code"
+)
+
+// An Operation is a call to an external API.
+type Operation string
+
+// External API operations.
+const (
+	OperationObserve Operation = "Observe"
+	OperationCreate  Operation = "Create"
+	OperationUpdate  Operation = "Update"
+	OperationDelete  Operation = "Delete"
+)
+
+// A Recorder records metrics about managed resources.
+type Recorder interface {
+	// RecordExternalCall records the duration of a call to the external API.
+	RecordExternalCall(gvk schema.GroupVersionKind, mg resource.Managed, op Operation, d time.Duration)
+
+	// RecordExternalError records that a call to the external API returned
+	// the supplied error.
+	RecordExternalError(gvk schema.GroupVersionKind, mg resource.Managed, op Operation, err error)
+
+	// RecordExternalCalls records the number of external API calls made
+	// during a single reconcile of the managed resource.
+	RecordExternalCalls(gvk schema.GroupVersionKind, mg resource.Managed, n int)
+
+	// RecordDrift records that the external resource was found to differ
+	// from the desired state of the managed resource.
+	RecordDrift(gvk schema.GroupVersionKind, mg resource.Managed)
+
+	// RecordReady records how long the managed resource took to become ready,
+	// if it became ready since its previous Ready condition was observed.
+	RecordReady(gvk schema.GroupVersionKind, mg resource.Managed, previous xpv1.Condition)
+
+	// RecordDeleted records how long the managed resource took to delete.
+	RecordDeleted(gvk schema.GroupVersionKind, mg resource.Managed)
+}
+
+// A NopRecorder does nothing.
+type NopRecorder struct{}
+
+// NewNopRecorder returns a Recorder that does nothing.
+func NewNopRecorder() Recorder {
+	return NopRecorder{}
+}
+
+// RecordExternalCall does nothing.
+func (NopRecorder) RecordExternalCall(_ schema.GroupVersionKind, _ resource.Managed, _ Operation, _ time.Duration) {
+}
+
+// RecordExternalError does nothing.
+func (NopRecorder) RecordExternalError(_ schema.GroupVersionKind, _ resource.Managed, _ Operation, _ error) {
+}
+
+// RecordExternalCalls does nothing.
+func (NopRecorder) RecordExternalCalls(_ schema.GroupVersionKind, _ resource.Managed, _ int) {}
+
+// RecordDrift does nothing.
+func (NopRecorder) RecordDrift(_ schema.GroupVersionKind, _ resource.Managed) {}
+
+// RecordReady does nothing.
+func (NopRecorder) RecordReady(_ schema.GroupVersionKind, _ resource.Managed, _ xpv1.Condition) {}
+
+// RecordDeleted does nothing.
+func (NopRecorder) RecordDeleted(_ schema.GroupVersionKind, _ resource.Managed) {}
+
+// ManagedMetrics is a Recorder that exposes managed resource metrics to
+// Prometheus. Register it with the controller-runtime metrics registry, i.e.
+// metrics.Registry.MustRegister(m), and share it between controllers.
+type ManagedMetrics struct {
+	externalCall     *prometheus.HistogramVec
+	externalError    *prometheus.CounterVec
+	externalCalls    *prometheus.HistogramVec
+	drift            *prometheus.CounterVec
+	timeToReady      *prometheus.HistogramVec
+	firstTimeToReady *prometheus.HistogramVec
+	deletion         *prometheus.HistogramVec
+
+	now func() time.Time
+}
+
+// A ManagedMetricsOption configures ManagedMetrics.
+type ManagedMetricsOption func(o *prometheus.Labels)
+
+// WithCluster labels all metrics with the name of the cluster the managed
+// resources exist in. Use one ManagedMetrics per cluster when a provider
+// reconciles managed resources in several clusters.
+func WithCluster(name string) ManagedMetricsOption {
+	return func(l *prometheus.Labels) {
+		(*l)[LabelCluster] = name
+	}
+}
+
+// NewManagedMetrics returns a new ManagedMetrics.
+func NewManagedMetrics(o ...ManagedMetricsOption) *ManagedMetrics {
+	cl := prometheus.Labels{}
+	for _, fn := range o {
+		fn(&cl)
+	}
+	keys := []string{LabelGVK, LabelClaim, LabelComposite}
+	return &ManagedMetrics{
+		externalCall: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:        "crossplane_managed_resource_external_api_duration_seconds",
+			ConstLabels: cl,
+			Help:        "The time taken by calls to the external API, by operation.",
+			Buckets:     prometheus.DefBuckets,
+		}, append(keys, LabelOperation)),
+		externalError: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "crossplane_managed_resource_external_api_errors_total",
+			ConstLabels: cl,
+			Help:        "The number of errors returned by calls to the external API, by operation, error class, and error code.",
+		}, append(keys, LabelOperation, LabelErrorClass, LabelErrorCode)),
+		externalCalls: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:        "crossplane_managed_resource_external_api_calls_per_reconcile",
+			ConstLabels: cl,
+			Help:        "The number of calls made to the external API during a single reconcile.",
+			Buckets:     prometheus.ExponentialBuckets(1, 2, 10),
+		}, keys),
+		drift: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "crossplane_managed_resource_drift_detections_total",
+			ConstLabels: cl,
+			Help:        "The number of times an external resource was found to differ from its managed resource.",
+		}, keys),
+		timeToReady: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:        "crossplane_managed_resource_time_to_readiness_seconds",
+			ConstLabels: cl,
+			Help:        "The time taken for a managed resource to become ready, since it was created or last became unready.",
+			Buckets:     prometheus.ExponentialBuckets(1, 2, 14),
+		}, keys),
+		firstTimeToReady: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:        "crossplane_managed_resource_first_time_to_readiness_seconds",
+			ConstLabels: cl,
+			Help:        "The time taken for a managed resource to first become ready after it was created.",
+			Buckets:     prometheus.ExponentialBuckets(1, 2, 14),
+		}, keys),
+		deletion: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:        "crossplane_managed_resource_deletion_seconds",
+			ConstLabels: cl,
+			Help:        "The time taken for a managed resource to be deleted, since deletion was requested.",
+			Buckets:     prometheus.ExponentialBuckets(1, 2, 14),
+		}, keys),
+		now: time.Now,
+	}
+}
+
+// labels returns the labels of the supplied managed resource.
+func labels(gvk schema.GroupVersionKind, mg resource.Managed) prometheus.Labels {
+	l := prometheus.Labels{LabelGVK: gvk.String(), LabelClaim: "", LabelComposite: ""}
+	if name := mg.GetLabels()[meta.LabelKeyClaimName]; name != "" {
+		l[LabelClaim] = mg.GetLabels()[meta.LabelKeyClaimNamespace] + "/" + name
+	}
+	l[LabelComposite] = mg.GetLabels()[meta.LabelKeyComposite]
+	return l
+}
+
+// RecordExternalCall records the duration of a call to the external API.
+func (m *ManagedMetrics) RecordExternalCall(gvk schema.GroupVersionKind, mg resource.Managed, op Operation, d time.Duration) {
+	l := labels(gvk, mg)
+	l[LabelOperation] = string(op)
+	m.externalCall.With(l).Observe(d.Seconds())
+}
+
+// RecordExternalError records that a call to the external API returned the
+// supplied error, labelled by its class and external code. Errors are given a
+// class and external code by an ErrorTranslator such as a translate.Registry.
+// Nothing is recorded if the supplied error is nil.
+func (m *ManagedMetrics) RecordExternalError(gvk schema.GroupVersionKind, mg resource.Managed, op Operation, err error) {
+	if err == nil {
+		return
+	}
+	l := labels(gvk, mg)
+	l[LabelOperation] = string(op)
+	l[LabelErrorClass] = string(errors.CodeOf(err))
+	l[LabelErrorCode] = errors.ExternalCodeOf(err)
+	m.externalError.With(l).Inc()
+}
+
+// RecordExternalCalls records the number of external API calls made during a
+// single reconcile of the managed resource.
+func (m *ManagedMetrics) RecordExternalCalls(gvk schema.GroupVersionKind, mg resource.Managed, n int) {
+	m.externalCalls.With(labels(gvk, mg)).Observe(float64(n))
+}
+
+// RecordDrift records that the external resource was found to differ from the
+// desired state of the managed resource.
+func (m *ManagedMetrics) RecordDrift(gvk schema.GroupVersionKind, mg resource.Managed) {
+	m.drift.With(labels(gvk, mg)).Inc()
+}
+
+// RecordReady records how long the managed resource took to become ready, if
+// its Ready condition is now true but the supplied previous Ready condition
+// was not. The time to readiness is measured from the previous condition's
+// last transition, or from creation if there was no previous condition. The
+// first time to readiness is also recorded if the managed resource had never
+// been ready, i.e. it had no previous Ready condition or was being created.
+func (m *ManagedMetrics) RecordReady(gvk schema.GroupVersionKind, mg resource.Managed, previous xpv1.Condition) {
+	if mg.GetCondition(xpv1.TypeReady).Status != corev1.ConditionTrue || previous.Status == corev1.ConditionTrue {
+		return
+	}
+
+	now := m.now()
+	l := labels(gvk, mg)
+	since := mg.GetCreationTimestamp().Time
+	if !previous.LastTransitionTime.IsZero() {
+		since = previous.LastTransitionTime.Time
+	}
+	m.timeToReady.With(l).Observe(now.Sub(since).Seconds())
+
+	if FirstReady(mg, previous) {
+		m.firstTimeToReady.With(l).Observe(now.Sub(mg.GetCreationTimestamp().Time).Seconds())
+	}
+}
+
+// FirstReady returns true if the supplied managed resource's Ready condition
+// is now true for the first time since it was created, given its previous
+// Ready condition. A managed resource is considered never to have been ready
+// if it had no previous Ready condition, or was being created.
+func FirstReady(mg resource.Managed, previous xpv1.Condition) bool {
+	if mg.GetCondition(xpv1.TypeReady).Status != corev1.ConditionTrue || previous.Status == corev1.ConditionTrue {
+		return false
+	}
+	return previous.Reason == "" || previous.Reason == xpv1.ReasonCreating
+}
+
+// RecordDeleted records how long the managed resource took to delete, since
+// its deletion was requested.
+func (m *ManagedMetrics) RecordDeleted(gvk schema.GroupVersionKind, mg resource.Managed) {
+	if mg.GetDeletionTimestamp() == nil {
+		return
+	}
+	m.deletion.With(labels(gvk, mg)).Observe(m.now().Sub(mg.GetDeletionTimestamp().Time).Seconds())
+}
+
+// Describe sends the descriptors of all managed resource metrics.
+func (m *ManagedMetrics) Describe(ch chan<- *prometheus.Desc) {
+	m.externalCall.Describe(ch)
+	m.externalError.Describe(ch)
+	m.externalCalls.Describe(ch)
+	m.drift.Describe(ch)
+	m.timeToReady.Describe(ch)
+	m.firstTimeToReady.Describe(ch)
+	m.deletion.Describe(ch)
+}
+
+// Collect sends the current values of all managed resource metrics.
+func (m *ManagedMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.externalCall.Collect(ch)
+	m.externalError.Collect(ch)
+	m.externalCalls.Collect(ch)
+	m.drift.Collect(ch)
+	m.timeToReady.Collect(ch)
+	m.firstTimeToReady.Collect(ch)
+	m.deletion.Collect(ch)
+}
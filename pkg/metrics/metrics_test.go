@@ -0,0 +1,245 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+)
+
+func TestRecordReady(t *testing.T) {
+	now := time.Now()
+	created := metav1.NewTime(now.Add(-10 * time.Minute))
+	gvk := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Cool"}
+
+	type args struct {
+		current  xpv1.Condition
+		previous xpv1.Condition
+	}
+	type want struct {
+		ready int
+		first int
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotReady": {
+			reason: "Nothing should be recorded if the managed resource isn't ready.",
+			args: args{
+				current:  xpv1.Creating(),
+				previous: xpv1.Condition{},
+			},
+		},
+		"AlreadyReady": {
+			reason: "Nothing should be recorded if the managed resource was already ready.",
+			args: args{
+				current:  xpv1.Available(),
+				previous: xpv1.Available(),
+			},
+		},
+		"FirstReady": {
+			reason: "Both the time to readiness and the first time to readiness should be recorded if the managed resource was being created.",
+			args: args{
+				current:  xpv1.Available(),
+				previous: xpv1.Creating(),
+			},
+			want: want{ready: 1, first: 1},
+		},
+		"ReadyAgain": {
+			reason: "Only the time to readiness should be recorded if the managed resource was ready before.",
+			args: args{
+				current:  xpv1.Available(),
+				previous: xpv1.Unavailable(),
+			},
+			want: want{ready: 1, first: 0},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := NewManagedMetrics()
+			m.now = func() time.Time { return now }
+
+			mg := &fake.Managed{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: created}}
+			mg.SetConditions(tc.args.current)
+			m.RecordReady(gvk, mg, tc.args.previous)
+
+			got := want{
+				ready: testutil.CollectAndCount(m.timeToReady),
+				first: testutil.CollectAndCount(m.firstTimeToReady),
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nRecordReady(...): -want series, +got series:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestFirstReady(t *testing.T) {
+	type args struct {
+		current  xpv1.Condition
+		previous xpv1.Condition
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   bool
+	}{
+		"NotReady": {
+			reason: "A managed resource that isn't ready isn't ready for the first time.",
+			args: args{
+				current:  xpv1.Creating(),
+				previous: xpv1.Condition{},
+			},
+			want: false,
+		},
+		"AlreadyReady": {
+			reason: "A managed resource that was already ready isn't ready for the first time.",
+			args: args{
+				current:  xpv1.Available(),
+				previous: xpv1.Available(),
+			},
+			want: false,
+		},
+		"NoPreviousCondition": {
+			reason: "A managed resource that had no Ready condition is ready for the first time.",
+			args: args{
+				current:  xpv1.Available(),
+				previous: xpv1.Condition{},
+			},
+			want: true,
+		},
+		"Created": {
+			reason: "A managed resource that was being created is ready for the first time.",
+			args: args{
+				current:  xpv1.Available(),
+				previous: xpv1.Creating(),
+			},
+			want: true,
+		},
+		"ReadyAgain": {
+			reason: "A managed resource that was unavailable has been ready before.",
+			args: args{
+				current:  xpv1.Available(),
+				previous: xpv1.Unavailable(),
+			},
+			want: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &fake.Managed{}
+			mg.SetConditions(tc.args.current)
+			got := FirstReady(mg, tc.args.previous)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nFirstReady(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRecordExternalError(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Cool"}
+
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   string
+	}{
+		"NoError": {
+			reason: "Nothing should be recorded if the call succeeded.",
+			err:    nil,
+			want:   "",
+		},
+		"Translated": {
+			reason: "Errors should be labelled with their class and external code.",
+			err:    errors.WithCode(errors.WithExternalCode(errors.New("boom"), "AccessDenied"), errors.CodeUnauthorized),
+			want: `
+				# HELP crossplane_managed_resource_external_api_errors_total The number of errors returned by calls to the external API, by operation, error class, and error code.
+				# TYPE crossplane_managed_resource_external_api_errors_total counter
+				crossplane_managed_resource_external_api_errors_total{claim="",class="Unauthorized",code="AccessDenied",composite="",gvk="example.org/v1, Kind=Cool",operation="Observe"} 1
+			`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := NewManagedMetrics()
+			m.RecordExternalError(gvk, &fake.Managed{}, OperationObserve, tc.err)
+
+			if err := testutil.CollectAndCompare(m.externalError, strings.NewReader(tc.want)); err != nil {
+				t.Errorf("\n%s\nRecordExternalError(...): %s", tc.reason, err)
+			}
+		})
+	}
+}
+
+func TestLabels(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Cool"}
+
+	cases := map[string]struct {
+		reason string
+		labels map[string]string
+		want   string
+	}{
+		"Unowned": {
+			reason: "A managed resource that wasn't composed should have empty ownership labels.",
+			want: `
+				# HELP crossplane_managed_resource_drift_detections_total The number of times an external resource was found to differ from its managed resource.
+				# TYPE crossplane_managed_resource_drift_detections_total counter
+				crossplane_managed_resource_drift_detections_total{claim="",composite="",gvk="example.org/v1, Kind=Cool"} 1
+			`,
+		},
+		"Claimed": {
+			reason: "A managed resource that was composed for a claim should be labelled with its claim and composite.",
+			labels: map[string]string{
+				meta.LabelKeyClaimNamespace: "default",
+				meta.LabelKeyClaimName:      "cool-claim",
+				meta.LabelKeyComposite:      "cool-xr",
+			},
+			want: `
+				# HELP crossplane_managed_resource_drift_detections_total The number of times an external resource was found to differ from its managed resource.
+				# TYPE crossplane_managed_resource_drift_detections_total counter
+				crossplane_managed_resource_drift_detections_total{claim="default/cool-claim",composite="cool-xr",gvk="example.org/v1, Kind=Cool"} 1
+			`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := NewManagedMetrics()
+			m.RecordDrift(gvk, &fake.Managed{ObjectMeta: metav1.ObjectMeta{Labels: tc.labels}})
+			if err := testutil.CollectAndCompare(m.drift, strings.NewReader(tc.want)); err != nil {
+				t.Errorf("\n%s\nRecordDrift(...): %s", tc.reason, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,319 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	commonv1 "go.opentelemetry.io/proto/otlp/common/v1"
+	metricsv1 "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcev1 "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/protobuf/proto"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+const (
+	errGather          = "cannot gather metrics"
+	errMarshal         = "cannot marshal OTLP metrics"
+	errNewRequest      = "cannot create OTLP export request"
+	errExport          = "cannot export OTLP metrics"
+	errFmtExportStatus = "OTLP endpoint returned status %d: %s"
+)
+
+const (
+	// DefaultOTLPExportInterval is the default interval at which metrics are
+	// exported.
+	DefaultOTLPExportInterval = 30 * time.Second
+
+	otlpScope       = "github.com/crossplane/crossplane-runtime/pkg/metrics"
+	otlpContentType = "application/x-protobuf"
+)
+
+// An OTLPExporterOption configures an OTLPExporter.
+type OTLPExporterOption func(e *OTLPExporter)
+
+// WithOTLPGatherer configures the Prometheus gatherer whose metrics are
+// exported. The controller-runtime metrics registry is used by default.
+func WithOTLPGatherer(g prometheus.Gatherer) OTLPExporterOption {
+	return func(e *OTLPExporter) {
+		e.gatherer = g
+	}
+}
+
+// WithOTLPExportInterval configures the interval at which metrics are
+// exported.
+func WithOTLPExportInterval(d time.Duration) OTLPExporterOption {
+	return func(e *OTLPExporter) {
+		e.interval = d
+	}
+}
+
+// WithOTLPHeaders configures headers sent with each export, for example to
+// authenticate to the endpoint.
+func WithOTLPHeaders(h map[string]string) OTLPExporterOption {
+	return func(e *OTLPExporter) {
+		e.headers = h
+	}
+}
+
+// WithOTLPResourceAttributes configures attributes describing the exporting
+// process, for example service.name.
+func WithOTLPResourceAttributes(a map[string]string) OTLPExporterOption {
+	return func(e *OTLPExporter) {
+		e.attributes = a
+	}
+}
+
+// WithOTLPHTTPClient configures the HTTP client used to export metrics.
+func WithOTLPHTTPClient(c *http.Client) OTLPExporterOption {
+	return func(e *OTLPExporter) {
+		e.client = c
+	}
+}
+
+// WithOTLPLogger configures the logger used to report failed exports.
+func WithOTLPLogger(l logging.Logger) OTLPExporterOption {
+	return func(e *OTLPExporter) {
+		e.log = l
+	}
+}
+
+// An OTLPExporter periodically pushes the metrics of a Prometheus gatherer to
+// an OpenTelemetry collector using OTLP over HTTP, for environments where
+// metrics are pushed rather than scraped. Counters are exported as cumulative
+// monotonic sums, gauges and untyped metrics as gauges, and histograms and
+// summaries as their OTLP equivalents.
+type OTLPExporter struct {
+	endpoint   string
+	gatherer   prometheus.Gatherer
+	interval   time.Duration
+	headers    map[string]string
+	attributes map[string]string
+	client     *http.Client
+	log        logging.Logger
+
+	started time.Time
+	now     func() time.Time
+}
+
+// NewOTLPExporter returns an OTLPExporter that exports metrics to the supplied
+// OTLP/HTTP metrics endpoint, e.g. http://collector:4318/v1/metrics. Add it to
+// a controller manager to export metrics while the manager runs.
+func NewOTLPExporter(endpoint string, o ...OTLPExporterOption) *OTLPExporter {
+	e := &OTLPExporter{
+		endpoint: endpoint,
+		gatherer: metrics.Registry,
+		interval: DefaultOTLPExportInterval,
+		client:   http.DefaultClient,
+		log:      logging.NewNopLogger(),
+		started:  time.Now(),
+		now:      time.Now,
+	}
+	for _, fn := range o {
+		fn(e)
+	}
+	return e
+}
+
+// NeedLeaderElection returns false; every replica exports its own metrics.
+func (e *OTLPExporter) NeedLeaderElection() bool {
+	return false
+}
+
+// Start exporting metrics at the configured interval until the supplied
+// context is cancelled. Failed exports are logged and retried at the next
+// interval.
+func (e *OTLPExporter) Start(ctx context.Context) error {
+	t := time.NewTicker(e.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+			if err := e.Export(ctx); err != nil {
+				e.log.Info("Cannot export metrics", "error", err, "endpoint", e.endpoint)
+			}
+		}
+	}
+}
+
+// Export the current value of all metrics.
+func (e *OTLPExporter) Export(ctx context.Context) error {
+	mfs, err := e.gatherer.Gather()
+	if err != nil {
+		return errors.Wrap(err, errGather)
+	}
+
+	// An ExportMetricsServiceRequest is wire compatible with MetricsData.
+	body, err := proto.Marshal(e.convert(mfs))
+	if err != nil {
+		return errors.Wrap(err, errMarshal)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, errNewRequest)
+	}
+	req.Header.Set("Content-Type", otlpContentType)
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+
+	rsp, err := e.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, errExport)
+	}
+	defer rsp.Body.Close() //nolint:errcheck // Nothing useful can be done with this error.
+	if rsp.StatusCode < 200 || rsp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(rsp.Body, 1024))
+		return errors.Errorf(errFmtExportStatus, rsp.StatusCode, string(msg))
+	}
+	return nil
+}
+
+func (e *OTLPExporter) convert(mfs []*dto.MetricFamily) *metricsv1.MetricsData {
+	start := uint64(e.started.UnixNano())
+	now := uint64(e.now().UnixNano())
+
+	ms := make([]*metricsv1.Metric, 0, len(mfs))
+	for _, mf := range mfs {
+		m := &metricsv1.Metric{Name: mf.GetName(), Description: mf.GetHelp()}
+		switch mf.GetType() { //nolint:exhaustive // Other types are exported as gauges.
+		case dto.MetricType_COUNTER:
+			s := &metricsv1.Sum{IsMonotonic: true, AggregationTemporality: metricsv1.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE}
+			for _, pm := range mf.GetMetric() {
+				s.DataPoints = append(s.DataPoints, number(pm, pm.GetCounter().GetValue(), start, now))
+			}
+			m.Data = &metricsv1.Metric_Sum{Sum: s}
+		case dto.MetricType_HISTOGRAM:
+			h := &metricsv1.Histogram{AggregationTemporality: metricsv1.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE}
+			for _, pm := range mf.GetMetric() {
+				h.DataPoints = append(h.DataPoints, histogram(pm, start, now))
+			}
+			m.Data = &metricsv1.Metric_Histogram{Histogram: h}
+		case dto.MetricType_SUMMARY:
+			s := &metricsv1.Summary{}
+			for _, pm := range mf.GetMetric() {
+				s.DataPoints = append(s.DataPoints, summary(pm, start, now))
+			}
+			m.Data = &metricsv1.Metric_Summary{Summary: s}
+		default:
+			g := &metricsv1.Gauge{}
+			for _, pm := range mf.GetMetric() {
+				v := pm.GetGauge().GetValue()
+				if mf.GetType() == dto.MetricType_UNTYPED {
+					v = pm.GetUntyped().GetValue()
+				}
+				g.DataPoints = append(g.DataPoints, number(pm, v, 0, now))
+			}
+			m.Data = &metricsv1.Metric_Gauge{Gauge: g}
+		}
+		ms = append(ms, m)
+	}
+
+	return &metricsv1.MetricsData{ResourceMetrics: []*metricsv1.ResourceMetrics{{
+		Resource:     &resourcev1.Resource{Attributes: attributes(e.attributes)},
+		ScopeMetrics: []*metricsv1.ScopeMetrics{{Scope: &commonv1.InstrumentationScope{Name: otlpScope}, Metrics: ms}},
+	}}}
+}
+
+func number(pm *dto.Metric, v float64, start, now uint64) *metricsv1.NumberDataPoint {
+	return &metricsv1.NumberDataPoint{
+		Attributes:        labelAttributes(pm),
+		StartTimeUnixNano: start,
+		TimeUnixNano:      now,
+		Value:             &metricsv1.NumberDataPoint_AsDouble{AsDouble: v},
+	}
+}
+
+func histogram(pm *dto.Metric, start, now uint64) *metricsv1.HistogramDataPoint {
+	h := pm.GetHistogram()
+	sum := h.GetSampleSum()
+	dp := &metricsv1.HistogramDataPoint{
+		Attributes:        labelAttributes(pm),
+		StartTimeUnixNano: start,
+		TimeUnixNano:      now,
+		Count:             h.GetSampleCount(),
+		Sum:               &sum,
+	}
+
+	// Prometheus bucket counts are cumulative, while OTLP bucket counts are
+	// not. OTLP has an implicit +Inf bucket.
+	prev := uint64(0)
+	for _, b := range h.GetBucket() {
+		if math.IsInf(b.GetUpperBound(), 1) {
+			continue
+		}
+		dp.ExplicitBounds = append(dp.ExplicitBounds, b.GetUpperBound())
+		dp.BucketCounts = append(dp.BucketCounts, b.GetCumulativeCount()-prev)
+		prev = b.GetCumulativeCount()
+	}
+	dp.BucketCounts = append(dp.BucketCounts, h.GetSampleCount()-prev)
+	return dp
+}
+
+func summary(pm *dto.Metric, start, now uint64) *metricsv1.SummaryDataPoint {
+	s := pm.GetSummary()
+	dp := &metricsv1.SummaryDataPoint{
+		Attributes:        labelAttributes(pm),
+		StartTimeUnixNano: start,
+		TimeUnixNano:      now,
+		Count:             s.GetSampleCount(),
+		Sum:               s.GetSampleSum(),
+	}
+	for _, q := range s.GetQuantile() {
+		dp.QuantileValues = append(dp.QuantileValues, &metricsv1.SummaryDataPoint_ValueAtQuantile{Quantile: q.GetQuantile(), Value: q.GetValue()})
+	}
+	return dp
+}
+
+func labelAttributes(pm *dto.Metric) []*commonv1.KeyValue {
+	kvs := make([]*commonv1.KeyValue, 0, len(pm.GetLabel()))
+	for _, l := range pm.GetLabel() {
+		kvs = append(kvs, attribute(l.GetName(), l.GetValue()))
+	}
+	return kvs
+}
+
+func attributes(a map[string]string) []*commonv1.KeyValue {
+	keys := make([]string, 0, len(a))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	kvs := make([]*commonv1.KeyValue, 0, len(a))
+	for _, k := range keys {
+		kvs = append(kvs, attribute(k, a[k]))
+	}
+	return kvs
+}
+
+func attribute(k, v string) *commonv1.KeyValue {
+	return &commonv1.KeyValue{Key: k, Value: &commonv1.AnyValue{Value: &commonv1.AnyValue_StringValue{StringValue: v}}}
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	commonv1 "go.opentelemetry.io/proto/otlp/common/v1"
+	metricsv1 "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcev1 "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestOTLPExporterExport(t *testing.T) {
+	started := time.Unix(100, 0)
+	now := time.Unix(200, 0)
+
+	reg := prometheus.NewRegistry()
+	c := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "cool_total", Help: "Cool things."}, []string{"kind"})
+	c.WithLabelValues("very").Add(3)
+	h := prometheus.NewHistogram(prometheus.HistogramOpts{Name: "cool_seconds", Help: "Cool durations.", Buckets: []float64{1, 2}})
+	h.Observe(0.5)
+	h.Observe(1.5)
+	h.Observe(3)
+	reg.MustRegister(c, h)
+
+	sum := 5.0
+	scope := &commonv1.InstrumentationScope{Name: otlpScope}
+	kv := func(k, v string) *commonv1.KeyValue {
+		return &commonv1.KeyValue{Key: k, Value: &commonv1.AnyValue{Value: &commonv1.AnyValue_StringValue{StringValue: v}}}
+	}
+
+	type want struct {
+		data *metricsv1.MetricsData
+		err  error
+	}
+	cases := map[string]struct {
+		reason string
+		status int
+		want   want
+	}{
+		"Success": {
+			reason: "Metrics should be converted to OTLP and exported.",
+			status: http.StatusOK,
+			want: want{
+				data: &metricsv1.MetricsData{ResourceMetrics: []*metricsv1.ResourceMetrics{{
+					Resource: &resourcev1.Resource{Attributes: []*commonv1.KeyValue{kv("service.name", "provider-cool")}},
+					ScopeMetrics: []*metricsv1.ScopeMetrics{{Scope: scope, Metrics: []*metricsv1.Metric{
+						{
+							Name:        "cool_seconds",
+							Description: "Cool durations.",
+							Data: &metricsv1.Metric_Histogram{Histogram: &metricsv1.Histogram{
+								AggregationTemporality: metricsv1.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+								DataPoints: []*metricsv1.HistogramDataPoint{{
+									Attributes:        []*commonv1.KeyValue{},
+									StartTimeUnixNano: uint64(started.UnixNano()),
+									TimeUnixNano:      uint64(now.UnixNano()),
+									Count:             3,
+									Sum:               &sum,
+									ExplicitBounds:    []float64{1, 2},
+									BucketCounts:      []uint64{1, 1, 1},
+								}},
+							}},
+						},
+						{
+							Name:        "cool_total",
+							Description: "Cool things.",
+							Data: &metricsv1.Metric_Sum{Sum: &metricsv1.Sum{
+								IsMonotonic:            true,
+								AggregationTemporality: metricsv1.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+								DataPoints: []*metricsv1.NumberDataPoint{{
+									Attributes:        []*commonv1.KeyValue{kv("kind", "very")},
+									StartTimeUnixNano: uint64(started.UnixNano()),
+									TimeUnixNano:      uint64(now.UnixNano()),
+									Value:             &metricsv1.NumberDataPoint_AsDouble{AsDouble: 3},
+								}},
+							}},
+						},
+					}}},
+				}}},
+			},
+		},
+		"ErrorStatus": {
+			reason: "An error should be returned if the endpoint does not accept the metrics.",
+			status: http.StatusBadRequest,
+			want: want{
+				err: errors.Errorf(errFmtExportStatus, http.StatusBadRequest, "nope"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got *metricsv1.MetricsData
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Content-Type") != otlpContentType || r.Header.Get("Authorization") != "Bearer cool" {
+					w.WriteHeader(http.StatusUnsupportedMediaType)
+					return
+				}
+				if tc.status != http.StatusOK {
+					w.WriteHeader(tc.status)
+					_, _ = w.Write([]byte("nope"))
+					return
+				}
+				b, _ := io.ReadAll(r.Body)
+				got = &metricsv1.MetricsData{}
+				if err := proto.Unmarshal(b, got); err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+				}
+			}))
+			defer srv.Close()
+
+			e := NewOTLPExporter(srv.URL,
+				WithOTLPGatherer(reg),
+				WithOTLPHeaders(map[string]string{"Authorization": "Bearer cool"}),
+				WithOTLPResourceAttributes(map[string]string{"service.name": "provider-cool"}),
+			)
+			e.started = started
+			e.now = func() time.Time { return now }
+
+			err := e.Export(context.Background())
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Export(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.data, got, protocmp.Transform()); diff != "" {
+				t.Errorf("\n%s\ne.Export(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
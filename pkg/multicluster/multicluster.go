@@ -0,0 +1,296 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package multicluster allows one provider process to reconcile managed
+// resources in several Kubernetes clusters, for example clusters discovered
+// using Cluster API.
+package multicluster
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// separator separates the cluster name from the namespace of an encoded
+// request. Neither cluster names nor namespaces may contain it.
+const separator = "/"
+
+const (
+	errFmtInvalidName = "invalid cluster name %q: must not be empty or contain %q"
+	errFmtExists      = "cluster %q already exists"
+)
+
+// A Request to reconcile a resource in a particular cluster.
+type Request struct {
+	// Cluster in which the resource exists. The empty string identifies the
+	// cluster the provider runs in.
+	Cluster string
+
+	reconcile.Request
+}
+
+// EncodeRequest encodes the supplied cluster aware request as a regular
+// reconcile.Request, so that it may be enqueued by a controller.
+func EncodeRequest(r Request) reconcile.Request {
+	if r.Cluster == "" {
+		return r.Request
+	}
+	return reconcile.Request{NamespacedName: types.NamespacedName{
+		Namespace: r.Cluster + separator + r.Namespace,
+		Name:      r.Name,
+	}}
+}
+
+// DecodeRequest decodes a reconcile.Request encoded by EncodeRequest. Requests
+// that were not encoded are decoded as requests for the local cluster.
+func DecodeRequest(r reconcile.Request) Request {
+	c, ns, ok := strings.Cut(r.Namespace, separator)
+	if !ok {
+		return Request{Request: r}
+	}
+	return Request{Cluster: c, Request: reconcile.Request{NamespacedName: types.NamespacedName{Namespace: ns, Name: r.Name}}}
+}
+
+type contextKey struct{}
+
+// WithCluster returns a copy of the supplied context that identifies the
+// supplied cluster.
+func WithCluster(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, contextKey{}, name)
+}
+
+// FromContext returns the name of the cluster identified by the supplied
+// context, or the empty string if it identifies no cluster.
+func FromContext(ctx context.Context) string {
+	name, _ := ctx.Value(contextKey{}).(string)
+	return name
+}
+
+// ExternalTagger returns an ExternalTagger that tags external resources with
+// the name of the cluster their managed resource exists in, if any.
+func ExternalTagger() resource.ExternalTagger {
+	return resource.ExternalTaggerFn(func(ctx context.Context, _ resource.Managed) (map[string]string, error) {
+		name := FromContext(ctx)
+		if name == "" {
+			return nil, nil
+		}
+		return map[string]string{resource.ExternalResourceTagKeyCluster: name}, nil
+	})
+}
+
+// Clusters is a registry of named clusters.
+type Clusters struct {
+	mu       sync.RWMutex
+	clusters map[string]cluster.Cluster
+}
+
+// NewClusters returns an empty registry of clusters.
+func NewClusters() *Clusters {
+	return &Clusters{clusters: make(map[string]cluster.Cluster)}
+}
+
+// Add the supplied cluster to the registry. The cluster should also be added
+// to a manager so that its cache is started.
+func (cs *Clusters) Add(name string, c cluster.Cluster) error {
+	if name == "" || strings.Contains(name, separator) {
+		return errors.Errorf(errFmtInvalidName, name, separator)
+	}
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if _, ok := cs.clusters[name]; ok {
+		return errors.Errorf(errFmtExists, name)
+	}
+	cs.clusters[name] = c
+	return nil
+}
+
+// Remove the named cluster from the registry.
+func (cs *Clusters) Remove(name string) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	delete(cs.clusters, name)
+}
+
+// Get the named cluster.
+func (cs *Clusters) Get(name string) (cluster.Cluster, bool) {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	c, ok := cs.clusters[name]
+	return c, ok
+}
+
+// Names returns the sorted names of all registered clusters.
+func (cs *Clusters) Names() []string {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	names := make([]string, 0, len(cs.clusters))
+	for n := range cs.clusters {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// A Reconciler dispatches requests encoded by EncodeRequest to the reconciler
+// of the cluster they were enqueued for.
+type Reconciler struct {
+	mu          sync.RWMutex
+	reconcilers map[string]reconcile.Reconciler
+}
+
+// NewReconciler returns a Reconciler that dispatches requests to per-cluster
+// reconcilers.
+func NewReconciler() *Reconciler {
+	return &Reconciler{reconcilers: make(map[string]reconcile.Reconciler)}
+}
+
+// Add the reconciler for the named cluster. Use the empty string to name the
+// cluster the provider runs in.
+func (r *Reconciler) Add(name string, rec reconcile.Reconciler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reconcilers[name] = rec
+}
+
+// Remove the reconciler for the named cluster.
+func (r *Reconciler) Remove(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.reconcilers, name)
+}
+
+// Reconcile the supplied request using the reconciler of the cluster it was
+// enqueued for. The cluster is identified by the context passed to that
+// reconciler. Requests for clusters without a reconciler, for example because
+// they were removed, are dropped.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	cr := DecodeRequest(req)
+	r.mu.RLock()
+	rec, ok := r.reconcilers[cr.Cluster]
+	r.mu.RUnlock()
+	if !ok {
+		return reconcile.Result{}, nil
+	}
+	return rec.Reconcile(WithCluster(ctx, cr.Cluster), cr.Request)
+}
+
+// EnqueueRequestsForCluster wraps the supplied event handler such that the
+// requests it enqueues are encoded with the name of the supplied cluster.
+func EnqueueRequestsForCluster(name string, h handler.EventHandler) handler.EventHandler {
+	return &clusterHandler{cluster: name, wrapped: h}
+}
+
+type clusterHandler struct {
+	cluster string
+	wrapped handler.EventHandler
+}
+
+func (h *clusterHandler) Create(e event.CreateEvent, q workqueue.RateLimitingInterface) {
+	h.wrapped.Create(e, &clusterQueue{RateLimitingInterface: q, cluster: h.cluster})
+}
+
+func (h *clusterHandler) Update(e event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	h.wrapped.Update(e, &clusterQueue{RateLimitingInterface: q, cluster: h.cluster})
+}
+
+func (h *clusterHandler) Delete(e event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	h.wrapped.Delete(e, &clusterQueue{RateLimitingInterface: q, cluster: h.cluster})
+}
+
+func (h *clusterHandler) Generic(e event.GenericEvent, q workqueue.RateLimitingInterface) {
+	h.wrapped.Generic(e, &clusterQueue{RateLimitingInterface: q, cluster: h.cluster})
+}
+
+// A clusterQueue encodes the cluster name in any reconcile.Request added to
+// it. Items of other types are added unchanged.
+type clusterQueue struct {
+	workqueue.RateLimitingInterface
+	cluster string
+}
+
+func (q *clusterQueue) encode(item any) any {
+	req, ok := item.(reconcile.Request)
+	if !ok {
+		return item
+	}
+	return EncodeRequest(Request{Cluster: q.cluster, Request: req})
+}
+
+func (q *clusterQueue) Add(item any) {
+	q.RateLimitingInterface.Add(q.encode(item))
+}
+
+func (q *clusterQueue) AddRateLimited(item any) {
+	q.RateLimitingInterface.AddRateLimited(q.encode(item))
+}
+
+func (q *clusterQueue) AddAfter(item any, d time.Duration) {
+	q.RateLimitingInterface.AddAfter(q.encode(item), d)
+}
+
+// Watch the supplied kind of object in the supplied named cluster, enqueuing
+// requests encoded with the cluster's name.
+func Watch(c controller.Controller, name string, cl cluster.Cluster, obj client.Object, h handler.EventHandler, p ...predicate.Predicate) error {
+	return c.Watch(source.NewKindWithCache(obj, cl.GetCache()), EnqueueRequestsForCluster(name, h), p...)
+}
+
+// ManagerFor returns a manager that uses the clients, cache, and scheme of the
+// supplied cluster, while delegating running controllers and other runnables
+// to the supplied manager. It allows reconcilers that are constructed from a
+// manager, such as the managed resource reconciler, to reconcile resources in
+// another cluster.
+func ManagerFor(m manager.Manager, c cluster.Cluster) manager.Manager {
+	return &clusterManager{Manager: m, cluster: c}
+}
+
+type clusterManager struct {
+	manager.Manager
+	cluster cluster.Cluster
+}
+
+func (m *clusterManager) GetConfig() *rest.Config              { return m.cluster.GetConfig() }
+func (m *clusterManager) GetScheme() *runtime.Scheme           { return m.cluster.GetScheme() }
+func (m *clusterManager) GetClient() client.Client             { return m.cluster.GetClient() }
+func (m *clusterManager) GetFieldIndexer() client.FieldIndexer { return m.cluster.GetFieldIndexer() }
+func (m *clusterManager) GetCache() cache.Cache                { return m.cluster.GetCache() }
+func (m *clusterManager) GetRESTMapper() kmeta.RESTMapper      { return m.cluster.GetRESTMapper() }
+func (m *clusterManager) GetAPIReader() client.Reader          { return m.cluster.GetAPIReader() }
+func (m *clusterManager) GetEventRecorderFor(name string) record.EventRecorder {
+	return m.cluster.GetEventRecorderFor(name)
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package multicluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var _ reconcile.Reconciler = &Reconciler{}
+
+func TestRequestRoundTrip(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		req    Request
+		want   reconcile.Request
+	}{
+		"LocalCluster": {
+			reason: "Requests for the local cluster should not be encoded.",
+			req:    Request{Request: reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "cool"}}},
+			want:   reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "cool"}},
+		},
+		"ClusterScoped": {
+			reason: "Requests for cluster scoped resources should be encoded with their cluster name.",
+			req:    Request{Cluster: "remote", Request: reconcile.Request{NamespacedName: types.NamespacedName{Name: "cool"}}},
+			want:   reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "remote/", Name: "cool"}},
+		},
+		"Namespaced": {
+			reason: "Requests for namespaced resources should be encoded with their cluster name.",
+			req:    Request{Cluster: "remote", Request: reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "cool"}}},
+			want:   reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "remote/ns", Name: "cool"}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := EncodeRequest(tc.req)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nEncodeRequest(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.req, DecodeRequest(got)); diff != "" {
+				t.Errorf("\n%s\nDecodeRequest(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestReconciler(t *testing.T) {
+	type want struct {
+		cluster string
+		req     reconcile.Request
+		called  bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		req    reconcile.Request
+		want   want
+	}{
+		"RemoteCluster": {
+			reason: "Requests should be dispatched to the reconciler of the cluster they were enqueued for.",
+			req:    reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "remote/ns", Name: "cool"}},
+			want: want{
+				cluster: "remote",
+				req:     reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "cool"}},
+				called:  true,
+			},
+		},
+		"UnknownCluster": {
+			reason: "Requests for unknown clusters should be dropped.",
+			req:    reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "gone/ns", Name: "cool"}},
+			want:   want{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := want{}
+			r := NewReconciler()
+			r.Add("remote", reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+				got = want{cluster: FromContext(ctx), req: req, called: true}
+				return reconcile.Result{}, nil
+			}))
+
+			if _, err := r.Reconcile(context.Background(), tc.req); err != nil {
+				t.Errorf("\n%s\nr.Reconcile(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestEnqueueRequestsForCluster(t *testing.T) {
+	cases := map[string]struct {
+		reason  string
+		cluster string
+		want    []any
+	}{
+		"EncodeRequests": {
+			reason:  "Requests enqueued by the wrapped handler should be encoded with the cluster name.",
+			cluster: "remote",
+			want:    []any{reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "remote/ns", Name: "cool"}}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+			defer q.ShutDown()
+
+			h := EnqueueRequestsForCluster(tc.cluster, &handler.EnqueueRequestForObject{})
+			h.Create(event.CreateEvent{Object: &fake.Managed{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cool"}}}, q)
+
+			got := make([]any, 0, q.Len())
+			for q.Len() > 0 {
+				i, _ := q.Get()
+				got = append(got, i)
+				q.Done(i)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nh.Create(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestExternalTagger(t *testing.T) {
+	type want struct {
+		tags map[string]string
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		ctx    context.Context
+		want   want
+	}{
+		"NoCluster": {
+			reason: "No tags should be returned if the context identifies no cluster.",
+			ctx:    context.Background(),
+			want:   want{},
+		},
+		"Cluster": {
+			reason: "The cluster name should be returned if the context identifies a cluster.",
+			ctx:    WithCluster(context.Background(), "remote"),
+			want: want{
+				tags: map[string]string{resource.ExternalResourceTagKeyCluster: "remote"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ExternalTagger().ExternalTags(tc.ctx, &fake.Managed{})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nExternalTags(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.tags, got); diff != "" {
+				t.Errorf("\n%s\nExternalTags(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimiter
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// Error strings.
+const (
+	errFmtNotRegistered = "rate limiter %q is not registered"
+	errFmtAcquire       = "cannot acquire token from rate limiter %q"
+)
+
+// LabelLimiter is the name of the rate limiter a metric pertains to.
+const LabelLimiter = "limiter"
+
+// A Registry of named rate limiters that are shared by the controllers of a
+// provider, for example one limiter per external API endpoint or per cloud
+// account. Sharing a limiter keeps all controllers that call an API within
+// its documented limits. A Registry is a Prometheus collector; register it
+// with the controller-runtime metrics registry to expose how long callers
+// wait for tokens, and how often they give up.
+type Registry struct {
+	mu       sync.RWMutex
+	limiters map[string]*rate.Limiter
+
+	wait     *prometheus.HistogramVec
+	timeouts *prometheus.CounterVec
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		limiters: make(map[string]*rate.Limiter),
+		wait: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "crossplane_rate_limiter_wait_seconds",
+			Help:    "The time callers waited to acquire a token from a shared rate limiter.",
+			Buckets: prometheus.ExponentialBuckets(0.01, 2, 14),
+		}, []string{LabelLimiter}),
+		timeouts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "crossplane_rate_limiter_timeouts_total",
+			Help: "The number of times callers gave up acquiring a token from a shared rate limiter.",
+		}, []string{LabelLimiter}),
+	}
+}
+
+// Register a named rate limiter that allows the supplied number of events per
+// second, with the supplied burst. If a limiter with the supplied name is
+// already registered its limits are updated, so that callers that already
+// hold it observe the new limits. Register returns the registered limiter.
+func (r *Registry) Register(name string, rps float64, burst int) *rate.Limiter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if l, ok := r.limiters[name]; ok {
+		l.SetLimit(rate.Limit(rps))
+		l.SetBurst(burst)
+		return l
+	}
+	l := rate.NewLimiter(rate.Limit(rps), burst)
+	r.limiters[name] = l
+	return l
+}
+
+// Get the named rate limiter. Get returns false if no limiter is registered
+// with the supplied name.
+func (r *Registry) Get(name string) (*rate.Limiter, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	l, ok := r.limiters[name]
+	return l, ok
+}
+
+// Names returns the sorted names of all registered rate limiters.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.limiters))
+	for n := range r.limiters {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Acquire a token from the named rate limiter, waiting until one is available
+// or the supplied context is done. The returned error has code Throttled if
+// the context is done, or its deadline would pass, before a token is
+// available.
+func (r *Registry) Acquire(ctx context.Context, name string) error {
+	l, ok := r.Get(name)
+	if !ok {
+		return errors.Errorf(errFmtNotRegistered, name)
+	}
+
+	start := time.Now()
+	if err := l.Wait(ctx); err != nil {
+		r.timeouts.WithLabelValues(name).Inc()
+		return errors.WithCode(errors.Wrapf(err, errFmtAcquire, name), errors.CodeThrottled)
+	}
+	r.wait.WithLabelValues(name).Observe(time.Since(start).Seconds())
+	return nil
+}
+
+// AcquireWithin acquires a token from the named rate limiter like Acquire,
+// but waits no longer than the supplied timeout.
+func (r *Registry) AcquireWithin(ctx context.Context, name string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return r.Acquire(ctx, name)
+}
+
+// Describe sends the descriptors of all rate limiter metrics.
+func (r *Registry) Describe(ch chan<- *prometheus.Desc) {
+	r.wait.Describe(ch)
+	r.timeouts.Describe(ch)
+}
+
+// Collect sends the current values of all rate limiter metrics.
+func (r *Registry) Collect(ch chan<- prometheus.Metric) {
+	r.wait.Collect(ch)
+	r.timeouts.Collect(ch)
+}
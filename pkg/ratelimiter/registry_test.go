@@ -0,0 +1,129 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimiter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var _ prometheus.Collector = &Registry{}
+
+func TestRegistryAcquire(t *testing.T) {
+	type args struct {
+		register bool
+		rps      float64
+		burst    int
+		acquired int
+		timeout  time.Duration
+	}
+	type want struct {
+		err      error
+		code     errors.Code
+		waits    int
+		timeouts int
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotRegistered": {
+			reason: "We should return an error if the named limiter is not registered.",
+			args: args{
+				timeout: time.Second,
+			},
+			want: want{
+				err: errors.Errorf(errFmtNotRegistered, "cool"),
+			},
+		},
+		"Available": {
+			reason: "We should acquire a token without waiting if one is available.",
+			args: args{
+				register: true,
+				rps:      1,
+				burst:    2,
+				acquired: 1,
+				timeout:  time.Second,
+			},
+			want: want{
+				waits: 1,
+			},
+		},
+		"Timeout": {
+			reason: "We should return a throttled error if no token is available within the timeout.",
+			args: args{
+				register: true,
+				rps:      0.001,
+				burst:    1,
+				acquired: 1,
+				timeout:  10 * time.Millisecond,
+			},
+			want: want{
+				err:      errors.WithCode(errors.Wrapf(errors.New("rate: Wait(n=1) would exceed context deadline"), errFmtAcquire, "cool"), errors.CodeThrottled),
+				code:     errors.CodeThrottled,
+				timeouts: 1,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewRegistry()
+			if tc.args.register {
+				l := r.Register("cool", tc.args.rps, tc.args.burst)
+				if !l.AllowN(time.Now(), tc.args.acquired) {
+					t.Fatalf("AllowN(...): cannot acquire %d tokens", tc.args.acquired)
+				}
+			}
+
+			err := r.AcquireWithin(context.Background(), "cool", tc.args.timeout)
+			got := want{err: err, code: errors.CodeOf(err), waits: testutil.CollectAndCount(r.wait), timeouts: int(testutil.ToFloat64(r.timeouts.WithLabelValues("cool")))}
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors(), cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nr.AcquireWithin(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRegistryRegister(t *testing.T) {
+	r := NewRegistry()
+	l := r.Register("cool", 1, 1)
+	if got := r.Register("cool", 2, 3); got != l {
+		t.Errorf("r.Register(...): want the existing limiter to be returned")
+	}
+
+	got, ok := r.Get("cool")
+	if !ok {
+		t.Fatalf("r.Get(...): want the registered limiter")
+	}
+	if diff := cmp.Diff([]float64{2, 3}, []float64{float64(got.Limit()), float64(got.Burst())}); diff != "" {
+		t.Errorf("r.Register(...): -want limits, +got limits:\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"cool"}, r.Names()); diff != "" {
+		t.Errorf("r.Names(): -want, +got:\n%s", diff)
+	}
+}
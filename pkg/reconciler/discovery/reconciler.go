@@ -0,0 +1,304 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package discovery provides a reconciler that discovers external resources
+// that are not managed by any managed resource.
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const (
+	shortWait   = 30 * time.Second
+	timeout     = 2 * time.Minute
+	defaultPoll = 10 * time.Minute
+
+	errGetPC   = "cannot get ProviderConfig"
+	errListExt = "cannot list external resources"
+	errListMR  = "cannot list managed resources"
+	errReport  = "cannot report unmanaged external resources"
+
+	fmtUnmanaged = "Discovered %d external %s resource(s) that are not managed"
+)
+
+// Event reasons.
+const (
+	reasonDiscover event.Reason = "DiscoverExternalResources"
+	reasonFound    event.Reason = "FoundUnmanagedExternalResources"
+)
+
+// ControllerName returns the recommended name for controllers that use this
+// package to discover a particular kind of external resource.
+func ControllerName(kind string) string {
+	return "discovery/" + strings.ToLower(kind)
+}
+
+// An ExternalResource is a resource in an external system, as returned by a
+// Lister.
+type ExternalResource struct {
+	// ExternalName of the resource, i.e. the value a managed resource would
+	// use as its external name annotation to refer to it.
+	ExternalName string
+
+	// ForProvider parameters required to import the resource. They are
+	// written to the spec.forProvider field of generated manifests.
+	ForProvider map[string]any
+}
+
+// A Lister lists the external resources of a particular kind that are
+// visible using the supplied ProviderConfig.
+type Lister interface {
+	List(ctx context.Context, pc resource.ProviderConfig) ([]ExternalResource, error)
+}
+
+// A ListerFn is a function that satisfies the Lister interface.
+type ListerFn func(ctx context.Context, pc resource.ProviderConfig) ([]ExternalResource, error)
+
+// List the external resources visible using the supplied ProviderConfig.
+func (fn ListerFn) List(ctx context.Context, pc resource.ProviderConfig) ([]ExternalResource, error) {
+	return fn(ctx, pc)
+}
+
+// An Inventory of the external resources that are visible using a
+// ProviderConfig, but that are not managed by any managed resource.
+type Inventory struct {
+	// ProviderConfig used to discover the external resources.
+	ProviderConfig resource.ProviderConfig
+
+	// Kind of managed resource that would manage the external resources.
+	Kind schema.GroupVersionKind
+
+	// Unmanaged external resources, sorted by external name.
+	Unmanaged []ExternalResource
+}
+
+// A Reporter reports an Inventory of unmanaged external resources, for example
+// by exposing metrics or writing an inventory object.
+type Reporter interface {
+	Report(ctx context.Context, i Inventory) error
+}
+
+// A ReporterFn is a function that satisfies the Reporter interface.
+type ReporterFn func(ctx context.Context, i Inventory) error
+
+// Report the supplied Inventory.
+func (fn ReporterFn) Report(ctx context.Context, i Inventory) error {
+	return fn(ctx, i)
+}
+
+// Kinds of resources a Reconciler is concerned with.
+type Kinds struct {
+	// Config is the kind of ProviderConfig to reconcile.
+	Config schema.GroupVersionKind
+
+	// Managed is the kind of managed resource that manages the kind of
+	// external resource being discovered.
+	Managed schema.GroupVersionKind
+
+	// ManagedList is the list kind of Managed.
+	ManagedList schema.GroupVersionKind
+}
+
+// A Reconciler reconciles ProviderConfigs by listing the external resources
+// they can see and reporting those that are not managed by a managed
+// resource. Each controller must watch the ProviderConfig kind for which it is
+// responsible.
+type Reconciler struct {
+	client client.Client
+	lister Lister
+	kind   schema.GroupVersionKind
+
+	newConfig      func() resource.ProviderConfig
+	newManagedList func() resource.ManagedList
+
+	reporters []Reporter
+	poll      time.Duration
+
+	log    logging.Logger
+	record event.Recorder
+}
+
+// A ReconcilerOption configures a Reconciler.
+type ReconcilerOption func(*Reconciler)
+
+// WithLogger specifies how the Reconciler should log messages.
+func WithLogger(l logging.Logger) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.log = l
+	}
+}
+
+// WithRecorder specifies how the Reconciler should record events. An event is
+// recorded on each ProviderConfig that can see unmanaged external resources.
+func WithRecorder(er event.Recorder) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.record = er
+	}
+}
+
+// WithReporters specifies additional ways the Reconciler should report
+// unmanaged external resources. Reporters are called in order each time a
+// ProviderConfig is reconciled, even if there are no unmanaged resources.
+func WithReporters(rp ...Reporter) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.reporters = append(r.reporters, rp...)
+	}
+}
+
+// WithPollInterval specifies how often the Reconciler should discover
+// external resources. The default is ten minutes.
+func WithPollInterval(after time.Duration) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.poll = after
+	}
+}
+
+// NewReconciler returns a Reconciler that uses the supplied Lister to discover
+// external resources that are not managed by any managed resource.
+func NewReconciler(m manager.Manager, of Kinds, l Lister, o ...ReconcilerOption) *Reconciler {
+	nc := func() resource.ProviderConfig {
+		return resource.MustCreateObject(of.Config, m.GetScheme()).(resource.ProviderConfig)
+	}
+	nml := func() resource.ManagedList {
+		return resource.MustCreateObject(of.ManagedList, m.GetScheme()).(resource.ManagedList)
+	}
+
+	// Panic early if we've been asked to reconcile a resource kind that has not
+	// been registered with our controller manager's scheme.
+	_, _ = nc(), nml()
+
+	r := &Reconciler{
+		client: m.GetClient(),
+		lister: l,
+		kind:   of.Managed,
+
+		newConfig:      nc,
+		newManagedList: nml,
+
+		poll: defaultPoll,
+
+		log:    logging.NewNopLogger(),
+		record: event.NewNopRecorder(),
+	}
+
+	for _, ro := range o {
+		ro(r)
+	}
+
+	return r
+}
+
+// Reconcile a ProviderConfig by discovering the external resources it can see
+// and reporting those that are not managed by a managed resource that uses it.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("request", req)
+	log.Debug("Reconciling")
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	pc := r.newConfig()
+	if err := r.client.Get(ctx, req.NamespacedName, pc); err != nil {
+		log.Debug(errGetPC, "error", err)
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetPC)
+	}
+
+	if meta.WasDeleted(pc) {
+		// There's no point discovering resources using a ProviderConfig that
+		// is going away.
+		return reconcile.Result{Requeue: false}, nil
+	}
+
+	log = log.WithValues(
+		"uid", pc.GetUID(),
+		"version", pc.GetResourceVersion(),
+		"name", pc.GetName(),
+	)
+
+	ext, err := r.lister.List(ctx, pc)
+	if err != nil {
+		log.Debug(errListExt, "error", err)
+		r.record.Event(pc, event.Warning(reasonDiscover, errors.Wrap(err, errListExt)))
+		return reconcile.Result{RequeueAfter: shortWait}, nil
+	}
+
+	l := r.newManagedList()
+	if err := r.client.List(ctx, l); err != nil {
+		log.Debug(errListMR, "error", err)
+		r.record.Event(pc, event.Warning(reasonDiscover, errors.Wrap(err, errListMR)))
+		return reconcile.Result{RequeueAfter: shortWait}, nil
+	}
+
+	i := Inventory{ProviderConfig: pc, Kind: r.kind, Unmanaged: Unmanaged(pc.GetName(), ext, l.GetItems())}
+	log = log.WithValues("unmanaged", len(i.Unmanaged))
+
+	for _, rp := range r.reporters {
+		if err := rp.Report(ctx, i); err != nil {
+			log.Debug(errReport, "error", err)
+			r.record.Event(pc, event.Warning(reasonDiscover, errors.Wrap(err, errReport)))
+			return reconcile.Result{RequeueAfter: shortWait}, nil
+		}
+	}
+
+	if len(i.Unmanaged) > 0 {
+		log.Debug("Discovered unmanaged external resources")
+		r.record.Event(pc, event.Normal(reasonFound, fmt.Sprintf(fmtUnmanaged, len(i.Unmanaged), r.kind.Kind)))
+	}
+
+	return reconcile.Result{RequeueAfter: r.poll}, nil
+}
+
+// Unmanaged returns the supplied external resources that are not managed by
+// any of the supplied managed resources that use the named ProviderConfig.
+// An external resource is managed if a managed resource uses its external
+// name. The returned resources are sorted by external name.
+func Unmanaged(pc string, ext []ExternalResource, mrs []resource.Managed) []ExternalResource {
+	managed := make(map[string]bool, len(mrs))
+	for _, mg := range mrs {
+		ref := mg.GetProviderConfigReference()
+		if ref == nil || ref.Name != pc {
+			continue
+		}
+		if en := meta.GetExternalName(mg); en != "" {
+			managed[en] = true
+		}
+	}
+
+	out := make([]ExternalResource, 0, len(ext))
+	for _, er := range ext {
+		if !managed[er.ExternalName] {
+			out = append(out, er)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ExternalName < out[j].ExternalName })
+	return out
+}
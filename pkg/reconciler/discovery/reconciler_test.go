@@ -0,0 +1,229 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+// This can't live in fake, because it would cause an import cycle due to
+// GetItems returning resource.Managed.
+type ManagedList struct { //nolint:musttag // This is a fake implementation to be used in unit tests only.
+	client.ObjectList
+	Items []resource.Managed
+}
+
+func (l *ManagedList) GetObjectKind() schema.ObjectKind {
+	return schema.EmptyObjectKind
+}
+
+func (l *ManagedList) DeepCopyObject() runtime.Object {
+	out := &ManagedList{}
+	j, err := json.Marshal(l)
+	if err != nil {
+		panic(err)
+	}
+	_ = json.Unmarshal(j, out)
+	return out
+}
+
+func (l *ManagedList) GetItems() []resource.Managed {
+	return l.Items
+}
+
+func managed(pc, externalName string) *fake.Managed {
+	mg := &fake.Managed{ProviderConfigReferencer: fake.ProviderConfigReferencer{Ref: &xpv1.Reference{Name: pc}}}
+	meta.SetExternalName(mg, externalName)
+	return mg
+}
+
+func TestReconciler(t *testing.T) {
+	errBoom := errors.New("boom")
+	now := metav1.Now()
+
+	of := Kinds{
+		Config:      fake.GVK(&fake.ProviderConfig{}),
+		Managed:     fake.GVK(&fake.Managed{}),
+		ManagedList: fake.GVK(&ManagedList{}),
+	}
+	scheme := fake.SchemeWith(&fake.ProviderConfig{}, &ManagedList{})
+
+	getPC := func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+		obj.SetName("cool-pc")
+		return nil
+	}
+	list := test.MockListFn(func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+		obj.(*ManagedList).Items = []resource.Managed{managed("cool-pc", "a"), managed("other-pc", "b")}
+		return nil
+	})
+	lister := ListerFn(func(_ context.Context, _ resource.ProviderConfig) ([]ExternalResource, error) {
+		return []ExternalResource{{ExternalName: "c"}, {ExternalName: "b"}, {ExternalName: "a"}}, nil
+	})
+
+	type args struct {
+		m manager.Manager
+		l Lister
+		o []ReconcilerOption
+	}
+	type want struct {
+		result reconcile.Result
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"GetProviderConfigError": {
+			reason: "Errors getting a provider config should be returned.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+					Scheme: scheme,
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetPC),
+			},
+		},
+		"ProviderConfigNotFound": {
+			reason: "We should return without requeueing if the provider config no longer exists.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, ""))},
+					Scheme: scheme,
+				},
+			},
+			want: want{},
+		},
+		"ProviderConfigDeleted": {
+			reason: "We should not discover external resources using a provider config that was deleted.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+						obj.SetDeletionTimestamp(&now)
+						return nil
+					}},
+					Scheme: scheme,
+				},
+			},
+			want: want{},
+		},
+		"ListExternalResourcesError": {
+			reason: "We should requeue after a short wait if we can't list external resources.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{MockGet: getPC},
+					Scheme: scheme,
+				},
+				l: ListerFn(func(_ context.Context, _ resource.ProviderConfig) ([]ExternalResource, error) {
+					return nil, errBoom
+				}),
+			},
+			want: want{
+				result: reconcile.Result{RequeueAfter: shortWait},
+			},
+		},
+		"ListManagedResourcesError": {
+			reason: "We should requeue after a short wait if we can't list managed resources.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{MockGet: getPC, MockList: test.NewMockListFn(errBoom)},
+					Scheme: scheme,
+				},
+				l: lister,
+			},
+			want: want{
+				result: reconcile.Result{RequeueAfter: shortWait},
+			},
+		},
+		"ReportError": {
+			reason: "We should requeue after a short wait if we can't report unmanaged external resources.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{MockGet: getPC, MockList: list},
+					Scheme: scheme,
+				},
+				l: lister,
+				o: []ReconcilerOption{WithReporters(ReporterFn(func(_ context.Context, _ Inventory) error { return errBoom }))},
+			},
+			want: want{
+				result: reconcile.Result{RequeueAfter: shortWait},
+			},
+		},
+		"Success": {
+			reason: "We should report the unmanaged external resources and requeue after the poll interval.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{MockGet: getPC, MockList: list},
+					Scheme: scheme,
+				},
+				l: lister,
+				o: []ReconcilerOption{
+					WithPollInterval(time.Hour),
+					WithReporters(ReporterFn(func(_ context.Context, i Inventory) error {
+						want := []ExternalResource{{ExternalName: "b"}, {ExternalName: "c"}}
+						if diff := cmp.Diff(want, i.Unmanaged); diff != "" {
+							t.Errorf("Report(...): -want unmanaged, +got unmanaged:\n%s", diff)
+						}
+						if diff := cmp.Diff(of.Managed, i.Kind); diff != "" {
+							t.Errorf("Report(...): -want kind, +got kind:\n%s", diff)
+						}
+						return nil
+					})),
+				},
+			},
+			want: want{
+				result: reconcile.Result{RequeueAfter: time.Hour},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewReconciler(tc.args.m, of, tc.args.l, tc.args.o...)
+			got, err := r.Reconcile(context.Background(), reconcile.Request{})
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
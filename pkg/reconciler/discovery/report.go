@@ -0,0 +1,163 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/metrics"
+)
+
+const (
+	errMarshalManifest = "cannot marshal manifest"
+	errWriteManifest   = "cannot write manifest"
+)
+
+// LabelProviderConfig is the metric label for the name of the ProviderConfig
+// used to discover external resources.
+const LabelProviderConfig = "provider_config"
+
+// Metrics is a Reporter that exposes the number of unmanaged external
+// resources to Prometheus. Register it with the controller-runtime metrics
+// registry, i.e. metrics.Registry.MustRegister(m), and share it between
+// controllers.
+type Metrics struct {
+	unmanaged *prometheus.GaugeVec
+}
+
+// NewMetrics returns a new Metrics.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		unmanaged: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "crossplane_unmanaged_external_resources",
+			Help: "The number of discovered external resources that are not managed by a managed resource.",
+		}, []string{LabelProviderConfig, metrics.LabelGVK}),
+	}
+}
+
+// Report the number of unmanaged external resources in the supplied Inventory.
+func (m *Metrics) Report(_ context.Context, i Inventory) error {
+	m.unmanaged.With(prometheus.Labels{
+		LabelProviderConfig: i.ProviderConfig.GetName(),
+		metrics.LabelGVK:    i.Kind.String(),
+	}).Set(float64(len(i.Unmanaged)))
+	return nil
+}
+
+// Describe sends the descriptors of all metrics to the supplied channel.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.unmanaged.Describe(ch)
+}
+
+// Collect sends all metrics to the supplied channel.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.unmanaged.Collect(ch)
+}
+
+// invalidName matches runs of characters that may not appear in a Kubernetes
+// object name.
+var invalidName = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// maxName is the maximum length of a generated managed resource name. It
+// leaves room for a suffix in labels and other derived names.
+const maxName = 63
+
+// ManifestName returns a Kubernetes object name for a managed resource that
+// imports the external resource with the supplied external name. Names that
+// are not already valid are sanitised, and suffixed with a hash of the
+// external name so that distinct external names never collide.
+func ManifestName(externalName string) string {
+	n := strings.Trim(invalidName.ReplaceAllString(strings.ToLower(externalName), "-"), "-.")
+	if n == externalName && len(n) <= maxName {
+		return n
+	}
+	sum := sha256.Sum256([]byte(externalName))
+	suffix := hex.EncodeToString(sum[:])[:8]
+	if len(n) > maxName-len(suffix)-1 {
+		n = strings.TrimRight(n[:maxName-len(suffix)-1], "-.")
+	}
+	if n == "" {
+		return suffix
+	}
+	return n + "-" + suffix
+}
+
+// Manifest returns a manifest for a managed resource of the supplied kind that
+// imports the supplied external resource using the named ProviderConfig. The
+// managed resource uses the ObserveOnly management policy, so that applying it
+// cannot change or delete the external resource.
+func Manifest(of schema.GroupVersionKind, pc string, er ExternalResource) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]any{}}
+	u.SetGroupVersionKind(of)
+	u.SetName(ManifestName(er.ExternalName))
+	meta.SetExternalName(u, er.ExternalName)
+
+	spec := map[string]any{
+		"managementPolicy":  string(xpv1.ManagementObserveOnly),
+		"providerConfigRef": map[string]any{"name": pc},
+	}
+	if len(er.ForProvider) > 0 {
+		spec["forProvider"] = er.ForProvider
+	}
+	u.Object["spec"] = spec
+	return u
+}
+
+// A ManifestWriter is a Reporter that writes an importable manifest for each
+// unmanaged external resource, as a stream of YAML documents.
+type ManifestWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewManifestWriter returns a ManifestWriter that writes to the supplied
+// writer.
+func NewManifestWriter(w io.Writer) *ManifestWriter {
+	return &ManifestWriter{w: w}
+}
+
+// Report writes a manifest for each unmanaged external resource in the
+// supplied Inventory.
+func (mw *ManifestWriter) Report(_ context.Context, i Inventory) error {
+	mw.mu.Lock()
+	defer mw.mu.Unlock()
+
+	for _, er := range i.Unmanaged {
+		b, err := yaml.Marshal(Manifest(i.Kind, i.ProviderConfig.GetName(), er))
+		if err != nil {
+			return errors.Wrap(err, errMarshalManifest)
+		}
+		if _, err := mw.w.Write(append([]byte("---\n"), b...)); err != nil {
+			return errors.Wrap(err, errWriteManifest)
+		}
+	}
+	return nil
+}
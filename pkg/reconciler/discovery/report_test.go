@@ -0,0 +1,156 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var (
+	_ Reporter = &Metrics{}
+	_ Reporter = &ManifestWriter{}
+)
+
+func TestManifestName(t *testing.T) {
+	cases := map[string]struct {
+		reason       string
+		externalName string
+		want         string
+	}{
+		"Valid": {
+			reason:       "Valid names should be used as is.",
+			externalName: "cool-bucket",
+			want:         "cool-bucket",
+		},
+		"Invalid": {
+			reason:       "Invalid names should be sanitised and suffixed with a hash.",
+			externalName: "arn:aws:s3:::Cool_Bucket",
+			want:         "arn-aws-s3-cool-bucket-f5e2120a",
+		},
+		"OnlyInvalid": {
+			reason:       "Names with no valid characters should be replaced by a hash.",
+			externalName: "///",
+			want:         "732c4e97",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ManifestName(tc.externalName)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nManifestName(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestManifestWriter(t *testing.T) {
+	of := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Bucket"}
+	pc := &fake.ProviderConfig{ObjectMeta: metav1.ObjectMeta{Name: "cool-pc"}}
+
+	type want struct {
+		out string
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		i      Inventory
+		want   want
+	}{
+		"NoneUnmanaged": {
+			reason: "Nothing should be written if there are no unmanaged external resources.",
+			i:      Inventory{ProviderConfig: pc, Kind: of},
+			want:   want{},
+		},
+		"Unmanaged": {
+			reason: "An ObserveOnly manifest should be written for each unmanaged external resource.",
+			i: Inventory{ProviderConfig: pc, Kind: of, Unmanaged: []ExternalResource{
+				{ExternalName: "a"},
+				{ExternalName: "b", ForProvider: map[string]any{"region": "us-east-1"}},
+			}},
+			want: want{out: `---
+apiVersion: example.org/v1
+kind: Bucket
+metadata:
+  annotations:
+    crossplane.io/external-name: a
+  name: a
+spec:
+  managementPolicy: ObserveOnly
+  providerConfigRef:
+    name: cool-pc
+---
+apiVersion: example.org/v1
+kind: Bucket
+metadata:
+  annotations:
+    crossplane.io/external-name: b
+  name: b
+spec:
+  forProvider:
+    region: us-east-1
+  managementPolicy: ObserveOnly
+  providerConfigRef:
+    name: cool-pc
+`},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			b := &bytes.Buffer{}
+			err := NewManifestWriter(b).Report(context.Background(), tc.i)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nmw.Report(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.out, b.String()); diff != "" {
+				t.Errorf("\n%s\nmw.Report(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestManifest(t *testing.T) {
+	of := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Bucket"}
+	got := Manifest(of, "cool-pc", ExternalResource{ExternalName: "Cool"})
+	want := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "example.org/v1",
+		"kind":       "Bucket",
+		"metadata": map[string]any{
+			"name":        ManifestName("Cool"),
+			"annotations": map[string]any{"crossplane.io/external-name": "Cool"},
+		},
+		"spec": map[string]any{
+			"managementPolicy":  "ObserveOnly",
+			"providerConfigRef": map[string]any{"name": "cool-pc"},
+		},
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Manifest(...): -want, +got:\n%s", diff)
+	}
+}
@@ -18,10 +18,13 @@ package managed
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/retry"
@@ -29,8 +32,11 @@ import (
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	"github.com/crossplane/crossplane-runtime/pkg/validation/policy"
 )
 
 // Error strings.
@@ -40,8 +46,53 @@ const (
 	errUpdateManagedStatus       = "cannot update managed resource status"
 	errResolveReferences         = "cannot resolve references"
 	errUpdateCriticalAnnotations = "cannot update critical annotations"
+	errSetExternalName           = "cannot set external name"
+	errGetOwner                  = "cannot get controller of managed resource"
+	errGetExternalTags           = "cannot get external tags"
+	errSetExternalTags           = "cannot set external tags"
+	errResolveProviderConfig     = "cannot resolve default ProviderConfig"
+	errGetSecret                 = "cannot get connection secret"
+	errInvalidPolicies           = "invalid management and deletion policies"
+	errAdoptSecret               = "cannot adopt connection secret"
+	errGetClaim                  = "cannot get claim of composite resource"
 )
 
+// Condition types.
+const (
+	// TypeProviderConfigResolved indicates which ProviderConfig a managed
+	// resource that didn't reference one defaulted to, and why.
+	TypeProviderConfigResolved xpv1.ConditionType = "ProviderConfigResolved"
+)
+
+// ProviderConfigResolved returns a condition that indicates the managed
+// resource defaulted to the supplied ProviderConfig from the supplied source.
+func ProviderConfigResolved(s resource.ProviderConfigSource, name string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeProviderConfigResolved,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             xpv1.ConditionReason(s),
+		Message:            fmt.Sprintf("Using ProviderConfig %q", name),
+	}
+}
+
+// ValidatePolicies returns an Initializer that refuses to initialize a managed
+// resource whose management and deletion policies violate their documented
+// interactions, as determined by policy.Validate. Such managed resources are
+// usually rejected at admission, but may exist if they were created before
+// the rules were enforced, or if the provider's webhooks are not deployed.
+func ValidatePolicies(managementPoliciesEnabled bool) InitializerFn {
+	return func(_ context.Context, mg resource.Managed) error {
+		errs := policy.Validate(mg.GetManagementPolicy(), mg.GetDeletionPolicy(), managementPoliciesEnabled)
+		if len(errs) == 0 {
+			return nil
+		}
+		// The managed resource must be updated before it can be
+		// initialized, so there's no point retrying.
+		return errors.Terminal(errors.Wrap(errs.ToAggregate(), errInvalidPolicies))
+	}
+}
+
 // NameAsExternalName writes the name of the managed resource to
 // the external name annotation field in order to be used as name of
 // the external resource in provider.
@@ -61,6 +112,195 @@ func (a *NameAsExternalName) Initialize(ctx context.Context, mg resource.Managed
 	return errors.Wrap(a.client.Update(ctx, mg), errUpdateManaged)
 }
 
+// TemplatedExternalName writes an external name rendered from a template to
+// the external name annotation field in order to be used as name of the
+// external resource in provider. See meta.SetExternalNameFromTemplate.
+type TemplatedExternalName struct {
+	client      client.Client
+	template    string
+	constraints []meta.ExternalNameConstraint
+}
+
+// NewTemplatedExternalName returns a new TemplatedExternalName that renders
+// the supplied template, and requires the rendered name to satisfy the
+// supplied constraints.
+func NewTemplatedExternalName(c client.Client, tmpl string, ec ...meta.ExternalNameConstraint) *TemplatedExternalName {
+	return &TemplatedExternalName{client: c, template: tmpl, constraints: ec}
+}
+
+// Initialize the given managed resource.
+func (a *TemplatedExternalName) Initialize(ctx context.Context, mg resource.Managed) error {
+	if meta.GetExternalName(mg) != "" {
+		return nil
+	}
+	if err := meta.SetExternalNameFromTemplate(mg, a.template, a.constraints...); err != nil {
+		return errors.Wrap(err, errSetExternalName)
+	}
+	return errors.Wrap(a.client.Update(ctx, mg), errUpdateManaged)
+}
+
+// An OwnerMetadataPropagator propagates labels and annotations from the
+// controller of a managed resource - typically a composite resource - to the
+// managed resource, and keeps them in sync.
+type OwnerMetadataPropagator struct {
+	client      client.Client
+	labels      meta.PropagationPolicy
+	annotations meta.PropagationPolicy
+}
+
+// NewOwnerMetadataPropagator returns a new OwnerMetadataPropagator that
+// propagates the labels and annotations allowed by the supplied policies.
+func NewOwnerMetadataPropagator(c client.Client, labels, annotations meta.PropagationPolicy) *OwnerMetadataPropagator {
+	return &OwnerMetadataPropagator{client: c, labels: labels, annotations: annotations}
+}
+
+// Initialize the given managed resource.
+func (p *OwnerMetadataPropagator) Initialize(ctx context.Context, mg resource.Managed) error {
+	ref := metav1.GetControllerOf(mg)
+	if ref == nil {
+		return nil
+	}
+
+	owner := &unstructured.Unstructured{}
+	owner.SetAPIVersion(ref.APIVersion)
+	owner.SetKind(ref.Kind)
+	if err := p.client.Get(ctx, types.NamespacedName{Namespace: mg.GetNamespace(), Name: ref.Name}, owner); err != nil {
+		// The owner may have been deleted, in which case it will soon
+		// delete this managed resource.
+		return errors.Wrap(resource.IgnoreNotFound(err), errGetOwner)
+	}
+
+	labels := meta.PropagateLabels(owner, mg, p.labels)
+	annotations := meta.PropagateAnnotations(owner, mg, p.annotations)
+	if !labels && !annotations {
+		return nil
+	}
+	return errors.Wrap(p.client.Update(ctx, mg), errUpdateManaged)
+}
+
+// An OwnerLabeler labels a managed resource with the name and UID of its
+// controller - typically a composite resource - and with the name, namespace,
+// and UID of the claim of that composite resource, if any. The labels are kept
+// in sync as the managed resource is adopted by another composite resource or
+// the composite resource is bound to another claim, and removed when the
+// managed resource is orphaned.
+type OwnerLabeler struct{ client client.Client }
+
+// NewOwnerLabeler returns a new OwnerLabeler.
+func NewOwnerLabeler(c client.Client) *OwnerLabeler {
+	return &OwnerLabeler{client: c}
+}
+
+// Initialize the given managed resource.
+func (l *OwnerLabeler) Initialize(ctx context.Context, mg resource.Managed) error {
+	ref := metav1.GetControllerOf(mg)
+	if ref == nil {
+		xr := meta.RemoveCompositeLabels(mg)
+		claim := meta.RemoveClaimLabels(mg)
+		if !xr && !claim {
+			return nil
+		}
+		return errors.Wrap(l.client.Update(ctx, mg), errUpdateManaged)
+	}
+
+	xr := composite.New()
+	xr.SetAPIVersion(ref.APIVersion)
+	xr.SetKind(ref.Kind)
+	if err := l.client.Get(ctx, types.NamespacedName{Namespace: mg.GetNamespace(), Name: ref.Name}, xr); err != nil {
+		// The owner may have been deleted, in which case it will soon
+		// delete this managed resource.
+		return errors.Wrap(resource.IgnoreNotFound(err), errGetOwner)
+	}
+	changed := meta.SetCompositeLabels(mg, xr)
+
+	cr := xr.GetClaimReference()
+	switch {
+	case cr == nil || cr.Name == "":
+		changed = meta.RemoveClaimLabels(mg) || changed
+	case cr.UID != "":
+		changed = meta.SetClaimLabels(mg, &metav1.ObjectMeta{Namespace: cr.Namespace, Name: cr.Name, UID: cr.UID}) || changed
+	case !labelledWithClaim(mg, cr.Namespace, cr.Name):
+		// Claim references typically don't include the claim's UID. Only get
+		// the claim when its labels are out of date, since its UID is the
+		// only thing we need from it.
+		claim := &unstructured.Unstructured{}
+		claim.SetAPIVersion(cr.APIVersion)
+		claim.SetKind(cr.Kind)
+		if err := l.client.Get(ctx, types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name}, claim); err != nil {
+			return errors.Wrap(resource.IgnoreNotFound(err), errGetClaim)
+		}
+		changed = meta.SetClaimLabels(mg, claim) || changed
+	}
+
+	if !changed {
+		return nil
+	}
+	return errors.Wrap(l.client.Update(ctx, mg), errUpdateManaged)
+}
+
+// labelledWithClaim returns true if the supplied managed resource is labelled
+// with the UID of the claim with the supplied namespace and name.
+func labelledWithClaim(mg resource.Managed, namespace, name string) bool {
+	l := mg.GetLabels()
+	return l[meta.LabelKeyClaimNamespace] == namespace && l[meta.LabelKeyClaimName] == name && l[meta.LabelKeyClaimUID] != ""
+}
+
+// An ExternalTagsInitializer adds the tags returned by an ExternalTagger to a
+// string map at the supplied field path of a managed resource, for example
+// spec.forProvider.tags. Tags that are already set are not overwritten, so
+// users may override any tag.
+type ExternalTagsInitializer struct {
+	client    client.Client
+	tagger    resource.ExternalTagger
+	fieldPath string
+}
+
+// NewExternalTagsInitializer returns a new ExternalTagsInitializer.
+func NewExternalTagsInitializer(c client.Client, t resource.ExternalTagger, fieldPath string) *ExternalTagsInitializer {
+	return &ExternalTagsInitializer{client: c, tagger: t, fieldPath: fieldPath}
+}
+
+// Initialize the given managed resource.
+func (a *ExternalTagsInitializer) Initialize(ctx context.Context, mg resource.Managed) error {
+	tags, err := a.tagger.ExternalTags(ctx, mg)
+	if err != nil {
+		return errors.Wrap(err, errGetExternalTags)
+	}
+
+	pv, err := fieldpath.PaveObject(mg)
+	if err != nil {
+		return errors.Wrap(err, errSetExternalTags)
+	}
+	existing, err := pv.GetStringObject(a.fieldPath)
+	if resource.Ignore(fieldpath.IsNotFound, err) != nil {
+		return errors.Wrap(err, errSetExternalTags)
+	}
+
+	merged := make(map[string]any, len(existing)+len(tags))
+	for k, v := range existing {
+		merged[k] = v
+	}
+	changed := false
+	for k, v := range tags {
+		if _, ok := merged[k]; ok {
+			continue
+		}
+		merged[k] = v
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	if err := pv.SetValue(a.fieldPath, merged); err != nil {
+		return errors.Wrap(err, errSetExternalTags)
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(pv.UnstructuredContent(), mg); err != nil {
+		return errors.Wrap(err, errSetExternalTags)
+	}
+	return errors.Wrap(a.client.Update(ctx, mg), errUpdateManaged)
+}
+
 // DefaultProviderConfig fills the ProviderConfigRef with `default` if it's left
 // empty.
 // Deprecated: Use OpenAPI schema defaulting instead.
@@ -81,9 +321,44 @@ func (a *DefaultProviderConfig) Initialize(ctx context.Context, mg resource.Mana
 	return errors.Wrap(a.client.Update(ctx, mg), errUpdateManaged)
 }
 
+// ProviderConfigFallback sets the ProviderConfigRef of a managed resource that
+// doesn't reference a ProviderConfig to its namespace's default ProviderConfig,
+// or failing that to the cluster's default ProviderConfig. The source of the
+// ProviderConfig is recorded in the TypeProviderConfigResolved status
+// condition.
+type ProviderConfigFallback struct {
+	client   client.Client
+	resolver *resource.DefaultProviderConfigResolver
+}
+
+// NewProviderConfigFallback returns a new ProviderConfigFallback.
+func NewProviderConfigFallback(c client.Client, o ...resource.ProviderConfigResolverOption) *ProviderConfigFallback {
+	return &ProviderConfigFallback{client: c, resolver: resource.NewDefaultProviderConfigResolver(c, o...)}
+}
+
+// Initialize the given managed resource.
+func (a *ProviderConfigFallback) Initialize(ctx context.Context, mg resource.Managed) error {
+	ref, src, err := a.resolver.Resolve(ctx, mg)
+	if err != nil {
+		return errors.Wrap(err, errResolveProviderConfig)
+	}
+	if src == resource.ProviderConfigSourceResource {
+		return nil
+	}
+	mg.SetProviderConfigReference(&ref)
+	if err := a.client.Update(ctx, mg); err != nil {
+		return errors.Wrap(err, errUpdateManaged)
+	}
+
+	// The managed resource's status is updated later in the reconcile.
+	mg.SetConditions(ProviderConfigResolved(src, ref.Name))
+	return nil
+}
+
 // An APISecretPublisher publishes ConnectionDetails by submitting a Secret to a
 // Kubernetes API server.
 type APISecretPublisher struct {
+	client client.Client
 	secret resource.Applicator
 	typer  runtime.ObjectTyper
 }
@@ -93,6 +368,7 @@ func NewAPISecretPublisher(c client.Client, ot runtime.ObjectTyper) *APISecretPu
 	// NOTE(negz): We transparently inject an APIPatchingApplicator in order to maintain
 	// backward compatibility with the original API of this function.
 	return &APISecretPublisher{
+		client: c,
 		secret: resource.NewApplicatorWithRetry(resource.NewAPIPatchingApplicator(c),
 			resource.IsAPIErrorWrapped, nil),
 		typer: ot,
@@ -129,6 +405,34 @@ func (a *APISecretPublisher) PublishConnection(ctx context.Context, o resource.C
 	return true, nil
 }
 
+// AdoptConnection transfers control of the connection secret of the supplied
+// managed resource from the supplied previous UID, e.g. because the managed
+// resource was restored from a backup. Secrets that are not controlled by the
+// previous UID are left alone.
+func (a *APISecretPublisher) AdoptConnection(ctx context.Context, o resource.ConnectionSecretOwner, previous types.UID) error {
+	if o.GetWriteConnectionSecretToReference() == nil {
+		return nil
+	}
+
+	want := resource.ConnectionSecretFor(o, resource.MustGetKind(o, a.typer))
+	s := &corev1.Secret{}
+	if err := a.client.Get(ctx, types.NamespacedName{Namespace: want.GetNamespace(), Name: want.GetName()}, s); err != nil {
+		return errors.Wrap(resource.IgnoreNotFound(err), errGetSecret)
+	}
+	if c := metav1.GetControllerOf(s); c == nil || c.UID != previous {
+		return nil
+	}
+
+	refs := make([]metav1.OwnerReference, 0, len(s.GetOwnerReferences()))
+	for _, ref := range s.GetOwnerReferences() {
+		if ref.UID != previous {
+			refs = append(refs, ref)
+		}
+	}
+	s.SetOwnerReferences(append(refs, want.GetOwnerReferences()...))
+	return errors.Wrap(a.client.Update(ctx, s), errAdoptSecret)
+}
+
 // UnpublishConnection is no-op since PublishConnection only creates resources
 // that will be garbage collected by Kubernetes when the managed resource is
 // deleted.
@@ -171,7 +475,19 @@ func (a *APISimpleReferenceResolver) ResolveReferences(ctx context.Context, mg r
 		return nil
 	}
 
-	return errors.Wrap(a.client.Update(ctx, mg), errUpdateManaged)
+	// Updating the managed resource resets its status to that of the API
+	// server. Preserve any recorded resolution outcomes so that they're
+	// persisted when its status is next written.
+	rec, ok := mg.(resource.ReferenceResolutionRecorder)
+	if !ok {
+		return errors.Wrap(a.client.Update(ctx, mg), errUpdateManaged)
+	}
+	resolved := rec.GetResolvedReferences()
+	if err := a.client.Update(ctx, mg); err != nil {
+		return errors.Wrap(err, errUpdateManaged)
+	}
+	rec.SetResolvedReferences(resolved)
+	return nil
 }
 
 // A RetryingCriticalAnnotationUpdater is a CriticalAnnotationUpdater that
@@ -194,7 +510,7 @@ func NewRetryingCriticalAnnotationUpdater(c client.Client) *RetryingCriticalAnno
 func (u *RetryingCriticalAnnotationUpdater) UpdateCriticalAnnotations(ctx context.Context, o client.Object) error {
 	a := o.GetAnnotations()
 	err := retry.OnError(retry.DefaultRetry, resource.IsAPIError, func() error {
-		nn := types.NamespacedName{Name: o.GetName()}
+		nn := types.NamespacedName{Namespace: o.GetNamespace(), Name: o.GetName()}
 		if err := u.client.Get(ctx, nn, o); err != nil {
 			return err
 		}
@@ -21,8 +21,12 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
@@ -30,13 +34,108 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/crossplane/crossplane-runtime/pkg/validation/policy"
 )
 
 var (
 	_ Initializer = &NameAsExternalName{}
+	_ Initializer = &TemplatedExternalName{}
+	_ Initializer = &OwnerMetadataPropagator{}
+	_ Initializer = &OwnerLabeler{}
+	_ Initializer = &ExternalTagsInitializer{}
+
+	_ ConnectionAdopter = &APISecretPublisher{}
+	_ ConnectionAdopter = PublisherChain{}
 )
 
+func TestConditionalInitializer(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		mg resource.Managed
+		p  []InitializerPredicate
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"PredicatesTrue": {
+			reason: "The initializer should run if all predicates are true.",
+			args: args{
+				mg: &fake.Managed{},
+				p:  []InitializerPredicate{NoExternalName()},
+			},
+			want: errBoom,
+		},
+		"PredicateFalse": {
+			reason: "The initializer should not run if any predicate is false.",
+			args: args{
+				mg: func() resource.Managed {
+					mg := &fake.Managed{}
+					meta.SetExternalName(mg, "cool")
+					return mg
+				}(),
+				p: []InitializerPredicate{NoExternalName()},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			i := NewConditionalInitializer(InitializerFn(func(_ context.Context, _ resource.Managed) error { return errBoom }), tc.args.p...)
+			err := i.Initialize(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ni.Initialize(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidatePolicies(t *testing.T) {
+	type args struct {
+		enabled bool
+		mg      resource.Managed
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"Valid": {
+			reason: "Managed resources with valid policies should be initialized.",
+			args: args{
+				enabled: true,
+				mg: &fake.Managed{
+					Manageable: fake.Manageable{Policy: xpv1.ManagementObserveOnly},
+					Orphanable: fake.Orphanable{Policy: xpv1.DeletionOrphan},
+				},
+			},
+		},
+		"Invalid": {
+			reason: "Managed resources with conflicting policies should not be initialized.",
+			args: args{
+				enabled: true,
+				mg:      &fake.Managed{Manageable: fake.Manageable{Policy: xpv1.ManagementObserveOnly}},
+			},
+			want: errors.Terminal(errors.Wrap(policy.Validate(xpv1.ManagementObserveOnly, "", true).ToAggregate(), errInvalidPolicies)),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidatePolicies(tc.args.enabled).Initialize(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nInitialize(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestNameAsExternalName(t *testing.T) {
 	type args struct {
 		ctx context.Context
@@ -117,6 +216,486 @@ func TestNameAsExternalName(t *testing.T) {
 	}
 }
 
+func TestTemplatedExternalName(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+
+	type want struct {
+		err error
+		mg  resource.Managed
+	}
+
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		client client.Client
+		tmpl   string
+		args   args
+		want   want
+	}{
+		"RenderError": {
+			tmpl: "",
+			args: args{
+				ctx: context.Background(),
+				mg:  &fake.Managed{ObjectMeta: metav1.ObjectMeta{Name: "cool"}},
+			},
+			want: want{
+				err: errors.Wrap(errors.New("external name template rendered an empty name"), errSetExternalName),
+				mg:  &fake.Managed{ObjectMeta: metav1.ObjectMeta{Name: "cool"}},
+			},
+		},
+		"UpdateManagedError": {
+			client: &test.MockClient{MockUpdate: test.NewMockUpdateFn(errBoom)},
+			tmpl:   "prefix-{{ .Name }}",
+			args: args{
+				ctx: context.Background(),
+				mg:  &fake.Managed{ObjectMeta: metav1.ObjectMeta{Name: "cool"}},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errUpdateManaged),
+				mg: &fake.Managed{ObjectMeta: metav1.ObjectMeta{
+					Name:        "cool",
+					Annotations: map[string]string{meta.AnnotationKeyExternalName: "prefix-cool"},
+				}},
+			},
+		},
+		"UpdateSuccessful": {
+			client: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+			tmpl:   "prefix-{{ .Name }}",
+			args: args{
+				ctx: context.Background(),
+				mg:  &fake.Managed{ObjectMeta: metav1.ObjectMeta{Name: "cool"}},
+			},
+			want: want{
+				mg: &fake.Managed{ObjectMeta: metav1.ObjectMeta{
+					Name:        "cool",
+					Annotations: map[string]string{meta.AnnotationKeyExternalName: "prefix-cool"},
+				}},
+			},
+		},
+		"UpdateNotNeeded": {
+			tmpl: "prefix-{{ .Name }}",
+			args: args{
+				ctx: context.Background(),
+				mg: &fake.Managed{ObjectMeta: metav1.ObjectMeta{
+					Name:        "cool",
+					Annotations: map[string]string{meta.AnnotationKeyExternalName: "some-name"},
+				}},
+			},
+			want: want{
+				mg: &fake.Managed{ObjectMeta: metav1.ObjectMeta{
+					Name:        "cool",
+					Annotations: map[string]string{meta.AnnotationKeyExternalName: "some-name"},
+				}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			api := NewTemplatedExternalName(tc.client, tc.tmpl)
+			err := api.Initialize(tc.args.ctx, tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("api.Initialize(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("api.Initialize(...) Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOwnerMetadataPropagator(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+
+	type want struct {
+		err error
+		mg  resource.Managed
+	}
+
+	errBoom := errors.New("boom")
+	owned := func(labels map[string]string) *fake.Managed {
+		mg := &fake.Managed{ObjectMeta: metav1.ObjectMeta{Name: "cool", Labels: labels}}
+		meta.AddControllerReference(mg, metav1.OwnerReference{APIVersion: "example.org/v1", Kind: "XCool", Name: "xcool", Controller: &[]bool{true}[0]})
+		return mg
+	}
+	withOwnerLabels := test.NewMockGetFn(nil, func(obj client.Object) error {
+		obj.SetLabels(map[string]string{"example.org/team": "platform"})
+		return nil
+	})
+	policy, err := meta.NewPropagationPolicy([]string{"example.org/*"}, nil)
+	if err != nil {
+		t.Fatalf("meta.NewPropagationPolicy(...): %s", err)
+	}
+
+	cases := map[string]struct {
+		reason string
+		client client.Client
+		args   args
+		want   want
+	}{
+		"NoController": {
+			reason: "Nothing should be propagated to a managed resource without a controller.",
+			args: args{
+				ctx: context.Background(),
+				mg:  &fake.Managed{ObjectMeta: metav1.ObjectMeta{Name: "cool"}},
+			},
+			want: want{
+				mg: &fake.Managed{ObjectMeta: metav1.ObjectMeta{Name: "cool"}},
+			},
+		},
+		"GetOwnerError": {
+			reason: "Errors getting the controller should be returned.",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			args: args{
+				ctx: context.Background(),
+				mg:  owned(nil),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetOwner),
+				mg:  owned(nil),
+			},
+		},
+		"UpdateManagedError": {
+			reason: "Errors updating the managed resource should be returned.",
+			client: &test.MockClient{MockGet: withOwnerLabels, MockUpdate: test.NewMockUpdateFn(errBoom)},
+			args: args{
+				ctx: context.Background(),
+				mg:  owned(nil),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errUpdateManaged),
+				mg:  owned(map[string]string{"example.org/team": "platform"}),
+			},
+		},
+		"UpdateSuccessful": {
+			reason: "Labels should be propagated from the controller.",
+			client: &test.MockClient{MockGet: withOwnerLabels, MockUpdate: test.NewMockUpdateFn(nil)},
+			args: args{
+				ctx: context.Background(),
+				mg:  owned(map[string]string{"example.org/team": "old"}),
+			},
+			want: want{
+				mg: owned(map[string]string{"example.org/team": "platform"}),
+			},
+		},
+		"UpdateNotNeeded": {
+			reason: "The managed resource should not be updated if its labels are in sync.",
+			client: &test.MockClient{MockGet: withOwnerLabels},
+			args: args{
+				ctx: context.Background(),
+				mg:  owned(map[string]string{"example.org/team": "platform"}),
+			},
+			want: want{
+				mg: owned(map[string]string{"example.org/team": "platform"}),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := NewOwnerMetadataPropagator(tc.client, policy, meta.PropagationPolicy{})
+			err := p.Initialize(tc.args.ctx, tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\np.Initialize(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg); diff != "" {
+				t.Errorf("\n%s\np.Initialize(...) Managed: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestOwnerLabeler(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+
+	type want struct {
+		err error
+		mg  resource.Managed
+	}
+
+	errBoom := errors.New("boom")
+	unowned := func(labels map[string]string) *fake.Managed {
+		return &fake.Managed{ObjectMeta: metav1.ObjectMeta{Name: "cool", Labels: labels}}
+	}
+	owned := func(labels map[string]string) *fake.Managed {
+		mg := unowned(labels)
+		meta.AddControllerReference(mg, metav1.OwnerReference{APIVersion: "example.org/v1", Kind: "XCool", Name: "xcool", UID: "xr-uid", Controller: &[]bool{true}[0]})
+		return mg
+	}
+	labelled := func() map[string]string {
+		return map[string]string{
+			meta.LabelKeyComposite:      "xcool",
+			meta.LabelKeyCompositeUID:   "xr-uid",
+			meta.LabelKeyClaimName:      "cool-claim",
+			meta.LabelKeyClaimNamespace: "cool-ns",
+			meta.LabelKeyClaimUID:       "claim-uid",
+		}
+	}
+	// getOwners gets a composite resource with the supplied claim reference,
+	// and a claim, or returns the supplied error getting the claim.
+	getOwners := func(claimRef map[string]any, claimErr error) test.MockGetFn {
+		return func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			switch obj.GetObjectKind().GroupVersionKind().Kind {
+			case "XCool":
+				xr := obj.(*composite.Unstructured)
+				xr.SetName("xcool")
+				xr.SetUID("xr-uid")
+				if claimRef != nil {
+					xr.Object["spec"] = map[string]any{"claimRef": claimRef}
+				}
+			case "Cool":
+				if claimErr != nil {
+					return claimErr
+				}
+				obj.SetName("cool-claim")
+				obj.SetNamespace("cool-ns")
+				obj.SetUID("claim-uid")
+			}
+			return nil
+		}
+	}
+	claimRef := map[string]any{"apiVersion": "example.org/v1", "kind": "Cool", "namespace": "cool-ns", "name": "cool-claim"}
+
+	cases := map[string]struct {
+		reason string
+		client client.Client
+		args   args
+		want   want
+	}{
+		"NoController": {
+			reason: "A managed resource without a controller or owner labels should not be updated.",
+			args: args{
+				ctx: context.Background(),
+				mg:  unowned(nil),
+			},
+			want: want{
+				mg: unowned(nil),
+			},
+		},
+		"Orphaned": {
+			reason: "The owner labels of a managed resource without a controller should be removed.",
+			client: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+			args: args{
+				ctx: context.Background(),
+				mg:  unowned(map[string]string{meta.LabelKeyComposite: "xcool", meta.LabelKeyCompositeUID: "xr-uid", "other": "label"}),
+			},
+			want: want{
+				mg: unowned(map[string]string{"other": "label"}),
+			},
+		},
+		"GetOwnerError": {
+			reason: "Errors getting the controller should be returned.",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			args: args{
+				ctx: context.Background(),
+				mg:  owned(nil),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetOwner),
+				mg:  owned(nil),
+			},
+		},
+		"GetClaimError": {
+			reason: "Errors getting the claim of the controller should be returned.",
+			client: &test.MockClient{MockGet: getOwners(claimRef, errBoom)},
+			args: args{
+				ctx: context.Background(),
+				mg:  owned(nil),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetClaim),
+				mg:  owned(map[string]string{meta.LabelKeyComposite: "xcool", meta.LabelKeyCompositeUID: "xr-uid"}),
+			},
+		},
+		"Claimed": {
+			reason: "A managed resource should be labelled with its composite resource and claim.",
+			client: &test.MockClient{MockGet: getOwners(claimRef, nil), MockUpdate: test.NewMockUpdateFn(nil)},
+			args: args{
+				ctx: context.Background(),
+				mg:  owned(nil),
+			},
+			want: want{
+				mg: owned(labelled()),
+			},
+		},
+		"ClaimReferenceWithUID": {
+			reason: "The claim should not be read if its reference includes its UID.",
+			client: &test.MockClient{
+				MockGet:    getOwners(map[string]any{"apiVersion": "example.org/v1", "kind": "Cool", "namespace": "cool-ns", "name": "cool-claim", "uid": "claim-uid"}, errBoom),
+				MockUpdate: test.NewMockUpdateFn(nil),
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  owned(nil),
+			},
+			want: want{
+				mg: owned(labelled()),
+			},
+		},
+		"Adopted": {
+			reason: "The labels of a managed resource adopted by another composite resource should be updated.",
+			client: &test.MockClient{MockGet: getOwners(claimRef, nil), MockUpdate: test.NewMockUpdateFn(nil)},
+			args: args{
+				ctx: context.Background(),
+				mg:  owned(map[string]string{meta.LabelKeyComposite: "xold", meta.LabelKeyCompositeUID: "old-uid", meta.LabelKeyClaimName: "old-claim", meta.LabelKeyClaimNamespace: "cool-ns", meta.LabelKeyClaimUID: "old-claim-uid"}),
+			},
+			want: want{
+				mg: owned(labelled()),
+			},
+		},
+		"Unclaimed": {
+			reason: "The claim labels of a managed resource whose composite resource has no claim should be removed.",
+			client: &test.MockClient{MockGet: getOwners(nil, nil), MockUpdate: test.NewMockUpdateFn(nil)},
+			args: args{
+				ctx: context.Background(),
+				mg:  owned(labelled()),
+			},
+			want: want{
+				mg: owned(map[string]string{meta.LabelKeyComposite: "xcool", meta.LabelKeyCompositeUID: "xr-uid"}),
+			},
+		},
+		"UpdateManagedError": {
+			reason: "Errors updating the managed resource should be returned.",
+			client: &test.MockClient{MockGet: getOwners(claimRef, nil), MockUpdate: test.NewMockUpdateFn(errBoom)},
+			args: args{
+				ctx: context.Background(),
+				mg:  owned(nil),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errUpdateManaged),
+				mg:  owned(labelled()),
+			},
+		},
+		"UpdateNotNeeded": {
+			reason: "The managed resource should not be updated, nor its claim read, if its labels are in sync.",
+			client: &test.MockClient{MockGet: getOwners(claimRef, errBoom)},
+			args: args{
+				ctx: context.Background(),
+				mg:  owned(labelled()),
+			},
+			want: want{
+				mg: owned(labelled()),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			l := NewOwnerLabeler(tc.client)
+			err := l.Initialize(tc.args.ctx, tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nl.Initialize(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg); diff != "" {
+				t.Errorf("\n%s\nl.Initialize(...) Managed: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestExternalTagsInitializer(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+
+	type want struct {
+		err error
+		mg  resource.Managed
+	}
+
+	errBoom := errors.New("boom")
+	tagger := resource.StaticTagger(map[string]string{"org": "example", "env": "dev"})
+
+	// We use labels as the tags field, because the fake managed resource
+	// doesn't have a spec. Its object metadata isn't tagged, so the labels
+	// are at field path 'objectMeta.labels'.
+	labelled := func(l map[string]string) *fake.Managed {
+		return &fake.Managed{ObjectMeta: metav1.ObjectMeta{Name: "cool", Labels: l}}
+	}
+
+	cases := map[string]struct {
+		reason string
+		client client.Client
+		tagger resource.ExternalTagger
+		args   args
+		want   want
+	}{
+		"TaggerError": {
+			reason: "Errors getting tags should be returned.",
+			tagger: resource.ExternalTaggerFn(func(_ context.Context, _ resource.Managed) (map[string]string, error) {
+				return nil, errBoom
+			}),
+			args: args{
+				ctx: context.Background(),
+				mg:  labelled(nil),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetExternalTags),
+				mg:  labelled(nil),
+			},
+		},
+		"UpdateManagedError": {
+			reason: "Errors updating the managed resource should be returned.",
+			client: &test.MockClient{MockUpdate: test.NewMockUpdateFn(errBoom)},
+			tagger: tagger,
+			args: args{
+				ctx: context.Background(),
+				mg:  labelled(nil),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errUpdateManaged),
+				mg:  labelled(map[string]string{"org": "example", "env": "dev"}),
+			},
+		},
+		"UpdateSuccessful": {
+			reason: "Missing tags should be added without overriding existing tags.",
+			client: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+			tagger: tagger,
+			args: args{
+				ctx: context.Background(),
+				mg:  labelled(map[string]string{"env": "prod"}),
+			},
+			want: want{
+				mg: labelled(map[string]string{"org": "example", "env": "prod"}),
+			},
+		},
+		"UpdateNotNeeded": {
+			reason: "The managed resource should not be updated if all tags are set.",
+			tagger: tagger,
+			args: args{
+				ctx: context.Background(),
+				mg:  labelled(map[string]string{"org": "other", "env": "prod"}),
+			},
+			want: want{
+				mg: labelled(map[string]string{"org": "other", "env": "prod"}),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			i := NewExternalTagsInitializer(tc.client, tc.tagger, "objectMeta.labels")
+			err := i.Initialize(tc.args.ctx, tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ni.Initialize(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg); diff != "" {
+				t.Errorf("\n%s\ni.Initialize(...) Managed: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestDefaultProviderConfig(t *testing.T) {
 	type args struct {
 		ctx context.Context
@@ -183,6 +762,91 @@ func TestDefaultProviderConfig(t *testing.T) {
 	}
 }
 
+func TestProviderConfigFallback(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		client client.Client
+		mg     resource.Managed
+	}
+
+	type want struct {
+		err error
+		mg  resource.Managed
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Referenced": {
+			reason: "A managed resource that references a ProviderConfig should not be updated.",
+			args: args{
+				mg: &fake.Managed{ProviderConfigReferencer: fake.ProviderConfigReferencer{Ref: &xpv1.Reference{Name: "mine"}}},
+			},
+			want: want{
+				mg: &fake.Managed{ProviderConfigReferencer: fake.ProviderConfigReferencer{Ref: &xpv1.Reference{Name: "mine"}}},
+			},
+		},
+		"ResolveError": {
+			reason: "Errors resolving the default ProviderConfig should be returned.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				mg:     &fake.Managed{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{meta.LabelKeyClaimNamespace: "cool"}}},
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrap(errBoom, "cannot get namespace to determine its default ProviderConfig"), errResolveProviderConfig),
+				mg:  &fake.Managed{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{meta.LabelKeyClaimNamespace: "cool"}}},
+			},
+		},
+		"UpdateError": {
+			reason: "Errors updating the managed resource should be returned.",
+			args: args{
+				client: &test.MockClient{MockUpdate: test.NewMockUpdateFn(errBoom)},
+				mg:     &fake.Managed{},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errUpdateManaged),
+				mg:  &fake.Managed{ProviderConfigReferencer: fake.ProviderConfigReferencer{Ref: &xpv1.Reference{Name: resource.DefaultProviderConfigName}}},
+			},
+		},
+		"NamespaceDefault": {
+			reason: "The namespace's default ProviderConfig should be used and recorded in status.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						obj.SetAnnotations(map[string]string{meta.AnnotationKeyDefaultProviderConfig: "team"})
+						return nil
+					}),
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				mg: &fake.Managed{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{meta.LabelKeyClaimNamespace: "cool"}}},
+			},
+			want: want{
+				mg: &fake.Managed{
+					ObjectMeta:               metav1.ObjectMeta{Labels: map[string]string{meta.LabelKeyClaimNamespace: "cool"}},
+					ProviderConfigReferencer: fake.ProviderConfigReferencer{Ref: &xpv1.Reference{Name: "team"}},
+					ConditionedStatus:        xpv1.ConditionedStatus{Conditions: []xpv1.Condition{ProviderConfigResolved(resource.ProviderConfigSourceNamespaceDefault, "team")}},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			api := NewProviderConfigFallback(tc.args.client)
+			err := api.Initialize(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\napi.Initialize(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\napi.Initialize(...) Managed: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestAPISecretPublisher(t *testing.T) {
 	errBoom := errors.New("boom")
 
@@ -288,7 +952,7 @@ func TestAPISecretPublisher(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			a := &APISecretPublisher{tc.fields.secret, tc.fields.typer}
+			a := &APISecretPublisher{secret: tc.fields.secret, typer: tc.fields.typer}
 			got, gotErr := a.PublishConnection(tc.args.ctx, tc.args.mg, tc.args.c)
 			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nPublish(...): -wantErr, +gotErr:\n%s", tc.reason, diff)
@@ -300,6 +964,112 @@ func TestAPISecretPublisher(t *testing.T) {
 	}
 }
 
+func TestAPISecretPublisherAdoptConnection(t *testing.T) {
+	errBoom := errors.New("boom")
+	controller := true
+
+	mg := &fake.Managed{
+		ObjectMeta: metav1.ObjectMeta{Name: "cool", UID: "new-uid"},
+		ConnectionSecretWriterTo: fake.ConnectionSecretWriterTo{Ref: &xpv1.SecretReference{
+			Namespace: "coolnamespace",
+			Name:      "coolsecret",
+		}},
+	}
+	gvk := fake.GVK(mg)
+
+	// controlledBy returns a connection secret controlled by the supplied UID.
+	controlledBy := func(uid types.UID) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "coolnamespace",
+			Name:      "coolsecret",
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "v1", Kind: "ConfigMap", Name: "other", UID: "other-uid"},
+				{APIVersion: gvk.GroupVersion().String(), Kind: gvk.Kind, Name: "cool", UID: uid, Controller: &controller, BlockOwnerDeletion: &controller},
+			},
+		}}
+	}
+
+	type args struct {
+		c  client.Client
+		mg resource.Managed
+	}
+
+	type want struct {
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ResourceDoesNotPublishSecret": {
+			reason: "A managed resource with a nil GetWriteConnectionSecretToReference should not adopt a secret",
+			args: args{
+				mg: &fake.Managed{},
+			},
+		},
+		"SecretNotFound": {
+			reason: "There is nothing to adopt if the connection secret doesn't exist",
+			args: args{
+				c:  &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, ""))},
+				mg: mg,
+			},
+		},
+		"GetError": {
+			reason: "An error getting the connection secret should be returned",
+			args: args{
+				c:  &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				mg: mg,
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetSecret),
+			},
+		},
+		"NotControlledByPrevious": {
+			reason: "A connection secret that isn't controlled by the previous UID should not be adopted",
+			args: args{
+				c: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+						controlledBy("someone-else").DeepCopyInto(o.(*corev1.Secret))
+						return nil
+					}),
+					MockUpdate: test.NewMockUpdateFn(errBoom),
+				},
+				mg: mg,
+			},
+		},
+		"Adopted": {
+			reason: "A connection secret controlled by the previous UID should be controlled by the managed resource",
+			args: args{
+				c: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+						controlledBy("old-uid").DeepCopyInto(o.(*corev1.Secret))
+						return nil
+					}),
+					MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+						if diff := cmp.Diff(controlledBy("new-uid"), o); diff != "" {
+							t.Errorf("Update(...): -want, +got:\n%s", diff)
+						}
+						return nil
+					}),
+				},
+				mg: mg,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			a := NewAPISecretPublisher(tc.args.c, fake.SchemeWith(&fake.Managed{}))
+			err := a.AdoptConnection(context.Background(), tc.args.mg, "old-uid")
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nAdoptConnection(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 type mockSimpleReferencer struct {
 	resource.Managed
 
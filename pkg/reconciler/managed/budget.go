@@ -0,0 +1,71 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"context"
+	"time"
+)
+
+// A Phase of a reconcile.
+type Phase string
+
+// Phases of a reconcile.
+const (
+	// PhaseResolve resolves the managed resource's references.
+	PhaseResolve Phase = "Resolve"
+
+	// PhaseConnect connects to the external system.
+	PhaseConnect Phase = "Connect"
+
+	// PhaseObserve observes the external resource.
+	PhaseObserve Phase = "Observe"
+
+	// PhaseMutate creates, updates, or deletes the external resource.
+	PhaseMutate Phase = "Mutate"
+
+	// PhasePublish publishes or unpublishes connection details.
+	PhasePublish Phase = "Publish"
+)
+
+// A Budget splits the timeout of a reconcile among its phases. Each phase is
+// allotted a share of the timeout between 0 and 1. A phase that exceeds its
+// share is cancelled, so that one slow phase can't consume the entire timeout
+// and leave no time to persist the managed resource's status. Phases without
+// a share are bound only by the overall timeout.
+type Budget map[Phase]float64
+
+// DefaultBudget is a Budget that favours the phases that call the external
+// system.
+var DefaultBudget = Budget{
+	PhaseResolve: 0.1,
+	PhaseConnect: 0.15,
+	PhaseObserve: 0.25,
+	PhaseMutate:  0.4,
+	PhasePublish: 0.1,
+}
+
+// Context returns a copy of the supplied context whose deadline is the
+// supplied phase's share of the supplied timeout from now, or the supplied
+// context's deadline if that is sooner.
+func (b Budget) Context(ctx context.Context, p Phase, timeout time.Duration) (context.Context, context.CancelFunc) {
+	share, ok := b[p]
+	if !ok || share <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, time.Duration(share*float64(timeout)))
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBudgetContext(t *testing.T) {
+	type args struct {
+		b       Budget
+		p       Phase
+		timeout time.Duration
+	}
+	type want struct {
+		deadline bool
+		max      time.Duration
+	}
+
+	cases := map[string]struct {
+		reason string
+		ctx    func() (context.Context, context.CancelFunc)
+		args   args
+		want   want
+	}{
+		"NilBudget": {
+			reason: "A nil budget should not add a deadline.",
+			ctx:    func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+			args: args{
+				p:       PhaseObserve,
+				timeout: time.Minute,
+			},
+			want: want{deadline: false},
+		},
+		"PhaseShare": {
+			reason: "A phase's deadline should be its share of the timeout.",
+			ctx:    func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+			args: args{
+				b:       Budget{PhaseObserve: 0.25},
+				p:       PhaseObserve,
+				timeout: time.Minute,
+			},
+			want: want{deadline: true, max: 15 * time.Second},
+		},
+		"ParentDeadlineSooner": {
+			reason: "A phase's deadline should not exceed the deadline of the supplied context.",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), time.Second)
+			},
+			args: args{
+				b:       Budget{PhaseMutate: 0.5},
+				p:       PhaseMutate,
+				timeout: time.Minute,
+			},
+			want: want{deadline: true, max: time.Second},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			parent, parentCancel := tc.ctx()
+			defer parentCancel()
+
+			ctx, cancel := tc.args.b.Context(parent, tc.args.p, tc.args.timeout)
+			defer cancel()
+
+			deadline, ok := ctx.Deadline()
+			if ok != tc.want.deadline {
+				t.Fatalf("\n%s\nb.Context(...): want deadline %t, got %t", tc.reason, tc.want.deadline, ok)
+			}
+			if ok && time.Until(deadline) > tc.want.max {
+				t.Errorf("\n%s\nb.Context(...): want deadline within %s, got %s", tc.reason, tc.want.max, time.Until(deadline))
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const errFmtExternalNameClaimed = "external name %q is already declared by %s"
+
+// An ExternalNameConflictDetector is an Initializer that detects other managed
+// resources of the same kind and provider config that declare the same
+// external name, and would thus fight over one external resource. The oldest
+// such managed resource may proceed. The others report the conflict using the
+// ExternalNameUnique condition and are not reconciled until it is resolved.
+// The supplied client must be backed by a cache with the index added by
+// resource.AddExternalNameIndex.
+type ExternalNameConflictDetector struct {
+	client client.Reader
+	list   resource.ManagedList
+}
+
+// NewExternalNameConflictDetector returns an Initializer that detects managed
+// resources that declare the same external name. The supplied list must be
+// the list type of the kind of managed resource being reconciled.
+func NewExternalNameConflictDetector(c client.Reader, l resource.ManagedList) *ExternalNameConflictDetector {
+	return &ExternalNameConflictDetector{client: c, list: l}
+}
+
+// Initialize returns an error if an older managed resource of the same kind
+// and provider config declares the supplied managed resource's external name.
+func (d *ExternalNameConflictDetector) Initialize(ctx context.Context, mg resource.Managed) error {
+	name := meta.GetExternalName(mg)
+	if name == "" {
+		return nil
+	}
+
+	others, err := resource.ListExternalNameConflicts(ctx, d.client, d.list, mg)
+	if err != nil {
+		return err
+	}
+
+	older := make([]string, 0, len(others))
+	for _, o := range others {
+		if !olderThan(o, mg) {
+			continue
+		}
+		older = append(older, types.NamespacedName{Namespace: o.GetNamespace(), Name: o.GetName()}.String())
+	}
+
+	if len(older) == 0 {
+		// Only set the True condition if it was previously False, to
+		// avoid adding the condition to every managed resource.
+		if mg.GetCondition(xpv1.TypeExternalNameUnique).Status == corev1.ConditionFalse {
+			mg.SetConditions(xpv1.ExternalNameUnique())
+		}
+		return nil
+	}
+
+	sort.Strings(older)
+	err = errors.Errorf(errFmtExternalNameClaimed, name, strings.Join(older, ", "))
+	mg.SetConditions(xpv1.ExternalNameConflict(err))
+	return err
+}
+
+// olderThan returns true if a was created before b. Managed resources that were
+// created at the same time are ordered by namespace and name.
+func olderThan(a, b resource.Managed) bool {
+	at, bt := a.GetCreationTimestamp(), b.GetCreationTimestamp()
+	if !at.Equal(&bt) {
+		return at.Before(&bt)
+	}
+	return types.NamespacedName{Namespace: a.GetNamespace(), Name: a.GetName()}.String() <
+		types.NamespacedName{Namespace: b.GetNamespace(), Name: b.GetName()}.String()
+}
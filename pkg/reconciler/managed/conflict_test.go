@@ -0,0 +1,153 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var _ Initializer = &ExternalNameConflictDetector{}
+
+func TestExternalNameConflictDetector(t *testing.T) {
+	errBoom := errors.New("boom")
+	now := time.Now()
+
+	// created returns a managed resource with the supplied name that declares
+	// the external name 'cool' and was created at the supplied time.
+	created := func(name string, t time.Time) *fake.Managed {
+		return &fake.Managed{ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			UID:               types.UID(name),
+			CreationTimestamp: metav1.NewTime(t),
+			Annotations:       map[string]string{meta.AnnotationKeyExternalName: "cool"},
+		}}
+	}
+
+	// listing returns a client that lists the supplied managed resources.
+	listing := func(mgs ...*fake.Managed) client.Reader {
+		return &test.MockClient{MockList: func(_ context.Context, l client.ObjectList, _ ...client.ListOption) error {
+			l.(*fake.ManagedList[resource.Managed]).Items = mgs
+			return nil
+		}}
+	}
+
+	type args struct {
+		c  client.Reader
+		mg resource.Managed
+	}
+	type want struct {
+		err error
+		mg  resource.Managed
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoExternalName": {
+			reason: "Managed resources without an external name can't conflict.",
+			args: args{
+				mg: &fake.Managed{},
+			},
+			want: want{
+				mg: &fake.Managed{},
+			},
+		},
+		"ListError": {
+			reason: "Errors listing managed resources should be returned.",
+			args: args{
+				c:  &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				mg: created("a", now),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, "cannot list managed resources by external name"),
+				mg:  created("a", now),
+			},
+		},
+		"Oldest": {
+			reason: "The oldest managed resource that declares an external name should proceed.",
+			args: args{
+				c:  listing(created("a", now), created("b", now.Add(time.Minute))),
+				mg: created("a", now),
+			},
+			want: want{
+				mg: created("a", now),
+			},
+		},
+		"ConflictResolved": {
+			reason: "A managed resource that no longer conflicts should report that its external name is unique.",
+			args: args{
+				c: listing(created("a", now)),
+				mg: func() resource.Managed {
+					mg := created("a", now)
+					mg.SetConditions(xpv1.ExternalNameConflict(errBoom))
+					return mg
+				}(),
+			},
+			want: want{
+				mg: func() resource.Managed {
+					mg := created("a", now)
+					mg.SetConditions(xpv1.ExternalNameUnique())
+					return mg
+				}(),
+			},
+		},
+		"Conflict": {
+			reason: "A managed resource that declares the external name of an older managed resource should report a conflict.",
+			args: args{
+				c:  listing(created("a", now), created("b", now.Add(time.Minute))),
+				mg: created("b", now.Add(time.Minute)),
+			},
+			want: want{
+				err: errors.Errorf(errFmtExternalNameClaimed, "cool", "/a"),
+				mg: func() resource.Managed {
+					mg := created("b", now.Add(time.Minute))
+					mg.SetConditions(xpv1.ExternalNameConflict(errors.Errorf(errFmtExternalNameClaimed, "cool", "/a")))
+					return mg
+				}(),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := NewExternalNameConflictDetector(tc.args.c, &fake.ManagedList[resource.Managed]{})
+			err := d.Initialize(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nInitialize(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\nInitialize(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// DefaultSpecDefaultsPath is the field path at which ProviderConfigs declare
+// spec defaults.
+const DefaultSpecDefaultsPath = "spec.defaults"
+
+const (
+	errGetDefaultsProviderConfig = "cannot get provider config to apply spec defaults"
+	errGetSpecDefaults           = "cannot get spec defaults from provider config"
+	errFmtApplySpecDefault       = "cannot apply spec default to field %q"
+)
+
+// A ProviderConfigDefaulterOption configures a ProviderConfigDefaulter.
+type ProviderConfigDefaulterOption func(d *ProviderConfigDefaulter)
+
+// WithSpecDefaultsPath configures the field path at which ProviderConfigs
+// declare spec defaults.
+func WithSpecDefaultsPath(path string) ProviderConfigDefaulterOption {
+	return func(d *ProviderConfigDefaulter) {
+		d.path = path
+	}
+}
+
+// A ProviderConfigDefaulter is an Initializer that applies spec defaults
+// declared by a managed resource's ProviderConfig, so that platform-wide
+// defaults such as a region, KMS key, or tags need not be duplicated into every
+// managed resource. Defaults are declared as an array of objects at
+// spec.defaults, for example:
+//
+//	defaults:
+//	- kind: Bucket
+//	  fieldPath: spec.forProvider.region
+//	  value: us-east-1
+//
+// A default applies to managed resources of the supplied kind, which may be
+// identified either by kind (Bucket) or by kind and group
+// (Bucket.s3.aws.crossplane.io). A default that omits kind applies to all
+// kinds. Fields that are already set are not overwritten.
+type ProviderConfigDefaulter struct {
+	client client.Client
+	config schema.GroupVersionKind
+	kind   schema.GroupVersionKind
+	path   string
+}
+
+// NewProviderConfigDefaulter returns an Initializer that applies spec defaults
+// declared by ProviderConfigs of the supplied kind to managed resources of the
+// supplied kind.
+func NewProviderConfigDefaulter(c client.Client, pc, mg schema.GroupVersionKind, o ...ProviderConfigDefaulterOption) *ProviderConfigDefaulter {
+	d := &ProviderConfigDefaulter{client: c, config: pc, kind: mg, path: DefaultSpecDefaultsPath}
+	for _, fn := range o {
+		fn(d)
+	}
+	return d
+}
+
+// Initialize the given managed resource.
+func (d *ProviderConfigDefaulter) Initialize(ctx context.Context, mg resource.Managed) error {
+	ref := mg.GetProviderConfigReference()
+	if ref == nil || ref.Name == "" {
+		return nil
+	}
+
+	pc := &unstructured.Unstructured{}
+	pc.SetGroupVersionKind(d.config)
+	if err := d.client.Get(ctx, types.NamespacedName{Name: ref.Name}, pc); err != nil {
+		return errors.Wrap(err, errGetDefaultsProviderConfig)
+	}
+
+	defaults, err := fieldpath.Pave(pc.Object).GetValue(d.path)
+	if fieldpath.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, errGetSpecDefaults)
+	}
+	items, ok := defaults.([]any)
+	if !ok {
+		return errors.New(errGetSpecDefaults)
+	}
+
+	pv, err := fieldpath.PaveObject(mg)
+	if err != nil {
+		return errors.Wrap(err, errUpdateManaged)
+	}
+
+	changed := false
+	for _, i := range items {
+		sd := fieldpath.Pave(asObject(i))
+		kind, _ := sd.GetString("kind")
+		if kind != "" && kind != d.kind.Kind && kind != d.kind.GroupKind().String() {
+			continue
+		}
+		fp, _ := sd.GetString("fieldPath")
+		v, err := sd.GetValue("value")
+		if fp == "" || err != nil {
+			continue
+		}
+		if _, err := pv.GetValue(fp); !fieldpath.IsNotFound(err) {
+			continue
+		}
+		if err := pv.SetValue(fp, v); err != nil {
+			return errors.Wrapf(err, errFmtApplySpecDefault, fp)
+		}
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(pv.UnstructuredContent(), mg); err != nil {
+		return errors.Wrap(err, errUpdateManaged)
+	}
+	return errors.Wrap(d.client.Update(ctx, mg), errUpdateManaged)
+}
+
+func asObject(v any) map[string]any {
+	o, _ := v.(map[string]any)
+	return o
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var _ Initializer = &ProviderConfigDefaulter{}
+
+func TestProviderConfigDefaulter(t *testing.T) {
+	errBoom := errors.New("boom")
+	pcGVK := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "ProviderConfig"}
+	mgGVK := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Bucket"}
+
+	// We use labels as the defaulted fields, because the fake managed
+	// resource doesn't have a spec. Its object metadata isn't tagged, so the
+	// labels are at field path 'objectMeta.labels'.
+	labelled := func(l map[string]string) *fake.Managed {
+		return &fake.Managed{
+			ObjectMeta:               metav1.ObjectMeta{Name: "cool", Labels: l},
+			ProviderConfigReferencer: fake.ProviderConfigReferencer{Ref: &xpv1.Reference{Name: "default"}},
+		}
+	}
+	get := func(defaults ...any) test.MockGetFn {
+		return func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			if len(defaults) == 0 {
+				return nil
+			}
+			return unstructured.SetNestedSlice(obj.(*unstructured.Unstructured).Object, defaults, "spec", "defaults")
+		}
+	}
+
+	type want struct {
+		err error
+		mg  resource.Managed
+	}
+
+	cases := map[string]struct {
+		reason string
+		client client.Client
+		mg     resource.Managed
+		want   want
+	}{
+		"NoProviderConfig": {
+			reason: "Managed resources that reference no ProviderConfig should not be defaulted.",
+			mg:     &fake.Managed{},
+			want: want{
+				mg: &fake.Managed{},
+			},
+		},
+		"GetProviderConfigError": {
+			reason: "Errors getting the ProviderConfig should be returned.",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			mg:     labelled(nil),
+			want: want{
+				err: errors.Wrap(errBoom, errGetDefaultsProviderConfig),
+				mg:  labelled(nil),
+			},
+		},
+		"NoDefaults": {
+			reason: "Managed resources should not be updated if their ProviderConfig declares no defaults.",
+			client: &test.MockClient{MockGet: get()},
+			mg:     labelled(nil),
+			want: want{
+				mg: labelled(nil),
+			},
+		},
+		"UpdateManagedError": {
+			reason: "Errors updating the managed resource should be returned.",
+			client: &test.MockClient{
+				MockGet:    get(map[string]any{"fieldPath": "objectMeta.labels.region", "value": "us-east-1"}),
+				MockUpdate: test.NewMockUpdateFn(errBoom),
+			},
+			mg: labelled(nil),
+			want: want{
+				err: errors.Wrap(errBoom, errUpdateManaged),
+				mg:  labelled(map[string]string{"region": "us-east-1"}),
+			},
+		},
+		"Defaulted": {
+			reason: "Defaults for our kind should be applied without overwriting fields that are already set.",
+			client: &test.MockClient{
+				MockGet: get(
+					map[string]any{"kind": "Bucket", "fieldPath": "objectMeta.labels.region", "value": "us-east-1"},
+					map[string]any{"kind": "Bucket.example.org", "fieldPath": "objectMeta.labels.kms", "value": "cool-key"},
+					map[string]any{"kind": "Database", "fieldPath": "objectMeta.labels.engine", "value": "postgres"},
+					map[string]any{"fieldPath": "objectMeta.labels.env", "value": "dev"},
+				),
+				MockUpdate: test.NewMockUpdateFn(nil),
+			},
+			mg: labelled(map[string]string{"env": "prod"}),
+			want: want{
+				mg: labelled(map[string]string{"region": "us-east-1", "kms": "cool-key", "env": "prod"}),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := NewProviderConfigDefaulter(tc.client, pcGVK, mgGVK)
+			err := d.Initialize(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nd.Initialize(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.mg, tc.mg); diff != "" {
+				t.Errorf("\n%s\nd.Initialize(...) Managed: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// A HealthChecker performs a deep health check of a managed resource's
+// external resource, for example by querying a database endpoint. It returns
+// an error describing why the external resource is unhealthy, if it is.
+type HealthChecker interface {
+	CheckHealth(ctx context.Context, mg resource.Managed) error
+}
+
+// A HealthCheckerFn is a function that satisfies the HealthChecker interface.
+type HealthCheckerFn func(ctx context.Context, mg resource.Managed) error
+
+// CheckHealth checks the health of the supplied managed resource's external
+// resource.
+func (fn HealthCheckerFn) CheckHealth(ctx context.Context, mg resource.Managed) error {
+	return fn(ctx, mg)
+}
+
+// healthChecks runs a HealthChecker at most once per interval per managed
+// resource.
+type healthChecks struct {
+	checker  HealthChecker
+	interval time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	checked map[types.UID]time.Time
+}
+
+func newHealthChecks(h HealthChecker, interval time.Duration) *healthChecks {
+	return &healthChecks{checker: h, interval: interval, now: time.Now, checked: make(map[types.UID]time.Time)}
+}
+
+// Check the health of the supplied managed resource's external resource, and
+// set its Healthy condition accordingly. Nothing is checked if the health of
+// the managed resource was checked within the interval.
+func (h *healthChecks) Check(ctx context.Context, mg resource.Managed) {
+	if h == nil {
+		return
+	}
+
+	h.mu.Lock()
+	now := h.now()
+	if last, ok := h.checked[mg.GetUID()]; ok && now.Sub(last) < h.interval {
+		h.mu.Unlock()
+		return
+	}
+	h.checked[mg.GetUID()] = now
+	h.mu.Unlock()
+
+	if err := h.checker.CheckHealth(ctx, mg); err != nil {
+		mg.SetConditions(xpv1.Unhealthy(err))
+		return
+	}
+	mg.SetConditions(xpv1.Healthy())
+}
+
+// Forget when the health of the supplied managed resource was last checked,
+// for example because its external resource no longer exists.
+func (h *healthChecks) Forget(mg resource.Managed) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.checked, mg.GetUID())
+}
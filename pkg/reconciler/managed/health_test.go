@@ -0,0 +1,109 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestHealthChecksCheck(t *testing.T) {
+	errBoom := errors.New("boom")
+	now := time.Now()
+
+	withConditions := func(c ...xpv1.Condition) *fake.Managed {
+		mg := &fake.Managed{ObjectMeta: metav1.ObjectMeta{UID: "cool-uid"}}
+		mg.SetConditions(c...)
+		return mg
+	}
+
+	type args struct {
+		checker HealthChecker
+		checked map[string]time.Time
+		mg      resource.Managed
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   resource.Managed
+	}{
+		"Healthy": {
+			reason: "A managed resource that passes its health check should be Healthy.",
+			args: args{
+				checker: HealthCheckerFn(func(_ context.Context, _ resource.Managed) error { return nil }),
+				mg:      withConditions(),
+			},
+			want: withConditions(xpv1.Healthy()),
+		},
+		"Unhealthy": {
+			reason: "A managed resource that fails its health check should be Unhealthy.",
+			args: args{
+				checker: HealthCheckerFn(func(_ context.Context, _ resource.Managed) error { return errBoom }),
+				mg:      withConditions(),
+			},
+			want: withConditions(xpv1.Unhealthy(errBoom)),
+		},
+		"CheckedRecently": {
+			reason: "A managed resource whose health was checked within the interval should not be checked again.",
+			args: args{
+				checker: HealthCheckerFn(func(_ context.Context, _ resource.Managed) error {
+					t.Errorf("CheckHealth(...): unexpected health check")
+					return nil
+				}),
+				checked: map[string]time.Time{"cool-uid": now.Add(-30 * time.Second)},
+				mg:      withConditions(xpv1.Healthy()),
+			},
+			want: withConditions(xpv1.Healthy()),
+		},
+		"CheckedLongAgo": {
+			reason: "A managed resource whose health was checked before the interval should be checked again.",
+			args: args{
+				checker: HealthCheckerFn(func(_ context.Context, _ resource.Managed) error { return errBoom }),
+				checked: map[string]time.Time{"cool-uid": now.Add(-2 * time.Minute)},
+				mg:      withConditions(xpv1.Healthy()),
+			},
+			want: withConditions(xpv1.Unhealthy(errBoom)),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := newHealthChecks(tc.args.checker, time.Minute)
+			h.now = func() time.Time { return now }
+			for uid, last := range tc.args.checked {
+				h.checked[types.UID(uid)] = last
+			}
+
+			h.Check(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\nh.Check(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
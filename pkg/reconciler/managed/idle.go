@@ -0,0 +1,246 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const (
+	errClientKey        = "cannot determine external client key"
+	errTrackUsage       = "cannot track provider config usage"
+	errFmtDisconnectKey = "cannot disconnect idle external client %q"
+)
+
+// DefaultIdleTimeout is how long an IdleEvictingConnecter keeps an unused
+// ExternalClient by default.
+const DefaultIdleTimeout = 10 * time.Minute
+
+// An ExternalClientDisconnecter is an ExternalClient that holds resources, for
+// example SDK sessions or connection pools, that must be released once it is
+// no longer used.
+type ExternalClientDisconnecter interface {
+	// Disconnect the ExternalClient, releasing its resources.
+	Disconnect(ctx context.Context) error
+}
+
+// An ExternalClientKeyFn returns a key that identifies the ExternalClient of
+// the supplied managed resource. Managed resources with the same key share an
+// ExternalClient.
+type ExternalClientKeyFn func(mg resource.Managed) (string, error)
+
+// ProviderConfigClientKey keys ExternalClients by the name of the
+// ProviderConfig the managed resource references.
+func ProviderConfigClientKey(mg resource.Managed) (string, error) {
+	ref := mg.GetProviderConfigReference()
+	if ref == nil {
+		return "", nil
+	}
+	return ref.Name, nil
+}
+
+// An IdleEvictingConnecterOption configures an IdleEvictingConnecter.
+type IdleEvictingConnecterOption func(c *IdleEvictingConnecter)
+
+// WithIdleTimeout configures how long an ExternalClient may go unused before
+// it is disconnected. It should be much longer than the reconcile timeout, so
+// that clients aren't disconnected while they're in use.
+func WithIdleTimeout(d time.Duration) IdleEvictingConnecterOption {
+	return func(c *IdleEvictingConnecter) {
+		c.idle = d
+	}
+}
+
+// WithExternalClientKey configures how ExternalClients are keyed.
+// ProviderConfigClientKey is used by default.
+func WithExternalClientKey(fn ExternalClientKeyFn) IdleEvictingConnecterOption {
+	return func(c *IdleEvictingConnecter) {
+		c.key = fn
+	}
+}
+
+// WithIdleEvictionClock configures the function an IdleEvictingConnecter uses
+// to determine the current time.
+func WithIdleEvictionClock(now func() time.Time) IdleEvictingConnecterOption {
+	return func(c *IdleEvictingConnecter) {
+		c.now = now
+	}
+}
+
+type idleClient struct {
+	client   ExternalClient
+	lastUsed time.Time
+}
+
+func (ic *idleClient) idle(now time.Time, timeout time.Duration) bool {
+	return now.Sub(ic.lastUsed) > timeout
+}
+
+// An IdleEvictingConnecter is an ExternalConnectDisconnecter that reuses the
+// ExternalClients produced by an ExternalConnecter, and disconnects those that
+// have not been used for longer than an idle timeout. Clients are recreated on
+// demand after they are disconnected. ExternalClients that implement
+// ExternalClientDisconnecter have their resources released when they are
+// disconnected.
+//
+// ExternalClients are shared by all managed resources with the same key, so
+// they must be safe for concurrent use, and must not retain the context passed
+// to Connect.
+//
+// The wrapped ExternalConnecter is not called when a client is reused, so it
+// must not be relied upon to track ProviderConfig usage. The
+// IdleEvictingConnecter tracks usage of every managed resource it connects
+// instead. Likewise clients are not recreated when their credentials change;
+// pass Evict to resource.WithCredentialsRotatedFn to recreate the clients of a
+// ProviderConfig whose credentials were rotated.
+type IdleEvictingConnecter struct {
+	connecter ExternalConnecter
+	tracker   resource.Tracker
+	key       ExternalClientKeyFn
+	idle      time.Duration
+	now       func() time.Time
+
+	mx      sync.Mutex
+	clients map[string]*idleClient
+
+	// evicted clients that may still be in use by an in-flight reconcile.
+	evicted []evictedClient
+}
+
+type evictedClient struct {
+	key string
+	*idleClient
+}
+
+// NewIdleEvictingConnecter returns an IdleEvictingConnecter that reuses the
+// ExternalClients produced by the supplied ExternalConnecter, and tracks usage
+// of ProviderConfigs using the supplied Tracker.
+func NewIdleEvictingConnecter(c ExternalConnecter, t resource.Tracker, o ...IdleEvictingConnecterOption) *IdleEvictingConnecter {
+	ic := &IdleEvictingConnecter{
+		connecter: c,
+		tracker:   t,
+		key:       ProviderConfigClientKey,
+		idle:      DefaultIdleTimeout,
+		now:       time.Now,
+		clients:   map[string]*idleClient{},
+	}
+	for _, fn := range o {
+		fn(ic)
+	}
+	return ic
+}
+
+// Connect tracks usage of the supplied managed resource's ProviderConfig and
+// returns its ExternalClient, connecting a new one if there is none.
+func (c *IdleEvictingConnecter) Connect(ctx context.Context, mg resource.Managed) (ExternalClient, error) {
+	if err := c.tracker.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackUsage)
+	}
+
+	k, err := c.key(mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errClientKey)
+	}
+
+	c.mx.Lock()
+	if ic, ok := c.clients[k]; ok {
+		ic.lastUsed = c.now()
+		c.mx.Unlock()
+		return ic.client, nil
+	}
+	c.mx.Unlock()
+
+	// Don't hold the lock while connecting, which may be slow.
+	ec, err := c.connecter.Connect(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	if ic, ok := c.clients[k]; ok {
+		// Another reconcile connected while we were connecting. Use its
+		// client, and release ours.
+		ic.lastUsed = c.now()
+		if d, ok := ec.(ExternalClientDisconnecter); ok {
+			_ = d.Disconnect(ctx)
+		}
+		return ic.client, nil
+	}
+	c.clients[k] = &idleClient{client: ec, lastUsed: c.now()}
+	return ec, nil
+}
+
+// Evict the ExternalClient with the supplied key, so that the next managed
+// resource with that key connects a new one. The evicted client is
+// disconnected once it has gone unused for longer than the idle timeout, so
+// that reconciles that are still using it aren't interrupted. Evict has the
+// signature of the function passed to resource.WithCredentialsRotatedFn, so
+// that clients keyed by ProviderConfigClientKey are recreated when their
+// credentials are rotated.
+func (c *IdleEvictingConnecter) Evict(key string) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	if ic, ok := c.clients[key]; ok {
+		c.evicted = append(c.evicted, evictedClient{key: key, idleClient: ic})
+		delete(c.clients, key)
+	}
+}
+
+// Disconnect the ExternalClients that have not been used for longer than the
+// idle timeout. The managed reconciler calls Disconnect at the end of every
+// reconcile.
+func (c *IdleEvictingConnecter) Disconnect(ctx context.Context) error {
+	c.mx.Lock()
+	now := c.now()
+	evicted := map[string][]ExternalClient{}
+	for k, ic := range c.clients {
+		if ic.idle(now, c.idle) {
+			evicted[k] = append(evicted[k], ic.client)
+			delete(c.clients, k)
+		}
+	}
+	keep := c.evicted[:0]
+	for _, ec := range c.evicted {
+		if !ec.idle(now, c.idle) {
+			keep = append(keep, ec)
+			continue
+		}
+		evicted[ec.key] = append(evicted[ec.key], ec.client)
+	}
+	c.evicted = keep
+	c.mx.Unlock()
+
+	errs := make([]error, 0, len(evicted))
+	for k, ecs := range evicted {
+		for _, ec := range ecs {
+			d, ok := ec.(ExternalClientDisconnecter)
+			if !ok {
+				continue
+			}
+			if err := d.Disconnect(ctx); err != nil {
+				errs = append(errs, errors.Wrapf(err, errFmtDisconnectKey, k))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,275 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var (
+	_ ExternalConnectDisconnecter = &IdleEvictingConnecter{}
+
+	// Evict can be called when a ProviderConfig's credentials are rotated.
+	_ = resource.WithCredentialsRotatedFn((&IdleEvictingConnecter{}).Evict)
+)
+
+// A disconnectingClient counts how many times it was disconnected.
+type disconnectingClient struct {
+	NopClient
+	disconnects *int
+	err         error
+}
+
+func (c *disconnectingClient) Disconnect(_ context.Context) error {
+	*c.disconnects++
+	return c.err
+}
+
+func TestIdleEvictingConnecter(t *testing.T) {
+	errBoom := errors.New("boom")
+	now := time.Now()
+
+	withProviderConfig := func(name string) resource.Managed {
+		return &fake.Managed{ProviderConfigReferencer: fake.ProviderConfigReferencer{Ref: &xpv1.Reference{Name: name}}}
+	}
+
+	type args struct {
+		first   resource.Managed
+		elapsed time.Duration
+		second  resource.Managed
+		err     error
+	}
+	type want struct {
+		connects    int
+		tracks      int
+		disconnects int
+		err         error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Reused": {
+			reason: "A client that was used within the idle timeout should be reused, but usage should be tracked each time.",
+			args: args{
+				first:   withProviderConfig("a"),
+				elapsed: time.Minute,
+				second:  withProviderConfig("a"),
+			},
+			want: want{connects: 1, tracks: 2},
+		},
+		"DifferentKeys": {
+			reason: "Managed resources with different keys should not share a client.",
+			args: args{
+				first:   withProviderConfig("a"),
+				elapsed: time.Minute,
+				second:  withProviderConfig("b"),
+			},
+			want: want{connects: 2, tracks: 2},
+		},
+		"Evicted": {
+			reason: "A client that went unused for longer than the idle timeout should be disconnected, and recreated on demand.",
+			args: args{
+				first:   withProviderConfig("a"),
+				elapsed: DefaultIdleTimeout + time.Second,
+				second:  withProviderConfig("a"),
+			},
+			want: want{connects: 2, tracks: 2, disconnects: 1},
+		},
+		"DisconnectError": {
+			reason: "Errors disconnecting idle clients should be returned.",
+			args: args{
+				first:   withProviderConfig("a"),
+				elapsed: DefaultIdleTimeout + time.Second,
+				second:  withProviderConfig("a"),
+				err:     errBoom,
+			},
+			want: want{connects: 2, tracks: 2, disconnects: 1, err: errors.Join(errors.Wrapf(errBoom, errFmtDisconnectKey, "a"))},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := want{}
+			ec := ExternalConnectorFn(func(_ context.Context, _ resource.Managed) (ExternalClient, error) {
+				got.connects++
+				return &disconnectingClient{disconnects: &got.disconnects, err: tc.args.err}, nil
+			})
+			tr := resource.TrackerFn(func(_ context.Context, _ resource.Managed) error {
+				got.tracks++
+				return nil
+			})
+			clock := now
+			c := NewIdleEvictingConnecter(ec, tr, WithIdleEvictionClock(func() time.Time { return clock }))
+
+			if _, err := c.Connect(context.Background(), tc.args.first); err != nil {
+				t.Fatalf("\n%s\nc.Connect(...): %s", tc.reason, err)
+			}
+			if err := c.Disconnect(context.Background()); err != nil {
+				t.Fatalf("\n%s\nc.Disconnect(...): %s", tc.reason, err)
+			}
+
+			clock = clock.Add(tc.args.elapsed)
+			got.err = c.Disconnect(context.Background())
+			if _, err := c.Connect(context.Background(), tc.args.second); err != nil {
+				t.Fatalf("\n%s\nc.Connect(...): %s", tc.reason, err)
+			}
+
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nIdleEvictingConnecter: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestIdleEvictingConnecterTrack(t *testing.T) {
+	errBoom := errors.New("boom")
+	mg := &fake.Managed{ProviderConfigReferencer: fake.ProviderConfigReferencer{Ref: &xpv1.Reference{Name: "a"}}}
+
+	type want struct {
+		connects int
+		err      error
+	}
+
+	cases := map[string]struct {
+		reason string
+		cached bool
+		want   want
+	}{
+		"NewClient": {
+			reason: "Errors tracking usage should be returned before connecting a new client.",
+			want:   want{err: errors.Wrap(errBoom, errTrackUsage)},
+		},
+		"CachedClient": {
+			reason: "Errors tracking usage should be returned rather than a cached client.",
+			cached: true,
+			want:   want{connects: 1, err: errors.Wrap(errBoom, errTrackUsage)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := want{}
+			ec := ExternalConnectorFn(func(_ context.Context, _ resource.Managed) (ExternalClient, error) {
+				got.connects++
+				return &NopClient{}, nil
+			})
+			var trackErr error
+			tr := resource.TrackerFn(func(_ context.Context, _ resource.Managed) error { return trackErr })
+			c := NewIdleEvictingConnecter(ec, tr)
+
+			if tc.cached {
+				if _, err := c.Connect(context.Background(), mg); err != nil {
+					t.Fatalf("\n%s\nc.Connect(...): %s", tc.reason, err)
+				}
+			}
+			trackErr = errBoom
+			_, got.err = c.Connect(context.Background(), mg)
+
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nc.Connect(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestIdleEvictingConnecterEvict(t *testing.T) {
+	now := time.Now()
+	mg := &fake.Managed{ProviderConfigReferencer: fake.ProviderConfigReferencer{Ref: &xpv1.Reference{Name: "a"}}}
+
+	type args struct {
+		key     string
+		elapsed time.Duration
+	}
+	type want struct {
+		connects    int
+		disconnects int
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"EvictedInUse": {
+			reason: "An evicted client should be replaced, but not disconnected while it may still be in use.",
+			args: args{
+				key:     "a",
+				elapsed: time.Minute,
+			},
+			want: want{connects: 2},
+		},
+		"EvictedIdle": {
+			reason: "An evicted client should be disconnected once it has gone unused for longer than the idle timeout.",
+			args: args{
+				key:     "a",
+				elapsed: DefaultIdleTimeout + time.Second,
+			},
+			want: want{connects: 2, disconnects: 1},
+		},
+		"OtherKey": {
+			reason: "Evicting a different key should not affect the client.",
+			args: args{
+				key:     "b",
+				elapsed: time.Minute,
+			},
+			want: want{connects: 1},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := want{}
+			ec := ExternalConnectorFn(func(_ context.Context, _ resource.Managed) (ExternalClient, error) {
+				got.connects++
+				return &disconnectingClient{disconnects: &got.disconnects}, nil
+			})
+			tr := resource.TrackerFn(func(_ context.Context, _ resource.Managed) error { return nil })
+			clock := now
+			c := NewIdleEvictingConnecter(ec, tr, WithIdleEvictionClock(func() time.Time { return clock }))
+
+			if _, err := c.Connect(context.Background(), mg); err != nil {
+				t.Fatalf("\n%s\nc.Connect(...): %s", tc.reason, err)
+			}
+			c.Evict(tc.args.key)
+
+			clock = clock.Add(tc.args.elapsed)
+			if err := c.Disconnect(context.Background()); err != nil {
+				t.Fatalf("\n%s\nc.Disconnect(...): %s", tc.reason, err)
+			}
+			if _, err := c.Connect(context.Background(), mg); err != nil {
+				t.Fatalf("\n%s\nc.Connect(...): %s", tc.reason, err)
+			}
+
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nc.Evict(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
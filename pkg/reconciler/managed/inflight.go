@@ -0,0 +1,127 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+)
+
+type inFlight struct {
+	generation int64
+	cancel     context.CancelFunc
+}
+
+// An InFlightTracker tracks the external calls that are in flight for each
+// managed resource, and cancels them when they're superseded. External calls
+// are superseded when their managed resource is deleted, or when its spec
+// changes. This ensures a hung external call, for example a Create that takes
+// ten minutes to time out, doesn't delay handling the managed resource's
+// deletion.
+type InFlightTracker struct {
+	mu       sync.Mutex
+	inFlight map[types.NamespacedName]*inFlight
+}
+
+// NewInFlightTracker returns a new InFlightTracker.
+func NewInFlightTracker() *InFlightTracker {
+	return &InFlightTracker{inFlight: make(map[types.NamespacedName]*inFlight)}
+}
+
+// Track the external calls of the supplied managed resource. The returned
+// context is cancelled when the calls are superseded. The returned function
+// must be called when the calls are done.
+func (t *InFlightTracker) Track(ctx context.Context, o client.Object) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	nn := types.NamespacedName{Namespace: o.GetNamespace(), Name: o.GetName()}
+
+	f := &inFlight{generation: o.GetGeneration(), cancel: cancel}
+
+	t.mu.Lock()
+	t.inFlight[nn] = f
+	t.mu.Unlock()
+
+	return ctx, func() {
+		t.mu.Lock()
+		// Only forget our own calls. They may have been superseded and the
+		// object tracked again.
+		if t.inFlight[nn] == f {
+			delete(t.inFlight, nn)
+		}
+		t.mu.Unlock()
+		cancel()
+	}
+}
+
+// Supersede the external calls of the supplied managed resource, if they're
+// in flight and the supplied object is newer than the object whose calls are
+// in flight, or was deleted. Supersede returns true if calls were cancelled.
+func (t *InFlightTracker) Supersede(o client.Object) bool {
+	nn := types.NamespacedName{Namespace: o.GetNamespace(), Name: o.GetName()}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	f, ok := t.inFlight[nn]
+	if !ok {
+		return false
+	}
+	if !meta.WasDeleted(o) && o.GetGeneration() <= f.generation {
+		return false
+	}
+	f.cancel()
+	delete(t.inFlight, nn)
+	return true
+}
+
+// Predicate returns a predicate that supersedes the in flight external calls
+// of managed resources that are updated or deleted. The predicate never
+// filters events; add it to the watch of the kind of managed resource that is
+// being reconciled.
+func (t *InFlightTracker) Predicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectNew != nil {
+				t.Supersede(e.ObjectNew)
+			}
+			return true
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			if e.Object != nil {
+				// The object may not have a deletion timestamp if its
+				// deletion was observed from a tombstone.
+				t.cancel(types.NamespacedName{Namespace: e.Object.GetNamespace(), Name: e.Object.GetName()})
+			}
+			return true
+		},
+	}
+}
+
+func (t *InFlightTracker) cancel(nn types.NamespacedName) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if f, ok := t.inFlight[nn]; ok {
+		f.cancel()
+		delete(t.inFlight, nn)
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+)
+
+func TestInFlightTracker(t *testing.T) {
+	tracked := &fake.Managed{ObjectMeta: metav1.ObjectMeta{Name: "cool", Generation: 1}}
+	withMeta := func(om metav1.ObjectMeta) *fake.Managed {
+		om.Name = "cool"
+		return &fake.Managed{ObjectMeta: om}
+	}
+
+	type args struct {
+		fn func(p predicate.Predicate) bool
+	}
+	type want struct {
+		cancelled bool
+		allowed   bool
+		inFlight  int
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"StatusChanged": {
+			reason: "Updates that don't change the generation should not supersede in flight calls.",
+			args: args{
+				fn: func(p predicate.Predicate) bool {
+					return p.Update(event.UpdateEvent{ObjectOld: tracked, ObjectNew: withMeta(metav1.ObjectMeta{Generation: 1})})
+				},
+			},
+			want: want{allowed: true, inFlight: 1},
+		},
+		"SpecChanged": {
+			reason: "Updates that change the generation should supersede in flight calls.",
+			args: args{
+				fn: func(p predicate.Predicate) bool {
+					return p.Update(event.UpdateEvent{ObjectOld: tracked, ObjectNew: withMeta(metav1.ObjectMeta{Generation: 2})})
+				},
+			},
+			want: want{cancelled: true, allowed: true},
+		},
+		"DeletionRequested": {
+			reason: "Updates that request deletion should supersede in flight calls.",
+			args: args{
+				fn: func(p predicate.Predicate) bool {
+					return p.Update(event.UpdateEvent{ObjectOld: tracked, ObjectNew: withMeta(metav1.ObjectMeta{Generation: 1, DeletionTimestamp: &metav1.Time{Time: time.Now()}})})
+				},
+			},
+			want: want{cancelled: true, allowed: true},
+		},
+		"Deleted": {
+			reason: "Deletes should supersede in flight calls.",
+			args: args{
+				fn: func(p predicate.Predicate) bool {
+					return p.Delete(event.DeleteEvent{Object: withMeta(metav1.ObjectMeta{})})
+				},
+			},
+			want: want{cancelled: true, allowed: true},
+		},
+		"OtherObject": {
+			reason: "Events for other objects should not supersede in flight calls.",
+			args: args{
+				fn: func(p predicate.Predicate) bool {
+					return p.Delete(event.DeleteEvent{Object: &fake.Managed{ObjectMeta: metav1.ObjectMeta{Name: "other"}}})
+				},
+			},
+			want: want{allowed: true, inFlight: 1},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tr := NewInFlightTracker()
+			ctx, done := tr.Track(context.Background(), tracked)
+			defer done()
+
+			allowed := tc.args.fn(tr.Predicate())
+			got := want{cancelled: ctx.Err() != nil, allowed: allowed, inFlight: len(tr.inFlight)}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\ntr.Predicate(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const (
+	errMarshalJournal   = "cannot marshal creation journal entry"
+	errUnmarshalJournal = "cannot unmarshal creation journal entry"
+	errWriteJournal     = "cannot write creation journal entry"
+	errReadJournal      = "cannot read creation journal entry"
+	errRemoveJournal    = "cannot remove creation journal entry"
+)
+
+// A CreationJournal durably records the outcome of creating an external
+// resource before it is recorded using the managed resource's critical
+// annotations. If the reconciler crashes, or cannot persist the annotations,
+// after creating an external resource it recovers the outcome from the journal
+// rather than refusing to proceed, or leaking the external resource.
+type CreationJournal interface {
+	// Record the critical annotations of the supplied managed resource.
+	Record(ctx context.Context, mg resource.Managed) error
+
+	// Recover any critical annotations previously recorded for the supplied
+	// managed resource by adding them to it. Recover returns true if any
+	// annotations were recovered.
+	Recover(ctx context.Context, mg resource.Managed) (bool, error)
+
+	// Forget any critical annotations recorded for the supplied managed
+	// resource, once they have been persisted to the API server.
+	Forget(ctx context.Context, mg resource.Managed) error
+}
+
+// A NopCreationJournal does nothing.
+type NopCreationJournal struct{}
+
+// Record does nothing.
+func (NopCreationJournal) Record(_ context.Context, _ resource.Managed) error { return nil }
+
+// Recover does nothing.
+func (NopCreationJournal) Recover(_ context.Context, _ resource.Managed) (bool, error) {
+	return false, nil
+}
+
+// Forget does nothing.
+func (NopCreationJournal) Forget(_ context.Context, _ resource.Managed) error { return nil }
+
+// criticalAnnotations returns the annotations of the supplied managed resource
+// that record the outcome of creating its external resource.
+func criticalAnnotations(mg resource.Managed) map[string]string {
+	a := map[string]string{}
+	for _, k := range []string{
+		meta.AnnotationKeyExternalName,
+		meta.AnnotationKeyExternalNameHistory,
+		meta.AnnotationKeyExternalCreatePending,
+		meta.AnnotationKeyExternalCreateSucceeded,
+		meta.AnnotationKeyExternalCreateFailed,
+		meta.AnnotationKeyExternalCreateUID,
+		meta.AnnotationKeyIdempotencyToken,
+	} {
+		if v, ok := mg.GetAnnotations()[k]; ok {
+			a[k] = v
+		}
+	}
+	return a
+}
+
+// recoverAnnotations adds the supplied recorded critical annotations to the
+// supplied managed resource if doing so would complete its creation. Stale
+// records, e.g. of a previous creation attempt, are ignored. It returns true
+// if the annotations were added.
+func recoverAnnotations(mg resource.Managed, recorded map[string]string) bool {
+	if len(recorded) == 0 {
+		return false
+	}
+	// Only the outcome of the pending creation may be recovered.
+	delete(recorded, meta.AnnotationKeyExternalCreatePending)
+
+	o := mg.DeepCopyObject().(resource.Managed)
+	meta.AddAnnotations(o, recorded)
+	if meta.ExternalCreateIncomplete(o) {
+		return false
+	}
+	meta.AddAnnotations(mg, recorded)
+	return true
+}
+
+// A FileCreationJournal records critical annotations to files in a local
+// directory, one file per managed resource. The directory should be on a
+// volume that survives the provider restarting, e.g. an emptyDir volume.
+type FileCreationJournal struct {
+	dir string
+}
+
+// NewFileCreationJournal returns a CreationJournal that records critical
+// annotations to files in the supplied directory.
+func NewFileCreationJournal(dir string) *FileCreationJournal {
+	return &FileCreationJournal{dir: dir}
+}
+
+func (j *FileCreationJournal) path(mg resource.Managed) string {
+	return filepath.Join(j.dir, string(mg.GetUID())+".json")
+}
+
+// Record the critical annotations of the supplied managed resource. The file
+// is written atomically, and synced to disk before Record returns.
+func (j *FileCreationJournal) Record(_ context.Context, mg resource.Managed) error {
+	data, err := json.Marshal(criticalAnnotations(mg))
+	if err != nil {
+		return errors.Wrap(err, errMarshalJournal)
+	}
+
+	f, err := os.CreateTemp(j.dir, ".journal-*")
+	if err != nil {
+		return errors.Wrap(err, errWriteJournal)
+	}
+	defer os.Remove(f.Name()) //nolint:errcheck // The file no longer exists if it was renamed.
+
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return errors.Wrap(err, errWriteJournal)
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return errors.Wrap(err, errWriteJournal)
+	}
+	if err := f.Close(); err != nil {
+		return errors.Wrap(err, errWriteJournal)
+	}
+	return errors.Wrap(os.Rename(f.Name(), j.path(mg)), errWriteJournal)
+}
+
+// Recover any critical annotations previously recorded for the supplied
+// managed resource.
+func (j *FileCreationJournal) Recover(_ context.Context, mg resource.Managed) (bool, error) {
+	data, err := os.ReadFile(j.path(mg))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrap(err, errReadJournal)
+	}
+	recorded := map[string]string{}
+	if err := json.Unmarshal(data, &recorded); err != nil {
+		return false, errors.Wrap(err, errUnmarshalJournal)
+	}
+	return recoverAnnotations(mg, recorded), nil
+}
+
+// Forget any critical annotations recorded for the supplied managed resource.
+func (j *FileCreationJournal) Forget(_ context.Context, mg resource.Managed) error {
+	err := os.Remove(j.path(mg))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return errors.Wrap(err, errRemoveJournal)
+}
+
+// A ConfigMapCreationJournal records critical annotations to a ConfigMap, one
+// key per managed resource. Writing to the ConfigMap may fail for the same
+// reasons writing to the managed resource does, but the journal survives the
+// provider crashing between creating an external resource and persisting its
+// critical annotations. Each replica of a provider should use its own
+// ConfigMap.
+type ConfigMapCreationJournal struct {
+	client client.Client
+	nn     types.NamespacedName
+}
+
+// NewConfigMapCreationJournal returns a CreationJournal that records critical
+// annotations to the supplied ConfigMap, creating it if necessary.
+func NewConfigMapCreationJournal(c client.Client, nn types.NamespacedName) *ConfigMapCreationJournal {
+	return &ConfigMapCreationJournal{client: c, nn: nn}
+}
+
+// Record the critical annotations of the supplied managed resource.
+func (j *ConfigMapCreationJournal) Record(ctx context.Context, mg resource.Managed) error {
+	data, err := json.Marshal(criticalAnnotations(mg))
+	if err != nil {
+		return errors.Wrap(err, errMarshalJournal)
+	}
+	return errors.Wrap(j.update(ctx, func(cm *corev1.ConfigMap) {
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[string(mg.GetUID())] = string(data)
+	}), errWriteJournal)
+}
+
+// Recover any critical annotations previously recorded for the supplied
+// managed resource.
+func (j *ConfigMapCreationJournal) Recover(ctx context.Context, mg resource.Managed) (bool, error) {
+	cm := &corev1.ConfigMap{}
+	err := j.client.Get(ctx, j.nn, cm)
+	if kerrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrap(err, errReadJournal)
+	}
+	data, ok := cm.Data[string(mg.GetUID())]
+	if !ok {
+		return false, nil
+	}
+	recorded := map[string]string{}
+	if err := json.Unmarshal([]byte(data), &recorded); err != nil {
+		return false, errors.Wrap(err, errUnmarshalJournal)
+	}
+	return recoverAnnotations(mg, recorded), nil
+}
+
+// Forget any critical annotations recorded for the supplied managed resource.
+func (j *ConfigMapCreationJournal) Forget(ctx context.Context, mg resource.Managed) error {
+	return errors.Wrap(j.update(ctx, func(cm *corev1.ConfigMap) {
+		delete(cm.Data, string(mg.GetUID()))
+	}), errRemoveJournal)
+}
+
+func (j *ConfigMapCreationJournal) update(ctx context.Context, fn func(cm *corev1.ConfigMap)) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm := &corev1.ConfigMap{}
+		cm.SetNamespace(j.nn.Namespace)
+		cm.SetName(j.nn.Name)
+		_, err := controllerutil.CreateOrUpdate(ctx, j.client, cm, func() error {
+			fn(cm)
+			return nil
+		})
+		return err
+	})
+}
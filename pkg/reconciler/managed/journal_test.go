@@ -0,0 +1,141 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var (
+	_ CreationJournal = NopCreationJournal{}
+	_ CreationJournal = &FileCreationJournal{}
+	_ CreationJournal = &ConfigMapCreationJournal{}
+)
+
+func TestFileCreationJournal(t *testing.T) {
+	pending := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	succeeded := pending.Add(time.Second)
+
+	// newManaged returns a managed resource that started creating an external
+	// resource at the pending time.
+	newManaged := func() *fake.Managed {
+		mg := &fake.Managed{ObjectMeta: metav1.ObjectMeta{UID: "cool-uid"}}
+		meta.SetExternalCreatePending(mg, pending)
+		return mg
+	}
+
+	type args struct {
+		record func() *fake.Managed
+		forget bool
+	}
+	type want struct {
+		recovered    bool
+		externalName string
+		err          error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NothingRecorded": {
+			reason: "Nothing should be recovered if nothing was recorded.",
+			args:   args{},
+			want:   want{},
+		},
+		"RecoverSucceeded": {
+			reason: "A recorded successful creation should be recovered.",
+			args: args{
+				record: func() *fake.Managed {
+					mg := newManaged()
+					meta.SetExternalName(mg, "cool-external")
+					meta.SetExternalCreateSucceeded(mg, succeeded)
+					return mg
+				},
+			},
+			want: want{
+				recovered:    true,
+				externalName: "cool-external",
+			},
+		},
+		"IgnoreStale": {
+			reason: "A record of an earlier creation attempt should not be recovered.",
+			args: args{
+				record: func() *fake.Managed {
+					mg := &fake.Managed{ObjectMeta: metav1.ObjectMeta{UID: "cool-uid"}}
+					meta.SetExternalName(mg, "stale-external")
+					meta.SetExternalCreateSucceeded(mg, pending.Add(-time.Hour))
+					return mg
+				},
+			},
+			want: want{},
+		},
+		"Forgotten": {
+			reason: "Nothing should be recovered once a record is forgotten.",
+			args: args{
+				record: func() *fake.Managed {
+					mg := newManaged()
+					meta.SetExternalCreateSucceeded(mg, succeeded)
+					return mg
+				},
+				forget: true,
+			},
+			want: want{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			j := NewFileCreationJournal(t.TempDir())
+			if tc.args.record != nil {
+				mg := tc.args.record()
+				if err := j.Record(context.Background(), mg); err != nil {
+					t.Fatalf("j.Record(...): %v", err)
+				}
+				if tc.args.forget {
+					if err := j.Forget(context.Background(), mg); err != nil {
+						t.Fatalf("j.Forget(...): %v", err)
+					}
+				}
+			}
+
+			mg := newManaged()
+			recovered, err := j.Recover(context.Background(), mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nj.Recover(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.recovered, recovered); diff != "" {
+				t.Errorf("\n%s\nj.Recover(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.externalName, meta.GetExternalName(mg)); diff != "" {
+				t.Errorf("\n%s\nj.Recover(...): -want external name, +got external name:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(!tc.want.recovered, meta.ExternalCreateIncomplete(mg)); diff != "" {
+				t.Errorf("\n%s\nj.Recover(...): -want incomplete, +got incomplete:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/metrics"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// An instrumentedClient records the duration of calls to an ExternalClient,
+// and the errors they return.
+type instrumentedClient struct {
+	client   ExternalClient
+	kind     schema.GroupVersionKind
+	recorder metrics.Recorder
+}
+
+func (c *instrumentedClient) Observe(ctx context.Context, mg resource.Managed) (o ExternalObservation, err error) {
+	defer c.record(mg, metrics.OperationObserve, time.Now(), &err)
+	return c.client.Observe(ctx, mg)
+}
+
+func (c *instrumentedClient) Create(ctx context.Context, mg resource.Managed) (cr ExternalCreation, err error) {
+	defer c.record(mg, metrics.OperationCreate, time.Now(), &err)
+	return c.client.Create(ctx, mg)
+}
+
+func (c *instrumentedClient) Update(ctx context.Context, mg resource.Managed) (u ExternalUpdate, err error) {
+	defer c.record(mg, metrics.OperationUpdate, time.Now(), &err)
+	return c.client.Update(ctx, mg)
+}
+
+func (c *instrumentedClient) Delete(ctx context.Context, mg resource.Managed) (err error) {
+	defer c.record(mg, metrics.OperationDelete, time.Now(), &err)
+	return c.client.Delete(ctx, mg)
+}
+
+func (c *instrumentedClient) record(mg resource.Managed, op metrics.Operation, started time.Time, err *error) {
+	c.recorder.RecordExternalCall(c.kind, mg, op, time.Since(started))
+	c.recorder.RecordExternalError(c.kind, mg, op, *err)
+}
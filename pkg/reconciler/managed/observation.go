@@ -0,0 +1,182 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// An ObservationCache caches the last observation of a managed resource's
+// external resource, so that reconciles triggered by status-only writes or
+// watch echoes need not observe the external resource again.
+type ObservationCache interface {
+	// Get the cached observation of the supplied managed resource's external
+	// resource. Get returns false if there is no cached observation, or if the
+	// managed resource's desired state changed since it was cached.
+	Get(mg resource.Managed) (ExternalObservation, bool)
+
+	// Set the cached observation of the supplied managed resource's external
+	// resource.
+	Set(mg resource.Managed, o ExternalObservation)
+
+	// Invalidate the cached observation of the supplied managed resource's
+	// external resource, for example because it was changed.
+	Invalidate(mg resource.Managed)
+}
+
+// A NopObservationCache caches nothing.
+type NopObservationCache struct{}
+
+// Get returns false.
+func (NopObservationCache) Get(_ resource.Managed) (ExternalObservation, bool) {
+	return ExternalObservation{}, false
+}
+
+// Set does nothing.
+func (NopObservationCache) Set(_ resource.Managed, _ ExternalObservation) {}
+
+// Invalidate does nothing.
+func (NopObservationCache) Invalidate(_ resource.Managed) {}
+
+// A TTLObservationCacheOption configures a TTLObservationCache.
+type TTLObservationCacheOption func(c *TTLObservationCache)
+
+// WithObservationCacheClock configures the function a TTLObservationCache uses
+// to determine the current time.
+func WithObservationCacheClock(now func() time.Time) TTLObservationCacheOption {
+	return func(c *TTLObservationCache) {
+		c.now = now
+	}
+}
+
+type cachedObservation struct {
+	hash        string
+	observation ExternalObservation
+	expires     time.Time
+}
+
+// A TTLObservationCache caches observations for a fixed TTL. Observations are
+// keyed by the managed resource's UID, and are discarded if the hash of its
+// spec and external name changed since they were cached. Only observations of
+// external resources that exist and are up to date are cached, since any other
+// observation causes the external resource to be changed. Expired observations
+// are swept from the cache at most once per TTL, when an observation is set.
+type TTLObservationCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu           sync.Mutex
+	observations map[types.UID]cachedObservation
+	nextSweep    time.Time
+}
+
+// NewTTLObservationCache returns an ObservationCache that caches observations
+// for the supplied TTL.
+func NewTTLObservationCache(ttl time.Duration, o ...TTLObservationCacheOption) *TTLObservationCache {
+	c := &TTLObservationCache{
+		ttl:          ttl,
+		now:          time.Now,
+		observations: make(map[types.UID]cachedObservation),
+	}
+	for _, fn := range o {
+		fn(c)
+	}
+	return c
+}
+
+// Get the cached observation of the supplied managed resource's external
+// resource.
+func (c *TTLObservationCache) Get(mg resource.Managed) (ExternalObservation, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	co, ok := c.observations[mg.GetUID()]
+	if !ok {
+		return ExternalObservation{}, false
+	}
+	if c.now().After(co.expires) || meta.WasDeleted(mg) || co.hash != desiredStateHash(mg) {
+		delete(c.observations, mg.GetUID())
+		return ExternalObservation{}, false
+	}
+	return co.observation, true
+}
+
+// Set the cached observation of the supplied managed resource's external
+// resource, if it exists and is up to date. The cached observation never
+// reports that the managed resource was late initialized; the fields it
+// late initialized are persisted by the reconcile that observed them.
+func (c *TTLObservationCache) Set(mg resource.Managed, o ExternalObservation) {
+	if !o.ResourceExists || !o.ResourceUpToDate || meta.WasDeleted(mg) {
+		c.Invalidate(mg)
+		return
+	}
+	o.ResourceLateInitialized = false
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	c.observations[mg.GetUID()] = cachedObservation{
+		hash:        desiredStateHash(mg),
+		observation: o,
+		expires:     now.Add(c.ttl),
+	}
+	if now.After(c.nextSweep) {
+		c.sweep(now)
+	}
+}
+
+// sweep removes expired observations, including those of managed resources
+// that no longer exist. The caller must hold c.mu.
+func (c *TTLObservationCache) sweep(now time.Time) {
+	for uid, co := range c.observations {
+		if now.After(co.expires) {
+			delete(c.observations, uid)
+		}
+	}
+	c.nextSweep = now.Add(c.ttl)
+}
+
+// Invalidate the cached observation of the supplied managed resource's
+// external resource.
+func (c *TTLObservationCache) Invalidate(mg resource.Managed) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.observations, mg.GetUID())
+}
+
+// desiredStateHash returns a hash of the supplied managed resource's spec and
+// external name.
+func desiredStateHash(mg resource.Managed) string {
+	h := sha256.New()
+	_, _ = h.Write([]byte(meta.GetExternalName(mg)))
+	if p, err := fieldpath.PaveObject(mg); err == nil {
+		spec, _ := p.GetValue("spec")
+		b, _ := json.Marshal(spec)
+		_, _ = h.Write(b)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
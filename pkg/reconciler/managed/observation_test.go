@@ -0,0 +1,205 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var (
+	_ ObservationCache = NopObservationCache{}
+	_ ObservationCache = &TTLObservationCache{}
+)
+
+func TestTTLObservationCache(t *testing.T) {
+	now := time.Now()
+	upToDate := ExternalObservation{ResourceExists: true, ResourceUpToDate: true}
+
+	named := func(name string) *fake.Managed {
+		mg := &fake.Managed{ObjectMeta: metav1.ObjectMeta{UID: "cool-uid"}}
+		meta.SetExternalName(mg, name)
+		return mg
+	}
+
+	type args struct {
+		set     resource.Managed
+		o       ExternalObservation
+		elapsed time.Duration
+		get     resource.Managed
+	}
+	type want struct {
+		o  ExternalObservation
+		ok bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Cached": {
+			reason: "An up to date observation should be returned within its TTL.",
+			args: args{
+				set:     named("cool"),
+				o:       upToDate,
+				elapsed: 5 * time.Second,
+				get:     named("cool"),
+			},
+			want: want{o: upToDate, ok: true},
+		},
+		"Expired": {
+			reason: "An observation should not be returned after its TTL.",
+			args: args{
+				set:     named("cool"),
+				o:       upToDate,
+				elapsed: time.Minute,
+				get:     named("cool"),
+			},
+			want: want{},
+		},
+		"DesiredStateChanged": {
+			reason: "An observation should not be returned if the managed resource's desired state changed.",
+			args: args{
+				set: named("cool"),
+				o:   upToDate,
+				get: named("other"),
+			},
+			want: want{},
+		},
+		"LateInitialized": {
+			reason: "A cached observation should not report that the managed resource was late initialized, since its late initialized fields were already persisted.",
+			args: args{
+				set: named("cool"),
+				o:   ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true},
+				get: named("cool"),
+			},
+			want: want{o: upToDate, ok: true},
+		},
+		"NotUpToDate": {
+			reason: "Observations of external resources that need to be updated should not be cached.",
+			args: args{
+				set: named("cool"),
+				o:   ExternalObservation{ResourceExists: true},
+				get: named("cool"),
+			},
+			want: want{},
+		},
+		"Deleted": {
+			reason: "An observation should not be returned if the managed resource was deleted.",
+			args: args{
+				set: named("cool"),
+				o:   upToDate,
+				get: func() resource.Managed {
+					mg := named("cool")
+					mg.SetDeletionTimestamp(&metav1.Time{Time: now})
+					return mg
+				}(),
+			},
+			want: want{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			clock := now
+			c := NewTTLObservationCache(10*time.Second, WithObservationCacheClock(func() time.Time { return clock }))
+
+			c.Set(tc.args.set, tc.args.o)
+			clock = clock.Add(tc.args.elapsed)
+			o, ok := c.Get(tc.args.get)
+
+			got := want{o: o, ok: ok}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nc.Get(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTTLObservationCacheSweep(t *testing.T) {
+	now := time.Now()
+	upToDate := ExternalObservation{ResourceExists: true, ResourceUpToDate: true}
+	withUID := func(uid types.UID) *fake.Managed {
+		return &fake.Managed{ObjectMeta: metav1.ObjectMeta{UID: uid}}
+	}
+
+	clock := now
+	c := NewTTLObservationCache(10*time.Second, WithObservationCacheClock(func() time.Time { return clock }))
+
+	// The observation of a is never read again, for example because a was
+	// deleted.
+	c.Set(withUID("a"), upToDate)
+	clock = clock.Add(5 * time.Second)
+	c.Set(withUID("b"), upToDate)
+	clock = clock.Add(6 * time.Second)
+	c.Set(withUID("c"), upToDate)
+
+	want := []types.UID{"b", "c"}
+	got := []types.UID{}
+	for uid := range c.observations {
+		got = append(got, uid)
+	}
+	if diff := cmp.Diff(want, got, cmpopts.SortSlices(func(a, b types.UID) bool { return a < b })); diff != "" {
+		t.Errorf("\nExpired observations should be swept when an observation is set.\nc.Set(...): -want UIDs, +got UIDs:\n%s", diff)
+	}
+}
+
+func TestReconcilerInvalidatesOrphanedObservation(t *testing.T) {
+	now := metav1.Now()
+	c := NewTTLObservationCache(time.Minute)
+	c.Set(&fake.Managed{ObjectMeta: metav1.ObjectMeta{UID: "cool-uid"}}, ExternalObservation{ResourceExists: true, ResourceUpToDate: true})
+
+	m := &fake.Manager{
+		Client: &test.MockClient{
+			MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+				mg := obj.(*fake.Managed)
+				mg.SetUID("cool-uid")
+				mg.SetDeletionTimestamp(&now)
+				mg.SetDeletionPolicy(xpv1.DeletionOrphan)
+				return nil
+			}),
+		},
+		Scheme: fake.SchemeWith(&fake.Managed{}),
+	}
+	r := NewReconciler(m, resource.ManagedKind(fake.GVK(&fake.Managed{})),
+		WithObservationCache(c),
+		WithConnectionPublishers(),
+		WithFinalizer(resource.FinalizerFns{RemoveFinalizerFn: func(_ context.Context, _ resource.Object) error { return nil }}),
+	)
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{}); err != nil {
+		t.Fatalf("r.Reconcile(...): %s", err)
+	}
+
+	if diff := cmp.Diff(0, len(c.observations)); diff != "" {
+		t.Errorf("\nThe observation of a managed resource that is deleted with an Orphan deletion policy should be invalidated.\nr.Reconcile(...): -want observations, +got observations:\n%s", diff)
+	}
+}
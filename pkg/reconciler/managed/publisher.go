@@ -18,41 +18,147 @@ package managed
 
 import (
 	"context"
+	"encoding/json"
+	"reflect"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 )
 
-const errSecretStoreDisabled = "cannot publish to secret store, feature is not enabled"
+const (
+	errSecretStoreDisabled = "cannot publish to secret store, feature is not enabled"
+	errFmtPublishTo        = "cannot publish to %s"
+	errPaveManaged         = "cannot pave managed resource"
+	errFmtGetDetailField   = "cannot get connection detail %q from field %q"
+	errFmtEncodeDetail     = "cannot encode connection detail %q"
+)
+
+// A NamedConnectionPublisher is a ConnectionPublisher with a name, e.g.
+// "Vault". A PublisherChain uses the name to report which publishers failed.
+type NamedConnectionPublisher struct {
+	ConnectionPublisher
+	Name string
+}
+
+// NewNamedConnectionPublisher returns a ConnectionPublisher with the supplied
+// name.
+func NewNamedConnectionPublisher(name string, p ConnectionPublisher) NamedConnectionPublisher {
+	return NamedConnectionPublisher{ConnectionPublisher: p, Name: name}
+}
+
+// publisherName returns the name of the supplied publisher, or the name of its
+// type if it is not a NamedConnectionPublisher.
+func publisherName(p ConnectionPublisher) string {
+	if n, ok := p.(NamedConnectionPublisher); ok {
+		return n.Name
+	}
+	t := reflect.TypeOf(p)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
+// A ConnectionAdopter adopts connection details that were published for a
+// managed resource with a different UID, for example because the managed
+// resource was restored from a backup with a new UID.
+type ConnectionAdopter interface {
+	// AdoptConnection details published for the supplied previous UID.
+	AdoptConnection(ctx context.Context, so resource.ConnectionSecretOwner, previous types.UID) error
+}
+
+// AdoptConnection details published for the supplied previous UID, if the
+// named publisher is a ConnectionAdopter.
+func (p NamedConnectionPublisher) AdoptConnection(ctx context.Context, so resource.ConnectionSecretOwner, previous types.UID) error {
+	a, ok := p.ConnectionPublisher.(ConnectionAdopter)
+	if !ok {
+		return nil
+	}
+	return a.AdoptConnection(ctx, so, previous)
+}
 
 // A PublisherChain chains multiple ManagedPublishers.
 type PublisherChain []ConnectionPublisher
 
-// PublishConnection calls each ConnectionPublisher.PublishConnection serially. It returns the first error it
-// encounters, if any.
+// PublishConnection calls each ConnectionPublisher.PublishConnection serially.
+// A publisher that fails does not prevent subsequent publishers from being
+// called. It returns the errors of all publishers that failed, joined.
+//
+// When the chain has more than one publisher, and the supplied owner has a
+// conditioned status, the chain reports which publishers succeeded and which
+// failed using the ConnectionDetailsPublished condition. The condition is only
+// set to true if it was previously false, to avoid adding it to resources that
+// have never failed to publish.
 func (pc PublisherChain) PublishConnection(ctx context.Context, o resource.ConnectionSecretOwner, c ConnectionDetails) (bool, error) {
 	published := false
+	succeeded := make([]string, 0, len(pc))
+	failed := make([]error, 0, len(pc))
+	errs := make([]error, 0, len(pc))
 	for _, p := range pc {
 		pb, err := p.PublishConnection(ctx, o, c)
 		if err != nil {
-			return published, err
+			errs = append(errs, err)
+			failed = append(failed, errors.Wrapf(err, errFmtPublishTo, publisherName(p)))
+			continue
 		}
+		succeeded = append(succeeded, publisherName(p))
 		if pb {
 			published = true
 		}
 	}
-	return published, nil
+	if len(pc) > 1 {
+		setPublishedCondition(o, succeeded, failed)
+	}
+	return published, errors.Join(errs...)
+}
+
+// setPublishedCondition reports the supplied publisher results using the
+// ConnectionDetailsPublished condition, if the supplied owner has one.
+func setPublishedCondition(o resource.ConnectionSecretOwner, succeeded []string, failed []error) {
+	cd, ok := o.(resource.Conditioned)
+	if !ok {
+		return
+	}
+	switch {
+	case len(failed) == 0:
+		if cd.GetCondition(xpv1.TypeConnectionDetailsPublished).Status == corev1.ConditionFalse {
+			cd.SetConditions(xpv1.ConnectionDetailsPublished())
+		}
+	case len(succeeded) == 0:
+		cd.SetConditions(xpv1.ConnectionDetailsPublishFailed(errors.Join(failed...)))
+	default:
+		cd.SetConditions(xpv1.ConnectionDetailsPartiallyPublished(succeeded, errors.Join(failed...)))
+	}
 }
 
-// UnpublishConnection calls each ConnectionPublisher.UnpublishConnection serially. It returns the first error it
-// encounters, if any.
+// UnpublishConnection calls each ConnectionPublisher.UnpublishConnection
+// serially. A publisher that fails does not prevent subsequent publishers from
+// being called. It returns the errors of all publishers that failed, joined.
 func (pc PublisherChain) UnpublishConnection(ctx context.Context, o resource.ConnectionSecretOwner, c ConnectionDetails) error {
+	errs := make([]error, 0, len(pc))
+	for _, p := range pc {
+		errs = append(errs, p.UnpublishConnection(ctx, o, c))
+	}
+	return errors.Join(errs...)
+}
+
+// AdoptConnection calls AdoptConnection on each ConnectionPublisher that is a
+// ConnectionAdopter. A publisher that fails does not prevent subsequent
+// publishers from being called. It returns the errors of all publishers that
+// failed, joined.
+func (pc PublisherChain) AdoptConnection(ctx context.Context, o resource.ConnectionSecretOwner, previous types.UID) error {
+	errs := make([]error, 0, len(pc))
 	for _, p := range pc {
-		if err := p.UnpublishConnection(ctx, o, c); err != nil {
-			return err
+		if a, ok := p.(ConnectionAdopter); ok {
+			errs = append(errs, a.AdoptConnection(ctx, o, previous))
 		}
 	}
-	return nil
+	return errors.Join(errs...)
 }
 
 // DisabledSecretStoreManager is a connection details manager that returns a proper
@@ -77,3 +183,45 @@ func (m *DisabledSecretStoreManager) UnpublishConnection(_ context.Context, so r
 	}
 	return nil
 }
+
+// A ConnectionDetailFieldPath derives a connection detail from a field of a
+// managed resource.
+type ConnectionDetailFieldPath struct {
+	// Key of the connection detail, e.g. "host".
+	Key string
+
+	// FieldPath of the field, e.g. "status.atProvider.endpoint".
+	FieldPath string
+}
+
+// ConnectionDetailsFromFieldPaths derives connection details from the supplied
+// fields of the supplied managed resource. Fields that are not set are
+// omitted. String fields are used verbatim, while other fields are JSON
+// encoded; e.g. the number 5432 becomes "5432".
+func ConnectionDetailsFromFieldPaths(mg resource.Managed, fps ...ConnectionDetailFieldPath) (ConnectionDetails, error) {
+	p, err := fieldpath.PaveObject(mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errPaveManaged)
+	}
+
+	cd := ConnectionDetails{}
+	for _, fp := range fps {
+		v, err := p.GetValue(fp.FieldPath)
+		if fieldpath.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtGetDetailField, fp.Key, fp.FieldPath)
+		}
+		if s, ok := v.(string); ok {
+			cd[fp.Key] = []byte(s)
+			continue
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtEncodeDetail, fp.Key)
+		}
+		cd[fp.Key] = b
+	}
+	return cd, nil
+}
@@ -21,6 +21,8 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
@@ -44,6 +46,7 @@ func TestPublisherChain(t *testing.T) {
 	type want struct {
 		err       error
 		published bool
+		condition xpv1.Condition
 	}
 
 	errBoom := errors.New("boom")
@@ -101,6 +104,83 @@ func TestPublisherChain(t *testing.T) {
 				err: errBoom,
 			},
 		},
+		"SomePublishersReturnError": {
+			p: PublisherChain{
+				NewNamedConnectionPublisher("Vault", ConnectionPublisherFns{
+					PublishConnectionFn: func(_ context.Context, o resource.ConnectionSecretOwner, c ConnectionDetails) (bool, error) {
+						return false, errBoom
+					},
+				}),
+				NewNamedConnectionPublisher("Secret", ConnectionPublisherFns{
+					PublishConnectionFn: func(_ context.Context, o resource.ConnectionSecretOwner, c ConnectionDetails) (bool, error) {
+						return true, nil
+					},
+				}),
+				NewNamedConnectionPublisher("Plugin", ConnectionPublisherFns{
+					PublishConnectionFn: func(_ context.Context, o resource.ConnectionSecretOwner, c ConnectionDetails) (bool, error) {
+						return false, errors.New("bang")
+					},
+				}),
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  &fake.Managed{},
+				c:   ConnectionDetails{},
+			},
+			want: want{
+				err:       errors.Join(errBoom, errors.New("bang")),
+				published: true,
+				condition: xpv1.ConnectionDetailsPartiallyPublished([]string{"Secret"}, errors.Join(
+					errors.Wrapf(errBoom, errFmtPublishTo, "Vault"),
+					errors.Wrapf(errors.New("bang"), errFmtPublishTo, "Plugin"),
+				)),
+			},
+		},
+		"AllPublishersReturnError": {
+			p: PublisherChain{
+				NewNamedConnectionPublisher("Vault", ConnectionPublisherFns{
+					PublishConnectionFn: func(_ context.Context, o resource.ConnectionSecretOwner, c ConnectionDetails) (bool, error) {
+						return false, errBoom
+					},
+				}),
+				&DisabledSecretStoreManager{},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg: &fake.Managed{ConnectionDetailsPublisherTo: fake.ConnectionDetailsPublisherTo{
+					To: &xpv1.PublishConnectionDetailsTo{Name: "cool"},
+				}},
+				c: ConnectionDetails{},
+			},
+			want: want{
+				err: errors.Join(errBoom, errors.New(errSecretStoreDisabled)),
+				condition: xpv1.ConnectionDetailsPublishFailed(errors.Join(
+					errors.Wrapf(errBoom, errFmtPublishTo, "Vault"),
+					errors.Wrapf(errors.New(errSecretStoreDisabled), errFmtPublishTo, "DisabledSecretStoreManager"),
+				)),
+			},
+		},
+		"RecoveredPublishers": {
+			p: PublisherChain{
+				NewNamedConnectionPublisher("Vault", ConnectionPublisherFns{
+					PublishConnectionFn: func(_ context.Context, o resource.ConnectionSecretOwner, c ConnectionDetails) (bool, error) {
+						return true, nil
+					},
+				}),
+				&DisabledSecretStoreManager{},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg: &fake.Managed{ConditionedStatus: xpv1.ConditionedStatus{
+					Conditions: []xpv1.Condition{xpv1.ConnectionDetailsPublishFailed(errBoom)},
+				}},
+				c: ConnectionDetails{},
+			},
+			want: want{
+				published: true,
+				condition: xpv1.ConnectionDetailsPublished(),
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -112,6 +192,14 @@ func TestPublisherChain(t *testing.T) {
 			if diff := cmp.Diff(tc.want.published, got); diff != "" {
 				t.Errorf("Publish(...): -wantPublished, +gotPublished:\n%s", diff)
 			}
+			want := tc.want.condition
+			if want.Type == "" {
+				// GetCondition returns an unknown condition if it isn't set.
+				want = xpv1.Condition{Type: xpv1.TypeConnectionDetailsPublished, Status: corev1.ConditionUnknown}
+			}
+			if diff := cmp.Diff(want, tc.args.mg.GetCondition(xpv1.TypeConnectionDetailsPublished), test.EquateConditions()); diff != "" {
+				t.Errorf("Publish(...): -wantCondition, +gotCondition:\n%s", diff)
+			}
 		})
 	}
 }
@@ -203,3 +291,68 @@ func TestDisabledSecretStoreUnpublish(t *testing.T) {
 		})
 	}
 }
+
+func TestConnectionDetailsFromFieldPaths(t *testing.T) {
+	mg := &fake.Managed{ObjectMeta: metav1.ObjectMeta{
+		Name:       "cool",
+		Generation: 5432,
+		Labels:     map[string]string{"cool": "very"},
+	}}
+
+	type args struct {
+		mg  resource.Managed
+		fps []ConnectionDetailFieldPath
+	}
+	type want struct {
+		cd  ConnectionDetails
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"InvalidFieldPath": {
+			reason: "An error should be returned if a field path is invalid.",
+			args: args{
+				mg:  mg,
+				fps: []ConnectionDetailFieldPath{{Key: "host", FieldPath: "metadata..name"}},
+			},
+			want: want{
+				err: errors.Wrapf(errors.Wrap(errors.New("unexpected '.' at position 9"), "cannot parse path \"metadata..name\""), errFmtGetDetailField, "host", "metadata..name"),
+			},
+		},
+		"Derived": {
+			reason: "Connection details should be derived from fields that are set, encoding non-string values as JSON.",
+			args: args{
+				mg: mg,
+				fps: []ConnectionDetailFieldPath{
+					{Key: "host", FieldPath: "objectMeta.name"},
+					{Key: "port", FieldPath: "objectMeta.generation"},
+					{Key: "labels", FieldPath: "objectMeta.labels"},
+					{Key: "missing", FieldPath: "status.atProvider.endpoint"},
+				},
+			},
+			want: want{
+				cd: ConnectionDetails{
+					"host":   []byte("cool"),
+					"port":   []byte("5432"),
+					"labels": []byte(`{"cool":"very"}`),
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ConnectionDetailsFromFieldPaths(tc.args.mg, tc.args.fps...)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nConnectionDetailsFromFieldPaths(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cd, got); diff != "" {
+				t.Errorf("\n%s\nConnectionDetailsFromFieldPaths(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
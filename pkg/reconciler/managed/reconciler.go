@@ -18,20 +18,31 @@ package managed
 
 import (
 	"context"
+	"runtime/pprof"
 	"strings"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/cloudevent"
+	"github.com/crossplane/crossplane-runtime/pkg/diagnostics"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/feature"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/metrics"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/shard"
 )
 
 const (
@@ -44,6 +55,12 @@ const (
 
 	defaultpollInterval = 1 * time.Minute
 	defaultGracePeriod  = 30 * time.Second
+
+	// Errors with these codes are retried after a fixed wait, rather than
+	// with exponential backoff.
+	throttledWait    = 30 * time.Second
+	unauthorizedWait = 2 * time.Minute
+	invalidInputWait = 5 * time.Minute
 )
 
 // Error strings.
@@ -58,6 +75,12 @@ const (
 	errReconcileDelete          = "delete failed"
 	errManagementPolicy         = "managementPolicy is set to a non-default value but the feature is not enabled."
 	errExternalResourceNotExist = "external resource does not exist"
+	errFeatureScope             = "cannot determine whether management policies are enabled"
+	errLateInitConflict         = "late initialized fields were changed in both the spec and the external system"
+	errAdoptRestored            = "cannot adopt external resource of restored managed resource"
+	errAdoptConnection          = "cannot adopt connection details of previous managed resource"
+
+	errFmtExternalCallBudget = "reconcile made %d external API calls, exceeding its budget of %d"
 )
 
 // Event reasons.
@@ -74,13 +97,22 @@ const (
 	reasonCannotUpdate               event.Reason = "CannotUpdateExternalResource"
 	reasonCannotUpdateManaged        event.Reason = "CannotUpdateManagedResource"
 	reasonManagementPolicyNotEnabled event.Reason = "CannotUseManagementPolicy"
-
-	reasonDeleted event.Reason = "DeletedExternalResource"
-	reasonCreated event.Reason = "CreatedExternalResource"
-	reasonUpdated event.Reason = "UpdatedExternalResource"
-	reasonPending event.Reason = "PendingExternalResource"
+	reasonLateInitConflict           event.Reason = "LateInitConflict"
+	reasonCannotAdopt                event.Reason = "CannotAdoptRestoredResource"
+	reasonCannotAdoptConnection      event.Reason = "CannotAdoptConnectionDetails"
+
+	reasonDeleted           event.Reason = "DeletedExternalResource"
+	reasonCreated           event.Reason = "CreatedExternalResource"
+	reasonUpdated           event.Reason = "UpdatedExternalResource"
+	reasonPending           event.Reason = "PendingExternalResource"
+	reasonAdopted           event.Reason = "AdoptedRestoredResource"
+	reasonAdoptedConnection event.Reason = "AdoptedConnectionDetails"
+	reasonPlanned           event.Reason = "PlannedExternalResourceChange"
+	reasonDrifted           event.Reason = "ExternalResourceNotUpToDate"
+	reasonRenamed           event.Reason = "ChangedExternalName"
 
 	reasonReconciliationPaused event.Reason = "ReconciliationPaused"
+	reasonExternalCallBudget   event.Reason = "ExceededExternalCallBudget"
 )
 
 // ControllerName returns the recommended name for controllers that use this
@@ -154,7 +186,8 @@ type Initializer interface {
 type InitializerChain []Initializer
 
 // Initialize calls each Initializer serially. It returns the first
-// error it encounters, if any.
+// error it encounters, if any. An Initializer may return StopInitialization
+// to stop the chain without reporting an error.
 func (cc InitializerChain) Initialize(ctx context.Context, mg resource.Managed) error {
 	for _, c := range cc {
 		if err := c.Initialize(ctx, mg); err != nil {
@@ -164,6 +197,69 @@ func (cc InitializerChain) Initialize(ctx context.Context, mg resource.Managed)
 	return nil
 }
 
+// An InitializerPredicate returns true if an Initializer should run for the
+// supplied managed resource.
+type InitializerPredicate func(mg resource.Managed) bool
+
+// NoExternalName returns true if the supplied managed resource has no
+// external name.
+func NoExternalName() InitializerPredicate {
+	return func(mg resource.Managed) bool {
+		return meta.GetExternalName(mg) == ""
+	}
+}
+
+// A ConditionalInitializer runs an Initializer only if all of its predicates
+// are true.
+type ConditionalInitializer struct {
+	Initializer
+	predicates []InitializerPredicate
+}
+
+// NewConditionalInitializer returns an Initializer that runs the supplied
+// Initializer only if all of the supplied predicates are true, for example
+// NewConditionalInitializer(NewNameAsExternalName(c), NoExternalName()).
+func NewConditionalInitializer(i Initializer, p ...InitializerPredicate) *ConditionalInitializer {
+	return &ConditionalInitializer{Initializer: i, predicates: p}
+}
+
+// Initialize the supplied managed resource if all predicates are true.
+func (ci *ConditionalInitializer) Initialize(ctx context.Context, mg resource.Managed) error {
+	for _, p := range ci.predicates {
+		if !p(mg) {
+			return nil
+		}
+	}
+	return ci.Initializer.Initialize(ctx, mg)
+}
+
+// An InitializationStopped error is returned by an Initializer that wants to
+// stop initialization without reporting an error, for example because it is
+// waiting for something to happen before it can finish initializing the
+// managed resource. The Reconciler requeues the managed resource rather than
+// proceeding to observe the external resource.
+type InitializationStopped struct {
+	// Reason initialization stopped.
+	Reason string
+
+	// RequeueAfter is how long to wait before reconciling the managed
+	// resource again. The managed resource is requeued with backoff if it is
+	// zero.
+	RequeueAfter time.Duration
+}
+
+// Error returns the reason initialization stopped.
+func (e *InitializationStopped) Error() string {
+	return "initialization stopped: " + e.Reason
+}
+
+// StopInitialization returns an error that stops an InitializerChain and
+// causes the Reconciler to requeue the managed resource after the supplied
+// duration, or with backoff if it is zero.
+func StopInitialization(reason string, requeueAfter time.Duration) error {
+	return &InitializationStopped{Reason: reason, RequeueAfter: requeueAfter}
+}
+
 // A InitializerFn is a function that satisfies the Initializer
 // interface.
 type InitializerFn func(ctx context.Context, mg resource.Managed) error
@@ -396,6 +492,12 @@ type ExternalObservation struct {
 	// resource every time they are called.
 	ResourceLateInitialized bool
 
+	// LateInitConflicts are late initialized fields that have since been
+	// changed both in the managed resource's spec and in the external system.
+	// Crossplane reports them using a warning event and the LateInitialized
+	// condition. See resource.LateInitializer for how to detect them.
+	LateInitConflicts []resource.LateInitConflict
+
 	// ConnectionDetails required to connect to this resource. These details
 	// are a set that is collated throughout the managed resource's lifecycle -
 	// i.e. returning new connection details will have no affect on old details
@@ -408,6 +510,19 @@ type ExternalObservation struct {
 	// finding where the observed diverges from the desired state.
 	// The string should be a cmp.Diff that details the difference.
 	Diff string
+
+	// ExternalName is the name the external resource should be known by, if
+	// it differs from the managed resource's external name. For example an
+	// external API may return a canonical ID for a resource that was observed
+	// by an alias. If set, the managed resource's external name is changed,
+	// its previous external name is recorded in its external name history,
+	// and it is requeued to be observed by its new name.
+	ExternalName string
+
+	// NotUpToDateReasons are human-readable reasons the external resource is
+	// not up to date, e.g. "tags differ". Crossplane reports them using an
+	// event and the Synced condition when it updates the external resource.
+	NotUpToDateReasons []string
 }
 
 // An ExternalCreation is the result of the creation of an external resource.
@@ -446,12 +561,23 @@ type ExternalUpdate struct {
 // for which it is responsible.
 type Reconciler struct {
 	client     client.Client
+	apiReader  client.Reader
+	kind       schema.GroupVersionKind
 	newManaged func() resource.Managed
 
+	// fieldManager to which the Reconciler attributes its writes.
+	fieldManager string
+
 	pollInterval              time.Duration
 	timeout                   time.Duration
 	creationGracePeriod       time.Duration
 	managementPoliciesEnabled bool
+	operationTracking         bool
+
+	// features and managementPoliciesFlag determine whether management
+	// policies are enabled for a particular managed resource.
+	features               *feature.Flags
+	managementPoliciesFlag feature.Flag
 
 	// The below structs embed the set of interfaces used to implement the
 	// managed resource reconciler. We do this primarily for readability, so
@@ -460,8 +586,47 @@ type Reconciler struct {
 	external mrExternal
 	managed  mrManaged
 
-	log    logging.Logger
-	record event.Recorder
+	// initializers that are run after the managed resource's initializers.
+	// They're built once the Reconciler's client is known, so that their
+	// writes are attributed to its field manager.
+	initializers []func(c client.Client) Initializer
+
+	translator ErrorTranslator
+
+	log       logging.Logger
+	record    event.Recorder
+	metrics   metrics.Recorder
+	lifecycle cloudevent.Emitter
+
+	// conflictBackoff is used to retry status updates that conflict with
+	// a concurrent write to the managed resource.
+	conflictBackoff wait.Backoff
+
+	// journal durably records the outcome of external resource creation.
+	journal CreationJournal
+
+	// observations caches observations of external resources.
+	observations ObservationCache
+
+	// inFlight tracks external calls so they can be superseded.
+	inFlight *InFlightTracker
+
+	// shard of managed resources to reconcile. All are reconciled if nil.
+	shard *shard.Shard
+
+	// health checks the health of external resources.
+	health *healthChecks
+
+	// connectionFieldPaths derive connection details from fields of the
+	// managed resource.
+	connectionFieldPaths []ConnectionDetailFieldPath
+
+	// budget splits the timeout among the phases of a reconcile.
+	budget Budget
+
+	// callBudget is the number of external API calls a single reconcile may
+	// make before a warning is emitted. Zero means no budget.
+	callBudget int
 }
 
 type mrManaged struct {
@@ -472,17 +637,28 @@ type mrManaged struct {
 	ReferenceResolver
 }
 
-func defaultMRManaged(m manager.Manager) mrManaged {
-	return mrManaged{
-		CriticalAnnotationUpdater: NewRetryingCriticalAnnotationUpdater(m.GetClient()),
-		Finalizer:                 resource.NewAPIFinalizer(m.GetClient(), FinalizerName),
-		Initializer:               NewNameAsExternalName(m.GetClient()),
-		ReferenceResolver:         NewAPISimpleReferenceResolver(m.GetClient()),
-		ConnectionPublisher: PublisherChain([]ConnectionPublisher{
-			NewAPISecretPublisher(m.GetClient(), m.GetScheme()),
+// defaultMRManaged returns the supplied mrManaged with any unset interfaces
+// set to their defaults, which use the supplied client.
+func defaultMRManaged(c client.Client, s *runtime.Scheme, m mrManaged) mrManaged {
+	if m.CriticalAnnotationUpdater == nil {
+		m.CriticalAnnotationUpdater = NewRetryingCriticalAnnotationUpdater(c)
+	}
+	if m.Finalizer == nil {
+		m.Finalizer = resource.NewFinalizerChain(c, resource.FinalizerStep{Name: FinalizerName})
+	}
+	if m.Initializer == nil {
+		m.Initializer = NewNameAsExternalName(c)
+	}
+	if m.ReferenceResolver == nil {
+		m.ReferenceResolver = NewAPISimpleReferenceResolver(c)
+	}
+	if m.ConnectionPublisher == nil {
+		m.ConnectionPublisher = PublisherChain([]ConnectionPublisher{
+			NewAPISecretPublisher(c, s),
 			&DisabledSecretStoreManager{},
-		}),
+		})
 	}
+	return m
 }
 
 type mrExternal struct {
@@ -519,6 +695,101 @@ func WithPollInterval(after time.Duration) ReconcilerOption {
 	}
 }
 
+// WithStatusConflictBackoff configures the backoff used to retry status
+// updates that conflict with a concurrent write to the managed resource. When
+// an update conflicts the latest version of the managed resource is read, the
+// reconciler's status is applied to it, and the update is retried. Pass a
+// backoff with one step to disable retries.
+func WithStatusConflictBackoff(b wait.Backoff) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.conflictBackoff = b
+	}
+}
+
+// WithCreationJournal configures a CreationJournal used to durably record the
+// outcome of creating an external resource before it is recorded using the
+// managed resource's critical annotations. This allows the Reconciler to
+// recover if it crashes or can't persist the annotations after creating an
+// external resource. By default nothing is journalled, and the Reconciler
+// refuses to proceed if it can't determine the outcome of a creation.
+func WithCreationJournal(j CreationJournal) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.journal = j
+	}
+}
+
+// WithObservationCache configures an ObservationCache used to avoid observing
+// an external resource that was recently observed to exist and be up to date,
+// for example when a reconcile is triggered by a status-only write or a watch
+// echo. By default nothing is cached.
+func WithObservationCache(c ObservationCache) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.observations = c
+	}
+}
+
+// WithShard configures the Reconciler to reconcile only the managed resources
+// in the supplied shard. Requests for managed resources in other shards are
+// ignored, so that they're reconciled only by the replica that owns their
+// shard even if they're enqueued by watches that don't filter by shard, e.g.
+// watches of ProviderConfigs or credentials Secrets.
+func WithShard(s shard.Shard) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.shard = &s
+	}
+}
+
+// WithInFlightTracker configures the InFlightTracker the Reconciler uses to
+// track its external calls. Add the tracker's predicate to the watch of the
+// managed resource to cancel external calls that are superseded. By default
+// external calls are tracked but never superseded.
+func WithInFlightTracker(t *InFlightTracker) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.inFlight = t
+	}
+}
+
+// WithHealthChecker configures the Reconciler to check the health of external
+// resources that exist using the supplied HealthChecker, at most once per
+// supplied interval. The outcome is reported using the Healthy condition,
+// which is separate from the Ready condition. A failed health check is not a
+// reconcile error.
+func WithHealthChecker(h HealthChecker, interval time.Duration) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.health = newHealthChecks(h, interval)
+	}
+}
+
+// WithConnectionDetailFieldPaths configures the Reconciler to derive connection
+// details from the supplied fields of the managed resource, for example the
+// 'host' key from 'status.atProvider.endpoint'. Derived details are published
+// along with those returned by the ExternalClient, which take precedence.
+func WithConnectionDetailFieldPaths(fps ...ConnectionDetailFieldPath) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.connectionFieldPaths = fps
+	}
+}
+
+// WithBudget configures the Reconciler to split its timeout among the phases
+// of a reconcile according to the supplied Budget. Note that the Connect phase
+// deadline applies to the context passed to ExternalConnecter.Connect, so
+// external clients must not retain that context.
+func WithBudget(b Budget) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.budget = b
+	}
+}
+
+// WithExternalCallBudget configures the Reconciler to warn when a single
+// reconcile makes more than the supplied number of external API calls, which
+// often points to an N+1 call pattern in a provider. Calls are counted using
+// the context passed to the ExternalClient; see metrics.CountCall.
+func WithExternalCallBudget(n int) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.callBudget = n
+	}
+}
+
 // WithCreationGracePeriod configures an optional period during which we will
 // wait for the external API to report that a newly created external resource
 // exists. This allows us to tolerate eventually consistent APIs that do not
@@ -546,6 +817,18 @@ func WithExternalConnectDisconnecter(c ExternalConnectDisconnecter) ReconcilerOp
 	}
 }
 
+// WithFieldManager specifies the field manager to which the Reconciler
+// attributes its writes, including those made by its default finalizer,
+// initializer, reference resolver, and connection publisher. Use
+// resource.FieldManagerName to derive one from the provider name and the
+// managed resource kind. Writes are attributed to the client's default field
+// manager if none is specified.
+func WithFieldManager(name string) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.fieldManager = name
+	}
+}
+
 // WithCriticalAnnotationUpdater specifies how the Reconciler should update a
 // managed resource's critical annotations. Implementations typically contain
 // some kind of retry logic to increase the likelihood that critical annotations
@@ -572,6 +855,59 @@ func WithInitializers(i ...Initializer) ReconcilerOption {
 	}
 }
 
+// WithOwnerMetadataPropagation configures the Reconciler to propagate labels
+// and annotations allowed by the supplied policies from the controller of each
+// managed resource (e.g. its composite resource) to the managed resource.
+// Propagation runs after any initializers. The provider must be allowed to get
+// the controllers of its managed resources. Use meta.NewPropagationPolicy to
+// build the policies.
+func WithOwnerMetadataPropagation(labels, annotations meta.PropagationPolicy) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.initializers = append(r.initializers, func(c client.Client) Initializer {
+			return NewOwnerMetadataPropagator(c, labels, annotations)
+		})
+	}
+}
+
+// WithOwnerLabels configures the Reconciler to label each managed resource with
+// the name and UID of its composite resource, and the name, namespace, and UID
+// of its claim, so that the managed resources of a claim can be selected by
+// label. Labelling runs after any initializers. The provider must be allowed
+// to get the composite resources and claims of its managed resources.
+func WithOwnerLabels() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.initializers = append(r.initializers, func(c client.Client) Initializer {
+			return NewOwnerLabeler(c)
+		})
+	}
+}
+
+// WithExternalTagger configures the Reconciler to add the tags returned by the
+// supplied ExternalTagger to a string map at the supplied field path of each
+// managed resource, for example spec.forProvider.tags. Tags that are already
+// set are not overwritten. Tagging runs after any initializers. Use a
+// resource.ExternalTaggerChain that includes resource.DefaultExternalTagger to
+// add tags to the default, identifying tags.
+func WithExternalTagger(t resource.ExternalTagger, fieldPath string) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.initializers = append(r.initializers, func(c client.Client) Initializer {
+			return NewExternalTagsInitializer(c, t, fieldPath)
+		})
+	}
+}
+
+// WithProviderConfigFallback configures the Reconciler to default the
+// ProviderConfigRef of managed resources that don't reference a ProviderConfig
+// to their namespace's default ProviderConfig, or failing that the cluster's
+// default ProviderConfig. Defaulting runs after any initializers.
+func WithProviderConfigFallback(o ...resource.ProviderConfigResolverOption) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.initializers = append(r.initializers, func(c client.Client) Initializer {
+			return NewProviderConfigFallback(c, o...)
+		})
+	}
+}
+
 // WithFinalizer specifies how the Reconciler should add and remove
 // finalizers to and from the managed resource.
 func WithFinalizer(f resource.Finalizer) ReconcilerOption {
@@ -588,6 +924,23 @@ func WithReferenceResolver(rr ReferenceResolver) ReconcilerOption {
 	}
 }
 
+// WithMetricRecorder specifies how the Reconciler should record metrics about
+// the managed resources it reconciles.
+func WithMetricRecorder(m metrics.Recorder) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.metrics = m
+	}
+}
+
+// WithLifecycleEmitter specifies how the Reconciler should emit events when
+// the managed resources it reconciles are created, become ready, drift from
+// their desired state, or are deleted.
+func WithLifecycleEmitter(e cloudevent.Emitter) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.lifecycle = e
+	}
+}
+
 // WithLogger specifies how the Reconciler should log messages.
 func WithLogger(l logging.Logger) ReconcilerOption {
 	return func(r *Reconciler) {
@@ -602,6 +955,29 @@ func WithRecorder(er event.Recorder) ReconcilerOption {
 	}
 }
 
+// WithErrorTranslator specifies how the Reconciler should translate errors
+// returned by its ExternalConnecter and ExternalClient. Translated errors
+// determine the Synced condition and when the managed resource is requeued -
+// e.g. errors translated to errors.CodeTerminal are not requeued, and errors
+// translated to errors.CodeThrottled are requeued after a fixed wait.
+func WithErrorTranslator(t ErrorTranslator) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.translator = t
+	}
+}
+
+// WithOperationTracking enables tracking of external update and delete
+// operations. When enabled the Reconciler records when each update and delete
+// was started, succeeded, or failed - and why it failed - using the
+// annotations documented in the meta package. Note that this causes an
+// additional update of the managed resource after each external update or
+// delete.
+func WithOperationTracking() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.operationTracking = true
+	}
+}
+
 // WithManagementPolicies enables support for management policies.
 func WithManagementPolicies() ReconcilerOption {
 	return func(r *Reconciler) {
@@ -609,6 +985,18 @@ func WithManagementPolicies() ReconcilerOption {
 	}
 }
 
+// WithScopedManagementPolicies enables support for management policies for
+// managed resources that the supplied feature flag is enabled for. Unlike
+// WithManagementPolicies the flag is evaluated each reconcile, so management
+// policies may be enabled or disabled at runtime, and may be rolled out to only
+// some managed resources using Flags.SetScope.
+func WithScopedManagementPolicies(fs *feature.Flags, f feature.Flag) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.features = fs
+		r.managementPoliciesFlag = f
+	}
+}
+
 // NewReconciler returns a Reconciler that reconciles managed resources of the
 // supplied ManagedKind with resources in an external system such as a cloud
 // provider API. It panics if asked to reconcile a managed resource kind that is
@@ -627,20 +1015,43 @@ func NewReconciler(m manager.Manager, of resource.ManagedKind, o ...ReconcilerOp
 
 	r := &Reconciler{
 		client:              m.GetClient(),
+		apiReader:           m.GetAPIReader(),
+		kind:                schema.GroupVersionKind(of),
 		newManaged:          nm,
 		pollInterval:        defaultpollInterval,
 		creationGracePeriod: defaultGracePeriod,
 		timeout:             reconcileTimeout,
-		managed:             defaultMRManaged(m),
 		external:            defaultMRExternal(),
+		translator:          NopErrorTranslator{},
 		log:                 logging.NewNopLogger(),
 		record:              event.NewNopRecorder(),
+		metrics:             metrics.NewNopRecorder(),
+		lifecycle:           cloudevent.NewNopEmitter(),
+		conflictBackoff:     resource.DefaultConflictBackoff,
+		journal:             NopCreationJournal{},
+		observations:        NopObservationCache{},
+		inFlight:            NewInFlightTracker(),
 	}
 
 	for _, ro := range o {
 		ro(r)
 	}
 
+	// Default the managed interfaces and build the initializers after
+	// applying our options, so that they use the field managed client.
+	if r.fieldManager != "" {
+		r.client = resource.NewFieldManagedClient(r.client, r.fieldManager)
+	}
+	r.managed = defaultMRManaged(r.client, m.GetScheme(), r.managed)
+
+	if len(r.initializers) > 0 {
+		ic := InitializerChain{r.managed.Initializer}
+		for _, fn := range r.initializers {
+			ic = append(ic, fn(r.client))
+		}
+		r.managed.Initializer = ic
+	}
+
 	return r
 }
 
@@ -652,13 +1063,19 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	log := r.log.WithValues("request", req)
 	log.Debug("Reconciling")
 
+	// Label this goroutine, and any it starts, so that profiles can be broken
+	// down by the kind of managed resource being reconciled.
+	pprof.SetGoroutineLabels(pprof.WithLabels(ctx, pprof.Labels(diagnostics.LabelKeyGVK, r.kind.String())))
+	defer pprof.SetGoroutineLabels(ctx)
+
 	ctx, cancel := context.WithTimeout(ctx, r.timeout+reconcileGracePeriod)
 	defer cancel()
 
-	// Govet linter has a check for lost cancel funcs but it's a false positive
-	// for child contexts as because parent's cancel is called, so we skip it
-	// for this line.
-	externalCtx, _ := context.WithTimeout(ctx, r.timeout) //nolint:govet // See note above.
+	// Count the external API calls made during this reconcile.
+	ctx = metrics.WithCallCounter(ctx)
+
+	externalCtx, externalCancel := context.WithTimeout(ctx, r.timeout)
+	defer externalCancel()
 
 	managed := r.newManaged()
 	if err := r.client.Get(ctx, req.NamespacedName, managed); err != nil {
@@ -668,12 +1085,28 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetManaged)
 	}
 
+	// The managed resource belongs to another replica's shard. We must check
+	// this here rather than relying on watch predicates alone, since some
+	// watches enqueue managed resources of every shard.
+	if r.shard != nil && !r.shard.Contains(managed) {
+		log.Debug("Ignoring managed resource in another shard", "shard", r.shard.String())
+		return reconcile.Result{}, nil
+	}
+
+	// Cancel our external calls if the managed resource is deleted or its
+	// spec changes while they're in flight. We'll be requeued to handle the
+	// change.
+	externalCtx, externalDone := r.inFlight.Track(externalCtx, managed)
+	defer externalDone()
+
+	previousReady := managed.GetCondition(xpv1.TypeReady)
 	record := r.record.WithAnnotations("external-name", meta.GetExternalName(managed))
 	log = log.WithValues(
 		"uid", managed.GetUID(),
 		"version", managed.GetResourceVersion(),
 		"external-name", meta.GetExternalName(managed),
 	)
+	defer r.recordCalls(ctx, log, managed)
 
 	// Check the pause annotation and return if it has the value "true"
 	// after logging, publishing an event and updating the SYNC status condition
@@ -683,7 +1116,19 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		managed.SetConditions(xpv1.ReconcilePaused())
 		// if the pause annotation is removed, we will have a chance to reconcile again and resume
 		// and if status update fails, we will reconcile again to retry to update the status
-		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+		return reconcile.Result{}, errors.Wrap(r.writeStatus(ctx, managed), errUpdateManagedStatus)
+	}
+
+	managementPoliciesEnabled := r.managementPoliciesEnabled
+	if r.managementPoliciesFlag != "" {
+		enabled, err := r.features.EnabledFor(ctx, r.managementPoliciesFlag, managed)
+		if err != nil {
+			log.Debug(errFeatureScope, "error", err)
+			err = errors.Wrap(err, errFeatureScope)
+			managed.SetConditions(reconcileError(err))
+			return requeueOnError(err), errors.Wrap(r.writeStatus(ctx, managed), errUpdateManagedStatus)
+		}
+		managementPoliciesEnabled = managementPoliciesEnabled || enabled
 	}
 
 	// Check if the ManagementPolicy is set to a non-default value while the
@@ -693,17 +1138,20 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	// not realize that the controller is still trying to reconcile
 	// (and modify or delete) the resource since they forgot to enable the
 	// feature flag.
-	if !r.managementPoliciesEnabled && (managed.GetManagementPolicy() == xpv1.ManagementObserveOnly || managed.GetManagementPolicy() == xpv1.ManagementOrphanOnDelete) {
+	if !managementPoliciesEnabled && !managed.GetManagementPolicy().IsDefault() {
 		log.Debug(errManagementPolicy, "policy", managed.GetManagementPolicy())
 		record.Event(managed, event.Warning(reasonManagementPolicyNotEnabled, errors.New(errManagementPolicy)))
 		managed.SetConditions(xpv1.ReconcileError(errors.New(errManagementPolicy)))
-		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+		return reconcile.Result{}, errors.Wrap(r.writeStatus(ctx, managed), errUpdateManagedStatus)
 	}
 
 	// If managed resource has a deletion timestamp and a deletion policy of
 	// Orphan, we do not need to observe the external resource before attempting
 	// to unpublish connection details and remove finalizer.
-	if meta.WasDeleted(managed) && shouldOrphan(r.managementPoliciesEnabled, managed) {
+	if meta.WasDeleted(managed) && shouldOrphan(managementPoliciesEnabled, managed) {
+		// We won't observe the external resource again, so we won't
+		// otherwise discard its cached observation.
+		r.observations.Invalidate(managed)
 		log = log.WithValues("deletion-timestamp", managed.GetDeletionTimestamp())
 
 		// Empty ConnectionDetails are passed to UnpublishConnection because we
@@ -718,8 +1166,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			// backoff.
 			log.Debug("Cannot unpublish connection details", "error", err)
 			record.Event(managed, event.Warning(reasonCannotUnpublish, err))
-			managed.SetConditions(xpv1.Deleting(), xpv1.ReconcileError(err))
-			return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+			managed.SetConditions(xpv1.Deleting(), reconcileError(err))
+			return requeueOnError(err), errors.Wrap(r.writeStatus(ctx, managed), errUpdateManagedStatus)
 		}
 		if err := r.managed.RemoveFinalizer(ctx, managed); err != nil {
 			// If this is the first time we encounter this issue we'll be
@@ -727,8 +1175,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			// condition. If not, we requeue explicitly, which will trigger
 			// backoff.
 			log.Debug("Cannot remove managed resource finalizer", "error", err)
-			managed.SetConditions(xpv1.Deleting(), xpv1.ReconcileError(err))
-			return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+			managed.SetConditions(xpv1.Deleting(), reconcileError(err))
+			return requeueOnError(err), errors.Wrap(r.writeStatus(ctx, managed), errUpdateManagedStatus)
 		}
 
 		// We've successfully unpublished our managed resource's connection
@@ -736,28 +1184,83 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		// controller that added a finalizer to this resource then it should no
 		// longer exist and thus there is no point trying to update its status.
 		log.Debug("Successfully deleted managed resource")
+		r.metrics.RecordDeleted(r.kind, managed)
+		r.lifecycle.Emit(ctx, cloudevent.TypeDeleted, r.kind, managed)
 		return reconcile.Result{Requeue: false}, nil
 	}
 
 	if err := r.managed.Initialize(ctx, managed); err != nil {
+		stop := &InitializationStopped{}
+		if errors.As(err, &stop) {
+			log.Debug("Stopped initializing managed resource", "reason", stop.Reason, "requeue-after", stop.RequeueAfter)
+			return reconcile.Result{Requeue: true, RequeueAfter: stop.RequeueAfter}, nil
+		}
+
 		// If this is the first time we encounter this issue we'll be requeued
 		// implicitly when we update our status with the new error condition. If
 		// not, we requeue explicitly, which will trigger backoff.
 		log.Debug("Cannot initialize managed resource", "error", err)
 		record.Event(managed, event.Warning(reasonCannotInitialize, err))
-		managed.SetConditions(xpv1.ReconcileError(err))
-		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+		managed.SetConditions(reconcileError(err))
+		return requeueOnError(err), errors.Wrap(r.writeStatus(ctx, managed), errUpdateManagedStatus)
 	}
 
 	// If we started but never completed creation of an external resource we
-	// may have lost critical information. For example if we didn't persist
-	// an updated external name we've leaked a resource. The safest thing to
-	// do is to refuse to proceed.
+	// may have lost critical information. We may be able to recover it from
+	// our creation journal.
+	if meta.ExternalCreateIncomplete(managed) {
+		r.recoverCreation(ctx, log, managed)
+	}
+
+	// If we still can't determine whether creation completed we may have lost
+	// critical information. For example if we didn't persist an updated
+	// external name we've leaked a resource. The safest thing to do is to
+	// refuse to proceed.
 	if meta.ExternalCreateIncomplete(managed) {
 		log.Debug(errCreateIncomplete)
 		record.Event(managed, event.Warning(reasonCannotInitialize, errors.New(errCreateIncomplete)))
 		managed.SetConditions(xpv1.Creating(), xpv1.ReconcileError(errors.New(errCreateIncomplete)))
-		return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+		return reconcile.Result{Requeue: false}, errors.Wrap(r.writeStatus(ctx, managed), errUpdateManagedStatus)
+	}
+
+	// A managed resource that was restored from a backup, for example by
+	// Velero, has a new UID but its external resource already exists. We
+	// adopt the external resource and any connection secrets rather than
+	// creating them again.
+	restored := meta.WasRestored(managed)
+	if restored {
+		if err := r.adoptRestored(ctx, managed); err != nil {
+			// If this is the first time we encounter this issue we'll be
+			// requeued implicitly when we update our status with the new error
+			// condition. If not, we requeue explicitly, which will trigger
+			// backoff.
+			log.Debug("Cannot adopt restored managed resource", "error", err)
+			record.Event(managed, event.Warning(reasonCannotAdopt, err))
+			managed.SetConditions(reconcileError(err))
+			return requeueOnError(err), errors.Wrap(r.writeStatus(ctx, managed), errUpdateManagedStatus)
+		}
+		log.Debug("Adopted external resource of restored managed resource")
+		record.Event(managed, event.Normal(reasonAdopted, "Adopted external resource of restored managed resource"))
+	}
+
+	// A managed resource may take over the external resource of a previous
+	// managed resource, for example one that was deleted with an Orphan
+	// deletion policy. If asked to we transfer ownership of the previous
+	// managed resource's connection secret, which we would otherwise refuse
+	// to overwrite.
+	if previous := meta.GetAdoptConnectionFrom(managed); previous != "" && !meta.WasDeleted(managed) {
+		if err := r.adoptConnection(ctx, managed, previous); err != nil {
+			// If this is the first time we encounter this issue we'll be
+			// requeued implicitly when we update our status with the new error
+			// condition. If not, we requeue explicitly, which will trigger
+			// backoff.
+			log.Debug("Cannot adopt connection details", "error", err, "previous-uid", previous)
+			record.Event(managed, event.Warning(reasonCannotAdoptConnection, err))
+			managed.SetConditions(reconcileError(err))
+			return requeueOnError(err), errors.Wrap(r.writeStatus(ctx, managed), errUpdateManagedStatus)
+		}
+		log.Debug("Adopted connection details of previous managed resource", "previous-uid", previous)
+		record.Event(managed, event.Normal(reasonAdoptedConnection, "Adopted connection details of previous managed resource "+string(previous)))
 	}
 
 	// We resolve any references before observing our external resource because
@@ -770,7 +1273,9 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	// impossible) that we need to resolve a reference in order to process a
 	// delete, and that reference is stale at delete time.
 	if !meta.WasDeleted(managed) {
-		if err := r.managed.ResolveReferences(ctx, managed); err != nil {
+		resolveCtx, resolveCancel := r.budget.Context(ctx, PhaseResolve, r.timeout)
+		defer resolveCancel()
+		if err := r.managed.ResolveReferences(resolveCtx, managed); err != nil {
 			// If any of our referenced resources are not yet ready (or if we
 			// encountered an error resolving them) we want to try again. If
 			// this is the first time we encounter this situation we'll be
@@ -778,13 +1283,19 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			// requeue explicitly, which will trigger backoff.
 			log.Debug("Cannot resolve managed resource references", "error", err)
 			record.Event(managed, event.Warning(reasonCannotResolveRefs, err))
-			managed.SetConditions(xpv1.ReconcileError(err))
-			return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+			managed.SetConditions(reconcileError(err))
+			return requeueOnError(err), errors.Wrap(r.writeStatus(ctx, managed), errUpdateManagedStatus)
 		}
 	}
 
-	external, err := r.external.Connect(externalCtx, managed)
-	if err != nil {
+	// Tell the ExternalClient which fields are managed outside Crossplane, so
+	// that their drift doesn't trigger continuous corrective updates.
+	externalCtx = withDriftIgnoredPaths(externalCtx, meta.GetDriftIgnoredPaths(managed))
+
+	connectCtx, connectCancel := r.budget.Context(externalCtx, PhaseConnect, r.timeout)
+	defer connectCancel()
+	external, err := r.external.Connect(connectCtx, managed)
+	if err = r.translator.Translate(err); err != nil {
 		// We'll usually hit this case if our Provider or its secret are missing
 		// or invalid. If this is first time we encounter this issue we'll be
 		// requeued implicitly when we update our status with the new error
@@ -792,9 +1303,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		// backoff.
 		log.Debug("Cannot connect to provider", "error", err)
 		record.Event(managed, event.Warning(reasonCannotConnect, err))
-		managed.SetConditions(xpv1.ReconcileError(errors.Wrap(err, errReconcileConnect)))
-		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+		err = errors.Wrap(err, errReconcileConnect)
+		managed.SetConditions(reconcileError(err))
+		return requeueOnError(err), errors.Wrap(r.writeStatus(ctx, managed), errUpdateManagedStatus)
 	}
+	external = &translatingClient{client: external, translator: r.translator}
+	external = &instrumentedClient{client: external, kind: r.kind, recorder: r.metrics}
 	defer func() {
 		if err := r.external.Disconnect(ctx); err != nil {
 			log.Debug("Cannot disconnect from provider", "error", err)
@@ -802,7 +1316,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		}
 	}()
 
-	observation, err := external.Observe(externalCtx, managed)
+	observeCtx, observeCancel := r.budget.Context(externalCtx, PhaseObserve, r.timeout)
+	defer observeCancel()
+	observation, cached := r.observations.Get(managed)
+	if !cached {
+		observation, err = external.Observe(observeCtx, managed)
+	}
 	if err != nil {
 		// We'll usually hit this case if our Provider credentials are invalid
 		// or insufficient for observing the external resource type we're
@@ -812,30 +1331,60 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		// trigger backoff.
 		log.Debug("Cannot observe external resource", "error", err)
 		record.Event(managed, event.Warning(reasonCannotObserve, err))
-		managed.SetConditions(xpv1.ReconcileError(errors.Wrap(err, errReconcileObserve)))
-		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+		err = errors.Wrap(err, errReconcileObserve)
+		managed.SetConditions(reconcileError(err))
+		return requeueOnError(err), errors.Wrap(r.writeStatus(ctx, managed), errUpdateManagedStatus)
+	}
+	if !cached {
+		r.observations.Set(managed, observation)
+	}
+	if meta.ChangeExternalName(managed, observation.ExternalName) {
+		// Persisting annotations resets any pending changes to the status,
+		// so we requeue to observe the external resource by its new name
+		// rather than continuing.
+		r.observations.Invalidate(managed)
+		log.Debug("Changed external name", "new-external-name", observation.ExternalName)
+		if err := r.managed.UpdateCriticalAnnotations(ctx, managed); err != nil {
+			log.Debug(errUpdateManagedAnnotations, "error", err)
+			record.Event(managed, event.Warning(reasonCannotUpdateManaged, errors.Wrap(err, errUpdateManagedAnnotations)))
+			managed.SetConditions(reconcileError(errors.Wrap(err, errUpdateManagedAnnotations)))
+			return requeueOnError(err), errors.Wrap(r.writeStatus(ctx, managed), errUpdateManagedStatus)
+		}
+		record.Event(managed, event.Normal(reasonRenamed, "Changed external name to "+observation.ExternalName))
+		return reconcile.Result{Requeue: true}, nil
+	}
+	r.metrics.RecordReady(r.kind, managed, previousReady)
+	recordTimeToReady(managed, previousReady, time.Now())
+	reportLateInitConflicts(managed, record, observation.LateInitConflicts)
+	if observation.ResourceExists && !meta.WasDeleted(managed) {
+		r.health.Check(externalCtx, managed)
+	} else {
+		r.health.Forget(managed)
+	}
+	if previousReady.Status != corev1.ConditionTrue && managed.GetCondition(xpv1.TypeReady).Status == corev1.ConditionTrue {
+		r.lifecycle.Emit(ctx, cloudevent.TypeBecameReady, r.kind, managed)
 	}
 
-	if r.managementPoliciesEnabled && managed.GetManagementPolicy() == xpv1.ManagementObserveOnly {
+	if managementPoliciesEnabled && managed.GetManagementPolicy().ShouldOnlyObserve() {
 		// In the observe-only mode, !observation.ResourceExists will be an error
 		// case, and we will explicitly return this information to the user.
 		if !observation.ResourceExists {
 			record.Event(managed, event.Warning(reasonCannotObserve, errors.New(errExternalResourceNotExist)))
 			managed.SetConditions(xpv1.ReconcileError(errors.Wrap(errors.New(errExternalResourceNotExist), errReconcileObserve)))
-			return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+			return reconcile.Result{Requeue: true}, errors.Wrap(r.writeStatus(ctx, managed), errUpdateManagedStatus)
 		}
 
 		// It is a valid use case to Observe a resource to get its connection
 		// details, so we publish them here.
-		if _, err := r.managed.PublishConnection(ctx, managed, observation.ConnectionDetails); err != nil {
+		if _, err := r.publishConnection(ctx, managed, observation.ConnectionDetails); err != nil {
 			// If this is the first time we encounter this issue we'll be
 			// requeued implicitly when we update our status with the new error
 			// condition. If not, we requeue explicitly, which will trigger
 			// backoff.
 			log.Debug("Cannot publish connection details", "error", err)
 			record.Event(managed, event.Warning(reasonCannotPublish, err))
-			managed.SetConditions(xpv1.ReconcileError(err))
-			return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+			managed.SetConditions(reconcileError(err))
+			return requeueOnError(err), errors.Wrap(r.writeStatus(ctx, managed), errUpdateManagedStatus)
 		}
 
 		// Since we're in the ObserveOnly mode, we don't want to update the spec
@@ -849,8 +1398,23 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		// reconcile.
 		log.Debug("Observed the resource successfully with management policy ObserveOnly", "requeue-after", time.Now().Add(r.pollInterval))
 		managed.SetConditions(xpv1.ReconcileSuccess())
-		return reconcile.Result{RequeueAfter: r.pollInterval}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+		return reconcile.Result{RequeueAfter: r.pollInterval}, errors.Wrap(r.writeStatus(ctx, managed), errUpdateManagedStatus)
+	}
+	// In dry-run mode we report what we would do to the external resource
+	// rather than doing it. We still finalize a deleted managed resource whose
+	// external resource no longer exists, since doing so changes nothing
+	// outside the API server.
+	if meta.IsDryRun(managed) && (!meta.WasDeleted(managed) || observation.ResourceExists) {
+		plan := plannedChange(managed, observation)
+		log.Debug("Planned change to external resource in dry-run mode", "plan", plan.Reason)
+		record.Event(managed, event.Normal(reasonPlanned, plan.Message))
+		managed.SetConditions(plan, xpv1.ReconcileSuccess())
+		return reconcile.Result{RequeueAfter: r.pollInterval}, errors.Wrap(r.writeStatus(ctx, managed), errUpdateManagedStatus)
 	}
+	if managed.GetCondition(xpv1.TypePlanned).Status == corev1.ConditionTrue {
+		managed.SetConditions(xpv1.PlanApplied())
+	}
+
 	// If this resource has a non-zero creation grace period we want to wait
 	// for that period to expire before we trust that the resource really
 	// doesn't exist. This is because some external APIs are eventually
@@ -868,7 +1432,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		// We'll only reach this point if deletion policy is not orphan, so we
 		// are safe to call external deletion if external resource exists.
 		if observation.ResourceExists {
-			if err := external.Delete(externalCtx, managed); err != nil {
+			deleteStarted := time.Now()
+			mutateCtx, mutateCancel := r.budget.Context(externalCtx, PhaseMutate, r.timeout)
+			defer mutateCancel()
+			r.observations.Invalidate(managed)
+			if err := external.Delete(mutateCtx, managed); err != nil {
 				// We'll hit this condition if we can't delete our external
 				// resource, for example if our provider credentials don't have
 				// access to delete it. If this is the first time we encounter
@@ -877,8 +1445,9 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 				// explicitly, which will trigger backoff.
 				log.Debug("Cannot delete external resource", "error", err)
 				record.Event(managed, event.Warning(reasonCannotDelete, err))
-				managed.SetConditions(xpv1.Deleting(), xpv1.ReconcileError(errors.Wrap(err, errReconcileDelete)))
-				return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+				err = errors.Wrap(err, errReconcileDelete)
+				managed.SetConditions(xpv1.Deleting(), reconcileError(err))
+				return requeueOnError(err), r.updateStatus(ctx, managed, deleteTracking(deleteStarted, err))
 			}
 
 			// We've successfully requested deletion of our external resource.
@@ -891,17 +1460,19 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			log.Debug("Successfully requested deletion of external resource")
 			record.Event(managed, event.Normal(reasonDeleted, "Successfully requested deletion of external resource"))
 			managed.SetConditions(xpv1.Deleting(), xpv1.ReconcileSuccess())
-			return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+			return reconcile.Result{Requeue: true}, r.updateStatus(ctx, managed, deleteTracking(deleteStarted, nil))
 		}
-		if err := r.managed.UnpublishConnection(ctx, managed, observation.ConnectionDetails); err != nil {
+		unpublishCtx, unpublishCancel := r.budget.Context(ctx, PhasePublish, r.timeout)
+		defer unpublishCancel()
+		if err := r.managed.UnpublishConnection(unpublishCtx, managed, observation.ConnectionDetails); err != nil {
 			// If this is the first time we encounter this issue we'll be
 			// requeued implicitly when we update our status with the new error
 			// condition. If not, we requeue explicitly, which will trigger
 			// backoff.
 			log.Debug("Cannot unpublish connection details", "error", err)
 			record.Event(managed, event.Warning(reasonCannotUnpublish, err))
-			managed.SetConditions(xpv1.Deleting(), xpv1.ReconcileError(err))
-			return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+			managed.SetConditions(xpv1.Deleting(), reconcileError(err))
+			return requeueOnError(err), errors.Wrap(r.writeStatus(ctx, managed), errUpdateManagedStatus)
 		}
 		if err := r.managed.RemoveFinalizer(ctx, managed); err != nil {
 			// If this is the first time we encounter this issue we'll be
@@ -909,8 +1480,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			// condition. If not, we requeue explicitly, which will trigger
 			// backoff.
 			log.Debug("Cannot remove managed resource finalizer", "error", err)
-			managed.SetConditions(xpv1.Deleting(), xpv1.ReconcileError(err))
-			return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+			managed.SetConditions(xpv1.Deleting(), reconcileError(err))
+			return requeueOnError(err), errors.Wrap(r.writeStatus(ctx, managed), errUpdateManagedStatus)
 		}
 
 		// We've successfully deleted our external resource (if necessary) and
@@ -918,17 +1489,19 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		// added a finalizer to this resource then it should no longer exist and
 		// thus there is no point trying to update its status.
 		log.Debug("Successfully deleted managed resource")
+		r.metrics.RecordDeleted(r.kind, managed)
+		r.lifecycle.Emit(ctx, cloudevent.TypeDeleted, r.kind, managed)
 		return reconcile.Result{Requeue: false}, nil
 	}
 
-	if _, err := r.managed.PublishConnection(ctx, managed, observation.ConnectionDetails); err != nil {
+	if _, err := r.publishConnection(ctx, managed, observation.ConnectionDetails); err != nil {
 		// If this is the first time we encounter this issue we'll be requeued
 		// implicitly when we update our status with the new error condition. If
 		// not, we requeue explicitly, which will trigger backoff.
 		log.Debug("Cannot publish connection details", "error", err)
 		record.Event(managed, event.Warning(reasonCannotPublish, err))
-		managed.SetConditions(xpv1.ReconcileError(err))
-		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+		managed.SetConditions(reconcileError(err))
+		return requeueOnError(err), errors.Wrap(r.writeStatus(ctx, managed), errUpdateManagedStatus)
 	}
 
 	if err := r.managed.AddFinalizer(ctx, managed); err != nil {
@@ -936,8 +1509,18 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		// implicitly when we update our status with the new error condition. If
 		// not, we requeue explicitly, which will trigger backoff.
 		log.Debug("Cannot add finalizer", "error", err)
-		managed.SetConditions(xpv1.ReconcileError(err))
-		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+		managed.SetConditions(reconcileError(err))
+		return requeueOnError(err), errors.Wrap(r.writeStatus(ctx, managed), errUpdateManagedStatus)
+	}
+
+	if !observation.ResourceExists && restored {
+		// A restored managed resource's external resource should exist. We
+		// never create it in the reconcile in which we adopted it, in case
+		// the external API is slow to report that it exists. Subsequent
+		// reconciles will create it if it really doesn't exist.
+		log.Debug("Waiting for restored external resource existence to be confirmed")
+		record.Event(managed, event.Normal(reasonPending, "Waiting for restored external resource existence to be confirmed"))
+		return reconcile.Result{Requeue: true}, nil
 	}
 
 	if !observation.ResourceExists {
@@ -952,11 +1535,15 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		if err := r.client.Update(ctx, managed); err != nil {
 			log.Debug(errUpdateManaged, "error", err)
 			record.Event(managed, event.Warning(reasonCannotUpdateManaged, errors.Wrap(err, errUpdateManaged)))
-			managed.SetConditions(xpv1.Creating(), xpv1.ReconcileError(errors.Wrap(err, errUpdateManaged)))
-			return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+			err = errors.Wrap(err, errUpdateManaged)
+			managed.SetConditions(xpv1.Creating(), reconcileError(err))
+			return requeueOnError(err), errors.Wrap(r.writeStatus(ctx, managed), errUpdateManagedStatus)
 		}
 
-		creation, err := external.Create(externalCtx, managed)
+		mutateCtx, mutateCancel := r.budget.Context(externalCtx, PhaseMutate, r.timeout)
+		defer mutateCancel()
+		r.observations.Invalidate(managed)
+		creation, err := external.Create(mutateCtx, managed)
 		if err != nil {
 			// We'll hit this condition if we can't create our external
 			// resource, for example if our provider credentials don't have
@@ -973,6 +1560,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			// won't know whether or not it created an external
 			// resource.
 			meta.SetExternalCreateFailed(managed, time.Now())
+			r.journalCreation(ctx, log, managed)
 			if err := r.managed.UpdateCriticalAnnotations(ctx, managed); err != nil {
 				log.Debug(errUpdateManagedAnnotations, "error", err)
 				record.Event(managed, event.Warning(reasonCannotUpdateManaged, errors.Wrap(err, errUpdateManagedAnnotations)))
@@ -982,10 +1570,13 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 				// early because presumably it's more useful to
 				// set our status condition to the reason the
 				// create failed.
+			} else {
+				r.forgetCreation(ctx, log, managed)
 			}
 
-			managed.SetConditions(xpv1.Creating(), xpv1.ReconcileError(errors.Wrap(err, errReconcileCreate)))
-			return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+			err = errors.Wrap(err, errReconcileCreate)
+			managed.SetConditions(xpv1.Creating(), reconcileError(err))
+			return requeueOnError(err), errors.Wrap(r.writeStatus(ctx, managed), errUpdateManagedStatus)
 		}
 
 		// In some cases our external-name may be set by Create above.
@@ -1003,21 +1594,26 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		// Create implementations are advised not to alter status, but
 		// we may revisit this in future.
 		meta.SetExternalCreateSucceeded(managed, time.Now())
+		meta.SetExternalCreateUID(managed)
+		r.journalCreation(ctx, log, managed)
 		if err := r.managed.UpdateCriticalAnnotations(ctx, managed); err != nil {
 			log.Debug(errUpdateManagedAnnotations, "error", err)
 			record.Event(managed, event.Warning(reasonCannotUpdateManaged, errors.Wrap(err, errUpdateManagedAnnotations)))
-			managed.SetConditions(xpv1.Creating(), xpv1.ReconcileError(errors.Wrap(err, errUpdateManagedAnnotations)))
-			return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+			err = errors.Wrap(err, errUpdateManagedAnnotations)
+			managed.SetConditions(xpv1.Creating(), reconcileError(err))
+			return requeueOnError(err), errors.Wrap(r.writeStatus(ctx, managed), errUpdateManagedStatus)
 		}
+		r.forgetCreation(ctx, log, managed)
+		r.lifecycle.Emit(ctx, cloudevent.TypeCreated, r.kind, managed)
 
-		if _, err := r.managed.PublishConnection(ctx, managed, creation.ConnectionDetails); err != nil {
+		if _, err := r.publishConnection(ctx, managed, creation.ConnectionDetails); err != nil {
 			// If this is the first time we encounter this issue we'll be
 			// requeued implicitly when we update our status with the new error
 			// condition. If not, we requeue explicitly, which will trigger backoff.
 			log.Debug("Cannot publish connection details", "error", err)
 			record.Event(managed, event.Warning(reasonCannotPublish, err))
-			managed.SetConditions(xpv1.Creating(), xpv1.ReconcileError(err))
-			return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+			managed.SetConditions(xpv1.Creating(), reconcileError(err))
+			return requeueOnError(err), errors.Wrap(r.writeStatus(ctx, managed), errUpdateManagedStatus)
 		}
 
 		// We've successfully created our external resource. In many cases the
@@ -1027,10 +1623,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		log.Debug("Successfully requested creation of external resource")
 		record.Event(managed, event.Normal(reasonCreated, "Successfully requested creation of external resource"))
 		managed.SetConditions(xpv1.Creating(), xpv1.ReconcileSuccess())
-		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+		return reconcile.Result{Requeue: true}, errors.Wrap(r.writeStatus(ctx, managed), errUpdateManagedStatus)
 	}
 
-	if observation.ResourceLateInitialized {
+	if observation.ResourceLateInitialized && managed.GetManagementPolicy().ShouldLateInitialize() {
 		// Note that this update may reset any pending updates to the status of
 		// the managed resource from when it was observed above. This is because
 		// the API server replies to the update with its unchanged view of the
@@ -1041,8 +1637,9 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		if err := r.client.Update(ctx, managed); err != nil {
 			log.Debug(errUpdateManaged, "error", err)
 			record.Event(managed, event.Warning(reasonCannotUpdateManaged, err))
-			managed.SetConditions(xpv1.ReconcileError(errors.Wrap(err, errUpdateManaged)))
-			return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+			err = errors.Wrap(err, errUpdateManaged)
+			managed.SetConditions(reconcileError(err))
+			return requeueOnError(err), errors.Wrap(r.writeStatus(ctx, managed), errUpdateManagedStatus)
 		}
 	}
 
@@ -1055,14 +1652,24 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		// https://github.com/crossplane/crossplane/issues/289
 		log.Debug("External resource is up to date", "requeue-after", time.Now().Add(r.pollInterval))
 		managed.SetConditions(xpv1.ReconcileSuccess())
-		return reconcile.Result{RequeueAfter: r.pollInterval}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+		return reconcile.Result{RequeueAfter: r.pollInterval}, errors.Wrap(r.writeStatus(ctx, managed), errUpdateManagedStatus)
 	}
 
 	if observation.Diff != "" {
 		log.Debug("External resource differs from desired state", "diff", observation.Diff)
 	}
+	if len(observation.NotUpToDateReasons) > 0 {
+		record.Event(managed, event.Normal(reasonDrifted, notUpToDateMessage(observation.NotUpToDateReasons)))
+	}
+
+	r.metrics.RecordDrift(r.kind, managed)
+	r.lifecycle.Emit(ctx, cloudevent.TypeDriftDetected, r.kind, managed)
 
-	update, err := external.Update(externalCtx, managed)
+	updateStarted := time.Now()
+	mutateCtx, mutateCancel := r.budget.Context(externalCtx, PhaseMutate, r.timeout)
+	defer mutateCancel()
+	r.observations.Invalidate(managed)
+	update, err := external.Update(mutateCtx, managed)
 	if err != nil {
 		// We'll hit this condition if we can't update our external resource,
 		// for example if our provider credentials don't have access to update
@@ -1071,18 +1678,19 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		// condition. If not, we requeue explicitly, which will trigger backoff.
 		log.Debug("Cannot update external resource")
 		record.Event(managed, event.Warning(reasonCannotUpdate, err))
-		managed.SetConditions(xpv1.ReconcileError(errors.Wrap(err, errReconcileUpdate)))
-		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+		err = errors.Wrap(err, errReconcileUpdate)
+		managed.SetConditions(reconcileError(err))
+		return requeueOnError(err), r.updateStatus(ctx, managed, updateTracking(updateStarted, err))
 	}
 
-	if _, err := r.managed.PublishConnection(ctx, managed, update.ConnectionDetails); err != nil {
+	if _, err := r.publishConnection(ctx, managed, update.ConnectionDetails); err != nil {
 		// If this is the first time we encounter this issue we'll be requeued
 		// implicitly when we update our status with the new error condition. If
 		// not, we requeue explicitly, which will trigger backoff.
 		log.Debug("Cannot publish connection details", "error", err)
 		record.Event(managed, event.Warning(reasonCannotPublish, err))
-		managed.SetConditions(xpv1.ReconcileError(err))
-		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+		managed.SetConditions(reconcileError(err))
+		return requeueOnError(err), r.updateStatus(ctx, managed, updateTracking(updateStarted, nil))
 	}
 
 	// We've successfully updated our external resource. Per the below issue
@@ -1092,8 +1700,224 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	// https://github.com/crossplane/crossplane/issues/289
 	log.Debug("Successfully requested update of external resource", "requeue-after", time.Now().Add(r.pollInterval))
 	record.Event(managed, event.Normal(reasonUpdated, "Successfully requested update of external resource"))
-	managed.SetConditions(xpv1.ReconcileSuccess())
-	return reconcile.Result{RequeueAfter: r.pollInterval}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+	synced := xpv1.ReconcileSuccess()
+	if len(observation.NotUpToDateReasons) > 0 {
+		synced = synced.WithMessage("Updated external resource. " + notUpToDateMessage(observation.NotUpToDateReasons))
+	}
+	managed.SetConditions(synced)
+	return reconcile.Result{RequeueAfter: r.pollInterval}, r.updateStatus(ctx, managed, updateTracking(updateStarted, nil))
+}
+
+// updateStatus persists the status of the supplied managed resource. If
+// operation tracking is enabled it then persists the supplied operation
+// tracking annotations. We persist the annotations after the status because
+// persisting annotations resets any pending changes to the status.
+func (r *Reconciler) updateStatus(ctx context.Context, mg resource.Managed, tracking map[string]string) error {
+	if err := r.writeStatus(ctx, mg); err != nil || !r.operationTracking {
+		return errors.Wrap(err, errUpdateManagedStatus)
+	}
+	meta.AddAnnotations(mg, tracking)
+	return errors.Wrap(r.managed.UpdateCriticalAnnotations(ctx, mg), errUpdateManagedAnnotations)
+}
+
+// recordTimeToReady records how long the supplied managed resource took to
+// first become ready after it was created, if it opts in to recording it. The
+// time is recorded only once.
+func recordTimeToReady(mg resource.Managed, previous xpv1.Condition, now time.Time) {
+	rec, ok := mg.(resource.TimeToReadyRecorder)
+	if !ok || rec.GetTimeToReady() != nil || !metrics.FirstReady(mg, previous) {
+		return
+	}
+	rec.SetTimeToReady(&metav1.Duration{Duration: now.Sub(mg.GetCreationTimestamp().Time)})
+}
+
+// reportLateInitConflicts reports the supplied late initialization conflicts
+// using a warning event and the LateInitialized condition. The condition is
+// only set to true if it was previously false, to avoid adding it to managed
+// resources that have never had a conflict.
+func reportLateInitConflicts(mg resource.Managed, record event.Recorder, conflicts []resource.LateInitConflict) {
+	if len(conflicts) == 0 {
+		if mg.GetCondition(xpv1.TypeLateInitialized).Status == corev1.ConditionFalse {
+			mg.SetConditions(xpv1.LateInitConsistent())
+		}
+		return
+	}
+	errs := make([]error, len(conflicts))
+	for i := range conflicts {
+		errs[i] = errors.New(conflicts[i].String())
+	}
+	err := errors.Wrap(errors.Join(errs...), errLateInitConflict)
+	record.Event(mg, event.Warning(reasonLateInitConflict, err))
+	mg.SetConditions(xpv1.LateInitConflict(err))
+}
+
+// journalCreation records the outcome of creating the supplied managed
+// resource's external resource in the creation journal. Journalling is best
+// effort; if it fails we rely on persisting critical annotations as usual.
+func (r *Reconciler) journalCreation(ctx context.Context, log logging.Logger, mg resource.Managed) {
+	if err := r.journal.Record(ctx, mg); err != nil {
+		log.Info("Cannot journal external resource creation", "error", err)
+	}
+}
+
+// forgetCreation forgets the outcome of creating the supplied managed
+// resource's external resource once it has been persisted.
+func (r *Reconciler) forgetCreation(ctx context.Context, log logging.Logger, mg resource.Managed) {
+	if err := r.journal.Forget(ctx, mg); err != nil {
+		log.Debug("Cannot forget journalled external resource creation", "error", err)
+	}
+}
+
+// recoverCreation attempts to recover the outcome of creating the supplied
+// managed resource's external resource from the creation journal, and to
+// persist it.
+func (r *Reconciler) recoverCreation(ctx context.Context, log logging.Logger, mg resource.Managed) {
+	ok, err := r.journal.Recover(ctx, mg)
+	if err != nil {
+		log.Info("Cannot recover journalled external resource creation", "error", err)
+		return
+	}
+	if !ok {
+		return
+	}
+	if err := r.managed.UpdateCriticalAnnotations(ctx, mg); err != nil {
+		log.Debug(errUpdateManagedAnnotations, "error", err)
+		return
+	}
+	log.Info("Recovered journalled external resource creation", "external-name", meta.GetExternalName(mg))
+	r.forgetCreation(ctx, log, mg)
+}
+
+// adoptRestored adopts the external resource and connection details of the
+// supplied restored managed resource, recording that the managed resource now
+// owns its external resource.
+func (r *Reconciler) adoptRestored(ctx context.Context, mg resource.Managed) error {
+	if a, ok := r.managed.ConnectionPublisher.(ConnectionAdopter); ok {
+		if err := a.AdoptConnection(ctx, mg, meta.GetExternalCreateUID(mg)); err != nil {
+			return errors.Wrap(err, errAdoptRestored)
+		}
+	}
+	meta.SetExternalCreateUID(mg)
+	return errors.Wrap(r.managed.UpdateCriticalAnnotations(ctx, mg), errAdoptRestored)
+}
+
+// adoptConnection transfers ownership of the connection details published for
+// the supplied previous managed resource UID to the supplied managed resource,
+// then removes the annotation that requested it.
+func (r *Reconciler) adoptConnection(ctx context.Context, mg resource.Managed, previous types.UID) error {
+	if a, ok := r.managed.ConnectionPublisher.(ConnectionAdopter); ok {
+		if err := a.AdoptConnection(ctx, mg, previous); err != nil {
+			return errors.Wrap(err, errAdoptConnection)
+		}
+	}
+	meta.RemoveAnnotations(mg, meta.AnnotationKeyAdoptConnectionFrom)
+	return errors.Wrap(r.client.Update(ctx, mg), errAdoptConnection)
+}
+
+type driftIgnoredPathsKey struct{}
+
+func withDriftIgnoredPaths(ctx context.Context, paths []string) context.Context {
+	if len(paths) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, driftIgnoredPathsKey{}, paths)
+}
+
+// DriftIgnoredPaths returns the field paths of the managed resource being
+// reconciled whose drift from their desired state should be ignored, per its
+// drift ignore annotation. The Reconciler supplies them to the ExternalClient
+// using the context passed to its methods. An ExternalClient should not
+// consider the external resource to be out of date because these fields
+// differ.
+func DriftIgnoredPaths(ctx context.Context) []string {
+	paths, _ := ctx.Value(driftIgnoredPathsKey{}).([]string)
+	return paths
+}
+
+// notUpToDateMessage renders the supplied reasons an external resource is not
+// up to date as a message.
+func notUpToDateMessage(reasons []string) string {
+	return "External resource is not up to date: " + strings.Join(reasons, "; ")
+}
+
+// plannedChange returns a condition describing the change that would be made
+// to the external resource of the supplied managed resource.
+func plannedChange(mg resource.Managed, o ExternalObservation) xpv1.Condition {
+	switch {
+	case meta.WasDeleted(mg):
+		return xpv1.PlannedDelete()
+	case !o.ResourceExists:
+		return xpv1.PlannedCreate()
+	case !o.ResourceUpToDate:
+		return xpv1.PlannedUpdate(o.Diff)
+	default:
+		return xpv1.PlannedNoChanges()
+	}
+}
+
+// publishConnection publishes the supplied connection details, along with any
+// derived from fields of the supplied managed resource.
+func (r *Reconciler) publishConnection(ctx context.Context, mg resource.Managed, cd ConnectionDetails) (bool, error) {
+	ctx, cancel := r.budget.Context(ctx, PhasePublish, r.timeout)
+	defer cancel()
+	if len(r.connectionFieldPaths) == 0 {
+		return r.managed.PublishConnection(ctx, mg, cd)
+	}
+	derived, err := ConnectionDetailsFromFieldPaths(mg, r.connectionFieldPaths...)
+	if err != nil {
+		return false, err
+	}
+	for k, v := range cd {
+		derived[k] = v
+	}
+	return r.managed.PublishConnection(ctx, mg, derived)
+}
+
+// recordCalls records the number of external API calls counted during the
+// current reconcile, and warns if they exceeded the call budget.
+func (r *Reconciler) recordCalls(ctx context.Context, log logging.Logger, mg resource.Managed) {
+	n := metrics.CallsFrom(ctx)
+	r.metrics.RecordExternalCalls(r.kind, mg, n)
+	log.Debug("Made external API calls", "calls", n)
+	if r.callBudget <= 0 || n <= r.callBudget {
+		return
+	}
+	err := errors.Errorf(errFmtExternalCallBudget, n, r.callBudget)
+	log.Info("Reconcile exceeded its external API call budget", "calls", n, "budget", r.callBudget)
+	r.record.Event(mg, event.Warning(reasonExternalCallBudget, err))
+}
+
+// writeStatus persists the status of the supplied managed resource, retrying
+// if the update conflicts with a concurrent write. The latest version of the
+// managed resource is read from the API server, not our cache.
+func (r *Reconciler) writeStatus(ctx context.Context, mg resource.Managed) error {
+	return resource.UpdateStatus(ctx, r.client, r.apiReader, mg, r.conflictBackoff)
+}
+
+// updateTracking returns annotations that record an external update that
+// started at the supplied time and, if err is non-nil, failed.
+func updateTracking(started time.Time, err error) map[string]string {
+	o := &metav1.ObjectMeta{}
+	meta.SetExternalUpdatePending(o, started)
+	if err != nil {
+		meta.SetExternalUpdateFailed(o, time.Now(), err)
+		return o.GetAnnotations()
+	}
+	meta.SetExternalUpdateSucceeded(o, time.Now())
+	return o.GetAnnotations()
+}
+
+// deleteTracking returns annotations that record an external delete that
+// started at the supplied time and, if err is non-nil, failed.
+func deleteTracking(started time.Time, err error) map[string]string {
+	o := &metav1.ObjectMeta{}
+	meta.SetExternalDeletePending(o, started)
+	if err != nil {
+		meta.SetExternalDeleteFailed(o, time.Now(), err)
+		return o.GetAnnotations()
+	}
+	meta.SetExternalDeleteSucceeded(o, time.Now())
+	return o.GetAnnotations()
 }
 
 // We need to be careful until we completely remove the deletionPolicy in favor
@@ -1106,7 +1930,7 @@ func shouldOrphan(managementPoliciesEnabled bool, managed resource.Managed) bool
 	if !managementPoliciesEnabled {
 		return managed.GetDeletionPolicy() == xpv1.DeletionOrphan
 	}
-	if managed.GetDeletionPolicy() == xpv1.DeletionDelete && managed.GetManagementPolicy() == xpv1.ManagementFullControl {
+	if managed.GetDeletionPolicy() == xpv1.DeletionDelete && managed.GetManagementPolicy().ShouldDelete() {
 		// This is the only case where we should delete the external resource,
 		// so do not orphan it.
 		return false
@@ -1121,3 +1945,47 @@ func shouldOrphan(managementPoliciesEnabled bool, managed resource.Managed) bool
 	// DeletionDelete && ManagementOrphanOnDelete (obeys non-default configuration)
 	return true
 }
+
+// reconcileErrorReasons are the Synced condition reasons used for errors with
+// a known code. Terminal errors are handled separately - see reconcileError.
+var reconcileErrorReasons = map[errors.Code]xpv1.ConditionReason{
+	errors.CodeThrottled:    xpv1.ReasonReconcileThrottled,
+	errors.CodeUnauthorized: xpv1.ReasonReconcileUnauthorized,
+	errors.CodeInvalidInput: xpv1.ReasonReconcileInvalidInput,
+	errors.CodeNotFound:     xpv1.ReasonReconcileNotFound,
+	errors.CodeConflict:     xpv1.ReasonReconcileConflict,
+}
+
+// reconcileError returns a Synced condition indicating that the supplied
+// error occurred while reconciling. Terminal errors and errors with a known
+// code use a distinct reason so that users can tell how they'll be retried.
+func reconcileError(err error) xpv1.Condition {
+	if errors.IsTerminal(err) {
+		return xpv1.ReconcileTerminalError(err)
+	}
+	c := xpv1.ReconcileError(err)
+	if r, ok := reconcileErrorReasons[errors.CodeOf(err)]; ok {
+		c.Reason = r
+	}
+	return c
+}
+
+// requeueOnError returns the result of a reconcile that failed with the
+// supplied error. Terminal errors aren't requeued - we'll try again only when
+// the managed resource changes. Throttled, unauthorized, and invalid input
+// errors are unlikely to be resolved by retrying immediately, so they're
+// requeued after a fixed wait. All other errors are requeued with backoff.
+func requeueOnError(err error) reconcile.Result {
+	if errors.IsTerminal(err) {
+		return reconcile.Result{}
+	}
+	switch errors.CodeOf(err) {
+	case errors.CodeThrottled:
+		return reconcile.Result{RequeueAfter: throttledWait}
+	case errors.CodeUnauthorized:
+		return reconcile.Result{RequeueAfter: unauthorizedWait}
+	case errors.CodeInvalidInput:
+		return reconcile.Result{RequeueAfter: invalidInputWait}
+	}
+	return reconcile.Result{Requeue: true}
+}
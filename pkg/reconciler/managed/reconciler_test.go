@@ -25,21 +25,30 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/feature"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/metrics"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/shard"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 )
 
 var _ reconcile.Reconciler = &Reconciler{}
 
+const managementPolicies feature.Flag = "EnableAlphaManagementPolicies"
+
 func TestReconciler(t *testing.T) {
 	type args struct {
 		m  manager.Manager
@@ -82,6 +91,31 @@ func TestReconciler(t *testing.T) {
 			},
 			want: want{result: reconcile.Result{}},
 		},
+		"ManagedInAnotherShard": {
+			reason: "Managed resources in another shard should be ignored, even if they were enqueued.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							obj.SetLabels(map[string]string{shard.LabelKeyShard: "1"})
+							return nil
+						}),
+						MockUpdate: func(_ context.Context, _ client.Object, _ ...client.UpdateOption) error {
+							t.Errorf("Update(...): unexpectedly updated a managed resource in another shard")
+							return nil
+						},
+						MockStatusUpdate: func(_ context.Context, _ client.Object, _ ...client.SubResourceUpdateOption) error {
+							t.Errorf("Status().Update(...): unexpectedly updated a managed resource in another shard")
+							return nil
+						},
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				mg: resource.ManagedKind(fake.GVK(&fake.Managed{})),
+				o:  []ReconcilerOption{WithShard(shard.Shard{Index: 0, Count: 2})},
+			},
+			want: want{result: reconcile.Result{}},
+		},
 		"UnpublishConnectionDetailsDeletionPolicyDeleteOrpahn": {
 			reason: "Errors unpublishing connection details should trigger a requeue after a short wait.",
 			args: args{
@@ -200,6 +234,30 @@ func TestReconciler(t *testing.T) {
 			},
 			want: want{result: reconcile.Result{Requeue: true}},
 		},
+		"InitializationStopped": {
+			reason: "We should requeue without reporting an error if an initializer stops initialization.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet:          test.NewMockGetFn(nil),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(errBoom),
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				mg: resource.ManagedKind(fake.GVK(&fake.Managed{})),
+				o: []ReconcilerOption{
+					WithInitializers(
+						InitializerFn(func(_ context.Context, _ resource.Managed) error {
+							return StopInitialization("waiting", 10*time.Second)
+						}),
+						InitializerFn(func(_ context.Context, _ resource.Managed) error {
+							return errBoom
+						}),
+					),
+				},
+			},
+			want: want{result: reconcile.Result{Requeue: true, RequeueAfter: 10 * time.Second}},
+		},
 		"ExternalCreatePending": {
 			reason: "We should return early if the managed resource appears to be pending creation. We might have leaked a resource and don't want to create another.",
 			args: args{
@@ -229,6 +287,191 @@ func TestReconciler(t *testing.T) {
 			},
 			want: want{result: reconcile.Result{Requeue: false}},
 		},
+		"AdoptRestoredError": {
+			reason: "Errors adopting the external resource of a restored managed resource should trigger a requeue after a short wait.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							obj.SetUID("new-uid")
+							meta.SetExternalName(obj, "cool")
+							meta.SetExternalCreateSucceeded(obj, now.Time)
+							meta.AddAnnotations(obj, map[string]string{meta.AnnotationKeyExternalCreateUID: "old-uid"})
+							return nil
+						}),
+						MockStatusUpdate: test.MockSubResourceUpdateFn(func(_ context.Context, obj client.Object, _ ...client.SubResourceUpdateOption) error {
+							want := &fake.Managed{}
+							want.SetUID("new-uid")
+							meta.SetExternalName(want, "cool")
+							meta.SetExternalCreateSucceeded(want, now.Time)
+							meta.SetExternalCreateUID(want)
+							want.SetConditions(xpv1.ReconcileError(errors.Wrap(errBoom, errAdoptRestored)))
+							if diff := cmp.Diff(want, obj, test.EquateConditions()); diff != "" {
+								reason := "Errors adopting a restored managed resource should be reported as a conditioned status."
+								t.Errorf("\nReason: %s\n-want, +got:\n%s", reason, diff)
+							}
+							return nil
+						}),
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				mg: resource.ManagedKind(fake.GVK(&fake.Managed{})),
+				o: []ReconcilerOption{
+					WithInitializers(),
+					WithCriticalAnnotationUpdater(CriticalAnnotationUpdateFn(func(ctx context.Context, o client.Object) error { return errBoom })),
+				},
+			},
+			want: want{result: reconcile.Result{Requeue: true}},
+		},
+		"RestoredExternalResourceNotYetObserved": {
+			reason: "We should not create the external resource of a restored managed resource in the reconcile in which we adopt it.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							obj.SetUID("new-uid")
+							meta.SetExternalName(obj, "cool")
+							meta.SetExternalCreateSucceeded(obj, now.Time)
+							meta.AddAnnotations(obj, map[string]string{meta.AnnotationKeyExternalCreateUID: "old-uid"})
+							return nil
+						}),
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				mg: resource.ManagedKind(fake.GVK(&fake.Managed{})),
+				o: []ReconcilerOption{
+					WithInitializers(),
+					WithReferenceResolver(ReferenceResolverFn(func(_ context.Context, _ resource.Managed) error { return nil })),
+					WithCriticalAnnotationUpdater(CriticalAnnotationUpdateFn(func(_ context.Context, o client.Object) error {
+						if got := meta.GetExternalCreateUID(o); got != "new-uid" {
+							t.Errorf("\nReason: The restored managed resource should own its external resource.\nGetExternalCreateUID(...): want new-uid, got %s", got)
+						}
+						return nil
+					})),
+					WithExternalConnecter(ExternalConnectorFn(func(_ context.Context, mg resource.Managed) (ExternalClient, error) {
+						c := &ExternalClientFns{
+							ObserveFn: func(_ context.Context, _ resource.Managed) (ExternalObservation, error) {
+								return ExternalObservation{ResourceExists: false}, nil
+							},
+							CreateFn: func(_ context.Context, _ resource.Managed) (ExternalCreation, error) {
+								t.Errorf("\nReason: The external resource of a restored managed resource should not be created.")
+								return ExternalCreation{}, nil
+							},
+						}
+						return c, nil
+					})),
+					WithConnectionPublishers(),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error { return nil }}),
+				},
+			},
+			want: want{result: reconcile.Result{Requeue: true}},
+		},
+		"AdoptConnectionError": {
+			reason: "Errors adopting the connection details of a previous managed resource should trigger a requeue.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							obj.SetUID("new-uid")
+							meta.AddAnnotations(obj, map[string]string{meta.AnnotationKeyAdoptConnectionFrom: "old-uid"})
+							return nil
+						}),
+						MockUpdate:       test.NewMockUpdateFn(nil),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				mg: resource.ManagedKind(fake.GVK(&fake.Managed{})),
+				o: []ReconcilerOption{
+					WithInitializers(),
+					WithConnectionPublishers(&adoptingPublisher{adopt: func(_ context.Context, _ resource.ConnectionSecretOwner, _ types.UID) error {
+						return errBoom
+					}}),
+				},
+			},
+			want: want{result: reconcile.Result{Requeue: true}},
+		},
+		"AdoptConnection": {
+			reason: "We should adopt the connection details of a previous managed resource, then stop asking to.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							obj.SetUID("new-uid")
+							meta.AddAnnotations(obj, map[string]string{meta.AnnotationKeyAdoptConnectionFrom: "old-uid"})
+							return nil
+						}),
+						MockUpdate: test.NewMockUpdateFn(nil, func(obj client.Object) error {
+							if got := meta.GetAdoptConnectionFrom(obj.(metav1.Object)); got != "" {
+								t.Errorf("\nReason: The adoption annotation should be removed once adopted.\nGetAdoptConnectionFrom(...): want \"\", got %s", got)
+							}
+							return nil
+						}),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				mg: resource.ManagedKind(fake.GVK(&fake.Managed{})),
+				o: []ReconcilerOption{
+					WithInitializers(),
+					WithReferenceResolver(ReferenceResolverFn(func(_ context.Context, _ resource.Managed) error { return nil })),
+					WithConnectionPublishers(&adoptingPublisher{adopt: func(_ context.Context, _ resource.ConnectionSecretOwner, previous types.UID) error {
+						if previous != "old-uid" {
+							t.Errorf("\nReason: We should adopt from the annotated UID.\nAdoptConnection(...): want old-uid, got %s", previous)
+						}
+						return nil
+					}}),
+					WithExternalConnecter(ExternalConnectorFn(func(_ context.Context, _ resource.Managed) (ExternalClient, error) {
+						return nil, errBoom
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{Requeue: true}},
+		},
+		"DryRunPlannedUpdate": {
+			reason: "A managed resource in dry-run mode should report the update it would make without making it.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							meta.AddAnnotations(obj, map[string]string{meta.AnnotationKeyDryRun: "true"})
+							return nil
+						}),
+						MockStatusUpdate: test.MockSubResourceUpdateFn(func(_ context.Context, obj client.Object, _ ...client.SubResourceUpdateOption) error {
+							want := &fake.Managed{}
+							meta.AddAnnotations(want, map[string]string{meta.AnnotationKeyDryRun: "true"})
+							want.SetConditions(xpv1.PlannedUpdate("-a, +b"), xpv1.ReconcileSuccess())
+							if diff := cmp.Diff(want, obj, test.EquateConditions()); diff != "" {
+								reason := "The planned update should be reported as a conditioned status."
+								t.Errorf("\nReason: %s\n-want, +got:\n%s", reason, diff)
+							}
+							return nil
+						}),
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				mg: resource.ManagedKind(fake.GVK(&fake.Managed{})),
+				o: []ReconcilerOption{
+					WithInitializers(),
+					WithReferenceResolver(ReferenceResolverFn(func(_ context.Context, _ resource.Managed) error { return nil })),
+					WithExternalConnecter(ExternalConnectorFn(func(_ context.Context, mg resource.Managed) (ExternalClient, error) {
+						c := &ExternalClientFns{
+							ObserveFn: func(_ context.Context, _ resource.Managed) (ExternalObservation, error) {
+								return ExternalObservation{ResourceExists: true, ResourceUpToDate: false, Diff: "-a, +b"}, nil
+							},
+							UpdateFn: func(_ context.Context, _ resource.Managed) (ExternalUpdate, error) {
+								t.Errorf("\nReason: The external resource of a managed resource in dry-run mode should not be updated.")
+								return ExternalUpdate{}, nil
+							},
+						}
+						return c, nil
+					})),
+					WithConnectionPublishers(),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error { return nil }}),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: defaultpollInterval}},
+		},
 		"ResolveReferencesError": {
 			reason: "Errors during reference resolution references should trigger a requeue after a short wait.",
 			args: args{
@@ -357,6 +600,108 @@ func TestReconciler(t *testing.T) {
 			},
 			want: want{result: reconcile.Result{Requeue: true}},
 		},
+		"ExternalObserveTerminalError": {
+			reason: "Terminal errors observing the external resource should be reported with a distinct reason and not trigger a requeue.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+						MockStatusUpdate: test.MockSubResourceUpdateFn(func(_ context.Context, obj client.Object, _ ...client.SubResourceUpdateOption) error {
+							want := &fake.Managed{}
+							want.SetConditions(xpv1.ReconcileTerminalError(errors.Wrap(errors.Terminal(errBoom), errReconcileObserve)))
+							if diff := cmp.Diff(want, obj, test.EquateConditions()); diff != "" {
+								reason := "Terminal errors observing the managed resource should be reported as a conditioned status."
+								t.Errorf("\nReason: %s\n-want, +got:\n%s", reason, diff)
+							}
+							return nil
+						}),
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				mg: resource.ManagedKind(fake.GVK(&fake.Managed{})),
+				o: []ReconcilerOption{
+					WithInitializers(),
+					WithExternalConnecter(ExternalConnectorFn(func(_ context.Context, mg resource.Managed) (ExternalClient, error) {
+						c := &ExternalClientFns{
+							ObserveFn: func(_ context.Context, _ resource.Managed) (ExternalObservation, error) {
+								return ExternalObservation{}, errors.Terminal(errBoom)
+							},
+						}
+						return c, nil
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{}},
+		},
+		"ExternalObserveRetryableError": {
+			reason: "Errors marked retryable should trigger a requeue even if they wrap a terminal error.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+						MockStatusUpdate: test.MockSubResourceUpdateFn(func(_ context.Context, obj client.Object, _ ...client.SubResourceUpdateOption) error {
+							want := &fake.Managed{}
+							want.SetConditions(xpv1.ReconcileError(errors.Wrap(errBoom, errReconcileObserve)))
+							if diff := cmp.Diff(want, obj, test.EquateConditions()); diff != "" {
+								reason := "Retryable errors observing the managed resource should be reported as a conditioned status."
+								t.Errorf("\nReason: %s\n-want, +got:\n%s", reason, diff)
+							}
+							return nil
+						}),
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				mg: resource.ManagedKind(fake.GVK(&fake.Managed{})),
+				o: []ReconcilerOption{
+					WithInitializers(),
+					WithExternalConnecter(ExternalConnectorFn(func(_ context.Context, mg resource.Managed) (ExternalClient, error) {
+						c := &ExternalClientFns{
+							ObserveFn: func(_ context.Context, _ resource.Managed) (ExternalObservation, error) {
+								return ExternalObservation{}, errors.Retryable(errors.Terminal(errBoom))
+							},
+						}
+						return c, nil
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{Requeue: true}},
+		},
+		"ExternalObserveTranslatedError": {
+			reason: "Errors observing the external resource should be translated before they are reported.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+						MockStatusUpdate: test.MockSubResourceUpdateFn(func(_ context.Context, obj client.Object, _ ...client.SubResourceUpdateOption) error {
+							want := &fake.Managed{}
+							want.SetConditions(xpv1.ReconcileTerminalError(errors.Wrap(errors.Wrap(errBoom, "explosion"), errReconcileObserve)))
+							if diff := cmp.Diff(want, obj, test.EquateConditions()); diff != "" {
+								reason := "Translated errors observing the managed resource should be reported as a conditioned status."
+								t.Errorf("\nReason: %s\n-want, +got:\n%s", reason, diff)
+							}
+							return nil
+						}),
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				mg: resource.ManagedKind(fake.GVK(&fake.Managed{})),
+				o: []ReconcilerOption{
+					WithInitializers(),
+					WithExternalConnecter(ExternalConnectorFn(func(_ context.Context, mg resource.Managed) (ExternalClient, error) {
+						c := &ExternalClientFns{
+							ObserveFn: func(_ context.Context, _ resource.Managed) (ExternalObservation, error) {
+								return ExternalObservation{}, errBoom
+							},
+						}
+						return c, nil
+					})),
+					WithErrorTranslator(ErrorTranslatorFn(func(err error) error {
+						return errors.Terminal(errors.Wrap(err, "explosion"))
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{}},
+		},
 		"CreationGracePeriod": {
 			reason: "If our resource appears not to exist during the creation grace period we should return early.",
 			args: args{
@@ -949,14 +1294,93 @@ func TestReconciler(t *testing.T) {
 			},
 			want: want{result: reconcile.Result{RequeueAfter: defaultpollInterval}},
 		},
-		"UpdateExternalError": {
-			reason: "Errors while updating an external resource should trigger a requeue after a short wait.",
+		"ExternalNameChanged": {
+			reason: "When Observe reports a new external name it should be persisted with its history, and a requeue should be triggered.",
 			args: args{
 				m: &fake.Manager{
 					Client: &test.MockClient{
-						MockGet: test.NewMockGetFn(nil),
-						MockStatusUpdate: test.MockSubResourceUpdateFn(func(_ context.Context, obj client.Object, _ ...client.SubResourceUpdateOption) error {
-							want := &fake.Managed{}
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							meta.SetExternalName(obj.(metav1.Object), "alias")
+							return nil
+						}),
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				mg: resource.ManagedKind(fake.GVK(&fake.Managed{})),
+				o: []ReconcilerOption{
+					WithInitializers(),
+					WithReferenceResolver(ReferenceResolverFn(func(_ context.Context, _ resource.Managed) error { return nil })),
+					WithExternalConnecter(ExternalConnectorFn(func(_ context.Context, mg resource.Managed) (ExternalClient, error) {
+						c := &ExternalClientFns{
+							ObserveFn: func(_ context.Context, _ resource.Managed) (ExternalObservation, error) {
+								return ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ExternalName: "canonical"}, nil
+							},
+						}
+						return c, nil
+					})),
+					WithCriticalAnnotationUpdater(CriticalAnnotationUpdateFn(func(_ context.Context, o client.Object) error {
+						want := map[string]string{
+							meta.AnnotationKeyExternalName:        "canonical",
+							meta.AnnotationKeyExternalNameHistory: `["alias"]`,
+						}
+						if diff := cmp.Diff(want, o.GetAnnotations()); diff != "" {
+							t.Errorf("\nReason: %s\n-want, +got:\n%s", "The changed external name and its history should be persisted.", diff)
+						}
+						return nil
+					})),
+					WithConnectionPublishers(),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error { return nil }}),
+				},
+			},
+			want: want{result: reconcile.Result{Requeue: true}},
+		},
+		"UpdateExternalError": {
+			reason: "Errors while updating an external resource should trigger a requeue after a short wait.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+						MockStatusUpdate: test.MockSubResourceUpdateFn(func(_ context.Context, obj client.Object, _ ...client.SubResourceUpdateOption) error {
+							want := &fake.Managed{}
+							want.SetConditions(xpv1.ReconcileError(errors.Wrap(errBoom, errReconcileUpdate)))
+							if diff := cmp.Diff(want, obj, test.EquateConditions()); diff != "" {
+								reason := "Errors while updating an external resource should be reported as a conditioned status."
+								t.Errorf("\nReason: %s\n-want, +got:\n%s", reason, diff)
+							}
+							return nil
+						}),
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				mg: resource.ManagedKind(fake.GVK(&fake.Managed{})),
+				o: []ReconcilerOption{
+					WithInitializers(),
+					WithReferenceResolver(ReferenceResolverFn(func(_ context.Context, _ resource.Managed) error { return nil })),
+					WithExternalConnecter(ExternalConnectorFn(func(_ context.Context, mg resource.Managed) (ExternalClient, error) {
+						c := &ExternalClientFns{
+							ObserveFn: func(_ context.Context, _ resource.Managed) (ExternalObservation, error) {
+								return ExternalObservation{ResourceExists: true, ResourceUpToDate: false}, nil
+							},
+							UpdateFn: func(_ context.Context, _ resource.Managed) (ExternalUpdate, error) {
+								return ExternalUpdate{}, errBoom
+							},
+						}
+						return c, nil
+					})),
+					WithConnectionPublishers(),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error { return nil }}),
+				},
+			},
+			want: want{result: reconcile.Result{Requeue: true}},
+		},
+		"UpdateExternalErrorTracked": {
+			reason: "Errors while updating an external resource should be recorded in annotations when operation tracking is enabled.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+						MockStatusUpdate: test.MockSubResourceUpdateFn(func(_ context.Context, obj client.Object, _ ...client.SubResourceUpdateOption) error {
+							want := &fake.Managed{}
 							want.SetConditions(xpv1.ReconcileError(errors.Wrap(errBoom, errReconcileUpdate)))
 							if diff := cmp.Diff(want, obj, test.EquateConditions()); diff != "" {
 								reason := "Errors while updating an external resource should be reported as a conditioned status."
@@ -969,6 +1393,7 @@ func TestReconciler(t *testing.T) {
 				},
 				mg: resource.ManagedKind(fake.GVK(&fake.Managed{})),
 				o: []ReconcilerOption{
+					WithOperationTracking(),
 					WithInitializers(),
 					WithReferenceResolver(ReferenceResolverFn(func(_ context.Context, _ resource.Managed) error { return nil })),
 					WithExternalConnecter(ExternalConnectorFn(func(_ context.Context, mg resource.Managed) (ExternalClient, error) {
@@ -982,6 +1407,17 @@ func TestReconciler(t *testing.T) {
 						}
 						return c, nil
 					})),
+					WithCriticalAnnotationUpdater(CriticalAnnotationUpdateFn(func(_ context.Context, o client.Object) error {
+						pending := meta.GetExternalUpdatePending(o)
+						failed, msg := meta.GetExternalUpdateFailed(o)
+						if pending.IsZero() || failed.IsZero() || !meta.GetExternalUpdateSucceeded(o).IsZero() {
+							t.Errorf("\nReason: %s\nWant pending and failed annotations, got %v", "A failed update should be recorded.", o.GetAnnotations())
+						}
+						if diff := cmp.Diff(errors.Wrap(errBoom, errReconcileUpdate).Error(), msg); diff != "" {
+							t.Errorf("\nReason: %s\n-want, +got:\n%s", "The update error should be recorded.", diff)
+						}
+						return nil
+					})),
 					WithConnectionPublishers(),
 					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error { return nil }}),
 				},
@@ -1077,6 +1513,83 @@ func TestReconciler(t *testing.T) {
 			},
 			want: want{result: reconcile.Result{RequeueAfter: defaultpollInterval}},
 		},
+		"UpdateSuccessfulWithReasons": {
+			reason: "The reasons an external resource was not up to date should be reported when it is updated.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+						MockStatusUpdate: test.MockSubResourceUpdateFn(func(_ context.Context, obj client.Object, _ ...client.SubResourceUpdateOption) error {
+							want := &fake.Managed{}
+							want.SetConditions(xpv1.ReconcileSuccess().WithMessage("Updated external resource. External resource is not up to date: tags differ; size differs"))
+							if diff := cmp.Diff(want, obj, test.EquateConditions()); diff != "" {
+								reason := "The reasons an external resource was not up to date should be reported in the Synced condition."
+								t.Errorf("\nReason: %s\n-want, +got:\n%s", reason, diff)
+							}
+							return nil
+						}),
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				mg: resource.ManagedKind(fake.GVK(&fake.Managed{})),
+				o: []ReconcilerOption{
+					WithInitializers(),
+					WithReferenceResolver(ReferenceResolverFn(func(_ context.Context, _ resource.Managed) error { return nil })),
+					WithExternalConnecter(ExternalConnectorFn(func(_ context.Context, mg resource.Managed) (ExternalClient, error) {
+						c := &ExternalClientFns{
+							ObserveFn: func(_ context.Context, _ resource.Managed) (ExternalObservation, error) {
+								return ExternalObservation{
+									ResourceExists:     true,
+									ResourceUpToDate:   false,
+									NotUpToDateReasons: []string{"tags differ", "size differs"},
+								}, nil
+							},
+							UpdateFn: func(_ context.Context, _ resource.Managed) (ExternalUpdate, error) {
+								return ExternalUpdate{}, nil
+							},
+						}
+						return c, nil
+					})),
+					WithConnectionPublishers(),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error { return nil }}),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: defaultpollInterval}},
+		},
+		"DriftIgnoredPaths": {
+			reason: "The field paths named by the drift ignore annotation should be supplied to the external client.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							meta.AddAnnotations(obj, map[string]string{meta.AnnotationKeyDriftIgnore: "spec.forProvider.tags"})
+							return nil
+						}),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				mg: resource.ManagedKind(fake.GVK(&fake.Managed{})),
+				o: []ReconcilerOption{
+					WithInitializers(),
+					WithReferenceResolver(ReferenceResolverFn(func(_ context.Context, _ resource.Managed) error { return nil })),
+					WithExternalConnecter(ExternalConnectorFn(func(_ context.Context, mg resource.Managed) (ExternalClient, error) {
+						c := &ExternalClientFns{
+							ObserveFn: func(ctx context.Context, _ resource.Managed) (ExternalObservation, error) {
+								if diff := cmp.Diff([]string{"spec.forProvider.tags"}, DriftIgnoredPaths(ctx)); diff != "" {
+									t.Errorf("\nReason: The drift ignored paths should be supplied to Observe.\n-want, +got:\n%s", diff)
+								}
+								return ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+							},
+						}
+						return c, nil
+					})),
+					WithConnectionPublishers(),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error { return nil }}),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: defaultpollInterval}},
+		},
 		"ReconciliationPausedSuccessful": {
 			reason: `If a managed resource has the pause annotation with value "true", there should be no further requeue requests.`,
 			args: args{
@@ -1235,6 +1748,87 @@ func TestReconciler(t *testing.T) {
 			},
 			want: want{result: reconcile.Result{Requeue: true}},
 		},
+		"ScopedManagementPoliciesOutOfScope": {
+			reason: `If management policies are enabled, but not for this managed resource, we should throw an error.`,
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							mg := obj.(*fake.Managed)
+							mg.SetManagementPolicy(xpv1.ManagementObserveOnly)
+							return nil
+						}),
+						MockStatusUpdate: test.MockSubResourceUpdateFn(func(_ context.Context, obj client.Object, _ ...client.SubResourceUpdateOption) error {
+							want := &fake.Managed{}
+							want.SetManagementPolicy(xpv1.ManagementObserveOnly)
+							want.SetConditions(xpv1.ReconcileError(errors.New(errManagementPolicy)))
+							if diff := cmp.Diff(want, obj, test.EquateConditions()); diff != "" {
+								reason := `If management policies are not enabled for the managed resource, it should return a proper error.`
+								t.Errorf("\nReason: %s\n-want, +got:\n%s", reason, diff)
+							}
+							return nil
+						}),
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				mg: resource.ManagedKind(fake.GVK(&fake.Managed{})),
+				o: []ReconcilerOption{
+					WithScopedManagementPolicies(func() *feature.Flags {
+						fs := &feature.Flags{}
+						fs.Enable(managementPolicies)
+						fs.SetScope(managementPolicies, feature.MatchingAnnotation("cool", "true"))
+						return fs
+					}(), managementPolicies),
+				},
+			},
+			want: want{result: reconcile.Result{}},
+		},
+		"ScopedManagementPoliciesInScope": {
+			reason: "If management policies are enabled for this managed resource, ObserveOnly should be honored.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							mg := obj.(*fake.Managed)
+							mg.SetAnnotations(map[string]string{"cool": "true"})
+							mg.SetManagementPolicy(xpv1.ManagementObserveOnly)
+							return nil
+						}),
+						MockStatusUpdate: test.MockSubResourceUpdateFn(func(_ context.Context, obj client.Object, _ ...client.SubResourceUpdateOption) error {
+							want := &fake.Managed{}
+							want.SetAnnotations(map[string]string{"cool": "true"})
+							want.SetManagementPolicy(xpv1.ManagementObserveOnly)
+							want.SetConditions(xpv1.ReconcileError(errors.Wrap(errors.New(errExternalResourceNotExist), errReconcileObserve)))
+							if diff := cmp.Diff(want, obj, test.EquateConditions()); diff != "" {
+								reason := "Resource does not exist should be reported as a conditioned status when ObserveOnly."
+								t.Errorf("\nReason: %s\n-want, +got:\n%s", reason, diff)
+							}
+							return nil
+						}),
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				mg: resource.ManagedKind(fake.GVK(&fake.Managed{})),
+				o: []ReconcilerOption{
+					WithInitializers(),
+					WithScopedManagementPolicies(func() *feature.Flags {
+						fs := &feature.Flags{}
+						fs.Enable(managementPolicies)
+						fs.SetScope(managementPolicies, feature.MatchingAnnotation("cool", "true"))
+						return fs
+					}(), managementPolicies),
+					WithExternalConnecter(ExternalConnectorFn(func(_ context.Context, mg resource.Managed) (ExternalClient, error) {
+						c := &ExternalClientFns{
+							ObserveFn: func(_ context.Context, _ resource.Managed) (ExternalObservation, error) {
+								return ExternalObservation{ResourceExists: false}, nil
+							},
+						}
+						return c, nil
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{Requeue: true}},
+		},
 		"ObserveOnlyPublishConnectionDetailsError": {
 			reason: "With ObserveOnly, errors publishing connection details after observation should trigger a requeue after a short wait.",
 			args: args{
@@ -1477,3 +2071,373 @@ func TestShouldOrphan(t *testing.T) {
 		})
 	}
 }
+
+func TestReportLateInitConflicts(t *testing.T) {
+	errBoom := errors.New("boom")
+	conflict := resource.LateInitConflict{Path: "spec.forProvider.region", LateInitialized: `"a"`, Desired: `"b"`, Observed: `"c"`}
+
+	type args struct {
+		mg        *fake.Managed
+		conflicts []resource.LateInitConflict
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []xpv1.Condition
+	}{
+		"NoConflicts": {
+			reason: "The condition should not be added if there have never been conflicts.",
+			args: args{
+				mg: &fake.Managed{},
+			},
+		},
+		"Resolved": {
+			reason: "The condition should become true once conflicts are resolved.",
+			args: args{
+				mg: &fake.Managed{ConditionedStatus: xpv1.ConditionedStatus{
+					Conditions: []xpv1.Condition{xpv1.LateInitConflict(errBoom)},
+				}},
+			},
+			want: []xpv1.Condition{xpv1.LateInitConsistent()},
+		},
+		"Conflicts": {
+			reason: "Conflicts should be reported using the condition.",
+			args: args{
+				mg:        &fake.Managed{},
+				conflicts: []resource.LateInitConflict{conflict},
+			},
+			want: []xpv1.Condition{xpv1.LateInitConflict(errors.Wrap(errors.New(conflict.String()), errLateInitConflict))},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			reportLateInitConflicts(tc.args.mg, event.NewNopRecorder(), tc.args.conflicts)
+			if diff := cmp.Diff(tc.want, tc.args.mg.Conditions, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\nreportLateInitConflicts(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRecordCalls(t *testing.T) {
+	type args struct {
+		calls  int
+		budget int
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []event.Event
+	}{
+		"NoBudget": {
+			reason: "No warning should be emitted if there is no call budget.",
+			args: args{
+				calls: 100,
+			},
+		},
+		"WithinBudget": {
+			reason: "No warning should be emitted if the reconcile made no more calls than its budget.",
+			args: args{
+				calls:  5,
+				budget: 5,
+			},
+		},
+		"ExceededBudget": {
+			reason: "A warning should be emitted if the reconcile made more calls than its budget.",
+			args: args{
+				calls:  6,
+				budget: 5,
+			},
+			want: []event.Event{event.Warning(reasonExternalCallBudget, errors.Errorf(errFmtExternalCallBudget, 6, 5))},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := metrics.WithCallCounter(context.Background())
+			for i := 0; i < tc.args.calls; i++ {
+				metrics.CountCall(ctx)
+			}
+			er := &eventCollector{}
+			r := &Reconciler{callBudget: tc.args.budget, metrics: metrics.NewNopRecorder(), record: er}
+
+			r.recordCalls(ctx, logging.NewNopLogger(), &fake.Managed{})
+			if diff := cmp.Diff(tc.want, er.events); diff != "" {
+				t.Errorf("\n%s\nr.recordCalls(...): -want events, +got events:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// An eventCollector collects the events it records.
+type eventCollector struct {
+	events []event.Event
+}
+
+func (c *eventCollector) Event(_ runtime.Object, e event.Event) {
+	c.events = append(c.events, e)
+}
+
+func (c *eventCollector) WithAnnotations(_ ...string) event.Recorder {
+	return c
+}
+
+func TestRecordTimeToReady(t *testing.T) {
+	now := time.Now()
+	created := metav1.NewTime(now.Add(-10 * time.Minute))
+	earlier := &metav1.Duration{Duration: time.Minute}
+
+	type args struct {
+		current  xpv1.Condition
+		previous xpv1.Condition
+		recorded *metav1.Duration
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   *metav1.Duration
+	}{
+		"NotReady": {
+			reason: "The time to readiness should not be recorded if the managed resource isn't ready.",
+			args: args{
+				current:  xpv1.Creating(),
+				previous: xpv1.Condition{},
+			},
+		},
+		"FirstReady": {
+			reason: "The time since creation should be recorded when the managed resource first becomes ready.",
+			args: args{
+				current:  xpv1.Available(),
+				previous: xpv1.Creating(),
+			},
+			want: &metav1.Duration{Duration: 10 * time.Minute},
+		},
+		"ReadyAgain": {
+			reason: "The time to readiness should not be recorded if the managed resource was ready before.",
+			args: args{
+				current:  xpv1.Available(),
+				previous: xpv1.Unavailable(),
+			},
+		},
+		"AlreadyRecorded": {
+			reason: "A previously recorded time to readiness should not be overwritten.",
+			args: args{
+				current:  xpv1.Available(),
+				previous: xpv1.Condition{},
+				recorded: earlier,
+			},
+			want: earlier,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &timeToReadyRecorder{Managed: fake.Managed{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: created}}, ttr: tc.args.recorded}
+			mg.SetConditions(tc.args.current)
+
+			recordTimeToReady(mg, tc.args.previous, now)
+			if diff := cmp.Diff(tc.want, mg.ttr); diff != "" {
+				t.Errorf("\n%s\nrecordTimeToReady(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// A timeToReadyRecorder is a managed resource that records its time to
+// readiness.
+type timeToReadyRecorder struct {
+	fake.Managed
+	ttr *metav1.Duration
+}
+
+func (m *timeToReadyRecorder) SetTimeToReady(d *metav1.Duration) { m.ttr = d }
+func (m *timeToReadyRecorder) GetTimeToReady() *metav1.Duration  { return m.ttr }
+
+type adoptingPublisher struct {
+	ConnectionPublisherFns
+	adopt func(ctx context.Context, so resource.ConnectionSecretOwner, previous types.UID) error
+}
+
+func (p *adoptingPublisher) AdoptConnection(ctx context.Context, so resource.ConnectionSecretOwner, previous types.UID) error {
+	return p.adopt(ctx, so, previous)
+}
+
+func TestWithFieldManagerInitializers(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		o      []ReconcilerOption
+		want   []string
+	}{
+		"InitializerBeforeFieldManager": {
+			reason: "Writes made by initializers configured before the field manager should be attributed to it.",
+			o:      []ReconcilerOption{WithOwnerLabels(), WithFieldManager("cool-manager")},
+			want:   []string{"cool-manager"},
+		},
+		"InitializerAfterFieldManager": {
+			reason: "Writes made by initializers configured after the field manager should be attributed to it.",
+			o:      []ReconcilerOption{WithFieldManager("cool-manager"), WithOwnerLabels()},
+			want:   []string{"cool-manager"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			m := &fake.Manager{
+				Client: &test.MockClient{MockUpdate: func(_ context.Context, _ client.Object, opts ...client.UpdateOption) error {
+					got = append(got, (&client.UpdateOptions{}).ApplyOptions(opts).FieldManager)
+					return nil
+				}},
+				Scheme: fake.SchemeWith(&fake.Managed{}),
+			}
+			r := NewReconciler(m, resource.ManagedKind(fake.GVK(&fake.Managed{})), tc.o...)
+
+			// An orphaned managed resource with an external name, so that only
+			// the OwnerLabeler writes, to remove its stale composite labels.
+			mg := &fake.Managed{ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{meta.AnnotationKeyExternalName: "cool"},
+				Labels:      map[string]string{meta.LabelKeyComposite: "xcool"},
+			}}
+			if err := r.managed.Initialize(context.Background(), mg); err != nil {
+				t.Fatalf("\n%s\nr.managed.Initialize(...): %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nr.managed.Initialize(...) field managers: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestWithFieldManager(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		o      []ReconcilerOption
+		want   string
+	}{
+		"NoFieldManager": {
+			reason: "Writes should not be attributed to a field manager by default.",
+			want:   "",
+		},
+		"FieldManager": {
+			reason: "Writes made by the default finalizer should be attributed to the field manager.",
+			o:      []ReconcilerOption{WithFieldManager("cool-manager")},
+			want:   "cool-manager",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ""
+			m := &fake.Manager{
+				Client: &test.MockClient{MockUpdate: func(_ context.Context, _ client.Object, opts ...client.UpdateOption) error {
+					got = (&client.UpdateOptions{}).ApplyOptions(opts).FieldManager
+					return nil
+				}},
+				Scheme: fake.SchemeWith(&fake.Managed{}),
+			}
+			r := NewReconciler(m, resource.ManagedKind(fake.GVK(&fake.Managed{})), tc.o...)
+			if err := r.managed.AddFinalizer(context.Background(), &fake.Managed{}); err != nil {
+				t.Fatalf("\n%s\nr.managed.AddFinalizer(...): %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nr.managed.AddFinalizer(...) field manager: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestReconcileErrorAndRequeue(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		reason xpv1.ConditionReason
+		result reconcile.Result
+	}
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   want
+	}{
+		"Unknown": {
+			reason: "Errors without a code should be reported as reconcile errors, and requeued with backoff.",
+			err:    errBoom,
+			want: want{
+				reason: xpv1.ReasonReconcileError,
+				result: reconcile.Result{Requeue: true},
+			},
+		},
+		"Terminal": {
+			reason: "Terminal errors should be reported as terminal, and not requeued.",
+			err:    errors.Wrap(errors.Terminal(errBoom), errReconcileObserve),
+			want: want{
+				reason: xpv1.ReasonReconcileTerminalError,
+				result: reconcile.Result{},
+			},
+		},
+		"Throttled": {
+			reason: "Throttled errors should be reported as throttled, and requeued after a fixed wait.",
+			err:    errors.Wrap(errors.WithCode(errBoom, errors.CodeThrottled), errReconcileObserve),
+			want: want{
+				reason: xpv1.ReasonReconcileThrottled,
+				result: reconcile.Result{RequeueAfter: throttledWait},
+			},
+		},
+		"Unauthorized": {
+			reason: "Unauthorized errors should be reported as unauthorized, and requeued after a fixed wait.",
+			err:    errors.WithCode(errBoom, errors.CodeUnauthorized),
+			want: want{
+				reason: xpv1.ReasonReconcileUnauthorized,
+				result: reconcile.Result{RequeueAfter: unauthorizedWait},
+			},
+		},
+		"InvalidInput": {
+			reason: "Invalid input errors should be reported as invalid input, and requeued after a fixed wait.",
+			err:    errors.WithCode(errBoom, errors.CodeInvalidInput),
+			want: want{
+				reason: xpv1.ReasonReconcileInvalidInput,
+				result: reconcile.Result{RequeueAfter: invalidInputWait},
+			},
+		},
+		"NotFound": {
+			reason: "Not found errors should be reported as not found, and requeued with backoff.",
+			err:    errors.WithCode(errBoom, errors.CodeNotFound),
+			want: want{
+				reason: xpv1.ReasonReconcileNotFound,
+				result: reconcile.Result{Requeue: true},
+			},
+		},
+		"Conflict": {
+			reason: "Conflict errors should be reported as conflicts, and requeued with backoff.",
+			err:    errors.WithCode(errBoom, errors.CodeConflict),
+			want: want{
+				reason: xpv1.ReasonReconcileConflict,
+				result: reconcile.Result{Requeue: true},
+			},
+		},
+		"RetryableThrottled": {
+			reason: "Errors marked retryable should keep the reason and requeue policy of their code.",
+			err:    errors.Retryable(errors.WithCode(errBoom, errors.CodeThrottled)),
+			want: want{
+				reason: xpv1.ReasonReconcileThrottled,
+				result: reconcile.Result{RequeueAfter: throttledWait},
+			},
+		},
+		"JoinedTerminalAndUnknown": {
+			reason: "Joined errors that aren't all terminal should be reported as reconcile errors, and requeued with backoff.",
+			err:    errors.Join(errors.Terminal(errBoom), errBoom),
+			want: want{
+				reason: xpv1.ReasonReconcileError,
+				result: reconcile.Result{Requeue: true},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want.reason, reconcileError(tc.err).Reason); diff != "" {
+				t.Errorf("\n%s\nreconcileError(...): -want reason, +got reason:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.result, requeueOnError(tc.err)); diff != "" {
+				t.Errorf("\n%s\nrequeueOnError(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
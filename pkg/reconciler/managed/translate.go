@@ -0,0 +1,74 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// An ErrorTranslator translates errors returned by an ExternalConnecter or
+// ExternalClient, for example by annotating them with a standard error code
+// and a human readable message. A *translate.Registry is an ErrorTranslator.
+type ErrorTranslator interface {
+	// Translate the supplied error. Translate must return nil if the
+	// supplied error is nil.
+	Translate(err error) error
+}
+
+// An ErrorTranslatorFn is a function that satisfies the ErrorTranslator
+// interface.
+type ErrorTranslatorFn func(err error) error
+
+// Translate the supplied error.
+func (fn ErrorTranslatorFn) Translate(err error) error {
+	return fn(err)
+}
+
+// A NopErrorTranslator does nothing.
+type NopErrorTranslator struct{}
+
+// Translate returns the supplied error unchanged.
+func (t NopErrorTranslator) Translate(err error) error {
+	return err
+}
+
+// A translatingClient translates the errors returned by an ExternalClient.
+type translatingClient struct {
+	client     ExternalClient
+	translator ErrorTranslator
+}
+
+func (c *translatingClient) Observe(ctx context.Context, mg resource.Managed) (ExternalObservation, error) {
+	o, err := c.client.Observe(ctx, mg)
+	return o, c.translator.Translate(err)
+}
+
+func (c *translatingClient) Create(ctx context.Context, mg resource.Managed) (ExternalCreation, error) {
+	cr, err := c.client.Create(ctx, mg)
+	return cr, c.translator.Translate(err)
+}
+
+func (c *translatingClient) Update(ctx context.Context, mg resource.Managed) (ExternalUpdate, error) {
+	u, err := c.client.Update(ctx, mg)
+	return u, c.translator.Translate(err)
+}
+
+func (c *translatingClient) Delete(ctx context.Context, mg resource.Managed) error {
+	return c.translator.Translate(c.client.Delete(ctx, mg))
+}
@@ -24,7 +24,10 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -45,6 +48,7 @@ const (
 	errGetPC        = "cannot get ProviderConfig"
 	errListPCUs     = "cannot list ProviderConfigUsages"
 	errDeletePCU    = "cannot delete ProviderConfigUsage"
+	errGetUser      = "cannot get managed resource using ProviderConfig"
 	errUpdate       = "cannot update ProviderConfig"
 	errUpdateStatus = "cannot update ProviderConfig status"
 )
@@ -87,6 +91,8 @@ type Reconciler struct {
 	newConfig    func() resource.ProviderConfig
 	newUsageList func() resource.ProviderConfigUsageList
 
+	collectUsages bool
+
 	log    logging.Logger
 	record event.Recorder
 }
@@ -108,6 +114,27 @@ func WithRecorder(er event.Recorder) ReconcilerOption {
 	}
 }
 
+// WithFieldManager specifies the field manager to which the Reconciler
+// attributes its writes.
+func WithFieldManager(name string) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.client = resource.NewFieldManagedClient(r.client, name)
+	}
+}
+
+// WithUsageGarbageCollection configures the Reconciler to delete
+// ProviderConfigUsages that are stale, for example because the managed
+// resource that used the ProviderConfig was force-deleted, or because another
+// usage tracks the same managed resource. Stale usages would otherwise block
+// deletion of the ProviderConfig indefinitely. Each managed resource that uses
+// the ProviderConfig is read once per reconcile to determine whether it
+// exists.
+func WithUsageGarbageCollection() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.collectUsages = true
+	}
+}
+
 // NewReconciler returns a Reconciler of ProviderConfigs.
 func NewReconciler(m manager.Manager, of resource.ProviderConfigKinds, o ...ReconcilerOption) *Reconciler {
 	nc := func() resource.ProviderConfig {
@@ -169,13 +196,34 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{RequeueAfter: shortWait}, nil
 	}
 
+	// Usages are named after the UID of the managed resource they track. Any
+	// other usage of the same managed resource is a duplicate.
+	canonical := map[types.UID]bool{}
+	for _, pcu := range l.GetItems() {
+		if c := metav1.GetControllerOf(pcu); c != nil && pcu.GetName() == string(c.UID) {
+			canonical[c.UID] = true
+		}
+	}
+	seen := map[types.UID]bool{}
+
 	users := int64(len(l.GetItems()))
 	for _, pcu := range l.GetItems() {
-		if metav1.GetControllerOf(pcu) == nil {
+		stale := metav1.GetControllerOf(pcu) == nil
+		if !stale && r.collectUsages {
+			var err error
+			if stale, err = r.isStale(ctx, pcu, canonical, seen); err != nil {
+				log.Debug(errGetUser, "error", err)
+				r.record.Event(pc, event.Warning(reasonAccount, errors.Wrap(err, errGetUser)))
+				return reconcile.Result{RequeueAfter: shortWait}, nil
+			}
+		}
+
+		if stale {
 			// Usages should always have a controller reference. If this one has
 			// none it's probably been stripped off (e.g. by a Velero restore).
 			// We can safely delete it - it's either stale, or will be recreated
-			// next time the relevant managed resource connects.
+			// next time the relevant managed resource connects. The same is
+			// true of usages found to be stale by garbage collection.
 			if err := r.client.Delete(ctx, pcu); resource.IgnoreNotFound(err) != nil {
 				log.Debug(errDeletePCU, "error", err)
 				r.record.Event(pc, event.Warning(reasonAccount, errors.Wrap(err, errDeletePCU)))
@@ -219,3 +267,36 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	pc.SetUsers(users)
 	return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, pc), errUpdateStatus)
 }
+
+// isStale returns true if the supplied usage's managed resource no longer
+// exists, or if the usage duplicates another usage of the same managed
+// resource.
+func (r *Reconciler) isStale(ctx context.Context, pcu resource.ProviderConfigUsage, canonical, seen map[types.UID]bool) (bool, error) {
+	owner := metav1.GetControllerOf(pcu)
+	ref := pcu.GetResourceReference()
+
+	mg := &metav1.PartialObjectMetadata{}
+	mg.SetGroupVersionKind(schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind))
+	if err := r.client.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, mg); err != nil {
+		if kerrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+
+	// The managed resource was deleted and recreated with the same name.
+	if mg.GetUID() != owner.UID {
+		return true, nil
+	}
+
+	// Keep the usage named after the managed resource's UID if there is one,
+	// otherwise the first usage we see.
+	if canonical[owner.UID] {
+		return pcu.GetName() != string(owner.UID), nil
+	}
+	if seen[owner.UID] {
+		return true, nil
+	}
+	seen[owner.UID] = true
+	return false, nil
+}
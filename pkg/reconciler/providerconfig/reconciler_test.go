@@ -31,6 +31,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
@@ -68,9 +69,22 @@ func TestReconciler(t *testing.T) {
 	uid := types.UID("so-unique")
 	ctrl := true
 
+	usage := func(name string, owner types.UID, mg string) resource.ProviderConfigUsage {
+		return &fake.ProviderConfigUsage{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				OwnerReferences: []metav1.OwnerReference{{UID: owner, Controller: &ctrl}},
+			},
+			RequiredTypedResourceReferencer: fake.RequiredTypedResourceReferencer{
+				Ref: xpv1.TypedReference{APIVersion: "example.org/v1", Kind: "Cool", Name: mg},
+			},
+		}
+	}
+
 	type args struct {
 		m  manager.Manager
 		of resource.ProviderConfigKinds
+		o  []ReconcilerOption
 	}
 
 	type want struct {
@@ -294,6 +308,138 @@ func TestReconciler(t *testing.T) {
 				err:    errors.Wrap(errBoom, errUpdateStatus),
 			},
 		},
+		"GetUserError": {
+			reason: "We should requeue after a short wait if we can't determine whether a usage is stale",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+							if _, ok := obj.(*metav1.PartialObjectMetadata); ok {
+								return errBoom
+							}
+							return nil
+						},
+						MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+							l := obj.(*ProviderConfigUsageList)
+							l.Items = []resource.ProviderConfigUsage{usage(string(uid), uid, "cool")}
+							return nil
+						}),
+					},
+					Scheme: fake.SchemeWith(&fake.ProviderConfig{}, &ProviderConfigUsageList{}),
+				},
+				of: resource.ProviderConfigKinds{
+					Config:    fake.GVK(&fake.ProviderConfig{}),
+					UsageList: fake.GVK(&ProviderConfigUsageList{}),
+				},
+				o: []ReconcilerOption{WithUsageGarbageCollection()},
+			},
+			want: want{
+				result: reconcile.Result{RequeueAfter: shortWait},
+			},
+		},
+		"CollectStaleUsages": {
+			reason: "We should delete usages of managed resources that no longer exist, and duplicate usages",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+							mg, ok := obj.(*metav1.PartialObjectMetadata)
+							if !ok {
+								return nil
+							}
+							switch key.Name {
+							case "gone":
+								return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+							case "recreated":
+								mg.SetUID("new-uid")
+							default:
+								mg.SetUID(uid)
+							}
+							return nil
+						},
+						MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+							l := obj.(*ProviderConfigUsageList)
+							l.Items = []resource.ProviderConfigUsage{
+								usage("gone-usage", "gone-uid", "gone"),
+								usage("recreated-usage", "old-uid", "recreated"),
+								usage("duplicate-usage", uid, "cool"),
+								usage(string(uid), uid, "cool"),
+							}
+							return nil
+						}),
+						MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+							if obj.GetName() == string(uid) {
+								t.Errorf("Delete(...): unexpectedly deleted usage %q", obj.GetName())
+							}
+							return nil
+						},
+						MockUpdate: test.NewMockUpdateFn(nil),
+						MockStatusUpdate: func(_ context.Context, obj client.Object, _ ...client.SubResourceUpdateOption) error {
+							if diff := cmp.Diff(int64(1), obj.(*fake.ProviderConfig).GetUsers()); diff != "" {
+								t.Errorf("StatusUpdate(...): -want users, +got users:\n%s", diff)
+							}
+							return nil
+						},
+					},
+					Scheme: fake.SchemeWith(&fake.ProviderConfig{}, &ProviderConfigUsageList{}),
+				},
+				of: resource.ProviderConfigKinds{
+					Config:    fake.GVK(&fake.ProviderConfig{}),
+					UsageList: fake.GVK(&ProviderConfigUsageList{}),
+				},
+				o: []ReconcilerOption{WithUsageGarbageCollection()},
+			},
+			want: want{
+				result: reconcile.Result{Requeue: false},
+			},
+		},
+		"KeepNamespacedUsages": {
+			reason: "We should get namespaced managed resources from their namespace, and keep their usages",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+							mg, ok := obj.(*metav1.PartialObjectMetadata)
+							if !ok {
+								return nil
+							}
+							if key.Namespace != "cool-ns" {
+								return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+							}
+							mg.SetUID(uid)
+							return nil
+						},
+						MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+							l := obj.(*ProviderConfigUsageList)
+							u := usage(string(uid), uid, "cool").(*fake.ProviderConfigUsage)
+							u.RequiredTypedResourceReferencer.Ref.Namespace = "cool-ns"
+							l.Items = []resource.ProviderConfigUsage{u}
+							return nil
+						}),
+						MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+							t.Errorf("Delete(...): unexpectedly deleted usage %q", obj.GetName())
+							return nil
+						},
+						MockUpdate: test.NewMockUpdateFn(nil),
+						MockStatusUpdate: func(_ context.Context, obj client.Object, _ ...client.SubResourceUpdateOption) error {
+							if diff := cmp.Diff(int64(1), obj.(*fake.ProviderConfig).GetUsers()); diff != "" {
+								t.Errorf("StatusUpdate(...): -want users, +got users:\n%s", diff)
+							}
+							return nil
+						},
+					},
+					Scheme: fake.SchemeWith(&fake.ProviderConfig{}, &ProviderConfigUsageList{}),
+				},
+				of: resource.ProviderConfigKinds{
+					Config:    fake.GVK(&fake.ProviderConfig{}),
+					UsageList: fake.GVK(&ProviderConfigUsageList{}),
+				},
+				o: []ReconcilerOption{WithUsageGarbageCollection()},
+			},
+			want: want{
+				result: reconcile.Result{Requeue: false},
+			},
+		},
 		"SuccessfulSetUsers": {
 			reason: "We should return without requeuing if we successfully update our user count",
 			args: args{
@@ -319,7 +465,7 @@ func TestReconciler(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			r := NewReconciler(tc.args.m, tc.args.of)
+			r := NewReconciler(tc.args.m, tc.args.of, tc.args.o...)
 			got, err := r.Reconcile(context.Background(), reconcile.Request{})
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
@@ -21,8 +21,11 @@ package reference
 import (
 	"context"
 	"strconv"
+	"time"
 
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -36,6 +39,7 @@ import (
 const (
 	errGetManaged  = "cannot get referenced resource"
 	errListManaged = "cannot list resources that match selector"
+	errSelector    = "cannot parse selector"
 	errNoMatches   = "no resources matched selector"
 	errNoValue     = "referenced field was empty (referenced resource may not yet be ready)"
 )
@@ -150,6 +154,11 @@ type ResolutionRequest struct {
 	Selector     *xpv1.Selector
 	To           To
 	Extract      ExtractValueFn
+
+	// FieldPath of the field the reference is resolved into. The outcome of
+	// resolution is recorded under this field path if the referencing
+	// managed resource is a resource.ReferenceResolutionRecorder.
+	FieldPath string
 }
 
 // IsNoOp returns true if the supplied ResolutionRequest cannot or should not be
@@ -204,6 +213,11 @@ type MultiResolutionRequest struct {
 	Selector      *xpv1.Selector
 	To            To
 	Extract       ExtractValueFn
+
+	// FieldPath of the field the references are resolved into. The outcome
+	// of resolution is recorded under this field path if the referencing
+	// managed resource is a resource.ReferenceResolutionRecorder.
+	FieldPath string
 }
 
 // IsNoOp returns true if the supplied MultiResolutionRequest cannot or should
@@ -261,18 +275,43 @@ func (rr MultiResolutionResponse) Validate() error {
 	return nil
 }
 
+// An APIResolverOption configures an APIResolver.
+type APIResolverOption func(r *APIResolver)
+
+// WithListPageSize configures the number of managed resources an APIResolver
+// lists per page when selecting references. A page size of zero or less lists
+// all matching managed resources in a single page.
+func WithListPageSize(n int64) APIResolverOption {
+	return func(r *APIResolver) {
+		r.pageSize = n
+	}
+}
+
 // An APIResolver selects and resolves references to managed resources in the
 // Kubernetes API server.
 type APIResolver struct {
-	client client.Reader
-	from   resource.Managed
+	client   client.Reader
+	from     resource.Managed
+	pageSize int64
+	now      func() time.Time
 }
 
 // NewAPIResolver returns a Resolver that selects and resolves references from
 // the supplied managed resource to other managed resources in the Kubernetes
-// API server.
-func NewAPIResolver(c client.Reader, from resource.Managed) *APIResolver {
-	return &APIResolver{client: c, from: from}
+// API server. Managed resources are listed resource.DefaultListPageSize at a
+// time when selecting references. References from a namespaced managed
+// resource are resolved and selected within its namespace.
+//
+// If the supplied managed resource is a resource.ReferenceResolutionRecorder
+// the outcome of each request with a FieldPath is recorded, including which
+// resource satisfied it. A reference that is resolved again is not extracted
+// again unless the resource it refers to changed since it was recorded.
+func NewAPIResolver(c client.Reader, from resource.Managed, o ...APIResolverOption) *APIResolver {
+	r := &APIResolver{client: c, from: from, pageSize: resource.DefaultListPageSize, now: time.Now}
+	for _, fn := range o {
+		fn(r)
+	}
+	return r
 }
 
 // Resolve the supplied ResolutionRequest. The returned ResolutionResponse
@@ -285,29 +324,49 @@ func (r *APIResolver) Resolve(ctx context.Context, req ResolutionRequest) (Resol
 
 	// The reference is already set - resolve it.
 	if req.Reference != nil {
-		if err := r.client.Get(ctx, types.NamespacedName{Name: req.Reference.Name}, req.To.Managed); err != nil {
+		if err := r.client.Get(ctx, types.NamespacedName{Namespace: r.from.GetNamespace(), Name: req.Reference.Name}, req.To.Managed); err != nil {
 			if kerrors.IsNotFound(err) {
 				return ResolutionResponse{}, getResolutionError(req.Reference.Policy, errors.Wrap(err, errGetManaged))
 			}
 			return ResolutionResponse{}, errors.Wrap(err, errGetManaged)
 		}
 
-		rsp := ResolutionResponse{ResolvedValue: req.Extract(req.To.Managed), ResolvedReference: req.Reference}
-		return rsp, getResolutionError(req.Reference.Policy, rsp.Validate())
+		rsp := ResolutionResponse{ResolvedValue: r.extract(req.FieldPath, req.To.Managed, req.Extract), ResolvedReference: req.Reference}
+		if err := rsp.Validate(); err != nil {
+			return rsp, getResolutionError(req.Reference.Policy, err)
+		}
+		r.record(req.FieldPath, resolved(req.To.Managed, rsp.ResolvedValue))
+		return rsp, nil
 	}
 
 	// The reference was not set, but a selector was. Select a reference.
-	if err := r.client.List(ctx, req.To.List, client.MatchingLabels(req.Selector.MatchLabels)); err != nil {
+	var rsp *ResolutionResponse
+	var selected xpv1.ResolvedReference
+	selectFirst := func() bool {
+		for _, to := range req.To.List.GetItems() {
+			if ControllersMustMatch(req.Selector) && !meta.HaveSameController(r.from, to) {
+				continue
+			}
+			rsp = &ResolutionResponse{ResolvedValue: req.Extract(to), ResolvedReference: &xpv1.Reference{Name: to.GetName()}}
+			selected = resolved(to, rsp.ResolvedValue)
+			return false
+		}
+		return true
+	}
+	sel, err := LabelSelector(req.Selector)
+	if err != nil {
+		return ResolutionResponse{}, errors.Wrap(err, errSelector)
+	}
+	if err := resource.ListPages(ctx, r.client, req.To.List, r.pageSize, selectFirst, client.MatchingLabelsSelector{Selector: sel}, client.InNamespace(r.from.GetNamespace())); err != nil {
 		return ResolutionResponse{}, errors.Wrap(err, errListManaged)
 	}
 
-	for _, to := range req.To.List.GetItems() {
-		if ControllersMustMatch(req.Selector) && !meta.HaveSameController(r.from, to) {
-			continue
+	if rsp != nil {
+		if err := rsp.Validate(); err != nil {
+			return *rsp, getResolutionError(req.Selector.Policy, err)
 		}
-
-		rsp := ResolutionResponse{ResolvedValue: req.Extract(to), ResolvedReference: &xpv1.Reference{Name: to.GetName()}}
-		return rsp, getResolutionError(req.Selector.Policy, rsp.Validate())
+		r.record(req.FieldPath, selected)
+		return *rsp, nil
 	}
 
 	// We couldn't resolve anything.
@@ -327,39 +386,128 @@ func (r *APIResolver) ResolveMultiple(ctx context.Context, req MultiResolutionRe
 	// The references are already set - resolve them.
 	if len(req.References) > 0 {
 		vals := make([]string, len(req.References))
+		rec := make([]xpv1.ResolvedReference, len(req.References))
 		for i := range req.References {
-			if err := r.client.Get(ctx, types.NamespacedName{Name: req.References[i].Name}, req.To.Managed); err != nil {
+			if err := r.client.Get(ctx, types.NamespacedName{Namespace: r.from.GetNamespace(), Name: req.References[i].Name}, req.To.Managed); err != nil {
 				if kerrors.IsNotFound(err) {
 					return MultiResolutionResponse{}, getResolutionError(req.References[i].Policy, errors.Wrap(err, errGetManaged))
 				}
 				return MultiResolutionResponse{}, errors.Wrap(err, errGetManaged)
 			}
-			vals[i] = req.Extract(req.To.Managed)
+			vals[i] = r.extract(req.FieldPath, req.To.Managed, req.Extract)
+			rec[i] = resolved(req.To.Managed, vals[i])
 		}
 
 		rsp := MultiResolutionResponse{ResolvedValues: vals, ResolvedReferences: req.References}
-		return rsp, rsp.Validate()
+		if err := rsp.Validate(); err != nil {
+			return rsp, err
+		}
+		r.record(req.FieldPath, rec...)
+		return rsp, nil
 	}
 
 	// No references were set, but a selector was. Select and resolve references.
-	if err := r.client.List(ctx, req.To.List, client.MatchingLabels(req.Selector.MatchLabels)); err != nil {
+	refs := make([]xpv1.Reference, 0)
+	vals := make([]string, 0)
+	rec := make([]xpv1.ResolvedReference, 0)
+	selectAll := func() bool {
+		for _, to := range req.To.List.GetItems() {
+			if ControllersMustMatch(req.Selector) && !meta.HaveSameController(r.from, to) {
+				continue
+			}
+
+			vals = append(vals, req.Extract(to))
+			refs = append(refs, xpv1.Reference{Name: to.GetName()})
+			rec = append(rec, resolved(to, vals[len(vals)-1]))
+		}
+		return true
+	}
+	sel, err := LabelSelector(req.Selector)
+	if err != nil {
+		return MultiResolutionResponse{}, errors.Wrap(err, errSelector)
+	}
+	if err := resource.ListPages(ctx, r.client, req.To.List, r.pageSize, selectAll, client.MatchingLabelsSelector{Selector: sel}, client.InNamespace(r.from.GetNamespace())); err != nil {
 		return MultiResolutionResponse{}, errors.Wrap(err, errListManaged)
 	}
 
-	items := req.To.List.GetItems()
-	refs := make([]xpv1.Reference, 0, len(items))
-	vals := make([]string, 0, len(items))
-	for _, to := range req.To.List.GetItems() {
-		if ControllersMustMatch(req.Selector) && !meta.HaveSameController(r.from, to) {
-			continue
+	rsp := MultiResolutionResponse{ResolvedValues: vals, ResolvedReferences: refs}
+	if err := rsp.Validate(); err != nil {
+		return rsp, getResolutionError(req.Selector.Policy, err)
+	}
+	r.record(req.FieldPath, rec...)
+	return rsp, nil
+}
+
+// resolved returns the outcome of resolving the supplied value from the
+// supplied resource.
+func resolved(to resource.Managed, value string) xpv1.ResolvedReference {
+	return xpv1.ResolvedReference{
+		Name:            to.GetName(),
+		UID:             to.GetUID(),
+		ResourceVersion: to.GetResourceVersion(),
+		Value:           value,
+	}
+}
+
+// extract the value of the supplied field path from the supplied resource,
+// unless the resource hasn't changed since the value was recorded.
+func (r *APIResolver) extract(path string, to resource.Managed, fn ExtractValueFn) string {
+	rec, ok := r.from.(resource.ReferenceResolutionRecorder)
+	if !ok || path == "" {
+		return fn(to)
+	}
+	for _, rr := range rec.GetResolvedReferences() {
+		if rr.FieldPath == path && rr.UID == to.GetUID() && rr.ResourceVersion != "" && rr.ResourceVersion == to.GetResourceVersion() {
+			return rr.Value
 		}
+	}
+	return fn(to)
+}
 
-		vals = append(vals, req.Extract(to))
-		refs = append(refs, xpv1.Reference{Name: to.GetName()})
+// record the supplied outcomes of resolving the supplied field path, replacing
+// any previously recorded outcomes. An outcome's resolved time is preserved if
+// its value and the resource that satisfied it didn't change.
+func (r *APIResolver) record(path string, out ...xpv1.ResolvedReference) {
+	rec, ok := r.from.(resource.ReferenceResolutionRecorder)
+	if !ok || path == "" {
+		return
 	}
 
-	rsp := MultiResolutionResponse{ResolvedValues: vals, ResolvedReferences: refs}
-	return rsp, getResolutionError(req.Selector.Policy, rsp.Validate())
+	existing := rec.GetResolvedReferences()
+	prev := make(map[string]xpv1.ResolvedReference)
+	for _, rr := range existing {
+		if rr.FieldPath == path {
+			prev[rr.Name] = rr
+		}
+	}
+
+	now := metav1.NewTime(r.now())
+	for i := range out {
+		out[i].FieldPath = path
+		out[i].ResolvedAt = now
+		if p, ok := prev[out[i].Name]; ok && p.UID == out[i].UID && p.Value == out[i].Value {
+			out[i].ResolvedAt = p.ResolvedAt
+		}
+	}
+
+	// Replace the outcomes in place, so that recording the same outcomes
+	// doesn't reorder them.
+	updated := make([]xpv1.ResolvedReference, 0, len(existing)+len(out))
+	replaced := false
+	for _, rr := range existing {
+		if rr.FieldPath != path {
+			updated = append(updated, rr)
+			continue
+		}
+		if !replaced {
+			updated = append(updated, out...)
+			replaced = true
+		}
+	}
+	if !replaced {
+		updated = append(updated, out...)
+	}
+	rec.SetResolvedReferences(updated)
 }
 
 func getResolutionError(p *xpv1.Policy, err error) error {
@@ -369,6 +517,15 @@ func getResolutionError(p *xpv1.Policy, err error) error {
 	return nil
 }
 
+// LabelSelector returns a label selector that matches the labels and set-based
+// requirements of the supplied Selector. A nil Selector matches everything.
+func LabelSelector(s *xpv1.Selector) (labels.Selector, error) {
+	if s == nil {
+		return labels.Everything(), nil
+	}
+	return metav1.LabelSelectorAsSelector(&metav1.LabelSelector{MatchLabels: s.MatchLabels, MatchExpressions: s.MatchExpressions})
+}
+
 // ControllersMustMatch returns true if the supplied Selector requires that a
 // reference be to a managed resource whose controller reference matches the
 // referencing resource.
@@ -19,6 +19,7 @@ package reference
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -40,13 +41,18 @@ import (
 type FakeManagedList struct {
 	client.ObjectList
 
-	Items []resource.Managed
+	Items    []resource.Managed
+	Continue string
 }
 
 func (fml *FakeManagedList) GetItems() []resource.Managed {
 	return fml.Items
 }
 
+func (fml *FakeManagedList) GetContinue() string {
+	return fml.Continue
+}
+
 func TestToAndFromPtr(t *testing.T) {
 	cases := map[string]struct {
 		want string
@@ -140,6 +146,9 @@ func TestResolve(t *testing.T) {
 	meta.SetExternalName(controlled, value)
 	meta.AddControllerReference(controlled, meta.AsController(&xpv1.TypedReference{UID: types.UID("very-unique")}))
 
+	invalid := metav1.LabelSelectorRequirement{Key: "env", Operator: "Bogus"}
+	_, errInvalid := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{invalid}})
+
 	type args struct {
 		ctx context.Context
 		req ResolutionRequest
@@ -273,6 +282,32 @@ func TestResolve(t *testing.T) {
 				},
 			},
 		},
+		"SuccessfulNamespacedResolve": {
+			reason: "References from a namespaced managed resource should be resolved within its namespace",
+			c: &test.MockClient{
+				MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+					if key.Namespace != "cool-ns" {
+						return errBoom
+					}
+					meta.SetExternalName(obj.(metav1.Object), value)
+					return nil
+				},
+			},
+			from: &fake.Managed{ObjectMeta: metav1.ObjectMeta{Namespace: "cool-ns"}},
+			args: args{
+				req: ResolutionRequest{
+					Reference: ref,
+					To:        To{Managed: &fake.Managed{}},
+					Extract:   ExternalName(),
+				},
+			},
+			want: want{
+				rsp: ResolutionResponse{
+					ResolvedValue:     value,
+					ResolvedReference: ref,
+				},
+			},
+		},
 		"OptionalReference": {
 			reason: "No error should be returned when the resolution policy is Optional",
 			c: &test.MockClient{
@@ -309,6 +344,54 @@ func TestResolve(t *testing.T) {
 				err: errors.Wrap(errBoom, errListManaged),
 			},
 		},
+		"InvalidSelector": {
+			reason: "Should return an error when the selector's requirements are invalid",
+			from:   &fake.Managed{},
+			args: args{
+				req: ResolutionRequest{
+					Selector: &xpv1.Selector{MatchExpressions: []metav1.LabelSelectorRequirement{invalid}},
+				},
+			},
+			want: want{
+				rsp: ResolutionResponse{},
+				err: errors.Wrap(errInvalid, errSelector),
+			},
+		},
+		"MatchExpressions": {
+			reason: "Should list resources using the selector's labels and set-based requirements",
+			c: &test.MockClient{
+				MockList: func(_ context.Context, obj client.ObjectList, opts ...client.ListOption) error {
+					lo := &client.ListOptions{}
+					lo.ApplyOptions(opts)
+					if got := lo.LabelSelector.String(); got != "cool=true,env in (dev,prod)" {
+						return errors.Errorf("unexpected label selector %q", got)
+					}
+					obj.(*FakeManagedList).Items = []resource.Managed{controlled}
+					return nil
+				},
+			},
+			from: &fake.Managed{},
+			args: args{
+				req: ResolutionRequest{
+					Selector: &xpv1.Selector{
+						MatchLabels: map[string]string{"cool": "true"},
+						MatchExpressions: []metav1.LabelSelectorRequirement{{
+							Key:      "env",
+							Operator: metav1.LabelSelectorOpIn,
+							Values:   []string{"dev", "prod"},
+						}},
+					},
+					To:      To{List: &FakeManagedList{}},
+					Extract: ExternalName(),
+				},
+			},
+			want: want{
+				rsp: ResolutionResponse{
+					ResolvedValue:     value,
+					ResolvedReference: &xpv1.Reference{Name: value},
+				},
+			},
+		},
 		"NoMatches": {
 			reason: "Should return an error when no managed resources match the selector",
 			c: &test.MockClient{
@@ -717,6 +800,42 @@ func TestResolveMultiple(t *testing.T) {
 				err: nil,
 			},
 		},
+		"Paginated": {
+			reason: "Managed resources should be selected from every page of results",
+			c: &test.MockClient{
+				MockList: func(_ context.Context, obj client.ObjectList, opts ...client.ListOption) error {
+					lo := &client.ListOptions{}
+					lo.ApplyOptions(opts)
+					if lo.Limit != resource.DefaultListPageSize {
+						return errors.Errorf("want limit %d, got %d", resource.DefaultListPageSize, lo.Limit)
+					}
+					l := obj.(*FakeManagedList)
+					mg := &fake.Managed{}
+					if lo.Continue == "" {
+						mg.SetName("first")
+						l.Items, l.Continue = []resource.Managed{mg}, "page-2"
+						return nil
+					}
+					mg.SetName("second")
+					l.Items, l.Continue = []resource.Managed{mg}, ""
+					return nil
+				},
+			},
+			from: &fake.Managed{},
+			args: args{
+				req: MultiResolutionRequest{
+					Selector: &xpv1.Selector{},
+					To:       To{List: &FakeManagedList{}},
+					Extract:  func(mg resource.Managed) string { return mg.GetName() },
+				},
+			},
+			want: want{
+				rsp: MultiResolutionResponse{
+					ResolvedValues:     []string{"first", "second"},
+					ResolvedReferences: []xpv1.Reference{{Name: "first"}, {Name: "second"}},
+				},
+			},
+		},
 		"BothReferenceSelector": {
 			reason: "When both Reference and Selector fields set and Policy is not set, the Reference must be resolved",
 			c: &test.MockClient{
@@ -789,3 +908,221 @@ func TestControllersMustMatch(t *testing.T) {
 		})
 	}
 }
+
+func TestLabelSelector(t *testing.T) {
+	invalid := metav1.LabelSelectorRequirement{Key: "env", Operator: "Bogus"}
+	_, errInvalid := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{invalid}})
+
+	type want struct {
+		selector string
+		err      error
+	}
+	cases := map[string]struct {
+		reason string
+		s      *xpv1.Selector
+		want   want
+	}{
+		"NilSelector": {
+			reason: "A nil selector should match everything.",
+			s:      nil,
+			want:   want{selector: ""},
+		},
+		"MatchLabels": {
+			reason: "A selector's labels should be matched.",
+			s:      &xpv1.Selector{MatchLabels: map[string]string{"cool": "true"}},
+			want:   want{selector: "cool=true"},
+		},
+		"MatchExpressions": {
+			reason: "A selector's labels and set-based requirements should be matched.",
+			s: &xpv1.Selector{
+				MatchLabels: map[string]string{"cool": "true"},
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "env", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"prod"}},
+					{Key: "team", Operator: metav1.LabelSelectorOpExists},
+				},
+			},
+			want: want{selector: "cool=true,env notin (prod),team"},
+		},
+		"InvalidExpression": {
+			reason: "An invalid set-based requirement should return an error.",
+			s:      &xpv1.Selector{MatchExpressions: []metav1.LabelSelectorRequirement{invalid}},
+			want:   want{err: errInvalid},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			sel, err := LabelSelector(tc.s)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nLabelSelector(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.selector, sel.String()); diff != "" {
+				t.Errorf("\n%s\nLabelSelector(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+type recordingManaged struct {
+	fake.Managed
+	Resolved []xpv1.ResolvedReference
+}
+
+func (m *recordingManaged) GetResolvedReferences() []xpv1.ResolvedReference {
+	return m.Resolved
+}
+
+func (m *recordingManaged) SetResolvedReferences(r []xpv1.ResolvedReference) {
+	m.Resolved = r
+}
+
+func TestResolveRecording(t *testing.T) {
+	now := time.Now()
+	earlier := metav1.NewTime(now.Add(-time.Hour))
+	always := xpv1.ResolvePolicyAlways
+
+	target := func(name, rv, externalName string) *fake.Managed {
+		mg := &fake.Managed{ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID(name + "-uid"), ResourceVersion: rv}}
+		meta.SetExternalName(mg, externalName)
+		return mg
+	}
+	get := func(to *fake.Managed) test.MockGetFn {
+		return func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			*obj.(*fake.Managed) = *to.DeepCopyObject().(*fake.Managed)
+			return nil
+		}
+	}
+	single := func(path string, ref *xpv1.Reference, sel *xpv1.Selector, items ...resource.Managed) func(context.Context, *APIResolver) ([]string, error) {
+		return func(ctx context.Context, r *APIResolver) ([]string, error) {
+			rsp, err := r.Resolve(ctx, ResolutionRequest{
+				Reference: ref,
+				Selector:  sel,
+				To:        To{Managed: &fake.Managed{}, List: &FakeManagedList{Items: items}},
+				Extract:   ExternalName(),
+				FieldPath: path,
+			})
+			return []string{rsp.ResolvedValue}, err
+		}
+	}
+
+	type args struct {
+		c       client.Client
+		from    *recordingManaged
+		resolve func(context.Context, *APIResolver) ([]string, error)
+	}
+	type want struct {
+		values   []string
+		err      error
+		resolved []xpv1.ResolvedReference
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"RecordSelected": {
+			reason: "We should record which resource satisfied a selector.",
+			args: args{
+				c:       &test.MockClient{MockList: test.NewMockListFn(nil)},
+				from:    &recordingManaged{},
+				resolve: single("spec.forProvider.id", nil, &xpv1.Selector{}, target("cool", "1", "cool-id")),
+			},
+			want: want{
+				values: []string{"cool-id"},
+				resolved: []xpv1.ResolvedReference{
+					{FieldPath: "spec.forProvider.id", Name: "cool", UID: "cool-uid", ResourceVersion: "1", Value: "cool-id", ResolvedAt: metav1.NewTime(now)},
+				},
+			},
+		},
+		"NoFieldPath": {
+			reason: "We should not record the outcome of a request with no field path.",
+			args: args{
+				c:       &test.MockClient{MockList: test.NewMockListFn(nil)},
+				from:    &recordingManaged{},
+				resolve: single("", nil, &xpv1.Selector{}, target("cool", "1", "cool-id")),
+			},
+			want: want{
+				values: []string{"cool-id"},
+			},
+		},
+		"TargetUnchanged": {
+			reason: "We should reuse the recorded value if the referenced resource didn't change.",
+			args: args{
+				c: &test.MockClient{MockGet: get(target("cool", "1", "new-id"))},
+				from: &recordingManaged{Resolved: []xpv1.ResolvedReference{
+					{FieldPath: "spec.forProvider.id", Name: "cool", UID: "cool-uid", ResourceVersion: "1", Value: "cool-id", ResolvedAt: earlier},
+				}},
+				resolve: single("spec.forProvider.id", &xpv1.Reference{Name: "cool", Policy: &xpv1.Policy{Resolve: &always}}, nil),
+			},
+			want: want{
+				values: []string{"cool-id"},
+				resolved: []xpv1.ResolvedReference{
+					{FieldPath: "spec.forProvider.id", Name: "cool", UID: "cool-uid", ResourceVersion: "1", Value: "cool-id", ResolvedAt: earlier},
+				},
+			},
+		},
+		"TargetChanged": {
+			reason: "We should resolve the value again if the referenced resource changed.",
+			args: args{
+				c: &test.MockClient{MockGet: get(target("cool", "2", "new-id"))},
+				from: &recordingManaged{Resolved: []xpv1.ResolvedReference{
+					{FieldPath: "spec.forProvider.other", Name: "other", Value: "other-id", ResolvedAt: earlier},
+					{FieldPath: "spec.forProvider.id", Name: "cool", UID: "cool-uid", ResourceVersion: "1", Value: "cool-id", ResolvedAt: earlier},
+				}},
+				resolve: single("spec.forProvider.id", &xpv1.Reference{Name: "cool", Policy: &xpv1.Policy{Resolve: &always}}, nil),
+			},
+			want: want{
+				values: []string{"new-id"},
+				resolved: []xpv1.ResolvedReference{
+					{FieldPath: "spec.forProvider.other", Name: "other", Value: "other-id", ResolvedAt: earlier},
+					{FieldPath: "spec.forProvider.id", Name: "cool", UID: "cool-uid", ResourceVersion: "2", Value: "new-id", ResolvedAt: metav1.NewTime(now)},
+				},
+			},
+		},
+		"RecordMultiple": {
+			reason: "We should record each resource that satisfied a multi-resolution selector, preserving unchanged outcomes.",
+			args: args{
+				c: &test.MockClient{MockList: test.NewMockListFn(nil)},
+				from: &recordingManaged{Resolved: []xpv1.ResolvedReference{
+					{FieldPath: "spec.forProvider.ids", Name: "a", UID: "a-uid", ResourceVersion: "1", Value: "a-id", ResolvedAt: earlier},
+				}},
+				resolve: func(ctx context.Context, r *APIResolver) ([]string, error) {
+					rsp, err := r.ResolveMultiple(ctx, MultiResolutionRequest{
+						Selector:  &xpv1.Selector{},
+						To:        To{Managed: &fake.Managed{}, List: &FakeManagedList{Items: []resource.Managed{target("a", "2", "a-id"), target("b", "1", "b-id")}}},
+						Extract:   ExternalName(),
+						FieldPath: "spec.forProvider.ids",
+					})
+					return rsp.ResolvedValues, err
+				},
+			},
+			want: want{
+				values: []string{"a-id", "b-id"},
+				resolved: []xpv1.ResolvedReference{
+					{FieldPath: "spec.forProvider.ids", Name: "a", UID: "a-uid", ResourceVersion: "2", Value: "a-id", ResolvedAt: earlier},
+					{FieldPath: "spec.forProvider.ids", Name: "b", UID: "b-uid", ResourceVersion: "1", Value: "b-id", ResolvedAt: metav1.NewTime(now)},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewAPIResolver(tc.args.c, tc.args.from)
+			r.now = func() time.Time { return now }
+			values, err := tc.args.resolve(context.Background(), r)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Resolve(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.values, values); diff != "" {
+				t.Errorf("\n%s\nr.Resolve(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.resolved, tc.args.from.GetResolvedReferences()); diff != "" {
+				t.Errorf("\n%s\nr.Resolve(...) resolved references: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -19,12 +19,18 @@ package resource
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"reflect"
 
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/jsonmergepatch"
+	"k8s.io/apimachinery/pkg/util/mergepatch"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
@@ -38,7 +44,12 @@ const (
 	errUpdateSecret         = "cannot update connection secret"
 	errCreateOrUpdateSecret = "cannot create or update connection secret"
 
-	errUpdateObject = "cannot update object"
+	errUpdateObject   = "cannot update object"
+	errPatchObject    = "cannot patch object"
+	errMarshalPatch   = "cannot marshal patch"
+	errMarshalDesired = "cannot marshal desired object"
+	errMarshalCurrent = "cannot marshal current object"
+	errThreeWayMerge  = "cannot compute three-way merge patch"
 )
 
 // An APIManagedConnectionPropagator propagates connection details by reading
@@ -59,15 +70,36 @@ func (a *APIManagedConnectionPropagator) PropagateConnection(ctx context.Context
 type APIConnectionPropagator struct {
 	client ClientApplicator
 	typer  runtime.ObjectTyper
+	filter ConnectionDetailsFilterFn
+}
+
+// A ConnectionDetailsFilterFn returns the subset of the supplied connection
+// details that should be propagated. It must not modify the supplied details.
+type ConnectionDetailsFilterFn func(data map[string][]byte) map[string][]byte
+
+// An APIConnectionPropagatorOption configures an APIConnectionPropagator.
+type APIConnectionPropagatorOption func(a *APIConnectionPropagator)
+
+// WithConnectionDetailsFilter configures the filter used to determine which
+// connection details are propagated. All connection details are propagated by
+// default.
+func WithConnectionDetailsFilter(fn ConnectionDetailsFilterFn) APIConnectionPropagatorOption {
+	return func(a *APIConnectionPropagator) {
+		a.filter = fn
+	}
 }
 
 // NewAPIConnectionPropagator returns a new APIConnectionPropagator.
 // Deprecated: This functionality will be removed soon.
-func NewAPIConnectionPropagator(c client.Client, t runtime.ObjectTyper) *APIConnectionPropagator {
-	return &APIConnectionPropagator{
+func NewAPIConnectionPropagator(c client.Client, t runtime.ObjectTyper, o ...APIConnectionPropagatorOption) *APIConnectionPropagator {
+	a := &APIConnectionPropagator{
 		client: ClientApplicator{Client: c, Applicator: NewAPIUpdatingApplicator(c)},
 		typer:  t,
 	}
+	for _, fn := range o {
+		fn(a)
+	}
+	return a
 }
 
 // PropagateConnection details from the supplied resource.
@@ -96,6 +128,9 @@ func (a *APIConnectionPropagator) PropagateConnection(ctx context.Context, to Lo
 
 	ts := LocalConnectionSecretFor(to, MustGetKind(to, a.typer))
 	ts.Data = fs.Data
+	if a.filter != nil {
+		ts.Data = a.filter(fs.Data)
+	}
 
 	meta.AllowPropagation(fs, ts)
 
@@ -106,27 +141,83 @@ func (a *APIConnectionPropagator) PropagateConnection(ctx context.Context, to Lo
 	return errors.Wrap(a.client.Update(ctx, fs), errUpdateSecret)
 }
 
+// A ResourceVersionPolicy determines whether an APIPatchingApplicator only
+// patches an object if its resource version has not changed.
+type ResourceVersionPolicy string
+
+// Resource version policies.
+const (
+	// ResourceVersionIfSet patches an object only if its resource version
+	// matches that of the desired object. Any object is patched if the
+	// desired object has no resource version.
+	ResourceVersionIfSet ResourceVersionPolicy = "IfSet"
+
+	// ResourceVersionIgnore patches an object regardless of its resource
+	// version.
+	ResourceVersionIgnore ResourceVersionPolicy = "Ignore"
+
+	// ResourceVersionRequire patches an object only if its resource version
+	// matches that of the desired object or, if the desired object has no
+	// resource version, only if it has not changed since it was read.
+	ResourceVersionRequire ResourceVersionPolicy = "Require"
+)
+
+const errFmtUnsupportedPatchType = "unsupported patch type %q: must be %q or %q"
+
+// An APIPatchingApplicatorOption configures an APIPatchingApplicator.
+type APIPatchingApplicatorOption func(a *APIPatchingApplicator)
+
+// WithPatchType configures the type of patch used to patch objects. A JSON
+// merge patch (the default) replaces lists wholesale. A strategic merge patch
+// merges lists according to their patch strategy, but is only supported by
+// built-in Kubernetes types.
+func WithPatchType(t types.PatchType) APIPatchingApplicatorOption {
+	return func(a *APIPatchingApplicator) {
+		a.patchType = t
+	}
+}
+
+// WithResourceVersionPolicy configures whether objects are only patched if
+// their resource version has not changed. The default is ResourceVersionIfSet.
+func WithResourceVersionPolicy(p ResourceVersionPolicy) APIPatchingApplicatorOption {
+	return func(a *APIPatchingApplicator) {
+		a.rvPolicy = p
+	}
+}
+
 // An APIPatchingApplicator applies changes to an object by either creating or
 // patching it in a Kubernetes API server.
 type APIPatchingApplicator struct {
-	client client.Client
+	client    client.Client
+	patchType types.PatchType
+	rvPolicy  ResourceVersionPolicy
 }
 
 // NewAPIPatchingApplicator returns an Applicator that applies changes to an
-// object by either creating or patching it in a Kubernetes API server.
-func NewAPIPatchingApplicator(c client.Client) *APIPatchingApplicator {
-	return &APIPatchingApplicator{client: c}
+// object by either creating or patching it in a Kubernetes API server. By
+// default objects are patched using a JSON merge patch.
+func NewAPIPatchingApplicator(c client.Client, o ...APIPatchingApplicatorOption) *APIPatchingApplicator {
+	a := &APIPatchingApplicator{client: c, patchType: types.MergePatchType, rvPolicy: ResourceVersionIfSet}
+	for _, fn := range o {
+		fn(a)
+	}
+	return a
 }
 
 // Apply changes to the supplied object. The object will be created if it does
-// not exist, or patched if it does. If the object does exist, it will only be
-// patched if the passed object has the same or an empty resource version.
+// not exist, or patched if it does. If the object does exist, whether it will
+// only be patched if the passed object has the same resource version depends
+// on the applicator's ResourceVersionPolicy.
 func (a *APIPatchingApplicator) Apply(ctx context.Context, o client.Object, ao ...ApplyOption) error {
 	m, ok := o.(metav1.Object)
 	if !ok {
 		return errors.New("cannot access object metadata")
 	}
 
+	if a.patchType != types.MergePatchType && a.patchType != types.StrategicMergePatchType {
+		return errors.Errorf(errFmtUnsupportedPatchType, a.patchType, types.MergePatchType, types.StrategicMergePatchType)
+	}
+
 	if m.GetName() == "" && m.GetGenerateName() != "" {
 		return errors.Wrap(a.client.Create(ctx, o), "cannot create object")
 	}
@@ -148,29 +239,72 @@ func (a *APIPatchingApplicator) Apply(ctx context.Context, o client.Object, ao .
 		}
 	}
 
-	// TODO(negz): Allow callers to override the kind of patch used.
-	return errors.Wrap(a.client.Patch(ctx, o, &patch{desired}), "cannot patch object")
+	if dm, ok := desired.(metav1.Object); ok {
+		switch a.rvPolicy {
+		case ResourceVersionIgnore:
+			dm.SetResourceVersion("")
+		case ResourceVersionRequire:
+			if dm.GetResourceVersion() == "" {
+				dm.SetResourceVersion(o.GetResourceVersion())
+			}
+		case ResourceVersionIfSet:
+		}
+	}
+
+	return errors.Wrap(a.client.Patch(ctx, o, &patch{from: desired, typ: a.patchType}), "cannot patch object")
 }
 
-type patch struct{ from runtime.Object }
+type patch struct {
+	from runtime.Object
+	typ  types.PatchType
+}
 
-func (p *patch) Type() types.PatchType                { return types.MergePatchType }
+func (p *patch) Type() types.PatchType {
+	if p.typ == "" {
+		return types.MergePatchType
+	}
+	return p.typ
+}
 func (p *patch) Data(_ client.Object) ([]byte, error) { return json.Marshal(p.from) }
 
+// DefaultConflictBackoff is the default backoff used to retry writes that
+// conflict with a concurrent write to the same object.
+var DefaultConflictBackoff = retry.DefaultRetry
+
+// An APIUpdatingApplicatorOption configures an APIUpdatingApplicator.
+type APIUpdatingApplicatorOption func(a *APIUpdatingApplicator)
+
+// WithConflictBackoff configures the backoff used to retry updates that
+// conflict with a concurrent write to the object. Updates are retried
+// DefaultConflictBackoff.Steps times by default. Pass a backoff with one step
+// to disable retries.
+func WithConflictBackoff(b wait.Backoff) APIUpdatingApplicatorOption {
+	return func(a *APIUpdatingApplicator) {
+		a.backoff = b
+	}
+}
+
 // An APIUpdatingApplicator applies changes to an object by either creating or
 // updating it in a Kubernetes API server.
 type APIUpdatingApplicator struct {
-	client client.Client
+	client  client.Client
+	backoff wait.Backoff
 }
 
 // NewAPIUpdatingApplicator returns an Applicator that applies changes to an
 // object by either creating or updating it in a Kubernetes API server.
-func NewAPIUpdatingApplicator(c client.Client) *APIUpdatingApplicator {
-	return &APIUpdatingApplicator{client: c}
+func NewAPIUpdatingApplicator(c client.Client, o ...APIUpdatingApplicatorOption) *APIUpdatingApplicator {
+	a := &APIUpdatingApplicator{client: c, backoff: DefaultConflictBackoff}
+	for _, fn := range o {
+		fn(a)
+	}
+	return a
 }
 
 // Apply changes to the supplied object. The object will be created if it does
-// not exist, or updated if it does.
+// not exist, or updated if it does. If the update conflicts with a concurrent
+// write the object is read again, the ApplyOptions are run again, and the
+// update is retried.
 func (a *APIUpdatingApplicator) Apply(ctx context.Context, o client.Object, ao ...ApplyOption) error {
 	m, ok := o.(Object)
 	if !ok {
@@ -181,27 +315,175 @@ func (a *APIUpdatingApplicator) Apply(ctx context.Context, o client.Object, ao .
 		return errors.Wrap(a.client.Create(ctx, o), "cannot create object")
 	}
 
-	current := o.DeepCopyObject().(client.Object)
+	desired := o.DeepCopyObject().(client.Object)
+	attempt := o
+	err := retry.RetryOnConflict(a.backoff, func() error {
+		attempt = desired.DeepCopyObject().(client.Object)
+		current := desired.DeepCopyObject().(client.Object)
+
+		err := a.client.Get(ctx, types.NamespacedName{Name: m.GetName(), Namespace: m.GetNamespace()}, current)
+		if kerrors.IsNotFound(err) {
+			// TODO(negz): Apply ApplyOptions here too?
+			return errors.Wrap(a.client.Create(ctx, attempt), "cannot create object")
+		}
+		if err != nil {
+			return errors.Wrap(err, "cannot get object")
+		}
+
+		for _, fn := range ao {
+			if err := fn(ctx, current, attempt); err != nil {
+				return err
+			}
+		}
+
+		// NOTE(hasheddan): we must set the resource version of the desired object
+		// to that of the current or the update will always fail.
+		attempt.SetResourceVersion(current.GetResourceVersion())
+		return errors.Wrap(a.client.Update(ctx, attempt), "cannot update object")
+	})
+	setObject(o, attempt)
+	return err
+}
+
+// setObject sets the supplied object to the value of the supplied source
+// object, which must be of the same type.
+func setObject(o, from client.Object) {
+	if o == from {
+		return
+	}
+	reflect.ValueOf(o).Elem().Set(reflect.ValueOf(from).Elem())
+}
+
+// UpdateStatus updates the status of the supplied object. If the update
+// conflicts with a concurrent write to the object its latest version is read
+// using the supplied reader, the supplied object's status is applied to it,
+// and the update is retried using the supplied backoff. The supplied object is
+// updated to reflect the latest version of the object.
+//
+// The reader should not be backed by a cache, e.g. it should be a manager's
+// API reader. A cache may not yet have observed the write we conflicted with,
+// in which case every retry would conflict again.
+func UpdateStatus(ctx context.Context, c client.Client, r client.Reader, o client.Object, b wait.Backoff) error {
+	first := true
+	return retry.RetryOnConflict(b, func() error {
+		if !first {
+			if err := refreshStatus(ctx, r, o); err != nil {
+				return err
+			}
+		}
+		first = false
+		return c.Status().Update(ctx, o)
+	})
+}
+
+// refreshStatus sets the supplied object to the latest version read from the
+// API server, but with its own status.
+func refreshStatus(ctx context.Context, r client.Reader, o client.Object) error {
+	desired, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+	if err != nil {
+		return errors.Wrap(err, errConvertDesired)
+	}
+
+	latest := o.DeepCopyObject().(client.Object)
+	if err := r.Get(ctx, types.NamespacedName{Namespace: o.GetNamespace(), Name: o.GetName()}, latest); err != nil {
+		return errors.Wrap(err, "cannot get object")
+	}
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(latest)
+	if err != nil {
+		return errors.Wrap(err, errConvertCurrent)
+	}
+
+	u["status"] = desired["status"]
+	return errors.Wrap(runtime.DefaultUnstructuredConverter.FromUnstructured(u, o), errConvertCurrent)
+}
+
+// An APIThreeWayApplicator applies changes to an object by either creating it
+// or patching it in a Kubernetes API server. Unlike an APIPatchingApplicator
+// it records the desired state it applied to an object, and computes a
+// three-way JSON merge patch between that state, the new desired state, and
+// the object's current state. Fields that are removed from the desired state
+// are thus removed from the object, while fields set by other actors are left
+// untouched.
+type APIThreeWayApplicator struct {
+	client client.Client
+}
 
-	err := a.client.Get(ctx, types.NamespacedName{Name: m.GetName(), Namespace: m.GetNamespace()}, current)
+// NewAPIThreeWayApplicator returns an Applicator that applies changes to an
+// object by computing a three-way merge between the desired state most
+// recently applied to the object, the new desired state, and the object's
+// current state.
+func NewAPIThreeWayApplicator(c client.Client) *APIThreeWayApplicator {
+	return &APIThreeWayApplicator{client: c}
+}
+
+// Apply changes to the supplied object. The object will be created if it does
+// not exist, or patched if it does. The supplied desired state is recorded in
+// the object's last-applied-configuration annotation.
+func (a *APIThreeWayApplicator) Apply(ctx context.Context, o client.Object, ao ...ApplyOption) error {
+	m, ok := o.(metav1.Object)
+	if !ok {
+		return errors.New("cannot access object metadata")
+	}
+
+	applied, err := lastApplied(o)
+	if err != nil {
+		return err
+	}
+	meta.AddAnnotations(m, map[string]string{meta.AnnotationKeyLastAppliedConfiguration: string(applied)})
+
+	if m.GetName() == "" && m.GetGenerateName() != "" {
+		return errors.Wrap(a.client.Create(ctx, o), "cannot create object")
+	}
+
+	desired := o.DeepCopyObject()
+	modified, err := json.Marshal(desired)
+	if err != nil {
+		return errors.Wrap(err, errMarshalDesired)
+	}
+
+	err = a.client.Get(ctx, types.NamespacedName{Name: m.GetName(), Namespace: m.GetNamespace()}, o)
 	if kerrors.IsNotFound(err) {
 		// TODO(negz): Apply ApplyOptions here too?
-		return errors.Wrap(a.client.Create(ctx, m), "cannot create object")
+		return errors.Wrap(a.client.Create(ctx, o), "cannot create object")
 	}
 	if err != nil {
 		return errors.Wrap(err, "cannot get object")
 	}
 
 	for _, fn := range ao {
-		if err := fn(ctx, current, m); err != nil {
+		if err := fn(ctx, o, desired); err != nil {
 			return err
 		}
 	}
 
-	// NOTE(hasheddan): we must set the resource version of the desired object
-	// to that of the current or the update will always fail.
-	m.SetResourceVersion(current.(metav1.Object).GetResourceVersion())
-	return errors.Wrap(a.client.Update(ctx, m), "cannot update object")
+	current, err := json.Marshal(o)
+	if err != nil {
+		return errors.Wrap(err, errMarshalCurrent)
+	}
+
+	original := []byte(o.GetAnnotations()[meta.AnnotationKeyLastAppliedConfiguration])
+	p, err := jsonmergepatch.CreateThreeWayJSONMergePatch(original, modified, current,
+		mergepatch.RequireKeyUnchanged("apiVersion"),
+		mergepatch.RequireKeyUnchanged("kind"),
+		mergepatch.RequireMetadataKeyUnchanged("name"),
+		mergepatch.RequireMetadataKeyUnchanged("namespace"))
+	if err != nil {
+		return errors.Wrap(err, errThreeWayMerge)
+	}
+
+	return errors.Wrap(a.client.Patch(ctx, o, client.RawPatch(types.MergePatchType, p)), "cannot patch object")
+}
+
+// lastApplied returns the JSON encoded desired state of the supplied object,
+// omitting its last-applied-configuration annotation.
+func lastApplied(o client.Object) ([]byte, error) {
+	d := o.DeepCopyObject().(client.Object)
+	meta.RemoveAnnotations(d, meta.AnnotationKeyLastAppliedConfiguration)
+	if len(d.GetAnnotations()) == 0 {
+		d.SetAnnotations(nil)
+	}
+	j, err := json.Marshal(d)
+	return j, errors.Wrap(err, errMarshalDesired)
 }
 
 // An APIFinalizer adds and removes finalizers to and from a resource.
@@ -245,6 +527,81 @@ func (a *APIFinalizer) RemoveFinalizer(ctx context.Context, obj Object) error {
 	return errors.Wrap(IgnoreNotFound(a.client.Update(ctx, obj)), errUpdateObject)
 }
 
+// An APIPatchingFinalizer adds and removes finalizers to and from a resource
+// by JSON patching its metadata.finalizers array. Unlike an APIFinalizer it
+// doesn't update the whole resource, so it doesn't conflict with controllers
+// that concurrently change other parts of the resource, including other
+// finalizers. Each patch tests the part of the array it changes, so it fails
+// rather than clobbering a concurrent change to that part.
+type APIPatchingFinalizer struct {
+	client    client.Client
+	finalizer string
+}
+
+// NewAPIPatchingFinalizer returns a new APIPatchingFinalizer.
+func NewAPIPatchingFinalizer(c client.Client, finalizer string) *APIPatchingFinalizer {
+	return &APIPatchingFinalizer{client: c, finalizer: finalizer}
+}
+
+type jsonPatchOp struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value any    `json:"value"`
+}
+
+// AddFinalizer to the supplied resource.
+func (a *APIPatchingFinalizer) AddFinalizer(ctx context.Context, obj Object) error {
+	if meta.FinalizerExists(obj, a.finalizer) {
+		return nil
+	}
+
+	// Appending to an existing array can't clobber a concurrent change. If
+	// there's no array we must create one, but only if nobody else has.
+	ops := []jsonPatchOp{{Op: "add", Path: "/metadata/finalizers/-", Value: a.finalizer}}
+	if len(obj.GetFinalizers()) == 0 {
+		ops = []jsonPatchOp{
+			{Op: "test", Path: "/metadata/finalizers", Value: nil},
+			{Op: "add", Path: "/metadata/finalizers", Value: []string{a.finalizer}},
+		}
+	}
+
+	meta.AddFinalizer(obj, a.finalizer)
+	return errors.Wrap(a.patch(ctx, obj, ops), errPatchObject)
+}
+
+// RemoveFinalizer from the supplied resource.
+func (a *APIPatchingFinalizer) RemoveFinalizer(ctx context.Context, obj Object) error {
+	i := -1
+	for j, f := range obj.GetFinalizers() {
+		if f == a.finalizer {
+			i = j
+			break
+		}
+	}
+	if i < 0 {
+		return nil
+	}
+
+	// Test that our finalizer is still at the index we're removing, in case
+	// another finalizer was concurrently removed.
+	path := fmt.Sprintf("/metadata/finalizers/%d", i)
+	ops := []jsonPatchOp{
+		{Op: "test", Path: path, Value: a.finalizer},
+		{Op: "remove", Path: path},
+	}
+
+	meta.RemoveFinalizer(obj, a.finalizer)
+	return errors.Wrap(IgnoreNotFound(a.patch(ctx, obj, ops)), errPatchObject)
+}
+
+func (a *APIPatchingFinalizer) patch(ctx context.Context, obj Object, ops []jsonPatchOp) error {
+	j, err := json.Marshal(ops)
+	if err != nil {
+		return errors.Wrap(err, errMarshalPatch)
+	}
+	return a.client.Patch(ctx, obj, client.RawPatch(types.JSONPatchType, j))
+}
+
 // A FinalizerFns satisfy the Finalizer interface.
 type FinalizerFns struct {
 	AddFinalizerFn    func(ctx context.Context, obj Object) error
@@ -18,6 +18,7 @@ package resource
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -26,6 +27,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
@@ -66,6 +68,7 @@ func TestPropagateConnection(t *testing.T) {
 	type fields struct {
 		client ClientApplicator
 		typer  runtime.ObjectTyper
+		filter ConnectionDetailsFilterFn
 	}
 
 	type args struct {
@@ -215,11 +218,42 @@ func TestPropagateConnection(t *testing.T) {
 				mg: mg,
 			},
 		},
+		"SuccessfulFiltered": {
+			reason: "Only connection details accepted by the filter should be propagated to the claim secret",
+			fields: fields{
+				client: ClientApplicator{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							s := ConnectionSecretFor(mg, fake.GVK(mg))
+							s.Data = map[string][]byte{"cool": {1}, "secret": {2}}
+							*o.(*corev1.Secret) = *s
+							return nil
+						}),
+						MockUpdate: test.NewMockUpdateFn(nil),
+					},
+					Applicator: ApplyFn(func(_ context.Context, o client.Object, _ ...ApplyOption) error {
+						want := map[string][]byte{"cool": {1}}
+						if diff := cmp.Diff(want, o.(*corev1.Secret).Data); diff != "" {
+							t.Errorf("-want, +got: %s", diff)
+						}
+						return nil
+					}),
+				},
+				typer: fake.SchemeWith(mg, cm),
+				filter: func(data map[string][]byte) map[string][]byte {
+					return map[string][]byte{"cool": data["cool"]}
+				},
+			},
+			args: args{
+				o:  cm,
+				mg: mg,
+			},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			api := &APIConnectionPropagator{client: tc.fields.client, typer: tc.fields.typer}
+			api := &APIConnectionPropagator{client: tc.fields.client, typer: tc.fields.typer, filter: tc.fields.filter}
 			err := api.PropagateConnection(tc.args.ctx, tc.args.o, tc.args.mg)
 			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\napi.PropagateConnection(...): -want error, +got error:\n%s", tc.reason, diff)
@@ -349,6 +383,354 @@ func TestAPIPatchingApplicator(t *testing.T) {
 	}
 }
 
+func TestAPIPatchingApplicatorOptions(t *testing.T) {
+	current := &object{}
+	current.SetName("cool")
+	current.SetResourceVersion("2")
+
+	type args struct {
+		o  client.Object
+		ao []APIPatchingApplicatorOption
+	}
+
+	type want struct {
+		patchType       types.PatchType
+		resourceVersion string
+		err             error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"UnsupportedPatchType": {
+			reason: "An error should be returned if the applicator is configured with an unsupported patch type.",
+			args: args{
+				o:  &object{ObjectMeta: metav1.ObjectMeta{Name: "cool"}},
+				ao: []APIPatchingApplicatorOption{WithPatchType(types.JSONPatchType)},
+			},
+			want: want{
+				err: errors.Errorf(errFmtUnsupportedPatchType, types.JSONPatchType, types.MergePatchType, types.StrategicMergePatchType),
+			},
+		},
+		"DefaultsToMergePatchIfSet": {
+			reason: "By default a JSON merge patch should be sent, including the desired object's resource version.",
+			args: args{
+				o: &object{ObjectMeta: metav1.ObjectMeta{Name: "cool", ResourceVersion: "1"}},
+			},
+			want: want{
+				patchType:       types.MergePatchType,
+				resourceVersion: "1",
+			},
+		},
+		"StrategicMergePatch": {
+			reason: "A strategic merge patch should be sent if configured.",
+			args: args{
+				o:  &object{ObjectMeta: metav1.ObjectMeta{Name: "cool"}},
+				ao: []APIPatchingApplicatorOption{WithPatchType(types.StrategicMergePatchType)},
+			},
+			want: want{
+				patchType: types.StrategicMergePatchType,
+			},
+		},
+		"IgnoreResourceVersion": {
+			reason: "The desired object's resource version should be omitted from the patch if it is to be ignored.",
+			args: args{
+				o:  &object{ObjectMeta: metav1.ObjectMeta{Name: "cool", ResourceVersion: "1"}},
+				ao: []APIPatchingApplicatorOption{WithResourceVersionPolicy(ResourceVersionIgnore)},
+			},
+			want: want{
+				patchType: types.MergePatchType,
+			},
+		},
+		"RequireResourceVersionFromDesired": {
+			reason: "The desired object's resource version should be sent if it is required and set.",
+			args: args{
+				o:  &object{ObjectMeta: metav1.ObjectMeta{Name: "cool", ResourceVersion: "1"}},
+				ao: []APIPatchingApplicatorOption{WithResourceVersionPolicy(ResourceVersionRequire)},
+			},
+			want: want{
+				patchType:       types.MergePatchType,
+				resourceVersion: "1",
+			},
+		},
+		"RequireResourceVersionFromCurrent": {
+			reason: "The current object's resource version should be sent if it is required and the desired object has none.",
+			args: args{
+				o:  &object{ObjectMeta: metav1.ObjectMeta{Name: "cool"}},
+				ao: []APIPatchingApplicatorOption{WithResourceVersionPolicy(ResourceVersionRequire)},
+			},
+			want: want{
+				patchType:       types.MergePatchType,
+				resourceVersion: "2",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := want{}
+			c := &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+					*o.(*object) = *current
+					return nil
+				}),
+				MockPatch: func(_ context.Context, obj client.Object, p client.Patch, _ ...client.PatchOption) error {
+					got.patchType = p.Type()
+					data, err := p.Data(obj)
+					if err != nil {
+						return err
+					}
+					m := metav1.ObjectMeta{}
+					if err := json.Unmarshal(data, &m); err != nil {
+						return err
+					}
+					got.resourceVersion = m.GetResourceVersion()
+					return nil
+				},
+			}
+
+			a := NewAPIPatchingApplicator(c, tc.args.ao...)
+			got.err = a.Apply(context.Background(), tc.args.o)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors(), cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nApply(...): -want, +got\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUpdateStatus(t *testing.T) {
+	errBoom := errors.New("boom")
+	errConflict := kerrors.NewConflict(schema.GroupResource{}, "", errBoom)
+
+	latest := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "cool", ResourceVersion: "2", Labels: map[string]string{"new": "true"}},
+		Status:     corev1.PodStatus{Phase: corev1.PodPending},
+	}
+
+	type args struct {
+		c client.Client
+		r client.Reader
+		o client.Object
+	}
+
+	type want struct {
+		o   client.Object
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"UpdateError": {
+			reason: "Errors other than conflicts should be returned without retrying.",
+			args: args{
+				c: &test.MockClient{MockStatusUpdate: test.NewMockSubResourceUpdateFn(errBoom)},
+				o: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cool"}},
+			},
+			want: want{
+				o:   &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cool"}},
+				err: errBoom,
+			},
+		},
+		"GetError": {
+			reason: "An error should be returned if we can't get the latest object after a conflict.",
+			args: args{
+				c: &test.MockClient{
+					MockStatusUpdate: test.NewMockSubResourceUpdateFn(errConflict),
+				},
+				r: &test.MockClient{
+					MockGet: test.NewMockGetFn(errBoom),
+				},
+				o: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cool"}},
+			},
+			want: want{
+				o:   &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cool"}},
+				err: errors.Wrap(errBoom, "cannot get object"),
+			},
+		},
+		"ConflictRetried": {
+			reason: "After a conflict our status should be applied to the latest object read by the reader, not the (stale) client, and the update retried.",
+			args: args{
+				c: &test.MockClient{
+					// The client's cache hasn't yet observed the write we
+					// conflicted with.
+					MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+						o.SetResourceVersion("1")
+						return nil
+					}),
+					MockStatusUpdate: func(_ context.Context, o client.Object, _ ...client.SubResourceUpdateOption) error {
+						if o.GetResourceVersion() != latest.GetResourceVersion() {
+							return errConflict
+						}
+						return nil
+					},
+				},
+				r: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+						latest.DeepCopyInto(o.(*corev1.Pod))
+						return nil
+					}),
+				},
+				o: &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: "cool", ResourceVersion: "1"},
+					Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+				},
+			},
+			want: want{
+				o: &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: "cool", ResourceVersion: "2", Labels: map[string]string{"new": "true"}},
+					Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := UpdateStatus(context.Background(), tc.args.c, tc.args.r, tc.args.o, DefaultConflictBackoff)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nUpdateStatus(...): -want error, +got error\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, tc.args.o); diff != "" {
+				t.Errorf("\n%s\nUpdateStatus(...): -want, +got\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestAPIThreeWayApplicator(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	// withApplied returns an object with the supplied labels, annotated as if
+	// the supplied labels had been applied.
+	withApplied := func(labels, applied map[string]string) *object {
+		a := &object{ObjectMeta: metav1.ObjectMeta{Name: "cool", Labels: applied}}
+		j, _ := lastApplied(a)
+		o := &object{ObjectMeta: metav1.ObjectMeta{Name: "cool", Labels: labels}}
+		meta.AddAnnotations(o, map[string]string{meta.AnnotationKeyLastAppliedConfiguration: string(j)})
+		return o
+	}
+
+	type args struct {
+		c  client.Client
+		o  client.Object
+		ao []ApplyOption
+	}
+
+	type want struct {
+		patch map[string]any
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"GetError": {
+			reason: "An error should be returned if we can't get the object.",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				o: &object{ObjectMeta: metav1.ObjectMeta{Name: "cool"}},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, "cannot get object"),
+			},
+		},
+		"CreateError": {
+			reason: "An error should be returned if we can't create the object.",
+			args: args{
+				c: &test.MockClient{
+					MockGet:    test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+					MockCreate: test.NewMockCreateFn(errBoom),
+				},
+				o: &object{ObjectMeta: metav1.ObjectMeta{Name: "cool"}},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, "cannot create object"),
+			},
+		},
+		"ApplyOptionError": {
+			reason: "Any errors from an apply option should be returned.",
+			args: args{
+				c:  &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+				o:  &object{ObjectMeta: metav1.ObjectMeta{Name: "cool"}},
+				ao: []ApplyOption{func(_ context.Context, _, _ runtime.Object) error { return errBoom }},
+			},
+			want: want{
+				err: errBoom,
+			},
+		},
+		"PatchError": {
+			reason: "An error should be returned if we can't patch the object.",
+			args: args{
+				c: &test.MockClient{
+					MockGet:   test.NewMockGetFn(nil),
+					MockPatch: test.NewMockPatchFn(errBoom),
+				},
+				o: &object{ObjectMeta: metav1.ObjectMeta{Name: "cool"}},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, "cannot patch object"),
+			},
+		},
+		"RemoveDeletedFields": {
+			reason: "Fields that were previously applied but are no longer desired should be removed, while fields set by others should be retained.",
+			args: args{
+				c: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+						*o.(*object) = *withApplied(
+							map[string]string{"applied": "true", "removed": "true", "other": "true"},
+							map[string]string{"applied": "true", "removed": "true"},
+						)
+						return nil
+					}),
+				},
+				o: &object{ObjectMeta: metav1.ObjectMeta{Name: "cool", Labels: map[string]string{"applied": "true", "added": "true"}}},
+			},
+			want: want{
+				// The test object's metadata isn't nested under a
+				// metadata key when marshalled to JSON.
+				patch: map[string]any{
+					"labels": map[string]any{"added": "true", "removed": nil},
+					"annotations": map[string]any{
+						meta.AnnotationKeyLastAppliedConfiguration: `{"Object":null,"name":"cool","creationTimestamp":null,"labels":{"added":"true","applied":"true"}}`,
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := want{}
+			c := tc.args.c
+			if mc, ok := c.(*test.MockClient); ok && mc.MockPatch == nil {
+				mc.MockPatch = func(_ context.Context, obj client.Object, p client.Patch, _ ...client.PatchOption) error {
+					data, err := p.Data(obj)
+					if err != nil {
+						return err
+					}
+					return json.Unmarshal(data, &got.patch)
+				}
+			}
+
+			a := NewAPIThreeWayApplicator(c)
+			got.err = a.Apply(context.Background(), tc.args.o, tc.args.ao...)
+			if diff := cmp.Diff(tc.want.err, got.err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nApply(...): -want error, +got error\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.patch, got.patch); diff != "" {
+				t.Errorf("\n%s\nApply(...): -want patch, +got patch\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestAPIUpdatingApplicator(t *testing.T) {
 	errBoom := errors.New("boom")
 	desired := &object{}
@@ -440,6 +822,28 @@ func TestAPIUpdatingApplicator(t *testing.T) {
 				o: desired,
 			},
 		},
+		"UpdateConflictRetried": {
+			reason: "An update that conflicts with a concurrent write should be retried.",
+			c: func() client.Client {
+				calls := 0
+				return &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+					MockUpdate: func(_ context.Context, _ client.Object, _ ...client.UpdateOption) error {
+						calls++
+						if calls == 1 {
+							return kerrors.NewConflict(schema.GroupResource{}, "", errBoom)
+						}
+						return nil
+					},
+				}
+			}(),
+			args: args{
+				o: desired,
+			},
+			want: want{
+				o: desired,
+			},
+		},
 		"Updated": {
 			reason: "No error should be returned if we successfully update an existing object. If no ApplyOption is passed the existing should not be modified",
 			c: &test.MockClient{
@@ -592,3 +996,135 @@ func TestAPIFinalizerAdder(t *testing.T) {
 		})
 	}
 }
+
+var _ Finalizer = &APIPatchingFinalizer{}
+
+func TestAPIPatchingFinalizer(t *testing.T) {
+	finalizer := "veryfinal"
+	errBoom := errors.New("boom")
+
+	// patch returns a MockPatchFn that records the patch it is called with.
+	patch := func(got *string, err error) test.MockPatchFn {
+		return func(_ context.Context, obj client.Object, p client.Patch, _ ...client.PatchOption) error {
+			d, _ := p.Data(obj)
+			*got = string(p.Type()) + " " + string(d)
+			return err
+		}
+	}
+
+	type args struct {
+		add bool
+		obj Object
+		err error
+	}
+	type want struct {
+		err   error
+		obj   Object
+		patch string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"AddExists": {
+			reason: "We should not patch if the finalizer already exists.",
+			args: args{
+				add: true,
+				obj: &fake.Object{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{finalizer}}},
+			},
+			want: want{
+				obj: &fake.Object{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{finalizer}}},
+			},
+		},
+		"AddFirst": {
+			reason: "We should create the finalizers array only if it does not exist.",
+			args: args{
+				add: true,
+				obj: &fake.Object{},
+			},
+			want: want{
+				obj:   &fake.Object{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{finalizer}}},
+				patch: `application/json-patch+json [{"op":"test","path":"/metadata/finalizers","value":null},{"op":"add","path":"/metadata/finalizers","value":["veryfinal"]}]`,
+			},
+		},
+		"AddAppend": {
+			reason: "We should append to an existing finalizers array.",
+			args: args{
+				add: true,
+				obj: &fake.Object{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{"other"}}},
+			},
+			want: want{
+				obj:   &fake.Object{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{"other", finalizer}}},
+				patch: `application/json-patch+json [{"op":"add","path":"/metadata/finalizers/-","value":"veryfinal"}]`,
+			},
+		},
+		"AddPatchError": {
+			reason: "Errors patching the object should be returned.",
+			args: args{
+				add: true,
+				obj: &fake.Object{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{"other"}}},
+				err: errBoom,
+			},
+			want: want{
+				err:   errors.Wrap(errBoom, errPatchObject),
+				obj:   &fake.Object{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{"other", finalizer}}},
+				patch: `application/json-patch+json [{"op":"add","path":"/metadata/finalizers/-","value":"veryfinal"}]`,
+			},
+		},
+		"RemoveNotExists": {
+			reason: "We should not patch if the finalizer does not exist.",
+			args: args{
+				obj: &fake.Object{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{"other"}}},
+			},
+			want: want{
+				obj: &fake.Object{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{"other"}}},
+			},
+		},
+		"Remove": {
+			reason: "We should remove the finalizer only if it is still at the index we read.",
+			args: args{
+				obj: &fake.Object{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{"other", finalizer}}},
+			},
+			want: want{
+				obj:   &fake.Object{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{"other"}}},
+				patch: `application/json-patch+json [{"op":"test","path":"/metadata/finalizers/1","value":"veryfinal"},{"op":"remove","path":"/metadata/finalizers/1","value":null}]`,
+			},
+		},
+		"RemoveNotFound": {
+			reason: "We should not return an error if the object no longer exists.",
+			args: args{
+				obj: &fake.Object{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{finalizer}}},
+				err: kerrors.NewNotFound(schema.GroupResource{}, ""),
+			},
+			want: want{
+				obj:   &fake.Object{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{}}},
+				patch: `application/json-patch+json [{"op":"test","path":"/metadata/finalizers/0","value":"veryfinal"},{"op":"remove","path":"/metadata/finalizers/0","value":null}]`,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ""
+			api := NewAPIPatchingFinalizer(&test.MockClient{MockPatch: patch(&got, tc.args.err)}, finalizer)
+
+			var err error
+			if tc.args.add {
+				err = api.AddFinalizer(context.Background(), tc.args.obj)
+			} else {
+				err = api.RemoveFinalizer(context.Background(), tc.args.obj)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\napi.Finalizer(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.obj, tc.args.obj); diff != "" {
+				t.Errorf("\n%s\napi.Finalizer(...) Object: -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.patch, got); diff != "" {
+				t.Errorf("\n%s\napi.Finalizer(...) Patch: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
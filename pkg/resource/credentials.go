@@ -0,0 +1,227 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// DefaultCredentialCacheTTL is the default time for which a CredentialCache
+// caches extracted credentials.
+const DefaultCredentialCacheTTL = 5 * time.Minute
+
+// credentialKey identifies the credentials extracted from a source using a set
+// of selectors.
+type credentialKey struct {
+	source xpv1.CredentialsSource
+	env    string
+	path   string
+	secret types.NamespacedName
+	key    string
+}
+
+func newCredentialKey(source xpv1.CredentialsSource, s xpv1.CommonCredentialSelectors) credentialKey {
+	k := credentialKey{source: source}
+	switch source { //nolint:exhaustive // Only these sources use selectors.
+	case xpv1.CredentialsSourceEnvironment:
+		if s.Env != nil {
+			k.env = s.Env.Name
+		}
+	case xpv1.CredentialsSourceFilesystem:
+		if s.Fs != nil {
+			k.path = s.Fs.Path
+		}
+	case xpv1.CredentialsSourceSecret:
+		if s.SecretRef != nil {
+			k.secret = types.NamespacedName{Namespace: s.SecretRef.Namespace, Name: s.SecretRef.Name}
+			k.key = s.SecretRef.Key
+		}
+	}
+	return k
+}
+
+type credentialEntry struct {
+	creds   []byte
+	expires time.Time
+}
+
+// A CredentialCacheOption configures a CredentialCache.
+type CredentialCacheOption func(c *CredentialCache)
+
+// WithCredentialCacheTTL configures how long credentials are cached for.
+func WithCredentialCacheTTL(ttl time.Duration) CredentialCacheOption {
+	return func(c *CredentialCache) {
+		c.ttl = ttl
+	}
+}
+
+// WithCredentialExtractor configures the CredentialExtractor used to extract
+// credentials that are not cached.
+func WithCredentialExtractor(e CredentialExtractor) CredentialCacheOption {
+	return func(c *CredentialCache) {
+		c.extract = e
+	}
+}
+
+// A credentialGeneration is incremented each time cached credentials are
+// invalidated. Credentials extracted while they were invalidated may be stale,
+// so they're only cached if the generation didn't change during extraction.
+type credentialGeneration struct {
+	all    uint64
+	secret uint64
+}
+
+// A CredentialCache caches extracted credentials, so that managed resources
+// that share a ProviderConfig don't each read its credentials every reconcile.
+// Credentials are cached by source and selectors until a TTL expires, or until
+// they are invalidated - for example because the Secret they were extracted
+// from changed.
+type CredentialCache struct {
+	extract CredentialExtractor
+	ttl     time.Duration
+	now     func() time.Time
+
+	mu      sync.Mutex
+	entries map[credentialKey]credentialEntry
+
+	// generation and secretGenerations are incremented when all credentials,
+	// or the credentials extracted from a Secret, are invalidated.
+	generation        uint64
+	secretGenerations map[types.NamespacedName]uint64
+}
+
+// NewCredentialCache returns a CredentialCache that caches credentials
+// extracted by CommonCredentialExtractor for DefaultCredentialCacheTTL.
+func NewCredentialCache(o ...CredentialCacheOption) *CredentialCache {
+	c := &CredentialCache{
+		extract: CommonCredentialExtractor,
+		ttl:     DefaultCredentialCacheTTL,
+		now:     time.Now,
+		entries: make(map[credentialKey]credentialEntry),
+
+		secretGenerations: make(map[types.NamespacedName]uint64),
+	}
+	for _, fn := range o {
+		fn(c)
+	}
+	return c
+}
+
+// Extract credentials from the supplied source, returning cached credentials
+// if possible. Its signature matches CommonCredentialExtractor, so
+// cache.Extract may be used in its place. Errors are not cached, nor are
+// credentials that were invalidated while they were being extracted. The
+// returned credentials are a copy, and may be modified by the caller.
+func (c *CredentialCache) Extract(ctx context.Context, source xpv1.CredentialsSource, kube client.Client, s xpv1.CommonCredentialSelectors) ([]byte, error) {
+	k := newCredentialKey(source, s)
+
+	c.mu.Lock()
+	e, ok := c.entries[k]
+	g := c.generationOf(k)
+	c.mu.Unlock()
+	if ok && c.now().Before(e.expires) {
+		return copyBytes(e.creds), nil
+	}
+
+	creds, err := c.extract(ctx, source, kube, s)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if c.generationOf(k) == g {
+		c.entries[k] = credentialEntry{creds: copyBytes(creds), expires: c.now().Add(c.ttl)}
+	}
+	c.mu.Unlock()
+	return creds, nil
+}
+
+// generationOf returns the generation of the supplied key. The caller must
+// hold c.mu.
+func (c *CredentialCache) generationOf(k credentialKey) credentialGeneration {
+	g := credentialGeneration{all: c.generation}
+	if k.source == xpv1.CredentialsSourceSecret {
+		g.secret = c.secretGenerations[k.secret]
+	}
+	return g
+}
+
+func copyBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	return append(make([]byte, 0, len(b)), b...)
+}
+
+// InvalidateSecret removes all credentials extracted from the supplied Secret
+// from the cache.
+func (c *CredentialCache) InvalidateSecret(nn types.NamespacedName) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.secretGenerations[nn]++
+	for k := range c.entries {
+		if k.source == xpv1.CredentialsSourceSecret && k.secret == nn {
+			delete(c.entries, k)
+		}
+	}
+}
+
+// Invalidate removes all credentials from the cache.
+func (c *CredentialCache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	c.entries = make(map[credentialKey]credentialEntry)
+}
+
+// InvalidateCredentialsForSecret invalidates credentials cached by a
+// CredentialCache when the Secret they were extracted from changes. It never
+// enqueues reconcile requests. Use it to watch Secrets, for example:
+//
+//	Watches(&source.Kind{Type: &corev1.Secret{}}, &resource.InvalidateCredentialsForSecret{Cache: cache})
+type InvalidateCredentialsForSecret struct {
+	Cache *CredentialCache
+}
+
+// Create does nothing; credentials can't be cached for a Secret that did not
+// exist.
+func (e *InvalidateCredentialsForSecret) Create(_ event.CreateEvent, _ workqueue.RateLimitingInterface) {
+}
+
+// Update invalidates credentials extracted from the updated Secret.
+func (e *InvalidateCredentialsForSecret) Update(evt event.UpdateEvent, _ workqueue.RateLimitingInterface) {
+	e.Cache.InvalidateSecret(types.NamespacedName{Namespace: evt.ObjectNew.GetNamespace(), Name: evt.ObjectNew.GetName()})
+}
+
+// Delete invalidates credentials extracted from the deleted Secret.
+func (e *InvalidateCredentialsForSecret) Delete(evt event.DeleteEvent, _ workqueue.RateLimitingInterface) {
+	e.Cache.InvalidateSecret(types.NamespacedName{Namespace: evt.Object.GetNamespace(), Name: evt.Object.GetName()})
+}
+
+// Generic invalidates credentials extracted from the supplied Secret.
+func (e *InvalidateCredentialsForSecret) Generic(evt event.GenericEvent, _ workqueue.RateLimitingInterface) {
+	e.Cache.InvalidateSecret(types.NamespacedName{Namespace: evt.Object.GetNamespace(), Name: evt.Object.GetName()})
+}
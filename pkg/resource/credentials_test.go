@@ -0,0 +1,187 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestCredentialCache(t *testing.T) {
+	errBoom := errors.New("boom")
+	now := time.Unix(0, 0)
+
+	selectors := xpv1.CommonCredentialSelectors{
+		SecretRef: &xpv1.SecretKeySelector{
+			SecretReference: xpv1.SecretReference{Namespace: "crossplane-system", Name: "creds"},
+			Key:             "credentials",
+		},
+	}
+	secret := func(name string) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "crossplane-system", Name: name}}
+	}
+
+	type args struct {
+		err error
+		// during is called while credentials are first extracted.
+		during func(c *CredentialCache)
+		// between is called between the first and second extraction.
+		between func(c *CredentialCache)
+	}
+	type want struct {
+		creds    []byte
+		err      error
+		extracts int
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Cached": {
+			reason: "Credentials should be extracted once and then cached.",
+			args:   args{between: func(_ *CredentialCache) {}},
+			want:   want{creds: []byte("cool"), extracts: 1},
+		},
+		"Expired": {
+			reason: "Credentials should be extracted again once the TTL expires.",
+			args: args{between: func(c *CredentialCache) {
+				c.now = func() time.Time { return now.Add(DefaultCredentialCacheTTL) }
+			}},
+			want: want{creds: []byte("cool"), extracts: 2},
+		},
+		"ErrorsAreNotCached": {
+			reason: "Credentials should be extracted again if extraction failed.",
+			args: args{
+				err:     errBoom,
+				between: func(_ *CredentialCache) {},
+			},
+			want: want{err: errBoom, extracts: 2},
+		},
+		"SecretUpdated": {
+			reason: "Credentials should be extracted again if their Secret was updated.",
+			args: args{between: func(c *CredentialCache) {
+				h := &InvalidateCredentialsForSecret{Cache: c}
+				h.Update(event.UpdateEvent{ObjectOld: secret("creds"), ObjectNew: secret("creds")}, nil)
+			}},
+			want: want{creds: []byte("cool"), extracts: 2},
+		},
+		"OtherSecretDeleted": {
+			reason: "Credentials should remain cached if another Secret was deleted.",
+			args: args{between: func(c *CredentialCache) {
+				h := &InvalidateCredentialsForSecret{Cache: c}
+				h.Delete(event.DeleteEvent{Object: secret("other")}, nil)
+			}},
+			want: want{creds: []byte("cool"), extracts: 1},
+		},
+		"Invalidated": {
+			reason: "Credentials should be extracted again if the cache was invalidated.",
+			args:   args{between: func(c *CredentialCache) { c.Invalidate() }},
+			want:   want{creds: []byte("cool"), extracts: 2},
+		},
+		"SecretUpdatedDuringExtraction": {
+			reason: "Credentials should not be cached if their Secret was updated while they were being extracted, because they may be stale.",
+			args: args{
+				during: func(c *CredentialCache) {
+					h := &InvalidateCredentialsForSecret{Cache: c}
+					h.Update(event.UpdateEvent{ObjectOld: secret("creds"), ObjectNew: secret("creds")}, nil)
+				},
+				between: func(_ *CredentialCache) {},
+			},
+			want: want{creds: []byte("cool"), extracts: 2},
+		},
+		"OtherSecretUpdatedDuringExtraction": {
+			reason: "Credentials should be cached if another Secret was updated while they were being extracted.",
+			args: args{
+				during: func(c *CredentialCache) {
+					h := &InvalidateCredentialsForSecret{Cache: c}
+					h.Update(event.UpdateEvent{ObjectOld: secret("other"), ObjectNew: secret("other")}, nil)
+				},
+				between: func(_ *CredentialCache) {},
+			},
+			want: want{creds: []byte("cool"), extracts: 1},
+		},
+		"InvalidatedDuringExtraction": {
+			reason: "Credentials should not be cached if the cache was invalidated while they were being extracted.",
+			args: args{
+				during:  func(c *CredentialCache) { c.Invalidate() },
+				between: func(_ *CredentialCache) {},
+			},
+			want: want{creds: []byte("cool"), extracts: 2},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			extracts := 0
+			var c *CredentialCache
+			c = NewCredentialCache(WithCredentialExtractor(func(_ context.Context, _ xpv1.CredentialsSource, _ client.Client, _ xpv1.CommonCredentialSelectors) ([]byte, error) {
+				extracts++
+				if extracts == 1 && tc.args.during != nil {
+					tc.args.during(c)
+				}
+				if tc.args.err != nil {
+					return nil, tc.args.err
+				}
+				return []byte("cool"), nil
+			}))
+			c.now = func() time.Time { return now }
+
+			_, _ = c.Extract(context.Background(), xpv1.CredentialsSourceSecret, nil, selectors)
+			tc.args.between(c)
+			got, err := c.Extract(context.Background(), xpv1.CredentialsSourceSecret, nil, selectors)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nc.Extract(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.creds, got); diff != "" {
+				t.Errorf("\n%s\nc.Extract(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.extracts, extracts); diff != "" {
+				t.Errorf("\n%s\nc.Extract(...): -want extracts, +got extracts:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCredentialCacheReturnsCopy(t *testing.T) {
+	c := NewCredentialCache(WithCredentialExtractor(func(_ context.Context, _ xpv1.CredentialsSource, _ client.Client, _ xpv1.CommonCredentialSelectors) ([]byte, error) {
+		return []byte("cool"), nil
+	}))
+
+	// Modifying the extracted credentials, or the cached credentials, should
+	// not modify the credentials in the cache.
+	for i := 0; i < 2; i++ {
+		got, _ := c.Extract(context.Background(), xpv1.CredentialsSourceSecret, nil, xpv1.CommonCredentialSelectors{})
+		if diff := cmp.Diff([]byte("cool"), got); diff != "" {
+			t.Errorf("\nc.Extract(...): -want, +got:\n%s", diff)
+		}
+		copy(got, "lame")
+	}
+}
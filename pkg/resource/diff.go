@@ -0,0 +1,272 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+)
+
+const (
+	errConvertCurrent = "cannot convert current object to unstructured"
+	errConvertDesired = "cannot convert desired object to unstructured"
+)
+
+// Redacted replaces the values of redacted fields in a Diff.
+const Redacted = "(redacted)"
+
+// ReasonApplyDiff indicates that changes are about to be applied to an object.
+const ReasonApplyDiff event.Reason = "ApplyingChanges"
+
+// DefaultRedactedPaths are the field paths whose values are redacted from a
+// Diff by default, because they contain the data of Secrets.
+var DefaultRedactedPaths = []string{"data", "stringData"}
+
+// DefaultIgnoredPaths are the field paths that are excluded from a Diff by
+// default, because they are set by the API server rather than applied.
+var DefaultIgnoredPaths = []string{
+	"status",
+	"metadata.resourceVersion",
+	"metadata.uid",
+	"metadata.generation",
+	"metadata.creationTimestamp",
+	"metadata.managedFields",
+}
+
+// A FieldDiff is a change to a single field. Lists are treated as a single
+// field because applicators replace them wholesale.
+type FieldDiff struct {
+	// Path to the changed field, e.g. spec.forProvider.region.
+	Path string `json:"path"`
+
+	// Current value of the field, or nil if it is not set.
+	Current any `json:"current,omitempty"`
+
+	// Desired value of the field.
+	Desired any `json:"desired,omitempty"`
+}
+
+// A Diff is the set of changes about to be applied to an object, ordered by
+// field path.
+type Diff []FieldDiff
+
+// Paths returns the path of each changed field.
+func (d Diff) Paths() []string {
+	p := make([]string, len(d))
+	for i := range d {
+		p[i] = d[i].Path
+	}
+	return p
+}
+
+// A DiffRecorder records the changes an Applicator is about to apply to an
+// object.
+type DiffRecorder interface {
+	RecordDiff(ctx context.Context, o client.Object, d Diff)
+}
+
+// A DiffRecorderFn is a function that satisfies the DiffRecorder interface.
+type DiffRecorderFn func(ctx context.Context, o client.Object, d Diff)
+
+// RecordDiff records the supplied changes.
+func (fn DiffRecorderFn) RecordDiff(ctx context.Context, o client.Object, d Diff) {
+	fn(ctx, o, d)
+}
+
+// DiffRecorders records changes using each of its recorders in order.
+type DiffRecorders []DiffRecorder
+
+// RecordDiff records the supplied changes using each recorder.
+func (rs DiffRecorders) RecordDiff(ctx context.Context, o client.Object, d Diff) {
+	for _, r := range rs {
+		r.RecordDiff(ctx, o, d)
+	}
+}
+
+// NewEventDiffRecorder returns a DiffRecorder that emits an event naming the
+// fields about to be changed. Field values are not included in the event.
+func NewEventDiffRecorder(r event.Recorder) DiffRecorder {
+	return DiffRecorderFn(func(_ context.Context, o client.Object, d Diff) {
+		r.Event(o, event.Normal(ReasonApplyDiff, fmt.Sprintf("Applying changes to fields: %s", strings.Join(d.Paths(), ", "))))
+	})
+}
+
+// NewLogDiffRecorder returns a DiffRecorder that logs the changes about to be
+// applied, including their (possibly redacted) values, at debug level.
+func NewLogDiffRecorder(l logging.Logger) DiffRecorder {
+	return DiffRecorderFn(func(_ context.Context, o client.Object, d Diff) {
+		l.Debug("Applying changes", "kind", o.GetObjectKind().GroupVersionKind().String(), "namespace", o.GetNamespace(), "name", o.GetName(), "diff", d)
+	})
+}
+
+// A DiffRecordingApplicatorOption configures a DiffRecordingApplicator.
+type DiffRecordingApplicatorOption func(a *DiffRecordingApplicator)
+
+// WithRedactedPaths configures the field paths whose values are redacted from
+// recorded diffs. Fields beneath the supplied paths are also redacted. The
+// DefaultRedactedPaths are replaced.
+func WithRedactedPaths(p ...string) DiffRecordingApplicatorOption {
+	return func(a *DiffRecordingApplicator) {
+		a.redact = p
+	}
+}
+
+// WithIgnoredPaths configures the field paths that are excluded from recorded
+// diffs. Fields beneath the supplied paths are also excluded. The
+// DefaultIgnoredPaths are replaced.
+func WithIgnoredPaths(p ...string) DiffRecordingApplicatorOption {
+	return func(a *DiffRecordingApplicator) {
+		a.ignore = p
+	}
+}
+
+// A DiffRecordingApplicator records the changes it is about to apply to an
+// object, then applies them using another Applicator. Only fields set in the
+// desired object are compared, because applicators don't remove fields that
+// are absent from the desired object. Fields that the desired object's
+// drift ignore annotation names are excluded, in addition to the ignored
+// paths.
+type DiffRecordingApplicator struct {
+	Applicator
+	client   client.Reader
+	recorder DiffRecorder
+	redact   []string
+	ignore   []string
+}
+
+// NewDiffRecordingApplicator returns an Applicator that records the changes
+// the supplied Applicator is about to apply using the supplied DiffRecorder.
+// The supplied client is used to read the current state of each object.
+func NewDiffRecordingApplicator(a Applicator, c client.Reader, r DiffRecorder, o ...DiffRecordingApplicatorOption) *DiffRecordingApplicator {
+	d := &DiffRecordingApplicator{
+		Applicator: a,
+		client:     c,
+		recorder:   r,
+		redact:     DefaultRedactedPaths,
+		ignore:     DefaultIgnoredPaths,
+	}
+	for _, fn := range o {
+		fn(d)
+	}
+	return d
+}
+
+// Apply records the changes about to be applied to the supplied object, then
+// applies them. Nothing is recorded if there are no changes.
+func (a *DiffRecordingApplicator) Apply(ctx context.Context, o client.Object, ao ...ApplyOption) error {
+	d, err := a.Diff(ctx, o)
+	if err != nil {
+		return err
+	}
+	if len(d) > 0 {
+		a.recorder.RecordDiff(ctx, o, d)
+	}
+	return a.Applicator.Apply(ctx, o, ao...)
+}
+
+// Diff returns the changes that applying the supplied desired object would
+// make to the current state of the object. Every field is reported as a
+// change if the object does not exist.
+func (a *DiffRecordingApplicator) Diff(ctx context.Context, desired client.Object) (Diff, error) {
+	current := map[string]any{}
+	if desired.GetName() != "" {
+		o := desired.DeepCopyObject().(client.Object)
+		err := a.client.Get(ctx, types.NamespacedName{Namespace: desired.GetNamespace(), Name: desired.GetName()}, o)
+		if IgnoreNotFound(err) != nil {
+			return nil, errors.Wrap(err, "cannot get object")
+		}
+		if err == nil {
+			if current, err = runtime.DefaultUnstructuredConverter.ToUnstructured(o); err != nil {
+				return nil, errors.Wrap(err, errConvertCurrent)
+			}
+		}
+	}
+
+	want, err := runtime.DefaultUnstructuredConverter.ToUnstructured(desired)
+	if err != nil {
+		return nil, errors.Wrap(err, errConvertDesired)
+	}
+
+	d := Diff{}
+	ignore := append(append([]string{}, a.ignore...), meta.GetDriftIgnoredPaths(desired)...)
+	a.diff("", current, want, ignore, &d)
+	sort.Slice(d, func(i, j int) bool { return d[i].Path < d[j].Path })
+	return d, nil
+}
+
+func (a *DiffRecordingApplicator) diff(prefix string, current, desired map[string]any, ignore []string, d *Diff) {
+	for k, want := range desired {
+		p := k
+		if prefix != "" {
+			p = prefix + "." + k
+		}
+		if want == nil || matchesPath(p, ignore) {
+			continue
+		}
+		got := current[k]
+
+		wm, wok := want.(map[string]any)
+		gm, gok := got.(map[string]any)
+		if wok && (gok || got == nil) {
+			a.diff(p, gm, wm, ignore, d)
+			continue
+		}
+
+		if reflect.DeepEqual(got, want) {
+			continue
+		}
+		if matchesPath(p, a.redact) {
+			if got != nil {
+				got = Redacted
+			}
+			want = Redacted
+		}
+		*d = append(*d, FieldDiff{Path: p, Current: got, Desired: want})
+	}
+}
+
+// IsDriftIgnored returns true if drift of the supplied field path from its
+// desired state should be ignored, because the supplied object's drift ignore
+// annotation names the path or one of its parents. Providers may use it to
+// avoid reporting externally managed fields as not up to date.
+func IsDriftIgnored(o metav1.Object, path string) bool {
+	return matchesPath(path, meta.GetDriftIgnoredPaths(o))
+}
+
+// matchesPath returns true if the supplied path is, or is beneath, any of the
+// supplied paths.
+func matchesPath(path string, paths []string) bool {
+	for _, p := range paths {
+		if path == p || strings.HasPrefix(path, p+".") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var _ Applicator = &DiffRecordingApplicator{}
+
+func TestDiffRecordingApplicator(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	current := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "default",
+			Name:            "cool",
+			ResourceVersion: "3",
+			Labels:          map[string]string{"cool": "true", "other": "true"},
+		},
+		Data: map[string][]byte{"password": []byte("old")},
+	}
+
+	type args struct {
+		c client.Reader
+		a Applicator
+		o client.Object
+	}
+
+	type want struct {
+		diff Diff
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"GetError": {
+			reason: "An error should be returned if we can't get the current object.",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				o: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "cool"}},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, "cannot get object"),
+			},
+		},
+		"ApplyError": {
+			reason: "Errors from the wrapped applicator should be returned.",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+				a: ApplyFn(func(_ context.Context, _ client.Object, _ ...ApplyOption) error { return errBoom }),
+				o: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "cool"}},
+			},
+			want: want{
+				err: errBoom,
+			},
+		},
+		"Created": {
+			reason: "Every desired field should be recorded if the object doesn't exist.",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, ""))},
+				a: ApplyFn(func(_ context.Context, _ client.Object, _ ...ApplyOption) error { return nil }),
+				o: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cool"}},
+			},
+			want: want{
+				diff: Diff{
+					{Path: "metadata.name", Desired: "cool"},
+					{Path: "metadata.namespace", Desired: "default"},
+				},
+			},
+		},
+		"Changed": {
+			reason: "Changed fields should be recorded with sensitive values redacted, ignoring fields that aren't desired or are set by the API server.",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+					current.DeepCopyInto(o.(*corev1.Secret))
+					return nil
+				})},
+				a: ApplyFn(func(_ context.Context, _ client.Object, _ ...ApplyOption) error { return nil }),
+				o: &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace:       "default",
+						Name:            "cool",
+						ResourceVersion: "2",
+						Labels:          map[string]string{"cool": "very"},
+					},
+					Data: map[string][]byte{"password": []byte("new")},
+				},
+			},
+			want: want{
+				diff: Diff{
+					{Path: "data.password", Current: Redacted, Desired: Redacted},
+					{Path: "metadata.labels.cool", Current: "true", Desired: "very"},
+				},
+			},
+		},
+		"DriftIgnored": {
+			reason: "Changes to fields named by the desired object's drift ignore annotation should not be recorded.",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+					current.DeepCopyInto(o.(*corev1.Secret))
+					o.SetAnnotations(map[string]string{meta.AnnotationKeyDriftIgnore: "data, metadata.labels"})
+					return nil
+				})},
+				a: ApplyFn(func(_ context.Context, _ client.Object, _ ...ApplyOption) error { return nil }),
+				o: &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace:   "default",
+						Name:        "cool",
+						Annotations: map[string]string{meta.AnnotationKeyDriftIgnore: "data, metadata.labels"},
+						Labels:      map[string]string{"cool": "very"},
+					},
+					Data: map[string][]byte{"password": []byte("new")},
+				},
+			},
+			want: want{},
+		},
+		"Unchanged": {
+			reason: "Nothing should be recorded if no desired fields would change.",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+					current.DeepCopyInto(o.(*corev1.Secret))
+					return nil
+				})},
+				a: ApplyFn(func(_ context.Context, _ client.Object, _ ...ApplyOption) error { return nil }),
+				o: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cool"}},
+			},
+			want: want{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got Diff
+			r := DiffRecorderFn(func(_ context.Context, _ client.Object, d Diff) { got = d })
+
+			a := NewDiffRecordingApplicator(tc.args.a, tc.args.c, r)
+			err := a.Apply(context.Background(), tc.args.o)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nApply(...): -want error, +got error\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.diff, got); diff != "" {
+				t.Errorf("\n%s\nApply(...): -want diff, +got diff\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
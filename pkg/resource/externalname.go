@@ -0,0 +1,86 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+)
+
+// ExternalNameIndexKey is the key of a field index of managed resources by
+// provider config and external name. Register it using AddExternalNameIndex.
+const ExternalNameIndexKey = "externalNameByProviderConfig"
+
+const errListExternalNames = "cannot list managed resources by external name"
+
+// IndexExternalName indexes a managed resource by its provider config and
+// external name. Managed resources without an external name are not indexed.
+func IndexExternalName(o client.Object) []string {
+	mg, ok := o.(Managed)
+	if !ok {
+		return nil
+	}
+	name := meta.GetExternalName(mg)
+	if name == "" {
+		return nil
+	}
+	return []string{externalNameIndexValue(mg, name)}
+}
+
+func externalNameIndexValue(mg Managed, name string) string {
+	pc := ""
+	if ref := mg.GetProviderConfigReference(); ref != nil {
+		pc = ref.Name
+	}
+	return pc + "/" + name
+}
+
+// AddExternalNameIndex adds ExternalNameIndexKey to the supplied indexer for
+// the supplied kind of managed resource. It must be added before the indexer's
+// cache is started.
+func AddExternalNameIndex(ctx context.Context, i client.FieldIndexer, of Managed) error {
+	return i.IndexField(ctx, of, ExternalNameIndexKey, IndexExternalName)
+}
+
+// ListExternalNameConflicts returns the other managed resources of the same
+// kind and provider config as the supplied managed resource that declare its
+// external name. The supplied list must be the list type of that kind, and the
+// supplied client must be backed by a cache with ExternalNameIndexKey.
+func ListExternalNameConflicts(ctx context.Context, c client.Reader, l ManagedList, mg Managed) ([]Managed, error) {
+	name := meta.GetExternalName(mg)
+	if name == "" {
+		return nil, nil
+	}
+
+	l = l.DeepCopyObject().(ManagedList)
+	if err := c.List(ctx, l, client.MatchingFields{ExternalNameIndexKey: externalNameIndexValue(mg, name)}); err != nil {
+		return nil, errors.Wrap(err, errListExternalNames)
+	}
+
+	others := make([]Managed, 0)
+	for _, o := range l.GetItems() {
+		if o.GetUID() == mg.GetUID() {
+			continue
+		}
+		others = append(others, o)
+	}
+	return others, nil
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestIndexExternalName(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		o      client.Object
+		want   []string
+	}{
+		"NotManaged": {
+			reason: "Objects that aren't managed resources should not be indexed.",
+			o:      &fake.Object{},
+		},
+		"NoExternalName": {
+			reason: "Managed resources without an external name should not be indexed.",
+			o:      &fake.Managed{},
+		},
+		"Indexed": {
+			reason: "Managed resources should be indexed by provider config and external name.",
+			o: &fake.Managed{
+				ObjectMeta:               metav1.ObjectMeta{Annotations: map[string]string{meta.AnnotationKeyExternalName: "cool"}},
+				ProviderConfigReferencer: fake.ProviderConfigReferencer{Ref: &xpv1.Reference{Name: "pc"}},
+			},
+			want: []string{"pc/cool"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IndexExternalName(tc.o)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nIndexExternalName(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestListExternalNameConflicts(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	named := func(uid, externalName string) *fake.Managed {
+		return &fake.Managed{ObjectMeta: metav1.ObjectMeta{
+			Name:        uid,
+			UID:         types.UID("uid-" + uid),
+			Annotations: map[string]string{meta.AnnotationKeyExternalName: externalName},
+		}}
+	}
+
+	type args struct {
+		c  client.Reader
+		mg Managed
+	}
+	type want struct {
+		others []Managed
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoExternalName": {
+			reason: "Managed resources without an external name can't conflict.",
+			args: args{
+				mg: &fake.Managed{},
+			},
+		},
+		"ListError": {
+			reason: "Errors listing managed resources should be returned.",
+			args: args{
+				c:  &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				mg: named("a", "cool"),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errListExternalNames),
+			},
+		},
+		"Conflicts": {
+			reason: "Other managed resources that declare the same external name should be returned.",
+			args: args{
+				c: &test.MockClient{MockList: func(_ context.Context, l client.ObjectList, opts ...client.ListOption) error {
+					lo := &client.ListOptions{}
+					lo.ApplyOptions(opts)
+					if got := lo.FieldSelector.String(); got != ExternalNameIndexKey+"=/cool" {
+						t.Errorf("List(...): unexpected field selector %q", got)
+					}
+					l.(*fake.ManagedList[Managed]).Items = []*fake.Managed{named("a", "cool"), named("b", "cool")}
+					return nil
+				}},
+				mg: named("a", "cool"),
+			},
+			want: want{
+				others: []Managed{named("b", "cool")},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ListExternalNameConflicts(context.Background(), tc.args.c, &fake.ManagedList[Managed]{}, tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nListExternalNameConflicts(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.others, got); diff != "" {
+				t.Errorf("\n%s\nListExternalNameConflicts(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -94,6 +94,22 @@ func (m *RequiredProviderConfigReferencer) GetProviderConfigReference() xpv1.Ref
 	return m.Ref
 }
 
+// SecondaryProviderConfigReferencer is a mock that implements the
+// SecondaryProviderConfigReferencer interface.
+type SecondaryProviderConfigReferencer struct{ Refs []xpv1.Reference }
+
+// SetSecondaryProviderConfigReferences sets the
+// SecondaryProviderConfigReferences.
+func (m *SecondaryProviderConfigReferencer) SetSecondaryProviderConfigReferences(r []xpv1.Reference) {
+	m.Refs = r
+}
+
+// GetSecondaryProviderConfigReferences gets the
+// SecondaryProviderConfigReferences.
+func (m *SecondaryProviderConfigReferencer) GetSecondaryProviderConfigReferences() []xpv1.Reference {
+	return m.Refs
+}
+
 // RequiredTypedResourceReferencer is a mock that implements the
 // RequiredTypedResourceReferencer interface.
 type RequiredTypedResourceReferencer struct{ Ref xpv1.TypedReference }
@@ -355,6 +371,37 @@ func (m *Managed) DeepCopyObject() runtime.Object {
 	return out
 }
 
+// ManagedList is a mock that implements ManagedList interface. T must be
+// resource.Managed; this package can't refer to it because the resource
+// package's tests import this package.
+type ManagedList[T any] struct {
+	metav1.ListMeta
+	Items []*Managed
+}
+
+// GetObjectKind returns schema.ObjectKind.
+func (l *ManagedList[T]) GetObjectKind() schema.ObjectKind {
+	return schema.EmptyObjectKind
+}
+
+// DeepCopyObject returns a copy of the object as runtime.Object
+func (l *ManagedList[T]) DeepCopyObject() runtime.Object {
+	out := &ManagedList[T]{ListMeta: *l.ListMeta.DeepCopy(), Items: make([]*Managed, len(l.Items))}
+	for i := range l.Items {
+		out.Items[i] = l.Items[i].DeepCopyObject().(*Managed)
+	}
+	return out
+}
+
+// GetItems returns the list of managed resources.
+func (l *ManagedList[T]) GetItems() []T {
+	items := make([]T, len(l.Items))
+	for i := range l.Items {
+		items[i] = any(l.Items[i]).(T)
+	}
+	return items
+}
+
 // Composite is a mock that implements Composite interface.
 type Composite struct {
 	metav1.ObjectMeta
@@ -451,6 +498,7 @@ type Manager struct {
 	manager.Manager
 
 	Client     client.Client
+	APIReader  client.Reader
 	Scheme     *runtime.Scheme
 	Config     *rest.Config
 	RESTMapper meta.RESTMapper
@@ -466,6 +514,14 @@ func (m *Manager) Elected() <-chan struct{} {
 // GetClient returns the client.
 func (m *Manager) GetClient() client.Client { return m.Client }
 
+// GetAPIReader returns the API reader, or the client if no API reader is set.
+func (m *Manager) GetAPIReader() client.Reader {
+	if m.APIReader == nil {
+		return m.Client
+	}
+	return m.APIReader
+}
+
 // GetScheme returns the scheme.
 func (m *Manager) GetScheme() *runtime.Scheme { return m.Scheme }
 
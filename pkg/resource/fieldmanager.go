@@ -0,0 +1,108 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// maxFieldManager is the maximum length of a field manager name accepted by
+// the API server.
+const maxFieldManager = 128
+
+// FieldManagerName returns the name a provider's controller for the supplied
+// kind should use as its field manager, e.g. provider-aws/bucket.s3.aws.
+func FieldManagerName(provider string, gvk schema.GroupVersionKind) string {
+	n := provider + "/" + strings.ToLower(gvk.GroupKind().String())
+	if len(n) > maxFieldManager {
+		n = n[:maxFieldManager]
+	}
+	return n
+}
+
+// A FieldManagedClient is a client that attributes every write it makes to a
+// field manager, so that an object's managedFields record which controller
+// wrote which fields and server-side apply conflicts name the controller that
+// caused them. A field owner supplied by the caller takes precedence.
+type FieldManagedClient struct {
+	client.Client
+	manager string
+}
+
+// NewFieldManagedClient returns a client that attributes every write it makes
+// to the named field manager. Use it to construct the client passed to
+// applicators, secret stores, and usage trackers.
+func NewFieldManagedClient(c client.Client, manager string) *FieldManagedClient {
+	return &FieldManagedClient{Client: c, manager: manager}
+}
+
+// Create the supplied object.
+func (c *FieldManagedClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	return c.Client.Create(ctx, obj, append([]client.CreateOption{client.FieldOwner(c.manager)}, opts...)...)
+}
+
+// Update the supplied object.
+func (c *FieldManagedClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	return c.Client.Update(ctx, obj, append([]client.UpdateOption{client.FieldOwner(c.manager)}, opts...)...)
+}
+
+// Patch the supplied object.
+func (c *FieldManagedClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return c.Client.Patch(ctx, obj, patch, append([]client.PatchOption{client.FieldOwner(c.manager)}, opts...)...)
+}
+
+// Status returns a client for the status subresource that attributes every
+// write it makes to the field manager.
+func (c *FieldManagedClient) Status() client.SubResourceWriter {
+	return &fieldManagedSubResourceWriter{SubResourceWriter: c.Client.Status(), manager: c.manager}
+}
+
+// SubResource returns a client for the named subresource that attributes
+// every write it makes to the field manager.
+func (c *FieldManagedClient) SubResource(subResource string) client.SubResourceClient {
+	sc := c.Client.SubResource(subResource)
+	return &fieldManagedSubResourceClient{
+		SubResourceReader:             sc,
+		fieldManagedSubResourceWriter: fieldManagedSubResourceWriter{SubResourceWriter: sc, manager: c.manager},
+	}
+}
+
+type fieldManagedSubResourceWriter struct {
+	client.SubResourceWriter
+	manager string
+}
+
+func (w *fieldManagedSubResourceWriter) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	return w.SubResourceWriter.Create(ctx, obj, subResource, append([]client.SubResourceCreateOption{client.FieldOwner(w.manager)}, opts...)...)
+}
+
+func (w *fieldManagedSubResourceWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	return w.SubResourceWriter.Update(ctx, obj, append([]client.SubResourceUpdateOption{client.FieldOwner(w.manager)}, opts...)...)
+}
+
+func (w *fieldManagedSubResourceWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	return w.SubResourceWriter.Patch(ctx, obj, patch, append([]client.SubResourcePatchOption{client.FieldOwner(w.manager)}, opts...)...)
+}
+
+type fieldManagedSubResourceClient struct {
+	client.SubResourceReader
+	fieldManagedSubResourceWriter
+}
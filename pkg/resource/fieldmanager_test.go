@@ -0,0 +1,148 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestFieldManagerName(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		provider string
+		gvk      schema.GroupVersionKind
+		want     string
+	}{
+		"Simple": {
+			reason:   "The name should combine the provider name and the kind.",
+			provider: "provider-example",
+			gvk:      schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Bucket"},
+			want:     "provider-example/bucket.example.org",
+		},
+		"TooLong": {
+			reason:   "The name should be truncated to the maximum length of a field manager.",
+			provider: strings.Repeat("p", 130),
+			gvk:      schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Bucket"},
+			want:     strings.Repeat("p", 128),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := FieldManagerName(tc.provider, tc.gvk)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nFieldManagerName(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestFieldManagedClient(t *testing.T) {
+	var got string
+	mc := &test.MockClient{
+		MockCreate: func(_ context.Context, _ client.Object, opts ...client.CreateOption) error {
+			got = (&client.CreateOptions{}).ApplyOptions(opts).FieldManager
+			return nil
+		},
+		MockUpdate: func(_ context.Context, _ client.Object, opts ...client.UpdateOption) error {
+			got = (&client.UpdateOptions{}).ApplyOptions(opts).FieldManager
+			return nil
+		},
+		MockPatch: func(_ context.Context, _ client.Object, _ client.Patch, opts ...client.PatchOption) error {
+			got = (&client.PatchOptions{}).ApplyOptions(opts).FieldManager
+			return nil
+		},
+		MockStatusUpdate: func(_ context.Context, _ client.Object, opts ...client.SubResourceUpdateOption) error {
+			got = (&client.SubResourceUpdateOptions{}).ApplyOptions(opts).FieldManager
+			return nil
+		},
+		MockStatusPatch: func(_ context.Context, _ client.Object, _ client.Patch, opts ...client.SubResourcePatchOption) error {
+			got = (&client.SubResourcePatchOptions{}).ApplyOptions(opts).FieldManager
+			return nil
+		},
+		MockSubResourceCreate: func(_ context.Context, _, _ client.Object, opts ...client.SubResourceCreateOption) error {
+			got = (&client.SubResourceCreateOptions{}).ApplyOptions(opts).FieldManager
+			return nil
+		},
+	}
+	c := NewFieldManagedClient(mc, "cool-manager")
+	ctx := context.Background()
+	obj := &fake.Managed{}
+
+	cases := map[string]struct {
+		reason string
+		write  func() error
+		want   string
+	}{
+		"Create": {
+			reason: "Creates should be attributed to the field manager.",
+			write:  func() error { return c.Create(ctx, obj) },
+			want:   "cool-manager",
+		},
+		"Update": {
+			reason: "Updates should be attributed to the field manager.",
+			write:  func() error { return c.Update(ctx, obj) },
+			want:   "cool-manager",
+		},
+		"Patch": {
+			reason: "Patches should be attributed to the field manager.",
+			write:  func() error { return c.Patch(ctx, obj, client.Merge) },
+			want:   "cool-manager",
+		},
+		"PatchExplicitOwner": {
+			reason: "A field owner supplied by the caller should take precedence.",
+			write:  func() error { return c.Patch(ctx, obj, client.Apply, client.FieldOwner("other")) },
+			want:   "other",
+		},
+		"StatusUpdate": {
+			reason: "Status updates should be attributed to the field manager.",
+			write:  func() error { return c.Status().Update(ctx, obj) },
+			want:   "cool-manager",
+		},
+		"StatusPatch": {
+			reason: "Status patches should be attributed to the field manager.",
+			write:  func() error { return c.Status().Patch(ctx, obj, client.Merge) },
+			want:   "cool-manager",
+		},
+		"SubResourceCreate": {
+			reason: "Subresource creates should be attributed to the field manager.",
+			write:  func() error { return c.SubResource("eviction").Create(ctx, obj, &fake.Object{}) },
+			want:   "cool-manager",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got = ""
+			if err := tc.write(); err != nil {
+				t.Fatalf("\n%s\nwrite(...): %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nwrite(...) field manager: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+)
+
+const (
+	errFmtFinalizerPending = "cannot remove finalizer %q yet"
+	errFmtFinalizerBlocked = "cannot remove finalizer %q before finalizer %q"
+)
+
+// A FinalizerCondition returns nil if a finalizer may be removed from the
+// supplied object, or an error explaining why it may not yet be removed.
+type FinalizerCondition func(ctx context.Context, obj Object) error
+
+// A FinalizerStep is a named finalizer in a FinalizerChain.
+type FinalizerStep struct {
+	// Name of the finalizer, e.g. finalizer.example.org/release-secrets.
+	Name string
+
+	// After names the finalizers of any steps that must be removed before
+	// this step's finalizer may be removed.
+	After []string
+
+	// Condition under which the finalizer may be removed. The finalizer may
+	// be removed as soon as the steps it follows have been removed if the
+	// condition is nil.
+	Condition FinalizerCondition
+}
+
+// A FinalizerChain adds and removes an ordered set of named finalizers to and
+// from a resource. Each finalizer is removed only once the finalizers it
+// follows have been removed and its own removal condition is met, for example
+// once the resource has no dependents or its external resource is gone.
+type FinalizerChain struct {
+	client client.Client
+	steps  []FinalizerStep
+}
+
+// NewFinalizerChain returns a Finalizer that adds and removes the finalizers
+// of the supplied steps.
+func NewFinalizerChain(c client.Client, s ...FinalizerStep) *FinalizerChain {
+	return &FinalizerChain{client: c, steps: s}
+}
+
+// AddFinalizer adds the finalizer of each step to the supplied resource.
+func (fc *FinalizerChain) AddFinalizer(ctx context.Context, obj Object) error {
+	changed := false
+	for _, s := range fc.steps {
+		if meta.FinalizerExists(obj, s.Name) {
+			continue
+		}
+		meta.AddFinalizer(obj, s.Name)
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return errors.Wrap(fc.client.Update(ctx, obj), errUpdateObject)
+}
+
+// RemoveFinalizer removes the finalizer of each step that may be removed from
+// the supplied resource. An error is returned if any finalizer could not yet
+// be removed, so that the caller will try again later.
+func (fc *FinalizerChain) RemoveFinalizer(ctx context.Context, obj Object) error {
+	var pending error
+	changed := false
+
+	// Removing one finalizer may unblock another, so we make passes over the
+	// steps until we remove nothing.
+	for progress := true; progress; {
+		progress = false
+		pending = nil
+		for _, s := range fc.steps {
+			if !meta.FinalizerExists(obj, s.Name) {
+				continue
+			}
+			if err := fc.removable(ctx, obj, s); err != nil {
+				if pending == nil {
+					pending = err
+				}
+				continue
+			}
+			meta.RemoveFinalizer(obj, s.Name)
+			progress, changed = true, true
+		}
+	}
+
+	if changed {
+		if err := IgnoreNotFound(fc.client.Update(ctx, obj)); err != nil {
+			return errors.Wrap(err, errUpdateObject)
+		}
+	}
+	return pending
+}
+
+func (fc *FinalizerChain) removable(ctx context.Context, obj Object, s FinalizerStep) error {
+	for _, after := range s.After {
+		if meta.FinalizerExists(obj, after) {
+			return errors.Errorf(errFmtFinalizerBlocked, s.Name, after)
+		}
+	}
+	if s.Condition == nil {
+		return nil
+	}
+	return errors.Wrapf(s.Condition(ctx, obj), errFmtFinalizerPending, s.Name)
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var _ Finalizer = &FinalizerChain{}
+
+func TestFinalizerChainAddFinalizer(t *testing.T) {
+	errBoom := errors.New("boom")
+	steps := []FinalizerStep{{Name: "first"}, {Name: "second"}}
+
+	type args struct {
+		client client.Client
+		obj    Object
+	}
+
+	type want struct {
+		err error
+		obj Object
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"UpdateError": {
+			reason: "An error should be returned if we can't update the object.",
+			args: args{
+				client: &test.MockClient{MockUpdate: test.NewMockUpdateFn(errBoom)},
+				obj:    &fake.Object{},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errUpdateObject),
+				obj: &fake.Object{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{"first", "second"}}},
+			},
+		},
+		"AddMissing": {
+			reason: "Only missing finalizers should be added.",
+			args: args{
+				client: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				obj:    &fake.Object{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{"second"}}},
+			},
+			want: want{
+				obj: &fake.Object{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{"second", "first"}}},
+			},
+		},
+		"AllExist": {
+			reason: "The object should not be updated if all finalizers exist.",
+			args: args{
+				client: &test.MockClient{MockUpdate: test.NewMockUpdateFn(errBoom)},
+				obj:    &fake.Object{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{"first", "second"}}},
+			},
+			want: want{
+				obj: &fake.Object{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{"first", "second"}}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fc := NewFinalizerChain(tc.args.client, steps...)
+			err := fc.AddFinalizer(context.Background(), tc.args.obj)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nfc.AddFinalizer(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.obj, tc.args.obj); diff != "" {
+				t.Errorf("\n%s\nfc.AddFinalizer(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestFinalizerChainRemoveFinalizer(t *testing.T) {
+	errBoom := errors.New("boom")
+	errDependents := errors.New("resource has dependents")
+
+	type args struct {
+		client client.Client
+		steps  []FinalizerStep
+		obj    Object
+	}
+
+	type want struct {
+		err error
+		obj Object
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"UpdateError": {
+			reason: "An error should be returned if we can't update the object.",
+			args: args{
+				client: &test.MockClient{MockUpdate: test.NewMockUpdateFn(errBoom)},
+				steps:  []FinalizerStep{{Name: "first"}},
+				obj:    &fake.Object{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{"first"}}},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errUpdateObject),
+				obj: &fake.Object{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{}}},
+			},
+		},
+		"RemoveInDependencyOrder": {
+			reason: "A finalizer should be removed once the finalizers it follows are removed, regardless of the order of steps.",
+			args: args{
+				client: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				steps: []FinalizerStep{
+					{Name: "release-secrets", After: []string{"deprovision"}},
+					{Name: "deprovision"},
+				},
+				obj: &fake.Object{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{"release-secrets", "deprovision", "other"}}},
+			},
+			want: want{
+				obj: &fake.Object{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{"other"}}},
+			},
+		},
+		"ConditionNotMet": {
+			reason: "A finalizer whose condition isn't met, and any finalizers that follow it, should not be removed.",
+			args: args{
+				client: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				steps: []FinalizerStep{
+					{Name: "wait-for-dependents", Condition: func(_ context.Context, _ Object) error { return errDependents }},
+					{Name: "deprovision", After: []string{"wait-for-dependents"}},
+					{Name: "unrelated"},
+				},
+				obj: &fake.Object{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{"wait-for-dependents", "deprovision", "unrelated"}}},
+			},
+			want: want{
+				err: errors.Wrapf(errDependents, errFmtFinalizerPending, "wait-for-dependents"),
+				obj: &fake.Object{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{"wait-for-dependents", "deprovision"}}},
+			},
+		},
+		"NothingToRemove": {
+			reason: "The object should not be updated if none of the chain's finalizers exist.",
+			args: args{
+				client: &test.MockClient{MockUpdate: test.NewMockUpdateFn(errBoom)},
+				steps:  []FinalizerStep{{Name: "first"}},
+				obj:    &fake.Object{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{"other"}}},
+			},
+			want: want{
+				obj: &fake.Object{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{"other"}}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fc := NewFinalizerChain(tc.args.client, tc.args.steps...)
+			err := fc.RemoveFinalizer(context.Background(), tc.args.obj)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nfc.RemoveFinalizer(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.obj, tc.args.obj); diff != "" {
+				t.Errorf("\n%s\nfc.RemoveFinalizer(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,351 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/afero"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	errFmtMissingEnv           = "environment variable %s is not set"
+	errReadIdentityToken       = "cannot read workload identity token"
+	errNewGCPTokenRequest      = "cannot create GCP metadata server request"
+	errGetGCPToken             = "cannot get GCP workload identity token"
+	errFmtGCPTokenStatus       = "GCP metadata server returned status %d"
+	errDecodeGCPToken          = "cannot decode GCP workload identity token"
+	errGetWorkloadIdentity     = "cannot get workload identity"
+	errMarshalWorkloadIdentity = "cannot marshal workload identity"
+)
+
+// Environment variables injected by the AWS EKS Pod Identity Webhook (IRSA).
+const (
+	EnvAWSRoleARN              = "AWS_ROLE_ARN"
+	EnvAWSWebIdentityTokenFile = "AWS_WEB_IDENTITY_TOKEN_FILE"
+	EnvAWSRoleSessionName      = "AWS_ROLE_SESSION_NAME"
+)
+
+// Environment variables injected by the Azure Workload Identity webhook.
+const (
+	EnvAzureClientID           = "AZURE_CLIENT_ID"
+	EnvAzureTenantID           = "AZURE_TENANT_ID"
+	EnvAzureFederatedTokenFile = "AZURE_FEDERATED_TOKEN_FILE"
+	EnvAzureAuthorityHost      = "AZURE_AUTHORITY_HOST"
+)
+
+// Attributes of a WorkloadIdentity.
+const (
+	AttributeAWSRoleARN         = "roleARN"
+	AttributeAWSRoleSessionName = "roleSessionName"
+	AttributeAzureClientID      = "clientID"
+	AttributeAzureTenantID      = "tenantID"
+	AttributeAzureAuthorityHost = "authorityHost"
+	AttributeTokenType          = "tokenType"
+)
+
+// DefaultGCPMetadataTokenEndpoint is the GKE metadata server endpoint that
+// issues access tokens for the workload's Google service account.
+const DefaultGCPMetadataTokenEndpoint = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+
+// A WorkloadIdentity is a credential issued to a provider by the platform it
+// runs on, for example a projected service account token that may be exchanged
+// for cloud credentials.
+type WorkloadIdentity struct {
+	// Token issued to the workload.
+	Token string `json:"token"`
+
+	// Expiry of the token. The zero time indicates that the expiry is
+	// unknown.
+	Expiry time.Time `json:"expiry"`
+
+	// Attributes required to use the token, for example the role to assume.
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// A WorkloadIdentitySource gets a WorkloadIdentity.
+type WorkloadIdentitySource interface {
+	WorkloadIdentity(ctx context.Context) (WorkloadIdentity, error)
+}
+
+// A WorkloadIdentitySourceFn is a function that satisfies the
+// WorkloadIdentitySource interface.
+type WorkloadIdentitySourceFn func(ctx context.Context) (WorkloadIdentity, error)
+
+// WorkloadIdentity gets a WorkloadIdentity.
+func (fn WorkloadIdentitySourceFn) WorkloadIdentity(ctx context.Context) (WorkloadIdentity, error) {
+	return fn(ctx)
+}
+
+type workloadIdentityConfig struct {
+	env         EnvLookupFn
+	fs          afero.Fs
+	client      *http.Client
+	gcpEndpoint string
+	now         func() time.Time
+}
+
+// A WorkloadIdentityOption configures a WorkloadIdentitySource.
+type WorkloadIdentityOption func(c *workloadIdentityConfig)
+
+// WithWorkloadIdentityEnv configures how environment variables are looked up.
+func WithWorkloadIdentityEnv(fn EnvLookupFn) WorkloadIdentityOption {
+	return func(c *workloadIdentityConfig) {
+		c.env = fn
+	}
+}
+
+// WithWorkloadIdentityFs configures the filesystem projected tokens are read
+// from.
+func WithWorkloadIdentityFs(fs afero.Fs) WorkloadIdentityOption {
+	return func(c *workloadIdentityConfig) {
+		c.fs = fs
+	}
+}
+
+// WithWorkloadIdentityHTTPClient configures the HTTP client used to get tokens
+// from metadata servers.
+func WithWorkloadIdentityHTTPClient(hc *http.Client) WorkloadIdentityOption {
+	return func(c *workloadIdentityConfig) {
+		c.client = hc
+	}
+}
+
+// WithGCPMetadataTokenEndpoint configures the GCP metadata server endpoint
+// used to get tokens.
+func WithGCPMetadataTokenEndpoint(url string) WorkloadIdentityOption {
+	return func(c *workloadIdentityConfig) {
+		c.gcpEndpoint = url
+	}
+}
+
+func newWorkloadIdentityConfig(o ...WorkloadIdentityOption) *workloadIdentityConfig {
+	c := &workloadIdentityConfig{
+		env:         os.Getenv,
+		fs:          afero.NewOsFs(),
+		client:      http.DefaultClient,
+		gcpEndpoint: DefaultGCPMetadataTokenEndpoint,
+		now:         time.Now,
+	}
+	for _, fn := range o {
+		fn(c)
+	}
+	return c
+}
+
+// lookup returns the values of the supplied environment variables, or an error
+// if any are not set.
+func (c *workloadIdentityConfig) lookup(names ...string) (map[string]string, error) {
+	v := make(map[string]string, len(names))
+	for _, n := range names {
+		v[n] = c.env(n)
+		if v[n] == "" {
+			return nil, errors.Errorf(errFmtMissingEnv, n)
+		}
+	}
+	return v, nil
+}
+
+// projectedToken reads a projected service account token from the supplied
+// path. The token is re-read each time because the kubelet rotates it.
+func (c *workloadIdentityConfig) projectedToken(path string) (WorkloadIdentity, error) {
+	b, err := afero.ReadFile(c.fs, path)
+	if err != nil {
+		return WorkloadIdentity{}, errors.Wrap(err, errReadIdentityToken)
+	}
+	t := strings.TrimSpace(string(b))
+	return WorkloadIdentity{Token: t, Expiry: jwtExpiry(t)}, nil
+}
+
+// jwtExpiry returns the expiry of the supplied JWT, or the zero time if it
+// cannot be determined. The JWT is not verified.
+func jwtExpiry(t string) time.Time {
+	parts := strings.Split(t, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	b, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+	claims := struct {
+		Exp int64 `json:"exp"`
+	}{}
+	if err := json.Unmarshal(b, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.Exp, 0)
+}
+
+// AWSWebIdentity returns a WorkloadIdentitySource that reads the web identity
+// token injected by IAM Roles for Service Accounts (IRSA). The token may be
+// exchanged for credentials for the role in the AttributeAWSRoleARN attribute
+// using STS AssumeRoleWithWebIdentity.
+func AWSWebIdentity(o ...WorkloadIdentityOption) WorkloadIdentitySource {
+	c := newWorkloadIdentityConfig(o...)
+	return WorkloadIdentitySourceFn(func(_ context.Context) (WorkloadIdentity, error) {
+		env, err := c.lookup(EnvAWSRoleARN, EnvAWSWebIdentityTokenFile)
+		if err != nil {
+			return WorkloadIdentity{}, err
+		}
+		wi, err := c.projectedToken(env[EnvAWSWebIdentityTokenFile])
+		if err != nil {
+			return WorkloadIdentity{}, err
+		}
+		wi.Attributes = map[string]string{AttributeAWSRoleARN: env[EnvAWSRoleARN]}
+		if n := c.env(EnvAWSRoleSessionName); n != "" {
+			wi.Attributes[AttributeAWSRoleSessionName] = n
+		}
+		return wi, nil
+	})
+}
+
+// AzureWorkloadIdentity returns a WorkloadIdentitySource that reads the
+// federated token injected by Azure Workload Identity. The token may be
+// exchanged for an access token for the application in the
+// AttributeAzureClientID attribute using a client assertion.
+func AzureWorkloadIdentity(o ...WorkloadIdentityOption) WorkloadIdentitySource {
+	c := newWorkloadIdentityConfig(o...)
+	return WorkloadIdentitySourceFn(func(_ context.Context) (WorkloadIdentity, error) {
+		env, err := c.lookup(EnvAzureClientID, EnvAzureTenantID, EnvAzureFederatedTokenFile)
+		if err != nil {
+			return WorkloadIdentity{}, err
+		}
+		wi, err := c.projectedToken(env[EnvAzureFederatedTokenFile])
+		if err != nil {
+			return WorkloadIdentity{}, err
+		}
+		wi.Attributes = map[string]string{
+			AttributeAzureClientID: env[EnvAzureClientID],
+			AttributeAzureTenantID: env[EnvAzureTenantID],
+		}
+		if h := c.env(EnvAzureAuthorityHost); h != "" {
+			wi.Attributes[AttributeAzureAuthorityHost] = h
+		}
+		return wi, nil
+	})
+}
+
+// GCPWorkloadIdentity returns a WorkloadIdentitySource that gets an access
+// token for the Google service account bound to the workload by GKE Workload
+// Identity from the GKE metadata server. The token may be used directly.
+func GCPWorkloadIdentity(o ...WorkloadIdentityOption) WorkloadIdentitySource {
+	c := newWorkloadIdentityConfig(o...)
+	return WorkloadIdentitySourceFn(func(ctx context.Context) (WorkloadIdentity, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.gcpEndpoint, nil)
+		if err != nil {
+			return WorkloadIdentity{}, errors.Wrap(err, errNewGCPTokenRequest)
+		}
+		req.Header.Set("Metadata-Flavor", "Google")
+
+		rsp, err := c.client.Do(req)
+		if err != nil {
+			return WorkloadIdentity{}, errors.Wrap(err, errGetGCPToken)
+		}
+		defer rsp.Body.Close() //nolint:errcheck // Nothing to do if we can't close the body.
+		if rsp.StatusCode != http.StatusOK {
+			return WorkloadIdentity{}, errors.Wrap(errors.Errorf(errFmtGCPTokenStatus, rsp.StatusCode), errGetGCPToken)
+		}
+
+		t := struct {
+			AccessToken string `json:"access_token"`
+			ExpiresIn   int64  `json:"expires_in"`
+			TokenType   string `json:"token_type"`
+		}{}
+		if err := json.NewDecoder(rsp.Body).Decode(&t); err != nil {
+			return WorkloadIdentity{}, errors.Wrap(err, errDecodeGCPToken)
+		}
+		return WorkloadIdentity{
+			Token:      t.AccessToken,
+			Expiry:     c.now().Add(time.Duration(t.ExpiresIn) * time.Second),
+			Attributes: map[string]string{AttributeTokenType: t.TokenType},
+		}, nil
+	})
+}
+
+// A CachingWorkloadIdentitySource caches the WorkloadIdentity of another
+// source until shortly before it expires.
+type CachingWorkloadIdentitySource struct {
+	source        WorkloadIdentitySource
+	refreshBefore time.Duration
+	now           func() time.Time
+
+	mu     sync.Mutex
+	cached *WorkloadIdentity
+}
+
+// NewCachingWorkloadIdentitySource returns a WorkloadIdentitySource that caches
+// the WorkloadIdentity of the supplied source, refreshing it when it is within
+// refreshBefore of expiring. A WorkloadIdentity with an unknown expiry is never
+// cached.
+func NewCachingWorkloadIdentitySource(s WorkloadIdentitySource, refreshBefore time.Duration) *CachingWorkloadIdentitySource {
+	return &CachingWorkloadIdentitySource{source: s, refreshBefore: refreshBefore, now: time.Now}
+}
+
+// WorkloadIdentity returns the cached WorkloadIdentity, refreshing it if it is
+// about to expire.
+func (s *CachingWorkloadIdentitySource) WorkloadIdentity(ctx context.Context) (WorkloadIdentity, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cached != nil && s.now().Add(s.refreshBefore).Before(s.cached.Expiry) {
+		return *s.cached, nil
+	}
+
+	wi, err := s.source.WorkloadIdentity(ctx)
+	if err != nil {
+		return WorkloadIdentity{}, err
+	}
+	s.cached = nil
+	if !wi.Expiry.IsZero() {
+		s.cached = &wi
+	}
+	return wi, nil
+}
+
+// A CredentialExtractor extracts credentials from the supplied source. Its
+// signature matches CommonCredentialExtractor.
+type CredentialExtractor func(ctx context.Context, source xpv1.CredentialsSource, c client.Client, s xpv1.CommonCredentialSelectors) ([]byte, error)
+
+// NewInjectedIdentityExtractor returns a CredentialExtractor that extracts
+// credentials from the InjectedIdentity source as the JSON encoding of the
+// WorkloadIdentity returned by the supplied WorkloadIdentitySource. Credentials
+// are extracted from all other sources using CommonCredentialExtractor.
+func NewInjectedIdentityExtractor(wis WorkloadIdentitySource) CredentialExtractor {
+	return func(ctx context.Context, source xpv1.CredentialsSource, c client.Client, s xpv1.CommonCredentialSelectors) ([]byte, error) {
+		if source != xpv1.CredentialsSourceInjectedIdentity {
+			return CommonCredentialExtractor(ctx, source, c, s)
+		}
+		wi, err := wis.WorkloadIdentity(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, errGetWorkloadIdentity)
+		}
+		b, err := json.Marshal(wi)
+		return b, errors.Wrap(err, errMarshalWorkloadIdentity)
+	}
+}
@@ -0,0 +1,304 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+// jwt returns an unsigned JWT that expires at the supplied time.
+func jwt(exp time.Time) string {
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString([]byte(`{"alg":"none"}`)) + "." +
+		enc.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, exp.Unix()))) + "."
+}
+
+func TestProjectedWorkloadIdentity(t *testing.T) {
+	exp := time.Unix(1700000000, 0)
+	token := jwt(exp)
+
+	fs := afero.NewMemMapFs()
+	_ = afero.WriteFile(fs, "/token", []byte(token+"\n"), 0o600)
+
+	type args struct {
+		source func(o ...WorkloadIdentityOption) WorkloadIdentitySource
+		env    map[string]string
+	}
+	type want struct {
+		wi  WorkloadIdentity
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"AWSMissingEnv": {
+			reason: "We should return an error if IRSA environment variables are not set.",
+			args: args{
+				source: AWSWebIdentity,
+				env:    map[string]string{EnvAWSWebIdentityTokenFile: "/token"},
+			},
+			want: want{err: errors.Errorf(errFmtMissingEnv, EnvAWSRoleARN)},
+		},
+		"AWSMissingToken": {
+			reason: "We should return an error if the IRSA token cannot be read.",
+			args: args{
+				source: AWSWebIdentity,
+				env:    map[string]string{EnvAWSRoleARN: "arn", EnvAWSWebIdentityTokenFile: "/nope"},
+			},
+			want: want{err: errors.Wrap(func() error { _, err := afero.ReadFile(fs, "/nope"); return err }(), errReadIdentityToken)},
+		},
+		"AWS": {
+			reason: "We should return the IRSA token and the role to assume.",
+			args: args{
+				source: AWSWebIdentity,
+				env: map[string]string{
+					EnvAWSRoleARN:              "arn",
+					EnvAWSWebIdentityTokenFile: "/token",
+					EnvAWSRoleSessionName:      "cool",
+				},
+			},
+			want: want{wi: WorkloadIdentity{
+				Token:      token,
+				Expiry:     exp,
+				Attributes: map[string]string{AttributeAWSRoleARN: "arn", AttributeAWSRoleSessionName: "cool"},
+			}},
+		},
+		"Azure": {
+			reason: "We should return the Azure federated token and the application it identifies.",
+			args: args{
+				source: AzureWorkloadIdentity,
+				env: map[string]string{
+					EnvAzureClientID:           "client",
+					EnvAzureTenantID:           "tenant",
+					EnvAzureFederatedTokenFile: "/token",
+				},
+			},
+			want: want{wi: WorkloadIdentity{
+				Token:      token,
+				Expiry:     exp,
+				Attributes: map[string]string{AttributeAzureClientID: "client", AttributeAzureTenantID: "tenant"},
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := tc.args.source(
+				WithWorkloadIdentityFs(fs),
+				WithWorkloadIdentityEnv(func(k string) string { return tc.args.env[k] }),
+			)
+			got, err := s.WorkloadIdentity(context.Background())
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.WorkloadIdentity(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.wi, got); diff != "" {
+				t.Errorf("\n%s\ns.WorkloadIdentity(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestGCPWorkloadIdentity(t *testing.T) {
+	type want struct {
+		wi  WorkloadIdentity
+		err error
+	}
+
+	cases := map[string]struct {
+		reason  string
+		handler http.HandlerFunc
+		want    want
+	}{
+		"ErrorStatus": {
+			reason: "We should return an error if the metadata server returns an error status.",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			want: want{err: errors.Wrap(errors.Errorf(errFmtGCPTokenStatus, http.StatusNotFound), errGetGCPToken)},
+		},
+		"Success": {
+			reason: "We should return the access token issued by the metadata server.",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Metadata-Flavor") != "Google" {
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+				_, _ = w.Write([]byte(`{"access_token":"cool","expires_in":3600,"token_type":"Bearer"}`))
+			},
+			want: want{wi: WorkloadIdentity{
+				Token:      "cool",
+				Expiry:     time.Unix(3600, 0),
+				Attributes: map[string]string{AttributeTokenType: "Bearer"},
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(tc.handler)
+			defer srv.Close()
+
+			s := GCPWorkloadIdentity(
+				WithGCPMetadataTokenEndpoint(srv.URL),
+				WithWorkloadIdentityHTTPClient(srv.Client()),
+				func(c *workloadIdentityConfig) { c.now = func() time.Time { return time.Unix(0, 0) } },
+			)
+			got, err := s.WorkloadIdentity(context.Background())
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.WorkloadIdentity(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.wi, got); diff != "" {
+				t.Errorf("\n%s\ns.WorkloadIdentity(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCachingWorkloadIdentitySource(t *testing.T) {
+	now := time.Unix(1000, 0)
+
+	type want struct {
+		wi    WorkloadIdentity
+		calls int
+	}
+
+	cases := map[string]struct {
+		reason string
+		expiry time.Time
+		want   want
+	}{
+		"Fresh": {
+			reason: "A WorkloadIdentity that is not about to expire should be cached.",
+			expiry: now.Add(time.Hour),
+			want:   want{wi: WorkloadIdentity{Token: "cool", Expiry: now.Add(time.Hour)}, calls: 1},
+		},
+		"AboutToExpire": {
+			reason: "A WorkloadIdentity that is about to expire should be refreshed.",
+			expiry: now.Add(time.Minute),
+			want:   want{wi: WorkloadIdentity{Token: "cool", Expiry: now.Add(time.Minute)}, calls: 2},
+		},
+		"UnknownExpiry": {
+			reason: "A WorkloadIdentity with an unknown expiry should not be cached.",
+			want:   want{wi: WorkloadIdentity{Token: "cool"}, calls: 2},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			s := NewCachingWorkloadIdentitySource(WorkloadIdentitySourceFn(func(_ context.Context) (WorkloadIdentity, error) {
+				calls++
+				return WorkloadIdentity{Token: "cool", Expiry: tc.expiry}, nil
+			}), 5*time.Minute)
+			s.now = func() time.Time { return now }
+
+			var got WorkloadIdentity
+			for i := 0; i < 2; i++ {
+				var err error
+				if got, err = s.WorkloadIdentity(context.Background()); err != nil {
+					t.Fatalf("\n%s\ns.WorkloadIdentity(...): %s", tc.reason, err)
+				}
+			}
+			if diff := cmp.Diff(tc.want.wi, got); diff != "" {
+				t.Errorf("\n%s\ns.WorkloadIdentity(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.calls, calls); diff != "" {
+				t.Errorf("\n%s\ns.WorkloadIdentity(...): -want calls, +got calls:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestInjectedIdentityExtractor(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		wis    WorkloadIdentitySource
+		source xpv1.CredentialsSource
+	}
+	type want struct {
+		creds []byte
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"OtherSource": {
+			reason: "Sources other than InjectedIdentity should be handled by CommonCredentialExtractor.",
+			args: args{
+				source: xpv1.CredentialsSourceNone,
+			},
+			want: want{},
+		},
+		"Error": {
+			reason: "Errors getting the workload identity should be returned.",
+			args: args{
+				wis: WorkloadIdentitySourceFn(func(_ context.Context) (WorkloadIdentity, error) {
+					return WorkloadIdentity{}, errBoom
+				}),
+				source: xpv1.CredentialsSourceInjectedIdentity,
+			},
+			want: want{err: errors.Wrap(errBoom, errGetWorkloadIdentity)},
+		},
+		"Success": {
+			reason: "The workload identity should be returned as JSON.",
+			args: args{
+				wis: WorkloadIdentitySourceFn(func(_ context.Context) (WorkloadIdentity, error) {
+					return WorkloadIdentity{
+						Token:      "cool",
+						Expiry:     time.Unix(0, 0).UTC(),
+						Attributes: map[string]string{AttributeAWSRoleARN: "arn"},
+					}, nil
+				}),
+				source: xpv1.CredentialsSourceInjectedIdentity,
+			},
+			want: want{creds: []byte(`{"token":"cool","expiry":"1970-01-01T00:00:00Z","attributes":{"roleARN":"arn"}}`)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := NewInjectedIdentityExtractor(tc.args.wis)
+			got, err := e(context.Background(), tc.args.source, nil, xpv1.CommonCredentialSelectors{})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(string(tc.want.creds), string(got)); diff != "" {
+				t.Errorf("\n%s\ne(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
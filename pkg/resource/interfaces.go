@@ -98,6 +98,14 @@ type RequiredProviderConfigReferencer interface {
 	SetProviderConfigReference(p xpv1.Reference)
 }
 
+// A SecondaryProviderConfigReferencer may reference provider configs in
+// addition to its primary provider config, for example the provider config of
+// a replication target or of a peered account.
+type SecondaryProviderConfigReferencer interface {
+	GetSecondaryProviderConfigReferences() []xpv1.Reference
+	SetSecondaryProviderConfigReferences(r []xpv1.Reference)
+}
+
 // A RequiredTypedResourceReferencer can reference a resource.
 type RequiredTypedResourceReferencer interface {
 	SetResourceReference(r xpv1.TypedReference)
@@ -168,6 +176,20 @@ type EnvironmentConfigReferencer interface {
 	GetEnvironmentConfigReferences() []corev1.ObjectReference
 }
 
+// A ReferenceResolutionRecorder records the outcomes of resolving its
+// references and selectors, typically in its status.
+type ReferenceResolutionRecorder interface {
+	SetResolvedReferences(r []xpv1.ResolvedReference)
+	GetResolvedReferences() []xpv1.ResolvedReference
+}
+
+// A TimeToReadyRecorder records how long it took to first become ready after
+// it was created, typically in its status.
+type TimeToReadyRecorder interface {
+	SetTimeToReady(d *metav1.Duration)
+	GetTimeToReady() *metav1.Duration
+}
+
 // A UserCounter can count how many users it has.
 type UserCounter interface {
 	SetUsers(i int64)
@@ -23,6 +23,7 @@ import "github.com/crossplane/crossplane-runtime/pkg/resource/fake"
 
 var (
 	_ Managed             = &fake.Managed{}
+	_ ManagedList         = &fake.ManagedList[Managed]{}
 	_ ProviderConfig      = &fake.ProviderConfig{}
 	_ ProviderConfigUsage = &fake.ProviderConfigUsage{}
 
@@ -17,22 +17,70 @@ limitations under the License.
 package resource
 
 import (
+	"encoding/json"
+	"fmt"
+	"reflect"
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 )
 
+// A LateInitializerOption configures a LateInitializer.
+type LateInitializerOption func(li *LateInitializer)
+
+// WithLateInitRecord configures a LateInitializer to read the values that the
+// supplied object's fields were previously late initialized to, in order to
+// detect conflicts. See LateInitializer.DetectConflict.
+func WithLateInitRecord(o metav1.Object) LateInitializerOption {
+	return func(li *LateInitializer) {
+		// An invalid record is treated as an empty record, so that it will
+		// be overwritten when the LateInitializer is persisted.
+		_ = json.Unmarshal([]byte(o.GetAnnotations()[meta.AnnotationKeyLateInitialized]), &li.record)
+	}
+}
+
 // NewLateInitializer returns a new instance of *LateInitializer.
-func NewLateInitializer() *LateInitializer {
-	return &LateInitializer{}
+func NewLateInitializer(o ...LateInitializerOption) *LateInitializer {
+	li := &LateInitializer{record: map[string]string{}}
+	for _, fn := range o {
+		fn(li)
+	}
+	if li.record == nil {
+		li.record = map[string]string{}
+	}
+	return li
 }
 
 // LateInitializer contains functions to late initialize two fields with varying
 // types. The main purpose of LateInitializer is to be able to report whether
 // anything different from the original value has been returned after all late
 // initialization calls.
+//
+// A LateInitializer can also record the values fields were late initialized
+// to, and use that record to detect fields that have since been changed both
+// by the user and by the external system, i.e. fields whose value would
+// otherwise ping-pong between the two.
 type LateInitializer struct {
-	changed bool
+	changed   bool
+	record    map[string]string
+	conflicts []LateInitConflict
+}
+
+// A LateInitConflict is a late initialized field whose desired and observed
+// values both changed after it was late initialized, and no longer agree.
+// Values are JSON encoded.
+type LateInitConflict struct {
+	Path            string
+	LateInitialized string
+	Desired         string
+	Observed        string
+}
+
+// String describes the conflict.
+func (c LateInitConflict) String() string {
+	return fmt.Sprintf("%s was late initialized to %s, but is now %s in the spec and %s in the external system", c.Path, c.LateInitialized, c.Desired, c.Observed)
 }
 
 // IsChanged reports whether the second argument is ever used in late initialization
@@ -47,6 +95,71 @@ func (li *LateInitializer) SetChanged() {
 	li.changed = true
 }
 
+// Track records that the field at the supplied path was late initialized to
+// the supplied value. Call it after late initializing a field.
+func (li *LateInitializer) Track(path string, value any) {
+	j, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	li.record[path] = string(j)
+}
+
+// DetectConflict records a conflict if the field at the supplied path was
+// previously late initialized, and both its supplied desired and observed
+// values have since changed to different values. Call it before late
+// initializing a field. Unset (i.e. nil) desired values are not conflicts,
+// because they will be late initialized again.
+func (li *LateInitializer) DetectConflict(path string, desired, observed any) {
+	initialized, ok := li.record[path]
+	if !ok || isNil(desired) {
+		return
+	}
+	d, err := json.Marshal(desired)
+	if err != nil {
+		return
+	}
+	o, err := json.Marshal(observed)
+	if err != nil {
+		return
+	}
+	if string(d) == initialized || string(o) == initialized || string(d) == string(o) {
+		return
+	}
+	li.conflicts = append(li.conflicts, LateInitConflict{Path: path, LateInitialized: initialized, Desired: string(d), Observed: string(o)})
+}
+
+// Conflicts returns any conflicts detected by DetectConflict.
+func (li *LateInitializer) Conflicts() []LateInitConflict {
+	return li.conflicts
+}
+
+// Persist writes the values tracked by Track to the supplied object's
+// late-initialized annotation. The annotation is only saved if the object is
+// subsequently persisted, e.g. because the LateInitializer IsChanged.
+func (li *LateInitializer) Persist(o metav1.Object) {
+	if len(li.record) == 0 {
+		return
+	}
+	j, err := json.Marshal(li.record)
+	if err != nil {
+		return
+	}
+	meta.AddAnnotations(o, map[string]string{meta.AnnotationKeyLateInitialized: string(j)})
+}
+
+func isNil(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() { //nolint:exhaustive // Only these kinds can be nil.
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+		return rv.IsNil()
+	}
+	return false
+}
+
 // LateInitializeStringPtr implements late initialization for *string.
 func (li *LateInitializer) LateInitializeStringPtr(org *string, from *string) *string {
 	if org != nil || from == nil {
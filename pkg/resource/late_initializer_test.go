@@ -22,6 +22,8 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 )
 
 func TestLateInitializeStringPtr(t *testing.T) {
@@ -268,3 +270,127 @@ func TestLateInitializeTimePtr(t *testing.T) {
 		})
 	}
 }
+
+func TestLateInitializerDetectConflict(t *testing.T) {
+	str := func(s string) *string { return &s }
+	recorded := &metav1.ObjectMeta{Annotations: map[string]string{
+		meta.AnnotationKeyLateInitialized: `{"spec.forProvider.region":"\"us-east-1\""}`,
+	}}
+
+	type args struct {
+		o        metav1.Object
+		path     string
+		desired  any
+		observed any
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []LateInitConflict
+	}{
+		"NotLateInitialized": {
+			reason: "Fields that were never late initialized can't conflict.",
+			args: args{
+				o:        recorded,
+				path:     "spec.forProvider.zone",
+				desired:  str("a"),
+				observed: str("b"),
+			},
+		},
+		"DesiredUnset": {
+			reason: "Unset fields don't conflict, because they'll be late initialized again.",
+			args: args{
+				o:        recorded,
+				path:     "spec.forProvider.region",
+				desired:  (*string)(nil),
+				observed: str("us-west-2"),
+			},
+		},
+		"OnlyDesiredChanged": {
+			reason: "Fields changed only in the spec don't conflict.",
+			args: args{
+				o:        recorded,
+				path:     "spec.forProvider.region",
+				desired:  str("us-west-2"),
+				observed: str("us-east-1"),
+			},
+		},
+		"BothChangedAndAgree": {
+			reason: "Fields changed in the spec and the external system don't conflict if they agree.",
+			args: args{
+				o:        recorded,
+				path:     "spec.forProvider.region",
+				desired:  str("us-west-2"),
+				observed: str("us-west-2"),
+			},
+		},
+		"Conflict": {
+			reason: "Fields changed in both the spec and the external system conflict if they disagree.",
+			args: args{
+				o:        recorded,
+				path:     "spec.forProvider.region",
+				desired:  str("us-west-2"),
+				observed: str("eu-west-1"),
+			},
+			want: []LateInitConflict{{
+				Path:            "spec.forProvider.region",
+				LateInitialized: `"us-east-1"`,
+				Desired:         `"us-west-2"`,
+				Observed:        `"eu-west-1"`,
+			}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			li := NewLateInitializer(WithLateInitRecord(tc.args.o))
+			li.DetectConflict(tc.args.path, tc.args.desired, tc.args.observed)
+			if diff := cmp.Diff(tc.want, li.Conflicts()); diff != "" {
+				t.Errorf("\n%s\nli.DetectConflict(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestLateInitializerPersist(t *testing.T) {
+	type args struct {
+		o     *metav1.ObjectMeta
+		track map[string]any
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   *metav1.ObjectMeta
+	}{
+		"NothingTracked": {
+			reason: "Nothing should be persisted if no fields were late initialized.",
+			args: args{
+				o: &metav1.ObjectMeta{},
+			},
+			want: &metav1.ObjectMeta{},
+		},
+		"MergeWithRecord": {
+			reason: "Tracked fields should be persisted along with those previously recorded.",
+			args: args{
+				o: &metav1.ObjectMeta{Annotations: map[string]string{
+					meta.AnnotationKeyLateInitialized: `{"spec.forProvider.region":"\"us-east-1\""}`,
+				}},
+				track: map[string]any{"spec.forProvider.size": 3},
+			},
+			want: &metav1.ObjectMeta{Annotations: map[string]string{
+				meta.AnnotationKeyLateInitialized: `{"spec.forProvider.region":"\"us-east-1\"","spec.forProvider.size":"3"}`,
+			}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			li := NewLateInitializer(WithLateInitRecord(tc.args.o))
+			for p, v := range tc.args.track {
+				li.Track(p, v)
+			}
+			li.Persist(tc.args.o)
+			if diff := cmp.Diff(tc.want, tc.args.o); diff != "" {
+				t.Errorf("\n%s\nli.Persist(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DefaultListPageSize is the default number of objects listed per page by
+// helpers that list objects a page at a time.
+const DefaultListPageSize int64 = 500
+
+// A PageFn is called with each page of objects listed by ListPages. It returns
+// false to stop listing.
+type PageFn func() bool
+
+// ListPages lists objects into the supplied list a page of pageSize objects at
+// a time, calling fn after each page is read into the list. The list is reused
+// for each page, so fn must not retain its items. Listing stops when there are
+// no more pages, or when fn returns false. A pageSize of zero or less lists all
+// objects in a single page.
+//
+// Note that readers backed by an informer cache return all matching objects
+// in a single page.
+func ListPages(ctx context.Context, c client.Reader, l client.ObjectList, pageSize int64, fn PageFn, o ...client.ListOption) error {
+	opts := make([]client.ListOption, 0, len(o)+2)
+	opts = append(opts, o...)
+	if pageSize > 0 {
+		opts = append(opts, client.Limit(pageSize))
+	}
+
+	cont := ""
+	for {
+		if err := c.List(ctx, l, append(opts, client.Continue(cont))...); err != nil {
+			return err
+		}
+		if !fn() {
+			return nil
+		}
+		if cont = l.GetContinue(); cont == "" {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestListPages(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	// pages returns a MockListFn that serves the supplied number of pages of
+	// Secrets, each named after the page it was listed in.
+	pages := func(n int) test.MockListFn {
+		return func(_ context.Context, obj client.ObjectList, opts ...client.ListOption) error {
+			lo := &client.ListOptions{}
+			lo.ApplyOptions(opts)
+			if lo.Limit != 2 {
+				return errors.Errorf("want limit 2, got %d", lo.Limit)
+			}
+			page := 0
+			if lo.Continue != "" {
+				page, _ = strconv.Atoi(lo.Continue)
+			}
+			l := obj.(*corev1.SecretList)
+			l.Items = []corev1.Secret{{}}
+			l.Items[0].SetName(strconv.Itoa(page))
+			l.Continue = ""
+			if page+1 < n {
+				l.Continue = strconv.Itoa(page + 1)
+			}
+			return nil
+		}
+	}
+
+	type args struct {
+		c    client.Reader
+		stop int
+	}
+	type want struct {
+		names []string
+		err   error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ListError": {
+			reason: "Errors listing a page should be returned.",
+			args: args{
+				c:    &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				stop: -1,
+			},
+			want: want{
+				err: errBoom,
+			},
+		},
+		"AllPages": {
+			reason: "Every page should be listed until there are no more.",
+			args: args{
+				c:    &test.MockClient{MockList: pages(3)},
+				stop: -1,
+			},
+			want: want{
+				names: []string{"0", "1", "2"},
+			},
+		},
+		"StopEarly": {
+			reason: "Listing should stop when the PageFn returns false.",
+			args: args{
+				c:    &test.MockClient{MockList: pages(3)},
+				stop: 1,
+			},
+			want: want{
+				names: []string{"0", "1"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			l := &corev1.SecretList{}
+			var names []string
+			fn := func() bool {
+				for _, s := range l.Items {
+					names = append(names, s.GetName())
+				}
+				return len(names)-1 != tc.args.stop
+			}
+			err := ListPages(context.Background(), tc.args.c, l, 2, fn)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nListPages(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.names, names); diff != "" {
+				t.Errorf("\n%s\nListPages(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -17,12 +17,14 @@ limitations under the License.
 package resource
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 )
 
@@ -184,6 +186,27 @@ func DesiredStateChanged() predicate.Predicate {
 	)
 }
 
+// BecameReady accepts update events for objects whose Ready condition became
+// true. Use it with EnqueueRequestsForReferencedObject to requeue the objects
+// that reference a managed resource as soon as it becomes ready, so that
+// references that could not be resolved until then converge without waiting
+// for the next poll.
+func BecameReady() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc:  func(event.CreateEvent) bool { return false },
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return !isReady(e.ObjectOld) && isReady(e.ObjectNew)
+		},
+	}
+}
+
+func isReady(o runtime.Object) bool {
+	c, ok := o.(Conditioned)
+	return ok && c.GetCondition(xpv1.TypeReady).Status == corev1.ConditionTrue
+}
+
 // AnnotationChangedPredicate implements a default update predicate function on
 // annotation change by ignoring the given annotation keys, if any.
 //
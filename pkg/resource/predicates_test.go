@@ -425,3 +425,57 @@ func TestDesiredStateChanged(t *testing.T) {
 		})
 	}
 }
+
+func TestBecameReady(t *testing.T) {
+	ready := &fake.Managed{}
+	ready.SetConditions(runtimev1.Available())
+	unready := &fake.Managed{}
+	unready.SetConditions(runtimev1.Unavailable())
+
+	cases := map[string]struct {
+		reason string
+		old    client.Object
+		new    client.Object
+		want   bool
+	}{
+		"BecameReady": {
+			reason: "Objects whose Ready condition became true should be accepted.",
+			old:    unready,
+			new:    ready,
+			want:   true,
+		},
+		"BecameReadyFromNoCondition": {
+			reason: "Objects that had no Ready condition and became ready should be accepted.",
+			old:    &fake.Managed{},
+			new:    ready,
+			want:   true,
+		},
+		"StillReady": {
+			reason: "Objects that were already ready should not be accepted.",
+			old:    ready,
+			new:    ready,
+			want:   false,
+		},
+		"BecameUnready": {
+			reason: "Objects that became unready should not be accepted.",
+			old:    ready,
+			new:    unready,
+			want:   false,
+		},
+		"NotConditioned": {
+			reason: "Objects without conditions should not be accepted.",
+			old:    &fake.Object{},
+			new:    &fake.Object{},
+			want:   false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := BecameReady().Update(event.UpdateEvent{ObjectOld: tc.old, ObjectNew: tc.new})
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nBecameReady().Update(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -18,6 +18,8 @@ package resource
 
 import (
 	"context"
+	"crypto/sha256"
+	"fmt"
 	"os"
 
 	"github.com/spf13/afero"
@@ -40,6 +42,8 @@ const (
 	errNoHandlerForSourceFmt = "no extraction handler registered for source: %s"
 	errMissingPCRef          = "managed resource does not reference a ProviderConfig"
 	errApplyPCU              = "cannot apply ProviderConfigUsage"
+	errGetDefaultNamespace   = "cannot get namespace to determine its default ProviderConfig"
+	errGetDefaultConfigMap   = "cannot get ConfigMap to determine the namespace's default ProviderConfig"
 )
 
 type errMissingRef struct{ error }
@@ -136,23 +140,47 @@ func NewProviderConfigUsageTracker(c client.Client, of ProviderConfigUsage) *Pro
 // references by creating or updating a ProviderConfigUsage. Track should be
 // called _before_ attempting to use the ProviderConfig. This ensures the
 // managed resource's usage is updated if the managed resource is updated to
-// reference a misconfigured ProviderConfig.
+// reference a misconfigured ProviderConfig. If the managed resource is a
+// SecondaryProviderConfigReferencer a ProviderConfigUsage is also created for
+// each secondary ProviderConfig it references.
 func (u *ProviderConfigUsageTracker) Track(ctx context.Context, mg Managed) error {
-	pcu := u.of.DeepCopyObject().(ProviderConfigUsage)
-	gvk := mg.GetObjectKind().GroupVersionKind()
 	ref := mg.GetProviderConfigReference()
 	if ref == nil {
 		return errMissingRef{errors.New(errMissingPCRef)}
 	}
 
-	pcu.SetName(string(mg.GetUID()))
-	pcu.SetLabels(map[string]string{xpv1.LabelKeyProviderName: ref.Name})
+	if err := u.track(ctx, mg, string(mg.GetUID()), ref.Name); err != nil {
+		return err
+	}
+
+	s, ok := mg.(SecondaryProviderConfigReferencer)
+	if !ok {
+		return nil
+	}
+	for _, sr := range s.GetSecondaryProviderConfigReferences() {
+		if sr.Name == ref.Name {
+			continue
+		}
+		if err := u.track(ctx, mg, SecondaryProviderConfigUsageName(mg.GetUID(), sr.Name), sr.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (u *ProviderConfigUsageTracker) track(ctx context.Context, mg Managed, name, pc string) error {
+	pcu := u.of.DeepCopyObject().(ProviderConfigUsage)
+	gvk := mg.GetObjectKind().GroupVersionKind()
+
+	pcu.SetName(name)
+	pcu.SetLabels(map[string]string{xpv1.LabelKeyProviderName: pc})
 	pcu.SetOwnerReferences([]metav1.OwnerReference{meta.AsController(meta.TypedReferenceTo(mg, gvk))})
-	pcu.SetProviderConfigReference(xpv1.Reference{Name: ref.Name})
+	pcu.SetProviderConfigReference(xpv1.Reference{Name: pc})
 	pcu.SetResourceReference(xpv1.TypedReference{
 		APIVersion: gvk.GroupVersion().String(),
 		Kind:       gvk.Kind,
 		Name:       mg.GetName(),
+		Namespace:  mg.GetNamespace(),
 	})
 
 	err := u.c.Apply(ctx, pcu,
@@ -163,3 +191,120 @@ func (u *ProviderConfigUsageTracker) Track(ctx context.Context, mg Managed) erro
 	)
 	return errors.Wrap(Ignore(IsNotAllowed, err), errApplyPCU)
 }
+
+// SecondaryProviderConfigUsageName returns the name of the ProviderConfigUsage
+// that tracks the supplied managed resource's use of the supplied secondary
+// ProviderConfig.
+func SecondaryProviderConfigUsageName(mg types.UID, pc string) string {
+	h := sha256.Sum256([]byte(pc))
+	return fmt.Sprintf("%s-%x", mg, h[:4])
+}
+
+// A ProviderConfigSource indicates how the ProviderConfig used by a managed
+// resource was determined.
+type ProviderConfigSource string
+
+// ProviderConfig sources.
+const (
+	// ProviderConfigSourceResource indicates that the managed resource
+	// referenced its ProviderConfig.
+	ProviderConfigSourceResource ProviderConfigSource = "Resource"
+
+	// ProviderConfigSourceNamespaceDefault indicates that the ProviderConfig
+	// is the default of the managed resource's namespace.
+	ProviderConfigSourceNamespaceDefault ProviderConfigSource = "NamespaceDefault"
+
+	// ProviderConfigSourceClusterDefault indicates that the ProviderConfig is
+	// the cluster's default.
+	ProviderConfigSourceClusterDefault ProviderConfigSource = "ClusterDefault"
+)
+
+// DefaultProviderConfigName is the name of the cluster's default
+// ProviderConfig.
+const DefaultProviderConfigName = "default"
+
+// DefaultProviderConfigMapKey is the key of the data of a namespace's default
+// ProviderConfig ConfigMap that specifies the name of its default
+// ProviderConfig.
+const DefaultProviderConfigMapKey = "providerConfig"
+
+// A ProviderConfigResolverOption configures a DefaultProviderConfigResolver.
+type ProviderConfigResolverOption func(r *DefaultProviderConfigResolver)
+
+// WithNamespaceDefaultConfigMap configures the resolver to read a namespace's
+// default ProviderConfig from the DefaultProviderConfigMapKey of the ConfigMap
+// with the supplied name in that namespace, if the namespace isn't annotated
+// with a default.
+func WithNamespaceDefaultConfigMap(name string) ProviderConfigResolverOption {
+	return func(r *DefaultProviderConfigResolver) {
+		r.configMap = name
+	}
+}
+
+// WithClusterDefaultProviderConfig configures the name of the cluster's
+// default ProviderConfig.
+func WithClusterDefaultProviderConfig(name string) ProviderConfigResolverOption {
+	return func(r *DefaultProviderConfigResolver) {
+		r.clusterDefault = name
+	}
+}
+
+// A DefaultProviderConfigResolver determines which ProviderConfig a managed
+// resource should use. It uses, in order of precedence:
+//
+//  1. The ProviderConfig the managed resource references.
+//  2. The default ProviderConfig of the managed resource's namespace, read
+//     from the namespace's AnnotationKeyDefaultProviderConfig annotation or
+//     optionally a ConfigMap. Cluster scoped managed resources use the
+//     namespace of the claim they were created for, if any.
+//  3. The cluster's default ProviderConfig.
+type DefaultProviderConfigResolver struct {
+	client         client.Reader
+	configMap      string
+	clusterDefault string
+}
+
+// NewDefaultProviderConfigResolver returns a DefaultProviderConfigResolver.
+func NewDefaultProviderConfigResolver(c client.Reader, o ...ProviderConfigResolverOption) *DefaultProviderConfigResolver {
+	r := &DefaultProviderConfigResolver{client: c, clusterDefault: DefaultProviderConfigName}
+	for _, fn := range o {
+		fn(r)
+	}
+	return r
+}
+
+// Resolve the ProviderConfig the supplied managed resource should use.
+func (r *DefaultProviderConfigResolver) Resolve(ctx context.Context, mg Managed) (xpv1.Reference, ProviderConfigSource, error) {
+	if ref := mg.GetProviderConfigReference(); ref != nil {
+		return *ref, ProviderConfigSourceResource, nil
+	}
+
+	ns := mg.GetNamespace()
+	if ns == "" {
+		ns = mg.GetLabels()[meta.LabelKeyClaimNamespace]
+	}
+	if ns == "" {
+		return xpv1.Reference{Name: r.clusterDefault}, ProviderConfigSourceClusterDefault, nil
+	}
+
+	n := &corev1.Namespace{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: ns}, n); err != nil {
+		return xpv1.Reference{}, "", errors.Wrap(err, errGetDefaultNamespace)
+	}
+	if name := n.GetAnnotations()[meta.AnnotationKeyDefaultProviderConfig]; name != "" {
+		return xpv1.Reference{Name: name}, ProviderConfigSourceNamespaceDefault, nil
+	}
+
+	if r.configMap != "" {
+		cm := &corev1.ConfigMap{}
+		err := r.client.Get(ctx, types.NamespacedName{Namespace: ns, Name: r.configMap}, cm)
+		if IgnoreNotFound(err) != nil {
+			return xpv1.Reference{}, "", errors.Wrap(err, errGetDefaultConfigMap)
+		}
+		if name := cm.Data[DefaultProviderConfigMapKey]; name != "" {
+			return xpv1.Reference{Name: name}, ProviderConfigSourceNamespaceDefault, nil
+		}
+	}
+
+	return xpv1.Reference{Name: r.clusterDefault}, ProviderConfigSourceClusterDefault, nil
+}
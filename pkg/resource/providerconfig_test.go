@@ -23,10 +23,15 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/spf13/afero"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 )
@@ -233,9 +238,15 @@ func TestExtractSecret(t *testing.T) {
 	}
 }
 
+type secondaryManaged struct {
+	fake.Managed
+	fake.SecondaryProviderConfigReferencer
+}
+
 func TestTrack(t *testing.T) {
 	errBoom := errors.New("boom")
 	name := "provisional"
+	uid := types.UID("so-unique")
 
 	type fields struct {
 		c  Applicator
@@ -313,6 +324,59 @@ func TestTrack(t *testing.T) {
 			},
 			want: errors.Wrap(errBoom, errApplyPCU),
 		},
+		"SecondaryApplyError": {
+			reason: "Errors applying a secondary ProviderConfigUsage should be returned",
+			fields: fields{
+				c: ApplyFn(func(c context.Context, r client.Object, ao ...ApplyOption) error {
+					if r.(ProviderConfigUsage).GetProviderConfigReference().Name == "secondary" {
+						return errBoom
+					}
+					return nil
+				}),
+				of: &fake.ProviderConfigUsage{},
+			},
+			args: args{
+				mg: &secondaryManaged{
+					Managed: fake.Managed{
+						ObjectMeta:               metav1.ObjectMeta{UID: uid},
+						ProviderConfigReferencer: fake.ProviderConfigReferencer{Ref: &xpv1.Reference{Name: name}},
+					},
+					SecondaryProviderConfigReferencer: fake.SecondaryProviderConfigReferencer{
+						Refs: []xpv1.Reference{{Name: "secondary"}},
+					},
+				},
+			},
+			want: errors.Wrap(errBoom, errApplyPCU),
+		},
+		"SecondaryProviderConfigs": {
+			reason: "A ProviderConfigUsage should be applied for each distinct secondary ProviderConfig",
+			fields: fields{
+				c: ApplyFn(func(c context.Context, r client.Object, ao ...ApplyOption) error {
+					pc := r.(ProviderConfigUsage).GetProviderConfigReference().Name
+					want := map[string]string{
+						name:        string(uid),
+						"secondary": SecondaryProviderConfigUsageName(uid, "secondary"),
+					}[pc]
+					if r.GetName() != want || r.GetLabels()[xpv1.LabelKeyProviderName] != pc {
+						return errors.Errorf("unexpected usage %q of ProviderConfig %q", r.GetName(), pc)
+					}
+					return nil
+				}),
+				of: &fake.ProviderConfigUsage{},
+			},
+			args: args{
+				mg: &secondaryManaged{
+					Managed: fake.Managed{
+						ObjectMeta:               metav1.ObjectMeta{UID: uid},
+						ProviderConfigReferencer: fake.ProviderConfigReferencer{Ref: &xpv1.Reference{Name: name}},
+					},
+					SecondaryProviderConfigReferencer: fake.SecondaryProviderConfigReferencer{
+						Refs: []xpv1.Reference{{Name: "secondary"}, {Name: name}},
+					},
+				},
+			},
+			want: nil,
+		},
 	}
 
 	for name, tc := range cases {
@@ -325,3 +389,108 @@ func TestTrack(t *testing.T) {
 		})
 	}
 }
+
+func TestDefaultProviderConfigResolver(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		c  client.Reader
+		o  []ProviderConfigResolverOption
+		mg Managed
+	}
+	type want struct {
+		ref xpv1.Reference
+		src ProviderConfigSource
+		err error
+	}
+
+	namespace := func(annotations map[string]string) test.MockGetFn {
+		return func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			switch o := obj.(type) {
+			case *corev1.Namespace:
+				if key.Name != "cool-ns" {
+					t.Errorf("Get(...): unexpected namespace %q", key.Name)
+				}
+				o.SetAnnotations(annotations)
+			case *corev1.ConfigMap:
+				if key.Namespace != "cool-ns" || key.Name != "defaults" {
+					return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+				}
+				o.Data = map[string]string{DefaultProviderConfigMapKey: "from-configmap"}
+			}
+			return nil
+		}
+	}
+	claimed := &fake.Managed{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{meta.LabelKeyClaimNamespace: "cool-ns"}}}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Referenced": {
+			reason: "A managed resource's own ProviderConfig reference should take precedence.",
+			args: args{
+				mg: &fake.Managed{ProviderConfigReferencer: fake.ProviderConfigReferencer{Ref: &xpv1.Reference{Name: "mine"}}},
+			},
+			want: want{ref: xpv1.Reference{Name: "mine"}, src: ProviderConfigSourceResource},
+		},
+		"NoNamespace": {
+			reason: "A managed resource without a namespace should use the cluster default.",
+			args: args{
+				mg: &fake.Managed{},
+			},
+			want: want{ref: xpv1.Reference{Name: DefaultProviderConfigName}, src: ProviderConfigSourceClusterDefault},
+		},
+		"GetNamespaceError": {
+			reason: "Errors getting the namespace should be returned.",
+			args: args{
+				c:  &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				mg: claimed,
+			},
+			want: want{err: errors.Wrap(errBoom, errGetDefaultNamespace)},
+		},
+		"NamespaceAnnotation": {
+			reason: "The namespace's annotated default should take precedence over the cluster default.",
+			args: args{
+				c:  &test.MockClient{MockGet: namespace(map[string]string{meta.AnnotationKeyDefaultProviderConfig: "team"})},
+				mg: claimed,
+			},
+			want: want{ref: xpv1.Reference{Name: "team"}, src: ProviderConfigSourceNamespaceDefault},
+		},
+		"NamespaceConfigMap": {
+			reason: "The namespace's ConfigMap default should be used if the namespace isn't annotated.",
+			args: args{
+				c:  &test.MockClient{MockGet: namespace(nil)},
+				o:  []ProviderConfigResolverOption{WithNamespaceDefaultConfigMap("defaults")},
+				mg: claimed,
+			},
+			want: want{ref: xpv1.Reference{Name: "from-configmap"}, src: ProviderConfigSourceNamespaceDefault},
+		},
+		"ClusterDefault": {
+			reason: "The configured cluster default should be used if the namespace has no default.",
+			args: args{
+				c:  &test.MockClient{MockGet: namespace(nil)},
+				o:  []ProviderConfigResolverOption{WithNamespaceDefaultConfigMap("missing"), WithClusterDefaultProviderConfig("cluster")},
+				mg: claimed,
+			},
+			want: want{ref: xpv1.Reference{Name: "cluster"}, src: ProviderConfigSourceClusterDefault},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewDefaultProviderConfigResolver(tc.args.c, tc.args.o...)
+			ref, src, err := r.Resolve(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Resolve(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ref, ref); diff != "" {
+				t.Errorf("\n%s\nr.Resolve(...): -want ref, +got ref:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.src, src); diff != "" {
+				t.Errorf("\n%s\nr.Resolve(...): -want source, +got source:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
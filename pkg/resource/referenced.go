@@ -0,0 +1,187 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+// A ReferenceExtractor returns the objects the supplied object references.
+type ReferenceExtractor func(o client.Object) []types.NamespacedName
+
+// ProviderConfigReferences extracts the ProviderConfig referenced by a managed
+// resource, or by any other ProviderConfigReferencer.
+func ProviderConfigReferences() ReferenceExtractor {
+	return func(o client.Object) []types.NamespacedName {
+		switch pcr := o.(type) {
+		case ProviderConfigReferencer:
+			if ref := pcr.GetProviderConfigReference(); ref != nil && ref.Name != "" {
+				return []types.NamespacedName{{Name: ref.Name}}
+			}
+		case RequiredProviderConfigReferencer:
+			if ref := pcr.GetProviderConfigReference(); ref.Name != "" {
+				return []types.NamespacedName{{Name: ref.Name}}
+			}
+		}
+		return nil
+	}
+}
+
+// FieldPathReferences extracts the objects referenced at the supplied field
+// paths, e.g. spec.forProvider.vpcIdRef. Each referencing field must have a
+// name field, and may have a namespace field. Fields that are not set are
+// ignored.
+func FieldPathReferences(paths ...string) ReferenceExtractor {
+	return func(o client.Object) []types.NamespacedName {
+		p, err := fieldpath.PaveObject(o)
+		if err != nil {
+			return nil
+		}
+		refs := make([]types.NamespacedName, 0, len(paths))
+		for _, path := range paths {
+			name, _ := p.GetString(path + ".name")
+			if name == "" {
+				continue
+			}
+			ns, _ := p.GetString(path + ".namespace")
+			refs = append(refs, types.NamespacedName{Namespace: ns, Name: name})
+		}
+		return refs
+	}
+}
+
+// ReferenceIndexKey returns the key of a field index of objects by the objects
+// of the supplied kind that they reference. Register it using
+// AddReferenceIndex.
+func ReferenceIndexKey(to schema.GroupKind) string {
+	return "referencesTo." + to.String()
+}
+
+// IndexReferences returns an IndexerFunc that indexes objects by the objects
+// that the supplied ReferenceExtractor returns.
+func IndexReferences(fn ReferenceExtractor) client.IndexerFunc {
+	return func(o client.Object) []string {
+		refs := fn(o)
+		keys := make([]string, 0, len(refs))
+		for _, nn := range refs {
+			keys = append(keys, nn.String())
+		}
+		return keys
+	}
+}
+
+// AddReferenceIndex adds ReferenceIndexKey for the supplied referenced kind to
+// the supplied indexer for the supplied kind of referencing object. It must be
+// added before the indexer's cache is started.
+func AddReferenceIndex(ctx context.Context, i client.FieldIndexer, of client.Object, to schema.GroupKind, fn ReferenceExtractor) error {
+	return i.IndexField(ctx, of, ReferenceIndexKey(to), IndexReferences(fn))
+}
+
+// A ReferencedObjectOption configures EnqueueRequestsForReferencedObject.
+type ReferencedObjectOption func(e *EnqueueRequestsForReferencedObject)
+
+// WithReferencedObjectLogger configures the logger used to report failures to
+// list the objects that reference a changed object.
+func WithReferencedObjectLogger(l logging.Logger) ReferencedObjectOption {
+	return func(e *EnqueueRequestsForReferencedObject) {
+		e.log = l
+	}
+}
+
+// EnqueueRequestsForReferencedObject enqueues a reconcile.Request for each
+// object that references a created, updated, or deleted object, for example
+// each managed resource that references a changed ProviderConfig, VPC, or
+// ConfigMap. Referencing objects are found using the index added by
+// AddReferenceIndex. Filter its events using the BecameReady predicate to
+// enqueue referencing objects only when the object they reference becomes
+// ready.
+type EnqueueRequestsForReferencedObject struct {
+	client client.Reader
+	list   client.ObjectList
+	key    string
+
+	log logging.Logger
+}
+
+// NewEnqueueRequestsForReferencedObject returns a handler that watches objects
+// of the supplied referenced kind, and enqueues requests for the objects in
+// the supplied kind of list that reference them. The supplied client must be
+// backed by a cache with the index added by AddReferenceIndex for the same
+// referenced kind.
+func NewEnqueueRequestsForReferencedObject(c client.Reader, l client.ObjectList, to schema.GroupKind, o ...ReferencedObjectOption) *EnqueueRequestsForReferencedObject {
+	e := &EnqueueRequestsForReferencedObject{
+		client: c,
+		list:   l,
+		key:    ReferenceIndexKey(to),
+		log:    logging.NewNopLogger(),
+	}
+	for _, fn := range o {
+		fn(e)
+	}
+	return e
+}
+
+// Create enqueues requests for objects that reference the created object.
+func (e *EnqueueRequestsForReferencedObject) Create(evt event.CreateEvent, q workqueue.RateLimitingInterface) {
+	e.enqueue(context.TODO(), evt.Object, q)
+}
+
+// Update enqueues requests for objects that reference the updated object.
+func (e *EnqueueRequestsForReferencedObject) Update(evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	e.enqueue(context.TODO(), evt.ObjectNew, q)
+}
+
+// Delete enqueues requests for objects that referenced the deleted object.
+func (e *EnqueueRequestsForReferencedObject) Delete(evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	e.enqueue(context.TODO(), evt.Object, q)
+}
+
+// Generic enqueues requests for objects that reference the supplied object.
+func (e *EnqueueRequestsForReferencedObject) Generic(evt event.GenericEvent, q workqueue.RateLimitingInterface) {
+	e.enqueue(context.TODO(), evt.Object, q)
+}
+
+func (e *EnqueueRequestsForReferencedObject) enqueue(ctx context.Context, o client.Object, q adder) {
+	if o == nil {
+		return
+	}
+	nn := types.NamespacedName{Namespace: o.GetNamespace(), Name: o.GetName()}
+
+	l := e.list.DeepCopyObject().(client.ObjectList)
+	if err := e.client.List(ctx, l, client.MatchingFields{e.key: nn.String()}); err != nil {
+		e.log.Info("Cannot list objects that reference a changed object", "error", err, "object", nn)
+		return
+	}
+	_ = kmeta.EachListItem(l, func(item runtime.Object) error {
+		if ro, ok := item.(client.Object); ok {
+			q.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: ro.GetNamespace(), Name: ro.GetName()}})
+		}
+		return nil
+	})
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var _ handler.EventHandler = &EnqueueRequestsForReferencedObject{}
+
+func TestIndexReferences(t *testing.T) {
+	type args struct {
+		fn ReferenceExtractor
+		o  client.Object
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []string
+	}{
+		"NoProviderConfig": {
+			reason: "Objects that reference no ProviderConfig should not be indexed.",
+			args: args{
+				fn: ProviderConfigReferences(),
+				o:  &fake.Managed{},
+			},
+			want: []string{},
+		},
+		"ProviderConfig": {
+			reason: "Objects should be indexed by the ProviderConfig they reference.",
+			args: args{
+				fn: ProviderConfigReferences(),
+				o: &fake.Managed{ProviderConfigReferencer: fake.ProviderConfigReferencer{
+					Ref: &xpv1.Reference{Name: "cool"},
+				}},
+			},
+			want: []string{"/cool"},
+		},
+		"FieldPaths": {
+			reason: "Objects should be indexed by the objects referenced at the supplied field paths, ignoring unset fields.",
+			args: args{
+				fn: FieldPathReferences("spec.forProvider.vpcIdRef", "spec.forProvider.configMapRef", "spec.forProvider.subnetIdRef"),
+				o: &unstructured.Unstructured{Object: map[string]any{
+					"spec": map[string]any{
+						"forProvider": map[string]any{
+							"vpcIdRef":     map[string]any{"name": "cool-vpc"},
+							"configMapRef": map[string]any{"namespace": "ns", "name": "cool-cm"},
+						},
+					},
+				}},
+			},
+			want: []string{"/cool-vpc", "ns/cool-cm"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IndexReferences(tc.args.fn)(tc.args.o)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nIndexReferences(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestEnqueueRequestsForReferencedObject(t *testing.T) {
+	errBoom := errors.New("boom")
+	to := schema.GroupKind{Group: "example.org", Kind: "ProviderConfig"}
+
+	type args struct {
+		list test.MockListFn
+		obj  client.Object
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []reconcile.Request
+	}{
+		"ListError": {
+			reason: "Nothing should be enqueued if we can't list referencing objects.",
+			args: args{
+				list: test.NewMockListFn(errBoom),
+				obj:  &fake.ProviderConfig{ObjectMeta: metav1.ObjectMeta{Name: "cool"}},
+			},
+		},
+		"Referenced": {
+			reason: "Each object that references the changed object should be enqueued.",
+			args: args{
+				list: func(_ context.Context, obj client.ObjectList, opts ...client.ListOption) error {
+					lo := &client.ListOptions{}
+					lo.ApplyOptions(opts)
+					if got := lo.FieldSelector.String(); got != ReferenceIndexKey(to)+"=/cool" {
+						t.Errorf("List(...): unexpected field selector %q", got)
+					}
+					obj.(*fake.ManagedList[Managed]).Items = []*fake.Managed{
+						{ObjectMeta: metav1.ObjectMeta{Name: "mr-a"}},
+						{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "mr-b"}},
+					}
+					return nil
+				},
+				obj: &fake.ProviderConfig{ObjectMeta: metav1.ObjectMeta{Name: "cool"}},
+			},
+			want: []reconcile.Request{
+				{NamespacedName: types.NamespacedName{Name: "mr-a"}},
+				{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "mr-b"}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := NewEnqueueRequestsForReferencedObject(&test.MockClient{MockList: tc.args.list}, &fake.ManagedList[Managed]{}, to)
+
+			q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+			defer q.ShutDown()
+			e.Update(event.UpdateEvent{ObjectOld: tc.args.obj, ObjectNew: tc.args.obj}, q)
+
+			var got []reconcile.Request
+			for q.Len() > 0 {
+				item, _ := q.Get()
+				got = append(got, item.(reconcile.Request))
+				q.Done(item)
+			}
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -44,6 +44,14 @@ const (
 	ExternalResourceTagKeyKind     = "crossplane-kind"
 	ExternalResourceTagKeyName     = "crossplane-name"
 	ExternalResourceTagKeyProvider = "crossplane-providerconfig"
+
+	// ExternalResourceTagKeyNamespace is only set for namespaced managed
+	// resources.
+	ExternalResourceTagKeyNamespace = "crossplane-namespace"
+
+	// ExternalResourceTagKeyCluster is only set when a provider reconciles
+	// managed resources in several clusters.
+	ExternalResourceTagKeyCluster = "crossplane-cluster"
 )
 
 // A ManagedKind contains the type metadata for a kind of managed resource.
@@ -125,13 +133,18 @@ func LocalConnectionSecretFor(o LocalConnectionSecretOwner, kind schema.GroupVer
 }
 
 // ConnectionSecretFor creates a connection for the supplied
-// ConnectionSecretOwner, assumed to be of the supplied kind. The secret is
-// written to 'default' namespace if the ConnectionSecretOwner does not specify
-// a namespace.
+// ConnectionSecretOwner, assumed to be of the supplied kind. The secret of a
+// namespaced ConnectionSecretOwner is always written to its own namespace,
+// because an owner reference may not cross namespaces. The secret of a cluster
+// scoped ConnectionSecretOwner is written to the namespace of its reference.
 func ConnectionSecretFor(o ConnectionSecretOwner, kind schema.GroupVersionKind) *corev1.Secret {
+	ns := o.GetWriteConnectionSecretToReference().Namespace
+	if o.GetNamespace() != "" {
+		ns = o.GetNamespace()
+	}
 	return &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace:       o.GetWriteConnectionSecretToReference().Namespace,
+			Namespace:       ns,
 			Name:            o.GetWriteConnectionSecretToReference().Name,
 			OwnerReferences: []metav1.OwnerReference{meta.AsController(meta.TypedReferenceTo(o, kind))},
 		},
@@ -194,12 +207,7 @@ func Ignore(is ErrorIs, err error) error {
 // by returning nil. Errors that do not satisfy any of the supplied functions
 // are returned unmodified.
 func IgnoreAny(err error, is ...ErrorIs) error {
-	for _, f := range is {
-		if f(err) {
-			return nil
-		}
-	}
-	return err
+	return Ignore(IsAnyOf(is...), err)
 }
 
 // IgnoreNotFound returns the supplied error, or nil if the error indicates a
@@ -208,6 +216,52 @@ func IgnoreNotFound(err error) error {
 	return Ignore(kerrors.IsNotFound, err)
 }
 
+// IgnoreAlreadyExists returns the supplied error, or nil if the error
+// indicates a Kubernetes resource already exists.
+func IgnoreAlreadyExists(err error) error {
+	return Ignore(kerrors.IsAlreadyExists, err)
+}
+
+// IgnoreConflict returns the supplied error, or nil if the error indicates a
+// Kubernetes resource was modified since it was read.
+func IgnoreConflict(err error) error {
+	return Ignore(kerrors.IsConflict, err)
+}
+
+// IsAnyOf returns an ErrorIs function that returns true if an error satisfies
+// any of the supplied ErrorIs functions.
+func IsAnyOf(is ...ErrorIs) ErrorIs {
+	return func(err error) bool {
+		for _, f := range is {
+			if f(err) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// IsAllOf returns an ErrorIs function that returns true if an error satisfies
+// all of the supplied ErrorIs functions.
+func IsAllOf(is ...ErrorIs) ErrorIs {
+	return func(err error) bool {
+		for _, f := range is {
+			if !f(err) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// IsNot returns an ErrorIs function that returns true if an error does not
+// satisfy the supplied ErrorIs function.
+func IsNot(is ErrorIs) ErrorIs {
+	return func(err error) bool {
+		return !is(err)
+	}
+}
+
 // IsAPIError returns true if the given error's type is of Kubernetes API error.
 func IsAPIError(err error) bool {
 	_, ok := err.(kerrors.APIStatus) //nolint: errorlint // we assert against the kerrors.APIStatus Interface which does not implement the error interface
@@ -399,13 +453,17 @@ func Apply(ctx context.Context, c client.Client, o client.Object, ao ...ApplyOpt
 }
 
 // GetExternalTags returns the identifying tags to be used to tag the external
-// resource in provider API.
+// resource in provider API. See ExternalTagger for a pluggable alternative.
 func GetExternalTags(mg Managed) map[string]string {
 	tags := map[string]string{
 		ExternalResourceTagKeyKind: strings.ToLower(mg.GetObjectKind().GroupVersionKind().GroupKind().String()),
 		ExternalResourceTagKeyName: mg.GetName(),
 	}
 
+	if ns := mg.GetNamespace(); ns != "" {
+		tags[ExternalResourceTagKeyNamespace] = ns
+	}
+
 	switch {
 	case mg.GetProviderConfigReference() != nil && mg.GetProviderConfigReference().Name != "":
 		tags[ExternalResourceTagKeyProvider] = mg.GetProviderConfigReference().Name
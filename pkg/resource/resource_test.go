@@ -153,6 +153,63 @@ func TestConnectionSecretFor(t *testing.T) {
 				Data: map[string][]byte{},
 			},
 		},
+		"NamespacedOwner": {
+			args: args{
+				o: &fake.MockConnectionSecretOwner{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: namespace,
+						Name:      name,
+						UID:       uid,
+					},
+					WriterTo: &xpv1.SecretReference{Namespace: "other", Name: secretName},
+				},
+				kind: MockOwnerGVK,
+			},
+			want: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      secretName,
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion:         MockOwnerGVK.GroupVersion().String(),
+						Kind:               MockOwnerGVK.Kind,
+						Name:               name,
+						UID:                uid,
+						Controller:         &controller,
+						BlockOwnerDeletion: &controller,
+					}},
+				},
+				Type: SecretTypeConnection,
+				Data: map[string][]byte{},
+			},
+		},
+		"ClusterScopedOwner": {
+			args: args{
+				o: &fake.MockConnectionSecretOwner{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: name,
+						UID:  uid,
+					},
+					WriterTo: &xpv1.SecretReference{Namespace: "other", Name: secretName},
+				},
+				kind: MockOwnerGVK,
+			},
+			want: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "other",
+					Name:      secretName,
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion:         MockOwnerGVK.GroupVersion().String(),
+						Kind:               MockOwnerGVK.Kind,
+						Name:               name,
+						UID:                uid,
+						Controller:         &controller,
+						BlockOwnerDeletion: &controller,
+					}},
+				},
+				Type: SecretTypeConnection,
+				Data: map[string][]byte{},
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
@@ -639,6 +696,20 @@ func TestGetExternalTags(t *testing.T) {
 				ExternalResourceTagKeyProvider: provName,
 			},
 		},
+		"SuccessfulWithNamespace": {
+			o: &fake.Managed{ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      name,
+			},
+				ProviderConfigReferencer: fake.ProviderConfigReferencer{Ref: &xpv1.Reference{Name: provName}},
+			},
+			want: map[string]string{
+				ExternalResourceTagKeyKind:      strings.ToLower((&fake.Managed{}).GetObjectKind().GroupVersionKind().GroupKind().String()),
+				ExternalResourceTagKeyName:      name,
+				ExternalResourceTagKeyNamespace: namespace,
+				ExternalResourceTagKeyProvider:  provName,
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -673,6 +744,88 @@ func Test_errNotAllowed_NotAllowed(t *testing.T) {
 	}
 }
 
+func TestIgnoreAlreadyExists(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		err  error
+		want error
+	}{
+		"AlreadyExists": {
+			err:  kerrors.NewAlreadyExists(schema.GroupResource{}, "cool"),
+			want: nil,
+		},
+		"Conflict": {
+			err:  kerrors.NewConflict(schema.GroupResource{}, "cool", errBoom),
+			want: kerrors.NewConflict(schema.GroupResource{}, "cool", errBoom),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IgnoreAlreadyExists(tc.err)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("IgnoreAlreadyExists(...): -want error, +got error:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIgnoreConflict(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		err  error
+		want error
+	}{
+		"Conflict": {
+			err:  kerrors.NewConflict(schema.GroupResource{}, "cool", errBoom),
+			want: nil,
+		},
+		"AlreadyExists": {
+			err:  kerrors.NewAlreadyExists(schema.GroupResource{}, "cool"),
+			want: kerrors.NewAlreadyExists(schema.GroupResource{}, "cool"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IgnoreConflict(tc.err)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("IgnoreConflict(...): -want error, +got error:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestErrorIsCombinators(t *testing.T) {
+	yes := func(_ error) bool { return true }
+	no := func(_ error) bool { return false }
+
+	cases := map[string]struct {
+		is   ErrorIs
+		want bool
+	}{
+		"IsAnyOfTrue":  {is: IsAnyOf(no, yes), want: true},
+		"IsAnyOfFalse": {is: IsAnyOf(no, no), want: false},
+		"IsAnyOfNone":  {is: IsAnyOf(), want: false},
+		"IsAllOfTrue":  {is: IsAllOf(yes, yes), want: true},
+		"IsAllOfFalse": {is: IsAllOf(yes, no), want: false},
+		"IsNotTrue":    {is: IsNot(no), want: true},
+		"IsNotFalse":   {is: IsNot(yes), want: false},
+		"Composed":     {is: IsAllOf(IsNot(no), IsAnyOf(no, yes)), want: true},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.is(errors.New("boom"))
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("is(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestIsAPIErrorWrapped(t *testing.T) {
 	testCases := map[string]struct {
 		err  error
@@ -0,0 +1,187 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+// DefaultCredentialsSecretRefPath is the field path at which most
+// ProviderConfigs reference their credentials Secret.
+const DefaultCredentialsSecretRefPath = "spec.credentials.secretRef"
+
+// A RotatedCredentialsOption configures EnqueueRequestsForRotatedCredentials.
+type RotatedCredentialsOption func(e *EnqueueRequestsForRotatedCredentials)
+
+// WithCredentialsSecretRefPath configures the field path at which
+// ProviderConfigs reference their credentials Secret. The referenced object
+// must have name and namespace fields.
+func WithCredentialsSecretRefPath(path string) RotatedCredentialsOption {
+	return func(e *EnqueueRequestsForRotatedCredentials) {
+		e.path = path
+	}
+}
+
+// WithRotatedCredentialCache configures a CredentialCache that is invalidated
+// when credentials are rotated.
+func WithRotatedCredentialCache(c *CredentialCache) RotatedCredentialsOption {
+	return func(e *EnqueueRequestsForRotatedCredentials) {
+		e.cache = c
+	}
+}
+
+// WithCredentialsRotatedFn configures a function that is called with the name
+// of each ProviderConfig whose credentials were rotated, for example to
+// discard cached external clients that were created using the old
+// credentials.
+func WithCredentialsRotatedFn(fn func(providerConfig string)) RotatedCredentialsOption {
+	return func(e *EnqueueRequestsForRotatedCredentials) {
+		e.rotated = append(e.rotated, fn)
+	}
+}
+
+// WithRotatedCredentialsLogger configures the logger used to report failures
+// to determine which managed resources use rotated credentials.
+func WithRotatedCredentialsLogger(l logging.Logger) RotatedCredentialsOption {
+	return func(e *EnqueueRequestsForRotatedCredentials) {
+		e.log = l
+	}
+}
+
+// EnqueueRequestsForRotatedCredentials enqueues a reconcile.Request for each
+// managed resource of a particular kind that uses a ProviderConfig whose
+// credentials Secret changed or was deleted. Managed resources connect to
+// their external system each time they're reconciled, so this ensures rotated
+// credentials take effect promptly rather than after the old credentials stop
+// working. Managed resources using a ProviderConfig are found using its
+// ProviderConfigUsages.
+type EnqueueRequestsForRotatedCredentials struct {
+	client    client.Reader
+	config    schema.GroupVersionKind
+	usageList func() ProviderConfigUsageList
+	managed   schema.GroupVersionKind
+
+	path    string
+	cache   *CredentialCache
+	rotated []func(providerConfig string)
+	log     logging.Logger
+}
+
+// NewEnqueueRequestsForRotatedCredentials returns a handler that watches
+// Secrets, and enqueues requests for managed resources of the supplied kind
+// that use ProviderConfigs of the supplied kind.
+func NewEnqueueRequestsForRotatedCredentials(c client.Reader, oc runtime.ObjectCreater, pc ProviderConfigKinds, mg schema.GroupVersionKind, o ...RotatedCredentialsOption) *EnqueueRequestsForRotatedCredentials {
+	e := &EnqueueRequestsForRotatedCredentials{
+		client: c,
+		config: pc.Config,
+		usageList: func() ProviderConfigUsageList {
+			return MustCreateObject(pc.UsageList, oc).(ProviderConfigUsageList)
+		},
+		managed: mg,
+		path:    DefaultCredentialsSecretRefPath,
+		log:     logging.NewNopLogger(),
+	}
+	for _, fn := range o {
+		fn(e)
+	}
+	return e
+}
+
+// Create does nothing; a new Secret can't rotate credentials that were in use.
+func (e *EnqueueRequestsForRotatedCredentials) Create(_ event.CreateEvent, _ workqueue.RateLimitingInterface) {
+}
+
+// Update enqueues requests if the data of the updated Secret changed.
+func (e *EnqueueRequestsForRotatedCredentials) Update(evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	o, ook := evt.ObjectOld.(*corev1.Secret)
+	n, nok := evt.ObjectNew.(*corev1.Secret)
+	if ook && nok && reflect.DeepEqual(o.Data, n.Data) && reflect.DeepEqual(o.StringData, n.StringData) {
+		return
+	}
+	e.enqueue(context.TODO(), evt.ObjectNew, q)
+}
+
+// Delete enqueues requests for managed resources that used the deleted Secret.
+func (e *EnqueueRequestsForRotatedCredentials) Delete(evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	e.enqueue(context.TODO(), evt.Object, q)
+}
+
+// Generic enqueues requests for managed resources that use the supplied
+// Secret.
+func (e *EnqueueRequestsForRotatedCredentials) Generic(evt event.GenericEvent, q workqueue.RateLimitingInterface) {
+	e.enqueue(context.TODO(), evt.Object, q)
+}
+
+func (e *EnqueueRequestsForRotatedCredentials) enqueue(ctx context.Context, s client.Object, q adder) {
+	nn := types.NamespacedName{Namespace: s.GetNamespace(), Name: s.GetName()}
+	if e.cache != nil {
+		e.cache.InvalidateSecret(nn)
+	}
+
+	pcs := &unstructured.UnstructuredList{}
+	pcs.SetGroupVersionKind(e.config.GroupVersion().WithKind(e.config.Kind + "List"))
+	rotated := func() bool {
+		for _, pc := range pcs.Items {
+			p := fieldpath.Pave(pc.Object)
+			ns, _ := p.GetString(e.path + ".namespace")
+			name, _ := p.GetString(e.path + ".name")
+			if ns == nn.Namespace && name == nn.Name {
+				e.enqueueUsers(ctx, pc.GetName(), q)
+			}
+		}
+		return true
+	}
+	if err := ListPages(ctx, e.client, pcs, DefaultListPageSize, rotated); err != nil {
+		e.log.Info("Cannot list ProviderConfigs to determine whether credentials were rotated", "error", err, "secret", nn)
+	}
+}
+
+func (e *EnqueueRequestsForRotatedCredentials) enqueueUsers(ctx context.Context, pc string, q adder) {
+	for _, fn := range e.rotated {
+		fn(pc)
+	}
+
+	l := e.usageList()
+	add := func() bool {
+		for _, u := range l.GetItems() {
+			ref := u.GetResourceReference()
+			if ref.APIVersion != e.managed.GroupVersion().String() || ref.Kind != e.managed.Kind {
+				continue
+			}
+			q.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}})
+		}
+		return true
+	}
+	if err := ListPages(ctx, e.client, l, DefaultListPageSize, add, client.MatchingLabels{xpv1.LabelKeyProviderName: pc}); err != nil {
+		e.log.Info("Cannot list ProviderConfigUsages to enqueue managed resources using rotated credentials", "error", err, "providerConfig", pc)
+	}
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var _ handler.EventHandler = &EnqueueRequestsForRotatedCredentials{}
+
+type usageList struct { //nolint:musttag // This is a fake implementation to be used in unit tests only.
+	client.ObjectList
+	Items []ProviderConfigUsage
+}
+
+func (p *usageList) GetObjectKind() schema.ObjectKind {
+	return schema.EmptyObjectKind
+}
+
+func (p *usageList) DeepCopyObject() runtime.Object {
+	out := &usageList{}
+	j, err := json.Marshal(p)
+	if err != nil {
+		panic(err)
+	}
+	_ = json.Unmarshal(j, out)
+	return out
+}
+
+func (p *usageList) GetItems() []ProviderConfigUsage {
+	return p.Items
+}
+
+func (p *usageList) GetContinue() string {
+	return ""
+}
+
+func TestEnqueueRequestsForRotatedCredentials(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	mg := fake.GVK(&fake.Managed{})
+	pck := ProviderConfigKinds{Config: fake.GVK(&fake.ProviderConfig{}), UsageList: fake.GVK(&usageList{})}
+
+	secret := func(data string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "crossplane-system", Name: "creds"},
+			Data:       map[string][]byte{"credentials": []byte(data)},
+		}
+	}
+	pc := func(name, secret string) unstructured.Unstructured {
+		u := unstructured.Unstructured{Object: map[string]any{
+			"spec": map[string]any{
+				"credentials": map[string]any{
+					"secretRef": map[string]any{"namespace": "crossplane-system", "name": secret},
+				},
+			},
+		}}
+		u.SetName(name)
+		return u
+	}
+	usage := func(apiVersion, kind, namespace, name string) ProviderConfigUsage {
+		return &fake.ProviderConfigUsage{
+			RequiredTypedResourceReferencer: fake.RequiredTypedResourceReferencer{
+				Ref: xpv1.TypedReference{APIVersion: apiVersion, Kind: kind, Namespace: namespace, Name: name},
+			},
+		}
+	}
+
+	list := func(pcErr error) test.MockListFn {
+		return func(_ context.Context, obj client.ObjectList, opts ...client.ListOption) error {
+			switch l := obj.(type) {
+			case *unstructured.UnstructuredList:
+				if pcErr != nil {
+					return pcErr
+				}
+				l.Items = []unstructured.Unstructured{pc("cool", "creds"), pc("other", "other-creds")}
+			case *usageList:
+				lo := &client.ListOptions{}
+				lo.ApplyOptions(opts)
+				if lo.LabelSelector.String() != xpv1.LabelKeyProviderName+"=cool" {
+					t.Errorf("List(...): unexpected usage label selector %q", lo.LabelSelector)
+				}
+				l.Items = []ProviderConfigUsage{
+					usage(mg.GroupVersion().String(), mg.Kind, "", "mr-a"),
+					usage(mg.GroupVersion().String(), mg.Kind, "cool-ns", "mr-c"),
+					usage("other.example.org/v1", "Other", "", "mr-b"),
+				}
+			}
+			return nil
+		}
+	}
+
+	type args struct {
+		list   test.MockListFn
+		update event.UpdateEvent
+	}
+	type want struct {
+		requests []reconcile.Request
+		rotated  []string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"DataUnchanged": {
+			reason: "Nothing should be enqueued if the Secret's data did not change.",
+			args: args{
+				list:   list(nil),
+				update: event.UpdateEvent{ObjectOld: secret("a"), ObjectNew: secret("a")},
+			},
+			want: want{},
+		},
+		"ListProviderConfigsError": {
+			reason: "Nothing should be enqueued if we can't list ProviderConfigs.",
+			args: args{
+				list:   list(errBoom),
+				update: event.UpdateEvent{ObjectOld: secret("a"), ObjectNew: secret("b")},
+			},
+			want: want{},
+		},
+		"Rotated": {
+			reason: "Managed resources of our kind, cluster scoped or namespaced, that use a ProviderConfig referencing the Secret should be enqueued.",
+			args: args{
+				list:   list(nil),
+				update: event.UpdateEvent{ObjectOld: secret("a"), ObjectNew: secret("b")},
+			},
+			want: want{
+				requests: []reconcile.Request{
+					{NamespacedName: types.NamespacedName{Name: "mr-a"}},
+					{NamespacedName: types.NamespacedName{Namespace: "cool-ns", Name: "mr-c"}},
+				},
+				rotated: []string{"cool"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var rotated []string
+			e := NewEnqueueRequestsForRotatedCredentials(
+				&test.MockClient{MockList: tc.args.list},
+				fake.SchemeWith(&usageList{}),
+				pck, mg,
+				WithCredentialsRotatedFn(func(pc string) { rotated = append(rotated, pc) }),
+			)
+
+			q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+			defer q.ShutDown()
+			e.Update(tc.args.update, q)
+
+			var got []reconcile.Request
+			for q.Len() > 0 {
+				item, _ := q.Get()
+				got = append(got, item.(reconcile.Request))
+				q.Done(item)
+			}
+
+			if diff := cmp.Diff(tc.want.requests, got); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.rotated, rotated); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want rotated, +got rotated:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
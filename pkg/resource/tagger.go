@@ -0,0 +1,135 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+)
+
+// External resources of claimed managed resources are tagged with the
+// following keys by the ClaimTagger.
+const (
+	ExternalResourceTagKeyClaimName      = "crossplane-claim-name"
+	ExternalResourceTagKeyClaimNamespace = "crossplane-claim-namespace"
+)
+
+const errGetNamespace = "cannot get namespace"
+
+// An ExternalTagger returns the tags with which to tag the external resource
+// of the supplied managed resource in the provider API.
+type ExternalTagger interface {
+	ExternalTags(ctx context.Context, mg Managed) (map[string]string, error)
+}
+
+// An ExternalTaggerFn is a function that satisfies the ExternalTagger
+// interface.
+type ExternalTaggerFn func(ctx context.Context, mg Managed) (map[string]string, error)
+
+// ExternalTags returns the tags with which to tag the supplied managed
+// resource's external resource.
+func (fn ExternalTaggerFn) ExternalTags(ctx context.Context, mg Managed) (map[string]string, error) {
+	return fn(ctx, mg)
+}
+
+// An ExternalTaggerChain merges the tags returned by multiple ExternalTaggers.
+// Tags returned by later taggers override those returned by earlier taggers.
+type ExternalTaggerChain []ExternalTagger
+
+// ExternalTags calls each ExternalTagger serially and merges the tags they
+// return. It returns the first error it encounters, if any.
+func (tc ExternalTaggerChain) ExternalTags(ctx context.Context, mg Managed) (map[string]string, error) {
+	tags := map[string]string{}
+	for _, t := range tc {
+		tt, err := t.ExternalTags(ctx, mg)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range tt {
+			tags[k] = v
+		}
+	}
+	return tags, nil
+}
+
+// DefaultExternalTagger returns the identifying tags returned by
+// GetExternalTags.
+func DefaultExternalTagger() ExternalTagger {
+	return ExternalTaggerFn(func(_ context.Context, mg Managed) (map[string]string, error) {
+		return GetExternalTags(mg), nil
+	})
+}
+
+// StaticTagger returns the supplied tags, for example organization wide
+// cost-allocation tags.
+func StaticTagger(tags map[string]string) ExternalTagger {
+	return ExternalTaggerFn(func(_ context.Context, _ Managed) (map[string]string, error) {
+		out := make(map[string]string, len(tags))
+		for k, v := range tags {
+			out[k] = v
+		}
+		return out, nil
+	})
+}
+
+// ClaimTagger returns tags identifying the claim a managed resource was
+// composed for, if any.
+func ClaimTagger() ExternalTagger {
+	return ExternalTaggerFn(func(_ context.Context, mg Managed) (map[string]string, error) {
+		tags := map[string]string{}
+		if n := mg.GetLabels()[meta.LabelKeyClaimName]; n != "" {
+			tags[ExternalResourceTagKeyClaimName] = n
+		}
+		if ns := mg.GetLabels()[meta.LabelKeyClaimNamespace]; ns != "" {
+			tags[ExternalResourceTagKeyClaimNamespace] = ns
+		}
+		return tags, nil
+	})
+}
+
+// NamespaceLabelTagger returns tags derived from the labels of a managed
+// resource's namespace. The labels with the supplied keys are returned as tags
+// with the same keys. The namespace of a cluster scoped managed resource is
+// the namespace of the claim it was composed for, if any.
+func NamespaceLabelTagger(c client.Reader, keys ...string) ExternalTagger {
+	return ExternalTaggerFn(func(ctx context.Context, mg Managed) (map[string]string, error) {
+		name := mg.GetNamespace()
+		if name == "" {
+			name = mg.GetLabels()[meta.LabelKeyClaimNamespace]
+		}
+		if name == "" {
+			return nil, nil
+		}
+		ns := &corev1.Namespace{}
+		if err := c.Get(ctx, types.NamespacedName{Name: name}, ns); err != nil {
+			return nil, errors.Wrap(err, errGetNamespace)
+		}
+		tags := map[string]string{}
+		for _, k := range keys {
+			if v, ok := ns.GetLabels()[k]; ok {
+				tags[k] = v
+			}
+		}
+		return tags, nil
+	})
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestExternalTaggerChain(t *testing.T) {
+	errBoom := errors.New("boom")
+	claimed := &fake.Managed{ObjectMeta: metav1.ObjectMeta{
+		Name: "cool",
+		Labels: map[string]string{
+			meta.LabelKeyClaimName:      "claim",
+			meta.LabelKeyClaimNamespace: "team-a",
+		},
+	}}
+
+	type want struct {
+		tags map[string]string
+		err  error
+	}
+	cases := map[string]struct {
+		reason string
+		tagger ExternalTagger
+		mg     Managed
+		want   want
+	}{
+		"Merged": {
+			reason: "Tags returned by later taggers should override those returned by earlier taggers.",
+			tagger: ExternalTaggerChain{
+				StaticTagger(map[string]string{"org": "example", "env": "dev"}),
+				ClaimTagger(),
+				StaticTagger(map[string]string{"env": "prod"}),
+			},
+			mg: claimed,
+			want: want{
+				tags: map[string]string{
+					"org":                                "example",
+					"env":                                "prod",
+					ExternalResourceTagKeyClaimName:      "claim",
+					ExternalResourceTagKeyClaimNamespace: "team-a",
+				},
+			},
+		},
+		"NamespaceLabels": {
+			reason: "Tags should be derived from the labels of the claim's namespace.",
+			tagger: NamespaceLabelTagger(&test.MockClient{
+				MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+					if key.Name != "team-a" {
+						t.Errorf("NamespaceLabelTagger: want namespace %q, got %q", "team-a", key.Name)
+					}
+					obj.SetLabels(map[string]string{"cost-center": "42", "other": "label"})
+					return nil
+				},
+			}, "cost-center", "missing"),
+			mg: claimed,
+			want: want{
+				tags: map[string]string{"cost-center": "42"},
+			},
+		},
+		"NoNamespace": {
+			reason: "No tags should be derived from namespace labels if there is no namespace.",
+			tagger: NamespaceLabelTagger(nil, "cost-center"),
+			mg:     &fake.Managed{},
+			want:   want{},
+		},
+		"Error": {
+			reason: "Errors returned by a tagger should be returned.",
+			tagger: ExternalTaggerChain{
+				DefaultExternalTagger(),
+				NamespaceLabelTagger(&test.MockClient{MockGet: test.NewMockGetFn(errBoom)}, "cost-center"),
+			},
+			mg: claimed,
+			want: want{
+				err: errors.Wrap(errBoom, errGetNamespace),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.tagger.ExternalTags(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nExternalTags(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.tags, got); diff != "" {
+				t.Errorf("\n%s\nExternalTags(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
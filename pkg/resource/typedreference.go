@@ -0,0 +1,49 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	errGetTypedReference = "cannot get referenced object"
+	errFmtUIDMismatch    = "referenced %s %q has UID %q, not %q: it was probably deleted and recreated"
+)
+
+// GetTypedReference gets the object the supplied reference refers to into the
+// supplied object, which may be unstructured. If the reference has a UID, it
+// returns an error unless the object it gets has that UID.
+func GetTypedReference(ctx context.Context, c client.Reader, ref xpv1.TypedReference, obj client.Object) error {
+	if _, ok := obj.(runtime.Unstructured); ok {
+		obj.GetObjectKind().SetGroupVersionKind(ref.GroupVersionKind())
+	}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, obj); err != nil {
+		return errors.Wrap(err, errGetTypedReference)
+	}
+	if ref.UID != "" && obj.GetUID() != ref.UID {
+		return errors.Errorf(errFmtUIDMismatch, ref.Kind, ref.Name, obj.GetUID(), ref.UID)
+	}
+	return nil
+}
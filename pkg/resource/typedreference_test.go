@@ -0,0 +1,106 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestGetTypedReference(t *testing.T) {
+	errBoom := errors.New("boom")
+	ref := xpv1.TypedReference{APIVersion: "example.org/v1", Kind: "Thing", Namespace: "default", Name: "cool"}
+
+	get := func(uid types.UID) test.MockGetFn {
+		return func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			if key != (types.NamespacedName{Namespace: "default", Name: "cool"}) {
+				return errors.Errorf("unexpected key %s", key)
+			}
+			if obj.GetObjectKind().GroupVersionKind() != ref.GroupVersionKind() {
+				return errors.Errorf("unexpected kind %s", obj.GetObjectKind().GroupVersionKind())
+			}
+			obj.(metav1.Object).SetUID(uid)
+			return nil
+		}
+	}
+	pinned := func(uid types.UID) xpv1.TypedReference {
+		r := ref
+		r.UID = uid
+		return r
+	}
+
+	type args struct {
+		c   client.Reader
+		ref xpv1.TypedReference
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"GetError": {
+			reason: "Errors getting the referenced object should be returned.",
+			args: args{
+				c:   &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				ref: ref,
+			},
+			want: errors.Wrap(errBoom, errGetTypedReference),
+		},
+		"Unpinned": {
+			reason: "A reference without a UID should be satisfied by any object of the referenced name.",
+			args: args{
+				c:   &test.MockClient{MockGet: get("some-uid")},
+				ref: ref,
+			},
+		},
+		"PinnedMatch": {
+			reason: "A reference with a UID should be satisfied by an object with that UID.",
+			args: args{
+				c:   &test.MockClient{MockGet: get("cool-uid")},
+				ref: pinned("cool-uid"),
+			},
+		},
+		"PinnedMismatch": {
+			reason: "A reference with a UID should not be satisfied by an object with a different UID.",
+			args: args{
+				c:   &test.MockClient{MockGet: get("new-uid")},
+				ref: pinned("cool-uid"),
+			},
+			want: errors.Errorf(errFmtUIDMismatch, "Thing", "cool", "new-uid", "cool-uid"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := GetTypedReference(context.Background(), tc.args.c, tc.args.ref, &unstructured.Unstructured{})
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nGetTypedReference(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -118,11 +118,19 @@ func (c *Unstructured) GetCompositionRevisionSelector() *metav1.LabelSelector {
 
 // SetCompositionRevisionSelector of this resource claim.
 func (c *Unstructured) SetCompositionRevisionSelector(ref *metav1.LabelSelector) {
+	if ref == nil {
+		_ = fieldpath.Pave(c.Object).DeleteField("spec.compositionRevisionSelector")
+		return
+	}
 	_ = fieldpath.Pave(c.Object).SetValue("spec.compositionRevisionSelector", ref)
 }
 
 // SetCompositionUpdatePolicy of this resource claim.
 func (c *Unstructured) SetCompositionUpdatePolicy(p *xpv1.UpdatePolicy) {
+	if p == nil {
+		_ = fieldpath.Pave(c.Object).DeleteField("spec.compositionUpdatePolicy")
+		return
+	}
 	_ = fieldpath.Pave(c.Object).SetValue("spec.compositionUpdatePolicy", p)
 }
 
@@ -225,3 +233,23 @@ func (c *Unstructured) GetConnectionDetailsLastPublishedTime() *metav1.Time {
 func (c *Unstructured) SetConnectionDetailsLastPublishedTime(t *metav1.Time) {
 	_ = fieldpath.Pave(c.Object).SetValue("status.connectionDetails.lastPublishedTime", t)
 }
+
+// GetEnvironmentConfigReferences of this composite resource claim.
+func (c *Unstructured) GetEnvironmentConfigReferences() []corev1.ObjectReference {
+	out := &[]corev1.ObjectReference{}
+	_ = fieldpath.Pave(c.Object).GetValueInto("spec.environmentConfigRefs", out)
+	return *out
+}
+
+// SetEnvironmentConfigReferences of this composite resource claim. Empty
+// references are omitted.
+func (c *Unstructured) SetEnvironmentConfigReferences(refs []corev1.ObjectReference) {
+	filtered := make([]corev1.ObjectReference, 0, len(refs))
+	for _, ref := range refs {
+		if ref == (corev1.ObjectReference{}) {
+			continue
+		}
+		filtered = append(filtered, ref)
+	}
+	_ = fieldpath.Pave(c.Object).SetValue("spec.environmentConfigRefs", filtered)
+}
@@ -118,11 +118,19 @@ func (c *Unstructured) GetCompositionRevisionSelector() *metav1.LabelSelector {
 
 // SetCompositionRevisionSelector of this resource claim.
 func (c *Unstructured) SetCompositionRevisionSelector(sel *metav1.LabelSelector) {
+	if sel == nil {
+		_ = fieldpath.Pave(c.Object).DeleteField("spec.compositionRevisionSelector")
+		return
+	}
 	_ = fieldpath.Pave(c.Object).SetValue("spec.compositionRevisionSelector", sel)
 }
 
 // SetCompositionUpdatePolicy of this Composite resource.
 func (c *Unstructured) SetCompositionUpdatePolicy(p *xpv1.UpdatePolicy) {
+	if p == nil {
+		_ = fieldpath.Pave(c.Object).DeleteField("spec.compositionUpdatePolicy")
+		return
+	}
 	_ = fieldpath.Pave(c.Object).SetValue("spec.compositionUpdatePolicy", p)
 }
 
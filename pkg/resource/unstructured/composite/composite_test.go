@@ -202,6 +202,15 @@ func TestCompositionRevisionSelector(t *testing.T) {
 			set:  sel,
 			want: sel,
 		},
+		"RemoveSelector": {
+			u: func() *Unstructured {
+				u := New()
+				u.SetCompositionRevisionSelector(sel)
+				return u
+			}(),
+			set:  nil,
+			want: nil,
+		},
 	}
 
 	for name, tc := range cases {
@@ -227,6 +236,15 @@ func TestCompositionUpdatePolicy(t *testing.T) {
 			set:  &p,
 			want: &p,
 		},
+		"RemovePolicy": {
+			u: func() *Unstructured {
+				u := New()
+				u.SetCompositionUpdatePolicy(&p)
+				return u
+			}(),
+			set:  nil,
+			want: nil,
+		},
 	}
 
 	for name, tc := range cases {
@@ -349,3 +367,33 @@ func TestConnectionDetailsLastPublishedTime(t *testing.T) {
 		})
 	}
 }
+
+func TestEnvironmentConfigReferences(t *testing.T) {
+	ref := corev1.ObjectReference{Name: "cool"}
+	cases := map[string]struct {
+		u    *Unstructured
+		set  []corev1.ObjectReference
+		want []corev1.ObjectReference
+	}{
+		"NewRefs": {
+			u:    New(),
+			set:  []corev1.ObjectReference{ref},
+			want: []corev1.ObjectReference{ref},
+		},
+		"OmitEmptyRefs": {
+			u:    New(),
+			set:  []corev1.ObjectReference{{}, ref},
+			want: []corev1.ObjectReference{ref},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc.u.SetEnvironmentConfigReferences(tc.set)
+			got := tc.u.GetEnvironmentConfigReferences()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nu.GetEnvironmentConfigReferences(): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unstructured
+
+import (
+	"github.com/imdario/mergo"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+)
+
+const (
+	fieldSpec   = "spec"
+	fieldStatus = "status"
+)
+
+// Error strings.
+const (
+	errFmtExpandPath  = "cannot expand field path %q"
+	errFmtGetPath     = "cannot get field path %q"
+	errFmtSetPath     = "cannot set field path %q"
+	errFmtDeletePath  = "cannot delete field path %q"
+	errFmtMergeFields = "cannot merge %s fields"
+)
+
+// Fields of a claim's spec that are never propagated to its composite resource,
+// either because they only make sense in the context of the claim or because
+// the composite resource has an equivalent field of its own.
+var claimSpecExcludePaths = []string{
+	"spec.resourceRef",
+	"spec.writeConnectionSecretToRef",
+	"spec.compositeDeletePolicy",
+}
+
+// Fields of a composite resource's status that are never propagated to its
+// claim. The claim maintains its own conditions and connection details.
+var compositeStatusExcludePaths = []string{
+	"status.conditions",
+	"status.connectionDetails",
+}
+
+type propagateOptions struct {
+	include []string
+	exclude []string
+}
+
+// A PropagateOption configures how fields are propagated between a claim and
+// its composite resource.
+type PropagateOption func(o *propagateOptions)
+
+// WithIncludePaths limits propagation to the supplied field paths, for example
+// "spec.parameters" or "status.atProvider.endpoints[*]". Paths are rooted at
+// the object being propagated from and may contain wildcards. All fields are
+// propagated if no include paths are supplied.
+func WithIncludePaths(paths ...string) PropagateOption {
+	return func(o *propagateOptions) {
+		o.include = append(o.include, paths...)
+	}
+}
+
+// WithExcludePaths prevents the supplied field paths from being propagated.
+// Paths are rooted at the object being propagated from and may contain
+// wildcards. Exclude paths take precedence over include paths.
+func WithExcludePaths(paths ...string) PropagateOption {
+	return func(o *propagateOptions) {
+		o.exclude = append(o.exclude, paths...)
+	}
+}
+
+// PropagateSpec propagates the spec fields of the supplied claim to the
+// supplied composite resource. Propagated fields are merged into the composite
+// resource's spec, overwriting any existing values. Fields that only make sense
+// in the context of the claim, such as its resource and connection secret
+// references, are never propagated.
+func PropagateSpec(cm, cp Wrapper, o ...PropagateOption) error {
+	opts := &propagateOptions{exclude: append([]string{}, claimSpecExcludePaths...)}
+	for _, fn := range o {
+		fn(opts)
+	}
+	return propagate(cm.GetUnstructured().Object, cp.GetUnstructured().Object, fieldSpec, opts)
+}
+
+// PropagateStatus propagates the status fields of the supplied composite
+// resource to the supplied claim. Propagated fields are merged into the claim's
+// status, overwriting any existing values. The composite resource's conditions
+// and connection details are never propagated.
+func PropagateStatus(cp, cm Wrapper, o ...PropagateOption) error {
+	opts := &propagateOptions{exclude: append([]string{}, compositeStatusExcludePaths...)}
+	for _, fn := range o {
+		fn(opts)
+	}
+	return propagate(cp.GetUnstructured().Object, cm.GetUnstructured().Object, fieldStatus, opts)
+}
+
+func propagate(from, to map[string]any, field string, o *propagateOptions) error {
+	src, ok := from[field].(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	// Work on a copy so that filtering never mutates the source object.
+	p := fieldpath.Pave(map[string]any{field: runtime.DeepCopyJSONValue(src)})
+
+	if len(o.include) > 0 {
+		in, err := include(p, o.include)
+		if err != nil {
+			return err
+		}
+		p = in
+	}
+
+	if err := exclude(p, o.exclude); err != nil {
+		return err
+	}
+
+	filtered, ok := p.UnstructuredContent()[field].(map[string]any)
+	if !ok || len(filtered) == 0 {
+		return nil
+	}
+
+	dst, ok := to[field].(map[string]any)
+	if !ok {
+		dst = make(map[string]any)
+	}
+	if err := mergo.Merge(&dst, filtered, mergo.WithOverride); err != nil {
+		return errors.Wrapf(err, errFmtMergeFields, field)
+	}
+	to[field] = dst
+	return nil
+}
+
+// include returns a new Paved containing only the supplied paths of p.
+func include(p *fieldpath.Paved, paths []string) (*fieldpath.Paved, error) {
+	out := fieldpath.Pave(make(map[string]any))
+	for _, path := range paths {
+		expanded, err := p.ExpandWildcards(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtExpandPath, path)
+		}
+		for _, e := range expanded {
+			v, err := p.GetValue(e)
+			if err != nil {
+				return nil, errors.Wrapf(err, errFmtGetPath, e)
+			}
+			if err := out.SetValue(e, v); err != nil {
+				return nil, errors.Wrapf(err, errFmtSetPath, e)
+			}
+		}
+	}
+	return out, nil
+}
+
+// exclude deletes the supplied paths from p.
+func exclude(p *fieldpath.Paved, paths []string) error {
+	for _, path := range paths {
+		expanded, err := p.ExpandWildcards(path)
+		if err != nil {
+			return errors.Wrapf(err, errFmtExpandPath, path)
+		}
+		// Delete in reverse order so that removing an array element doesn't
+		// shift the indices of elements we're yet to delete.
+		for i := len(expanded) - 1; i >= 0; i-- {
+			if err := p.DeleteField(expanded[i]); err != nil {
+				return errors.Wrapf(err, errFmtDeletePath, expanded[i])
+			}
+		}
+	}
+	return nil
+}
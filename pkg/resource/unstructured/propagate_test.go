@@ -0,0 +1,210 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unstructured
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+type object struct{ unstructured.Unstructured }
+
+func (o *object) GetUnstructured() *unstructured.Unstructured { return &o.Unstructured }
+
+func newObject(content map[string]any) *object {
+	return &object{Unstructured: unstructured.Unstructured{Object: content}}
+}
+
+func TestPropagateSpec(t *testing.T) {
+	type args struct {
+		cm Wrapper
+		cp Wrapper
+		o  []PropagateOption
+	}
+	type want struct {
+		cp  map[string]any
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoSpec": {
+			reason: "Nothing should be propagated if the claim has no spec.",
+			args: args{
+				cm: newObject(map[string]any{}),
+				cp: newObject(map[string]any{"spec": map[string]any{"a": "b"}}),
+			},
+			want: want{
+				cp: map[string]any{"spec": map[string]any{"a": "b"}},
+			},
+		},
+		"AllFields": {
+			reason: "All user defined spec fields should be merged into the composite, except those that only apply to the claim.",
+			args: args{
+				cm: newObject(map[string]any{"spec": map[string]any{
+					"parameters":                  map[string]any{"size": "large"},
+					"resourceRef":                 map[string]any{"name": "cool-xr"},
+					"writeConnectionSecretToRef":  map[string]any{"name": "cool-secret"},
+					"compositeDeletePolicy":       "Foreground",
+					"compositionUpdatePolicy":     "Manual",
+					"compositionRevisionSelector": map[string]any{"matchLabels": map[string]any{"channel": "dev"}},
+				}}),
+				cp: newObject(map[string]any{"spec": map[string]any{
+					"parameters": map[string]any{"region": "us-east-1", "size": "small"},
+					"claimRef":   map[string]any{"name": "cool-claim"},
+				}}),
+			},
+			want: want{
+				cp: map[string]any{"spec": map[string]any{
+					"parameters":                  map[string]any{"region": "us-east-1", "size": "large"},
+					"claimRef":                    map[string]any{"name": "cool-claim"},
+					"compositionUpdatePolicy":     "Manual",
+					"compositionRevisionSelector": map[string]any{"matchLabels": map[string]any{"channel": "dev"}},
+				}},
+			},
+		},
+		"IncludeAndExclude": {
+			reason: "Only included paths that are not excluded should be propagated.",
+			args: args{
+				cm: newObject(map[string]any{"spec": map[string]any{
+					"parameters": map[string]any{"size": "large", "secret": "shh"},
+					"other":      "value",
+				}}),
+				cp: newObject(map[string]any{}),
+				o: []PropagateOption{
+					WithIncludePaths("spec.parameters"),
+					WithExcludePaths("spec.parameters.secret"),
+				},
+			},
+			want: want{
+				cp: map[string]any{"spec": map[string]any{
+					"parameters": map[string]any{"size": "large"},
+				}},
+			},
+		},
+		"ExcludeWildcard": {
+			reason: "Exclude paths containing wildcards should be expanded.",
+			args: args{
+				cm: newObject(map[string]any{"spec": map[string]any{
+					"tags": []any{
+						map[string]any{"key": "a", "internal": true},
+						map[string]any{"key": "b", "internal": true},
+					},
+				}}),
+				cp: newObject(map[string]any{}),
+				o: []PropagateOption{
+					WithExcludePaths("spec.tags[*].internal"),
+				},
+			},
+			want: want{
+				cp: map[string]any{"spec": map[string]any{
+					"tags": []any{
+						map[string]any{"key": "a"},
+						map[string]any{"key": "b"},
+					},
+				}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := PropagateSpec(tc.args.cm, tc.args.cp, tc.args.o...)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nPropagateSpec(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cp, tc.args.cp.GetUnstructured().Object); diff != "" {
+				t.Errorf("\n%s\nPropagateSpec(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPropagateStatus(t *testing.T) {
+	type args struct {
+		cp Wrapper
+		cm Wrapper
+		o  []PropagateOption
+	}
+	type want struct {
+		cm  map[string]any
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"AllFields": {
+			reason: "All status fields should be merged into the claim, except conditions and connection details.",
+			args: args{
+				cp: newObject(map[string]any{"status": map[string]any{
+					"address":           "127.0.0.1",
+					"conditions":        []any{map[string]any{"type": "Ready"}},
+					"connectionDetails": map[string]any{"lastPublishedTime": "now"},
+				}}),
+				cm: newObject(map[string]any{"status": map[string]any{
+					"conditions": []any{map[string]any{"type": "Synced"}},
+				}}),
+			},
+			want: want{
+				cm: map[string]any{"status": map[string]any{
+					"address":    "127.0.0.1",
+					"conditions": []any{map[string]any{"type": "Synced"}},
+				}},
+			},
+		},
+		"IncludeWildcard": {
+			reason: "Include paths containing wildcards should be expanded.",
+			args: args{
+				cp: newObject(map[string]any{"status": map[string]any{
+					"endpoints": map[string]any{"a": "1", "b": "2"},
+					"internal":  "secret",
+				}}),
+				cm: newObject(map[string]any{}),
+				o: []PropagateOption{
+					WithIncludePaths("status.endpoints.*"),
+				},
+			},
+			want: want{
+				cm: map[string]any{"status": map[string]any{
+					"endpoints": map[string]any{"a": "1", "b": "2"},
+				}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := PropagateStatus(tc.args.cp, tc.args.cm, tc.args.o...)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nPropagateStatus(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cm, tc.args.cm.GetUnstructured().Object); diff != "" {
+				t.Errorf("\n%s\nPropagateStatus(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
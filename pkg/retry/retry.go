@@ -0,0 +1,163 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package retry retries calls to flaky external APIs, and helps make retried
+// create calls idempotent.
+package retry
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+)
+
+const (
+	errGenerateToken = "cannot generate idempotency token"
+	errFmtGiveUp     = "giving up after %d attempts"
+)
+
+// DefaultBackoff is the backoff used by Do unless overridden using
+// WithBackoff. It makes up to five attempts, waiting roughly 1, 2, 4, and 8
+// seconds between them.
+var DefaultBackoff = wait.Backoff{
+	Duration: 1 * time.Second,
+	Factor:   2,
+	Jitter:   0.1,
+	Steps:    5,
+	Cap:      30 * time.Second,
+}
+
+type config struct {
+	backoff   wait.Backoff
+	retriable func(error) bool
+}
+
+// An Option configures how Do retries.
+type Option func(c *config)
+
+// WithBackoff configures how many times Do attempts a function, and how long
+// it waits between attempts. The wait is randomised by the backoff's jitter.
+func WithBackoff(b wait.Backoff) Option {
+	return func(c *config) {
+		c.backoff = b
+	}
+}
+
+// WithRetriable configures which errors Do retries. By default all errors
+// that are not terminal are retried. See errors.IsRetryable.
+func WithRetriable(fn func(error) bool) Option {
+	return func(c *config) {
+		c.retriable = fn
+	}
+}
+
+// Do calls the supplied function until it succeeds, it returns an error that
+// should not be retried, its attempts are exhausted, or the supplied context
+// is done. Do returns the last error returned by the function.
+func Do(ctx context.Context, fn func(ctx context.Context) error, o ...Option) error {
+	c := &config{backoff: DefaultBackoff, retriable: errors.IsRetryable}
+	for _, opt := range o {
+		opt(c)
+	}
+
+	b := c.backoff
+	attempts := 0
+	for {
+		attempts++
+		err := fn(ctx)
+		if err == nil || !c.retriable(err) {
+			return err
+		}
+		if b.Steps <= 1 {
+			return errors.Wrapf(err, errFmtGiveUp, attempts)
+		}
+
+		t := time.NewTimer(b.Step())
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return errors.Wrapf(err, errFmtGiveUp, attempts)
+		case <-t.C:
+		}
+	}
+}
+
+// NewToken returns a new random idempotency token.
+func NewToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, errGenerateToken)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// IdempotencyToken returns the idempotency token of the supplied managed
+// resource. A new token is generated and stored in the managed resource's
+// annotations if it has none. Call IdempotencyToken from an ExternalClient's
+// Create method and pass the token to the external API; the managed reconciler
+// persists the annotation whether or not Create succeeds, so a retried create
+// reuses the token.
+func IdempotencyToken(o metav1.Object) (string, error) {
+	if t := meta.GetIdempotencyToken(o); t != "" {
+		return t, nil
+	}
+	t, err := NewToken()
+	if err != nil {
+		return "", err
+	}
+	meta.SetIdempotencyToken(o, t)
+	return t, nil
+}
+
+// A TokenExtractor returns the idempotency token of the existing external
+// resource that caused the supplied error, if any. Most APIs that support
+// idempotency tokens either return the conflicting resource, or its token,
+// when a create call conflicts with an existing resource.
+type TokenExtractor func(err error) (string, bool)
+
+// CreatedWithToken returns true if the supplied error indicates that the
+// external resource already exists and was created using the supplied
+// managed resource's idempotency token, i.e. an earlier create call that
+// appeared to fail actually succeeded. Create should treat such errors as
+// success.
+func CreatedWithToken(err error, o metav1.Object, fn TokenExtractor) bool {
+	if err == nil {
+		return false
+	}
+	want := meta.GetIdempotencyToken(o)
+	if want == "" {
+		return false
+	}
+	got, ok := fn(err)
+	return ok && got == want
+}
+
+// IgnoreCreatedWithToken returns nil if the supplied error indicates that the
+// external resource was already created using the supplied managed
+// resource's idempotency token. Otherwise it returns the supplied error.
+func IgnoreCreatedWithToken(err error, o metav1.Object, fn TokenExtractor) error {
+	if CreatedWithToken(err, o, fn) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,232 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestDo(t *testing.T) {
+	errBoom := errors.New("boom")
+	fast := wait.Backoff{Duration: time.Millisecond, Factor: 1, Jitter: 0.1, Steps: 3}
+
+	// failN returns a function that fails with the supplied error the
+	// supplied number of times, then succeeds.
+	failN := func(n int, err error) func(context.Context) error {
+		return func(_ context.Context) error {
+			if n > 0 {
+				n--
+				return err
+			}
+			return nil
+		}
+	}
+
+	type args struct {
+		ctx context.Context
+		fn  func(context.Context) error
+		o   []Option
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"Success": {
+			reason: "We should return nil if the function succeeds on its first attempt.",
+			args: args{
+				ctx: context.Background(),
+				fn:  failN(0, errBoom),
+				o:   []Option{WithBackoff(fast)},
+			},
+		},
+		"EventualSuccess": {
+			reason: "We should retry the function until it succeeds.",
+			args: args{
+				ctx: context.Background(),
+				fn:  failN(2, errBoom),
+				o:   []Option{WithBackoff(fast)},
+			},
+		},
+		"AttemptsExhausted": {
+			reason: "We should return the last error if the function never succeeds.",
+			args: args{
+				ctx: context.Background(),
+				fn:  failN(3, errBoom),
+				o:   []Option{WithBackoff(fast)},
+			},
+			want: errors.Wrapf(errBoom, errFmtGiveUp, 3),
+		},
+		"TerminalError": {
+			reason: "We should not retry terminal errors.",
+			args: args{
+				ctx: context.Background(),
+				fn:  failN(1, errors.Terminal(errBoom)),
+				o:   []Option{WithBackoff(fast)},
+			},
+			want: errors.Terminal(errBoom),
+		},
+		"NotRetriable": {
+			reason: "We should not retry errors the supplied function says are not retriable.",
+			args: args{
+				ctx: context.Background(),
+				fn:  failN(1, errBoom),
+				o:   []Option{WithBackoff(fast), WithRetriable(func(_ error) bool { return false })},
+			},
+			want: errBoom,
+		},
+		"ContextDone": {
+			reason: "We should stop retrying when the context is done.",
+			args: args{
+				ctx: func() context.Context {
+					ctx, cancel := context.WithCancel(context.Background())
+					cancel()
+					return ctx
+				}(),
+				fn: failN(3, errBoom),
+				o:  []Option{WithBackoff(wait.Backoff{Duration: time.Hour, Steps: 3})},
+			},
+			want: errors.Wrapf(errBoom, errFmtGiveUp, 1),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := Do(tc.args.ctx, tc.args.fn, tc.args.o...)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nDo(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestIdempotencyToken(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		o      metav1.Object
+		want   string
+	}{
+		"ExistingToken": {
+			reason: "An existing idempotency token should be returned.",
+			o: &metav1.ObjectMeta{Annotations: map[string]string{
+				meta.AnnotationKeyIdempotencyToken: "cool-token",
+			}},
+			want: "cool-token",
+		},
+		"NewToken": {
+			reason: "A new idempotency token should be generated and stored if none exists.",
+			o:      &metav1.ObjectMeta{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := IdempotencyToken(tc.o)
+			if err != nil {
+				t.Fatalf("\n%s\nIdempotencyToken(...): unexpected error: %s", tc.reason, err)
+			}
+			if tc.want != "" {
+				if diff := cmp.Diff(tc.want, got); diff != "" {
+					t.Errorf("\n%s\nIdempotencyToken(...): -want, +got:\n%s", tc.reason, diff)
+				}
+			}
+			if got == "" {
+				t.Errorf("\n%s\nIdempotencyToken(...): want a token, got none", tc.reason)
+			}
+			if diff := cmp.Diff(got, meta.GetIdempotencyToken(tc.o)); diff != "" {
+				t.Errorf("\n%s\nIdempotencyToken(...) annotation: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreatedWithToken(t *testing.T) {
+	errExists := errors.New("already exists")
+	tokenOf := func(token string) TokenExtractor {
+		return func(err error) (string, bool) {
+			if !errors.Is(err, errExists) {
+				return "", false
+			}
+			return token, true
+		}
+	}
+	withToken := func(token string) metav1.Object {
+		o := &metav1.ObjectMeta{}
+		if token != "" {
+			meta.SetIdempotencyToken(o, token)
+		}
+		return o
+	}
+
+	type args struct {
+		err error
+		o   metav1.Object
+		fn  TokenExtractor
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   bool
+	}{
+		"NoError": {
+			reason: "A nil error does not indicate the resource already exists.",
+			args:   args{o: withToken("cool-token"), fn: tokenOf("cool-token")},
+			want:   false,
+		},
+		"NoToken": {
+			reason: "A resource with no idempotency token cannot have created the existing resource.",
+			args:   args{err: errExists, o: withToken(""), fn: tokenOf("cool-token")},
+			want:   false,
+		},
+		"OtherError": {
+			reason: "An error that does not indicate the resource already exists should not match.",
+			args:   args{err: errors.New("boom"), o: withToken("cool-token"), fn: tokenOf("cool-token")},
+			want:   false,
+		},
+		"DifferentToken": {
+			reason: "An existing resource created with a different token was not created by us.",
+			args:   args{err: errExists, o: withToken("cool-token"), fn: tokenOf("other-token")},
+			want:   false,
+		},
+		"SameToken": {
+			reason: "An existing resource created with our token was created by us.",
+			args:   args{err: errExists, o: withToken("cool-token"), fn: tokenOf("cool-token")},
+			want:   true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := CreatedWithToken(tc.args.err, tc.args.o, tc.args.fn)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nCreatedWithToken(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package shard allows several replicas of a provider to each reconcile a
+// subset, or shard, of its resources.
+package shard
+
+import (
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// LabelKeyShard is the key of a label that pins a resource to a particular
+// shard, regardless of its hash. The value must be a shard index.
+const LabelKeyShard = "crossplane.io/shard"
+
+const (
+	errFmtParse      = "cannot parse shard %q: must be of the form <index>/<count>"
+	errFmtOutOfRange = "shard index %d must be less than shard count %d"
+)
+
+// A Shard of resources. Each resource belongs to exactly one of Count shards.
+// A resource belongs to the shard indicated by its LabelKeyShard label if it
+// has one, and otherwise to a shard determined by consistently hashing its
+// namespace and name. Consistent hashing ensures few resources move between
+// shards when the shard count changes.
+type Shard struct {
+	// Index of this shard, from 0 to Count-1.
+	Index int
+
+	// Count of shards.
+	Count int
+}
+
+// Parse a shard of the form <index>/<count>, for example 0/3.
+func Parse(s string) (Shard, error) {
+	i, c, ok := strings.Cut(s, "/")
+	if !ok {
+		return Shard{}, errors.Errorf(errFmtParse, s)
+	}
+	idx, err := strconv.Atoi(i)
+	if err != nil {
+		return Shard{}, errors.Wrapf(err, errFmtParse, s)
+	}
+	cnt, err := strconv.Atoi(c)
+	if err != nil {
+		return Shard{}, errors.Wrapf(err, errFmtParse, s)
+	}
+	sh := Shard{Index: idx, Count: cnt}
+	if err := sh.Validate(); err != nil {
+		return Shard{}, err
+	}
+	return sh, nil
+}
+
+// Validate returns an error if the shard is invalid.
+func (s Shard) Validate() error {
+	if s.Index < 0 || s.Count < 1 || s.Index >= s.Count {
+		return errors.Errorf(errFmtOutOfRange, s.Index, s.Count)
+	}
+	return nil
+}
+
+// String returns the shard in the form <index>/<count>.
+func (s Shard) String() string {
+	return strconv.Itoa(s.Index) + "/" + strconv.Itoa(s.Count)
+}
+
+// Of returns the index of the shard the supplied object belongs to.
+func (s Shard) Of(o client.Object) int {
+	if v, ok := o.GetLabels()[LabelKeyShard]; ok {
+		if i, err := strconv.Atoi(v); err == nil && i >= 0 && i < s.Count {
+			return i
+		}
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(o.GetNamespace() + "/" + o.GetName()))
+	return jump(h.Sum64(), s.Count)
+}
+
+// Contains returns true if the supplied object belongs to this shard.
+func (s Shard) Contains(o client.Object) bool {
+	return s.Of(o) == s.Index
+}
+
+// Predicate returns a predicate that accepts only events for objects that
+// belong to this shard. Use it to filter the watches of a controller, e.g.
+// using builder.WithEventFilter.
+func (s Shard) Predicate() predicate.Predicate {
+	return predicate.NewPredicateFuncs(s.Contains)
+}
+
+// jump returns the bucket of the supplied key using Lamping and Veach's jump
+// consistent hash. See https://arxiv.org/abs/1406.2294.
+func jump(key uint64, buckets int) int {
+	var b, j int64 = -1, 0
+	for j < int64(buckets) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int(b)
+}
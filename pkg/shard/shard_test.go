@@ -0,0 +1,160 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shard
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestParse(t *testing.T) {
+	type want struct {
+		s   Shard
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		s      string
+		want   want
+	}{
+		"Valid": {
+			reason: "A shard of the form <index>/<count> should be parsed.",
+			s:      "1/3",
+			want:   want{s: Shard{Index: 1, Count: 3}},
+		},
+		"NoSeparator": {
+			reason: "A shard without a separator should be rejected.",
+			s:      "1",
+			want:   want{err: errors.Errorf(errFmtParse, "1")},
+		},
+		"NotANumber": {
+			reason: "A shard with a non-numeric index should be rejected.",
+			s:      "a/3",
+			want:   want{err: errors.Wrapf(func() error { _, err := strconv.Atoi("a"); return err }(), errFmtParse, "a/3")},
+		},
+		"OutOfRange": {
+			reason: "A shard whose index is not less than its count should be rejected.",
+			s:      "3/3",
+			want:   want{err: errors.Errorf(errFmtOutOfRange, 3, 3)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := Parse(tc.s)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nParse(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.s, got); diff != "" {
+				t.Errorf("\n%s\nParse(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestOf(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		s      Shard
+		o      client.Object
+		want   int
+	}{
+		"Pinned": {
+			reason: "An object labelled with a valid shard should belong to that shard.",
+			s:      Shard{Count: 3},
+			o:      &fake.Managed{ObjectMeta: metav1.ObjectMeta{Name: "cool", Labels: map[string]string{LabelKeyShard: "2"}}},
+			want:   2,
+		},
+		"PinnedOutOfRange": {
+			reason: "An object labelled with an invalid shard should be hashed.",
+			s:      Shard{Count: 1},
+			o:      &fake.Managed{ObjectMeta: metav1.ObjectMeta{Name: "cool", Labels: map[string]string{LabelKeyShard: "2"}}},
+			want:   0,
+		},
+		"SingleShard": {
+			reason: "Every object should belong to the only shard.",
+			s:      Shard{Count: 1},
+			o:      &fake.Managed{ObjectMeta: metav1.ObjectMeta{Name: "cool"}},
+			want:   0,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.s.Of(tc.o)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ns.Of(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestConsistency(t *testing.T) {
+	const objects = 10000
+
+	cases := map[string]struct {
+		reason string
+		from   int
+		to     int
+	}{
+		"ScaleUp": {
+			reason: "Scaling from 4 to 5 shards should move about a fifth of objects.",
+			from:   4,
+			to:     5,
+		},
+		"ScaleDown": {
+			reason: "Scaling from 5 to 4 shards should move about a fifth of objects.",
+			from:   5,
+			to:     4,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			moved := 0
+			per := make([]int, tc.to)
+			for i := 0; i < objects; i++ {
+				o := &fake.Managed{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("mr-%d", i)}}
+				before, after := Shard{Count: tc.from}.Of(o), Shard{Count: tc.to}.Of(o)
+				if before != after {
+					moved++
+				}
+				per[after]++
+			}
+
+			// Ideally 1/5 of objects move. Allow for some variance.
+			if moved > objects/4 {
+				t.Errorf("\n%s\nOf(...): moved %d of %d objects", tc.reason, moved, objects)
+			}
+			for i, n := range per {
+				if n < objects/tc.to*8/10 || n > objects/tc.to*12/10 {
+					t.Errorf("\n%s\nOf(...): shard %d has %d of %d objects", tc.reason, i, n, objects)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package external implements an in-memory, simulated external API that can
+// be used to test managed resource controllers hermetically.
+package external
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// Error strings.
+const (
+	errFmtNotFound      = "external resource %q not found"
+	errFmtAlreadyExists = "external resource %q already exists"
+)
+
+// A Resource in the simulated external API.
+type Resource struct {
+	// Name of the resource, i.e. its external name.
+	Name string
+
+	// Attributes of the resource.
+	Attributes map[string]any
+
+	// Created is when the resource was created.
+	Created time.Time
+
+	// Ready is true once the resource has existed for the ready delay of the
+	// Cloud.
+	Ready bool
+}
+
+// An Option configures a Cloud.
+type Option func(c *Cloud)
+
+// WithClock configures the function a Cloud uses to determine the current
+// time. Tests can use it to advance time without sleeping.
+func WithClock(now func() time.Time) Option {
+	return func(c *Cloud) {
+		c.now = now
+	}
+}
+
+// WithConsistencyDelay configures how long writes take to become visible to
+// reads, simulating an eventually consistent API. Until then reads observe
+// the resource as it was before the write. Writes themselves are always
+// checked against the latest state. Writes are visible immediately by
+// default.
+func WithConsistencyDelay(d time.Duration) Option {
+	return func(c *Cloud) {
+		c.consistency = d
+	}
+}
+
+// WithReadyDelay configures how long resources take to become ready after
+// they are created. Resources are ready immediately by default.
+func WithReadyDelay(d time.Duration) Option {
+	return func(c *Cloud) {
+		c.ready = d
+	}
+}
+
+type state struct {
+	// The latest state of the resource, or nil if it's deleted.
+	latest *Resource
+
+	// The state of the resource reads observe until visible, or nil if it
+	// didn't exist.
+	previous *Resource
+	visible  time.Time
+}
+
+// A Cloud is an in-memory simulation of an external API, keyed by external
+// name. It is safe for concurrent use.
+type Cloud struct {
+	now         func() time.Time
+	consistency time.Duration
+	ready       time.Duration
+
+	mu        sync.Mutex
+	resources map[string]*state
+}
+
+// New returns an empty Cloud.
+func New(o ...Option) *Cloud {
+	c := &Cloud{now: time.Now, resources: make(map[string]*state)}
+	for _, fn := range o {
+		fn(c)
+	}
+	return c
+}
+
+// IsNotFound returns true if the supplied error indicates an external resource
+// was not found.
+func IsNotFound(err error) bool {
+	return errors.HasCode(err, errors.CodeNotFound)
+}
+
+// IsAlreadyExists returns true if the supplied error indicates an external
+// resource already exists.
+func IsAlreadyExists(err error) bool {
+	return errors.HasCode(err, errors.CodeConflict)
+}
+
+// Get the named resource, as currently visible to reads.
+func (c *Cloud) Get(name string) (Resource, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	s, ok := c.resources[name]
+	if !ok {
+		return Resource{}, errors.WithCode(errors.Errorf(errFmtNotFound, name), errors.CodeNotFound)
+	}
+	r := s.latest
+	if now.Before(s.visible) {
+		r = s.previous
+	}
+	if r == nil {
+		return Resource{}, errors.WithCode(errors.Errorf(errFmtNotFound, name), errors.CodeNotFound)
+	}
+	out := copyResource(r)
+	out.Ready = !now.Before(r.Created.Add(c.ready))
+	return out, nil
+}
+
+// Create the named resource with the supplied attributes.
+func (c *Cloud) Create(name string, attrs map[string]any) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s, ok := c.resources[name]
+	if ok && s.latest != nil {
+		return errors.WithCode(errors.Errorf(errFmtAlreadyExists, name), errors.CodeConflict)
+	}
+	c.write(name, &Resource{Name: name, Attributes: copyAttributes(attrs), Created: c.now()})
+	return nil
+}
+
+// Update the attributes of the named resource.
+func (c *Cloud) Update(name string, attrs map[string]any) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s, ok := c.resources[name]
+	if !ok || s.latest == nil {
+		return errors.WithCode(errors.Errorf(errFmtNotFound, name), errors.CodeNotFound)
+	}
+	r := copyResource(s.latest)
+	r.Attributes = copyAttributes(attrs)
+	c.write(name, &r)
+	return nil
+}
+
+// Delete the named resource.
+func (c *Cloud) Delete(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s, ok := c.resources[name]
+	if !ok || s.latest == nil {
+		return errors.WithCode(errors.Errorf(errFmtNotFound, name), errors.CodeNotFound)
+	}
+	c.write(name, nil)
+	return nil
+}
+
+// Names returns the sorted names of all resources that exist, regardless of
+// whether reads can observe them yet.
+func (c *Cloud) Names() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	names := make([]string, 0, len(c.resources))
+	for n, s := range c.resources {
+		if s.latest != nil {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// write must be called with the lock held.
+func (c *Cloud) write(name string, r *Resource) {
+	now := c.now()
+	s, ok := c.resources[name]
+	if !ok {
+		s = &state{}
+		c.resources[name] = s
+	}
+	// Reads observe the state that was visible when the write happened.
+	if !now.Before(s.visible) {
+		s.previous = s.latest
+	}
+	s.latest = r
+	s.visible = now.Add(c.consistency)
+}
+
+func copyResource(r *Resource) Resource {
+	out := *r
+	out.Attributes = copyAttributes(r.Attributes)
+	return out
+}
+
+func copyAttributes(attrs map[string]any) map[string]any {
+	if attrs == nil {
+		return nil
+	}
+	out := make(map[string]any, len(attrs))
+	for k, v := range attrs {
+		out[k] = v
+	}
+	return out
+}
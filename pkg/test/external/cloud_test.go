@@ -0,0 +1,183 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestCloud(t *testing.T) {
+	now := time.Now()
+	attrs := map[string]any{"size": 1}
+	bigger := map[string]any{"size": 2}
+
+	// A step is a write that happens at an offset from now.
+	type step struct {
+		at time.Duration
+		fn func(c *Cloud) error
+	}
+	create := func(at time.Duration, a map[string]any) step {
+		return step{at: at, fn: func(c *Cloud) error { return c.Create("cool", a) }}
+	}
+	update := func(at time.Duration, a map[string]any) step {
+		return step{at: at, fn: func(c *Cloud) error { return c.Update("cool", a) }}
+	}
+	del := func(at time.Duration) step {
+		return step{at: at, fn: func(c *Cloud) error { return c.Delete("cool") }}
+	}
+
+	type args struct {
+		o     []Option
+		steps []step
+		get   time.Duration
+	}
+	type want struct {
+		err error
+		r   Resource
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotFound": {
+			reason: "Getting a resource that was never created should return a not found error.",
+			args:   args{},
+			want: want{
+				err: errors.WithCode(errors.Errorf(errFmtNotFound, "cool"), errors.CodeNotFound),
+			},
+		},
+		"Created": {
+			reason: "A created resource should be visible immediately by default.",
+			args: args{
+				steps: []step{create(0, attrs)},
+			},
+			want: want{
+				r: Resource{Name: "cool", Attributes: attrs, Created: now, Ready: true},
+			},
+		},
+		"AlreadyExists": {
+			reason: "Creating a resource that already exists should return a conflict error, even if it isn't visible yet.",
+			args: args{
+				o:     []Option{WithConsistencyDelay(time.Minute)},
+				steps: []step{create(0, attrs), create(time.Second, attrs)},
+				get:   time.Minute,
+			},
+			want: want{
+				err: errors.WithCode(errors.Errorf(errFmtAlreadyExists, "cool"), errors.CodeConflict),
+			},
+		},
+		"NotYetVisible": {
+			reason: "A created resource should not be visible until the consistency delay passes.",
+			args: args{
+				o:     []Option{WithConsistencyDelay(time.Minute)},
+				steps: []step{create(0, attrs)},
+				get:   30 * time.Second,
+			},
+			want: want{
+				err: errors.WithCode(errors.Errorf(errFmtNotFound, "cool"), errors.CodeNotFound),
+			},
+		},
+		"StaleUpdate": {
+			reason: "Reads should observe the previous attributes of a resource until an update is visible.",
+			args: args{
+				o:     []Option{WithConsistencyDelay(time.Minute)},
+				steps: []step{create(0, attrs), update(2*time.Minute, bigger)},
+				get:   150 * time.Second,
+			},
+			want: want{
+				r: Resource{Name: "cool", Attributes: attrs, Created: now, Ready: true},
+			},
+		},
+		"VisibleUpdate": {
+			reason: "Reads should observe the new attributes of a resource once an update is visible.",
+			args: args{
+				o:     []Option{WithConsistencyDelay(time.Minute)},
+				steps: []step{create(0, attrs), update(2*time.Minute, bigger)},
+				get:   3 * time.Minute,
+			},
+			want: want{
+				r: Resource{Name: "cool", Attributes: bigger, Created: now, Ready: true},
+			},
+		},
+		"NotReady": {
+			reason: "A resource should not be ready until the ready delay passes.",
+			args: args{
+				o:     []Option{WithReadyDelay(time.Minute)},
+				steps: []step{create(0, attrs)},
+				get:   30 * time.Second,
+			},
+			want: want{
+				r: Resource{Name: "cool", Attributes: attrs, Created: now},
+			},
+		},
+		"StaleDelete": {
+			reason: "A deleted resource should be visible until the consistency delay passes.",
+			args: args{
+				o:     []Option{WithConsistencyDelay(time.Minute)},
+				steps: []step{create(0, attrs), del(2 * time.Minute)},
+				get:   150 * time.Second,
+			},
+			want: want{
+				r: Resource{Name: "cool", Attributes: attrs, Created: now, Ready: true},
+			},
+		},
+		"DeleteNotFound": {
+			reason: "Deleting a resource that was already deleted should return a not found error.",
+			args: args{
+				steps: []step{create(0, attrs), del(time.Second), del(2 * time.Second)},
+				get:   3 * time.Second,
+			},
+			want: want{
+				err: errors.WithCode(errors.Errorf(errFmtNotFound, "cool"), errors.CodeNotFound),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			clock := now
+			c := New(append(tc.args.o, WithClock(func() time.Time { return clock }))...)
+
+			var err error
+			for _, s := range tc.args.steps {
+				clock = now.Add(s.at)
+				if err = s.fn(c); err != nil {
+					break
+				}
+			}
+			if err == nil {
+				clock = now.Add(tc.args.get)
+				var r Resource
+				r, err = c.Get("cool")
+				if diff := cmp.Diff(tc.want.r, r); diff != "" {
+					t.Errorf("\n%s\nc.Get(...): -want, +got:\n%s", tc.reason, diff)
+				}
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nc.Get(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	"context"
+	"reflect"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// An AttributesFn returns the attributes a managed resource desires its
+// external resource to have.
+type AttributesFn func(mg resource.Managed) map[string]any
+
+// Connecter returns an ExternalConnecter that connects managed resources to
+// the Cloud. Their external resources are named by their external name, or
+// their metadata.name if they have none, and are up to date if their
+// attributes deeply equal those returned by the supplied AttributesFn.
+// Managed resources are marked available once their external resource is
+// ready.
+func (c *Cloud) Connecter(fn AttributesFn) managed.ExternalConnecter {
+	return managed.ExternalConnectorFn(func(_ context.Context, _ resource.Managed) (managed.ExternalClient, error) {
+		return &client{cloud: c, attributes: fn}, nil
+	})
+}
+
+type client struct {
+	cloud      *Cloud
+	attributes AttributesFn
+}
+
+func (e *client) Observe(_ context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	name := meta.GetExternalName(mg)
+	if name == "" {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	r, err := e.cloud.Get(name)
+	if IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	if r.Ready {
+		mg.SetConditions(xpv1.Available())
+	}
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: reflect.DeepEqual(e.attributes(mg), r.Attributes),
+	}, nil
+}
+
+func (e *client) Create(_ context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	name := meta.GetExternalName(mg)
+	if name == "" {
+		name = mg.GetName()
+		meta.SetExternalName(mg, name)
+	}
+	return managed.ExternalCreation{}, e.cloud.Create(name, e.attributes(mg))
+}
+
+func (e *client) Update(_ context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	return managed.ExternalUpdate{}, e.cloud.Update(meta.GetExternalName(mg), e.attributes(mg))
+}
+
+func (e *client) Delete(_ context.Context, mg resource.Managed) error {
+	err := e.cloud.Delete(meta.GetExternalName(mg))
+	if IsNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package admission generates ValidatingAdmissionPolicies that enforce the
+// invariants of managed resources, so that they are enforced even in clusters
+// where a provider does not run a validating webhook.
+package admission
+
+import (
+	"bytes"
+	"fmt"
+
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/api/admissionregistration/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/validation/cel"
+	"github.com/crossplane/crossplane-runtime/pkg/validation/policy"
+)
+
+const errMarshalManifest = "cannot marshal manifest"
+
+// AnnotationKeyTTL is the conventional annotation used to declare the time to
+// live of a resource, as a Go duration string such as "1h30m". The managed
+// reconciler does not interpret it, but tools that garbage collect resources
+// may.
+const AnnotationKeyTTL = "crossplane.io/ttl"
+
+// durationPattern matches a Go duration string, as parsed by
+// time.ParseDuration, without a sign.
+const durationPattern = `^([0-9]+(\.[0-9]*)?(ns|us|µs|ms|s|m|h))+$`
+
+// PausedAnnotationFormat returns a Validation that requires the paused
+// annotation, if set, to be either "true" or "false".
+func PausedAnnotationFormat() v1alpha1.Validation {
+	return v1alpha1.Validation{
+		Expression: fmt.Sprintf("!has(object.metadata.annotations) || !(%[1]q in object.metadata.annotations) || object.metadata.annotations[%[1]q] in ['true', 'false']", meta.AnnotationKeyReconciliationPaused),
+		Message:    fmt.Sprintf("annotation %s must be either 'true' or 'false'", meta.AnnotationKeyReconciliationPaused),
+	}
+}
+
+// TTLAnnotationFormat returns a Validation that requires the TTL annotation,
+// if set, to be a Go duration string.
+func TTLAnnotationFormat() v1alpha1.Validation {
+	return DurationAnnotationFormat(AnnotationKeyTTL)
+}
+
+// DurationAnnotationFormat returns a Validation that requires the supplied
+// annotation, if set, to be a Go duration string such as "1h30m".
+func DurationAnnotationFormat(key string) v1alpha1.Validation {
+	return v1alpha1.Validation{
+		Expression: fmt.Sprintf("!has(object.metadata.annotations) || !(%[1]q in object.metadata.annotations) || object.metadata.annotations[%[1]q].matches(%[2]q)", key, durationPattern),
+		Message:    fmt.Sprintf("annotation %s must be a duration, for example '1h30m'", key),
+	}
+}
+
+// ManagementPolicies returns Validations that enforce the interactions of
+// management and deletion policies documented by policy.Validate. The
+// management policy must be the default policy unless the management policies
+// feature is enabled, in which case it must be consistent with the deletion
+// policy. The Validations are generated from the policy package's CEL rules.
+func ManagementPolicies(managementPoliciesEnabled bool) []v1alpha1.Validation {
+	rs := policy.RulesFor("object.spec", managementPoliciesEnabled)
+	v := make([]v1alpha1.Validation, len(rs))
+	for i := range rs {
+		v[i] = validation(rs[i])
+	}
+	return v
+}
+
+// ImmutableExternalName returns a Validation that prevents the external name
+// annotation from being changed or removed once set. It is not one of the
+// Invariants, because some providers support changing the external name of a
+// managed resource in order to import a different external resource.
+func ImmutableExternalName() v1alpha1.Validation {
+	return validation(cel.ImmutableExternalName())
+}
+
+func validation(r cel.Rule) v1alpha1.Validation {
+	return v1alpha1.Validation{Expression: r.Rule, Message: r.Message}
+}
+
+// Invariants returns the Validations that all managed resources must satisfy,
+// including the ManagementPolicies.
+func Invariants(managementPoliciesEnabled bool) []v1alpha1.Validation {
+	v := []v1alpha1.Validation{PausedAnnotationFormat(), TTLAnnotationFormat()}
+	return append(v, ManagementPolicies(managementPoliciesEnabled)...)
+}
+
+// A PolicyOption configures a generated ValidatingAdmissionPolicy.
+type PolicyOption func(p *v1alpha1.ValidatingAdmissionPolicy)
+
+// WithValidations adds the supplied Validations to a generated policy.
+func WithValidations(v ...v1alpha1.Validation) PolicyOption {
+	return func(p *v1alpha1.ValidatingAdmissionPolicy) {
+		p.Spec.Validations = append(p.Spec.Validations, v...)
+	}
+}
+
+// WithFailurePolicy sets the failure policy of a generated policy.
+func WithFailurePolicy(fp v1alpha1.FailurePolicyType) PolicyOption {
+	return func(p *v1alpha1.ValidatingAdmissionPolicy) {
+		p.Spec.FailurePolicy = &fp
+	}
+}
+
+// NewPolicy returns a ValidatingAdmissionPolicy with the supplied name that
+// applies to the creation and update of the supplied resources. A resource of
+// "*" matches all resources of its group. The policy has no validations unless
+// they are supplied using WithValidations.
+func NewPolicy(name string, resources []schema.GroupResource, o ...PolicyOption) *v1alpha1.ValidatingAdmissionPolicy {
+	rules := make([]v1alpha1.NamedRuleWithOperations, len(resources))
+	for i, gr := range resources {
+		rules[i] = v1alpha1.NamedRuleWithOperations{
+			RuleWithOperations: admissionv1.RuleWithOperations{
+				Operations: []admissionv1.OperationType{admissionv1.Create, admissionv1.Update},
+				Rule: admissionv1.Rule{
+					APIGroups:   []string{gr.Group},
+					APIVersions: []string{"*"},
+					Resources:   []string{gr.Resource},
+				},
+			},
+		}
+	}
+
+	p := &v1alpha1.ValidatingAdmissionPolicy{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       "ValidatingAdmissionPolicy",
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v1alpha1.ValidatingAdmissionPolicySpec{
+			MatchConstraints: &v1alpha1.MatchResources{ResourceRules: rules},
+		},
+	}
+	for _, fn := range o {
+		fn(p)
+	}
+	return p
+}
+
+// NewBinding returns a ValidatingAdmissionPolicyBinding that binds the supplied
+// policy to all of the resources it matches.
+func NewBinding(p *v1alpha1.ValidatingAdmissionPolicy) *v1alpha1.ValidatingAdmissionPolicyBinding {
+	return &v1alpha1.ValidatingAdmissionPolicyBinding{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       "ValidatingAdmissionPolicyBinding",
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: p.GetName()},
+		Spec:       v1alpha1.ValidatingAdmissionPolicyBindingSpec{PolicyName: p.GetName()},
+	}
+}
+
+// Generate returns a YAML manifest containing a ValidatingAdmissionPolicy that
+// enforces the runtime's Invariants for the supplied resources, and a binding
+// for that policy. Additional validations may be supplied as PolicyOptions.
+func Generate(name string, managementPoliciesEnabled bool, resources []schema.GroupResource, o ...PolicyOption) ([]byte, error) {
+	p := NewPolicy(name, resources, append([]PolicyOption{WithValidations(Invariants(managementPoliciesEnabled)...)}, o...)...)
+	return Marshal(p, NewBinding(p))
+}
+
+// Marshal the supplied objects as a multi-document YAML manifest.
+func Marshal(objs ...runtime.Object) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	for i, o := range objs {
+		y, err := yaml.Marshal(o)
+		if err != nil {
+			return nil, errors.Wrap(err, errMarshalManifest)
+		}
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		buf.Write(y)
+	}
+	return buf.Bytes(), nil
+}
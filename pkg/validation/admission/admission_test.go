@@ -0,0 +1,297 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"testing"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/go-cmp/cmp"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/api/admissionregistration/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+)
+
+// valid returns true if the supplied object satisfies all of the supplied
+// Validations, as evaluated by a ValidatingAdmissionPolicy. A nil oldObject
+// indicates a create.
+func valid(t *testing.T, v []v1alpha1.Validation, object, oldObject map[string]any) bool {
+	t.Helper()
+	env, err := cel.NewEnv(cel.Variable("object", cel.DynType), cel.Variable("oldObject", cel.DynType))
+	if err != nil {
+		t.Fatalf("cel.NewEnv(...): %v", err)
+	}
+	vars := map[string]any{"object": object, "oldObject": nil}
+	if oldObject != nil {
+		vars["oldObject"] = oldObject
+	}
+	for _, val := range v {
+		ast, iss := env.Compile(val.Expression)
+		if iss.Err() != nil {
+			t.Fatalf("env.Compile(%q): %v", val.Expression, iss.Err())
+		}
+		prg, err := env.Program(ast)
+		if err != nil {
+			t.Fatalf("env.Program(%q): %v", val.Expression, err)
+		}
+		out, _, err := prg.Eval(vars)
+		if err != nil {
+			t.Fatalf("prg.Eval(%q): %v", val.Expression, err)
+		}
+		if out.Value() != true {
+			return false
+		}
+	}
+	return true
+}
+
+func TestInvariants(t *testing.T) {
+	mr := func(annotations map[string]any, spec map[string]any) map[string]any {
+		m := map[string]any{}
+		if annotations != nil {
+			m["annotations"] = annotations
+		}
+		return map[string]any{"metadata": m, "spec": spec}
+	}
+
+	type args struct {
+		enabled bool
+		object  map[string]any
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   bool
+	}{
+		"Defaults": {
+			reason: "A managed resource with no annotations or policies should be valid.",
+			args: args{
+				object: mr(nil, map[string]any{}),
+			},
+			want: true,
+		},
+		"Paused": {
+			reason: "The paused annotation may be 'true' or 'false'.",
+			args: args{
+				object: mr(map[string]any{meta.AnnotationKeyReconciliationPaused: "true"}, map[string]any{}),
+			},
+			want: true,
+		},
+		"InvalidPaused": {
+			reason: "The paused annotation may only be 'true' or 'false'.",
+			args: args{
+				object: mr(map[string]any{meta.AnnotationKeyReconciliationPaused: "yes"}, map[string]any{}),
+			},
+			want: false,
+		},
+		"TTL": {
+			reason: "The TTL annotation may be a Go duration.",
+			args: args{
+				object: mr(map[string]any{AnnotationKeyTTL: "1h30m"}, map[string]any{}),
+			},
+			want: true,
+		},
+		"FractionalTTL": {
+			reason: "The TTL annotation may be a fractional Go duration.",
+			args: args{
+				object: mr(map[string]any{AnnotationKeyTTL: "1.5h"}, map[string]any{}),
+			},
+			want: true,
+		},
+		"InvalidTTL": {
+			reason: "The TTL annotation must be a Go duration.",
+			args: args{
+				object: mr(map[string]any{AnnotationKeyTTL: "a day"}, map[string]any{}),
+			},
+			want: false,
+		},
+		"NonDefaultManagementPolicyDisabled": {
+			reason: "A non-default management policy is invalid when management policies are disabled.",
+			args: args{
+				object: mr(nil, map[string]any{"managementPolicy": "ObserveOnly", "deletionPolicy": "Orphan"}),
+			},
+			want: false,
+		},
+		"OrphanDisabled": {
+			reason: "An Orphan deletion policy is valid when management policies are disabled.",
+			args: args{
+				object: mr(nil, map[string]any{"deletionPolicy": "Orphan"}),
+			},
+			want: true,
+		},
+		"ObserveOnlyOrphanEnabled": {
+			reason: "An ObserveOnly management policy may be combined with an Orphan deletion policy.",
+			args: args{
+				enabled: true,
+				object:  mr(nil, map[string]any{"managementPolicy": "ObserveOnly", "deletionPolicy": "Orphan"}),
+			},
+			want: true,
+		},
+		"FullControlDeleteEnabled": {
+			reason: "A FullControl management policy may be combined with a Delete deletion policy.",
+			args: args{
+				enabled: true,
+				object:  mr(nil, map[string]any{"managementPolicy": "FullControl", "deletionPolicy": "Delete"}),
+			},
+			want: true,
+		},
+		"ObserveOnlyDeleteEnabled": {
+			reason: "An ObserveOnly management policy may not be combined with a Delete deletion policy.",
+			args: args{
+				enabled: true,
+				object:  mr(nil, map[string]any{"managementPolicy": "ObserveOnly", "deletionPolicy": "Delete"}),
+			},
+			want: false,
+		},
+		"OrphanOnDeleteDefaultEnabled": {
+			reason: "An OrphanOnDelete management policy may not be combined with the default deletion policy.",
+			args: args{
+				enabled: true,
+				object:  mr(nil, map[string]any{"managementPolicy": "OrphanOnDelete"}),
+			},
+			want: false,
+		},
+		"FullControlOrphanEnabled": {
+			reason: "A FullControl management policy may not be combined with an Orphan deletion policy.",
+			args: args{
+				enabled: true,
+				object:  mr(nil, map[string]any{"deletionPolicy": "Orphan"}),
+			},
+			want: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := valid(t, Invariants(tc.args.enabled), tc.args.object, nil)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nInvariants(...): -want valid, +got valid:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestImmutableExternalName(t *testing.T) {
+	withName := func(name string) map[string]any {
+		return map[string]any{"metadata": map[string]any{"annotations": map[string]any{meta.AnnotationKeyExternalName: name}}}
+	}
+
+	type args struct {
+		object    map[string]any
+		oldObject map[string]any
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   bool
+	}{
+		"Create": {
+			reason: "A managed resource may be created with an external name.",
+			args: args{
+				object: withName("cool"),
+			},
+			want: true,
+		},
+		"Unchanged": {
+			reason: "An unchanged external name should be valid.",
+			args: args{
+				object:    withName("cool"),
+				oldObject: withName("cool"),
+			},
+			want: true,
+		},
+		"Changed": {
+			reason: "An external name may not be changed once set.",
+			args: args{
+				object:    withName("lame"),
+				oldObject: withName("cool"),
+			},
+			want: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := valid(t, []v1alpha1.Validation{ImmutableExternalName()}, tc.args.object, tc.args.oldObject)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nImmutableExternalName(): -want valid, +got valid:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNewPolicy(t *testing.T) {
+	fail := v1alpha1.Fail
+	v := v1alpha1.Validation{Expression: "true"}
+
+	want := &v1alpha1.ValidatingAdmissionPolicy{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "admissionregistration.k8s.io/v1alpha1", Kind: "ValidatingAdmissionPolicy"},
+		ObjectMeta: metav1.ObjectMeta{Name: "cool"},
+		Spec: v1alpha1.ValidatingAdmissionPolicySpec{
+			FailurePolicy: &fail,
+			MatchConstraints: &v1alpha1.MatchResources{ResourceRules: []v1alpha1.NamedRuleWithOperations{{
+				RuleWithOperations: admissionv1.RuleWithOperations{
+					Operations: []admissionv1.OperationType{admissionv1.Create, admissionv1.Update},
+					Rule: admissionv1.Rule{
+						APIGroups:   []string{"example.org"},
+						APIVersions: []string{"*"},
+						Resources:   []string{"*"},
+					},
+				},
+			}}},
+			Validations: []v1alpha1.Validation{v},
+		},
+	}
+
+	got := NewPolicy("cool", []schema.GroupResource{{Group: "example.org", Resource: "*"}}, WithValidations(v), WithFailurePolicy(fail))
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("NewPolicy(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	p := &v1alpha1.ValidatingAdmissionPolicy{ObjectMeta: metav1.ObjectMeta{Name: "cool"}}
+
+	want := `metadata:
+  creationTimestamp: null
+  name: cool
+spec:
+  validations: null
+---
+apiVersion: admissionregistration.k8s.io/v1alpha1
+kind: ValidatingAdmissionPolicyBinding
+metadata:
+  creationTimestamp: null
+  name: cool
+spec:
+  policyName: cool
+`
+
+	got, err := Marshal([]runtime.Object{p, NewBinding(p)}...)
+	if err != nil {
+		t.Fatalf("Marshal(...): %v", err)
+	}
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("Marshal(...): -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cel contains reusable CEL validation rules for the CRDs of managed
+// resources, and generators for the kubebuilder markers that declare them.
+//
+// Rules are written in terms of the 'self' and 'oldSelf' variables that are
+// available to a CRD's x-kubernetes-validations. A rule applies to the schema
+// node it is declared on, so the fields a rule refers to are the names of
+// fields of that node, for example 'name' rather than 'spec.forProvider.name'.
+// Note that CRD validation rules cannot read an object's annotations, so rules
+// concerning annotations, such as ImmutableExternalName, are instead written
+// for ValidatingAdmissionPolicies in terms of the 'object' and 'oldObject'
+// variables.
+package cel
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+)
+
+// A Rule is a CEL validation rule.
+type Rule struct {
+	// Rule is the CEL expression. It must evaluate to true for an object to
+	// be valid.
+	Rule string
+
+	// Message is returned when the Rule evaluates to false.
+	Message string
+}
+
+// ValidationRule returns the CRD validation rule representation of this Rule.
+func (r Rule) ValidationRule() extv1.ValidationRule {
+	return extv1.ValidationRule{Rule: r.Rule, Message: r.Message}
+}
+
+// Marker returns the kubebuilder marker comment that declares this Rule.
+func (r Rule) Marker() string {
+	m := "// +kubebuilder:validation:XValidation:rule=" + strconv.Quote(r.Rule)
+	if r.Message != "" {
+		m += ",message=" + strconv.Quote(r.Message)
+	}
+	return m
+}
+
+// Markers returns the kubebuilder marker comments that declare the supplied
+// rules, one per line.
+func Markers(rules ...Rule) string {
+	m := make([]string, len(rules))
+	for i, r := range rules {
+		m[i] = r.Marker()
+	}
+	return strings.Join(m, "\n")
+}
+
+// ImmutableOnceSet returns a Rule that prevents the supplied field from being
+// changed or removed once it has been set, for example a field that determines
+// the external name of a managed resource. The field may be set by an update
+// if it was previously unset, e.g. when it is late-initialized. The Rule is a
+// transition rule, so it is only evaluated on update.
+func ImmutableOnceSet(field string) Rule {
+	return Rule{
+		Rule:    fmt.Sprintf("!has(oldSelf.%[1]s) || (has(self.%[1]s) && self.%[1]s == oldSelf.%[1]s)", field),
+		Message: fmt.Sprintf("%s is immutable once set", field),
+	}
+}
+
+// ExactlyOneOf returns a Rule that requires exactly one of the supplied fields
+// to be set.
+func ExactlyOneOf(fields ...string) Rule {
+	return Rule{
+		Rule:    fmt.Sprintf("%s.filter(x, x).size() == 1", hasAll(fields)),
+		Message: fmt.Sprintf("exactly one of %s must be set", strings.Join(fields, ", ")),
+	}
+}
+
+// AtMostOneOf returns a Rule that requires at most one of the supplied fields
+// to be set.
+func AtMostOneOf(fields ...string) Rule {
+	return Rule{
+		Rule:    fmt.Sprintf("%s.filter(x, x).size() <= 1", hasAll(fields)),
+		Message: fmt.Sprintf("at most one of %s may be set", strings.Join(fields, ", ")),
+	}
+}
+
+// ReferenceOrSelector returns a Rule that requires at least one of the supplied
+// field, its reference, or its selector to be set, i.e. that a required field
+// is either set or can be resolved. It assumes the conventional naming of
+// reference fields, i.e. that field 'vpcId' is referenced by 'vpcIdRef' and
+// selected by 'vpcIdSelector'. Note that the managed reconciler sets the field
+// when it resolves a reference, and the reference when it resolves a selector,
+// so a resolved managed resource may have all three set.
+func ReferenceOrSelector(field string) Rule {
+	fields := []string{field, field + "Ref", field + "Selector"}
+	return Rule{
+		Rule:    fmt.Sprintf("%s.exists(x, x)", hasAll(fields)),
+		Message: fmt.Sprintf("one of %s must be set", strings.Join(fields, ", ")),
+	}
+}
+
+// ImmutableExternalName returns a Rule that prevents the external name
+// annotation from being changed or removed once it has been set. It may be set
+// by an update if it was previously unset, e.g. when the managed reconciler
+// sets it after creating an external resource. CRD validation rules cannot read
+// annotations, so unlike other Rules this Rule is written in terms of the
+// 'object' and 'oldObject' variables of a ValidatingAdmissionPolicy.
+func ImmutableExternalName() Rule {
+	a := func(o string) string {
+		return fmt.Sprintf("%s.metadata.annotations[%q]", o, meta.AnnotationKeyExternalName)
+	}
+	has := func(o string) string {
+		return fmt.Sprintf("(has(%[1]s.metadata.annotations) && %[2]q in %[1]s.metadata.annotations)", o, meta.AnnotationKeyExternalName)
+	}
+	return Rule{
+		Rule:    fmt.Sprintf("oldObject == null || !%s || (%s && %s == %s)", has("oldObject"), has("object"), a("object"), a("oldObject")),
+		Message: fmt.Sprintf("annotation %s is immutable once set", meta.AnnotationKeyExternalName),
+	}
+}
+
+// NoShrink returns a Rule that prevents the supplied list or map field from
+// having elements removed. The Rule is a transition rule, so it is only
+// evaluated on update.
+func NoShrink(field string) Rule {
+	return Rule{
+		Rule:    fmt.Sprintf("!has(oldSelf.%[1]s) || (has(self.%[1]s) && size(self.%[1]s) >= size(oldSelf.%[1]s))", field),
+		Message: fmt.Sprintf("elements may not be removed from %s", field),
+	}
+}
+
+func hasAll(fields []string) string {
+	h := make([]string, len(fields))
+	for i, f := range fields {
+		h[i] = fmt.Sprintf("has(self.%s)", f)
+	}
+	return "[" + strings.Join(h, ", ") + "]"
+}
@@ -0,0 +1,323 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cel
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	apiservercel "k8s.io/apiextensions-apiserver/pkg/apiserver/schema/cel"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+)
+
+// validate the supplied objects using the supplied Rule, as the API server
+// would validate a CRD. A nil oldSelf indicates a create.
+func validate(t *testing.T, r Rule, self, oldSelf map[string]any) field.ErrorList {
+	t.Helper()
+	str := extv1.JSONSchemaProps{Type: "string"}
+	obj := extv1.JSONSchemaProps{Type: "object", Properties: map[string]extv1.JSONSchemaProps{"name": str}}
+	props := extv1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]extv1.JSONSchemaProps{
+			"name":          str,
+			"a":             str,
+			"b":             str,
+			"vpcId":         str,
+			"vpcIdRef":      obj,
+			"vpcIdSelector": obj,
+			"tags":          {Type: "array", Items: &extv1.JSONSchemaPropsOrArray{Schema: &str}},
+		},
+		XValidations: extv1.ValidationRules{r.ValidationRule()},
+	}
+	internal := &apiextensions.JSONSchemaProps{}
+	if err := extv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(&props, internal, nil); err != nil {
+		t.Fatalf("cannot convert schema: %v", err)
+	}
+	ss, err := schema.NewStructural(internal)
+	if err != nil {
+		t.Fatalf("cannot build structural schema: %v", err)
+	}
+	v := apiservercel.NewValidator(ss, false, apiservercel.PerCallLimit)
+	var old any
+	if oldSelf != nil {
+		old = oldSelf
+	}
+	errs, _ := v.Validate(context.Background(), field.NewPath("spec"), ss, self, old, apiservercel.RuntimeCELCostBudget)
+	return errs
+}
+
+func TestRules(t *testing.T) {
+	type args struct {
+		r       Rule
+		self    map[string]any
+		oldSelf map[string]any
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   bool
+	}{
+		"ImmutableOnceSetUnchanged": {
+			reason: "An unchanged field should be valid.",
+			args: args{
+				r:       ImmutableOnceSet("name"),
+				self:    map[string]any{"name": "cool"},
+				oldSelf: map[string]any{"name": "cool"},
+			},
+			want: true,
+		},
+		"ImmutableOnceSetLateInit": {
+			reason: "A field that was unset may be set.",
+			args: args{
+				r:       ImmutableOnceSet("name"),
+				self:    map[string]any{"name": "cool"},
+				oldSelf: map[string]any{},
+			},
+			want: true,
+		},
+		"ImmutableOnceSetChanged": {
+			reason: "A field that was set may not be changed.",
+			args: args{
+				r:       ImmutableOnceSet("name"),
+				self:    map[string]any{"name": "lame"},
+				oldSelf: map[string]any{"name": "cool"},
+			},
+			want: false,
+		},
+		"ImmutableOnceSetRemoved": {
+			reason: "A field that was set may not be removed.",
+			args: args{
+				r:       ImmutableOnceSet("name"),
+				self:    map[string]any{},
+				oldSelf: map[string]any{"name": "cool"},
+			},
+			want: false,
+		},
+		"ExactlyOneOf": {
+			reason: "Exactly one of the fields may be set.",
+			args: args{
+				r:    ExactlyOneOf("a", "b"),
+				self: map[string]any{"a": "cool"},
+			},
+			want: true,
+		},
+		"ExactlyOneOfNone": {
+			reason: "One of the fields must be set.",
+			args: args{
+				r:    ExactlyOneOf("a", "b"),
+				self: map[string]any{},
+			},
+			want: false,
+		},
+		"ExactlyOneOfBoth": {
+			reason: "Both fields may not be set.",
+			args: args{
+				r:    ExactlyOneOf("a", "b"),
+				self: map[string]any{"a": "cool", "b": "cool"},
+			},
+			want: false,
+		},
+		"AtMostOneOfNone": {
+			reason: "No fields need be set.",
+			args: args{
+				r:    AtMostOneOf("a", "b"),
+				self: map[string]any{},
+			},
+			want: true,
+		},
+		"AtMostOneOfBoth": {
+			reason: "Both fields may not be set.",
+			args: args{
+				r:    AtMostOneOf("a", "b"),
+				self: map[string]any{"a": "cool", "b": "cool"},
+			},
+			want: false,
+		},
+		"ReferenceOrSelectorNone": {
+			reason: "One of the field, its reference, or its selector must be set.",
+			args: args{
+				r:    ReferenceOrSelector("vpcId"),
+				self: map[string]any{},
+			},
+			want: false,
+		},
+		"ReferenceOrSelectorSelector": {
+			reason: "An unresolved selector should be valid.",
+			args: args{
+				r:    ReferenceOrSelector("vpcId"),
+				self: map[string]any{"vpcIdSelector": map[string]any{}},
+			},
+			want: true,
+		},
+		"ReferenceOrSelectorResolved": {
+			reason: "A resolved selector, with the field and reference set by the managed reconciler, should be valid.",
+			args: args{
+				r: ReferenceOrSelector("vpcId"),
+				self: map[string]any{
+					"vpcId":         "vpc-1234",
+					"vpcIdRef":      map[string]any{"name": "cool"},
+					"vpcIdSelector": map[string]any{},
+				},
+				oldSelf: map[string]any{"vpcIdSelector": map[string]any{}},
+			},
+			want: true,
+		},
+		"NoShrinkGrow": {
+			reason: "Elements may be added to a list.",
+			args: args{
+				r:       NoShrink("tags"),
+				self:    map[string]any{"tags": []any{"a", "b"}},
+				oldSelf: map[string]any{"tags": []any{"a"}},
+			},
+			want: true,
+		},
+		"NoShrinkShrink": {
+			reason: "Elements may not be removed from a list.",
+			args: args{
+				r:       NoShrink("tags"),
+				self:    map[string]any{"tags": []any{"a"}},
+				oldSelf: map[string]any{"tags": []any{"a", "b"}},
+			},
+			want: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			errs := validate(t, tc.args.r, tc.args.self, tc.args.oldSelf)
+			for _, err := range errs {
+				// Rules that fail to compile are always a bug.
+				if strings.Contains(err.Error(), "compile") {
+					t.Fatalf("\n%s\nRule %q: %v", tc.reason, tc.args.r.Rule, err)
+				}
+			}
+			if got := len(errs) == 0; got != tc.want {
+				t.Errorf("\n%s\nRule %q: want valid %t, got errors: %v", tc.reason, tc.args.r.Rule, tc.want, errs)
+			}
+		})
+	}
+}
+
+func TestImmutableExternalName(t *testing.T) {
+	env, err := cel.NewEnv(cel.Variable("object", cel.DynType), cel.Variable("oldObject", cel.DynType))
+	if err != nil {
+		t.Fatalf("cel.NewEnv(...): %v", err)
+	}
+	ast, iss := env.Compile(ImmutableExternalName().Rule)
+	if iss.Err() != nil {
+		t.Fatalf("env.Compile(...): %v", iss.Err())
+	}
+	prg, err := env.Program(ast)
+	if err != nil {
+		t.Fatalf("env.Program(...): %v", err)
+	}
+
+	withName := func(name string) map[string]any {
+		return map[string]any{"metadata": map[string]any{"annotations": map[string]any{meta.AnnotationKeyExternalName: name}}}
+	}
+	without := map[string]any{"metadata": map[string]any{}}
+
+	cases := map[string]struct {
+		reason    string
+		object    any
+		oldObject any
+		want      bool
+	}{
+		"Create": {
+			reason: "Objects may be created with an external name.",
+			object: withName("cool"),
+			want:   true,
+		},
+		"Set": {
+			reason:    "An external name may be set if it was unset.",
+			object:    withName("cool"),
+			oldObject: without,
+			want:      true,
+		},
+		"Unchanged": {
+			reason:    "An unchanged external name should be valid.",
+			object:    withName("cool"),
+			oldObject: withName("cool"),
+			want:      true,
+		},
+		"Changed": {
+			reason:    "An external name may not be changed once set.",
+			object:    withName("lame"),
+			oldObject: withName("cool"),
+			want:      false,
+		},
+		"Removed": {
+			reason:    "An external name may not be removed once set.",
+			object:    without,
+			oldObject: withName("cool"),
+			want:      false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			out, _, err := prg.Eval(map[string]any{"object": tc.object, "oldObject": tc.oldObject})
+			if err != nil {
+				t.Fatalf("\n%s\nprg.Eval(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, out.Value()); diff != "" {
+				t.Errorf("\n%s\nprg.Eval(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMarkers(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		rules  []Rule
+		want   string
+	}{
+		"NoMessage": {
+			reason: "A rule without a message should produce a marker without a message.",
+			rules:  []Rule{{Rule: "self.a == 'b'"}},
+			want:   `// +kubebuilder:validation:XValidation:rule="self.a == 'b'"`,
+		},
+		"Multiple": {
+			reason: "Each rule should produce a marker on its own line, with quotes escaped.",
+			rules: []Rule{
+				{Rule: `self.a == "b"`, Message: "a must be b"},
+				AtMostOneOf("a", "b"),
+			},
+			want: `// +kubebuilder:validation:XValidation:rule="self.a == \"b\"",message="a must be b"` + "\n" +
+				`// +kubebuilder:validation:XValidation:rule="[has(self.a), has(self.b)].filter(x, x).size() <= 1",message="at most one of a, b may be set"`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Markers(tc.rules...)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nMarkers(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
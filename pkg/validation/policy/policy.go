@@ -0,0 +1,127 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package policy validates the interplay of a managed resource's management
+// and deletion policies. The same rules are enforced by webhooks, by CEL
+// validation rules, and by the managed reconciler, so that providers need not
+// each encode them.
+package policy
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/validation/cel"
+)
+
+const (
+	errManagementPoliciesDisabled = "must be FullControl unless the management policies feature is enabled"
+	errFmtConflict                = "must be %s when managementPolicy is %s, or the external resource will not be handled as the deletionPolicy specifies"
+)
+
+var (
+	// ManagementPolicyPath is the path to a managed resource's management
+	// policy.
+	ManagementPolicyPath = field.NewPath("spec", "managementPolicy")
+
+	// DeletionPolicyPath is the path to a managed resource's deletion policy.
+	DeletionPolicyPath = field.NewPath("spec", "deletionPolicy")
+)
+
+// Validate returns the ways in which the supplied management and deletion
+// policies violate their documented interactions, or an empty list if they
+// are valid. Unset policies are treated as their defaults; FullControl and
+// Delete respectively. Unknown policies are violations. A non-default
+// management policy is a violation unless managementPoliciesEnabled is true,
+// in which case the managed reconciler only obeys the deletion policy when:
+//
+//   - A FullControl management policy is combined with a Delete deletion policy.
+//   - An ObserveOnly or OrphanOnDelete management policy is combined with an
+//     Orphan deletion policy.
+//
+// Any other combination is a violation.
+func Validate(mp xpv1.ManagementPolicy, dp xpv1.DeletionPolicy, managementPoliciesEnabled bool) field.ErrorList {
+	errs := field.ErrorList{}
+
+	switch mp {
+	case "", xpv1.ManagementFullControl:
+	case xpv1.ManagementObserveOnly, xpv1.ManagementOrphanOnDelete:
+		if !managementPoliciesEnabled {
+			errs = append(errs, field.Forbidden(ManagementPolicyPath, errManagementPoliciesDisabled))
+		}
+	default:
+		errs = append(errs, field.NotSupported(ManagementPolicyPath, mp, []string{
+			string(xpv1.ManagementFullControl),
+			string(xpv1.ManagementObserveOnly),
+			string(xpv1.ManagementOrphanOnDelete),
+		}))
+	}
+
+	switch dp {
+	case "", xpv1.DeletionDelete, xpv1.DeletionOrphan:
+	default:
+		errs = append(errs, field.NotSupported(DeletionPolicyPath, dp, []string{
+			string(xpv1.DeletionDelete),
+			string(xpv1.DeletionOrphan),
+		}))
+	}
+
+	// The management policy is always FullControl when the feature is not
+	// enabled, so the deletion policy alone determines whether the external
+	// resource is deleted. There's no point checking combinations of policies
+	// we already know to be invalid.
+	if !managementPoliciesEnabled || len(errs) > 0 {
+		return errs
+	}
+
+	fullControl := mp.IsDefault()
+	deletes := dp == "" || dp == xpv1.DeletionDelete
+	switch {
+	case fullControl && !deletes:
+		errs = append(errs, field.Invalid(DeletionPolicyPath, dp, fmt.Sprintf(errFmtConflict, xpv1.DeletionDelete, mp)))
+	case !fullControl && deletes:
+		errs = append(errs, field.Invalid(DeletionPolicyPath, dp, fmt.Sprintf(errFmtConflict, xpv1.DeletionOrphan, mp)))
+	}
+	return errs
+}
+
+// Rules returns CEL validation rules that enforce the same interactions as
+// Validate. The rules apply to the spec of a managed resource. Unknown
+// policies are not checked; they are rejected by the enums of the policy
+// fields' schemas.
+func Rules(managementPoliciesEnabled bool) []cel.Rule {
+	return RulesFor("self", managementPoliciesEnabled)
+}
+
+// RulesFor returns the same CEL validation rules as Rules, but the rules apply
+// to the supplied CEL expression for the spec of a managed resource, for
+// example "object.spec" in a ValidatingAdmissionPolicy.
+func RulesFor(spec string, managementPoliciesEnabled bool) []cel.Rule {
+	fullControl := fmt.Sprintf("(!has(%[1]s.managementPolicy) || %[1]s.managementPolicy == %[2]q)", spec, xpv1.ManagementFullControl)
+	if !managementPoliciesEnabled {
+		return []cel.Rule{{
+			Rule:    fullControl,
+			Message: "managementPolicy " + errManagementPoliciesDisabled,
+		}}
+	}
+	deletes := fmt.Sprintf("(!has(%[1]s.deletionPolicy) || %[1]s.deletionPolicy == %[2]q)", spec, xpv1.DeletionDelete)
+	return []cel.Rule{{
+		Rule:    fmt.Sprintf("%s == %s", fullControl, deletes),
+		Message: fmt.Sprintf("managementPolicy %s requires deletionPolicy %s, and vice versa", xpv1.ManagementFullControl, xpv1.DeletionDelete),
+	}}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+func TestValidate(t *testing.T) {
+	type args struct {
+		mp      xpv1.ManagementPolicy
+		dp      xpv1.DeletionPolicy
+		enabled bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   field.ErrorList
+	}{
+		"Defaults": {
+			reason: "Unset policies should be valid.",
+			args:   args{},
+			want:   field.ErrorList{},
+		},
+		"NonDefaultManagementPolicyDisabled": {
+			reason: "A non-default management policy should be forbidden if management policies are not enabled.",
+			args:   args{mp: xpv1.ManagementObserveOnly, dp: xpv1.DeletionOrphan},
+			want: field.ErrorList{
+				field.Forbidden(ManagementPolicyPath, errManagementPoliciesDisabled),
+			},
+		},
+		"OrphanDisabled": {
+			reason: "Any known deletion policy should be valid if management policies are not enabled.",
+			args:   args{dp: xpv1.DeletionOrphan},
+			want:   field.ErrorList{},
+		},
+		"FullControlDelete": {
+			reason: "A FullControl management policy should be valid with a Delete deletion policy.",
+			args:   args{mp: xpv1.ManagementFullControl, dp: xpv1.DeletionDelete, enabled: true},
+			want:   field.ErrorList{},
+		},
+		"OrphanOnDeleteOrphan": {
+			reason: "An OrphanOnDelete management policy should be valid with an Orphan deletion policy.",
+			args:   args{mp: xpv1.ManagementOrphanOnDelete, dp: xpv1.DeletionOrphan, enabled: true},
+			want:   field.ErrorList{},
+		},
+		"FullControlOrphan": {
+			reason: "A FullControl management policy should require a Delete deletion policy.",
+			args:   args{mp: xpv1.ManagementFullControl, dp: xpv1.DeletionOrphan, enabled: true},
+			want: field.ErrorList{
+				field.Invalid(DeletionPolicyPath, xpv1.DeletionOrphan, fmt.Sprintf(errFmtConflict, xpv1.DeletionDelete, xpv1.ManagementFullControl)),
+			},
+		},
+		"ObserveOnlyDefaultDeletionPolicy": {
+			reason: "An unset deletion policy should be treated as Delete, which conflicts with an ObserveOnly management policy.",
+			args:   args{mp: xpv1.ManagementObserveOnly, enabled: true},
+			want: field.ErrorList{
+				field.Invalid(DeletionPolicyPath, xpv1.DeletionPolicy(""), fmt.Sprintf(errFmtConflict, xpv1.DeletionOrphan, xpv1.ManagementObserveOnly)),
+			},
+		},
+		"UnknownPolicies": {
+			reason: "Unknown policies should not be supported, and should not be checked for conflicts.",
+			args:   args{mp: "Sometimes", dp: "Maybe", enabled: true},
+			want: field.ErrorList{
+				field.NotSupported(ManagementPolicyPath, xpv1.ManagementPolicy("Sometimes"), []string{"FullControl", "ObserveOnly", "OrphanOnDelete"}),
+				field.NotSupported(DeletionPolicyPath, xpv1.DeletionPolicy("Maybe"), []string{"Delete", "Orphan"}),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Validate(tc.args.mp, tc.args.dp, tc.args.enabled)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nValidate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRules(t *testing.T) {
+	cases := map[string]struct {
+		reason  string
+		spec    string
+		enabled bool
+		want    []string
+	}{
+		"Disabled": {
+			reason: "The management policy should be required to be the default if management policies are not enabled.",
+			spec:   "self",
+			want:   []string{`(!has(self.managementPolicy) || self.managementPolicy == "FullControl")`},
+		},
+		"Enabled": {
+			reason:  "FullControl should be required to go with Delete if management policies are enabled.",
+			spec:    "self",
+			enabled: true,
+			want:    []string{`(!has(self.managementPolicy) || self.managementPolicy == "FullControl") == (!has(self.deletionPolicy) || self.deletionPolicy == "Delete")`},
+		},
+		"ObjectSpec": {
+			reason:  "Rules should apply to the supplied expression for the spec.",
+			spec:    "object.spec",
+			enabled: true,
+			want:    []string{`(!has(object.spec.managementPolicy) || object.spec.managementPolicy == "FullControl") == (!has(object.spec.deletionPolicy) || object.spec.deletionPolicy == "Delete")`},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := []string{}
+			for _, r := range RulesFor(tc.spec, tc.enabled) {
+				got = append(got, r.Rule)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nRulesFor(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+
+	if diff := cmp.Diff(RulesFor("self", true), Rules(true)); diff != "" {
+		t.Errorf("\nRules should apply to self.\nRules(...): -want, +got:\n%s", diff)
+	}
+}
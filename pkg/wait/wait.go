@@ -0,0 +1,138 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package wait polls until a condition is satisfied. Polling is bound by the
+// supplied context, so when waiting from within a managed reconciler the wait
+// cannot outlast the reconcile phase's share of the reconcile timeout.
+package wait
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const (
+	errTimeout   = "timed out waiting for condition"
+	errGetObject = "cannot get object"
+)
+
+// DefaultBackoff is the backoff used by For unless overridden using
+// WithBackoff. It polls after roughly 1 second, then backs off until it polls
+// roughly every 30 seconds.
+var DefaultBackoff = wait.Backoff{
+	Duration: 1 * time.Second,
+	Factor:   1.5,
+	Jitter:   0.2,
+	Steps:    math.MaxInt32,
+	Cap:      30 * time.Second,
+}
+
+// A ConditionFunc returns true if the condition being waited for is
+// satisfied. Waiting stops if it returns an error.
+type ConditionFunc func(ctx context.Context) (bool, error)
+
+type config struct {
+	backoff wait.Backoff
+}
+
+// An Option configures how For polls.
+type Option func(c *config)
+
+// WithBackoff configures how long For waits between polls. Polling continues
+// at the backoff's final interval once its steps are exhausted, or its cap is
+// reached. The interval is randomised by the backoff's jitter.
+func WithBackoff(b wait.Backoff) Option {
+	return func(c *config) {
+		c.backoff = b
+	}
+}
+
+// For polls the supplied condition until it is satisfied, it returns an
+// error, or the supplied context is done. The condition is polled once
+// immediately.
+func For(ctx context.Context, fn ConditionFunc, o ...Option) error {
+	c := &config{backoff: DefaultBackoff}
+	for _, opt := range o {
+		opt(c)
+	}
+
+	b := c.backoff
+	for {
+		done, err := fn(ctx)
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+
+		t := time.NewTimer(b.Step())
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return errors.Wrap(ctx.Err(), errTimeout)
+		case <-t.C:
+		}
+	}
+}
+
+// Within returns a copy of the supplied context whose deadline is the
+// supplied share, between 0 and 1, of the time remaining until the supplied
+// context's deadline. Use it to partition the time available to a phase of a
+// reconcile, for example to leave time to clean up if a wait times out. The
+// copy is only cancellable if the supplied context has no deadline.
+func Within(ctx context.Context, share float64) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	if !ok || share <= 0 || share >= 1 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, time.Duration(share*float64(time.Until(deadline))))
+}
+
+// ObjectSatisfies returns a ConditionFunc that gets the supplied object and
+// returns true if the supplied function does. The object is updated with its
+// latest state each time the condition is polled.
+func ObjectSatisfies(c client.Reader, key types.NamespacedName, obj client.Object, fn func(o client.Object) bool) ConditionFunc {
+	return func(ctx context.Context) (bool, error) {
+		if err := c.Get(ctx, key, obj); err != nil {
+			return false, errors.Wrap(err, errGetObject)
+		}
+		return fn(obj), nil
+	}
+}
+
+// ConditionTrue returns a function that returns true if the supplied object
+// has a condition of the supplied type with status True. It returns false if
+// the object does not have conditions. Use it with ObjectSatisfies, e.g. to
+// wait for a managed resource to become ready.
+func ConditionTrue(ct xpv1.ConditionType) func(o client.Object) bool {
+	return func(o client.Object) bool {
+		c, ok := o.(resource.Conditioned)
+		if !ok {
+			return false
+		}
+		return resource.IsConditionTrue(c.GetCondition(ct))
+	}
+}
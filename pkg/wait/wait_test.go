@@ -0,0 +1,211 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wait
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestFor(t *testing.T) {
+	errBoom := errors.New("boom")
+	fast := wait.Backoff{Duration: time.Millisecond, Factor: 2, Jitter: 0.1, Steps: 3}
+
+	// doneAfter returns a condition that is satisfied after it has been
+	// polled the supplied number of times.
+	doneAfter := func(n int) ConditionFunc {
+		return func(_ context.Context) (bool, error) {
+			n--
+			return n <= 0, nil
+		}
+	}
+	cancelled := func() context.Context {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		return ctx
+	}
+
+	type args struct {
+		ctx context.Context
+		fn  ConditionFunc
+		o   []Option
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"Immediate": {
+			reason: "We should return immediately if the condition is already satisfied.",
+			args: args{
+				ctx: cancelled(),
+				fn:  doneAfter(1),
+			},
+		},
+		"Eventually": {
+			reason: "We should poll until the condition is satisfied, even once the backoff's steps are exhausted.",
+			args: args{
+				ctx: context.Background(),
+				fn:  doneAfter(6),
+				o:   []Option{WithBackoff(fast)},
+			},
+		},
+		"ConditionError": {
+			reason: "We should stop polling if the condition returns an error.",
+			args: args{
+				ctx: context.Background(),
+				fn:  func(_ context.Context) (bool, error) { return false, errBoom },
+				o:   []Option{WithBackoff(fast)},
+			},
+			want: errBoom,
+		},
+		"ContextDone": {
+			reason: "We should stop polling when the context is done.",
+			args: args{
+				ctx: cancelled(),
+				fn:  doneAfter(2),
+			},
+			want: errors.Wrap(context.Canceled, errTimeout),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := For(tc.args.ctx, tc.args.fn, tc.args.o...)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nFor(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestWithin(t *testing.T) {
+	withTimeout := func(d time.Duration) context.Context {
+		ctx, cancel := context.WithTimeout(context.Background(), d)
+		t.Cleanup(cancel)
+		return ctx
+	}
+
+	type args struct {
+		ctx   context.Context
+		share float64
+	}
+	type want struct {
+		deadline bool
+		max      time.Duration
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoDeadline": {
+			reason: "A context without a deadline should not be given one.",
+			args:   args{ctx: context.Background(), share: 0.5},
+			want:   want{deadline: false},
+		},
+		"Share": {
+			reason: "A context's remaining time should be partitioned according to the share.",
+			args:   args{ctx: withTimeout(time.Minute), share: 0.5},
+			want:   want{deadline: true, max: 30 * time.Second},
+		},
+		"InvalidShare": {
+			reason: "An invalid share should not change the context's deadline.",
+			args:   args{ctx: withTimeout(time.Minute), share: 2},
+			want:   want{deadline: true, max: time.Minute},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := Within(tc.args.ctx, tc.args.share)
+			defer cancel()
+
+			deadline, ok := ctx.Deadline()
+			if diff := cmp.Diff(tc.want.deadline, ok); diff != "" {
+				t.Errorf("\n%s\nWithin(...): -want deadline, +got deadline:\n%s", tc.reason, diff)
+			}
+			if remaining := time.Until(deadline); ok && (remaining > tc.want.max || remaining < tc.want.max-5*time.Second) {
+				t.Errorf("\n%s\nWithin(...): want about %s remaining, got %s", tc.reason, tc.want.max, remaining)
+			}
+		})
+	}
+}
+
+func TestObjectSatisfies(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	withReady := func(c xpv1.Condition) test.MockGetFn {
+		return func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			obj.(*fake.Managed).SetConditions(c)
+			return nil
+		}
+	}
+
+	type want struct {
+		done bool
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		c      client.Reader
+		want   want
+	}{
+		"GetError": {
+			reason: "Errors getting the object should be returned.",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			want:   want{err: errors.Wrap(errBoom, errGetObject)},
+		},
+		"NotReady": {
+			reason: "An object without a true condition of the supplied type should not satisfy the condition.",
+			c:      &test.MockClient{MockGet: withReady(xpv1.Creating())},
+			want:   want{done: false},
+		},
+		"Ready": {
+			reason: "An object with a true condition of the supplied type should satisfy the condition.",
+			c:      &test.MockClient{MockGet: withReady(xpv1.Available())},
+			want:   want{done: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fn := ObjectSatisfies(tc.c, types.NamespacedName{Name: "cool"}, &fake.Managed{}, ConditionTrue(xpv1.TypeReady))
+			done, err := fn(context.Background())
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nfn(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.done, done); diff != "" {
+				t.Errorf("\n%s\nfn(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,298 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package conversion contains a hub-and-spoke framework for building CRD
+// conversion webhooks.
+package conversion
+
+import (
+	"encoding/json"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+)
+
+// AnnotationKeyPreservedFields is the annotation used to preserve the values of
+// fields that exist in the hub version of a kind but not in a spoke version.
+const AnnotationKeyPreservedFields = "conversion.crossplane.io/preserved-fields"
+
+// Error strings.
+const (
+	errParseGroupVersion   = "cannot parse apiVersion"
+	errStashFields         = "cannot preserve fields"
+	errRestoreFields       = "cannot restore preserved fields"
+	errConvertToHub        = "cannot convert to hub version"
+	errConvertFromHub      = "cannot convert from hub version"
+	errMarshalPreserved    = "cannot marshal preserved fields"
+	errUnmarshalPreserved  = "cannot unmarshal preserved fields"
+	errFmtUnknownKind      = "no conversions registered for %s"
+	errFmtUnknownVersion   = "no conversions registered for version %q of %s"
+	errFmtGetPath          = "cannot get field path %q"
+	errFmtSetPath          = "cannot set field path %q"
+	errFmtDeletePath       = "cannot delete field path %q"
+	errFmtMapFieldPath     = "cannot map field path %q to %q"
+	errFmtVersionNotServed = "cannot convert to version %q of %s"
+)
+
+// A ConvertFn converts the supplied source object to the supplied destination
+// object. The destination object is a deep copy of the source object, with its
+// apiVersion set to the destination version, so a ConvertFn need only deal
+// with fields that differ between the two versions.
+type ConvertFn func(src, dst *unstructured.Unstructured) error
+
+// A Spoke version of a kind. Spokes are converted to and from the hub version
+// of their kind. Conversion between two spokes always passes through the hub.
+type Spoke struct {
+	// Version of this spoke, for example v1beta1.
+	Version string
+
+	// Paths maps the field paths of this spoke to the equivalent field paths
+	// of the hub version, for example "spec.forProvider.size" to
+	// "spec.forProvider.instanceSize". Paths are applied before ToHub, and
+	// inverted and applied before FromHub.
+	Paths map[string]string
+
+	// ToHub converts this spoke to the hub version. Optional.
+	ToHub ConvertFn
+
+	// FromHub converts the hub version to this spoke. Optional.
+	FromHub ConvertFn
+
+	// Preserve is the set of hub field paths that this spoke cannot represent.
+	// Their values are stashed in an annotation when converting to this spoke
+	// and restored when converting back to the hub, so that they survive a
+	// round trip through this spoke.
+	Preserve []string
+}
+
+type hub struct {
+	version string
+	spokes  map[string]Spoke
+}
+
+// A Registry of hub-and-spoke conversions. A Registry may hold conversions for
+// many kinds, and is safe for concurrent use.
+type Registry struct {
+	kinds map[schema.GroupKind]hub
+	mx    sync.RWMutex
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{kinds: make(map[schema.GroupKind]hub)}
+}
+
+// Register the supplied hub version and spokes of the supplied kind. Register
+// replaces any existing conversions for the kind.
+func (r *Registry) Register(gk schema.GroupKind, hubVersion string, spokes ...Spoke) {
+	h := hub{version: hubVersion, spokes: make(map[string]Spoke, len(spokes))}
+	for _, s := range spokes {
+		h.spokes[s.Version] = s
+	}
+
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	r.kinds[gk] = h
+}
+
+// Convert the supplied object to the supplied version of its kind.
+func (r *Registry) Convert(src *unstructured.Unstructured, toVersion string) (*unstructured.Unstructured, error) {
+	gvk := src.GroupVersionKind()
+	gk := gvk.GroupKind()
+
+	r.mx.RLock()
+	h, ok := r.kinds[gk]
+	r.mx.RUnlock()
+	if !ok {
+		return nil, errors.Errorf(errFmtUnknownKind, gk)
+	}
+
+	if gvk.Version == toVersion {
+		return src.DeepCopy(), nil
+	}
+
+	// Convert to the hub, unless we're already there.
+	hubObj := src
+	if gvk.Version != h.version {
+		s, ok := h.spokes[gvk.Version]
+		if !ok {
+			return nil, errors.Errorf(errFmtUnknownVersion, gvk.Version, gk)
+		}
+		var err error
+		if hubObj, err = toHub(s, src, gk.WithVersion(h.version)); err != nil {
+			return nil, errors.Wrap(err, errConvertToHub)
+		}
+	}
+
+	if toVersion == h.version {
+		return hubObj, nil
+	}
+
+	s, ok := h.spokes[toVersion]
+	if !ok {
+		return nil, errors.Errorf(errFmtVersionNotServed, toVersion, gk)
+	}
+	dst, err := fromHub(s, hubObj, gk.WithVersion(toVersion))
+	return dst, errors.Wrap(err, errConvertFromHub)
+}
+
+// RoundTrip converts the supplied object to the supplied version and back
+// again. Providers may use RoundTrip in their tests to ensure that converting
+// an object through a spoke version is lossless.
+func RoundTrip(r *Registry, o *unstructured.Unstructured, via string) (*unstructured.Unstructured, error) {
+	gv, err := schema.ParseGroupVersion(o.GetAPIVersion())
+	if err != nil {
+		return nil, errors.Wrap(err, errParseGroupVersion)
+	}
+	c, err := r.Convert(o, via)
+	if err != nil {
+		return nil, err
+	}
+	return r.Convert(c, gv.Version)
+}
+
+func toHub(s Spoke, src *unstructured.Unstructured, to schema.GroupVersionKind) (*unstructured.Unstructured, error) {
+	dst := src.DeepCopy()
+	dst.SetGroupVersionKind(to)
+
+	if err := mapPaths(dst, s.Paths); err != nil {
+		return nil, err
+	}
+	if s.ToHub != nil {
+		if err := s.ToHub(src, dst); err != nil {
+			return nil, err
+		}
+	}
+	return dst, errors.Wrap(restore(dst), errRestoreFields)
+}
+
+func fromHub(s Spoke, src *unstructured.Unstructured, to schema.GroupVersionKind) (*unstructured.Unstructured, error) {
+	dst := src.DeepCopy()
+	dst.SetGroupVersionKind(to)
+
+	if err := stash(dst, s.Preserve); err != nil {
+		return nil, errors.Wrap(err, errStashFields)
+	}
+	inverse := make(map[string]string, len(s.Paths))
+	for spoke, hub := range s.Paths {
+		inverse[hub] = spoke
+	}
+	if err := mapPaths(dst, inverse); err != nil {
+		return nil, err
+	}
+	if s.FromHub != nil {
+		if err := s.FromHub(src, dst); err != nil {
+			return nil, err
+		}
+	}
+	return dst, nil
+}
+
+// mapPaths moves the value at each key of the supplied map to the path at the
+// corresponding value.
+func mapPaths(u *unstructured.Unstructured, paths map[string]string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+
+	p := fieldpath.Pave(u.Object)
+	values := make(map[string]any, len(paths))
+	for from := range paths {
+		v, err := p.GetValue(from)
+		if fieldpath.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, errFmtGetPath, from)
+		}
+		values[from] = v
+		if err := p.DeleteField(from); err != nil {
+			return errors.Wrapf(err, errFmtDeletePath, from)
+		}
+	}
+	for from, v := range values {
+		if err := p.SetValue(paths[from], v); err != nil {
+			return errors.Wrapf(err, errFmtMapFieldPath, from, paths[from])
+		}
+	}
+	u.SetUnstructuredContent(p.UnstructuredContent())
+	return nil
+}
+
+// stash moves the values at the supplied paths to an annotation.
+func stash(u *unstructured.Unstructured, paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+
+	p := fieldpath.Pave(u.Object)
+	preserved := make(map[string]any, len(paths))
+	for _, path := range paths {
+		v, err := p.GetValue(path)
+		if fieldpath.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, errFmtGetPath, path)
+		}
+		preserved[path] = v
+		if err := p.DeleteField(path); err != nil {
+			return errors.Wrapf(err, errFmtDeletePath, path)
+		}
+	}
+	u.SetUnstructuredContent(p.UnstructuredContent())
+
+	if len(preserved) == 0 {
+		return nil
+	}
+	j, err := json.Marshal(preserved)
+	if err != nil {
+		return errors.Wrap(err, errMarshalPreserved)
+	}
+	meta.AddAnnotations(u, map[string]string{AnnotationKeyPreservedFields: string(j)})
+	return nil
+}
+
+// restore moves the values stashed in an annotation back to their paths.
+func restore(u *unstructured.Unstructured) error {
+	a, ok := u.GetAnnotations()[AnnotationKeyPreservedFields]
+	if !ok {
+		return nil
+	}
+
+	preserved := map[string]any{}
+	if err := json.Unmarshal([]byte(a), &preserved); err != nil {
+		return errors.Wrap(err, errUnmarshalPreserved)
+	}
+	meta.RemoveAnnotations(u, AnnotationKeyPreservedFields)
+	if len(u.GetAnnotations()) == 0 {
+		u.SetAnnotations(nil)
+	}
+
+	p := fieldpath.Pave(u.Object)
+	for path, v := range preserved {
+		if err := p.SetValue(path, v); err != nil {
+			return errors.Wrapf(err, errFmtSetPath, path)
+		}
+	}
+	u.SetUnstructuredContent(p.UnstructuredContent())
+	return nil
+}
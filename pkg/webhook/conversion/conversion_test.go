@@ -0,0 +1,188 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conversion
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var gk = schema.GroupKind{Group: "example.org", Kind: "Thing"}
+
+func thing(version string, content map[string]any) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: content}
+	u.SetGroupVersionKind(gk.WithVersion(version))
+	u.SetName("cool")
+	return u
+}
+
+func registry() *Registry {
+	r := NewRegistry()
+	r.Register(gk, "v1",
+		Spoke{
+			Version:  "v1beta1",
+			Paths:    map[string]string{"spec.size": "spec.instanceSize"},
+			Preserve: []string{"spec.zone"},
+		},
+		Spoke{
+			Version: "v1alpha1",
+			ToHub: func(_, dst *unstructured.Unstructured) error {
+				return unstructured.SetNestedField(dst.Object, "converted", "spec", "note")
+			},
+			FromHub: func(_, dst *unstructured.Unstructured) error {
+				unstructured.RemoveNestedField(dst.Object, "spec", "note")
+				return nil
+			},
+		},
+	)
+	return r
+}
+
+func TestConvert(t *testing.T) {
+	type args struct {
+		src *unstructured.Unstructured
+		to  string
+	}
+	type want struct {
+		dst *unstructured.Unstructured
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"UnknownKind": {
+			reason: "We should return an error if no conversions are registered for the kind.",
+			args: args{
+				src: func() *unstructured.Unstructured {
+					u := &unstructured.Unstructured{Object: map[string]any{}}
+					u.SetGroupVersionKind(schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Other"})
+					return u
+				}(),
+				to: "v1beta1",
+			},
+			want: want{
+				err: errors.Errorf(errFmtUnknownKind, schema.GroupKind{Group: "example.org", Kind: "Other"}),
+			},
+		},
+		"SameVersion": {
+			reason: "Converting to the same version should return a copy of the object.",
+			args: args{
+				src: thing("v1", map[string]any{"spec": map[string]any{"instanceSize": "large"}}),
+				to:  "v1",
+			},
+			want: want{
+				dst: thing("v1", map[string]any{"spec": map[string]any{"instanceSize": "large"}}),
+			},
+		},
+		"SpokeToHub": {
+			reason: "Field paths should be mapped when converting from a spoke to the hub.",
+			args: args{
+				src: thing("v1beta1", map[string]any{"spec": map[string]any{"size": "large"}}),
+				to:  "v1",
+			},
+			want: want{
+				dst: thing("v1", map[string]any{"spec": map[string]any{"instanceSize": "large"}}),
+			},
+		},
+		"HubToSpoke": {
+			reason: "Field paths should be mapped, and unrepresentable fields preserved, when converting from the hub to a spoke.",
+			args: args{
+				src: thing("v1", map[string]any{"spec": map[string]any{"instanceSize": "large", "zone": "a"}}),
+				to:  "v1beta1",
+			},
+			want: want{
+				dst: func() *unstructured.Unstructured {
+					u := thing("v1beta1", map[string]any{"spec": map[string]any{"size": "large"}})
+					u.SetAnnotations(map[string]string{AnnotationKeyPreservedFields: `{"spec.zone":"a"}`})
+					return u
+				}(),
+			},
+		},
+		"SpokeToSpoke": {
+			reason: "Conversions between spokes should pass through the hub.",
+			args: args{
+				src: thing("v1beta1", map[string]any{"spec": map[string]any{"size": "large"}}),
+				to:  "v1alpha1",
+			},
+			want: want{
+				dst: thing("v1alpha1", map[string]any{"spec": map[string]any{"instanceSize": "large"}}),
+			},
+		},
+		"UnknownVersion": {
+			reason: "We should return an error if the desired version is not a registered spoke.",
+			args: args{
+				src: thing("v1", map[string]any{}),
+				to:  "v2",
+			},
+			want: want{
+				err: errors.Errorf(errFmtVersionNotServed, "v2", gk),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := registry().Convert(tc.args.src, tc.args.to)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Convert(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.dst, got); diff != "" {
+				t.Errorf("\n%s\nr.Convert(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		o      *unstructured.Unstructured
+		via    string
+	}{
+		"PreservedFields": {
+			reason: "Fields a spoke cannot represent should survive a round trip through it.",
+			o:      thing("v1", map[string]any{"spec": map[string]any{"instanceSize": "large", "zone": "a"}}),
+			via:    "v1beta1",
+		},
+		"ConvertFns": {
+			reason: "ConvertFns should be inverses of each other.",
+			o:      thing("v1alpha1", map[string]any{"spec": map[string]any{"instanceSize": "large"}}),
+			via:    "v1",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := RoundTrip(registry(), tc.o, tc.via)
+			if err != nil {
+				t.Fatalf("\n%s\nRoundTrip(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.o, got); diff != "" {
+				t.Errorf("\n%s\nRoundTrip(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conversion
+
+import (
+	"encoding/json"
+	"net/http"
+
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+// Error strings.
+const (
+	errDecodeReview     = "cannot decode conversion review"
+	errNoRequest        = "conversion review has no request"
+	errDecodeObject     = "cannot decode object"
+	errEncodeObject     = "cannot encode object"
+	errParseDesired     = "cannot parse desired apiVersion"
+	errFmtConvertObject = "cannot convert object %d"
+	errFmtWrongGroup    = "cannot convert object of group %q to group %q"
+)
+
+// A HandlerOption configures a Handler.
+type HandlerOption func(h *Handler)
+
+// WithLogger configures the logger used by a Handler.
+func WithLogger(l logging.Logger) HandlerOption {
+	return func(h *Handler) {
+		h.log = l
+	}
+}
+
+// A Handler serves CRD conversion webhook requests using the conversions in a
+// Registry.
+type Handler struct {
+	registry *Registry
+	log      logging.Logger
+}
+
+// NewHandler returns a Handler that serves conversion webhook requests using
+// the conversions in the supplied Registry.
+func NewHandler(r *Registry, o ...HandlerOption) *Handler {
+	h := &Handler{registry: r, log: logging.NewNopLogger()}
+	for _, fn := range o {
+		fn(h)
+	}
+	return h
+}
+
+// Register a Handler that serves the supplied Registry at the supplied path of
+// the supplied webhook server. Note that controller-runtime's webhook builder
+// serves its own conversion webhook at /convert, so the path must differ if
+// both are used with the same server.
+func Register(s *webhook.Server, path string, r *Registry, o ...HandlerOption) {
+	s.Register(path, NewHandler(r, o...))
+}
+
+// ServeHTTP serves a CRD conversion webhook request.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	review := &extv1.ConversionReview{}
+	if err := json.NewDecoder(req.Body).Decode(review); err != nil {
+		h.log.Debug(errDecodeReview, "error", err)
+		http.Error(w, errors.Wrap(err, errDecodeReview).Error(), http.StatusBadRequest)
+		return
+	}
+	if review.Request == nil {
+		h.log.Debug(errNoRequest)
+		http.Error(w, errNoRequest, http.StatusBadRequest)
+		return
+	}
+
+	review.Response = h.convert(review.Request)
+	review.Response.UID = review.Request.UID
+	review.Request = nil
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(review); err != nil {
+		h.log.Debug("cannot encode conversion review", "error", err)
+	}
+}
+
+func (h *Handler) convert(req *extv1.ConversionRequest) *extv1.ConversionResponse {
+	gv, err := schema.ParseGroupVersion(req.DesiredAPIVersion)
+	if err != nil {
+		return failed(errors.Wrap(err, errParseDesired))
+	}
+
+	objs := make([]runtime.RawExtension, len(req.Objects))
+	for i, raw := range req.Objects {
+		src := &unstructured.Unstructured{}
+		if err := src.UnmarshalJSON(raw.Raw); err != nil {
+			return failed(errors.Wrapf(errors.Wrap(err, errDecodeObject), errFmtConvertObject, i))
+		}
+		// Conversion webhooks only convert between versions of a group.
+		if g := src.GroupVersionKind().Group; g != gv.Group {
+			return failed(errors.Wrapf(errors.Errorf(errFmtWrongGroup, g, gv.Group), errFmtConvertObject, i))
+		}
+		dst, err := h.registry.Convert(src, gv.Version)
+		if err != nil {
+			h.log.Debug("Cannot convert object", "error", err, "name", src.GetName(), "from", src.GetAPIVersion(), "to", req.DesiredAPIVersion)
+			return failed(errors.Wrapf(err, errFmtConvertObject, i))
+		}
+		j, err := dst.MarshalJSON()
+		if err != nil {
+			return failed(errors.Wrapf(errors.Wrap(err, errEncodeObject), errFmtConvertObject, i))
+		}
+		objs[i] = runtime.RawExtension{Raw: j}
+	}
+
+	return &extv1.ConversionResponse{
+		ConvertedObjects: objs,
+		Result:           metav1.Status{Status: metav1.StatusSuccess},
+	}
+}
+
+func failed(err error) *extv1.ConversionResponse {
+	return &extv1.ConversionResponse{
+		Result: metav1.Status{Status: metav1.StatusFailure, Message: err.Error()},
+	}
+}
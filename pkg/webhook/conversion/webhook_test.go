@@ -0,0 +1,158 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conversion
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestServeHTTP(t *testing.T) {
+	raw := func(o json.Marshaler) runtime.RawExtension {
+		j, _ := o.MarshalJSON()
+		return runtime.RawExtension{Raw: j}
+	}
+
+	type want struct {
+		code     int
+		response *extv1.ConversionResponse
+	}
+
+	cases := map[string]struct {
+		reason string
+		body   string
+		want   want
+	}{
+		"InvalidBody": {
+			reason: "We should return a bad request if the body is not a conversion review.",
+			body:   "{",
+			want: want{
+				code: http.StatusBadRequest,
+			},
+		},
+		"NoRequest": {
+			reason: "We should return a bad request if the conversion review has no request.",
+			body:   "{}",
+			want: want{
+				code: http.StatusBadRequest,
+			},
+		},
+		"Success": {
+			reason: "We should convert all objects to the desired version.",
+			body: func() string {
+				j, _ := json.Marshal(&extv1.ConversionReview{Request: &extv1.ConversionRequest{
+					UID:               types.UID("cool-uid"),
+					DesiredAPIVersion: "example.org/v1",
+					Objects: []runtime.RawExtension{
+						raw(thing("v1beta1", map[string]any{"spec": map[string]any{"size": "large"}})),
+					},
+				}})
+				return string(j)
+			}(),
+			want: want{
+				code: http.StatusOK,
+				response: &extv1.ConversionResponse{
+					UID: types.UID("cool-uid"),
+					ConvertedObjects: []runtime.RawExtension{
+						raw(thing("v1", map[string]any{"spec": map[string]any{"instanceSize": "large"}})),
+					},
+					Result: metav1.Status{Status: metav1.StatusSuccess},
+				},
+			},
+		},
+		"WrongGroup": {
+			reason: "We should refuse to convert objects to a different group.",
+			body: func() string {
+				j, _ := json.Marshal(&extv1.ConversionReview{Request: &extv1.ConversionRequest{
+					UID:               types.UID("cool-uid"),
+					DesiredAPIVersion: "example.net/v1",
+					Objects: []runtime.RawExtension{
+						raw(thing("v1beta1", map[string]any{})),
+					},
+				}})
+				return string(j)
+			}(),
+			want: want{
+				code: http.StatusOK,
+				response: &extv1.ConversionResponse{
+					UID: types.UID("cool-uid"),
+					Result: metav1.Status{
+						Status:  metav1.StatusFailure,
+						Message: `cannot convert object 0: cannot convert object of group "example.org" to group "example.net"`,
+					},
+				},
+			},
+		},
+		"Failure": {
+			reason: "We should report a failed conversion in the response.",
+			body: func() string {
+				j, _ := json.Marshal(&extv1.ConversionReview{Request: &extv1.ConversionRequest{
+					UID:               types.UID("cool-uid"),
+					DesiredAPIVersion: "example.org/v2",
+					Objects: []runtime.RawExtension{
+						raw(thing("v1", map[string]any{})),
+					},
+				}})
+				return string(j)
+			}(),
+			want: want{
+				code: http.StatusOK,
+				response: &extv1.ConversionResponse{
+					UID: types.UID("cool-uid"),
+					Result: metav1.Status{
+						Status:  metav1.StatusFailure,
+						Message: `cannot convert object 0: cannot convert to version "v2" of Thing.example.org`,
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			NewHandler(registry()).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/convert", bytes.NewBufferString(tc.body)))
+
+			if diff := cmp.Diff(tc.want.code, w.Code); diff != "" {
+				t.Errorf("\n%s\nServeHTTP(...): -want code, +got code:\n%s", tc.reason, diff)
+			}
+			if tc.want.response == nil {
+				return
+			}
+			got := &extv1.ConversionReview{}
+			if err := json.Unmarshal(w.Body.Bytes(), got); err != nil {
+				t.Fatalf("\n%s\nServeHTTP(...): cannot decode response: %v", tc.reason, err)
+			}
+			// Encoders may or may not append a trailing newline.
+			equateRaw := cmp.Comparer(func(a, b runtime.RawExtension) bool {
+				return bytes.Equal(bytes.TrimSpace(a.Raw), bytes.TrimSpace(b.Raw))
+			})
+			if diff := cmp.Diff(tc.want.response, got.Response, equateRaw); diff != "" {
+				t.Errorf("\n%s\nServeHTTP(...): -want response, +got response:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
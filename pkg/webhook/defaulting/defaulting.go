@@ -0,0 +1,101 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package defaulting contains rules for building defaulting webhooks for
+// managed resources.
+package defaulting
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/webhook"
+)
+
+// Error strings.
+const (
+	errNotProviderConfigReferencer = "object cannot reference a provider config"
+	errNotOrphanable               = "object does not have a deletion policy"
+	errNotMetaObject               = "object does not have Kubernetes object metadata"
+	errSetupWebhook                = "cannot set up defaulting webhook"
+)
+
+// DefaultProviderConfigRef returns a MutateFn that sets the provider config
+// reference of an object to the supplied name, unless it already references a
+// provider config.
+func DefaultProviderConfigRef(name string) webhook.MutateFn {
+	return func(_ context.Context, obj runtime.Object) error {
+		pcr, ok := obj.(resource.ProviderConfigReferencer)
+		if !ok {
+			return errors.New(errNotProviderConfigReferencer)
+		}
+		if pcr.GetProviderConfigReference() != nil {
+			return nil
+		}
+		pcr.SetProviderConfigReference(&xpv1.Reference{Name: name})
+		return nil
+	}
+}
+
+// DefaultDeletionPolicy returns a MutateFn that sets the deletion policy of an
+// object to the supplied policy, unless it already has a deletion policy.
+func DefaultDeletionPolicy(p xpv1.DeletionPolicy) webhook.MutateFn {
+	return func(_ context.Context, obj runtime.Object) error {
+		o, ok := obj.(resource.Orphanable)
+		if !ok {
+			return errors.New(errNotOrphanable)
+		}
+		if o.GetDeletionPolicy() != "" {
+			return nil
+		}
+		o.SetDeletionPolicy(p)
+		return nil
+	}
+}
+
+// DefaultExternalNameFromName returns a MutateFn that sets the external name
+// of an object to its metadata.name, unless it already has an external name.
+func DefaultExternalNameFromName() webhook.MutateFn {
+	return func(_ context.Context, obj runtime.Object) error {
+		o, ok := obj.(metav1.Object)
+		if !ok {
+			return errors.New(errNotMetaObject)
+		}
+		if meta.GetExternalName(o) != "" || o.GetName() == "" {
+			return nil
+		}
+		meta.SetExternalName(o, o.GetName())
+		return nil
+	}
+}
+
+// SetupWebhookWithManager registers a defaulting webhook for the supplied kind
+// of object with the supplied manager. The webhook applies the supplied
+// MutateFns in order.
+func SetupWebhookWithManager(mgr ctrl.Manager, of runtime.Object, fns ...webhook.MutateFn) error {
+	err := ctrl.NewWebhookManagedBy(mgr).
+		For(of).
+		WithDefaulter(webhook.NewMutator(webhook.WithMutationFns(fns...))).
+		Complete()
+	return errors.Wrap(err, errSetupWebhook)
+}
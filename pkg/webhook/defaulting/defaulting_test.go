@@ -0,0 +1,154 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package defaulting
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/crossplane/crossplane-runtime/pkg/webhook"
+)
+
+func TestDefaultingFns(t *testing.T) {
+	type args struct {
+		fn  webhook.MutateFn
+		obj runtime.Object
+	}
+	type want struct {
+		obj runtime.Object
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ProviderConfigRefNotReferencer": {
+			reason: "We should return an error if the object cannot reference a provider config.",
+			args: args{
+				fn:  DefaultProviderConfigRef("default"),
+				obj: &fake.Object{},
+			},
+			want: want{
+				obj: &fake.Object{},
+				err: errors.New(errNotProviderConfigReferencer),
+			},
+		},
+		"ProviderConfigRefUnset": {
+			reason: "We should default an unset provider config reference.",
+			args: args{
+				fn:  DefaultProviderConfigRef("default"),
+				obj: &fake.Managed{},
+			},
+			want: want{
+				obj: &fake.Managed{ProviderConfigReferencer: fake.ProviderConfigReferencer{Ref: &xpv1.Reference{Name: "default"}}},
+			},
+		},
+		"ProviderConfigRefSet": {
+			reason: "We should not overwrite an existing provider config reference.",
+			args: args{
+				fn:  DefaultProviderConfigRef("default"),
+				obj: &fake.Managed{ProviderConfigReferencer: fake.ProviderConfigReferencer{Ref: &xpv1.Reference{Name: "cool"}}},
+			},
+			want: want{
+				obj: &fake.Managed{ProviderConfigReferencer: fake.ProviderConfigReferencer{Ref: &xpv1.Reference{Name: "cool"}}},
+			},
+		},
+		"DeletionPolicyNotOrphanable": {
+			reason: "We should return an error if the object does not have a deletion policy.",
+			args: args{
+				fn:  DefaultDeletionPolicy(xpv1.DeletionOrphan),
+				obj: &fake.Object{},
+			},
+			want: want{
+				obj: &fake.Object{},
+				err: errors.New(errNotOrphanable),
+			},
+		},
+		"DeletionPolicyUnset": {
+			reason: "We should default an unset deletion policy.",
+			args: args{
+				fn:  DefaultDeletionPolicy(xpv1.DeletionOrphan),
+				obj: &fake.Managed{},
+			},
+			want: want{
+				obj: &fake.Managed{Orphanable: fake.Orphanable{Policy: xpv1.DeletionOrphan}},
+			},
+		},
+		"DeletionPolicySet": {
+			reason: "We should not overwrite an existing deletion policy.",
+			args: args{
+				fn:  DefaultDeletionPolicy(xpv1.DeletionOrphan),
+				obj: &fake.Managed{Orphanable: fake.Orphanable{Policy: xpv1.DeletionDelete}},
+			},
+			want: want{
+				obj: &fake.Managed{Orphanable: fake.Orphanable{Policy: xpv1.DeletionDelete}},
+			},
+		},
+		"ExternalNameUnset": {
+			reason: "We should default an unset external name to the object's name.",
+			args: args{
+				fn:  DefaultExternalNameFromName(),
+				obj: &fake.Managed{ObjectMeta: metav1.ObjectMeta{Name: "cool"}},
+			},
+			want: want{
+				obj: &fake.Managed{ObjectMeta: metav1.ObjectMeta{
+					Name:        "cool",
+					Annotations: map[string]string{meta.AnnotationKeyExternalName: "cool"},
+				}},
+			},
+		},
+		"ExternalNameSet": {
+			reason: "We should not overwrite an existing external name.",
+			args: args{
+				fn: DefaultExternalNameFromName(),
+				obj: &fake.Managed{ObjectMeta: metav1.ObjectMeta{
+					Name:        "cool",
+					Annotations: map[string]string{meta.AnnotationKeyExternalName: "existing"},
+				}},
+			},
+			want: want{
+				obj: &fake.Managed{ObjectMeta: metav1.ObjectMeta{
+					Name:        "cool",
+					Annotations: map[string]string{meta.AnnotationKeyExternalName: "existing"},
+				}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.args.fn(context.Background(), tc.args.obj)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nfn(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.obj, tc.args.obj); diff != "" {
+				t.Errorf("\n%s\nfn(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
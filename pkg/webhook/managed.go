@@ -0,0 +1,185 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/validation/policy"
+)
+
+// Error strings.
+const (
+	errNotManageable = "object does not have a management policy"
+	errNotOrphanable = "object does not have a deletion policy"
+	errNotManaged    = "object is not a managed resource"
+	errPaveObject    = "cannot pave object"
+	errPaveOldObject = "cannot pave old object"
+
+	errFmtGetField             = "cannot get field %q"
+	errFmtImmutableField       = "field %q is immutable"
+	errFmtExternalNameConflict = "external name %q is already declared by %s"
+)
+
+// ValidateCreatePolicies returns a ValidateCreateFn that rejects objects whose
+// management and deletion policies violate their documented interactions, as
+// determined by policy.Validate. Objects with a non-default management policy
+// are rejected unless managementPoliciesEnabled is true, rather than being
+// accepted and then ignored by the managed reconciler.
+func ValidateCreatePolicies(managementPoliciesEnabled bool) ValidateCreateFn {
+	return func(_ context.Context, obj runtime.Object) error {
+		return validatePolicies(managementPoliciesEnabled, obj)
+	}
+}
+
+// ValidateUpdatePolicies returns a ValidateUpdateFn that applies the same rules
+// as ValidateCreatePolicies to the updated object, but only if the update
+// changes its management or deletion policy. This allows objects that were
+// created before the rules were enforced, or before the management policies
+// feature was disabled, to be updated without first fixing their policies.
+func ValidateUpdatePolicies(managementPoliciesEnabled bool) ValidateUpdateFn {
+	return func(_ context.Context, oldObj, newObj runtime.Object) error {
+		if !policiesChanged(oldObj, newObj) {
+			return nil
+		}
+		return validatePolicies(managementPoliciesEnabled, newObj)
+	}
+}
+
+// policiesChanged returns true unless both objects have the same management
+// and deletion policies.
+func policiesChanged(oldObj, newObj runtime.Object) bool {
+	om, ook := oldObj.(resource.Manageable)
+	nm, nok := newObj.(resource.Manageable)
+	if !ook || !nok || om.GetManagementPolicy() != nm.GetManagementPolicy() {
+		return true
+	}
+	oo, ook := oldObj.(resource.Orphanable)
+	no, nok := newObj.(resource.Orphanable)
+	return !ook || !nok || oo.GetDeletionPolicy() != no.GetDeletionPolicy()
+}
+
+func validatePolicies(managementPoliciesEnabled bool, obj runtime.Object) error {
+	m, ok := obj.(resource.Manageable)
+	if !ok {
+		return errors.New(errNotManageable)
+	}
+	o, ok := obj.(resource.Orphanable)
+	if !ok {
+		return errors.New(errNotOrphanable)
+	}
+
+	return policy.Validate(m.GetManagementPolicy(), o.GetDeletionPolicy(), managementPoliciesEnabled).ToAggregate()
+}
+
+// ValidateImmutableFields returns a ValidateUpdateFn that rejects updates that
+// change or remove any of the supplied field paths, for example
+// "spec.forProvider.region". A field that was not set may be set by an update,
+// for example to allow it to be late-initialized.
+func ValidateImmutableFields(paths ...string) ValidateUpdateFn {
+	return func(_ context.Context, oldObj, newObj runtime.Object) error {
+		op, err := fieldpath.PaveObject(oldObj)
+		if err != nil {
+			return errors.Wrap(err, errPaveOldObject)
+		}
+		np, err := fieldpath.PaveObject(newObj)
+		if err != nil {
+			return errors.Wrap(err, errPaveObject)
+		}
+
+		for _, path := range paths {
+			ov, err := op.GetValue(path)
+			if fieldpath.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return errors.Wrapf(err, errFmtGetField, path)
+			}
+			nv, err := np.GetValue(path)
+			if resource.Ignore(fieldpath.IsNotFound, err) != nil {
+				return errors.Wrapf(err, errFmtGetField, path)
+			}
+			if !reflect.DeepEqual(ov, nv) {
+				return errors.Errorf(errFmtImmutableField, path)
+			}
+		}
+		return nil
+	}
+}
+
+// ValidateCreateExternalNameUnique returns a ValidateCreateFn that rejects
+// managed resources that declare an external name already declared by another
+// managed resource of the same kind and provider config. The supplied list
+// must be the list type of the kind of managed resource being validated, and
+// the supplied client must be backed by a cache with the index added by
+// resource.AddExternalNameIndex.
+func ValidateCreateExternalNameUnique(c client.Reader, l resource.ManagedList) ValidateCreateFn {
+	return func(ctx context.Context, obj runtime.Object) error {
+		return validateExternalNameUnique(ctx, c, l, obj)
+	}
+}
+
+// ValidateUpdateExternalNameUnique returns a ValidateUpdateFn that applies the
+// same rules as ValidateCreateExternalNameUnique to updates that change the
+// external name or provider config of a managed resource.
+func ValidateUpdateExternalNameUnique(c client.Reader, l resource.ManagedList) ValidateUpdateFn {
+	return func(ctx context.Context, oldObj, newObj runtime.Object) error {
+		om, ok := oldObj.(resource.Managed)
+		if !ok {
+			return errors.New(errNotManaged)
+		}
+		nm, ok := newObj.(resource.Managed)
+		if !ok {
+			return errors.New(errNotManaged)
+		}
+		if meta.GetExternalName(om) == meta.GetExternalName(nm) && reflect.DeepEqual(om.GetProviderConfigReference(), nm.GetProviderConfigReference()) {
+			return nil
+		}
+		return validateExternalNameUnique(ctx, c, l, newObj)
+	}
+}
+
+func validateExternalNameUnique(ctx context.Context, c client.Reader, l resource.ManagedList, obj runtime.Object) error {
+	mg, ok := obj.(resource.Managed)
+	if !ok {
+		return errors.New(errNotManaged)
+	}
+	others, err := resource.ListExternalNameConflicts(ctx, c, l, mg)
+	if err != nil {
+		return err
+	}
+	if len(others) == 0 {
+		return nil
+	}
+	names := make([]string, len(others))
+	for i, o := range others {
+		names[i] = types.NamespacedName{Namespace: o.GetNamespace(), Name: o.GetName()}.String()
+	}
+	sort.Strings(names)
+	return errors.Errorf(errFmtExternalNameConflict, meta.GetExternalName(mg), strings.Join(names, ", "))
+}
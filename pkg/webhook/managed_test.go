@@ -0,0 +1,447 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestValidatePolicies(t *testing.T) {
+	type args struct {
+		enabled bool
+		obj     runtime.Object
+	}
+	type want struct {
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotManageable": {
+			reason: "We should return an error if the object does not have a management policy.",
+			args: args{
+				obj: &fake.Object{},
+			},
+			want: want{
+				err: errors.New(errNotManageable),
+			},
+		},
+		"Defaults": {
+			reason: "We should accept an object that uses the default policies.",
+			args: args{
+				obj: &fake.Managed{},
+			},
+		},
+		"NonDefaultManagementPolicyDisabled": {
+			reason: "We should reject a non-default management policy if management policies are not enabled.",
+			args: args{
+				obj: &fake.Managed{Manageable: fake.Manageable{Policy: xpv1.ManagementObserveOnly}},
+			},
+			want: want{
+				err: field.ErrorList{
+					field.Forbidden(field.NewPath("spec", "managementPolicy"), "must be FullControl unless the management policies feature is enabled"),
+				}.ToAggregate(),
+			},
+		},
+		"NonDefaultManagementPolicyEnabled": {
+			reason: "We should accept a non-default management policy if management policies are enabled.",
+			args: args{
+				enabled: true,
+				obj: &fake.Managed{
+					Manageable: fake.Manageable{Policy: xpv1.ManagementOrphanOnDelete},
+					Orphanable: fake.Orphanable{Policy: xpv1.DeletionOrphan},
+				},
+			},
+		},
+		"FullControlDeleteEnabled": {
+			reason: "We should accept a FullControl management policy combined with a Delete deletion policy.",
+			args: args{
+				enabled: true,
+				obj: &fake.Managed{
+					Manageable: fake.Manageable{Policy: xpv1.ManagementFullControl},
+					Orphanable: fake.Orphanable{Policy: xpv1.DeletionDelete},
+				},
+			},
+		},
+		"ObserveOnlyOrphanEnabled": {
+			reason: "We should accept an ObserveOnly management policy combined with an Orphan deletion policy.",
+			args: args{
+				enabled: true,
+				obj: &fake.Managed{
+					Manageable: fake.Manageable{Policy: xpv1.ManagementObserveOnly},
+					Orphanable: fake.Orphanable{Policy: xpv1.DeletionOrphan},
+				},
+			},
+		},
+		"FullControlOrphanDisabled": {
+			reason: "We should accept an Orphan deletion policy if management policies are not enabled.",
+			args: args{
+				obj: &fake.Managed{Orphanable: fake.Orphanable{Policy: xpv1.DeletionOrphan}},
+			},
+		},
+		"ObserveOnlyDelete": {
+			reason: "We should reject an ObserveOnly management policy combined with a Delete deletion policy.",
+			args: args{
+				enabled: true,
+				obj: &fake.Managed{
+					Manageable: fake.Manageable{Policy: xpv1.ManagementObserveOnly},
+					Orphanable: fake.Orphanable{Policy: xpv1.DeletionDelete},
+				},
+			},
+			want: want{
+				err: field.ErrorList{
+					field.Invalid(field.NewPath("spec", "deletionPolicy"), xpv1.DeletionDelete, "must be Orphan when managementPolicy is ObserveOnly, or the external resource will not be handled as the deletionPolicy specifies"),
+				}.ToAggregate(),
+			},
+		},
+		"OrphanOnDeleteDelete": {
+			reason: "We should reject an OrphanOnDelete management policy combined with a Delete deletion policy.",
+			args: args{
+				enabled: true,
+				obj: &fake.Managed{
+					Manageable: fake.Manageable{Policy: xpv1.ManagementOrphanOnDelete},
+					Orphanable: fake.Orphanable{Policy: xpv1.DeletionDelete},
+				},
+			},
+			want: want{
+				err: field.ErrorList{
+					field.Invalid(field.NewPath("spec", "deletionPolicy"), xpv1.DeletionDelete, "must be Orphan when managementPolicy is OrphanOnDelete, or the external resource will not be handled as the deletionPolicy specifies"),
+				}.ToAggregate(),
+			},
+		},
+		"OrphanOnDeleteDefaultDeletionPolicy": {
+			reason: "We should treat an unset deletion policy as Delete, which conflicts with an OrphanOnDelete management policy.",
+			args: args{
+				enabled: true,
+				obj:     &fake.Managed{Manageable: fake.Manageable{Policy: xpv1.ManagementOrphanOnDelete}},
+			},
+			want: want{
+				err: field.ErrorList{
+					field.Invalid(field.NewPath("spec", "deletionPolicy"), xpv1.DeletionPolicy(""), "must be Orphan when managementPolicy is OrphanOnDelete, or the external resource will not be handled as the deletionPolicy specifies"),
+				}.ToAggregate(),
+			},
+		},
+		"FullControlOrphan": {
+			reason: "We should reject a FullControl management policy combined with an Orphan deletion policy.",
+			args: args{
+				enabled: true,
+				obj: &fake.Managed{
+					Manageable: fake.Manageable{Policy: xpv1.ManagementFullControl},
+					Orphanable: fake.Orphanable{Policy: xpv1.DeletionOrphan},
+				},
+			},
+			want: want{
+				err: field.ErrorList{
+					field.Invalid(field.NewPath("spec", "deletionPolicy"), xpv1.DeletionOrphan, "must be Delete when managementPolicy is FullControl, or the external resource will not be handled as the deletionPolicy specifies"),
+				}.ToAggregate(),
+			},
+		},
+		"UnknownManagementPolicy": {
+			reason: "We should reject an unknown management policy.",
+			args: args{
+				enabled: true,
+				obj:     &fake.Managed{Manageable: fake.Manageable{Policy: "Sometimes"}},
+			},
+			want: want{
+				err: field.ErrorList{
+					field.NotSupported(field.NewPath("spec", "managementPolicy"), xpv1.ManagementPolicy("Sometimes"), []string{"FullControl", "ObserveOnly", "OrphanOnDelete"}),
+				}.ToAggregate(),
+			},
+		},
+		"UnknownDeletionPolicy": {
+			reason: "We should reject an unknown deletion policy.",
+			args: args{
+				obj: &fake.Managed{Orphanable: fake.Orphanable{Policy: "Maybe"}},
+			},
+			want: want{
+				err: field.ErrorList{
+					field.NotSupported(field.NewPath("spec", "deletionPolicy"), xpv1.DeletionPolicy("Maybe"), []string{"Delete", "Orphan"}),
+				}.ToAggregate(),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateCreatePolicies(tc.args.enabled)(context.Background(), tc.args.obj)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateCreatePolicies(...): -want, +got\n%s\n", tc.reason, diff)
+			}
+			err = ValidateUpdatePolicies(tc.args.enabled)(context.Background(), nil, tc.args.obj)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateUpdatePolicies(...): -want, +got\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateUpdatePoliciesRatchet(t *testing.T) {
+	type args struct {
+		enabled bool
+		oldObj  runtime.Object
+		newObj  runtime.Object
+	}
+	type want struct {
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"UnchangedInvalidPolicies": {
+			reason: "We should accept an update that doesn't change invalid policies, e.g. because the object was created while management policies were enabled.",
+			args: args{
+				oldObj: &fake.Managed{Manageable: fake.Manageable{Policy: xpv1.ManagementObserveOnly}},
+				newObj: &fake.Managed{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"cool": "true"}},
+					Manageable: fake.Manageable{Policy: xpv1.ManagementObserveOnly},
+				},
+			},
+		},
+		"ChangedManagementPolicy": {
+			reason: "We should reject an update that changes the management policy to an invalid one.",
+			args: args{
+				oldObj: &fake.Managed{},
+				newObj: &fake.Managed{Manageable: fake.Manageable{Policy: xpv1.ManagementObserveOnly}},
+			},
+			want: want{
+				err: field.ErrorList{
+					field.Forbidden(field.NewPath("spec", "managementPolicy"), "must be FullControl unless the management policies feature is enabled"),
+				}.ToAggregate(),
+			},
+		},
+		"ChangedDeletionPolicy": {
+			reason: "We should reject an update that changes the deletion policy to one that conflicts with the unchanged management policy.",
+			args: args{
+				enabled: true,
+				oldObj: &fake.Managed{
+					Manageable: fake.Manageable{Policy: xpv1.ManagementObserveOnly},
+					Orphanable: fake.Orphanable{Policy: xpv1.DeletionOrphan},
+				},
+				newObj: &fake.Managed{
+					Manageable: fake.Manageable{Policy: xpv1.ManagementObserveOnly},
+					Orphanable: fake.Orphanable{Policy: xpv1.DeletionDelete},
+				},
+			},
+			want: want{
+				err: field.ErrorList{
+					field.Invalid(field.NewPath("spec", "deletionPolicy"), xpv1.DeletionDelete, "must be Orphan when managementPolicy is ObserveOnly, or the external resource will not be handled as the deletionPolicy specifies"),
+				}.ToAggregate(),
+			},
+		},
+		"ChangedToValidPolicies": {
+			reason: "We should accept an update that fixes invalid policies.",
+			args: args{
+				oldObj: &fake.Managed{Manageable: fake.Manageable{Policy: xpv1.ManagementObserveOnly}},
+				newObj: &fake.Managed{Manageable: fake.Manageable{Policy: xpv1.ManagementFullControl}},
+			},
+		},
+		"OldNotManageable": {
+			reason: "We should validate the policies of the new object if the old object doesn't have policies.",
+			args: args{
+				oldObj: &fake.Object{},
+				newObj: &fake.Managed{Manageable: fake.Manageable{Policy: xpv1.ManagementObserveOnly}},
+			},
+			want: want{
+				err: field.ErrorList{
+					field.Forbidden(field.NewPath("spec", "managementPolicy"), "must be FullControl unless the management policies feature is enabled"),
+				}.ToAggregate(),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateUpdatePolicies(tc.args.enabled)(context.Background(), tc.args.oldObj, tc.args.newObj)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateUpdatePolicies(...): -want, +got\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateImmutableFields(t *testing.T) {
+	obj := func(spec map[string]any) runtime.Object {
+		return &unstructured.Unstructured{Object: map[string]any{"spec": spec}}
+	}
+
+	type args struct {
+		paths  []string
+		oldObj runtime.Object
+		newObj runtime.Object
+	}
+	type want struct {
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Unchanged": {
+			reason: "We should accept an update that does not change an immutable field.",
+			args: args{
+				paths:  []string{"spec.region"},
+				oldObj: obj(map[string]any{"region": "us-east-1", "size": "small"}),
+				newObj: obj(map[string]any{"region": "us-east-1", "size": "large"}),
+			},
+		},
+		"Set": {
+			reason: "We should accept an update that sets an immutable field that was not previously set.",
+			args: args{
+				paths:  []string{"spec.region"},
+				oldObj: obj(map[string]any{}),
+				newObj: obj(map[string]any{"region": "us-east-1"}),
+			},
+		},
+		"Changed": {
+			reason: "We should reject an update that changes an immutable field.",
+			args: args{
+				paths:  []string{"spec.region"},
+				oldObj: obj(map[string]any{"region": "us-east-1"}),
+				newObj: obj(map[string]any{"region": "eu-west-1"}),
+			},
+			want: want{
+				err: errors.Errorf(errFmtImmutableField, "spec.region"),
+			},
+		},
+		"Removed": {
+			reason: "We should reject an update that removes an immutable field.",
+			args: args{
+				paths:  []string{"spec.region"},
+				oldObj: obj(map[string]any{"region": "us-east-1"}),
+				newObj: obj(map[string]any{}),
+			},
+			want: want{
+				err: errors.Errorf(errFmtImmutableField, "spec.region"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateImmutableFields(tc.args.paths...)(context.Background(), tc.args.oldObj, tc.args.newObj)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateImmutableFields(...): -want, +got\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateExternalNameUnique(t *testing.T) {
+	named := func(name, externalName string) *fake.Managed {
+		mg := &fake.Managed{ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID(name)}}
+		meta.SetExternalName(mg, externalName)
+		return mg
+	}
+	c := &test.MockClient{MockList: func(_ context.Context, l client.ObjectList, _ ...client.ListOption) error {
+		l.(*fake.ManagedList[resource.Managed]).Items = []*fake.Managed{named("existing", "cool")}
+		return nil
+	}}
+
+	type args struct {
+		oldObj runtime.Object
+		newObj runtime.Object
+	}
+	type want struct {
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotManaged": {
+			reason: "Objects that aren't managed resources should be rejected.",
+			args: args{
+				newObj: &unstructured.Unstructured{},
+			},
+			want: want{
+				err: errors.New(errNotManaged),
+			},
+		},
+		"CreateConflict": {
+			reason: "Creating a managed resource that declares another's external name should be rejected.",
+			args: args{
+				newObj: named("new", "cool"),
+			},
+			want: want{
+				err: errors.Errorf(errFmtExternalNameConflict, "cool", "/existing"),
+			},
+		},
+		"CreateUnique": {
+			reason: "Creating a managed resource that declares only its own external name should be allowed.",
+			args: args{
+				newObj: named("existing", "cool"),
+			},
+		},
+		"UpdateUnchanged": {
+			reason: "Updates that don't change the external name should be allowed.",
+			args: args{
+				oldObj: named("new", "cool"),
+				newObj: named("new", "cool"),
+			},
+		},
+		"UpdateConflict": {
+			reason: "Updates that change the external name to another's should be rejected.",
+			args: args{
+				oldObj: named("new", "other"),
+				newObj: named("new", "cool"),
+			},
+			want: want{
+				err: errors.Errorf(errFmtExternalNameConflict, "cool", "/existing"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var err error
+			if tc.args.oldObj == nil {
+				err = ValidateCreateExternalNameUnique(c, &fake.ManagedList[resource.Managed]{})(context.Background(), tc.args.newObj)
+			} else {
+				err = ValidateUpdateExternalNameUnique(c, &fake.ManagedList[resource.Managed]{})(context.Background(), tc.args.oldObj, tc.args.newObj)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateExternalNameUnique(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
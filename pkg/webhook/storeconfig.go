@@ -0,0 +1,221 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"crypto/tls"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	"github.com/crossplane/crossplane-runtime/pkg/connection/store"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errNotStoreConfig   = "object is not a secret store config"
+	errConvertObject    = "cannot convert object from unstructured data"
+	errBuildStore       = "cannot build secret store"
+	errProbeStore       = "cannot read from secret store"
+	errFmtSetStoreField = "cannot set field %q"
+)
+
+// ProbeSecretName is the name of the connection secret that ProbeStoreConfig
+// attempts to read. The secret need not exist.
+const ProbeSecretName = "crossplane-store-config-probe"
+
+// ReasonStoreProbeFailed is the reason of the warning event recorded by
+// WarnWithEvent.
+const ReasonStoreProbeFailed event.Reason = "StoreProbeFailed"
+
+const probeTimeout = 5 * time.Second
+
+// The store config of a StoreConfig is inlined in its spec.
+var storeConfigPath = field.NewPath("spec")
+
+// DefaultStoreConfig returns a MutateFn that defaults the missing fields of a
+// StoreConfig. A missing type is inferred from the store block that is set,
+// and is Kubernetes if no other block is set. A missing Vault KV version is
+// v2.
+func DefaultStoreConfig() MutateFn {
+	return func(_ context.Context, obj runtime.Object) error {
+		sc, ok := obj.(connection.StoreConfig)
+		if !ok {
+			return errors.New(errNotStoreConfig)
+		}
+		cfg := sc.GetStoreConfig()
+
+		defaults := map[string]any{}
+		if cfg.Type == nil {
+			defaults[storeConfigPath.Child("type").String()] = string(inferStoreType(cfg))
+		}
+		if cfg.Vault != nil && cfg.Vault.Version == nil {
+			defaults[storeConfigPath.Child("vault", "version").String()] = string(xpv1.VaultKVVersionV2)
+		}
+		if len(defaults) == 0 {
+			return nil
+		}
+
+		p, err := fieldpath.PaveObject(obj)
+		if err != nil {
+			return errors.Wrap(err, errPaveObject)
+		}
+		for path, v := range defaults {
+			if err := p.SetValue(path, v); err != nil {
+				return errors.Wrapf(err, errFmtSetStoreField, path)
+			}
+		}
+		return errors.Wrap(runtime.DefaultUnstructuredConverter.FromUnstructured(p.UnstructuredContent(), obj), errConvertObject)
+	}
+}
+
+func inferStoreType(cfg xpv1.SecretStoreConfig) xpv1.SecretStoreType {
+	switch {
+	case cfg.Vault != nil && cfg.Plugin == nil && cfg.SOPS == nil:
+		return xpv1.SecretStoreVault
+	case cfg.Plugin != nil && cfg.Vault == nil && cfg.SOPS == nil:
+		return xpv1.SecretStorePlugin
+	case cfg.SOPS != nil && cfg.Vault == nil && cfg.Plugin == nil:
+		return xpv1.SecretStoreSOPS
+	}
+	return xpv1.SecretStoreKubernetes
+}
+
+// ValidateCreateStoreConfig returns a ValidateCreateFn that rejects
+// StoreConfigs whose store blocks don't match their declared type, as
+// determined by ValidateStoreConfig.
+func ValidateCreateStoreConfig() ValidateCreateFn {
+	return func(_ context.Context, obj runtime.Object) error {
+		return validateStoreConfig(obj)
+	}
+}
+
+// ValidateUpdateStoreConfig returns a ValidateUpdateFn that applies the same
+// rules as ValidateCreateStoreConfig to the updated object.
+func ValidateUpdateStoreConfig() ValidateUpdateFn {
+	return func(_ context.Context, _, newObj runtime.Object) error {
+		return validateStoreConfig(newObj)
+	}
+}
+
+func validateStoreConfig(obj runtime.Object) error {
+	sc, ok := obj.(connection.StoreConfig)
+	if !ok {
+		return errors.New(errNotStoreConfig)
+	}
+	return ValidateStoreConfig(sc.GetStoreConfig(), storeConfigPath).ToAggregate()
+}
+
+// ValidateStoreConfig returns the violations of the supplied store config,
+// found at the supplied path. The store block for the declared type must be
+// set, unless the type is Kubernetes, and the blocks for other types must not
+// be set. A missing type is treated as Kubernetes.
+func ValidateStoreConfig(cfg xpv1.SecretStoreConfig, path *field.Path) field.ErrorList {
+	t := xpv1.SecretStoreKubernetes
+	if cfg.Type != nil {
+		t = *cfg.Type
+	}
+
+	blocks := map[xpv1.SecretStoreType]bool{
+		xpv1.SecretStoreKubernetes: cfg.Kubernetes != nil,
+		xpv1.SecretStoreVault:      cfg.Vault != nil,
+		xpv1.SecretStorePlugin:     cfg.Plugin != nil,
+		xpv1.SecretStoreSOPS:       cfg.SOPS != nil,
+	}
+	fields := map[xpv1.SecretStoreType]*field.Path{
+		xpv1.SecretStoreKubernetes: path.Child("kubernetes"),
+		xpv1.SecretStoreVault:      path.Child("vault"),
+		xpv1.SecretStorePlugin:     path.Child("plugin"),
+		xpv1.SecretStoreSOPS:       path.Child("sops"),
+	}
+	types := []xpv1.SecretStoreType{xpv1.SecretStoreKubernetes, xpv1.SecretStoreVault, xpv1.SecretStorePlugin, xpv1.SecretStoreSOPS}
+
+	if _, ok := blocks[t]; !ok {
+		supported := make([]string, len(types))
+		for i := range types {
+			supported[i] = string(types[i])
+		}
+		return field.ErrorList{field.NotSupported(path.Child("type"), t, supported)}
+	}
+
+	errs := field.ErrorList{}
+	for _, other := range types {
+		if other != t && blocks[other] {
+			errs = append(errs, field.Forbidden(fields[other], "must not be set when type is "+string(t)))
+		}
+	}
+	if t != xpv1.SecretStoreKubernetes && !blocks[t] {
+		errs = append(errs, field.Required(fields[t], "must be set when type is "+string(t)))
+	}
+	return errs
+}
+
+// A StoreProbeWarnFn is called when a StoreConfig fails its connectivity probe.
+type StoreProbeWarnFn func(ctx context.Context, obj runtime.Object, err error)
+
+// WarnWithEvent returns a StoreProbeWarnFn that records a warning event on
+// the StoreConfig that failed its connectivity probe.
+func WarnWithEvent(r event.Recorder) StoreProbeWarnFn {
+	return func(_ context.Context, obj runtime.Object, err error) {
+		r.Event(obj, event.Warning(ReasonStoreProbeFailed, err))
+	}
+}
+
+// ProbeStoreConfig returns a ValidateCreateFn that builds the secret store a
+// StoreConfig configures and reads ProbeSecretName from it, to detect stores
+// that can't be reached at admission time. The supplied TLS config is used by
+// plugin stores. It never rejects a StoreConfig, since the store may become
+// reachable later. Instead it calls the supplied StoreProbeWarnFn if the probe
+// fails. controller-runtime validators cannot return admission warnings, so
+// WarnWithEvent is a typical StoreProbeWarnFn.
+func ProbeStoreConfig(kube client.Client, tcfg *tls.Config, sb connection.StoreBuilderFn, warn StoreProbeWarnFn) ValidateCreateFn {
+	return func(ctx context.Context, obj runtime.Object) error {
+		sc, ok := obj.(connection.StoreConfig)
+		if !ok {
+			return errors.New(errNotStoreConfig)
+		}
+		if err := probeStore(ctx, kube, tcfg, sb, sc.GetStoreConfig()); err != nil {
+			warn(ctx, obj, err)
+		}
+		return nil
+	}
+}
+
+func probeStore(ctx context.Context, kube client.Client, tcfg *tls.Config, sb connection.StoreBuilderFn, cfg xpv1.SecretStoreConfig) error {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	if cfg.Type == nil {
+		t := inferStoreType(cfg)
+		cfg.Type = &t
+	}
+	ss, err := sb(ctx, kube, tcfg, cfg)
+	if err != nil {
+		return errors.Wrap(err, errBuildStore)
+	}
+	n := store.ScopedName{Name: ProbeSecretName, Scope: cfg.DefaultScope}
+	return errors.Wrap(resource.IgnoreNotFound(ss.ReadKeyValues(ctx, n, &store.Secret{})), errProbeStore)
+}
@@ -0,0 +1,275 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"crypto/tls"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	"github.com/crossplane/crossplane-runtime/pkg/connection/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/connection/store"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	resourcefake "github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+// storeConfig is shaped like a StoreConfig, which inlines its store config in
+// its spec.
+type storeConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec storeConfigSpec `json:"spec"`
+}
+
+type storeConfigSpec struct {
+	xpv1.SecretStoreConfig `json:",inline"`
+}
+
+func (s *storeConfig) GetStoreConfig() xpv1.SecretStoreConfig {
+	return s.Spec.SecretStoreConfig
+}
+
+func (s *storeConfig) DeepCopyObject() runtime.Object {
+	out := &storeConfig{TypeMeta: s.TypeMeta}
+	s.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	s.Spec.SecretStoreConfig.DeepCopyInto(&out.Spec.SecretStoreConfig)
+	return out
+}
+
+func withStoreConfig(cfg xpv1.SecretStoreConfig) *storeConfig {
+	return &storeConfig{ObjectMeta: metav1.ObjectMeta{Name: "cool"}, Spec: storeConfigSpec{SecretStoreConfig: cfg}}
+}
+
+func storeType(t xpv1.SecretStoreType) *xpv1.SecretStoreType { return &t }
+
+func kvVersion(v xpv1.VaultKVVersion) *xpv1.VaultKVVersion { return &v }
+
+func TestDefaultStoreConfig(t *testing.T) {
+	type want struct {
+		obj runtime.Object
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		obj    runtime.Object
+		want   want
+	}{
+		"NotStoreConfig": {
+			reason: "We should return an error if the object is not a StoreConfig.",
+			obj:    &resourcefake.Object{},
+			want: want{
+				obj: &resourcefake.Object{},
+				err: errors.New(errNotStoreConfig),
+			},
+		},
+		"NoDefaults": {
+			reason: "We should not change a StoreConfig with no missing fields.",
+			obj:    withStoreConfig(xpv1.SecretStoreConfig{Type: storeType(xpv1.SecretStoreVault), Vault: &xpv1.VaultSecretStoreConfig{Version: kvVersion(xpv1.VaultKVVersionV1)}}),
+			want: want{
+				obj: withStoreConfig(xpv1.SecretStoreConfig{Type: storeType(xpv1.SecretStoreVault), Vault: &xpv1.VaultSecretStoreConfig{Version: kvVersion(xpv1.VaultKVVersionV1)}}),
+			},
+		},
+		"DefaultKubernetes": {
+			reason: "We should default the type of a StoreConfig with no store blocks to Kubernetes.",
+			obj:    withStoreConfig(xpv1.SecretStoreConfig{DefaultScope: "crossplane-system"}),
+			want: want{
+				obj: withStoreConfig(xpv1.SecretStoreConfig{Type: storeType(xpv1.SecretStoreKubernetes), DefaultScope: "crossplane-system"}),
+			},
+		},
+		"InferVault": {
+			reason: "We should infer the type of a StoreConfig from its only store block, and default its Vault KV version.",
+			obj:    withStoreConfig(xpv1.SecretStoreConfig{Vault: &xpv1.VaultSecretStoreConfig{Server: "https://vault"}}),
+			want: want{
+				obj: withStoreConfig(xpv1.SecretStoreConfig{Type: storeType(xpv1.SecretStoreVault), Vault: &xpv1.VaultSecretStoreConfig{Server: "https://vault", Version: kvVersion(xpv1.VaultKVVersionV2)}}),
+			},
+		},
+		"InferPlugin": {
+			reason: "We should infer the type of a StoreConfig from its only store block.",
+			obj:    withStoreConfig(xpv1.SecretStoreConfig{Plugin: &xpv1.PluginStoreConfig{Endpoint: "ess:4040"}}),
+			want: want{
+				obj: withStoreConfig(xpv1.SecretStoreConfig{Type: storeType(xpv1.SecretStorePlugin), Plugin: &xpv1.PluginStoreConfig{Endpoint: "ess:4040"}}),
+			},
+		},
+		"InferSOPS": {
+			reason: "We should infer the type of a StoreConfig from its only store block.",
+			obj:    withStoreConfig(xpv1.SecretStoreConfig{SOPS: &xpv1.SOPSSecretStoreConfig{Path: "/secrets"}}),
+			want: want{
+				obj: withStoreConfig(xpv1.SecretStoreConfig{Type: storeType(xpv1.SecretStoreSOPS), SOPS: &xpv1.SOPSSecretStoreConfig{Path: "/secrets"}}),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := DefaultStoreConfig()(context.Background(), tc.obj)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nDefaultStoreConfig(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.obj, tc.obj); diff != "" {
+				t.Errorf("\n%s\nDefaultStoreConfig(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateStoreConfig(t *testing.T) {
+	spec := field.NewPath("spec")
+
+	cases := map[string]struct {
+		reason string
+		cfg    xpv1.SecretStoreConfig
+		want   field.ErrorList
+	}{
+		"DefaultKubernetes": {
+			reason: "A StoreConfig with no type and no store blocks is a valid Kubernetes store.",
+			cfg:    xpv1.SecretStoreConfig{},
+			want:   field.ErrorList{},
+		},
+		"ValidVault": {
+			reason: "A Vault StoreConfig with only a Vault block is valid.",
+			cfg:    xpv1.SecretStoreConfig{Type: storeType(xpv1.SecretStoreVault), Vault: &xpv1.VaultSecretStoreConfig{}},
+			want:   field.ErrorList{},
+		},
+		"ValidSOPS": {
+			reason: "A SOPS StoreConfig with only a SOPS block is valid.",
+			cfg:    xpv1.SecretStoreConfig{Type: storeType(xpv1.SecretStoreSOPS), SOPS: &xpv1.SOPSSecretStoreConfig{}},
+			want:   field.ErrorList{},
+		},
+		"UnknownType": {
+			reason: "A StoreConfig of an unknown type is invalid.",
+			cfg:    xpv1.SecretStoreConfig{Type: storeType("Cool")},
+			want: field.ErrorList{
+				field.NotSupported(spec.Child("type"), xpv1.SecretStoreType("Cool"), []string{"Kubernetes", "Vault", "Plugin", "SOPS"}),
+			},
+		},
+		"MissingBlock": {
+			reason: "A Plugin StoreConfig must have a Plugin block.",
+			cfg:    xpv1.SecretStoreConfig{Type: storeType(xpv1.SecretStorePlugin)},
+			want: field.ErrorList{
+				field.Required(spec.Child("plugin"), "must be set when type is Plugin"),
+			},
+		},
+		"ExclusiveBlocks": {
+			reason: "A StoreConfig must not have store blocks for types other than its own.",
+			cfg:    xpv1.SecretStoreConfig{Kubernetes: &xpv1.KubernetesSecretStoreConfig{}, Vault: &xpv1.VaultSecretStoreConfig{}, Plugin: &xpv1.PluginStoreConfig{}},
+			want: field.ErrorList{
+				field.Forbidden(spec.Child("vault"), "must not be set when type is Kubernetes"),
+				field.Forbidden(spec.Child("plugin"), "must not be set when type is Kubernetes"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ValidateStoreConfig(tc.cfg, spec)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nValidateStoreConfig(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestProbeStoreConfig(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	builder := func(ss connection.Store, err error) connection.StoreBuilderFn {
+		return func(_ context.Context, _ client.Client, _ *tls.Config, cfg xpv1.SecretStoreConfig) (connection.Store, error) {
+			if cfg.Type == nil {
+				t.Errorf("ProbeStoreConfig(...): store built with no type")
+			}
+			return ss, err
+		}
+	}
+	reader := func(err error) connection.Store {
+		return &fake.SecretStore{ReadKeyValuesFn: func(_ context.Context, n store.ScopedName, _ *store.Secret) error {
+			if diff := cmp.Diff(store.ScopedName{Name: ProbeSecretName, Scope: "crossplane-system"}, n); diff != "" {
+				t.Errorf("ReadKeyValues(...): -want, +got:\n%s", diff)
+			}
+			return err
+		}}
+	}
+
+	type want struct {
+		err  error
+		warn error
+	}
+
+	cases := map[string]struct {
+		reason string
+		sb     connection.StoreBuilderFn
+		obj    runtime.Object
+		want   want
+	}{
+		"NotStoreConfig": {
+			reason: "We should return an error if the object is not a StoreConfig.",
+			obj:    &resourcefake.Object{},
+			want: want{
+				err: errors.New(errNotStoreConfig),
+			},
+		},
+		"BuildError": {
+			reason: "We should warn, but not reject, if the store cannot be built.",
+			sb:     builder(nil, errBoom),
+			obj:    withStoreConfig(xpv1.SecretStoreConfig{DefaultScope: "crossplane-system"}),
+			want: want{
+				warn: errors.Wrap(errBoom, errBuildStore),
+			},
+		},
+		"ReadError": {
+			reason: "We should warn, but not reject, if the store cannot be read.",
+			sb:     builder(reader(errBoom), nil),
+			obj:    withStoreConfig(xpv1.SecretStoreConfig{DefaultScope: "crossplane-system"}),
+			want: want{
+				warn: errors.Wrap(errBoom, errProbeStore),
+			},
+		},
+		"ProbeSecretNotFound": {
+			reason: "We should not warn if the store can be read but the probe secret does not exist.",
+			sb:     builder(reader(kerrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, ProbeSecretName)), nil),
+			obj:    withStoreConfig(xpv1.SecretStoreConfig{DefaultScope: "crossplane-system"}),
+			want:   want{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var warned error
+			warn := func(_ context.Context, _ runtime.Object, err error) { warned = err }
+
+			err := ProbeStoreConfig(nil, nil, tc.sb, warn)(context.Background(), tc.obj)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nProbeStoreConfig(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.warn, warned, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nProbeStoreConfig(...): -want warning, +got warning:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}